@@ -13,6 +13,7 @@ import (
 
 	"github.com/nmstate/kubernetes-nmstate/pkg/apis"
 	"github.com/nmstate/kubernetes-nmstate/pkg/controller"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy"
 	"github.com/nmstate/kubernetes-nmstate/pkg/webhook"
 	"github.com/nmstate/kubernetes-nmstate/version"
 
@@ -157,7 +158,16 @@ func main() {
 	log.Info("Starting the Cmd.")
 
 	// Start the Cmd
-	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
+	// The manager is stopped once the desired state applies in flight
+	// finish, or are marked as interrupted, so their enactments are not
+	// left Progressing
+	stop := make(chan struct{})
+	go func() {
+		<-signals.SetupSignalHandler()
+		nodenetworkconfigurationpolicy.Shutdown()
+		close(stop)
+	}()
+	if err := mgr.Start(stop); err != nil {
 		log.Error(err, "Manager exited non-zero")
 		os.Exit(1)
 	}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes, scripts tell a timeout from other failures with them
+const (
+	exitFailure = 1
+	exitTimeout = 2
+)
+
+func newRootCommand() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:           "nmstatectl-k8s",
+		Short:         "Command line client for kubernetes-nmstate policies and enactments",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	// kubeconfig flag registered by controller-runtime
+	rootCmd.PersistentFlags().AddGoFlagSet(flag.CommandLine)
+	rootCmd.AddCommand(newWaitCommand())
+	rootCmd.AddCommand(newRenderCommand())
+	rootCmd.AddCommand(newReportCommand())
+	return rootCmd
+}
+
+func main() {
+	err := newRootCommand().Execute()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		if isTimeout(err) {
+			os.Exit(exitTimeout)
+		}
+		os.Exit(exitFailure)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/selectors"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+// nodeReportedState returns the node current state as reported at its
+// NodeNetworkState
+func nodeReportedState(cli client.Client, node string) (nmstatev1alpha1.State, error) {
+	nodeNetworkState := nmstatev1alpha1.NodeNetworkState{}
+	err := cli.Get(context.TODO(), types.NamespacedName{Name: node}, &nodeNetworkState)
+	if err != nil {
+		return nmstatev1alpha1.State{}, fmt.Errorf("failed retrieving current state of node %s: %v", node, err)
+	}
+	if nodeNetworkState.Status.LastSuccessfulUpdateTime.IsZero() {
+		return nmstatev1alpha1.State{}, fmt.Errorf("node %s has not reported its current state yet", node)
+	}
+	return nodeNetworkState.Status.CurrentState, nil
+}
+
+// renderDesiredState renders the policy desired state patch against the
+// state nmstate reports at the node, like the handler does, and names the
+// interfaces identified by MAC or PCI address like at the node
+func renderDesiredState(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec, reportedState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	desiredState := policySpec.DesiredState
+	if policySpec.DesiredStatePatch != nil {
+		observedState, err := render.ObservedState(reportedState)
+		if err != nil {
+			return nmstatev1alpha1.State{}, fmt.Errorf("failed reading current state: %v", err)
+		}
+		desiredState, err = render.DesiredState(observedState, *policySpec.DesiredStatePatch)
+		if err != nil {
+			return nmstatev1alpha1.State{}, fmt.Errorf("failed rendering desired state patch: %v", err)
+		}
+	}
+	if !render.HasInterfaceMatches(desiredState) {
+		return desiredState, nil
+	}
+	interfaces, err := render.NodeInterfaces(reportedState)
+	if err != nil {
+		return nmstatev1alpha1.State{}, fmt.Errorf("failed reading node interfaces: %v", err)
+	}
+	return render.ResolveInterfaceMatches(desiredState, interfaces)
+}
+
+// overriddenInterfaces returns the interfaces of the base policy desired
+// state that the policies taking precedence over it at the node configure
+func overriddenInterfaces(cli client.Client, desiredState nmstatev1alpha1.State, node string, reportedState nmstatev1alpha1.State) ([]string, error) {
+	policies := nmstatev1alpha1.NodeNetworkConfigurationPolicyList{}
+	err := cli.List(context.TODO(), &policies)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing policies: %v", err)
+	}
+	overriding := []nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+	for _, policy := range policies.Items {
+		if policy.Spec.Base || policy.Spec.Audit || policy.DeletionTimestamp != nil {
+			continue
+		}
+		unmatched, err := unmatchedSelectors(cli, policy, node)
+		if err != nil {
+			return nil, err
+		}
+		if len(unmatched) > 0 {
+			continue
+		}
+		policy.Spec.DesiredState, err = renderDesiredState(policy.Spec, reportedState)
+		// Like at the handler, the policies with matched interfaces not
+		// found at the node do not configure anything there
+		if render.IsInterfaceNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed rendering policy/%s desired state: %v", policy.Name, err)
+		}
+		overriding = append(overriding, policy)
+	}
+	overrides, err := render.InterfaceOverrides(desiredState, overriding)
+	if err != nil {
+		return nil, err
+	}
+	interfaces := []string{}
+	for _, override := range overrides {
+		interfaces = append(interfaces, override.Interface)
+	}
+	return interfaces, nil
+}
+
+// renderPolicy returns the desired state the handler of the node would
+// apply for the policy. Desired state patches are rendered against the node
+// current state reported at its NodeNetworkState, without what the handler
+// reports on top of nmstate, and the interfaces the policy ignores or other
+// policies override for base policies are left out. Nothing is created or
+// changed at the cluster.
+func renderPolicy(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, node string) (nmstatev1alpha1.State, error) {
+	reportedState := nmstatev1alpha1.State{}
+	if policy.Spec.DesiredStatePatch != nil || render.HasInterfaceMatches(policy.Spec.DesiredState) || policy.Spec.Base {
+		var err error
+		reportedState, err = nodeReportedState(cli, node)
+		if err != nil {
+			return nmstatev1alpha1.State{}, err
+		}
+	}
+	desiredState, err := renderDesiredState(policy.Spec, reportedState)
+	if err != nil {
+		return nmstatev1alpha1.State{}, err
+	}
+
+	if policy.Spec.Base {
+		interfaces, err := overriddenInterfaces(cli, desiredState, node, reportedState)
+		if err != nil {
+			return nmstatev1alpha1.State{}, fmt.Errorf("failed checking interfaces overridden by other policies: %v", err)
+		}
+		desiredState, _, err = render.IgnoreInterfaces(desiredState, interfaces)
+		if err != nil {
+			return nmstatev1alpha1.State{}, fmt.Errorf("failed excluding interfaces overridden by other policies: %v", err)
+		}
+	}
+
+	desiredState, _, err = render.IgnoreInterfaces(desiredState, policy.Spec.IgnoredInterfaces)
+	if err != nil {
+		return nmstatev1alpha1.State{}, fmt.Errorf("failed excluding ignored interfaces: %v", err)
+	}
+	return desiredState, nil
+}
+
+// unmatchedSelectors returns the policy selectors the node does not match
+// as <label>=<value>, sorted by label
+func unmatchedSelectors(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, node string) ([]string, error) {
+	policySelectors := selectors.NewFromPolicy(cli, policy)
+	unmatchedLabels, err := policySelectors.UnmatchedNodeLabels(node)
+	if err != nil {
+		return nil, fmt.Errorf("failed checking policy/%s selectors at node %s: %v", policy.Name, node, err)
+	}
+	unmatched := []string{}
+	for label, value := range unmatchedLabels {
+		unmatched = append(unmatched, label+"="+value)
+	}
+	sort.Strings(unmatched)
+	return unmatched, nil
+}
+
+func newRenderCommand() *cobra.Command {
+	var node string
+	cmd := &cobra.Command{
+		Use:   "render <policy> --node=<node>",
+		Short: "Print the desired state a policy would apply at a node",
+		Long: `Print the desired state the handler of a node would apply for a policy, with
+the desired state patch rendered against the node current state, the
+interfaces matched by MAC or PCI address named like at the node, and the
+ignored interfaces, or the ones overridden by other policies for base
+policies, left out. It is read-only, the policy is not applied and no
+enactment is created. The dropped settings reset by the handler at apply
+time are not included.`,
+		Example: `  nmstatectl-k8s render eth1-policy --node=node01`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if node == "" {
+				return fmt.Errorf("--node is mandatory")
+			}
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+			err = cli.Get(context.TODO(), types.NamespacedName{Name: args[0]}, &policy)
+			if err != nil {
+				return fmt.Errorf("failed retrieving policy/%s: %v", args[0], err)
+			}
+			unmatched, err := unmatchedSelectors(cli, policy, node)
+			if err != nil {
+				return err
+			}
+			if len(unmatched) > 0 {
+				fmt.Fprintf(cmd.OutOrStderr(), "WARNING: policy/%s does not match node %s, unmatched selectors: %s\n", args[0], node, strings.Join(unmatched, ", "))
+			}
+			desiredState, err := renderPolicy(cli, policy, node)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), desiredState.String())
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&node, "node", "", "Node to render the policy desired state for")
+	return cmd
+}
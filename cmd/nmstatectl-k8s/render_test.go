@@ -0,0 +1,154 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("render", func() {
+	const currentState = `interfaces:
+- name: eth1
+  type: ethernet
+  state: down
+`
+	newScheme := func() *runtime.Scheme {
+		s := runtime.NewScheme()
+		s.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.Node{})
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion, &nmstatev1alpha1.NodeNetworkState{}, &nmstatev1alpha1.NodeNetworkConfigurationPolicy{}, &nmstatev1alpha1.NodeNetworkConfigurationPolicyList{})
+		return s
+	}
+	newNodeNetworkState := func(currentState string) *nmstatev1alpha1.NodeNetworkState {
+		nodeNetworkState := &nmstatev1alpha1.NodeNetworkState{ObjectMeta: metav1.ObjectMeta{Name: "node01"}}
+		if currentState != "" {
+			nodeNetworkState.Status.CurrentState = nmstatev1alpha1.NewState(currentState)
+			nodeNetworkState.Status.LastSuccessfulUpdateTime = metav1.Now()
+		}
+		return nodeNetworkState
+	}
+	newPolicy := func() nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		return nmstatev1alpha1.NodeNetworkConfigurationPolicy{ObjectMeta: metav1.ObjectMeta{Name: "eth1-policy"}}
+	}
+
+	It("should return the desired state of policies without patch", func() {
+		policy := newPolicy()
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState(currentState)
+		desiredState, err := renderPolicy(fake.NewFakeClientWithScheme(newScheme()), policy, "node01")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(desiredState.String()).To(MatchYAML(currentState))
+	})
+
+	Context("with a desired state patch", func() {
+		var policy nmstatev1alpha1.NodeNetworkConfigurationPolicy
+		BeforeEach(func() {
+			policy = newPolicy()
+			patch := nmstatev1alpha1.NewStatePatch(`- op: replace
+  path: /interfaces/0/state
+  value: up
+`)
+			policy.Spec.DesiredStatePatch = &patch
+		})
+
+		It("should render it against the node current state", func() {
+			cli := fake.NewFakeClientWithScheme(newScheme(), newNodeNetworkState(currentState))
+			desiredState, err := renderPolicy(cli, policy, "node01")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(desiredState.String()).To(MatchYAML("interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n"))
+		})
+
+		It("should render it without what the handler reports on top of nmstate", func() {
+			cli := fake.NewFakeClientWithScheme(newScheme(), newNodeNetworkState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: down
+  promisc: false
+  hardware:
+    mac-address: 52:55:00:d1:56:01
+`))
+			desiredState, err := renderPolicy(cli, policy, "node01")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(desiredState.String()).To(MatchYAML("interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n"))
+		})
+
+		It("should fail if the node has not reported its current state", func() {
+			cli := fake.NewFakeClientWithScheme(newScheme(), newNodeNetworkState(""))
+			_, err := renderPolicy(cli, policy, "node01")
+			Expect(err).To(MatchError("node node01 has not reported its current state yet"))
+		})
+	})
+
+	It("should name the matched interfaces like at the node and leave out the ignored ones", func() {
+		policy := newPolicy()
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState(`interfaces:
+- name: uplink
+  type: ethernet
+  state: up
+  match:
+    mac-address: 52:55:00:d1:56:01
+- name: cni0
+  type: linux-bridge
+  state: absent
+`)
+		policy.Spec.IgnoredInterfaces = []string{"cni0"}
+		cli := fake.NewFakeClientWithScheme(newScheme(), newNodeNetworkState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: down
+  hardware:
+    mac-address: 52:55:00:d1:56:01
+`))
+		desiredState, err := renderPolicy(cli, policy, "node01")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(desiredState.String()).To(MatchYAML("interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n"))
+	})
+
+	It("should leave out of base policies the interfaces other policies configure at the node", func() {
+		policy := newPolicy()
+		policy.Name = "base"
+		policy.Spec.Base = true
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  mtu: 9000
+- name: eth2
+  type: ethernet
+  state: up
+  mtu: 9000
+`)
+		storagePolicy := newPolicy()
+		storagePolicy.Name = "storage"
+		storagePolicy.Spec.DesiredState = nmstatev1alpha1.NewState(`interfaces:
+- name: storage
+  type: ethernet
+  state: up
+  match:
+    pci-address: "0000:03:00.0"
+`)
+		cli := fake.NewFakeClientWithScheme(newScheme(), newNodeNetworkState(`interfaces:
+- name: eth2
+  type: ethernet
+  state: up
+  hardware:
+    pci-address: "0000:03:00.0"
+`), &policy, &storagePolicy, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node01"}})
+		desiredState, err := renderPolicy(cli, policy, "node01")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(desiredState.String()).To(MatchYAML("interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  mtu: 9000\n"))
+	})
+
+	It("should return the selectors not matched by the node", func() {
+		policy := newPolicy()
+		policy.Spec.NodeSelector = map[string]string{"zone": "a", "role": "edge", "os": "linux"}
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node01", Labels: map[string]string{"os": "linux"}}}
+		unmatched, err := unmatchedSelectors(fake.NewFakeClientWithScheme(newScheme(), node), policy, "node01")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unmatched).To(Equal([]string{"role=edge", "zone=a"}))
+	})
+})
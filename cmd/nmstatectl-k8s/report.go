@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+const (
+	reportFormatTable = "table"
+	reportFormatYAML  = "yaml"
+	reportFormatJSON  = "json"
+)
+
+var reportFormats = []string{reportFormatTable, reportFormatYAML, reportFormatJSON}
+
+func checkReportFormat(format string) error {
+	for _, known := range reportFormats {
+		if format == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid output format %q, it has to be one of %s", format, strings.Join(reportFormats, ", "))
+}
+
+// Status of the policies and enactments without conditions yet
+const unknownStatus = "Unknown"
+
+// Status of the enactments whose policy does not match their node
+const notMatchingStatus = "NotMatching"
+
+// report is the status of all the policies and their enactments
+type report struct {
+	GeneratedAt metav1.Time    `json:"generatedAt"`
+	Policies    []policyReport `json:"policies"`
+}
+
+type policyReport struct {
+	Name       string                          `json:"name"`
+	Generation int64                           `json:"generation"`
+	Status     string                          `json:"status"`
+	Reason     nmstatev1alpha1.ConditionReason `json:"reason,omitempty"`
+	Message    string                          `json:"message,omitempty"`
+	Since      *metav1.Time                    `json:"since,omitempty"`
+	Enactments enactmentsSummary               `json:"enactments"`
+	Nodes      []enactmentReport               `json:"nodes,omitempty"`
+}
+
+// enactmentsSummary counts the policy enactments like the policy summary
+type enactmentsSummary struct {
+	Matching    int `json:"matching"`
+	Available   int `json:"available"`
+	Failed      int `json:"failed"`
+	Progressing int `json:"progressing"`
+}
+
+type enactmentReport struct {
+	Node             string                          `json:"node"`
+	Status           string                          `json:"status"`
+	Reason           nmstatev1alpha1.ConditionReason `json:"reason,omitempty"`
+	Message          string                          `json:"message,omitempty"`
+	FailureCode      nmstatev1alpha1.FailureCode     `json:"failureCode,omitempty"`
+	PolicyGeneration int64                           `json:"policyGeneration,omitempty"`
+	Since            *metav1.Time                    `json:"since,omitempty"`
+}
+
+// policyStatus returns the outcome of the policy conditions, taken from the
+// true one, progressing policies have them unknown
+func policyStatus(conditions nmstatev1alpha1.ConditionList) (string, *nmstatev1alpha1.Condition) {
+	for _, conditionType := range []nmstatev1alpha1.ConditionType{
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDegraded,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable,
+	} {
+		if condition := conditions.Find(conditionType); condition != nil && condition.Status == corev1.ConditionTrue {
+			return string(conditionType), condition
+		}
+	}
+	if condition := conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable); condition != nil {
+		return "Progressing", condition
+	}
+	return unknownStatus, nil
+}
+
+// enactmentStatus returns the outcome of the enactment conditions, taken
+// from the true one, or NotMatching if the policy does not match the node
+func enactmentStatus(conditions nmstatev1alpha1.ConditionList) (string, *nmstatev1alpha1.Condition) {
+	for _, conditionType := range []nmstatev1alpha1.ConditionType{
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing,
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable,
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionProgressing,
+	} {
+		if condition := conditions.Find(conditionType); condition != nil && condition.Status == corev1.ConditionTrue {
+			return string(conditionType), condition
+		}
+	}
+	if condition := conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionMatching); condition != nil && condition.Status == corev1.ConditionFalse {
+		return notMatchingStatus, condition
+	}
+	return unknownStatus, nil
+}
+
+// buildReport reports the policies with their enactments, sorted by policy
+// and node name
+func buildReport(policies []nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactments []nmstatev1alpha1.NodeNetworkConfigurationEnactment, now time.Time) report {
+	enactmentsByPolicy := map[string][]nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+	for _, enactment := range enactments {
+		policy := enactment.Labels[nmstatev1alpha1.EnactmentPolicyLabel]
+		enactmentsByPolicy[policy] = append(enactmentsByPolicy[policy], enactment)
+	}
+
+	generated := report{GeneratedAt: metav1.NewTime(now), Policies: []policyReport{}}
+	for _, policy := range policies {
+		policyEnactments := enactmentsByPolicy[policy.Name]
+		count := enactmentconditions.Count(nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{Items: policyEnactments})
+		policyReport := policyReport{
+			Name:       policy.Name,
+			Generation: policy.Generation,
+			Enactments: enactmentsSummary{
+				Matching:    count.Matching(),
+				Available:   count.Available(),
+				Failed:      count.Failed(),
+				Progressing: count.Progressing(),
+			},
+		}
+		status, condition := policyStatus(policy.Status.Conditions)
+		policyReport.Status = status
+		if condition != nil {
+			policyReport.Reason = condition.Reason
+			policyReport.Message = condition.Message
+			policyReport.Since = condition.LastTransitionTime.DeepCopy()
+		}
+
+		for _, enactment := range policyEnactments {
+			enactmentReport := enactmentReport{
+				Node:             enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel],
+				FailureCode:      enactment.Status.FailureCode,
+				PolicyGeneration: enactment.Status.PolicyGeneration,
+			}
+			status, condition := enactmentStatus(enactment.Status.Conditions)
+			enactmentReport.Status = status
+			if condition != nil {
+				enactmentReport.Reason = condition.Reason
+				enactmentReport.Message = condition.Message
+				enactmentReport.Since = condition.LastTransitionTime.DeepCopy()
+			}
+			policyReport.Nodes = append(policyReport.Nodes, enactmentReport)
+		}
+		sort.Slice(policyReport.Nodes, func(i, j int) bool { return policyReport.Nodes[i].Node < policyReport.Nodes[j].Node })
+		generated.Policies = append(generated.Policies, policyReport)
+	}
+	sort.Slice(generated.Policies, func(i, j int) bool { return generated.Policies[i].Name < generated.Policies[j].Name })
+	return generated
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
+
+func since(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return "<none>"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// writeReportTable writes a row per policy and a row per enactment below
+// it, the messages are only at the yaml and json formats
+func writeReportTable(out io.Writer, generated report) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "POLICY\tNODE\tSTATUS\tREASON\tFAILURE CODE\tENACTMENTS\tSINCE")
+	for _, policy := range generated.Policies {
+		enactments := fmt.Sprintf("%d/%d Available, %d Failed, %d Progressing", policy.Enactments.Available, policy.Enactments.Matching, policy.Enactments.Failed, policy.Enactments.Progressing)
+		fmt.Fprintf(w, "%s\t\t%s\t%s\t\t%s\t%s\n", policy.Name, policy.Status, orNone(string(policy.Reason)), enactments, since(policy.Since))
+		for _, node := range policy.Nodes {
+			fmt.Fprintf(w, "\t%s\t%s\t%s\t%s\t\t%s\n", node.Node, node.Status, orNone(string(node.Reason)), orNone(string(node.FailureCode)), since(node.Since))
+		}
+	}
+	fmt.Fprintf(w, "\nGenerated at %s\n", since(&generated.GeneratedAt))
+	return w.Flush()
+}
+
+func writeReport(out io.Writer, generated report, format string) error {
+	switch format {
+	case reportFormatTable:
+		return writeReportTable(out, generated)
+	case reportFormatYAML:
+		output, err := yaml.Marshal(generated)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(output)
+		return err
+	case reportFormatJSON:
+		output, err := json.MarshalIndent(generated, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(output))
+		return err
+	}
+	return checkReportFormat(format)
+}
+
+func gatherReport(cli client.Client) (report, error) {
+	policies := nmstatev1alpha1.NodeNetworkConfigurationPolicyList{}
+	err := cli.List(context.TODO(), &policies)
+	if err != nil {
+		return report{}, fmt.Errorf("failed listing policies: %v", err)
+	}
+	enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+	err = cli.List(context.TODO(), &enactments)
+	if err != nil {
+		return report{}, fmt.Errorf("failed listing enactments: %v", err)
+	}
+	return buildReport(policies.Items, enactments.Items, time.Now()), nil
+}
+
+func newReportCommand() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Print the status of all the policies and their enactments",
+		Long: `Print the status of all the policies and, below each of them, the status
+of its enactment at every node, with the reasons, failure codes and the time
+they changed. The table leaves out the conditions messages, the yaml and json
+formats include them.`,
+		Example: `  nmstatectl-k8s report
+  nmstatectl-k8s report -o yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := checkReportFormat(output)
+			if err != nil {
+				return err
+			}
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			generated, err := gatherReport(cli)
+			if err != nil {
+				return err
+			}
+			return writeReport(cmd.OutOrStdout(), generated, output)
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", reportFormatTable, "Output format, one of table, yaml or json")
+	return cmd
+}
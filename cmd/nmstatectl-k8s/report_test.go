@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("report", func() {
+	now := time.Date(2020, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	newPolicy := func(name string, available corev1.ConditionStatus) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 2}}
+		policy.Status.Conditions.Set(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable, available, nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionSuccessfullyConfigured, "1/1 nodes successfully configured")
+		return policy
+	}
+	newEnactment := func(policy, node string, setConditions func(*nmstatev1alpha1.ConditionList, string), message string, failureCode nmstatev1alpha1.FailureCode) nmstatev1alpha1.NodeNetworkConfigurationEnactment {
+		enactment := nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: nmstatev1alpha1.EnactmentKey(node, policy).Name,
+				Labels: map[string]string{
+					nmstatev1alpha1.EnactmentPolicyLabel: policy,
+					nmstatev1alpha1.EnactmentNodeLabel:   node,
+				},
+			},
+		}
+		enactmentconditions.SetMatching(&enactment.Status.Conditions, "")
+		setConditions(&enactment.Status.Conditions, message)
+		enactment.Status.FailureCode = failureCode
+		enactment.Status.PolicyGeneration = 2
+		return enactment
+	}
+
+	policies := []nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+		newPolicy("eth2-policy", corev1.ConditionUnknown),
+		newPolicy("eth1-policy", corev1.ConditionTrue),
+	}
+	enactments := []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+		newEnactment("eth2-policy", "node02", enactmentconditions.SetFailedToConfigure, "error reconciling NodeNetworkConfigurationPolicy: NmstateTimeoutError", nmstatev1alpha1.FailureCodeTimeout),
+		newEnactment("eth2-policy", "node01", enactmentconditions.SetProgressing, "", ""),
+		newEnactment("eth1-policy", "node01", enactmentconditions.SetSuccess, "successfully reconciled", ""),
+	}
+
+	It("should report the policies and their enactments sorted by name", func() {
+		generated := buildReport(policies, enactments, now)
+		Expect(generated.GeneratedAt.Time).To(Equal(now))
+		Expect(generated.Policies).To(HaveLen(2))
+
+		eth1Policy := generated.Policies[0]
+		Expect(eth1Policy.Name).To(Equal("eth1-policy"))
+		Expect(eth1Policy.Generation).To(Equal(int64(2)))
+		Expect(eth1Policy.Status).To(Equal("Available"))
+		Expect(eth1Policy.Reason).To(Equal(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionSuccessfullyConfigured))
+		Expect(eth1Policy.Enactments).To(Equal(enactmentsSummary{Matching: 1, Available: 1}))
+		Expect(eth1Policy.Nodes).To(HaveLen(1))
+		Expect(eth1Policy.Nodes[0].Status).To(Equal("Available"))
+		Expect(eth1Policy.Nodes[0].Message).To(Equal("successfully reconciled"))
+
+		eth2Policy := generated.Policies[1]
+		Expect(eth2Policy.Name).To(Equal("eth2-policy"))
+		Expect(eth2Policy.Status).To(Equal("Progressing"))
+		Expect(eth2Policy.Enactments).To(Equal(enactmentsSummary{Matching: 2, Failed: 1, Progressing: 1}))
+		Expect(eth2Policy.Nodes).To(HaveLen(2))
+		Expect(eth2Policy.Nodes[0].Node).To(Equal("node01"))
+		Expect(eth2Policy.Nodes[0].Status).To(Equal("Progressing"))
+		Expect(eth2Policy.Nodes[1].Node).To(Equal("node02"))
+		Expect(eth2Policy.Nodes[1].Status).To(Equal("Failing"))
+		Expect(eth2Policy.Nodes[1].Reason).To(Equal(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailedToConfigure))
+		Expect(eth2Policy.Nodes[1].FailureCode).To(Equal(nmstatev1alpha1.FailureCodeTimeout))
+		Expect(eth2Policy.Nodes[1].Since).ToNot(BeNil())
+	})
+
+	It("should report the enactments not matching their node and the policies without conditions", func() {
+		enactment := newEnactment("eth1-policy", "node02", enactmentconditions.SetNodeSelectorNotMatching, "unmatched selectors", "")
+		generated := buildReport([]nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			{ObjectMeta: metav1.ObjectMeta{Name: "eth1-policy"}},
+		}, []nmstatev1alpha1.NodeNetworkConfigurationEnactment{enactment}, now)
+		Expect(generated.Policies).To(HaveLen(1))
+		Expect(generated.Policies[0].Status).To(Equal(unknownStatus))
+		Expect(generated.Policies[0].Since).To(BeNil())
+		Expect(generated.Policies[0].Nodes[0].Status).To(Equal(notMatchingStatus))
+	})
+
+	It("should write a table with a row per policy and enactment", func() {
+		out := bytes.Buffer{}
+		err := writeReport(&out, buildReport(policies, enactments, now), reportFormatTable)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.String()).To(ContainSubstring("POLICY"))
+		Expect(out.String()).To(MatchRegexp(`eth1-policy\s+Available\s+SuccessfullyConfigured\s+1/1 Available, 0 Failed, 0 Progressing`))
+		Expect(out.String()).To(MatchRegexp(`node02\s+Failing\s+FailedToConfigure\s+Timeout`))
+		Expect(out.String()).To(ContainSubstring("Generated at 2020-03-01T10:00:00Z"))
+		Expect(out.String()).ToNot(ContainSubstring("error reconciling"))
+	})
+
+	It("should write the messages at the yaml and json formats", func() {
+		for _, format := range []string{reportFormatYAML, reportFormatJSON} {
+			out := bytes.Buffer{}
+			err := writeReport(&out, buildReport(policies, enactments, now), format)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.String()).To(ContainSubstring("error reconciling NodeNetworkConfigurationPolicy: NmstateTimeoutError"))
+			Expect(out.String()).To(ContainSubstring("failureCode"))
+		}
+	})
+
+	It("should fail with an unknown format", func() {
+		Expect(checkReportFormat("wide")).To(HaveOccurred())
+		Expect(writeReport(&bytes.Buffer{}, report{}, "wide")).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Interval to check the condition again
+const waitPollInterval = 2 * time.Second
+
+const (
+	policyKind    = "policy"
+	enactmentKind = "enactment"
+)
+
+var kindAliases = map[string]string{
+	"policy":                            policyKind,
+	"nncp":                              policyKind,
+	"nodenetworkconfigurationpolicy":    policyKind,
+	"enactment":                         enactmentKind,
+	"nnce":                              enactmentKind,
+	"nodenetworkconfigurationenactment": enactmentKind,
+}
+
+var kindConditionTypes = map[string][]nmstatev1alpha1.ConditionType{
+	policyKind: {
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDegraded,
+	},
+	enactmentKind: nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionTypes[:],
+}
+
+// timeoutError is returned if the condition is not met in time
+type timeoutError struct {
+	target    waitTarget
+	condition waitCondition
+	timeout   time.Duration
+}
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s waiting for %s condition %s", e.timeout, e.target, e.condition)
+}
+
+func isTimeout(err error) bool {
+	_, isTimeout := err.(*timeoutError)
+	return isTimeout
+}
+
+// waitTarget is the policy or enactment to wait for
+type waitTarget struct {
+	kind string
+	name string
+}
+
+func (t waitTarget) String() string {
+	return t.kind + "/" + t.name
+}
+
+// waitCondition is the condition type and status to wait for
+type waitCondition struct {
+	conditionType nmstatev1alpha1.ConditionType
+	status        corev1.ConditionStatus
+}
+
+func (c waitCondition) String() string {
+	return fmt.Sprintf("%s=%s", c.conditionType, c.status)
+}
+
+// parseWaitTarget parses <kind>/<name>, the per node enactment of a policy is
+// the target with node
+func parseWaitTarget(resource string, node string) (waitTarget, error) {
+	kindName := strings.SplitN(resource, "/", 2)
+	if len(kindName) != 2 || kindName[1] == "" {
+		return waitTarget{}, fmt.Errorf("invalid resource %q, it has to be policy/<name> or enactment/<name>", resource)
+	}
+	kind, known := kindAliases[strings.ToLower(kindName[0])]
+	if !known {
+		return waitTarget{}, fmt.Errorf("invalid resource kind %q, it has to be policy or enactment", kindName[0])
+	}
+	target := waitTarget{kind: kind, name: kindName[1]}
+	if node != "" {
+		if kind != policyKind {
+			return waitTarget{}, fmt.Errorf("--node is only valid waiting for a policy")
+		}
+		target = waitTarget{kind: enactmentKind, name: nmstatev1alpha1.EnactmentKey(node, target.name).Name}
+	}
+	return target, nil
+}
+
+// parseWaitCondition parses <type>[=<status>], the status is true if not
+// set and the type is matched ignoring case, like kubectl wait does
+func parseWaitCondition(kind string, condition string) (waitCondition, error) {
+	typeStatus := strings.SplitN(condition, "=", 2)
+	parsed := waitCondition{status: corev1.ConditionTrue}
+	if len(typeStatus) == 2 {
+		switch strings.ToLower(typeStatus[1]) {
+		case "true":
+			parsed.status = corev1.ConditionTrue
+		case "false":
+			parsed.status = corev1.ConditionFalse
+		case "unknown":
+			parsed.status = corev1.ConditionUnknown
+		default:
+			return parsed, fmt.Errorf("invalid condition status %q, it has to be True, False or Unknown", typeStatus[1])
+		}
+	}
+	names := []string{}
+	for _, conditionType := range kindConditionTypes[kind] {
+		if strings.EqualFold(string(conditionType), typeStatus[0]) {
+			parsed.conditionType = conditionType
+			return parsed, nil
+		}
+		names = append(names, string(conditionType))
+	}
+	return parsed, fmt.Errorf("invalid %s condition %q, it has to be one of %s", kind, typeStatus[0], strings.Join(names, ", "))
+}
+
+func targetConditions(cli client.Client, target waitTarget) (nmstatev1alpha1.ConditionList, error) {
+	key := types.NamespacedName{Name: target.name}
+	if target.kind == policyKind {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		err := cli.Get(context.TODO(), key, &policy)
+		return policy.Status.Conditions, err
+	}
+	enactment := nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+	err := cli.Get(context.TODO(), key, &enactment)
+	return enactment.Status.Conditions, err
+}
+
+// waitForCondition blocks until the target has the condition, targets not
+// created yet are waited for too
+func waitForCondition(cli client.Client, target waitTarget, condition waitCondition, timeout time.Duration, interval time.Duration) error {
+	err := wait.PollImmediate(interval, timeout, func() (bool, error) {
+		conditions, err := targetConditions(cli, target)
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed retrieving %s: %v", target, err)
+		}
+		found := conditions.Find(condition.conditionType)
+		return found != nil && found.Status == condition.status, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return &timeoutError{target: target, condition: condition, timeout: timeout}
+	}
+	return err
+}
+
+func newWaitCommand() *cobra.Command {
+	var (
+		forCondition string
+		node         string
+		timeout      time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "wait (policy|enactment)/<name> --for=<condition>[=<status>]",
+		Short: "Wait for a policy or enactment to reach a condition",
+		Long: `Wait for a policy or enactment to reach a condition, or for the enactment
+of a policy at a node with --node. It exits with 0 once the condition is
+met, with 2 if the timeout elapses first and with 1 on any other failure.`,
+		Example: `  nmstatectl-k8s wait policy/eth1-policy --for=Available --timeout=5m
+  nmstatectl-k8s wait policy/eth1-policy --node=node01 --for=Failing=False`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := parseWaitTarget(args[0], node)
+			if err != nil {
+				return err
+			}
+			if forCondition == "" {
+				return fmt.Errorf("--for is mandatory")
+			}
+			condition, err := parseWaitCondition(target.kind, forCondition)
+			if err != nil {
+				return err
+			}
+			cli, err := newClient()
+			if err != nil {
+				return err
+			}
+			err = waitForCondition(cli, target, condition, timeout, waitPollInterval)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s condition %s met\n", target, condition)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&forCondition, "for", "", "Condition to wait for, like Available or Degraded=False")
+	cmd.Flags().StringVar(&node, "node", "", "Wait for the enactment of the policy at this node")
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "Time to wait before giving up")
+	return cmd
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("wait", func() {
+	DescribeTable("resources",
+		func(resource string, node string, expectedTarget waitTarget, expectedError string) {
+			target, err := parseWaitTarget(resource, node)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(target).To(Equal(expectedTarget))
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+		Entry("policy", "policy/eth1-policy", "", waitTarget{kind: policyKind, name: "eth1-policy"}, ""),
+		Entry("policy short name", "nncp/eth1-policy", "", waitTarget{kind: policyKind, name: "eth1-policy"}, ""),
+		Entry("enactment", "nnce/node01.eth1-policy", "", waitTarget{kind: enactmentKind, name: "node01.eth1-policy"}, ""),
+		Entry("policy at a node", "policy/eth1-policy", "node01", waitTarget{kind: enactmentKind, name: "node01.eth1-policy"}, ""),
+		Entry("without name", "policy", "", waitTarget{}, `invalid resource "policy", it has to be policy/<name> or enactment/<name>`),
+		Entry("with unknown kind", "nns/node01", "", waitTarget{}, `invalid resource kind "nns", it has to be policy or enactment`),
+		Entry("enactment at a node", "enactment/node01.eth1-policy", "node01", waitTarget{}, "--node is only valid waiting for a policy"),
+	)
+
+	DescribeTable("conditions",
+		func(kind string, condition string, expectedCondition waitCondition, expectedError string) {
+			parsed, err := parseWaitCondition(kind, condition)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(parsed).To(Equal(expectedCondition))
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+		Entry("true by default", policyKind, "Available", waitCondition{nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable, corev1.ConditionTrue}, ""),
+		Entry("ignoring case", policyKind, "degraded=false", waitCondition{nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDegraded, corev1.ConditionFalse}, ""),
+		Entry("of enactment", enactmentKind, "Progressing=Unknown", waitCondition{nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionProgressing, corev1.ConditionUnknown}, ""),
+		Entry("of another kind", policyKind, "Failing", waitCondition{}, `invalid policy condition "Failing", it has to be one of Available, Degraded`),
+		Entry("with invalid status", policyKind, "Available=yes", waitCondition{}, `invalid condition status "yes", it has to be True, False or Unknown`),
+	)
+
+	Context("waiting for a policy condition", func() {
+		policy := func(status corev1.ConditionStatus) *nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+			policy := &nmstatev1alpha1.NodeNetworkConfigurationPolicy{ObjectMeta: metav1.ObjectMeta{Name: "eth1-policy"}}
+			policy.Status.Conditions.Set(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable, status, "", "")
+			return policy
+		}
+		target := waitTarget{kind: policyKind, name: "eth1-policy"}
+		available := waitCondition{nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable, corev1.ConditionTrue}
+
+		newScheme := func() *runtime.Scheme {
+			s := runtime.NewScheme()
+			s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion, &nmstatev1alpha1.NodeNetworkConfigurationPolicy{})
+			return s
+		}
+
+		It("should return once the condition is met", func() {
+			cli := fake.NewFakeClientWithScheme(newScheme(), policy(corev1.ConditionTrue))
+			Expect(waitForCondition(cli, target, available, time.Second, 10*time.Millisecond)).To(Succeed())
+		})
+
+		It("should time out if the condition is not met", func() {
+			cli := fake.NewFakeClientWithScheme(newScheme(), policy(corev1.ConditionFalse))
+			err := waitForCondition(cli, target, available, 50*time.Millisecond, 10*time.Millisecond)
+			Expect(isTimeout(err)).To(BeTrue())
+			Expect(err).To(MatchError("timed out after 50ms waiting for policy/eth1-policy condition Available=True"))
+		})
+
+		It("should wait for policies not created yet", func() {
+			cli := fake.NewFakeClientWithScheme(newScheme())
+			err := waitForCondition(cli, target, available, 50*time.Millisecond, 10*time.Millisecond)
+			Expect(isTimeout(err)).To(BeTrue())
+		})
+	})
+})
@@ -3,6 +3,8 @@
 ${KUBECTL} apply -f deploy/crds/nmstate.io_nodenetworkstates_crd.yaml
 ${KUBECTL} apply -f deploy/crds/nmstate.io_nodenetworkconfigurationpolicies_crd.yaml
 ${KUBECTL} apply -f deploy/crds/nmstate.io_nodenetworkconfigurationenactments_crd.yaml
+${KUBECTL} apply -f deploy/crds/nmstate.io_nodenetworkconfigurationpolicybundles_crd.yaml
+${KUBECTL} apply -f deploy/crds/nmstate.io_nodenetworkstatelabels_crd.yaml
 ${KUBECTL} delete --ignore-not-found -f ${local_handler_manifest}
 
 # Set debug verbosity level for logs when using cluster-sync
@@ -68,3 +68,53 @@ func (conditions ConditionList) Find(conditionType ConditionType) *Condition {
 	}
 	return nil
 }
+
+// IsTrue returns true if the condition of the given type is present and true
+func (conditions ConditionList) IsTrue(conditionType ConditionType) bool {
+	condition := conditions.Find(conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// failure returns the true Degraded condition of policies and bundles or the
+// true Failing condition of enactments and node network states
+func (conditions ConditionList) failure() *Condition {
+	for _, conditionType := range []ConditionType{NodeNetworkConfigurationPolicyConditionDegraded, NodeNetworkConfigurationEnactmentConditionFailing} {
+		condition := conditions.Find(conditionType)
+		if condition != nil && condition.Status == corev1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+// IsAvailable returns true if the desired state has been configured, or there
+// was nothing to configure, and it is not degraded
+func (conditions ConditionList) IsAvailable() bool {
+	return conditions.IsTrue(NodeNetworkConfigurationPolicyConditionAvailable) && !conditions.IsDegraded()
+}
+
+// IsDegraded returns true if the desired state failed to be configured
+func (conditions ConditionList) IsDegraded() bool {
+	return conditions.failure() != nil
+}
+
+// IsProgressing returns true if the desired state is still being configured,
+// that is, it is neither available nor degraded and it has not been decided
+// that there is nothing to configure. Objects without conditions are not
+// reconciled yet and are progressing too.
+func (conditions ConditionList) IsProgressing() bool {
+	if conditions.IsAvailable() || conditions.IsDegraded() {
+		return false
+	}
+	available := conditions.Find(NodeNetworkConfigurationPolicyConditionAvailable)
+	return available == nil || available.Status == corev1.ConditionUnknown
+}
+
+// FailureMessage returns the message of the condition reporting the failure
+// or empty if it is not degraded
+func (conditions ConditionList) FailureMessage() string {
+	if failure := conditions.failure(); failure != nil {
+		return failure.Message
+	}
+	return ""
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
@@ -114,3 +115,32 @@ var _ = Describe("Conditions list", func() {
 		})
 	})
 })
+
+var _ = Describe("Conditions state", func() {
+	conditions := func(available corev1.ConditionStatus, degraded corev1.ConditionStatus, degradedType ConditionType) ConditionList {
+		return ConditionList{
+			Condition{Type: NodeNetworkConfigurationPolicyConditionAvailable, Status: available},
+			Condition{Type: degradedType, Status: degraded, Message: "failed applying desired state"},
+		}
+	}
+	type state struct {
+		available, degraded, progressing bool
+		failureMessage                   string
+	}
+	DescribeTable("helpers",
+		func(conditions ConditionList, expected state) {
+			Expect(state{
+				available:      conditions.IsAvailable(),
+				degraded:       conditions.IsDegraded(),
+				progressing:    conditions.IsProgressing(),
+				failureMessage: conditions.FailureMessage(),
+			}).To(Equal(expected))
+		},
+		Entry("without conditions", ConditionList{}, state{progressing: true}),
+		Entry("with progressing policy", conditions(corev1.ConditionUnknown, corev1.ConditionUnknown, NodeNetworkConfigurationPolicyConditionDegraded), state{progressing: true}),
+		Entry("with available policy", conditions(corev1.ConditionTrue, corev1.ConditionFalse, NodeNetworkConfigurationPolicyConditionDegraded), state{available: true}),
+		Entry("with degraded policy", conditions(corev1.ConditionFalse, corev1.ConditionTrue, NodeNetworkConfigurationPolicyConditionDegraded), state{degraded: true, failureMessage: "failed applying desired state"}),
+		Entry("with failing enactment", conditions(corev1.ConditionFalse, corev1.ConditionTrue, NodeNetworkConfigurationEnactmentConditionFailing), state{degraded: true, failureMessage: "failed applying desired state"}),
+		Entry("with not matching enactment", conditions(corev1.ConditionFalse, corev1.ConditionFalse, NodeNetworkConfigurationEnactmentConditionFailing), state{}),
+	)
+})
@@ -31,6 +31,17 @@ type NodeNetworkConfigurationEnactment struct {
 	Status NodeNetworkConfigurationEnactmentStatus `json:"status,omitempty"`
 }
 
+// ApplyResourceUsage is the CPU time the commands run by a desired state
+// apply, like nmstatectl, nmcli or ip, consumed at the node. The time
+// NetworkManager spends applying it is not included.
+type ApplyResourceUsage struct {
+	// CPU time spent in user mode
+	UserCPUTime metav1.Duration `json:"userCPUTime"`
+
+	// CPU time spent in kernel mode
+	SystemCPUTime metav1.Duration `json:"systemCPUTime"`
+}
+
 // NodeNetworkConfigurationEnactmentStatus defines the observed state of NodeNetworkConfigurationEnactment
 // +k8s:openapi-gen=true
 type NodeNetworkConfigurationEnactmentStatus struct {
@@ -39,15 +50,147 @@ type NodeNetworkConfigurationEnactmentStatus struct {
 	// the policy desiredState as template
 	DesiredState State `json:"desiredState,omitempty"`
 
+	// The desired state last applied successfully at the node, the
+	// settings dropped from the policy since then are removed from it
+	// +optional
+	AppliedDesiredState State `json:"appliedDesiredState,omitempty"`
+
+	// The global forwarding the node had before the policy set it, by
+	// family, it's restored once the policy drops the family
+	// +optional
+	PreviousForwarding map[string]bool `json:"previousForwarding,omitempty"`
+
+	// Time the last desired state apply started and finished at the node
+	// and how long it took, the finish time and duration are empty while
+	// it's in progress
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// CPU time the commands run by the last desired state apply consumed
+	// at the node, empty while it's in progress
+	// +optional
+	ResourceUsage *ApplyResourceUsage `json:"resourceUsage,omitempty"`
+
+	// Paths of the applied desired state that do not match the node current
+	// state anymore, refreshed with each node network state report
+	// +optional
+	Drift []string `json:"drift,omitempty"`
+
+	// NetworkManager connections named by the desired state, with the UUID
+	// given to them at the node
+	// +optional
+	Connections []NetworkManagerConnection `json:"connections,omitempty"`
+
+	// Policy ignored interfaces removed from the desired state, nmstate
+	// leaves them alone
+	// +optional
+	IgnoredInterfaces []string `json:"ignoredInterfaces,omitempty"`
+
+	// Interfaces of a base policy configured by other policies matching the
+	// node, removed from the desired state so those policies take precedence
+	// +optional
+	OverriddenInterfaces []InterfaceOverride `json:"overriddenInterfaces,omitempty"`
+
+	// Generation of the policy the enactment desired state was taken from
+	// +optional
+	PolicyGeneration int64 `json:"policyGeneration,omitempty"`
+
+	// Value of the policy rollout resume annotation when the policy
+	// generation was taken, the failures from before the rollout was
+	// resumed do not halt it
+	// +optional
+	RolloutResume string `json:"rolloutResume,omitempty"`
+
+	// Links bounced by the MTU changes of the last applied desired state,
+	// the NIC drivers reset them to change their MTU whatever the order
+	// +optional
+	LinkFlaps []string `json:"linkFlaps,omitempty"`
+
+	// Stale routes removed from the policy route tables after the last
+	// applied desired state, with the policy route table cleanup
+	// +optional
+	RemovedStaleRoutes []string `json:"removedStaleRoutes,omitempty"`
+
+	// Node current state taken right before the last desired state apply
+	// started, to debug it after the fact
+	// +optional
+	PreApplySnapshot *StateSnapshot `json:"preApplySnapshot,omitempty"`
+
+	// Desired states previously applied at the node, oldest first, only
+	// kept if the handler is configured to keep them
+	// +optional
+	History []EnactmentRevision `json:"history,omitempty"`
+
+	// Stable code of the failure reported at the Failing condition, empty if
+	// it's not failing, to branch on it instead of on the failure message
+	// +optional
+	FailureCode FailureCode `json:"failureCode,omitempty"`
+
 	Conditions ConditionList `json:"conditions,omitempty"`
 }
 
+// InterfaceOverride is an interface of a base policy configured by another
+// policy at the node
+// +k8s:openapi-gen=true
+type InterfaceOverride struct {
+	Interface string `json:"interface"`
+	Policy    string `json:"policy"`
+}
+
+// StateSnapshot is a nmstatectl show output with its sensitive values
+// redacted and gzip compressed
+// +k8s:openapi-gen=true
+type StateSnapshot struct {
+	// Time the snapshot was taken at
+	Time metav1.Time `json:"time"`
+
+	// Size of the uncompressed snapshot in bytes
+	Size int `json:"size"`
+
+	// Compressed snapshot, base64 encoded, empty if it's omitted
+	// +optional
+	Data []byte `json:"data,omitempty"`
+
+	// Omitted is true if the compressed snapshot exceeds the size bound
+	// and it's not kept
+	// +optional
+	Omitted bool `json:"omitted,omitempty"`
+}
+
+// EnactmentRevision is a desired state previously applied by the enactment
+// along with its result
+// +k8s:openapi-gen=true
+type EnactmentRevision struct {
+	// Generation of the policy the desired state was taken from
+	// +optional
+	PolicyGeneration int64 `json:"policyGeneration,omitempty"`
+
+	DesiredState State `json:"desiredState,omitempty"`
+
+	// Time the desired state apply finished at, empty if it did not
+	// finish
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+
+	// Reason of the enactment condition the apply finished with
+	// +optional
+	Reason ConditionReason `json:"reason,omitempty"`
+}
+
 const (
 	EnactmentPolicyLabel                                                = "nmstate.io/policy"
+	EnactmentNodeLabel                                                  = "nmstate.io/node"
 	NodeNetworkConfigurationEnactmentConditionAvailable   ConditionType = "Available"
 	NodeNetworkConfigurationEnactmentConditionFailing     ConditionType = "Failing"
 	NodeNetworkConfigurationEnactmentConditionProgressing ConditionType = "Progressing"
 	NodeNetworkConfigurationEnactmentConditionMatching    ConditionType = "Matching"
+
+	// Only set for the enactments of audit policies
+	NodeNetworkConfigurationEnactmentConditionCompliant ConditionType = "Compliant"
 )
 
 var NodeNetworkConfigurationEnactmentConditionTypes = [...]ConditionType{
@@ -60,9 +203,75 @@ var NodeNetworkConfigurationEnactmentConditionTypes = [...]ConditionType{
 const (
 	NodeNetworkConfigurationEnactmentConditionFailedToConfigure                ConditionReason = "FailedToConfigure"
 	NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured           ConditionReason = "SuccessfullyConfigured"
+	NodeNetworkConfigurationEnactmentConditionConfiguredWithoutRollback        ConditionReason = "ConfiguredWithoutRollback"
+	NodeNetworkConfigurationEnactmentConditionConfiguredLinkDown               ConditionReason = "ConfiguredLinkDown"
+	NodeNetworkConfigurationEnactmentConditionDHCPFellBack                     ConditionReason = "DhcpFellBack"
+	NodeNetworkConfigurationEnactmentConditionSTPManagementBridge              ConditionReason = "StpManagementBridge"
 	NodeNetworkConfigurationEnactmentConditionConfigurationProgressing         ConditionReason = "ConfigurationProgressing"
 	NodeNetworkConfigurationEnactmentConditionNodeSelectorNotMatching          ConditionReason = "NodeSelectorNotMatching"
 	NodeNetworkConfigurationEnactmentConditionNodeSelectorAllSelectorsMatching ConditionReason = "AllSelectorsMatching"
+	NodeNetworkConfigurationEnactmentConditionNodeFileMissing                  ConditionReason = "NodeFileMissing"
+	NodeNetworkConfigurationEnactmentConditionQuarantined                      ConditionReason = "Quarantined"
+	NodeNetworkConfigurationEnactmentConditionRolloutHalted                    ConditionReason = "RolloutHalted"
+	NodeNetworkConfigurationEnactmentConditionWaitingPostBoot                  ConditionReason = "WaitingPostBoot"
+	NodeNetworkConfigurationEnactmentConditionProtectedInterfaceModified       ConditionReason = "ProtectedInterfaceModified"
+	NodeNetworkConfigurationEnactmentConditionInterfaceNotAllowed              ConditionReason = "InterfaceNotAllowed"
+	NodeNetworkConfigurationEnactmentConditionInterfaceNotFound                ConditionReason = "InterfaceNotFound"
+	NodeNetworkConfigurationEnactmentConditionDeviceUnmanaged                  ConditionReason = "DeviceUnmanaged"
+	NodeNetworkConfigurationEnactmentConditionAddressConflict                  ConditionReason = "AddressConflict"
+	NodeNetworkConfigurationEnactmentConditionCoolingDown                      ConditionReason = "CoolingDown"
+	NodeNetworkConfigurationEnactmentConditionWaitingForLock                   ConditionReason = "WaitingForLock"
+	NodeNetworkConfigurationEnactmentConditionWaitingForRollout                ConditionReason = "WaitingForRollout"
+	NodeNetworkConfigurationEnactmentConditionNmstateBusy                      ConditionReason = "NmstateBusy"
+	NodeNetworkConfigurationEnactmentConditionCheckpointFailed                 ConditionReason = "CheckpointFailed"
+	NodeNetworkConfigurationEnactmentConditionApplyTimeout                     ConditionReason = "ApplyTimeout"
+	NodeNetworkConfigurationEnactmentConditionSecretNotFound                   ConditionReason = "SecretNotFound"
+	NodeNetworkConfigurationEnactmentConditionInterrupted                      ConditionReason = "Interrupted"
+	NodeNetworkConfigurationEnactmentConditionStaged                           ConditionReason = "Staged"
+	NodeNetworkConfigurationEnactmentConditionAudited                          ConditionReason = "Audited"
+	NodeNetworkConfigurationEnactmentConditionNodeCompliant                    ConditionReason = "Compliant"
+	NodeNetworkConfigurationEnactmentConditionNodeNonCompliant                 ConditionReason = "NonCompliant"
+)
+
+// FailureCode classifies the enactment failures, unlike the failure messages
+// the codes do not change between versions
+type FailureCode string
+
+const (
+	// nmstate rejected the desired state values
+	FailureCodeInvalidDesiredState FailureCode = "InvalidDesiredState"
+	// nmstate or NetworkManager do not support the desired state
+	FailureCodeNotSupported FailureCode = "NotSupported"
+	// The node current state does not match the desired state once applied
+	FailureCodeVerificationFailed FailureCode = "VerificationFailed"
+	// NetworkManager failed activating the desired state
+	FailureCodeNetworkManagerFailed FailureCode = "NetworkManagerFailed"
+	// nmstate is not allowed to apply the desired state
+	FailureCodePermissionDenied FailureCode = "PermissionDenied"
+	// A tool or plugin needed by the desired state is missing at the node
+	FailureCodeDependencyMissing FailureCode = "DependencyMissing"
+	// The desired state did not finish applying in time
+	FailureCodeTimeout FailureCode = "Timeout"
+	// The node lost connectivity with the desired state, it was rolled back
+	FailureCodeConnectivityLost FailureCode = "ConnectivityLost"
+	// The policy readiness checks failed with the desired state, it was
+	// rolled back
+	FailureCodeReadinessFailed FailureCode = "ReadinessFailed"
+	// The nmstate checkpoint could not be created
+	FailureCodeCheckpointFailed FailureCode = "CheckpointFailed"
+	// The handler refused to apply the desired state at the node, like with
+	// protected interfaces or address conflicts
+	FailureCodeRejected FailureCode = "Rejected"
+	// Something the desired state refers to is missing at the node, like a
+	// matched interface or a secret
+	FailureCodePrerequisiteMissing FailureCode = "PrerequisiteMissing"
+	// The desired state was not applied at the node because of the policy
+	// rollout or quarantine
+	FailureCodeNotApplied FailureCode = "NotApplied"
+	// nmstate or the handler failed unexpectedly
+	FailureCodeInternalError FailureCode = "InternalError"
+	// Any other apply failure
+	FailureCodeApplyFailed FailureCode = "ApplyFailed"
 )
 
 func EnactmentKey(node string, policy string) types.NamespacedName {
@@ -79,6 +288,7 @@ func NewEnactment(nodeName string, policy NodeNetworkConfigurationPolicy) NodeNe
 			// Associate policy with the enactment using labels
 			Labels: map[string]string{
 				EnactmentPolicyLabel: policy.Name,
+				EnactmentNodeLabel:   nodeName,
 			},
 		},
 		Status: NodeNetworkConfigurationEnactmentStatus{
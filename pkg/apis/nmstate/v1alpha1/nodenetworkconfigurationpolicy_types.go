@@ -22,6 +22,7 @@ type NodeNetworkConfigurationPolicyList struct {
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=nodenetworkconfigurationpolicies,shortName=nncp,scope=Cluster
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.summary",description="Summary of the policy enactments"
 type NodeNetworkConfigurationPolicy struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -39,19 +40,277 @@ type NodeNetworkConfigurationPolicySpec struct {
 	// +optional
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 
+	// CloudSelector restricts the policy to the cloud nodes with the given
+	// instance metadata, taken from the well-known labels set by the cloud
+	// providers, the rest of the nodes are not matching the policy
+	// +optional
+	CloudSelector *CloudSelector `json:"cloudSelector,omitempty"`
+
 	// The desired configuration of the policy
 	DesiredState State `json:"desiredState,omitempty"`
+
+	// DesiredStatePatch is a JSON patch applied to the node current state
+	// to produce the desired configuration of the policy, it cannot be set
+	// together with DesiredState
+	// +optional
+	DesiredStatePatch *StatePatch `json:"desiredStatePatch,omitempty"`
+
+	// PostBootDelay is the minimum time the node has to be up before
+	// the desired state is applied, so it does not race with the network
+	// initialization after a reboot
+	// +optional
+	PostBootDelay *metav1.Duration `json:"postBootDelay,omitempty"`
+
+	// ProtectedInterfaces is a list of interfaces the policy must never
+	// modify, the enactment fails if the desired state changes them or
+	// attaches them to a bridge or bond
+	// +optional
+	ProtectedInterfaces []string `json:"protectedInterfaces,omitempty"`
+
+	// Base marks the policy as a baseline for the nodes it matches, the
+	// interfaces configured by other policies matching a node are removed
+	// from its desired state there so those policies take precedence
+	// +optional
+	Base bool `json:"base,omitempty"`
+
+	// IgnoredInterfaces is a list of interfaces managed by others, like
+	// CNI plugins, they are removed from the desired state before applying
+	// it so nmstate leaves them alone
+	// +optional
+	IgnoredInterfaces []string `json:"ignoredInterfaces,omitempty"`
+
+	// DriftIgnore is a list of paths of the desired state, like
+	// interfaces.*.ipv6.address, excluded when comparing it with the node
+	// current state to detect drift
+	// +optional
+	DriftIgnore []string `json:"driftIgnore,omitempty"`
+
+	// Audit makes the policy only report if the nodes comply with the
+	// desired state, it's never applied
+	// +optional
+	Audit bool `json:"audit,omitempty"`
+
+	// ReadinessChecks have to pass after applying the desired state for
+	// the enactment to be available, otherwise it's rolled back
+	// +optional
+	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+
+	// ExpectedNodeCount is the minimum number of nodes the policy is
+	// expected to match, the policy is degraded if it matches fewer of
+	// them, like when some nodes lost a label
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ExpectedNodeCount int `json:"expectedNodeCount,omitempty"`
+
+	// RequireNodeFile is the path of a file, under
+	// /var/lib/nmstate/node-files, that has to exist at the node for the
+	// policy to be applied there, like a provisioning marker
+	// +optional
+	RequireNodeFile string `json:"requireNodeFile,omitempty"`
+
+	// ExpectedCarrier is a list of interfaces expected to have carrier
+	// after applying the desired state, the enactment is available as
+	// ConfiguredLinkDown otherwise. Interfaces not listed, like the ones
+	// intentionally without cable, are not checked
+	// +optional
+	ExpectedCarrier []string `json:"expectedCarrier,omitempty"`
+
+	// RouteTableCleanup removes, after applying the desired state, the
+	// static routes left at the route tables of the desired state routes,
+	// besides the main one, that are not at the desired state. Routes of
+	// other route tables or added by other protocols are not touched
+	// +optional
+	RouteTableCleanup bool `json:"routeTableCleanup,omitempty"`
+
+	// Rollout configures how the policy is rolled out at the matching
+	// nodes
+	// +optional
+	Rollout *PolicyRollout `json:"rollout,omitempty"`
+
+	// ReconcileInterval is how often each node checks that the desired
+	// state is still applied, applying it again if it has drifted. Without
+	// it the policy is only reconciled when it or the node changes
+	// +optional
+	ReconcileInterval *metav1.Duration `json:"reconcileInterval,omitempty"`
+
+	// ApplyTimeout bounds how long nmstate can take applying the desired
+	// state, it is aborted and the enactment fails if it takes longer.
+	// Without it nmstate is waited for until it returns
+	// +optional
+	ApplyTimeout *metav1.Duration `json:"applyTimeout,omitempty"`
+
+	// AnycastAddresses are static IP addresses the desired state can set
+	// at more than one node, the enactment warns about the rest of them if
+	// they are rendered by the policy for another node too
+	// +optional
+	AnycastAddresses []string `json:"anycastAddresses,omitempty"`
+
+	// Stage makes the nodes only check and stage the desired state,
+	// holding it as Staged until the policy activate annotation is set
+	// to the policy generation, then all of them apply it at once
+	// +optional
+	Stage bool `json:"stage,omitempty"`
+}
+
+// CloudSelector matches the cloud nodes by their instance metadata, a node
+// matches if it has one of the values given for each of the fields
+// +k8s:openapi-gen=true
+type CloudSelector struct {
+	// Instance types, from the node.kubernetes.io/instance-type label
+	// +optional
+	InstanceTypes []string `json:"instanceTypes,omitempty"`
+
+	// Regions, from the topology.kubernetes.io/region label
+	// +optional
+	Regions []string `json:"regions,omitempty"`
+
+	// Zones, from the topology.kubernetes.io/zone label
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+}
+
+// PolicyRollout configures how the policy is rolled out at the matching nodes
+// +k8s:openapi-gen=true
+type PolicyRollout struct {
+	// OnFailure is Continue to keep applying the policy at the rest of the
+	// nodes when it fails at one of them, the default, or Halt to stop
+	// applying it at the nodes that have not applied it yet
+	// +kubebuilder:validation:Enum=Halt;Continue
+	// +optional
+	OnFailure RolloutOnFailure `json:"onFailure,omitempty"`
+
+	// Topology is Zone to roll the policy out zone by zone, in the order of
+	// the node topology.kubernetes.io/zone label values, the nodes of a zone
+	// apply it once it's available at the previous zones. Empty rolls it
+	// out at all the nodes at the same time.
+	// +kubebuilder:validation:Enum=Zone
+	// +optional
+	Topology RolloutTopology `json:"topology,omitempty"`
+
+	// Priority orders the policies rolled out by zone within each zone, the
+	// nodes of a zone apply the policy once the ones with higher priority
+	// are available at the zone
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+type RolloutOnFailure string
+
+const (
+	RolloutOnFailureHalt     RolloutOnFailure = "Halt"
+	RolloutOnFailureContinue RolloutOnFailure = "Continue"
+)
+
+type RolloutTopology string
+
+const (
+	RolloutTopologyZone RolloutTopology = "Zone"
+)
+
+// ReadinessCheck is a condition the node has to fulfill after applying the
+// desired state
+// +k8s:openapi-gen=true
+type ReadinessCheck struct {
+	// AnyInterfaceUp passes if at least one of the interfaces is up with
+	// carrier and a global IP address, like a set of redundant uplinks
+	// +optional
+	AnyInterfaceUp []string `json:"anyInterfaceUp,omitempty"`
+
+	// TCPConnect passes if the node can open a TCP connection to the
+	// host:port target, like an in-cluster service or external endpoint
+	// +optional
+	TCPConnect string `json:"tcpConnect,omitempty"`
+
+	// HTTPGet passes if a GET of the http or https URL from the node
+	// returns a status below 400
+	// +optional
+	HTTPGet string `json:"httpGet,omitempty"`
 }
 
 // NodeNetworkConfigurationPolicyStatus defines the observed state of NodeNetworkConfigurationPolicy
 // +k8s:openapi-gen=true
 type NodeNetworkConfigurationPolicyStatus struct {
 	Conditions ConditionList `json:"conditions,omitempty" optional:"true"`
+
+	// Summary of the enactments status for the policy, something like
+	// "8/10 Available, 1 Failed, 1 Progressing"
+	// +optional
+	Summary string `json:"summary,omitempty"`
+
+	// Compliance of the nodes with the desired state of audit policies,
+	// something like "2/3 nodes compliant, non compliant: node02"
+	// +optional
+	Compliance string `json:"compliance,omitempty"`
+
+	// Number of consecutive policy generations that failed to configure
+	// all the matching nodes
+	// +optional
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+
+	// Last policy generation that failed to configure all the matching nodes
+	// +optional
+	LastFailedGeneration int64 `json:"lastFailedGeneration,omitempty"`
+
+	// Value of the quarantine resume annotation used to lift
+	// the last quarantine
+	// +optional
+	QuarantineResume string `json:"quarantineResume,omitempty"`
+
+	// Planned rollout of the policy at the matching nodes and its
+	// progress, something like "3 matching nodes in 1 batch of 3, batch 1
+	// of 1 in progress"
+	// +optional
+	Rollout string `json:"rollout,omitempty"`
+
+	// Nodes applying the desired state right now, sorted and bounded, the
+	// summary has the count of all of them
+	// +optional
+	ProgressingNodes []string `json:"progressingNodes,omitempty"`
 }
 
+const (
+	// Changing the value of this annotation lifts the quarantine of a policy
+	NodeNetworkConfigurationPolicyQuarantineResumeAnnotation = "nmstate.io/quarantine-resume"
+
+	// Changing the value of this annotation resumes the halted rollout of
+	// a policy at the nodes that have not applied it yet
+	NodeNetworkConfigurationPolicyRolloutResumeAnnotation = "nmstate.io/rollout-resume"
+
+	// Setting this annotation to the policy generation activates the
+	// desired state staged at the nodes
+	NodeNetworkConfigurationPolicyActivateAnnotation = "nmstate.io/activate"
+
+	// Name of the secret, at the handler namespace, with the webhook
+	// notified when the policy finishes configuring or fails
+	NodeNetworkConfigurationPolicyNotificationSecretAnnotation = "nmstate.io/notification-secret"
+
+	// Set by the webhook to the fingerprint of the desired state of the
+	// policies rendered the same at every node, the handlers take it as is
+	// instead of rendering it again
+	NodeNetworkConfigurationPolicyNodeInvariantAnnotation = "nmstate.io/node-invariant"
+
+	// Node annotation making the desired state applies at the node fail
+	// with its value as error, only honored by handlers with e2e failure
+	// injection enabled
+	NodeFailApplyAnnotation = "nmstate.io/e2e-fail-apply"
+)
+
+const (
+	// Keeps the policy until the handlers have removed its enactments
+	NodeNetworkConfigurationPolicyEnactmentsFinalizer = "nmstate.io/enactments"
+
+	// Directory of the nodes the policies required files are allowed at,
+	// it's mounted read-only at the handler
+	NodeNetworkConfigurationPolicyNodeFilesDir = "/var/lib/nmstate/node-files"
+)
+
 const (
 	NodeNetworkConfigurationPolicyConditionAvailable ConditionType = "Available"
 	NodeNetworkConfigurationPolicyConditionDegraded  ConditionType = "Degraded"
+
+	// Only set for the policies with deprecated desired state fields, it's
+	// a warning not taken into account for the policy outcome
+	NodeNetworkConfigurationPolicyConditionDeprecated ConditionType = "Deprecated"
 )
 
 var NodeNetworkConfigurationPolicyConditionTypes = [...]ConditionType{
@@ -64,6 +323,11 @@ const (
 	NodeNetworkConfigurationPolicyConditionSuccessfullyConfigured      ConditionReason = "SuccessfullyConfigured"
 	NodeNetworkConfigurationPolicyConditionConfigurationProgressing    ConditionReason = "ConfigurationProgressing"
 	NodeNetworkConfigurationPolicyConditionConfigurationNoMatchingNode ConditionReason = "NoMatchingNode"
+	NodeNetworkConfigurationPolicyConditionQuarantined                 ConditionReason = "Quarantined"
+	NodeNetworkConfigurationPolicyConditionFewerNodesThanExpected      ConditionReason = "FewerNodesThanExpected"
+	NodeNetworkConfigurationPolicyConditionRolloutHalted               ConditionReason = "RolloutHalted"
+	NodeNetworkConfigurationPolicyConditionDeprecatedFields            ConditionReason = "DeprecatedFields"
+	NodeNetworkConfigurationPolicyConditionStaged                      ConditionReason = "Staged"
 )
 
 func init() {
@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeNetworkConfigurationPolicyBundleList contains a list of NodeNetworkConfigurationPolicyBundle
+type NodeNetworkConfigurationPolicyBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeNetworkConfigurationPolicyBundle `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeNetworkConfigurationPolicyBundle is the Schema for the nodenetworkconfigurationpolicybundles API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=nodenetworkconfigurationpolicybundles,shortName=nncpb,scope=Cluster
+type NodeNetworkConfigurationPolicyBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeNetworkConfigurationPolicyBundleSpec   `json:"spec,omitempty"`
+	Status NodeNetworkConfigurationPolicyBundleStatus `json:"status,omitempty"`
+}
+
+// NodeNetworkConfigurationPolicyBundleSpec defines the desired state of NodeNetworkConfigurationPolicyBundle
+// +k8s:openapi-gen=true
+type NodeNetworkConfigurationPolicyBundleSpec struct {
+	// Names of the policies applied together at every node, either all
+	// the ones matching the node are configured or none
+	Policies []string `json:"policies"`
+}
+
+// NodeNetworkConfigurationPolicyBundleStatus defines the observed state of NodeNetworkConfigurationPolicyBundle
+// +k8s:openapi-gen=true
+type NodeNetworkConfigurationPolicyBundleStatus struct {
+	Conditions ConditionList `json:"conditions,omitempty" optional:"true"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeNetworkConfigurationPolicyBundle{}, &NodeNetworkConfigurationPolicyBundleList{})
+}
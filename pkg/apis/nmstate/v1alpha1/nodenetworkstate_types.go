@@ -10,9 +10,200 @@ type NodeNetworkStateStatus struct {
 	CurrentState             State       `json:"currentState,omitempty"`
 	LastSuccessfulUpdateTime metav1.Time `json:"lastSuccessfulUpdateTime,omitempty"`
 
+	// ConfigSerial is increased every time the reported state changes, so
+	// consumers can detect changes without comparing the whole state
+	// +optional
+	ConfigSerial int64 `json:"configSerial,omitempty"`
+
+	// NetworkManager connection profiles present at the node
+	// +optional
+	Connections []NetworkManagerConnection `json:"connections,omitempty"`
+
+	// NetworkManager devices present at the node and whether
+	// NetworkManager manages them, it does not configure unmanaged ones
+	// +optional
+	Devices []NetworkManagerDevice `json:"devices,omitempty"`
+
+	// Effective routes at the node kernel routing tables, including the
+	// ones added by the kernel or learned by DHCP
+	// +optional
+	RunningRoutes []RunningRoute `json:"runningRoutes,omitempty"`
+
+	// Permanent neighbor entries at the node kernel, the static ARP and
+	// NDP ones
+	// +optional
+	StaticNeighbors []StaticNeighbor `json:"staticNeighbors,omitempty"`
+
+	// Effective status of the bonds at the node kernel, including the
+	// negotiated LACP state
+	// +optional
+	Bonds []BondStatus `json:"bonds,omitempty"`
+
+	// Recent carrier transitions of the interfaces, only reported if the
+	// handler is configured to keep them
+	// +optional
+	CarrierHistory []InterfaceCarrierHistory `json:"carrierHistory,omitempty"`
+
+	// Versions of the node kernel and network stack
+	// +optional
+	SystemInfo *SystemInfo `json:"systemInfo,omitempty"`
+
+	// Policies that last configured the node interfaces, taken from the
+	// desired state of the node enactments applied successfully
+	// +optional
+	InterfaceOwners []InterfaceOwner `json:"interfaceOwners,omitempty"`
+
 	Conditions ConditionList `json:"conditions,omitempty" optional:"true"`
 }
 
+// InterfaceOwner is the policy that last configured an interface at the node
+// +k8s:openapi-gen=true
+type InterfaceOwner struct {
+	Interface string `json:"interface"`
+	Policy    string `json:"policy"`
+	// Time the policy desired state was applied at
+	// +optional
+	AppliedAt *metav1.Time `json:"appliedAt,omitempty"`
+}
+
+// NetworkManagerConnection is a NetworkManager connection profile present at the node
+// +k8s:openapi-gen=true
+type NetworkManagerConnection struct {
+	Name   string `json:"name"`
+	UUID   string `json:"uuid"`
+	Type   string `json:"type,omitempty"`
+	Device string `json:"device,omitempty"`
+
+	// ExternallyManaged is true if the connection is not configured by
+	// any of the node's enactments, for example hand-crafted keyfiles
+	// +optional
+	ExternallyManaged bool `json:"externallyManaged,omitempty"`
+}
+
+// NetworkManagerDevice is a network device as seen by NetworkManager
+// +k8s:openapi-gen=true
+type NetworkManagerDevice struct {
+	Name  string `json:"name"`
+	Type  string `json:"type,omitempty"`
+	State string `json:"state,omitempty"`
+
+	// Managed is false if NetworkManager is configured to ignore the
+	// device, policies cannot configure it
+	Managed bool `json:"managed"`
+}
+
+// RunningRoute is a route present at the node kernel routing tables
+// +k8s:openapi-gen=true
+type RunningRoute struct {
+	Destination      string `json:"destination"`
+	NextHopAddress   string `json:"next-hop-address,omitempty"`
+	NextHopInterface string `json:"next-hop-interface,omitempty"`
+	Metric           int    `json:"metric,omitempty"`
+	Table            string `json:"table,omitempty"`
+
+	// Protocol that installed the route, like kernel, dhcp, static or ra
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// Preferred source address of the traffic sent through the route
+	// +optional
+	PreferredSource string `json:"preferredSource,omitempty"`
+
+	// Scope of the route destination, like global, link or host
+	// +optional
+	Scope string `json:"scope,omitempty"`
+}
+
+// SystemInfo are the versions of the node kernel and network stack
+// +k8s:openapi-gen=true
+type SystemInfo struct {
+	KernelVersion         string `json:"kernelVersion,omitempty"`
+	NetworkManagerVersion string `json:"networkManagerVersion,omitempty"`
+	NmstateVersion        string `json:"nmstateVersion,omitempty"`
+}
+
+// StaticNeighbor is a permanent neighbor entry present at the node kernel
+// +k8s:openapi-gen=true
+type StaticNeighbor struct {
+	IP               string `json:"ip"`
+	LinkLayerAddress string `json:"link-layer-address,omitempty"`
+	Interface        string `json:"interface"`
+}
+
+// BondStatus is the effective status of a bond at the node kernel
+// +k8s:openapi-gen=true
+type BondStatus struct {
+	Name     string `json:"name"`
+	Mode     string `json:"mode,omitempty"`
+	LACPRate string `json:"lacpRate,omitempty"`
+
+	// Active aggregator negotiated with the partner, only for 802.3ad
+	// +optional
+	AggregatorID      string `json:"aggregatorID,omitempty"`
+	ActorKey          string `json:"actorKey,omitempty"`
+	PartnerKey        string `json:"partnerKey,omitempty"`
+	PartnerMacAddress string `json:"partnerMacAddress,omitempty"`
+
+	// Slave carrying the traffic, only for the modes with an active slave,
+	// or None if all the slaves are down
+	// +optional
+	ActiveSlave string `json:"activeSlave,omitempty"`
+
+	// Last change of the active slave and the slave active before it, the
+	// kernel does not keep the failover time so it is the time of the
+	// first report seeing it
+	// +optional
+	PreviousActiveSlave string       `json:"previousActiveSlave,omitempty"`
+	LastFailoverTime    *metav1.Time `json:"lastFailoverTime,omitempty"`
+
+	Slaves []BondSlaveStatus `json:"slaves,omitempty"`
+}
+
+// BondSlaveStatus is the effective status of a bond slave at the node kernel
+// +k8s:openapi-gen=true
+type BondSlaveStatus struct {
+	Name         string `json:"name"`
+	MIIStatus    string `json:"miiStatus,omitempty"`
+	AggregatorID string `json:"aggregatorID,omitempty"`
+
+	// Times the slave link went down since it was added to the bond
+	// +optional
+	LinkFailureCount string `json:"linkFailureCount,omitempty"`
+
+	// LACP port state bits sent by the node and received from the partner
+	// +optional
+	ActorPortState   string `json:"actorPortState,omitempty"`
+	PartnerPortState string `json:"partnerPortState,omitempty"`
+}
+
+// InterfaceCarrierHistory is the bounded list of recent carrier transitions
+// of an interface
+// +k8s:openapi-gen=true
+type InterfaceCarrierHistory struct {
+	Name string `json:"name"`
+
+	// Kernel count of carrier changes since the interface was created
+	CarrierChanges int64 `json:"carrierChanges"`
+
+	// Transitions seen by the handler, oldest first
+	// +optional
+	Transitions []CarrierTransition `json:"transitions,omitempty"`
+}
+
+// CarrierTransition is a carrier change of an interface seen by the handler
+// +k8s:openapi-gen=true
+type CarrierTransition struct {
+	Time metav1.Time `json:"time"`
+
+	// Carrier after the transition, up or down
+	Carrier   string `json:"carrier"`
+	OperState string `json:"operState,omitempty"`
+
+	// Carrier changes counted by the kernel since the previous report, more
+	// than one if the link flapped in between
+	Changes int64 `json:"changes"`
+}
+
 const (
 	NodeNetworkStateConditionAvailable ConditionType = "Available"
 	NodeNetworkStateConditionFailing   ConditionType = "Failing"
@@ -30,7 +30,42 @@ func (t *State) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// NormalizeState returns the state as the YAML kubernetes-nmstate manages,
+// states may be given as JSON too by programmatic producers, YAML being a
+// superset of it, equivalent states are normalized to the same YAML
+func NormalizeState(state State) (State, error) {
+	if len(state.Raw) == 0 {
+		return state, nil
+	}
+	stateJSON, err := yaml.YAMLToJSON([]byte(state.Raw))
+	if err != nil {
+		return state, err
+	}
+	output, err := yaml.JSONToYAML(stateJSON)
+	if err != nil {
+		return state, err
+	}
+	return State{Raw: output}, nil
+}
+
 // Simple stringer for State
 func (t State) String() string {
 	return string(t.Raw)
 }
+
+func (t StatePatch) MarshalJSON() (output []byte, err error) {
+	return yaml.YAMLToJSON([]byte(t.Raw))
+}
+
+func (t *StatePatch) UnmarshalJSON(b []byte) error {
+	output, err := yaml.JSONToYAML(b)
+	if err != nil {
+		return err
+	}
+	*t = StatePatch{Raw: output}
+	return nil
+}
+
+func (t StatePatch) String() string {
+	return string(t.Raw)
+}
@@ -0,0 +1,61 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeNetworkStateLabelList contains a list of NodeNetworkStateLabel
+type NodeNetworkStateLabelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeNetworkStateLabel `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeNetworkStateLabel is the Schema for the nodenetworkstatelabels API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=nodenetworkstatelabels,shortName=nnsl,scope=Cluster
+// +kubebuilder:printcolumn:name="Key",type="string",JSONPath=".spec.key",description="Key of the node label"
+// +kubebuilder:printcolumn:name="Path",type="string",JSONPath=".spec.path",description="Path of the label value at the current state"
+type NodeNetworkStateLabel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NodeNetworkStateLabelSpec `json:"spec,omitempty"`
+}
+
+// NodeNetworkStateLabelSpec defines the desired state of NodeNetworkStateLabel
+// +k8s:openapi-gen=true
+type NodeNetworkStateLabelSpec struct {
+	// Key of the label set at the nodes, it has to be under the nmstate.io
+	// prefix, like nmstate.io/uplink-speed
+	Key string `json:"key"`
+
+	// Path of the label value at the node NodeNetworkState current state,
+	// in gjson syntax, like interfaces.#(name=="eth0").ethernet.speed. The
+	// nodes the path is not found at are not labeled
+	Path string `json:"path"`
+
+	// Value of the label at the nodes the path is found at, without it
+	// the label value is the one found at the path
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+const (
+	// Prefix the keys of the labels derived from the current state have to
+	// be under, so other labels of the nodes are never changed
+	NodeNetworkStateLabelPrefix = "nmstate.io/"
+
+	// Node annotation with the keys of the labels derived from the current
+	// state, so the ones of deleted NodeNetworkStateLabels are removed
+	NodeNetworkStateLabelsAnnotation = "nmstate.io/state-labels"
+)
+
+func init() {
+	SchemeBuilder.Register(&NodeNetworkStateLabel{}, &NodeNetworkStateLabelList{})
+}
@@ -23,3 +23,19 @@ func NewState(raw string) State {
 
 // [1] https://github.com/kubernetes/kube-openapi/tree/master/pkg/generators
 func (_ State) OpenAPISchemaType() []string { return []string{"object"} }
+
+// StatePatch contains a JSON patch [1], a list of operations, to apply to a
+// State, as yaml like State so it's kept transparent at kubernetes-nmstate.
+//
+// [1] https://tools.ietf.org/html/rfc6902
+// +k8s:openapi-gen=true
+// +kubebuilder:validation:Type=array
+type StatePatch struct {
+	Raw RawState `json:"-"`
+}
+
+func NewStatePatch(raw string) StatePatch {
+	return StatePatch{Raw: RawState(raw)}
+}
+
+func (_ StatePatch) OpenAPISchemaType() []string { return []string{"array"} }
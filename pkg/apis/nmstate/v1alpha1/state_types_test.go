@@ -0,0 +1,61 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	yaml "sigs.k8s.io/yaml"
+)
+
+var _ = Describe("State formats", func() {
+	yamlState := NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  ipv4:
+    enabled: true
+    dhcp: true
+`)
+	jsonState := NewState(`{
+	"interfaces": [
+		{
+			"name": "eth1",
+			"type": "ethernet",
+			"state": "up",
+			"ipv4": {"enabled": true, "dhcp": true}
+		}
+	]
+}`)
+
+	It("should normalize equivalent YAML and JSON states to the same YAML", func() {
+		normalizedYAML, err := NormalizeState(yamlState)
+		Expect(err).ToNot(HaveOccurred())
+		normalizedJSON, err := NormalizeState(jsonState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(normalizedJSON).To(Equal(normalizedYAML))
+		Expect(normalizedYAML.String()).To(MatchYAML(yamlState.String()))
+	})
+
+	It("should keep empty states empty", func() {
+		Expect(NormalizeState(NewState(""))).To(Equal(NewState("")))
+	})
+
+	It("should fail normalizing invalid states", func() {
+		_, err := NormalizeState(NewState(`{"interfaces": [`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should decode policies written as YAML and JSON to the same desired state", func() {
+		yamlPolicy := NodeNetworkConfigurationPolicy{}
+		Expect(yaml.Unmarshal([]byte(`spec:
+  desiredState:
+    interfaces:
+    - name: eth1
+      type: ethernet
+      state: up
+`), &yamlPolicy)).To(Succeed())
+		jsonPolicy := NodeNetworkConfigurationPolicy{}
+		Expect(yaml.Unmarshal([]byte(`{"spec": {"desiredState": {"interfaces": [{"state": "up", "type": "ethernet", "name": "eth1"}]}}}`), &jsonPolicy)).To(Succeed())
+		Expect(jsonPolicy.Spec.DesiredState).To(Equal(yamlPolicy.Spec.DesiredState))
+	})
+})
@@ -5,9 +5,117 @@
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplyResourceUsage) DeepCopyInto(out *ApplyResourceUsage) {
+	*out = *in
+	out.UserCPUTime = in.UserCPUTime
+	out.SystemCPUTime = in.SystemCPUTime
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplyResourceUsage.
+func (in *ApplyResourceUsage) DeepCopy() *ApplyResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplyResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BondSlaveStatus) DeepCopyInto(out *BondSlaveStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BondSlaveStatus.
+func (in *BondSlaveStatus) DeepCopy() *BondSlaveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BondSlaveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BondStatus) DeepCopyInto(out *BondStatus) {
+	*out = *in
+	if in.LastFailoverTime != nil {
+		in, out := &in.LastFailoverTime, &out.LastFailoverTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Slaves != nil {
+		in, out := &in.Slaves, &out.Slaves
+		*out = make([]BondSlaveStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BondStatus.
+func (in *BondStatus) DeepCopy() *BondStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BondStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CarrierTransition) DeepCopyInto(out *CarrierTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CarrierTransition.
+func (in *CarrierTransition) DeepCopy() *CarrierTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(CarrierTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudSelector) DeepCopyInto(out *CloudSelector) {
+	*out = *in
+	if in.InstanceTypes != nil {
+		in, out := &in.InstanceTypes, &out.InstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudSelector.
+func (in *CloudSelector) DeepCopy() *CloudSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -48,6 +156,118 @@ func (in ConditionList) DeepCopy() ConditionList {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnactmentRevision) DeepCopyInto(out *EnactmentRevision) {
+	*out = *in
+	in.DesiredState.DeepCopyInto(&out.DesiredState)
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnactmentRevision.
+func (in *EnactmentRevision) DeepCopy() *EnactmentRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(EnactmentRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceCarrierHistory) DeepCopyInto(out *InterfaceCarrierHistory) {
+	*out = *in
+	if in.Transitions != nil {
+		in, out := &in.Transitions, &out.Transitions
+		*out = make([]CarrierTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceCarrierHistory.
+func (in *InterfaceCarrierHistory) DeepCopy() *InterfaceCarrierHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceCarrierHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceOverride) DeepCopyInto(out *InterfaceOverride) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceOverride.
+func (in *InterfaceOverride) DeepCopy() *InterfaceOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterfaceOwner) DeepCopyInto(out *InterfaceOwner) {
+	*out = *in
+	if in.AppliedAt != nil {
+		in, out := &in.AppliedAt, &out.AppliedAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterfaceOwner.
+func (in *InterfaceOwner) DeepCopy() *InterfaceOwner {
+	if in == nil {
+		return nil
+	}
+	out := new(InterfaceOwner)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkManagerConnection) DeepCopyInto(out *NetworkManagerConnection) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkManagerConnection.
+func (in *NetworkManagerConnection) DeepCopy() *NetworkManagerConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkManagerConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkManagerDevice) DeepCopyInto(out *NetworkManagerDevice) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkManagerDevice.
+func (in *NetworkManagerDevice) DeepCopy() *NetworkManagerDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkManagerDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeNetworkConfigurationEnactment) DeepCopyInto(out *NodeNetworkConfigurationEnactment) {
 	*out = *in
@@ -112,6 +332,74 @@ func (in *NodeNetworkConfigurationEnactmentList) DeepCopyObject() runtime.Object
 func (in *NodeNetworkConfigurationEnactmentStatus) DeepCopyInto(out *NodeNetworkConfigurationEnactmentStatus) {
 	*out = *in
 	in.DesiredState.DeepCopyInto(&out.DesiredState)
+	in.AppliedDesiredState.DeepCopyInto(&out.AppliedDesiredState)
+	if in.PreviousForwarding != nil {
+		in, out := &in.PreviousForwarding, &out.PreviousForwarding
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = new(ApplyResourceUsage)
+		**out = **in
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = make([]NetworkManagerConnection, len(*in))
+		copy(*out, *in)
+	}
+	if in.IgnoredInterfaces != nil {
+		in, out := &in.IgnoredInterfaces, &out.IgnoredInterfaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OverriddenInterfaces != nil {
+		in, out := &in.OverriddenInterfaces, &out.OverriddenInterfaces
+		*out = make([]InterfaceOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.LinkFlaps != nil {
+		in, out := &in.LinkFlaps, &out.LinkFlaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedStaleRoutes != nil {
+		in, out := &in.RemovedStaleRoutes, &out.RemovedStaleRoutes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreApplySnapshot != nil {
+		in, out := &in.PreApplySnapshot, &out.PreApplySnapshot
+		*out = new(StateSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]EnactmentRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(ConditionList, len(*in))
@@ -160,6 +448,111 @@ func (in *NodeNetworkConfigurationPolicy) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNetworkConfigurationPolicyBundle) DeepCopyInto(out *NodeNetworkConfigurationPolicyBundle) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNetworkConfigurationPolicyBundle.
+func (in *NodeNetworkConfigurationPolicyBundle) DeepCopy() *NodeNetworkConfigurationPolicyBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNetworkConfigurationPolicyBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeNetworkConfigurationPolicyBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNetworkConfigurationPolicyBundleList) DeepCopyInto(out *NodeNetworkConfigurationPolicyBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeNetworkConfigurationPolicyBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNetworkConfigurationPolicyBundleList.
+func (in *NodeNetworkConfigurationPolicyBundleList) DeepCopy() *NodeNetworkConfigurationPolicyBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNetworkConfigurationPolicyBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeNetworkConfigurationPolicyBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNetworkConfigurationPolicyBundleSpec) DeepCopyInto(out *NodeNetworkConfigurationPolicyBundleSpec) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNetworkConfigurationPolicyBundleSpec.
+func (in *NodeNetworkConfigurationPolicyBundleSpec) DeepCopy() *NodeNetworkConfigurationPolicyBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNetworkConfigurationPolicyBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNetworkConfigurationPolicyBundleStatus) DeepCopyInto(out *NodeNetworkConfigurationPolicyBundleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(ConditionList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNetworkConfigurationPolicyBundleStatus.
+func (in *NodeNetworkConfigurationPolicyBundleStatus) DeepCopy() *NodeNetworkConfigurationPolicyBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNetworkConfigurationPolicyBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeNetworkConfigurationPolicyList) DeepCopyInto(out *NodeNetworkConfigurationPolicyList) {
 	*out = *in
@@ -203,7 +596,69 @@ func (in *NodeNetworkConfigurationPolicySpec) DeepCopyInto(out *NodeNetworkConfi
 			(*out)[key] = val
 		}
 	}
+	if in.CloudSelector != nil {
+		in, out := &in.CloudSelector, &out.CloudSelector
+		*out = new(CloudSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	in.DesiredState.DeepCopyInto(&out.DesiredState)
+	if in.DesiredStatePatch != nil {
+		in, out := &in.DesiredStatePatch, &out.DesiredStatePatch
+		*out = new(StatePatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostBootDelay != nil {
+		in, out := &in.PostBootDelay, &out.PostBootDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ProtectedInterfaces != nil {
+		in, out := &in.ProtectedInterfaces, &out.ProtectedInterfaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IgnoredInterfaces != nil {
+		in, out := &in.IgnoredInterfaces, &out.IgnoredInterfaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DriftIgnore != nil {
+		in, out := &in.DriftIgnore, &out.DriftIgnore
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReadinessChecks != nil {
+		in, out := &in.ReadinessChecks, &out.ReadinessChecks
+		*out = make([]ReadinessCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExpectedCarrier != nil {
+		in, out := &in.ExpectedCarrier, &out.ExpectedCarrier
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(PolicyRollout)
+		**out = **in
+	}
+	if in.ReconcileInterval != nil {
+		in, out := &in.ReconcileInterval, &out.ReconcileInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ApplyTimeout != nil {
+		in, out := &in.ApplyTimeout, &out.ApplyTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AnycastAddresses != nil {
+		in, out := &in.AnycastAddresses, &out.AnycastAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -227,6 +682,11 @@ func (in *NodeNetworkConfigurationPolicyStatus) DeepCopyInto(out *NodeNetworkCon
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ProgressingNodes != nil {
+		in, out := &in.ProgressingNodes, &out.ProgressingNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -267,6 +727,82 @@ func (in *NodeNetworkState) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNetworkStateLabel) DeepCopyInto(out *NodeNetworkStateLabel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNetworkStateLabel.
+func (in *NodeNetworkStateLabel) DeepCopy() *NodeNetworkStateLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNetworkStateLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeNetworkStateLabel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNetworkStateLabelList) DeepCopyInto(out *NodeNetworkStateLabelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeNetworkStateLabel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNetworkStateLabelList.
+func (in *NodeNetworkStateLabelList) DeepCopy() *NodeNetworkStateLabelList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNetworkStateLabelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeNetworkStateLabelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNetworkStateLabelSpec) DeepCopyInto(out *NodeNetworkStateLabelSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNetworkStateLabelSpec.
+func (in *NodeNetworkStateLabelSpec) DeepCopy() *NodeNetworkStateLabelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNetworkStateLabelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeNetworkStateList) DeepCopyInto(out *NodeNetworkStateList) {
 	*out = *in
@@ -305,6 +841,52 @@ func (in *NodeNetworkStateStatus) DeepCopyInto(out *NodeNetworkStateStatus) {
 	*out = *in
 	in.CurrentState.DeepCopyInto(&out.CurrentState)
 	in.LastSuccessfulUpdateTime.DeepCopyInto(&out.LastSuccessfulUpdateTime)
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = make([]NetworkManagerConnection, len(*in))
+		copy(*out, *in)
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]NetworkManagerDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.RunningRoutes != nil {
+		in, out := &in.RunningRoutes, &out.RunningRoutes
+		*out = make([]RunningRoute, len(*in))
+		copy(*out, *in)
+	}
+	if in.StaticNeighbors != nil {
+		in, out := &in.StaticNeighbors, &out.StaticNeighbors
+		*out = make([]StaticNeighbor, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bonds != nil {
+		in, out := &in.Bonds, &out.Bonds
+		*out = make([]BondStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CarrierHistory != nil {
+		in, out := &in.CarrierHistory, &out.CarrierHistory
+		*out = make([]InterfaceCarrierHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SystemInfo != nil {
+		in, out := &in.SystemInfo, &out.SystemInfo
+		*out = new(SystemInfo)
+		**out = **in
+	}
+	if in.InterfaceOwners != nil {
+		in, out := &in.InterfaceOwners, &out.InterfaceOwners
+		*out = make([]InterfaceOwner, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(ConditionList, len(*in))
@@ -325,6 +907,22 @@ func (in *NodeNetworkStateStatus) DeepCopy() *NodeNetworkStateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRollout) DeepCopyInto(out *PolicyRollout) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRollout.
+func (in *PolicyRollout) DeepCopy() *PolicyRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in RawState) DeepCopyInto(out *RawState) {
 	{
@@ -345,6 +943,43 @@ func (in RawState) DeepCopy() RawState {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessCheck) DeepCopyInto(out *ReadinessCheck) {
+	*out = *in
+	if in.AnyInterfaceUp != nil {
+		in, out := &in.AnyInterfaceUp, &out.AnyInterfaceUp
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessCheck.
+func (in *ReadinessCheck) DeepCopy() *ReadinessCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunningRoute) DeepCopyInto(out *RunningRoute) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunningRoute.
+func (in *RunningRoute) DeepCopy() *RunningRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(RunningRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *State) DeepCopyInto(out *State) {
 	*out = *in
@@ -365,3 +1000,78 @@ func (in *State) DeepCopy() *State {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatePatch) DeepCopyInto(out *StatePatch) {
+	*out = *in
+	if in.Raw != nil {
+		in, out := &in.Raw, &out.Raw
+		*out = make(RawState, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatePatch.
+func (in *StatePatch) DeepCopy() *StatePatch {
+	if in == nil {
+		return nil
+	}
+	out := new(StatePatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateSnapshot) DeepCopyInto(out *StateSnapshot) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateSnapshot.
+func (in *StateSnapshot) DeepCopy() *StateSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(StateSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticNeighbor) DeepCopyInto(out *StaticNeighbor) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticNeighbor.
+func (in *StaticNeighbor) DeepCopy() *StaticNeighbor {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticNeighbor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemInfo) DeepCopyInto(out *SystemInfo) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemInfo.
+func (in *SystemInfo) DeepCopy() *SystemInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemInfo)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -11,196 +11,235 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"./pkg/apis/nmstate/v1alpha1.Condition":                               schema_pkg_apis_nmstate_v1alpha1_Condition(ref),
-		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationEnactment":       schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationEnactment(ref),
-		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationEnactmentStatus": schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationEnactmentStatus(ref),
-		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicy":          schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicy(ref),
-		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicySpec":      schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicySpec(ref),
-		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyStatus":    schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicyStatus(ref),
-		"./pkg/apis/nmstate/v1alpha1.NodeNetworkState":                        schema_pkg_apis_nmstate_v1alpha1_NodeNetworkState(ref),
-		"./pkg/apis/nmstate/v1alpha1.NodeNetworkStateStatus":                  schema_pkg_apis_nmstate_v1alpha1_NodeNetworkStateStatus(ref),
-		"./pkg/apis/nmstate/v1alpha1.State":                                   schema_pkg_apis_nmstate_v1alpha1_State(ref),
+		"./pkg/apis/nmstate/v1alpha1.BondSlaveStatus":                            schema_pkg_apis_nmstate_v1alpha1_BondSlaveStatus(ref),
+		"./pkg/apis/nmstate/v1alpha1.BondStatus":                                 schema_pkg_apis_nmstate_v1alpha1_BondStatus(ref),
+		"./pkg/apis/nmstate/v1alpha1.CarrierTransition":                          schema_pkg_apis_nmstate_v1alpha1_CarrierTransition(ref),
+		"./pkg/apis/nmstate/v1alpha1.CloudSelector":                              schema_pkg_apis_nmstate_v1alpha1_CloudSelector(ref),
+		"./pkg/apis/nmstate/v1alpha1.Condition":                                  schema_pkg_apis_nmstate_v1alpha1_Condition(ref),
+		"./pkg/apis/nmstate/v1alpha1.EnactmentRevision":                          schema_pkg_apis_nmstate_v1alpha1_EnactmentRevision(ref),
+		"./pkg/apis/nmstate/v1alpha1.InterfaceCarrierHistory":                    schema_pkg_apis_nmstate_v1alpha1_InterfaceCarrierHistory(ref),
+		"./pkg/apis/nmstate/v1alpha1.InterfaceOverride":                          schema_pkg_apis_nmstate_v1alpha1_InterfaceOverride(ref),
+		"./pkg/apis/nmstate/v1alpha1.InterfaceOwner":                             schema_pkg_apis_nmstate_v1alpha1_InterfaceOwner(ref),
+		"./pkg/apis/nmstate/v1alpha1.NetworkManagerConnection":                   schema_pkg_apis_nmstate_v1alpha1_NetworkManagerConnection(ref),
+		"./pkg/apis/nmstate/v1alpha1.NetworkManagerDevice":                       schema_pkg_apis_nmstate_v1alpha1_NetworkManagerDevice(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationEnactment":          schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationEnactment(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationEnactmentStatus":    schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationEnactmentStatus(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicy":             schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicy(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyBundle":       schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicyBundle(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyBundleSpec":   schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicyBundleSpec(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyBundleStatus": schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicyBundleStatus(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicySpec":         schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicySpec(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyStatus":       schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicyStatus(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkState":                           schema_pkg_apis_nmstate_v1alpha1_NodeNetworkState(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkStateLabel":                      schema_pkg_apis_nmstate_v1alpha1_NodeNetworkStateLabel(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkStateLabelSpec":                  schema_pkg_apis_nmstate_v1alpha1_NodeNetworkStateLabelSpec(ref),
+		"./pkg/apis/nmstate/v1alpha1.NodeNetworkStateStatus":                     schema_pkg_apis_nmstate_v1alpha1_NodeNetworkStateStatus(ref),
+		"./pkg/apis/nmstate/v1alpha1.PolicyRollout":                              schema_pkg_apis_nmstate_v1alpha1_PolicyRollout(ref),
+		"./pkg/apis/nmstate/v1alpha1.ReadinessCheck":                             schema_pkg_apis_nmstate_v1alpha1_ReadinessCheck(ref),
+		"./pkg/apis/nmstate/v1alpha1.RunningRoute":                               schema_pkg_apis_nmstate_v1alpha1_RunningRoute(ref),
+		"./pkg/apis/nmstate/v1alpha1.State":                                      schema_pkg_apis_nmstate_v1alpha1_State(ref),
+		"./pkg/apis/nmstate/v1alpha1.StatePatch":                                 schema_pkg_apis_nmstate_v1alpha1_StatePatch(ref),
+		"./pkg/apis/nmstate/v1alpha1.StateSnapshot":                              schema_pkg_apis_nmstate_v1alpha1_StateSnapshot(ref),
+		"./pkg/apis/nmstate/v1alpha1.StaticNeighbor":                             schema_pkg_apis_nmstate_v1alpha1_StaticNeighbor(ref),
+		"./pkg/apis/nmstate/v1alpha1.SystemInfo":                                 schema_pkg_apis_nmstate_v1alpha1_SystemInfo(ref),
 	}
 }
 
-func schema_pkg_apis_nmstate_v1alpha1_Condition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_nmstate_v1alpha1_BondSlaveStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "BondSlaveStatus is the effective status of a bond slave at the node kernel",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"type": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"status": {
+					"miiStatus": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"reason": {
+					"aggregatorID": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"message": {
+					"linkFailureCount": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Times the slave link went down since it was added to the bond",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"lastHearbeatTime": {
+					"actorPortState": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "LACP port state bits sent by the node and received from the partner",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"lastTransitionTime": {
+					"partnerPortState": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
 				},
-				Required: []string{"type", "status"},
+				Required: []string{"name"},
 			},
 		},
-		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationEnactment(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_nmstate_v1alpha1_BondStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "NodeNetworkConfigurationEnactment is the Schema for the nodenetworkconfigurationenactments API",
+				Description: "BondStatus is the effective status of a bond at the node kernel",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"apiVersion": {
+					"mode": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lacpRate": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"aggregatorID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Active aggregator negotiated with the partner, only for 802.3ad",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"actorKey": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"status": {
+					"partnerKey": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationEnactmentStatus"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationEnactmentStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
-	}
-}
-
-func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationEnactmentStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "NodeNetworkConfigurationEnactmentStatus defines the observed state of NodeNetworkConfigurationEnactment",
-				Type:        []string{"object"},
-				Properties: map[string]spec.Schema{
-					"desiredState": {
+					"partnerMacAddress": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The desired state rendered for the enactment's node using the policy desiredState as template",
-							Ref:         ref("./pkg/apis/nmstate/v1alpha1.State"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"conditions": {
+					"activeSlave": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Slave carrying the traffic, only for the modes with an active slave, or None if all the slaves are down",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"previousActiveSlave": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Last change of the active slave and the slave active before it, the kernel does not keep the failover time so it is the time of the first report seeing it",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastFailoverTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"slaves": {
 						SchemaProps: spec.SchemaProps{
 							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("./pkg/apis/nmstate/v1alpha1.Condition"),
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.BondSlaveStatus"),
 									},
 								},
 							},
 						},
 					},
 				},
+				Required: []string{"name"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/nmstate/v1alpha1.Condition", "./pkg/apis/nmstate/v1alpha1.State"},
+			"./pkg/apis/nmstate/v1alpha1.BondSlaveStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_nmstate_v1alpha1_CarrierTransition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "NodeNetworkConfigurationPolicy is the Schema for the nodenetworkconfigurationpolicies API",
+				Description: "CarrierTransition is a carrier change of an interface seen by the handler",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"time": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"apiVersion": {
+					"carrier": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Description: "Carrier after the transition, up or down",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
-						},
-					},
-					"spec": {
+					"operState": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicySpec"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"status": {
+					"changes": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyStatus"),
+							Description: "Carrier changes counted by the kernel since the previous report, more than one if the link flapped in between",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
 				},
+				Required: []string{"time", "carrier", "changes"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicySpec", "./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_nmstate_v1alpha1_CloudSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "NodeNetworkConfigurationPolicySpec defines the desired state of NodeNetworkConfigurationPolicy",
+				Description: "CloudSelector matches the cloud nodes by their instance metadata, a node matches if it has one of the values given for each of the fields",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"nodeSelector": {
+					"instanceTypes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "NodeSelector is a selector which must be true for the policy to be applied to the node. Selector which must match a node's labels for the policy to be scheduled on that node. More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/",
-							Type:        []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
+							Description: "Instance types, from the node.kubernetes.io/instance-type label",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Type:   []string{"string"},
@@ -210,129 +249,1492 @@ func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicySpec(ref com
 							},
 						},
 					},
-					"desiredState": {
+					"regions": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The desired configuration of the policy",
-							Ref:         ref("./pkg/apis/nmstate/v1alpha1.State"),
+							Description: "Regions, from the topology.kubernetes.io/region label",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"zones": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Zones, from the topology.kubernetes.io/zone label",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
-		Dependencies: []string{
-			"./pkg/apis/nmstate/v1alpha1.State"},
 	}
 }
 
-func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicyStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_nmstate_v1alpha1_Condition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "NodeNetworkConfigurationPolicyStatus defines the observed state of NodeNetworkConfigurationPolicy",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"conditions": {
+					"type": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("./pkg/apis/nmstate/v1alpha1.Condition"),
-									},
-								},
-							},
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lastHearbeatTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
 				},
+				Required: []string{"type", "status"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/nmstate/v1alpha1.Condition"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_nmstate_v1alpha1_EnactmentRevision(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "NodeNetworkState is the Schema for the nodenetworkstates API",
+				Description: "EnactmentRevision is a desired state previously applied by the enactment along with its result",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"policyGeneration": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Generation of the policy the desired state was taken from",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-					"apiVersion": {
+					"desiredState": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
+							Ref: ref("./pkg/apis/nmstate/v1alpha1.State"),
 						},
 					},
-					"metadata": {
+					"finishedAt": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Description: "Time the desired state apply finished at, empty if it did not finish",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
 						},
 					},
-					"status": {
+					"reason": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/nmstate/v1alpha1.NodeNetworkStateStatus"),
+							Description: "Reason of the enactment condition the apply finished with",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/nmstate/v1alpha1.NodeNetworkStateStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"./pkg/apis/nmstate/v1alpha1.State", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkStateStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_nmstate_v1alpha1_InterfaceCarrierHistory(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "NodeNetworkStateStatus is the status of the NodeNetworkState of a specific node",
+				Description: "InterfaceCarrierHistory is the bounded list of recent carrier transitions of an interface",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"currentState": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/nmstate/v1alpha1.State"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"lastSuccessfulUpdateTime": {
+					"carrierChanges": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "Kernel count of carrier changes since the interface was created",
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
-					"conditions": {
+					"transitions": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "Transitions seen by the handler, oldest first",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("./pkg/apis/nmstate/v1alpha1.Condition"),
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.CarrierTransition"),
 									},
 								},
 							},
 						},
 					},
 				},
+				Required: []string{"name", "carrierChanges"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/nmstate/v1alpha1.Condition", "./pkg/apis/nmstate/v1alpha1.State", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"./pkg/apis/nmstate/v1alpha1.CarrierTransition"},
 	}
 }
 
-func schema_pkg_apis_nmstate_v1alpha1_State(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_nmstate_v1alpha1_InterfaceOverride(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "State contains the namestatectl yaml [1] as string instead of golang struct so we don't need to be in sync with the schema.\n\n[1] https://github.com/nmstate/nmstate/blob/master/libnmstate/schemas/operational-state.yaml",
+				Description: "InterfaceOverride is an interface of a base policy configured by another policy at the node",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"interface": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"policy": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"interface", "policy"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_InterfaceOwner(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "InterfaceOwner is the policy that last configured an interface at the node",
 				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"interface": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"policy": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"appliedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time the policy desired state was applied at",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"interface", "policy"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NetworkManagerConnection(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NetworkManagerConnection is a NetworkManager connection profile present at the node",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"uuid": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"device": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"externallyManaged": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExternallyManaged is true if the connection is not configured by any of the node's enactments, for example hand-crafted keyfiles",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "uuid"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NetworkManagerDevice(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NetworkManagerDevice is a network device as seen by NetworkManager",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"managed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Managed is false if NetworkManager is configured to ignore the device, policies cannot configure it",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "managed"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationEnactment(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkConfigurationEnactment is the Schema for the nodenetworkconfigurationenactments API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationEnactmentStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationEnactmentStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationEnactmentStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkConfigurationEnactmentStatus defines the observed state of NodeNetworkConfigurationEnactment",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"desiredState": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The desired state rendered for the enactment's node using the policy desiredState as template",
+							Ref:         ref("./pkg/apis/nmstate/v1alpha1.State"),
+						},
+					},
+					"appliedDesiredState": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The desired state last applied successfully at the node, the settings dropped from the policy since then are removed from it",
+							Ref:         ref("./pkg/apis/nmstate/v1alpha1.State"),
+						},
+					},
+					"previousForwarding": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The global forwarding the node had before the policy set it, by family, it's restored once the policy drops the family",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"boolean"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"startedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time the last desired state apply started and finished at the node and how long it took, the finish time and duration are empty while it's in progress",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"finishedAt": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"resourceUsage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CPU time the commands run by the last desired state apply consumed at the node, empty while it's in progress",
+							Ref:         ref("./pkg/apis/nmstate/v1alpha1.ApplyResourceUsage"),
+						},
+					},
+					"drift": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Paths of the applied desired state that do not match the node current state anymore, refreshed with each node network state report",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"connections": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NetworkManager connections named by the desired state, with the UUID given to them at the node",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.NetworkManagerConnection"),
+									},
+								},
+							},
+						},
+					},
+					"ignoredInterfaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Policy ignored interfaces removed from the desired state, nmstate leaves them alone",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"overriddenInterfaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Interfaces of a base policy configured by other policies matching the node, removed from the desired state so those policies take precedence",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.InterfaceOverride"),
+									},
+								},
+							},
+						},
+					},
+					"policyGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Generation of the policy the enactment desired state was taken from",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"rolloutResume": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value of the policy rollout resume annotation when the policy generation was taken, the failures from before the rollout was resumed do not halt it",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"linkFlaps": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Links bounced by the MTU changes of the last applied desired state, the NIC drivers reset them to change their MTU whatever the order",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"removedStaleRoutes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Stale routes removed from the policy route tables after the last applied desired state, with the policy route table cleanup",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"preApplySnapshot": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Node current state taken right before the last desired state apply started, to debug it after the fact",
+							Ref:         ref("./pkg/apis/nmstate/v1alpha1.StateSnapshot"),
+						},
+					},
+					"history": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Desired states previously applied at the node, oldest first, only kept if the handler is configured to keep them",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.EnactmentRevision"),
+									},
+								},
+							},
+						},
+					},
+					"failureCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Stable code of the failure reported at the Failing condition, empty if it's not failing, to branch on it instead of on the failure message",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/nmstate/v1alpha1.ApplyResourceUsage", "./pkg/apis/nmstate/v1alpha1.Condition", "./pkg/apis/nmstate/v1alpha1.EnactmentRevision", "./pkg/apis/nmstate/v1alpha1.InterfaceOverride", "./pkg/apis/nmstate/v1alpha1.NetworkManagerConnection", "./pkg/apis/nmstate/v1alpha1.State", "./pkg/apis/nmstate/v1alpha1.StateSnapshot", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkConfigurationPolicy is the Schema for the nodenetworkconfigurationpolicies API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicySpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicySpec", "./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicyBundle(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkConfigurationPolicyBundle is the Schema for the nodenetworkconfigurationpolicybundles API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyBundleSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyBundleStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyBundleSpec", "./pkg/apis/nmstate/v1alpha1.NodeNetworkConfigurationPolicyBundleStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicyBundleSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkConfigurationPolicyBundleSpec defines the desired state of NodeNetworkConfigurationPolicyBundle",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"policies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Names of the policies applied together at every node, either all the ones matching the node are configured or none",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"policies"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicyBundleStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkConfigurationPolicyBundleStatus defines the observed state of NodeNetworkConfigurationPolicyBundle",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/nmstate/v1alpha1.Condition"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkConfigurationPolicySpec defines the desired state of NodeNetworkConfigurationPolicy",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"nodeSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeSelector is a selector which must be true for the policy to be applied to the node. Selector which must match a node's labels for the policy to be scheduled on that node. More info: https://kubernetes.io/docs/concepts/configuration/assign-pod-node/",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"cloudSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CloudSelector restricts the policy to the cloud nodes with the given instance metadata, taken from the well-known labels set by the cloud providers, the rest of the nodes are not matching the policy",
+							Ref:         ref("./pkg/apis/nmstate/v1alpha1.CloudSelector"),
+						},
+					},
+					"desiredState": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The desired configuration of the policy",
+							Ref:         ref("./pkg/apis/nmstate/v1alpha1.State"),
+						},
+					},
+					"desiredStatePatch": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DesiredStatePatch is a JSON patch applied to the node current state to produce the desired configuration of the policy, it cannot be set together with DesiredState",
+							Ref:         ref("./pkg/apis/nmstate/v1alpha1.StatePatch"),
+						},
+					},
+					"postBootDelay": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PostBootDelay is the minimum time the node has to be up before the desired state is applied, so it does not race with the network initialization after a reboot",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"protectedInterfaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ProtectedInterfaces is a list of interfaces the policy must never modify, the enactment fails if the desired state changes them or attaches them to a bridge or bond",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"base": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Base marks the policy as a baseline for the nodes it matches, the interfaces configured by other policies matching a node are removed from its desired state there so those policies take precedence",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"ignoredInterfaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IgnoredInterfaces is a list of interfaces managed by others, like CNI plugins, they are removed from the desired state before applying it so nmstate leaves them alone",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"driftIgnore": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DriftIgnore is a list of paths of the desired state, like interfaces.*.ipv6.address, excluded when comparing it with the node current state to detect drift",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"audit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Audit makes the policy only report if the nodes comply with the desired state, it's never applied",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"readinessChecks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadinessChecks have to pass after applying the desired state for the enactment to be available, otherwise it's rolled back",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.ReadinessCheck"),
+									},
+								},
+							},
+						},
+					},
+					"expectedNodeCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExpectedNodeCount is the minimum number of nodes the policy is expected to match, the policy is degraded if it matches fewer of them, like when some nodes lost a label",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"requireNodeFile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RequireNodeFile is the path of a file, under /var/lib/nmstate/node-files, that has to exist at the node for the policy to be applied there, like a provisioning marker",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"expectedCarrier": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExpectedCarrier is a list of interfaces expected to have carrier after applying the desired state, the enactment is available as ConfiguredLinkDown otherwise. Interfaces not listed, like the ones intentionally without cable, are not checked",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"routeTableCleanup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RouteTableCleanup removes, after applying the desired state, the static routes left at the route tables of the desired state routes, besides the main one, that are not at the desired state. Routes of other route tables or added by other protocols are not touched",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"rollout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rollout configures how the policy is rolled out at the matching nodes",
+							Ref:         ref("./pkg/apis/nmstate/v1alpha1.PolicyRollout"),
+						},
+					},
+					"reconcileInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReconcileInterval is how often each node checks that the desired state is still applied, applying it again if it has drifted. Without it the policy is only reconciled when it or the node changes",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"applyTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ApplyTimeout bounds how long nmstate can take applying the desired state, it is aborted and the enactment fails if it takes longer. Without it nmstate is waited for until it returns",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"anycastAddresses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AnycastAddresses are static IP addresses the desired state can set at more than one node, the enactment warns about the rest of them if they are rendered by the policy for another node too",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"stage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Stage makes the nodes only check and stage the desired state, holding it as Staged until the policy activate annotation is set to the policy generation, then all of them apply it at once",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/nmstate/v1alpha1.CloudSelector", "./pkg/apis/nmstate/v1alpha1.PolicyRollout", "./pkg/apis/nmstate/v1alpha1.ReadinessCheck", "./pkg/apis/nmstate/v1alpha1.State", "./pkg/apis/nmstate/v1alpha1.StatePatch", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkConfigurationPolicyStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkConfigurationPolicyStatus defines the observed state of NodeNetworkConfigurationPolicy",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"summary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Summary of the enactments status for the policy, something like \"8/10 Available, 1 Failed, 1 Progressing\"",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"compliance": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Compliance of the nodes with the desired state of audit policies, something like \"2/3 nodes compliant, non compliant: node02\"",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"consecutiveFailures": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of consecutive policy generations that failed to configure all the matching nodes",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"lastFailedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Last policy generation that failed to configure all the matching nodes",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"quarantineResume": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value of the quarantine resume annotation used to lift the last quarantine",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"rollout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Planned rollout of the policy at the matching nodes and its progress, something like \"3 matching nodes in 1 batch of 3, batch 1 of 1 in progress\"",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"progressingNodes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Nodes applying the desired state right now, sorted and bounded, the summary has the count of all of them",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/nmstate/v1alpha1.Condition"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkState(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkState is the Schema for the nodenetworkstates API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/nmstate/v1alpha1.NodeNetworkStateStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/nmstate/v1alpha1.NodeNetworkStateStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkStateLabel(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkStateLabel is the Schema for the nodenetworkstatelabels API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/nmstate/v1alpha1.NodeNetworkStateLabelSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/nmstate/v1alpha1.NodeNetworkStateLabelSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkStateLabelSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkStateLabelSpec defines the desired state of NodeNetworkStateLabel",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"key": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Key of the label set at the nodes, it has to be under the nmstate.io prefix, like nmstate.io/uplink-speed",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path of the label value at the node NodeNetworkState current state, in gjson syntax, like interfaces.#(name==\"eth0\").ethernet.speed. The nodes the path is not found at are not labeled",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value of the label at the nodes the path is found at, without it the label value is the one found at the path",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"key", "path"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_NodeNetworkStateStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeNetworkStateStatus is the status of the NodeNetworkState of a specific node",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"currentState": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/nmstate/v1alpha1.State"),
+						},
+					},
+					"lastSuccessfulUpdateTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"configSerial": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigSerial is increased every time the reported state changes, so consumers can detect changes without comparing the whole state",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"connections": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NetworkManager connection profiles present at the node",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.NetworkManagerConnection"),
+									},
+								},
+							},
+						},
+					},
+					"devices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NetworkManager devices present at the node and whether NetworkManager manages them, it does not configure unmanaged ones",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.NetworkManagerDevice"),
+									},
+								},
+							},
+						},
+					},
+					"runningRoutes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Effective routes at the node kernel routing tables, including the ones added by the kernel or learned by DHCP",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.RunningRoute"),
+									},
+								},
+							},
+						},
+					},
+					"staticNeighbors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Permanent neighbor entries at the node kernel, the static ARP and NDP ones",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.StaticNeighbor"),
+									},
+								},
+							},
+						},
+					},
+					"bonds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Effective status of the bonds at the node kernel, including the negotiated LACP state",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.BondStatus"),
+									},
+								},
+							},
+						},
+					},
+					"carrierHistory": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Recent carrier transitions of the interfaces, only reported if the handler is configured to keep them",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.InterfaceCarrierHistory"),
+									},
+								},
+							},
+						},
+					},
+					"systemInfo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Versions of the node kernel and network stack",
+							Ref:         ref("./pkg/apis/nmstate/v1alpha1.SystemInfo"),
+						},
+					},
+					"interfaceOwners": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Policies that last configured the node interfaces, taken from the desired state of the node enactments applied successfully",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.InterfaceOwner"),
+									},
+								},
+							},
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/nmstate/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/nmstate/v1alpha1.BondStatus", "./pkg/apis/nmstate/v1alpha1.Condition", "./pkg/apis/nmstate/v1alpha1.InterfaceCarrierHistory", "./pkg/apis/nmstate/v1alpha1.InterfaceOwner", "./pkg/apis/nmstate/v1alpha1.NetworkManagerConnection", "./pkg/apis/nmstate/v1alpha1.NetworkManagerDevice", "./pkg/apis/nmstate/v1alpha1.RunningRoute", "./pkg/apis/nmstate/v1alpha1.State", "./pkg/apis/nmstate/v1alpha1.StaticNeighbor", "./pkg/apis/nmstate/v1alpha1.SystemInfo", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_PolicyRollout(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PolicyRollout configures how the policy is rolled out at the matching nodes",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"onFailure": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OnFailure is Continue to keep applying the policy at the rest of the nodes when it fails at one of them, the default, or Halt to stop applying it at the nodes that have not applied it yet",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"topology": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Topology is Zone to roll the policy out zone by zone, in the order of the node topology.kubernetes.io/zone label values, the nodes of a zone apply it once it's available at the previous zones. Empty rolls it out at all the nodes at the same time.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"priority": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Priority orders the policies rolled out by zone within each zone, the nodes of a zone apply the policy once the ones with higher priority are available at the zone",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_ReadinessCheck(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ReadinessCheck is a condition the node has to fulfill after applying the desired state",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"anyInterfaceUp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AnyInterfaceUp passes if at least one of the interfaces is up with carrier and a global IP address, like a set of redundant uplinks",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"tcpConnect": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TCPConnect passes if the node can open a TCP connection to the host:port target, like an in-cluster service or external endpoint",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"httpGet": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HTTPGet passes if a GET of the http or https URL from the node returns a status below 400",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_RunningRoute(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RunningRoute is a route present at the node kernel routing tables",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"destination": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"next-hop-address": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"next-hop-interface": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"metric": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"table": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"protocol": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Protocol that installed the route, like kernel, dhcp, static or ra",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"preferredSource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Preferred source address of the traffic sent through the route",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"scope": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Scope of the route destination, like global, link or host",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"destination"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_State(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "State contains the namestatectl yaml [1] as string instead of golang struct so we don't need to be in sync with the schema.\n\n[1] https://github.com/nmstate/nmstate/blob/master/libnmstate/schemas/operational-state.yaml",
+				Type:        []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_StatePatch(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StatePatch contains a JSON patch [1], a list of operations, to apply to a State, as yaml like State so it's kept transparent at kubernetes-nmstate.\n\n[1] https://tools.ietf.org/html/rfc6902",
+				Type:        []string{"object"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_StateSnapshot(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StateSnapshot is a nmstatectl show output with its sensitive values redacted and gzip compressed",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"time": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time the snapshot was taken at",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Size of the uncompressed snapshot in bytes",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"data": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Compressed snapshot, base64 encoded, empty if it's omitted",
+							Type:        []string{"string"},
+							Format:      "byte",
+						},
+					},
+					"omitted": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Omitted is true if the compressed snapshot exceeds the size bound and it's not kept",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"time", "size"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_StaticNeighbor(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StaticNeighbor is a permanent neighbor entry present at the node kernel",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"ip": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"link-layer-address": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"interface": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"ip", "interface"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_nmstate_v1alpha1_SystemInfo(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SystemInfo are the versions of the node kernel and network stack",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kernelVersion": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"networkManagerVersion": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"nmstateVersion": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
 			},
 		},
 	}
@@ -0,0 +1,89 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// anycastAddresses returns the policy addresses allowed at several nodes in
+// their canonical form, so they compare with the desired state ones
+func anycastAddresses(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) map[string]bool {
+	addresses := map[string]bool{}
+	for _, address := range policy.Spec.AnycastAddresses {
+		if parsedAddress := net.ParseIP(address); parsedAddress != nil {
+			addresses[parsedAddress.String()] = true
+		}
+	}
+	return addresses
+}
+
+// conflictingAddresses returns the static addresses of the desired state
+// that the current policy generation has rendered for other matching nodes
+// too, like a template giving the same address to all of them, along with
+// those nodes. Failing enactments have not configured their addresses.
+func conflictingAddresses(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) ([]string, error) {
+	addresses, err := nmstate.StaticAddresses(policy.Spec.DesiredState)
+	if err != nil || len(addresses) == 0 {
+		return []string{}, err
+	}
+	anycast := anycastAddresses(policy)
+
+	enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+	err = cli.List(context.TODO(), &enactments, client.MatchingLabels{nmstatev1alpha1.EnactmentPolicyLabel: policy.Name})
+	if err != nil {
+		return []string{}, err
+	}
+
+	nodesByAddress := map[string][]string{}
+	for _, enactment := range enactments.Items {
+		node := enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel]
+		if node == nodeName || enactment.Status.PolicyGeneration != policy.Generation || !enactment.Status.Conditions.IsTrue(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionMatching) {
+			continue
+		}
+		if enactment.Status.Conditions.IsTrue(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing) {
+			continue
+		}
+		nodeAddresses, err := nmstate.StaticAddresses(enactment.Status.DesiredState)
+		if err != nil {
+			return []string{}, fmt.Errorf("failed reading enactment %s static addresses: %v", enactment.Name, err)
+		}
+		for _, address := range nodeAddresses {
+			nodesByAddress[address] = append(nodesByAddress[address], node)
+		}
+	}
+
+	conflicts := []string{}
+	for _, address := range addresses {
+		nodes := nodesByAddress[address]
+		if len(nodes) == 0 || anycast[address] {
+			continue
+		}
+		sort.Strings(nodes)
+		conflicts = append(conflicts, fmt.Sprintf("%s at %s", address, strings.Join(nodes, ", ")))
+	}
+	return conflicts, nil
+}
+
+// addressConflicts returns the static addresses conflicts of the applied
+// policy desired state, they are only warned about since the same address
+// may be meant to be at several nodes. They are checked once applied so
+// the nodes applying the policy at the same time see each other.
+func addressConflicts(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) []string {
+	conflicts, err := conflictingAddresses(cli, policy)
+	if err != nil {
+		log.Error(err, "failed checking static addresses conflicts", "policy", policy.Name)
+		return []string{}
+	}
+	if len(conflicts) > 0 {
+		log.Info("Desired state applied but its static addresses are rendered for other nodes too", "policy", policy.Name, "conflicts", conflicts)
+	}
+	return conflicts
+}
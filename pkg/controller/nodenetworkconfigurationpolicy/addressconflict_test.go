@@ -0,0 +1,92 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("Policy static addresses conflicts", func() {
+	desiredState := func(address string) nmstatev1alpha1.State {
+		return nmstatev1alpha1.NewState("interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  ipv4:\n    enabled: true\n    address:\n    - ip: " + address + "\n      prefix-length: 24\n")
+	}
+
+	policy := func(anycastAddresses ...string) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "eth1-policy", Generation: 2},
+		}
+		policy.Spec.DesiredState = desiredState("192.0.2.10")
+		policy.Spec.AnycastAddresses = anycastAddresses
+		return policy
+	}
+
+	enactment := func(node string, generation int64, address string, setters ...func(*nmstatev1alpha1.ConditionList, string)) *nmstatev1alpha1.NodeNetworkConfigurationEnactment {
+		enactment := nmstatev1alpha1.NewEnactment(node, policy())
+		enactment.Status.PolicyGeneration = generation
+		enactment.Status.DesiredState = desiredState(address)
+		for _, setter := range setters {
+			setter(&enactment.Status.Conditions, "")
+		}
+		return &enactment
+	}
+
+	check := func(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactments []runtime.Object, expectedConflicts []string) {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+		)
+		cli := fake.NewFakeClientWithScheme(s, enactments...)
+		conflicts, err := conflictingAddresses(cli, policy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conflicts).To(Equal(expectedConflicts))
+	}
+
+	DescribeTable("checking the other nodes", check,
+		Entry("with the same address rendered for other nodes, should report them",
+			policy(),
+			[]runtime.Object{
+				enactment("node03", 2, "192.0.2.10", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+				enactment("node02", 2, "192.0.2.10", enactmentconditions.SetMatching),
+				enactment("node04", 2, "192.0.2.11", enactmentconditions.SetMatching),
+			},
+			[]string{"192.0.2.10 at node02, node03"},
+		),
+		Entry("with the address at a previous generation, should not report it",
+			policy(),
+			[]runtime.Object{enactment("node02", 1, "192.0.2.10", enactmentconditions.SetMatching)},
+			[]string{},
+		),
+		Entry("with the address at a node not matching the policy, should not report it",
+			policy(),
+			[]runtime.Object{enactment("node02", 2, "192.0.2.10", enactmentconditions.SetNodeSelectorNotMatching)},
+			[]string{},
+		),
+		Entry("with the address at a failing node, should not report it",
+			policy(),
+			[]runtime.Object{enactment("node02", 2, "192.0.2.10", enactmentconditions.SetMatching, enactmentconditions.SetFailedToConfigure)},
+			[]string{},
+		),
+		Entry("with the address at a node already warned about it, should report it",
+			policy(),
+			[]runtime.Object{enactment("node02", 2, "192.0.2.10", enactmentconditions.SetMatching, enactmentconditions.SetAddressConflict)},
+			[]string{"192.0.2.10 at node02"},
+		),
+		Entry("with an anycast address, should not report it",
+			policy("192.0.2.10"),
+			[]runtime.Object{enactment("node02", 2, "192.0.2.10", enactmentconditions.SetMatching)},
+			[]string{},
+		),
+	)
+	It("should not report the address rendered for this node only", func() {
+		check(policy(), []runtime.Object{enactment(nodeName, 2, "192.0.2.10", enactmentconditions.SetMatching)}, []string{})
+	})
+})
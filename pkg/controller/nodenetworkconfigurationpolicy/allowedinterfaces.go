@@ -0,0 +1,18 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/allowlist"
+)
+
+// The only interfaces the handler changes, the webhook checks them too but
+// the desired state patches are only rendered at the nodes and policies may
+// be admitted before the allow list is configured
+var allowedInterfaces allowlist.AllowList
+
+func init() {
+	var err error
+	allowedInterfaces, err = allowlist.FromEnvironment()
+	if err != nil {
+		panic(err.Error())
+	}
+}
@@ -0,0 +1,155 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// policyApply is a policy whose desired state is applied at the node, on
+// its own or along with the rest of the policies of its bundle
+type policyApply struct {
+	policy              nmstatev1alpha1.NodeNetworkConfigurationPolicy
+	enactmentConditions enactmentconditions.EnactmentConditions
+	// previousForwarding is the global forwarding the node had before the
+	// policy set it, recorded at the enactment once the policy is applied
+	previousForwarding map[string]bool
+}
+
+// applyPolicies applies the desired state of a policy, or the merged one of
+// the matching policies of a bundle, holding the node apply lock, and
+// records the outcome at all the policies enactments. The policies are
+// applied together, all of them succeed or all of them are rolled back.
+// name is the policy or bundle name, the busy retries are tracked by it.
+func (r *ReconcileNodeNetworkConfigurationPolicy) applyPolicies(kind string, name string, applies []policyApply, desiredState nmstatev1alpha1.State, secretValues []string, readinessChecks []nmstatev1alpha1.ReadinessCheck, timeout time.Duration, logger logr.Logger) reconcile.Result {
+	lockHolder := kind + " " + name
+	if holder, locked := nodeApplyLock.tryLock(lockHolder); !locked {
+		logger.Info(fmt.Sprintf("Node apply lock held by %s, waiting before applying desired state", holder))
+		for _, apply := range applies {
+			apply.enactmentConditions.NotifyWaitingForLock(holder)
+		}
+		return reconcile.Result{RequeueAfter: applyLockRetryInterval}
+	}
+	applyingConditions := []enactmentconditions.EnactmentConditions{}
+	policies := []nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+	for _, apply := range applies {
+		applyingConditions = append(applyingConditions, apply.enactmentConditions)
+		policies = append(policies, apply.policy)
+	}
+	if !inFlightApplies.begin(lockHolder, applyingConditions...) {
+		nodeApplyLock.unlock()
+		logger.Info("Handler is stopping, leaving desired state apply to the next handler")
+		return reconcile.Result{}
+	}
+	defer inFlightApplies.end(lockHolder)
+	reportPreApplySnapshot(r.client, policies...)
+	for _, apply := range applies {
+		apply.enactmentConditions.NotifyProgressing()
+	}
+	linkFlaps := mtuLinkFlaps(desiredState, logger)
+	stpBridges := stpManagementBridges(desiredState, logger)
+	applyStarted := time.Now()
+	// The stale routes are removed if any of the policies cleans up its
+	// route tables
+	var routeTableCleanup *nmstate.RouteTableCleanup
+	for _, policy := range policies {
+		if policy.Spec.RouteTableCleanup {
+			routeTableCleanup = &nmstate.RouteTableCleanup{}
+		}
+	}
+	nmstate.StartApplyResourceUsage()
+	nmstateOutput, err := applyDesiredState(r.client, desiredState, readinessChecks, timeout, routeTableCleanup)
+	applyDuration := time.Since(applyStarted)
+	resourceUsage := nmstate.StopApplyResourceUsage()
+	// nmstate may echo the resolved secrets, they are redacted before the
+	// output or the error is reported anywhere
+	nmstateOutput = nmstate.RedactSecretValues(nmstateOutput, secretValues)
+	err = nmstate.RedactSecretValuesError(err, secretValues)
+	nodeApplyLock.unlock()
+	for _, policy := range policies {
+		reportResourceUsage(r.client, policy, resourceUsage)
+	}
+	if nmstate.IsBusy(err) {
+		if backoff, retry := r.busyBackoff(name); retry {
+			logger.Info(fmt.Sprintf("nmstate is busy, applying %s desired state again in %s", kind, backoff), "error", err.Error())
+			for _, apply := range applies {
+				apply.enactmentConditions.NotifyNmstateBusy(backoff)
+			}
+			return reconcile.Result{RequeueAfter: backoff}
+		}
+	}
+	// Without checkpoint the desired state cannot be rolled back, nothing
+	// is applied and it's retried like when nmstate is busy
+	if nmstate.IsCheckpointFailed(err) {
+		if backoff, retry := r.busyBackoff(name); retry {
+			logger.Info(fmt.Sprintf("nmstate failed creating checkpoint, applying %s desired state again in %s", kind, backoff), "error", err.Error())
+			for _, apply := range applies {
+				apply.enactmentConditions.NotifyCheckpointFailed(err, backoff)
+			}
+			return reconcile.Result{RequeueAfter: backoff}
+		}
+		for _, apply := range applies {
+			apply.enactmentConditions.NotifyCheckpointFailedToConfigure(err)
+		}
+		r.reportResults(policies, err, applyDuration)
+		return reconcile.Result{}
+	}
+	r.resetBusyBackoff(name)
+	// A hung nmstate would hold the node apply lock forever, it's aborted
+	// and not retried until the policy or the node changes
+	if nmstate.IsApplyTimeout(err) {
+		logger.Error(err, "nmstate apply timed out")
+		for _, apply := range applies {
+			apply.enactmentConditions.NotifyApplyTimeout(err)
+		}
+		r.reportResults(policies, err, applyDuration)
+		return reconcile.Result{}
+	}
+	if err != nil {
+		errmsg := fmt.Errorf("error reconciling %s at desired state apply: %s, %v", lockHolder, nmstateOutput, err)
+		for _, apply := range applies {
+			apply.enactmentConditions.NotifyFailedToConfigure(errmsg, nmstate.FailureCode(err))
+		}
+		r.reportResults(policies, errmsg, applyDuration)
+		logger.Error(errmsg, fmt.Sprintf("Rolling back network configuration, manual intervention needed: %s", nmstateOutput))
+		return reconcile.Result{}
+	}
+	logger.Info("nmstate", "output", nmstateOutput)
+
+	// The policies applied together are notified of the DHCP fallbacks,
+	// link flaps and spanning tree bridges of the whole desired state
+	interval := time.Duration(0)
+	for i := range applies {
+		policy := applies[i].policy
+		reportRemovedStaleRoutes(r.client, policy, routeTableCleanup)
+		recordAppliedDesiredState(r.client, policy, applies[i].previousForwarding)
+		notifySuccess(policy, desiredState, stpBridges, addressConflicts(r.client, policy), &applies[i].enactmentConditions)
+		r.reportResult(policy, nil, applyDuration)
+		reportConnections(r.client, policy)
+		reportLinkFlaps(r.client, policy, linkFlaps)
+		interval = shortestInterval(interval, reconcileInterval(policy))
+	}
+	return reconcile.Result{RequeueAfter: interval}
+}
+
+func (r *ReconcileNodeNetworkConfigurationPolicy) reportResults(policies []nmstatev1alpha1.NodeNetworkConfigurationPolicy, applyErr error, applyDuration time.Duration) {
+	for _, policy := range policies {
+		r.reportResult(policy, applyErr, applyDuration)
+	}
+}
+
+// shortestInterval returns the shortest of the requeue intervals, zero
+// ones are not requeued so they are left out
+func shortestInterval(interval time.Duration, other time.Duration) time.Duration {
+	if interval == 0 || (other > 0 && other < interval) {
+		return other
+	}
+	return interval
+}
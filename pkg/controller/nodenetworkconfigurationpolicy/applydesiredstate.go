@@ -0,0 +1,18 @@
+// +build !e2e
+
+package nodenetworkconfigurationpolicy
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// applyDesiredState applies the desired state at the node, failures can
+// only be injected at the handlers built with the e2e tag
+func applyDesiredState(cli client.Client, desiredState nmstatev1alpha1.State, readinessChecks []nmstatev1alpha1.ReadinessCheck, applyTimeout time.Duration, routeTableCleanup *nmstate.RouteTableCleanup) (string, error) {
+	return nmstate.ApplyDesiredState(desiredState, readinessChecks, applyTimeout, routeTableCleanup)
+}
@@ -0,0 +1,40 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"sync"
+	"time"
+)
+
+// Interval to check again the apply lock when another apply holds it
+const applyLockRetryInterval = 5 * time.Second
+
+// applyLock serializes the desired state applies at the node, the policy
+// and bundle controllers run their own workers and nmstate cannot apply
+// two desired states at the same time, the second one would fail
+// creating its checkpoint. It's not taken per interface, the checkpoint
+// covers every device of the node until the apply is committed, so
+// policies with disjoint interfaces cannot apply concurrently either.
+type applyLock struct {
+	mutex  sync.Mutex
+	holder string
+}
+
+var nodeApplyLock = &applyLock{}
+
+// tryLock takes the lock for holder if it is free, otherwise it returns the
+// current holder
+func (l *applyLock) tryLock(holder string) (string, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.holder != "" {
+		return l.holder, false
+	}
+	l.holder = holder
+	return "", true
+}
+
+func (l *applyLock) unlock() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.holder = ""
+}
@@ -0,0 +1,33 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Node apply lock", func() {
+	var lock *applyLock
+
+	BeforeEach(func() {
+		lock = &applyLock{}
+	})
+
+	It("should be taken by the first holder", func() {
+		_, locked := lock.tryLock("policy policy1")
+		Expect(locked).To(BeTrue())
+	})
+
+	It("should report the holder while taken", func() {
+		lock.tryLock("policy policy1")
+		holder, locked := lock.tryLock("bundle bundle1")
+		Expect(locked).To(BeFalse())
+		Expect(holder).To(Equal("policy policy1"))
+	})
+
+	It("should be taken again after unlocking it", func() {
+		lock.tryLock("policy policy1")
+		lock.unlock()
+		_, locked := lock.tryLock("bundle bundle1")
+		Expect(locked).To(BeTrue())
+	})
+})
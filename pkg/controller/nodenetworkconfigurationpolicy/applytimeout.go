@@ -0,0 +1,16 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"time"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// applyTimeout returns how long nmstate can take applying the policy
+// desired state, 0 if it's not bounded
+func applyTimeout(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) time.Duration {
+	if policy.Spec.ApplyTimeout == nil {
+		return 0
+	}
+	return policy.Spec.ApplyTimeout.Duration
+}
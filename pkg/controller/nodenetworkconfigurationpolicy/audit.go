@@ -0,0 +1,41 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+// audit reports if the node complies with the desired state of an audit
+// policy without applying it, the compliance is refreshed afterwards with
+// the node network state and at the policy reconcile interval
+func (r *ReconcileNodeNetworkConfigurationPolicy) audit(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, renderErr error, enactmentConditions *enactmentconditions.EnactmentConditions) (reconcile.Result, error) {
+	if render.IsInterfaceNotFound(renderErr) {
+		enactmentConditions.NotifyInterfaceNotFound(renderErr)
+		return reconcile.Result{}, nil
+	}
+	if renderErr != nil {
+		enactmentConditions.NotifyFailedToConfigure(errors.Wrap(renderErr, "failed rendering desired state patch"), nmstatev1alpha1.FailureCodeApplyFailed)
+		return reconcile.Result{}, nil
+	}
+
+	// The reported state has the settings the handler applies besides
+	// nmstate, so the audited desired states can set them too
+	currentState, err := nmstate.ReportedCurrentState()
+	if err != nil {
+		enactmentConditions.NotifyFailedToConfigure(errors.Wrap(err, "failed retrieving current state to audit"), nmstatev1alpha1.FailureCodeApplyFailed)
+		return reconcile.Result{}, nil
+	}
+	drift, err := nmstate.Drift(policy.Spec.DesiredState, currentState, policy.Spec.DriftIgnore)
+	if err != nil {
+		enactmentConditions.NotifyFailedToConfigure(errors.Wrap(err, "failed comparing desired state with current state"), nmstatev1alpha1.FailureCodeApplyFailed)
+		return reconcile.Result{}, nil
+	}
+	enactmentConditions.NotifyAudited(drift)
+	return reconcile.Result{RequeueAfter: reconcileInterval(policy)}, nil
+}
@@ -0,0 +1,119 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/selectors"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+// effectiveDesiredState returns the desired state the policy applies at this
+// node, with its desired state patch rendered against the node current state
+// and the interfaces identified by MAC or PCI address named like at the node.
+// The node invariant policies were rendered by the webhook, their desired
+// state is taken as is.
+func effectiveDesiredState(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) (nmstatev1alpha1.State, error) {
+	if renderedByWebhook(policy) {
+		return policy.Spec.DesiredState, nil
+	}
+	desiredState, err := nmstate.EffectiveDesiredState(policy.Spec)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstate.ResolveInterfaceMatches(desiredState)
+}
+
+// overridingPolicies returns the policies taking precedence over the base
+// ones at this node, the ones that are not base policies themselves and
+// match the node, with their effective desired state. Audit policies do not
+// configure anything, the ones being deleted are going away and the ones
+// with interfaces not found at the node are not applied.
+func overridingPolicies(cli client.Client) ([]nmstatev1alpha1.NodeNetworkConfigurationPolicy, error) {
+	policies := nmstatev1alpha1.NodeNetworkConfigurationPolicyList{}
+	err := cli.List(context.TODO(), &policies)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed listing policies")
+	}
+	overriding := []nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+	for _, policy := range policies.Items {
+		if policy.Spec.Base || policy.Spec.Audit || policy.DeletionTimestamp != nil {
+			continue
+		}
+		policySelectors := selectors.NewFromPolicy(cli, policy)
+		unmatchingNodeLabels, err := policySelectors.UnmatchedNodeLabels(nodeName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed checking policy %s node selectors", policy.Name)
+		}
+		if len(unmatchingNodeLabels) > 0 {
+			continue
+		}
+		policy.Spec.DesiredState, err = effectiveDesiredState(policy)
+		if render.IsInterfaceNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed rendering policy %s desired state", policy.Name)
+		}
+		overriding = append(overriding, policy)
+	}
+	return overriding, nil
+}
+
+// excludeOverriddenInterfaces removes from the base policy effective desired
+// state the interfaces other policies configure at this node, and returns
+// them
+func excludeOverriddenInterfaces(cli client.Client, policy *nmstatev1alpha1.NodeNetworkConfigurationPolicy) ([]nmstatev1alpha1.InterfaceOverride, error) {
+	overriding, err := overridingPolicies(cli)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed checking policies overriding the base policy")
+	}
+	overrides, err := render.InterfaceOverrides(policy.Spec.DesiredState, overriding)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed checking interfaces overridden by other policies")
+	}
+	if len(overrides) == 0 {
+		return overrides, nil
+	}
+	interfaces := []string{}
+	for _, override := range overrides {
+		interfaces = append(interfaces, override.Interface)
+	}
+	desiredState, _, err := render.IgnoreInterfaces(policy.Spec.DesiredState, interfaces)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed excluding interfaces overridden by other policies")
+	}
+	policy.Spec.DesiredState = desiredState
+	return overrides, nil
+}
+
+// baseRequestsForPolicy reconciles the base policies when another policy
+// changes, it may override their interfaces or stop doing so
+func baseRequestsForPolicy(cli client.Client) handler.ToRequestsFunc {
+	return func(object handler.MapObject) []reconcile.Request {
+		policy, isPolicy := object.Object.(*nmstatev1alpha1.NodeNetworkConfigurationPolicy)
+		if !isPolicy || policy.Spec.Base {
+			return nil
+		}
+		policies := nmstatev1alpha1.NodeNetworkConfigurationPolicyList{}
+		err := cli.List(context.TODO(), &policies)
+		if err != nil {
+			log.Error(err, "failed listing base policies", "policy", object.Meta.GetName())
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for _, basePolicy := range policies.Items {
+			if basePolicy.Spec.Base {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: basePolicy.Name}})
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,48 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Base policies", func() {
+	newPolicy := func(name string, desiredState string) *nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		return &nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+				DesiredState: nmstatev1alpha1.NewState(desiredState),
+			},
+		}
+	}
+
+	It("should take the effective desired state of the overriding policies", func() {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicyList{},
+		)
+		basePolicy := newPolicy("base", "interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n")
+		basePolicy.Spec.Base = true
+		auditPolicy := newPolicy("audit", "interfaces:\n- name: eth2\n  type: ethernet\n  state: up\n")
+		auditPolicy.Spec.Audit = true
+		cli := fake.NewFakeClientWithScheme(s,
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}},
+			basePolicy,
+			auditPolicy,
+			newPolicy("eth1", "interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  mtu: 9000\n"),
+			newPolicy("uplink", "interfaces:\n- name: uplink\n  type: ethernet\n  state: up\n  match:\n    mac-address: 02:00:5e:10:00:99\n"),
+		)
+
+		overriding, err := overridingPolicies(cli)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(overriding).To(HaveLen(1))
+		Expect(overriding[0].Name).To(Equal("eth1"))
+	})
+})
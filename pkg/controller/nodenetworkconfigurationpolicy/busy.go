@@ -0,0 +1,41 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"time"
+)
+
+const (
+	// Applies retried while nmstate is busy before failing
+	busyRetries = 5
+	// Wait before the first retry, doubled at every retry
+	busyBackoffBase = 2 * time.Second
+	busyBackoffCap  = time.Minute
+)
+
+// busyBackoff returns how long to wait before applying again a desired
+// state not applied because nmstate was busy, and false once the retries
+// are exhausted
+func (r *ReconcileNodeNetworkConfigurationPolicy) busyBackoff(name string) (time.Duration, bool) {
+	if r.busyAttempts == nil {
+		r.busyAttempts = map[string]int{}
+	}
+
+	attempt := r.busyAttempts[name]
+	if attempt >= busyRetries {
+		delete(r.busyAttempts, name)
+		return 0, false
+	}
+	r.busyAttempts[name] = attempt + 1
+
+	backoff := busyBackoffBase << uint(attempt)
+	if backoff > busyBackoffCap {
+		backoff = busyBackoffCap
+	}
+	return backoff, true
+}
+
+// resetBusyBackoff forgets the busy retries once the desired state is applied
+// or fails for other reasons
+func (r *ReconcileNodeNetworkConfigurationPolicy) resetBusyBackoff(name string) {
+	delete(r.busyAttempts, name)
+}
@@ -0,0 +1,52 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NodeNetworkConfigurationPolicy nmstate busy backoff", func() {
+	var reconciler ReconcileNodeNetworkConfigurationPolicy
+
+	BeforeEach(func() {
+		reconciler = ReconcileNodeNetworkConfigurationPolicy{}
+	})
+
+	It("should double the backoff until the retries are exhausted", func() {
+		backoffs := []time.Duration{}
+		for {
+			backoff, retry := reconciler.busyBackoff("policy1")
+			if !retry {
+				break
+			}
+			backoffs = append(backoffs, backoff)
+		}
+		Expect(backoffs).To(Equal([]time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second}))
+	})
+
+	It("should start again after exhausting the retries", func() {
+		for i := 0; i <= busyRetries; i++ {
+			reconciler.busyBackoff("policy1")
+		}
+		backoff, retry := reconciler.busyBackoff("policy1")
+		Expect(retry).To(BeTrue())
+		Expect(backoff).To(Equal(busyBackoffBase))
+	})
+
+	It("should track the retries per policy", func() {
+		reconciler.busyBackoff("policy1")
+		reconciler.busyBackoff("policy1")
+		backoff, _ := reconciler.busyBackoff("policy2")
+		Expect(backoff).To(Equal(busyBackoffBase))
+	})
+
+	It("should start again after reset", func() {
+		reconciler.busyBackoff("policy1")
+		reconciler.busyBackoff("policy1")
+		reconciler.resetBusyBackoff("policy1")
+		backoff, _ := reconciler.busyBackoff("policy1")
+		Expect(backoff).To(Equal(busyBackoffBase))
+	})
+})
@@ -0,0 +1,43 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// notifySuccess notifies the successful apply of the policy desired state
+// along with the warnings found at the node: the expected carrier
+// interfaces whose links are down, the desired state is fine but the
+// physical layer is not, the interfaces that fell back from DHCP to static
+// addresses, the static addresses rendered for other nodes too and the
+// spanning tree enabled at the bridges of the node default route
+func notifySuccess(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, desiredState nmstatev1alpha1.State, stpBridges []string, conflicts []string, enactmentConditions *enactmentconditions.EnactmentConditions) {
+	warnings := []enactmentconditions.SuccessWarning{}
+	if len(policy.Spec.ExpectedCarrier) > 0 {
+		if linkDown := nmstate.LinkDownInterfaces(policy.Spec.ExpectedCarrier); len(linkDown) > 0 {
+			log.Info("Desired state applied but interfaces are without carrier", "policy", policy.Name, "interfaces", linkDown)
+			warnings = append(warnings, enactmentconditions.ConfiguredLinkDownWarning(linkDown))
+		}
+	}
+	fellBack, err := nmstate.DHCPFellBackInterfaces(desiredState)
+	if err != nil {
+		log.Error(err, "failed checking DHCP fallback interfaces", "policy", policy.Name)
+	} else if len(fellBack) > 0 {
+		log.Info("Desired state applied but interfaces fell back from DHCP to static addresses", "policy", policy.Name, "interfaces", fellBack)
+		warnings = append(warnings, enactmentconditions.DHCPFellBackWarning(fellBack))
+	}
+	if len(conflicts) > 0 {
+		warnings = append(warnings, enactmentconditions.AddressConflictWarning(conflicts))
+	}
+	if len(stpBridges) > 0 {
+		warnings = append(warnings, enactmentconditions.STPManagementBridgesWarning(stpBridges))
+	}
+	if len(warnings) > 0 {
+		enactmentConditions.NotifySuccessWithWarnings(warnings)
+	} else if nmstate.RollbackCheckpointDisabled() {
+		enactmentConditions.NotifySuccessWithoutRollback()
+	} else {
+		enactmentConditions.NotifySuccess()
+	}
+}
@@ -0,0 +1,58 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("Policy success notification", func() {
+	var (
+		cli          client.Client
+		policy       nmstatev1alpha1.NodeNetworkConfigurationPolicy
+		desiredState = nmstatev1alpha1.NewState("interfaces:\n- name: br1\n  type: linux-bridge\n  state: up\n")
+	)
+
+	available := func() nmstatev1alpha1.Condition {
+		enactment := nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+		Expect(cli.Get(context.TODO(), nmstatev1alpha1.EnactmentKey(nodeName, policy.Name), &enactment)).To(Succeed())
+		return *enactment.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable)
+	}
+
+	BeforeEach(func() {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+		)
+		policy = nmstatev1alpha1.NodeNetworkConfigurationPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy1"}}
+		enactment := nmstatev1alpha1.NewEnactment(nodeName, policy)
+		cli = fake.NewFakeClientWithScheme(s, &enactment)
+	})
+
+	It("should report all the warnings at the message", func() {
+		enactmentConditions := enactmentconditions.New(cli, nmstatev1alpha1.EnactmentKey(nodeName, policy.Name))
+		notifySuccess(policy, desiredState, []string{"br1"}, []string{"192.0.2.10 at node02"}, &enactmentConditions)
+		condition := available()
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAddressConflict))
+		Expect(condition.Message).To(Equal("successfully reconciled but desired state static addresses are rendered for other nodes too: 192.0.2.10 at node02 and spanning tree enabled at bridges carrying the node default route, their traffic stopped for the forwarding delay: br1"))
+	})
+
+	It("should report the success without warnings", func() {
+		enactmentConditions := enactmentconditions.New(cli, nmstatev1alpha1.EnactmentKey(nodeName, policy.Name))
+		notifySuccess(policy, desiredState, []string{}, []string{}, &enactmentConditions)
+		condition := available()
+		Expect(condition.Reason).To(Equal(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured))
+		Expect(condition.Message).To(Equal("successfully reconciled"))
+	})
+})
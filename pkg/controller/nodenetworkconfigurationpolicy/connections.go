@@ -0,0 +1,30 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// reportConnections reports at the policy enactment the connections named
+// by its desired state, so they can be matched with their UUID
+func reportConnections(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) {
+	enactmentKey := nmstatev1alpha1.EnactmentKey(nodeName, policy.Name)
+	logger := log.WithName("reportConnections").WithValues("enactment", enactmentKey.Name)
+	connections, err := nmstate.NamedConnections(policy.Spec.DesiredState)
+	if err != nil {
+		logger.Error(err, "failed retrieving named connections, not reporting them")
+		return
+	}
+	if len(connections) == 0 {
+		connections = nil
+	}
+	err = enactmentstatus.Update(cli, enactmentKey, func(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) {
+		status.Connections = connections
+	})
+	if err != nil {
+		logger.Error(err, "failed reporting named connections")
+	}
+}
@@ -0,0 +1,79 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var (
+	// Quiet period after a policy spec change before applying it, so
+	// rapid successive edits are coalesced in a single apply, zero
+	// disables it.
+	applyCooldown time.Duration = 0
+)
+
+func init() {
+	cooldown, isSet := os.LookupEnv("POLICY_APPLY_COOLDOWN")
+	if !isSet || cooldown == "" {
+		return
+	}
+	var err error
+	applyCooldown, err = time.ParseDuration(cooldown)
+	if err != nil {
+		panic(fmt.Sprintf("Failed while converting evnironment variable to duration: %v", err))
+	}
+}
+
+// specChange is the first time the handler has seen a policy generation
+type specChange struct {
+	generation int64
+	seen       time.Time
+}
+
+// generationApplied returns true if the enactment of this node is
+// available for the policy generation, so it's not a new spec change, like
+// at the reconciles after a handler restart
+func (r *ReconcileNodeNetworkConfigurationPolicy) generationApplied(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) bool {
+	enactment := nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+	err := r.client.Get(context.TODO(), nmstatev1alpha1.EnactmentKey(nodeName, policy.Name), &enactment)
+	if err != nil {
+		return false
+	}
+	return enactment.Status.PolicyGeneration == policy.Generation &&
+		enactment.Status.Conditions.IsTrue(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable)
+}
+
+// forgetSpecChange drops the spec change of a policy that is applied or
+// deleted
+func (r *ReconcileNodeNetworkConfigurationPolicy) forgetSpecChange(name string) {
+	delete(r.specChanges, name)
+}
+
+// cooldownRemaining returns how long the policy has still to stay
+// unchanged before applying it, every new generation restarts the quiet
+// period. Generations already applied at the node are not waited for.
+func (r *ReconcileNodeNetworkConfigurationPolicy) cooldownRemaining(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, applied bool, now time.Time) time.Duration {
+	if applyCooldown <= 0 || applied {
+		r.forgetSpecChange(policy.Name)
+		return 0
+	}
+	if r.specChanges == nil {
+		r.specChanges = map[string]specChange{}
+	}
+
+	change, found := r.specChanges[policy.Name]
+	if !found || change.generation != policy.Generation {
+		change = specChange{generation: policy.Generation, seen: now}
+		r.specChanges[policy.Name] = change
+	}
+
+	quiet := now.Sub(change.seen)
+	if quiet >= applyCooldown {
+		return 0
+	}
+	return applyCooldown - quiet
+}
@@ -0,0 +1,121 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var (
+	// Policy annotation with the correlation ID, like a change ticket, to
+	// propagate to the enactments, events and metrics, empty disables it.
+	correlationAnnotation = ""
+
+	enactmentResults = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubernetes_nmstate_enactment_results_total",
+			Help: "Number of desired state applies per policy, node, result and correlation ID",
+		},
+		[]string{"policy", "node", "result", "correlation_id"},
+	)
+
+	enactmentApplyDurations = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubernetes_nmstate_enactment_apply_duration_seconds",
+			Help:    "Time taken to apply the policies desired state per policy, node and result",
+			Buckets: []float64{1, 5, 10, 30, 60, 120, 240, 480},
+		},
+		[]string{"policy", "node", "result"},
+	)
+)
+
+// Length the events messages are truncated to, the full failure is at the
+// enactment conditions
+const maxEventMessageLength = 1024
+
+func init() {
+	correlationAnnotation = os.Getenv("CORRELATION_ANNOTATION")
+	metrics.Registry.MustRegister(enactmentResults, enactmentApplyDurations)
+}
+
+func correlationID(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) string {
+	if correlationAnnotation == "" {
+		return ""
+	}
+	return policy.ObjectMeta.Annotations[correlationAnnotation]
+}
+
+// copyCorrelationAnnotation copies the policy correlation annotation to the
+// enactment, removing it if the policy has not it anymore. It returns true
+// if the enactment changed. It's only called when the enactment is created
+// or the policy generation changes, so the enactments keep the ID of the
+// change they applied.
+func copyCorrelationAnnotation(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactment *nmstatev1alpha1.NodeNetworkConfigurationEnactment) bool {
+	if correlationAnnotation == "" {
+		return false
+	}
+	id := correlationID(policy)
+	current, found := enactment.ObjectMeta.Annotations[correlationAnnotation]
+	if id == "" {
+		if !found {
+			return false
+		}
+		delete(enactment.ObjectMeta.Annotations, correlationAnnotation)
+		return true
+	}
+	if found && current == id {
+		return false
+	}
+	if enactment.ObjectMeta.Annotations == nil {
+		enactment.ObjectMeta.Annotations = map[string]string{}
+	}
+	enactment.ObjectMeta.Annotations[correlationAnnotation] = id
+	return true
+}
+
+// correlationChangedWithGeneration copies the policy correlation annotation
+// to an existing enactment if it's not initialized yet for the policy
+// generation, the ones already applying it keep their correlation ID. It
+// returns true if the enactment changed.
+func correlationChangedWithGeneration(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactment *nmstatev1alpha1.NodeNetworkConfigurationEnactment) bool {
+	if enactment.Status.PolicyGeneration == policy.Generation {
+		return false
+	}
+	return copyCorrelationAnnotation(policy, enactment)
+}
+
+// truncateEventMessage cuts the messages longer than maxEventMessageLength,
+// like the ones with the whole nmstate output
+func truncateEventMessage(message string) string {
+	if len(message) <= maxEventMessageLength {
+		return message
+	}
+	return message[:maxEventMessageLength] + "... (truncated, see the enactment)"
+}
+
+// reportResult emits an event at the policy and counts the result of
+// applying its desired state at the node, both tagged with the policy
+// correlation ID, and how long the apply took
+func (r *ReconcileNodeNetworkConfigurationPolicy) reportResult(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, applyErr error, applyDuration time.Duration) {
+	id := correlationID(policy)
+	eventType, reason := corev1.EventTypeNormal, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured
+	message := fmt.Sprintf("node %s configured", nodeName)
+	if applyErr != nil {
+		eventType, reason = corev1.EventTypeWarning, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailedToConfigure
+		message = fmt.Sprintf("node %s failed to configure: %s", nodeName, truncateEventMessage(applyErr.Error()))
+	}
+	if id != "" {
+		message = fmt.Sprintf("%s, %s: %s", message, correlationAnnotation, id)
+	}
+
+	enactmentResults.WithLabelValues(policy.Name, nodeName, string(reason), id).Inc()
+	enactmentApplyDurations.WithLabelValues(policy.Name, nodeName, string(reason)).Observe(applyDuration.Seconds())
+	r.recorder.Event(&policy, eventType, string(reason), message)
+}
@@ -0,0 +1,139 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NodeNetworkConfigurationPolicy correlation ID", func() {
+	var (
+		recorder   *record.FakeRecorder
+		reconciler ReconcileNodeNetworkConfigurationPolicy
+		policy     nmstatev1alpha1.NodeNetworkConfigurationPolicy
+	)
+
+	resultsCount := func(result string, id string) float64 {
+		metric := dto.Metric{}
+		Expect(enactmentResults.WithLabelValues(policy.Name, nodeName, result, id).Write(&metric)).To(Succeed())
+		return metric.GetCounter().GetValue()
+	}
+
+	durationsCount := func(result string) uint64 {
+		metric := dto.Metric{}
+		observer, err := enactmentApplyDurations.GetMetricWithLabelValues(policy.Name, nodeName, result)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(observer.(prometheus.Histogram).Write(&metric)).To(Succeed())
+		return metric.GetHistogram().GetSampleCount()
+	}
+
+	BeforeEach(func() {
+		correlationAnnotation = "change-id"
+		recorder = record.NewFakeRecorder(10)
+		reconciler = ReconcileNodeNetworkConfigurationPolicy{recorder: recorder}
+		policy = nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "policy1",
+				Annotations: map[string]string{"change-id": "CHG-1234"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		correlationAnnotation = ""
+	})
+
+	It("should copy the correlation annotation to the enactment", func() {
+		enactment := nmstatev1alpha1.NewEnactment(nodeName, policy)
+		Expect(copyCorrelationAnnotation(policy, &enactment)).To(BeTrue())
+		Expect(enactment.Annotations).To(HaveKeyWithValue("change-id", "CHG-1234"))
+		Expect(copyCorrelationAnnotation(policy, &enactment)).To(BeFalse())
+	})
+
+	It("should update and remove the correlation annotation of existing enactments", func() {
+		enactment := nmstatev1alpha1.NewEnactment(nodeName, policy)
+		enactment.Annotations = map[string]string{"change-id": "CHG-1000"}
+		Expect(copyCorrelationAnnotation(policy, &enactment)).To(BeTrue())
+		Expect(enactment.Annotations).To(HaveKeyWithValue("change-id", "CHG-1234"))
+
+		policy.Annotations = nil
+		Expect(copyCorrelationAnnotation(policy, &enactment)).To(BeTrue())
+		Expect(enactment.Annotations).ToNot(HaveKey("change-id"))
+	})
+
+	It("should only update the correlation annotation of existing enactments for new policy generations", func() {
+		policy.Generation = 2
+		enactment := nmstatev1alpha1.NewEnactment(nodeName, policy)
+		enactment.Annotations = map[string]string{"change-id": "CHG-1000"}
+		enactment.Status.PolicyGeneration = 2
+		Expect(correlationChangedWithGeneration(policy, &enactment)).To(BeFalse())
+		Expect(enactment.Annotations).To(HaveKeyWithValue("change-id", "CHG-1000"))
+
+		policy.Generation = 3
+		Expect(correlationChangedWithGeneration(policy, &enactment)).To(BeTrue())
+		Expect(enactment.Annotations).To(HaveKeyWithValue("change-id", "CHG-1234"))
+	})
+
+	It("should not copy other annotations", func() {
+		policy.Annotations = map[string]string{"foo": "bar"}
+		enactment := nmstatev1alpha1.NewEnactment(nodeName, policy)
+		copyCorrelationAnnotation(policy, &enactment)
+		Expect(enactment.Annotations).To(BeEmpty())
+	})
+
+	It("should not copy anything when disabled", func() {
+		correlationAnnotation = ""
+		enactment := nmstatev1alpha1.NewEnactment(nodeName, policy)
+		copyCorrelationAnnotation(policy, &enactment)
+		Expect(enactment.Annotations).To(BeEmpty())
+	})
+
+	It("should label the enactments created without node label", func() {
+		enactment := nmstatev1alpha1.NewEnactment(nodeName, policy)
+		Expect(setEnactmentNodeLabel(&enactment)).To(BeFalse())
+
+		delete(enactment.Labels, nmstatev1alpha1.EnactmentNodeLabel)
+		Expect(setEnactmentNodeLabel(&enactment)).To(BeTrue())
+		Expect(enactment.Labels).To(HaveKeyWithValue(nmstatev1alpha1.EnactmentNodeLabel, nodeName))
+		Expect(enactment.Labels).To(HaveKeyWithValue(nmstatev1alpha1.EnactmentPolicyLabel, policy.Name))
+
+		enactment.Labels = nil
+		Expect(setEnactmentNodeLabel(&enactment)).To(BeTrue())
+		Expect(enactment.Labels).To(HaveKeyWithValue(nmstatev1alpha1.EnactmentNodeLabel, nodeName))
+	})
+
+	It("should tag the success event and metric", func() {
+		before := resultsCount("SuccessfullyConfigured", "CHG-1234")
+		beforeDurations := durationsCount("SuccessfullyConfigured")
+		reconciler.reportResult(policy, nil, 3*time.Second)
+		Expect(<-recorder.Events).To(Equal(fmt.Sprintf("Normal SuccessfullyConfigured node %s configured, change-id: CHG-1234", nodeName)))
+		Expect(resultsCount("SuccessfullyConfigured", "CHG-1234")).To(Equal(before + 1))
+		Expect(durationsCount("SuccessfullyConfigured")).To(Equal(beforeDurations + 1))
+	})
+
+	It("should tag the failure event and metric", func() {
+		before := resultsCount("FailedToConfigure", "CHG-1234")
+		reconciler.reportResult(policy, fmt.Errorf("boom"), 3*time.Second)
+		Expect(<-recorder.Events).To(Equal(fmt.Sprintf("Warning FailedToConfigure node %s failed to configure: boom, change-id: CHG-1234", nodeName)))
+		Expect(resultsCount("FailedToConfigure", "CHG-1234")).To(Equal(before + 1))
+	})
+
+	It("should truncate the long failures at the event", func() {
+		reconciler.reportResult(policy, fmt.Errorf("%s", strings.Repeat("x", 2*maxEventMessageLength)), 3*time.Second)
+		event := <-recorder.Events
+		Expect(len(event)).To(BeNumerically("<", maxEventMessageLength+200))
+		Expect(event).To(ContainSubstring("truncated"))
+		Expect(event).To(HaveSuffix("change-id: CHG-1234"))
+	})
+})
@@ -0,0 +1,117 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// droppedSettings are the settings of the desired state previously applied
+// at the node that are removed from it once the policy drops them, each
+// returns the policy desired state removing the dropped ones
+var droppedSettings = []struct {
+	name   string
+	remove func(previousState nmstatev1alpha1.State, desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error)
+}{
+	{"dummy interfaces", nmstate.RemoveDroppedDummies},
+	{"tunnels", nmstate.RemoveDroppedTunnels},
+	{"static neighbors", nmstate.RemoveDroppedNeighbors},
+	{"interfaces sysctls", nmstate.RemoveDroppedSysctls},
+	{"bridges multicast options", nmstate.RemoveDroppedBridgesMulticast},
+	{"promiscuous mode", nmstate.RemoveDroppedPromisc},
+	{"disable-ipv6", nmstate.RemoveDroppedDisableIPv6},
+}
+
+// previousApply returns the desired state of the policy last applied
+// successfully at the node, empty if it was never applied, and the global
+// forwarding the node had before the policy set it
+func (r *ReconcileNodeNetworkConfigurationPolicy) previousApply(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) (nmstatev1alpha1.State, map[string]bool, error) {
+	enactment := nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+	err := r.client.Get(context.TODO(), nmstatev1alpha1.EnactmentKey(nodeName, policy.Name), &enactment)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nmstatev1alpha1.NewState(""), map[string]bool{}, nil
+		}
+		return nmstatev1alpha1.NewState(""), map[string]bool{}, err
+	}
+	// Enactments available from before the applied desired state was
+	// recorded have it as desired state
+	appliedDesiredState := strings.TrimSpace(enactment.Status.AppliedDesiredState.String())
+	if (appliedDesiredState == "" || appliedDesiredState == "null") && enactment.Status.Conditions.IsTrue(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable) {
+		return enactment.Status.DesiredState, enactment.Status.PreviousForwarding, nil
+	}
+	return enactment.Status.AppliedDesiredState, enactment.Status.PreviousForwarding, nil
+}
+
+// forwardingBeforePolicy returns the global forwarding the node had before
+// the policy set it for the families the policy desired state sets, the
+// ones not recorded yet are read from the node before applying it
+func (r *ReconcileNodeNetworkConfigurationPolicy) forwardingBeforePolicy(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, logger logr.Logger) map[string]bool {
+	_, previousForwarding, err := r.previousApply(policy)
+	if err != nil {
+		logger.Error(err, "failed retrieving previous global forwarding, reading it from the node")
+	}
+	forwarding, err := nmstate.CurrentForwarding(policy.Spec.DesiredState)
+	if err != nil {
+		logger.Error(err, "failed reading global forwarding, not restoring it once dropped")
+		return map[string]bool{}
+	}
+	for family := range forwarding {
+		if enabled, recorded := previousForwarding[family]; recorded {
+			forwarding[family] = enabled
+		}
+	}
+	return forwarding
+}
+
+// removeDroppedSettings returns the policy desired state with the settings
+// of the desired state previously applied at the node, and dropped from the
+// policy since then, removed. The settings failing to be checked are kept
+// at the node.
+func (r *ReconcileNodeNetworkConfigurationPolicy) removeDroppedSettings(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, logger logr.Logger) nmstatev1alpha1.State {
+	desiredState := policy.Spec.DesiredState
+	previousDesiredState, previousForwarding, err := r.previousApply(policy)
+	if err != nil {
+		logger.Error(err, "failed retrieving previously applied desired state, keeping the dropped settings at the node")
+		return desiredState
+	}
+	for _, setting := range droppedSettings {
+		state, err := setting.remove(previousDesiredState, desiredState)
+		if err != nil {
+			logger.Error(err, "failed checking dropped "+setting.name+", keeping them at the node")
+			continue
+		}
+		desiredState = state
+	}
+	// The dropped global forwarding goes back to the value from before the
+	// policy, not to the kernel default
+	state, err := nmstate.RemoveDroppedForwarding(previousForwarding, desiredState)
+	if err != nil {
+		logger.Error(err, "failed checking dropped global forwarding, keeping it at the node")
+		return desiredState
+	}
+	return state
+}
+
+// recordAppliedDesiredState keeps the desired state at the policy enactment
+// once it's successfully applied, so the settings dropped from the policy
+// later on are removed from the node, with the global forwarding the node
+// had before the policy set it
+func recordAppliedDesiredState(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, previousForwarding map[string]bool) {
+	enactmentKey := nmstatev1alpha1.EnactmentKey(nodeName, policy.Name)
+	err := enactmentstatus.Update(cli, enactmentKey, func(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) {
+		status.AppliedDesiredState = policy.Spec.DesiredState
+		status.PreviousForwarding = previousForwarding
+	})
+	if err != nil {
+		log.WithName("recordAppliedDesiredState").WithValues("enactment", enactmentKey.Name).Error(err, "failed recording applied desired state")
+	}
+}
@@ -0,0 +1,89 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("NodeNetworkConfigurationPolicy dropped settings", func() {
+	const dummyState = "interfaces:\n- name: dummy0\n  state: up\n  type: dummy\n"
+	var (
+		cli        client.Client
+		reconciler ReconcileNodeNetworkConfigurationPolicy
+		policy     nmstatev1alpha1.NodeNetworkConfigurationPolicy
+		enactment  nmstatev1alpha1.NodeNetworkConfigurationEnactment
+	)
+
+	BeforeEach(func() {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+		)
+		policy = nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy1"},
+			Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+				DesiredState: nmstatev1alpha1.NewState("interfaces: []\n"),
+			},
+		}
+		enactment = nmstatev1alpha1.NewEnactment(nodeName, policy)
+	})
+
+	removeDroppedSettings := func() string {
+		cli = fake.NewFakeClientWithScheme(scheme.Scheme, &enactment)
+		reconciler = ReconcileNodeNetworkConfigurationPolicy{client: cli}
+		return string(reconciler.removeDroppedSettings(policy, logf.Log).Raw)
+	}
+
+	It("should remove the settings dropped since the last applied desired state", func() {
+		enactment.Status.AppliedDesiredState = nmstatev1alpha1.NewState(dummyState)
+		Expect(removeDroppedSettings()).To(ContainSubstring("state: absent"))
+	})
+
+	It("should not take the desired state not applied yet as previous one", func() {
+		enactment.Status.DesiredState = nmstatev1alpha1.NewState(dummyState)
+		Expect(removeDroppedSettings()).To(Equal("interfaces: []\n"))
+	})
+
+	It("should take the desired state of the enactments available before recording the applied one", func() {
+		enactment.Status.DesiredState = nmstatev1alpha1.NewState(dummyState)
+		enactmentconditions.SetSuccess(&enactment.Status.Conditions, "")
+		Expect(removeDroppedSettings()).To(ContainSubstring("state: absent"))
+	})
+
+	It("should record the applied desired state", func() {
+		cli = fake.NewFakeClientWithScheme(scheme.Scheme, &enactment)
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState(dummyState)
+		recordAppliedDesiredState(cli, policy, map[string]bool{"ipv6": false})
+
+		recorded := nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+		Expect(cli.Get(context.TODO(), nmstatev1alpha1.EnactmentKey(nodeName, policy.Name), &recorded)).To(Succeed())
+		Expect(recorded.Status.AppliedDesiredState.String()).To(MatchYAML(dummyState))
+		Expect(recorded.Status.PreviousForwarding).To(Equal(map[string]bool{"ipv6": false}))
+	})
+
+	It("should restore the dropped global forwarding to its value before the policy", func() {
+		enactment.Status.AppliedDesiredState = nmstatev1alpha1.NewState("forwarding:\n  ipv6: true\n")
+		enactment.Status.PreviousForwarding = map[string]bool{"ipv6": false}
+		Expect(removeDroppedSettings()).To(MatchYAML("forwarding:\n  ipv6: false\ninterfaces: []\n"))
+	})
+
+	It("should keep the recorded global forwarding before the policy", func() {
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState("forwarding:\n  ipv4: true\n")
+		enactment.Status.PreviousForwarding = map[string]bool{"ipv4": false, "ipv6": true}
+		cli = fake.NewFakeClientWithScheme(scheme.Scheme, &enactment)
+		reconciler = ReconcileNodeNetworkConfigurationPolicy{client: cli}
+		Expect(reconciler.forwardingBeforePolicy(policy, logf.Log)).To(Equal(map[string]bool{"ipv4": false}))
+	})
+})
@@ -2,6 +2,8 @@ package conditions
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -33,7 +35,7 @@ func New(client client.Client, enactmentKey types.NamespacedName) EnactmentCondi
 func (ec *EnactmentConditions) NotifyNodeSelectorFailure(err error) {
 	ec.logger.Info("NotifyNodeSelectorFailure")
 	message := fmt.Sprintf("failure checking node selectors : %v", err)
-	err = ec.updateEnactmentConditions(SetNodeSelectorNotMatching, message)
+	err = ec.updateEnactmentConditions(SetNodeSelectorNotMatching, message, "")
 	if err != nil {
 		ec.logger.Error(err, "Error notifying state NodeSelectorNotMatching with failure")
 	}
@@ -42,15 +44,24 @@ func (ec *EnactmentConditions) NotifyNodeSelectorFailure(err error) {
 func (ec *EnactmentConditions) NotifyNodeSelectorNotMatching(unmatchingLabels map[string]string) {
 	ec.logger.Info("NotifyNodeSelectorNotMatching")
 	message := fmt.Sprintf("Unmatching labels: %v", unmatchingLabels)
-	err := ec.updateEnactmentConditions(SetNodeSelectorNotMatching, message)
+	err := ec.updateEnactmentConditions(SetNodeSelectorNotMatching, message, "")
 	if err != nil {
 		ec.logger.Error(err, "Error notifying state NodeSelectorNotMatching")
 	}
 }
 
+func (ec *EnactmentConditions) NotifyNodeFileMissing(path string) {
+	ec.logger.Info("NotifyNodeFileMissing")
+	message := fmt.Sprintf("Required node file %s does not exist", path)
+	err := ec.updateEnactmentConditions(SetNodeFileMissing, message, "")
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state NodeFileMissing")
+	}
+}
+
 func (ec *EnactmentConditions) NotifyMatching() {
 	ec.logger.Info("NotifyMatching")
-	err := ec.updateEnactmentConditions(SetMatching, "All policy selectors are matching the node")
+	err := ec.updateEnactmentConditions(SetMatching, "All policy selectors are matching the node", "")
 	if err != nil {
 		ec.logger.Error(err, "Error notifying state Matching")
 	}
@@ -58,52 +69,319 @@ func (ec *EnactmentConditions) NotifyMatching() {
 
 func (ec *EnactmentConditions) NotifyProgressing() {
 	ec.logger.Info("NotifyProgressing")
-	err := ec.updateEnactmentConditions(SetProgressing, "Applying desired state")
+	err := ec.updateEnactmentStatus(SetProgressing, "Applying desired state", "", enactmentstatus.SetApplyStarted)
 	if err != nil {
 		ec.logger.Error(err, "Error notifying state Progressing")
 	}
 }
 
-func (ec *EnactmentConditions) NotifyFailedToConfigure(failedErr error) {
+func (ec *EnactmentConditions) NotifyWaitingPostBoot(remaining time.Duration) {
+	ec.logger.Info("NotifyWaitingPostBoot")
+	message := fmt.Sprintf("Waiting %s for the node post boot delay before applying desired state", remaining)
+	err := ec.updateEnactmentConditions(SetWaitingPostBoot, message, "")
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state WaitingPostBoot")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyCoolingDown(remaining time.Duration) {
+	ec.logger.Info("NotifyCoolingDown")
+	message := fmt.Sprintf("Waiting %s for the policy to stop changing before applying desired state", remaining)
+	err := ec.updateEnactmentConditions(SetCoolingDown, message, "")
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state CoolingDown")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyWaitingForLock(holder string) {
+	ec.logger.Info("NotifyWaitingForLock")
+	message := fmt.Sprintf("Waiting for %s to finish applying its desired state at the node", holder)
+	err := ec.updateEnactmentConditions(SetWaitingForLock, message, "")
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state WaitingForLock")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyWaitingForRollout(waitingFor string) {
+	ec.logger.Info("NotifyWaitingForRollout")
+	message := fmt.Sprintf("Waiting for %s to be available before applying desired state", waitingFor)
+	err := ec.updateEnactmentConditions(SetWaitingForRollout, message, "")
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state WaitingForRollout")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyStaged(generation int64) {
+	ec.logger.Info("NotifyStaged")
+	message := fmt.Sprintf("Desired state staged, waiting for the policy to be activated with the %s annotation set to %d", nmstatev1alpha1.NodeNetworkConfigurationPolicyActivateAnnotation, generation)
+	err := ec.updateEnactmentConditions(SetStaged, message, "")
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state Staged")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyBundleStaged(policy string, generation int64) {
+	ec.logger.Info("NotifyBundleStaged")
+	message := fmt.Sprintf("Bundle desired state staged, waiting for policy %s to be activated with the %s annotation set to %d", policy, nmstatev1alpha1.NodeNetworkConfigurationPolicyActivateAnnotation, generation)
+	err := ec.updateEnactmentConditions(SetStaged, message, "")
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state Staged")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyNmstateBusy(backoff time.Duration) {
+	ec.logger.Info("NotifyNmstateBusy")
+	message := fmt.Sprintf("nmstate is busy with another transaction, applying desired state again in %s", backoff)
+	err := ec.updateEnactmentConditions(SetNmstateBusy, message, "")
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state NmstateBusy")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyCheckpointFailed(failedErr error, backoff time.Duration) {
+	ec.logger.Info("NotifyCheckpointFailed")
+	message := fmt.Sprintf("%v, applying desired state again in %s", failedErr, backoff)
+	err := ec.updateEnactmentConditions(SetCheckpointFailedRetrying, message, "")
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state CheckpointFailed")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyCheckpointFailedToConfigure(failedErr error) {
+	ec.logger.Info("NotifyCheckpointFailedToConfigure")
+	err := ec.updateEnactmentStatus(SetCheckpointFailed, failedErr.Error(), nmstatev1alpha1.FailureCodeCheckpointFailed, enactmentstatus.SetApplyFinished)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state CheckpointFailed with failure")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyApplyTimeout(failedErr error) {
+	ec.logger.Info("NotifyApplyTimeout")
+	err := ec.updateEnactmentStatus(SetApplyTimeout, failedErr.Error(), nmstatev1alpha1.FailureCodeTimeout, enactmentstatus.SetApplyFinished)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state ApplyTimeout")
+	}
+}
+
+func (ec *EnactmentConditions) NotifySecretNotFound(failedErr error) {
+	ec.logger.Info("NotifySecretNotFound")
+	err := ec.updateEnactmentConditions(SetSecretNotFound, failedErr.Error(), nmstatev1alpha1.FailureCodePrerequisiteMissing)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state SecretNotFound")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyInterrupted() {
+	ec.logger.Info("NotifyInterrupted")
+	err := ec.updateEnactmentStatus(SetInterrupted, "Desired state apply interrupted by handler stop, the next handler at the node will apply it again", "", enactmentstatus.SetApplyFinished)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state Interrupted")
+	}
+}
+
+// NotifyFailedToConfigure reports the failure with the code of the typed
+// error behind it, the message wraps it so it's given apart
+func (ec *EnactmentConditions) NotifyFailedToConfigure(failedErr error, failureCode nmstatev1alpha1.FailureCode) {
 	ec.logger.Info("NotifyFailedToConfigure")
-	err := ec.updateEnactmentConditions(SetFailedToConfigure, failedErr.Error())
+	err := ec.updateEnactmentStatus(SetFailedToConfigure, failedErr.Error(), failureCode, enactmentstatus.SetApplyFinished)
 	if err != nil {
 		ec.logger.Error(err, "Error notifying state FailingToConfigure")
 	}
 }
 
+func (ec *EnactmentConditions) NotifyProtectedInterfacesModified(protectedInterfaces []string) {
+	ec.logger.Info("NotifyProtectedInterfacesModified")
+	message := fmt.Sprintf("Desired state modifies protected interfaces: %v", protectedInterfaces)
+	err := ec.updateEnactmentConditions(SetProtectedInterfaceModified, message, nmstatev1alpha1.FailureCodeRejected)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state ProtectedInterfaceModified")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyInterfacesNotAllowed(interfaces []string, allowedInterfaces string) {
+	ec.logger.Info("NotifyInterfacesNotAllowed")
+	message := fmt.Sprintf("Desired state modifies interfaces outside the allowed interfaces %s: %s", allowedInterfaces, strings.Join(interfaces, ", "))
+	err := ec.updateEnactmentConditions(SetInterfaceNotAllowed, message, nmstatev1alpha1.FailureCodeRejected)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state InterfaceNotAllowed")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyInterfaceNotFound(err error) {
+	ec.logger.Info("NotifyInterfaceNotFound")
+	message := fmt.Sprintf("Desired state interfaces not found at the node: %v", err)
+	err = ec.updateEnactmentConditions(SetInterfaceNotFound, message, nmstatev1alpha1.FailureCodePrerequisiteMissing)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state InterfaceNotFound")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyDevicesUnmanaged(devices []string) {
+	ec.logger.Info("NotifyDevicesUnmanaged")
+	message := fmt.Sprintf("Desired state configures devices unmanaged by NetworkManager, it will not touch them: %v", devices)
+	err := ec.updateEnactmentConditions(SetDeviceUnmanaged, message, nmstatev1alpha1.FailureCodePrerequisiteMissing)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state DeviceUnmanaged")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyQuarantined() {
+	ec.logger.Info("NotifyQuarantined")
+	err := ec.updateEnactmentConditions(SetQuarantined, "Policy is quarantined, desired state not applied", nmstatev1alpha1.FailureCodeNotApplied)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state Quarantined")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyRolloutHalted(failedNode string) {
+	ec.logger.Info("NotifyRolloutHalted")
+	message := fmt.Sprintf("Policy rollout halted after failing at node %s, desired state not applied", failedNode)
+	err := ec.updateEnactmentConditions(SetRolloutHalted, message, nmstatev1alpha1.FailureCodeNotApplied)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state RolloutHalted")
+	}
+}
+
 func (ec *EnactmentConditions) NotifySuccess() {
 	ec.logger.Info("NotifySuccess")
-	err := ec.updateEnactmentConditions(SetSuccess, "successfully reconciled")
+	err := ec.updateEnactmentStatus(SetSuccess, "successfully reconciled", "", enactmentstatus.SetApplyFinished)
 	if err != nil {
 		ec.logger.Error(err, "Error notifying state Success")
 	}
 }
 
+func (ec *EnactmentConditions) NotifySuccessWithoutRollback() {
+	ec.logger.Info("NotifySuccessWithoutRollback")
+	err := ec.updateEnactmentStatus(SetSuccessWithoutRollback, "successfully reconciled with the rollback checkpoint disabled, the desired state would not have been rolled back", "", enactmentstatus.SetApplyFinished)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state Success without rollback")
+	}
+}
+
+// SuccessWarning is an issue found at the node after the desired state is
+// successfully applied, the enactment is still available
+type SuccessWarning struct {
+	conditionsSetter func(*nmstatev1alpha1.ConditionList, string)
+	message          string
+}
+
+func ConfiguredLinkDownWarning(interfaces []string) SuccessWarning {
+	return SuccessWarning{SetConfiguredLinkDown, fmt.Sprintf("interfaces without carrier: %s", strings.Join(interfaces, ", "))}
+}
+
+func DHCPFellBackWarning(interfaces []string) SuccessWarning {
+	return SuccessWarning{SetDHCPFellBack, fmt.Sprintf("interfaces without DHCP lease fell back to static addresses: %s", strings.Join(interfaces, ", "))}
+}
+
+func AddressConflictWarning(conflicts []string) SuccessWarning {
+	return SuccessWarning{SetAddressConflict, fmt.Sprintf("desired state static addresses are rendered for other nodes too: %s", strings.Join(conflicts, "; "))}
+}
+
+func STPManagementBridgesWarning(bridges []string) SuccessWarning {
+	return SuccessWarning{SetSTPManagementBridge, fmt.Sprintf("spanning tree enabled at bridges carrying the node default route, their traffic stopped for the forwarding delay: %s", strings.Join(bridges, ", "))}
+}
+
+// NotifySuccessWithWarnings reports all the warnings at the message, the
+// reason is the one of the first warning
+func (ec *EnactmentConditions) NotifySuccessWithWarnings(warnings []SuccessWarning) {
+	ec.logger.Info("NotifySuccessWithWarnings")
+	messages := []string{}
+	for _, warning := range warnings {
+		messages = append(messages, warning.message)
+	}
+	message := fmt.Sprintf("successfully reconciled but %s", strings.Join(messages, " and "))
+	err := ec.updateEnactmentStatus(warnings[0].conditionsSetter, message, "", enactmentstatus.SetApplyFinished)
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state Success with warnings")
+	}
+}
+
+func (ec *EnactmentConditions) NotifyAudited(drift []string) {
+	ec.logger.Info("NotifyAudited")
+	err := enactmentstatus.Update(ec.client, ec.enactmentKey,
+		func(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) {
+			SetAudited(&status.Conditions, drift)
+			setFailureCode(status, "")
+			status.Drift = nil
+			if len(drift) > 0 {
+				status.Drift = drift
+			}
+		})
+	if err != nil {
+		ec.logger.Error(err, "Error notifying state Audited")
+	}
+}
+
 func (ec *EnactmentConditions) Reset() {
 	ec.logger.Info("Reset")
 	err := ec.updateEnactmentConditions(func(conditionList *nmstatev1alpha1.ConditionList, message string) {
 		conditionList = &nmstatev1alpha1.ConditionList{}
-	}, "")
+	}, "", "")
 	if err != nil {
 		ec.logger.Error(err, "Error resetting conditions")
 	}
 }
 
+// updateEnactmentConditions updates the conditions with the failure code
+// of the notified failure, empty for the rest of notifications
 func (ec *EnactmentConditions) updateEnactmentConditions(
 	conditionsSetter func(*nmstatev1alpha1.ConditionList, string),
 	message string,
+	failureCode nmstatev1alpha1.FailureCode,
+) error {
+	return enactmentstatus.Update(ec.client, ec.enactmentKey,
+		func(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) {
+			conditionsSetter(&status.Conditions, message)
+			setFailureCode(status, failureCode)
+		})
+}
+
+// updateEnactmentStatus updates the conditions together with the apply
+// timing
+func (ec *EnactmentConditions) updateEnactmentStatus(
+	conditionsSetter func(*nmstatev1alpha1.ConditionList, string),
+	message string,
+	failureCode nmstatev1alpha1.FailureCode,
+	timingSetter func(*nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus, time.Time),
 ) error {
 	return enactmentstatus.Update(ec.client, ec.enactmentKey,
 		func(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) {
 			conditionsSetter(&status.Conditions, message)
+			setFailureCode(status, failureCode)
+			timingSetter(status, time.Now())
 		})
 }
 
+// setFailureCode sets the code of the failure just notified, the enactment
+// keeps the previous one if it is still failing after other notifications
+// and it's cleared once it's not failing anymore
+func setFailureCode(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus, failureCode nmstatev1alpha1.FailureCode) {
+	failing := status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing)
+	if failing == nil || failing.Status != corev1.ConditionTrue {
+		status.FailureCode = ""
+		return
+	}
+	if failureCode != "" {
+		status.FailureCode = failureCode
+	}
+}
+
 func SetFailedToConfigure(conditions *nmstatev1alpha1.ConditionList, message string) {
 	SetFailed(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailedToConfigure, message)
 }
 
+func SetCheckpointFailed(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetFailed(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionCheckpointFailed, message)
+}
+
+func SetApplyTimeout(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetFailed(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionApplyTimeout, message)
+}
+
+func SetSecretNotFound(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetFailed(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSecretNotFound, message)
+}
+
 func SetFailed(conditions *nmstatev1alpha1.ConditionList, reason nmstatev1alpha1.ConditionReason, message string) {
 	conditions.Set(
 		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing,
@@ -125,44 +403,188 @@ func SetFailed(conditions *nmstatev1alpha1.ConditionList, reason nmstatev1alpha1
 	)
 }
 
+// SetInterrupted sets the enactment as neither progressing nor failing, the
+// outcome of the interrupted apply is unknown
+func SetInterrupted(conditions *nmstatev1alpha1.ConditionList, message string) {
+	reason := nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionInterrupted
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionProgressing,
+		corev1.ConditionFalse,
+		reason,
+		message,
+	)
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing,
+		corev1.ConditionUnknown,
+		reason,
+		"",
+	)
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable,
+		corev1.ConditionUnknown,
+		reason,
+		"",
+	)
+}
+
+func SetQuarantined(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetFailed(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionQuarantined, message)
+}
+
+func SetRolloutHalted(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetFailed(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionRolloutHalted, message)
+}
+
+func SetAddressConflict(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetSucceeded(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAddressConflict, message)
+}
+
+func SetProtectedInterfaceModified(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetFailed(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionProtectedInterfaceModified, message)
+}
+
+func SetInterfaceNotAllowed(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetFailed(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionInterfaceNotAllowed, message)
+}
+
+func SetInterfaceNotFound(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetFailed(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionInterfaceNotFound, message)
+}
+
+func SetDeviceUnmanaged(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetFailed(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionDeviceUnmanaged, message)
+}
+
 func SetSuccess(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetSucceeded(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured, message)
+}
+
+func SetSuccessWithoutRollback(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetSucceeded(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionConfiguredWithoutRollback, message)
+}
+
+func SetConfiguredLinkDown(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetSucceeded(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionConfiguredLinkDown, message)
+}
+
+func SetDHCPFellBack(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetSucceeded(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionDHCPFellBack, message)
+}
+
+func SetSTPManagementBridge(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetSucceeded(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSTPManagementBridge, message)
+}
+
+func SetSucceeded(conditions *nmstatev1alpha1.ConditionList, reason nmstatev1alpha1.ConditionReason, message string) {
 	conditions.Set(
 		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable,
 		corev1.ConditionTrue,
-		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured,
+		reason,
 		message,
 	)
 	conditions.Set(
 		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing,
 		corev1.ConditionFalse,
-		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured,
+		reason,
 		"",
 	)
 	conditions.Set(
 		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionProgressing,
 		corev1.ConditionFalse,
-		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured,
+		reason,
 		"",
 	)
 }
 
+// SetAudited finishes the enactment of an audit policy without applying
+// it, reporting if the node complies with the desired state, that is,
+// if there is no drift
+func SetAudited(conditions *nmstatev1alpha1.ConditionList, drift []string) {
+	message := "Policy is audited, desired state not applied"
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable,
+		corev1.ConditionTrue,
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAudited,
+		message,
+	)
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing,
+		corev1.ConditionFalse,
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAudited,
+		"",
+	)
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionProgressing,
+		corev1.ConditionFalse,
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAudited,
+		"",
+	)
+	if len(drift) == 0 {
+		conditions.Set(
+			nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionCompliant,
+			corev1.ConditionTrue,
+			nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionNodeCompliant,
+			"Node complies with the desired state",
+		)
+	} else {
+		conditions.Set(
+			nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionCompliant,
+			corev1.ConditionFalse,
+			nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionNodeNonCompliant,
+			fmt.Sprintf("Node does not comply with the desired state at: %s", strings.Join(drift, ", ")),
+		)
+	}
+}
+
 func SetProgressing(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetInProgress(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionConfigurationProgressing, message)
+}
+
+func SetWaitingPostBoot(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetInProgress(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionWaitingPostBoot, message)
+}
+
+func SetCoolingDown(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetInProgress(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionCoolingDown, message)
+}
+
+func SetWaitingForLock(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetInProgress(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionWaitingForLock, message)
+}
+
+func SetWaitingForRollout(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetInProgress(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionWaitingForRollout, message)
+}
+
+func SetStaged(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetInProgress(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionStaged, message)
+}
+
+func SetNmstateBusy(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetInProgress(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionNmstateBusy, message)
+}
+
+func SetCheckpointFailedRetrying(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetInProgress(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionCheckpointFailed, message)
+}
+
+func SetInProgress(conditions *nmstatev1alpha1.ConditionList, reason nmstatev1alpha1.ConditionReason, message string) {
 	conditions.Set(
 		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionProgressing,
 		corev1.ConditionTrue,
-		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionConfigurationProgressing,
+		reason,
 		message,
 	)
 	conditions.Set(
 		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing,
 		corev1.ConditionUnknown,
-		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionConfigurationProgressing,
+		reason,
 		"",
 	)
 	conditions.Set(
 		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable,
 		corev1.ConditionUnknown,
-		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionConfigurationProgressing,
+		reason,
 		"",
 	)
 }
@@ -171,6 +593,10 @@ func SetNodeSelectorNotMatching(conditions *nmstatev1alpha1.ConditionList, messa
 	SetNotMatching(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionNodeSelectorNotMatching, message)
 }
 
+func SetNodeFileMissing(conditions *nmstatev1alpha1.ConditionList, message string) {
+	SetNotMatching(conditions, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionNodeFileMissing, message)
+}
+
 func SetNotMatching(conditions *nmstatev1alpha1.ConditionList, reason nmstatev1alpha1.ConditionReason, message string) {
 	conditions.Set(
 		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing,
@@ -0,0 +1,74 @@
+package enactmentstatus
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var (
+	// The labels are part of the metrics interface used by dashboards,
+	// they are only added, never renamed nor removed
+	enactmentConditionLabels = []string{"node", "policy", "condition", "status"}
+
+	enactmentConditions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubernetes_nmstate_enactment_condition",
+			Help: "Conditions of the enactments per node, policy, condition and status, 1 for the current status of the condition and 0 for the others",
+		},
+		enactmentConditionLabels,
+	)
+
+	conditionStatuses = []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown}
+)
+
+func init() {
+	metrics.Registry.MustRegister(enactmentConditions)
+}
+
+// reportConditions sets the condition gauges of the enactment, every
+// condition has a gauge per status so queries don't miss the statuses the
+// enactment is not at. The enactment of a node that stops matching the
+// policy selectors is kept but its gauges are removed, like the deleted ones.
+func reportConditions(enactment nmstatev1alpha1.NodeNetworkConfigurationEnactment) {
+	if nodeSelectorNotMatching(enactment) {
+		ForgetConditions(enactment)
+		return
+	}
+	node := enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel]
+	policy := enactment.Labels[nmstatev1alpha1.EnactmentPolicyLabel]
+	for _, conditionType := range nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionTypes {
+		current := corev1.ConditionUnknown
+		if condition := enactment.Status.Conditions.Find(conditionType); condition != nil {
+			current = condition.Status
+		}
+		for _, status := range conditionStatuses {
+			value := 0.0
+			if status == current {
+				value = 1
+			}
+			enactmentConditions.WithLabelValues(node, policy, string(conditionType), strings.ToLower(string(status))).Set(value)
+		}
+	}
+}
+
+// ForgetConditions removes the condition gauges of a deleted enactment
+func ForgetConditions(enactment nmstatev1alpha1.NodeNetworkConfigurationEnactment) {
+	node := enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel]
+	policy := enactment.Labels[nmstatev1alpha1.EnactmentPolicyLabel]
+	for _, conditionType := range nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionTypes {
+		for _, status := range conditionStatuses {
+			enactmentConditions.DeleteLabelValues(node, policy, string(conditionType), strings.ToLower(string(status)))
+		}
+	}
+}
+
+func nodeSelectorNotMatching(enactment nmstatev1alpha1.NodeNetworkConfigurationEnactment) bool {
+	condition := enactment.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionMatching)
+	return condition != nil && condition.Status == corev1.ConditionFalse && condition.Reason == nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionNodeSelectorNotMatching
+}
@@ -0,0 +1,97 @@
+package enactmentstatus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Enactment conditions metrics", func() {
+	var enactment nmstatev1alpha1.NodeNetworkConfigurationEnactment
+
+	// conditionGauges collects the condition gauges of the enactment by
+	// condition and status, without creating the missing ones
+	conditionGauges := func() map[string]float64 {
+		metrics := make(chan prometheus.Metric, 100)
+		enactmentConditions.Collect(metrics)
+		close(metrics)
+		gauges := map[string]float64{}
+		for metric := range metrics {
+			written := dto.Metric{}
+			Expect(metric.Write(&written)).To(Succeed())
+			labels := map[string]string{}
+			for _, label := range written.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["node"] == "node01" && labels["policy"] == "policy1" {
+				gauges[labels["condition"]+"/"+labels["status"]] = written.GetGauge().GetValue()
+			}
+		}
+		return gauges
+	}
+
+	BeforeEach(func() {
+		enactmentConditions.Reset()
+		enactment = nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node01.policy1",
+				Labels: map[string]string{
+					nmstatev1alpha1.EnactmentNodeLabel:   "node01",
+					nmstatev1alpha1.EnactmentPolicyLabel: "policy1",
+				},
+			},
+		}
+		enactment.Status.Conditions.Set(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable, corev1.ConditionTrue, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured, "")
+		enactment.Status.Conditions.Set(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionMatching, corev1.ConditionTrue, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionNodeSelectorAllSelectorsMatching, "")
+	})
+
+	It("should report a gauge per condition and status with the current status at 1", func() {
+		reportConditions(enactment)
+
+		gauges := conditionGauges()
+		Expect(gauges).To(HaveLen(3 * len(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionTypes)))
+		Expect(gauges).To(HaveKeyWithValue("Available/true", 1.0))
+		Expect(gauges).To(HaveKeyWithValue("Available/false", 0.0))
+		Expect(gauges).To(HaveKeyWithValue("Available/unknown", 0.0))
+		Expect(gauges).To(HaveKeyWithValue("Matching/true", 1.0))
+	})
+
+	It("should report the conditions the enactment has not as unknown", func() {
+		reportConditions(enactment)
+
+		gauges := conditionGauges()
+		Expect(gauges).To(HaveKeyWithValue("Failing/true", 0.0))
+		Expect(gauges).To(HaveKeyWithValue("Failing/false", 0.0))
+		Expect(gauges).To(HaveKeyWithValue("Failing/unknown", 1.0))
+	})
+
+	It("should forget the gauges once the node stops matching the policy selectors", func() {
+		reportConditions(enactment)
+		Expect(conditionGauges()).ToNot(BeEmpty())
+
+		enactment.Status.Conditions.Set(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionMatching, corev1.ConditionFalse, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionNodeSelectorNotMatching, "")
+		reportConditions(enactment)
+		Expect(conditionGauges()).To(BeEmpty())
+	})
+
+	It("should keep the gauges while a required node file is missing", func() {
+		enactment.Status.Conditions.Set(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionMatching, corev1.ConditionFalse, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionNodeFileMissing, "")
+		reportConditions(enactment)
+		Expect(conditionGauges()).To(HaveKeyWithValue("Matching/false", 1.0))
+	})
+
+	It("should forget the gauges of a deleted enactment", func() {
+		reportConditions(enactment)
+		Expect(conditionGauges()).ToNot(BeEmpty())
+
+		ForgetConditions(enactment)
+		Expect(conditionGauges()).To(BeEmpty())
+	})
+})
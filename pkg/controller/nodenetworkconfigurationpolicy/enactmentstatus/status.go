@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
@@ -21,6 +22,11 @@ var (
 	log = logf.Log.WithName("enactmentstatus")
 )
 
+// Update sets the enactment status fields owned by the policy controller of
+// the enactment node, all of them but the drift of non audit policies that
+// is refreshed by the node network state controller with UpdateDrift. Each
+// controller only waits for its own fields to reach the cache, so an update
+// of the other one in the meantime does not make it time out.
 func Update(client client.Client, key types.NamespacedName, statusSetter func(*nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus)) error {
 	logger := log.WithValues("enactment", key.Name)
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
@@ -38,6 +44,7 @@ func Update(client client.Client, key types.NamespacedName, statusSetter func(*n
 		if err != nil {
 			return err
 		}
+		reportConditions(*instance)
 
 		// Wait until enactment has being updated at the node
 		expectedStatus := instance.Status
@@ -47,9 +54,55 @@ func Update(client client.Client, key types.NamespacedName, statusSetter func(*n
 				return false, err
 			}
 
-			isEqual := reflect.DeepEqual(expectedStatus, instance.Status)
+			isEqual := reflect.DeepEqual(withoutDrift(expectedStatus), withoutDrift(instance.Status))
 			logger.Info(fmt.Sprintf("enactment updated at the node: %t", isEqual))
 			return isEqual, nil
 		})
 	})
 }
+
+// UpdateDrift sets the drift of the enactment, detected comparing the
+// desired state with the node current state. The enactment may have been
+// updated since the drift was detected, it's only set if the enactment is
+// still available with that desired state, otherwise the policy controller
+// is applying a new one and the drift is stale.
+func UpdateDrift(client client.Client, key types.NamespacedName, desiredState nmstatev1alpha1.State, drift []string) error {
+	logger := log.WithValues("enactment", key.Name)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		instance := &nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+		err := client.Get(context.TODO(), key, instance)
+		if err != nil {
+			return errors.Wrap(err, "getting enactment failed")
+		}
+		if !instance.Status.Conditions.IsAvailable() || !reflect.DeepEqual(instance.Status.DesiredState, desiredState) {
+			logger.Info("enactment changed since its drift was detected, not updating it")
+			return nil
+		}
+		instance.Status.Drift = drift
+		return client.Status().Update(context.TODO(), instance)
+	})
+}
+
+func withoutDrift(status nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus {
+	status.Drift = nil
+	return status
+}
+
+// SetApplyStarted records the start of a desired state apply, clearing the
+// finish time and duration of the previous one
+func SetApplyStarted(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus, now time.Time) {
+	status.StartedAt = &metav1.Time{Time: now}
+	status.FinishedAt = nil
+	status.Duration = nil
+	status.ResourceUsage = nil
+}
+
+// SetApplyFinished records the finish of the desired state apply in
+// progress, if any, and how long it took
+func SetApplyFinished(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus, now time.Time) {
+	if status.StartedAt == nil || status.FinishedAt != nil {
+		return
+	}
+	status.FinishedAt = &metav1.Time{Time: now}
+	status.Duration = &metav1.Duration{Duration: now.Sub(status.StartedAt.Time)}
+}
@@ -0,0 +1,137 @@
+package enactmentstatus
+
+import (
+	"context"
+	"reflect"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Enactment status", func() {
+	const (
+		appliedState = "interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n"
+		otherState   = "interfaces:\n- name: eth1\n  type: ethernet\n  state: down\n"
+	)
+	key := types.NamespacedName{Name: "node01.policy1"}
+
+	type updateDriftCase struct {
+		available bool
+		// newDesiredState is applied by the policy controller after the
+		// drift is detected
+		newDesiredState string
+		previousDrift   []string
+		drift           []string
+		expectedDrift   []string
+	}
+	DescribeTable("updating the drift",
+		func(c updateDriftCase) {
+			s := scheme.Scheme
+			s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+				&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+			)
+			enactment := &nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Status: nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus{
+					DesiredState: nmstatev1alpha1.NewState(appliedState),
+					Drift:        c.previousDrift,
+				},
+			}
+			if c.available {
+				enactment.Status.Conditions.Set(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable, corev1.ConditionTrue, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured, "")
+			} else {
+				enactment.Status.Conditions.Set(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionProgressing, corev1.ConditionTrue, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionConfigurationProgressing, "")
+			}
+			cli := fake.NewFakeClientWithScheme(s, enactment)
+
+			updated := &nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+			Expect(cli.Get(context.TODO(), key, updated)).To(Succeed())
+			detectedState := updated.Status.DesiredState
+			if c.newDesiredState != "" {
+				updated.Status.DesiredState = nmstatev1alpha1.NewState(c.newDesiredState)
+				Expect(cli.Status().Update(context.TODO(), updated)).To(Succeed())
+			}
+
+			err := UpdateDrift(cli, key, detectedState, c.drift)
+			Expect(err).ToNot(HaveOccurred())
+
+			enactment = &nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+			Expect(cli.Get(context.TODO(), key, enactment)).To(Succeed())
+			Expect(enactment.Status.Drift).To(Equal(c.expectedDrift))
+		},
+		Entry("sets it when the enactment is available with the same desired state", updateDriftCase{
+			available:     true,
+			drift:         []string{"interfaces.eth1.state"},
+			expectedDrift: []string{"interfaces.eth1.state"},
+		}),
+		Entry("clears it when the node is back at the desired state", updateDriftCase{
+			available:     true,
+			previousDrift: []string{"interfaces.eth1.state"},
+			expectedDrift: nil,
+		}),
+		Entry("keeps it when the enactment has a new desired state", updateDriftCase{
+			available:       true,
+			newDesiredState: otherState,
+			previousDrift:   []string{"interfaces.eth1.state"},
+			drift:           []string{"interfaces.eth1.mtu"},
+			expectedDrift:   []string{"interfaces.eth1.state"},
+		}),
+		Entry("keeps it when the enactment is not available", updateDriftCase{
+			available:     false,
+			previousDrift: []string{"interfaces.eth1.state"},
+			drift:         []string{"interfaces.eth1.mtu"},
+			expectedDrift: []string{"interfaces.eth1.state"},
+		}),
+	)
+
+	type withoutDriftCase struct {
+		status   nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus
+		other    nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus
+		expected bool
+	}
+	DescribeTable("comparing statuses without the drift",
+		func(c withoutDriftCase) {
+			Expect(reflect.DeepEqual(withoutDrift(c.status), withoutDrift(c.other))).To(Equal(c.expected))
+		},
+		Entry("ignores a drift set by the node network state controller", withoutDriftCase{
+			status: nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus{
+				DesiredState: nmstatev1alpha1.NewState(appliedState),
+			},
+			other: nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus{
+				DesiredState: nmstatev1alpha1.NewState(appliedState),
+				Drift:        []string{"interfaces.eth1.state"},
+			},
+			expected: true,
+		}),
+		Entry("ignores a drift cleared by the node network state controller", withoutDriftCase{
+			status: nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus{
+				DesiredState: nmstatev1alpha1.NewState(appliedState),
+				Drift:        []string{"interfaces.eth1.state"},
+			},
+			other: nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus{
+				DesiredState: nmstatev1alpha1.NewState(appliedState),
+			},
+			expected: true,
+		}),
+		Entry("does not ignore the rest of the status", withoutDriftCase{
+			status: nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus{
+				DesiredState: nmstatev1alpha1.NewState(appliedState),
+				Drift:        []string{"interfaces.eth1.state"},
+			},
+			other: nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus{
+				DesiredState: nmstatev1alpha1.NewState(otherState),
+				Drift:        []string{"interfaces.eth1.state"},
+			},
+			expected: false,
+		}),
+	)
+})
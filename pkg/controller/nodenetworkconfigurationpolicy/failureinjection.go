@@ -0,0 +1,45 @@
+// +build e2e
+
+// The fail apply node annotation is only honored by the handlers built with
+// the e2e tag, so the e2e tests can make the applies at chosen nodes fail
+// without breaking their network. The released handlers are built without it.
+
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// injectedFailure returns the error of the fail apply annotation of the
+// node, nil if it's not annotated
+func injectedFailure(cli client.Client) error {
+	node := corev1.Node{}
+	err := cli.Get(context.TODO(), types.NamespacedName{Name: nodeName}, &node)
+	if err != nil {
+		return fmt.Errorf("failed checking injected failure at node %s: %v", nodeName, err)
+	}
+	message, injected := node.Annotations[nmstatev1alpha1.NodeFailApplyAnnotation]
+	if !injected {
+		return nil
+	}
+	return fmt.Errorf("failure injected by the %s node annotation: %s", nmstatev1alpha1.NodeFailApplyAnnotation, message)
+}
+
+// applyDesiredState applies the desired state at the node, unless a failure
+// is injected, then nothing is applied
+func applyDesiredState(cli client.Client, desiredState nmstatev1alpha1.State, readinessChecks []nmstatev1alpha1.ReadinessCheck, applyTimeout time.Duration, routeTableCleanup *nmstate.RouteTableCleanup) (string, error) {
+	err := injectedFailure(cli)
+	if err != nil {
+		return "", err
+	}
+	return nmstate.ApplyDesiredState(desiredState, readinessChecks, applyTimeout, routeTableCleanup)
+}
@@ -0,0 +1,37 @@
+// +build e2e
+
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Failure injection", func() {
+	DescribeTable("checking the node annotation",
+		func(annotations map[string]string, shouldFail bool) {
+			node := corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: nodeName, Annotations: annotations},
+			}
+			cli := fake.NewFakeClientWithScheme(scheme.Scheme, &node)
+			err := injectedFailure(cli)
+			if shouldFail {
+				Expect(err).To(MatchError(ContainSubstring("simulated node failure")))
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		},
+		Entry("when the node is annotated, should fail",
+			map[string]string{nmstatev1alpha1.NodeFailApplyAnnotation: "simulated node failure"}, true),
+		Entry("when the node is not annotated, should not fail",
+			map[string]string{}, false),
+	)
+})
@@ -0,0 +1,135 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus"
+)
+
+// How long to wait for the rest of handlers to remove their enactments
+// before checking again
+const finalizeRequeueAfter = 5 * time.Second
+
+// How long to wait for the rest of handlers after the policy deletion, past
+// it the enactments of the handlers not finishing, like the ones of not
+// ready nodes, are deleted by any handler and the finalizer removed
+const finalizeTimeout = 5 * time.Minute
+
+func hasEnactmentsFinalizer(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) bool {
+	for _, finalizer := range policy.Finalizers {
+		if finalizer == nmstatev1alpha1.NodeNetworkConfigurationPolicyEnactmentsFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// updateFinalizers sets the policy finalizers returned by the mutate func,
+// all the handlers update the policy so it's retried on conflict.
+func updateFinalizers(cli client.Client, policyKey types.NamespacedName, mutate func([]string) []string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		err := cli.Get(context.TODO(), policyKey, &policy)
+		if err != nil {
+			return err
+		}
+		policy.Finalizers = mutate(policy.Finalizers)
+		return cli.Update(context.TODO(), &policy)
+	})
+}
+
+func addEnactmentsFinalizer(cli client.Client, policyKey types.NamespacedName) error {
+	return updateFinalizers(cli, policyKey, func(finalizers []string) []string {
+		for _, finalizer := range finalizers {
+			if finalizer == nmstatev1alpha1.NodeNetworkConfigurationPolicyEnactmentsFinalizer {
+				return finalizers
+			}
+		}
+		return append(finalizers, nmstatev1alpha1.NodeNetworkConfigurationPolicyEnactmentsFinalizer)
+	})
+}
+
+func removeEnactmentsFinalizer(cli client.Client, policyKey types.NamespacedName) error {
+	return updateFinalizers(cli, policyKey, func(finalizers []string) []string {
+		remaining := []string{}
+		for _, finalizer := range finalizers {
+			if finalizer != nmstatev1alpha1.NodeNetworkConfigurationPolicyEnactmentsFinalizer {
+				remaining = append(remaining, finalizer)
+			}
+		}
+		return remaining
+	})
+}
+
+// finalize removes the enactment of the policy being deleted at this node,
+// and the ones of nodes that no longer exist, the last handler to finish
+// removes the finalizer. Reconciles are not concurrent so, at this point,
+// any desired state apply of the policy at this node is already finished.
+// Once finalizeTimeout expires the rest of enactments are deleted too, so a
+// handler that is not running does not keep the policy forever.
+func (r *ReconcileNodeNetworkConfigurationPolicy) finalize(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) (reconcile.Result, error) {
+	logger := log.WithName("finalize").WithValues("policy", policy.Name)
+	r.forgetSpecChange(policy.Name)
+	if !hasEnactmentsFinalizer(policy) {
+		return reconcile.Result{}, nil
+	}
+
+	nodes := corev1.NodeList{}
+	err := r.client.List(context.TODO(), &nodes)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed listing nodes")
+	}
+	existingNodes := map[string]bool{}
+	for _, node := range nodes.Items {
+		existingNodes[node.Name] = true
+	}
+
+	enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+	err = r.client.List(context.TODO(), &enactments, client.MatchingLabels{nmstatev1alpha1.EnactmentPolicyLabel: policy.Name})
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed listing enactments")
+	}
+
+	timedOut := policy.DeletionTimestamp != nil && time.Since(policy.DeletionTimestamp.Time) > finalizeTimeout
+	if timedOut {
+		logger.Info("timed out waiting for the rest of nodes, deleting their enactments", "timeout", finalizeTimeout)
+	}
+
+	remaining := 0
+	for i, enactment := range enactments.Items {
+		enactmentNode := enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel]
+		if enactmentNode != nodeName && existingNodes[enactmentNode] && !timedOut {
+			remaining++
+			continue
+		}
+		logger.Info("deleting enactment", "enactment", enactment.Name)
+		err = r.client.Delete(context.TODO(), &enactments.Items[i])
+		if err != nil && !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, errors.Wrapf(err, "failed deleting enactment %s", enactment.Name)
+		}
+		enactmentstatus.ForgetConditions(enactment)
+	}
+
+	if remaining > 0 {
+		logger.Info("waiting for the rest of nodes to delete their enactments", "remaining", remaining)
+		return reconcile.Result{RequeueAfter: finalizeRequeueAfter}, nil
+	}
+
+	logger.Info("all enactments deleted, removing finalizer")
+	err = removeEnactmentsFinalizer(r.client, types.NamespacedName{Name: policy.Name})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return reconcile.Result{}, errors.Wrap(err, "failed removing finalizer")
+	}
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,103 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NodeNetworkConfigurationPolicy finalizer", func() {
+	var (
+		cli        client.Client
+		reconciler ReconcileNodeNetworkConfigurationPolicy
+		policyKey  = types.NamespacedName{Name: "policy1"}
+	)
+
+	enactmentNames := func() []string {
+		enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+		Expect(cli.List(context.TODO(), &enactments)).To(Succeed())
+		names := []string{}
+		for _, enactment := range enactments.Items {
+			names = append(names, enactment.Name)
+		}
+		return names
+	}
+
+	deletedPolicy := func() nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		Expect(cli.Get(context.TODO(), policyKey, &policy)).To(Succeed())
+		policy.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		return policy
+	}
+
+	BeforeEach(func() {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+		)
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: policyKey.Name},
+		}
+		thisNodeEnactment := nmstatev1alpha1.NewEnactment(nodeName, policy)
+		otherNodeEnactment := nmstatev1alpha1.NewEnactment("node02", policy)
+		goneNodeEnactment := nmstatev1alpha1.NewEnactment("node03", policy)
+		thisNode := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+		otherNode := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node02"}}
+		cli = fake.NewFakeClientWithScheme(s, &policy, &thisNodeEnactment, &otherNodeEnactment, &goneNodeEnactment, &thisNode, &otherNode)
+		reconciler = ReconcileNodeNetworkConfigurationPolicy{client: cli}
+
+		Expect(addEnactmentsFinalizer(cli, policyKey)).To(Succeed())
+		Expect(addEnactmentsFinalizer(cli, policyKey)).To(Succeed())
+	})
+
+	It("should add the finalizer once", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		Expect(cli.Get(context.TODO(), policyKey, &policy)).To(Succeed())
+		Expect(policy.Finalizers).To(Equal([]string{nmstatev1alpha1.NodeNetworkConfigurationPolicyEnactmentsFinalizer}))
+	})
+
+	It("should delete the enactments of this and gone nodes and wait for the rest", func() {
+		result, err := reconciler.finalize(deletedPolicy())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{RequeueAfter: finalizeRequeueAfter}))
+		Expect(enactmentNames()).To(ConsistOf(nmstatev1alpha1.EnactmentKey("node02", policyKey.Name).Name))
+		Expect(hasEnactmentsFinalizer(deletedPolicy())).To(BeTrue())
+	})
+
+	It("should remove the finalizer when all the enactments are deleted", func() {
+		otherNodeEnactment := nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+		Expect(cli.Get(context.TODO(), nmstatev1alpha1.EnactmentKey("node02", policyKey.Name), &otherNodeEnactment)).To(Succeed())
+		Expect(cli.Delete(context.TODO(), &otherNodeEnactment)).To(Succeed())
+
+		result, err := reconciler.finalize(deletedPolicy())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(enactmentNames()).To(BeEmpty())
+		Expect(hasEnactmentsFinalizer(deletedPolicy())).To(BeFalse())
+	})
+
+	It("should delete the rest of enactments and remove the finalizer when the deletion times out", func() {
+		policy := deletedPolicy()
+		policy.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-finalizeTimeout - time.Minute)}
+
+		result, err := reconciler.finalize(policy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(enactmentNames()).To(BeEmpty())
+		Expect(hasEnactmentsFinalizer(deletedPolicy())).To(BeFalse())
+	})
+})
@@ -0,0 +1,47 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NodeNetworkConfigurationPolicy generation change", func() {
+	var (
+		reconciler ReconcileNodeNetworkConfigurationPolicy
+		policy     nmstatev1alpha1.NodeNetworkConfigurationPolicy
+	)
+
+	BeforeEach(func() {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+		)
+		policy = nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy1", Generation: 2},
+		}
+		enactment := nmstatev1alpha1.NewEnactment(nodeName, policy)
+		enactment.Status.PolicyGeneration = 2
+		reconciler = ReconcileNodeNetworkConfigurationPolicy{client: fake.NewFakeClientWithScheme(s, &policy, &enactment)}
+	})
+
+	It("should not be changed for the generation initialized at the enactment", func() {
+		Expect(reconciler.generationChanged(policy)).To(BeFalse())
+	})
+
+	It("should be changed for new generations", func() {
+		policy.Generation = 3
+		Expect(reconciler.generationChanged(policy)).To(BeTrue())
+	})
+
+	It("should be changed without enactment", func() {
+		policy.Name = "policy2"
+		Expect(reconciler.generationChanged(policy)).To(BeTrue())
+	})
+})
@@ -0,0 +1,77 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Upper bound of revisions kept per enactment, each one has a whole desired
+// state so they cannot grow the enactment beyond the object size limit
+const maxEnactmentHistoryLength = 10
+
+var (
+	// Previous desired states kept at the enactments, the history is not
+	// kept with 0
+	enactmentHistoryLength = 0
+)
+
+func init() {
+	length, isSet := os.LookupEnv("ENACTMENT_HISTORY_LENGTH")
+	if !isSet || length == "" {
+		return
+	}
+	var err error
+	enactmentHistoryLength, err = strconv.Atoi(length)
+	if err != nil {
+		panic(fmt.Sprintf("Failed while converting evnironment variable to int: %v", err))
+	}
+	if enactmentHistoryLength > maxEnactmentHistoryLength {
+		enactmentHistoryLength = maxEnactmentHistoryLength
+	}
+}
+
+// finishedReason returns the reason the enactment apply finished with,
+// empty if it did not finish
+func finishedReason(conditions nmstatev1alpha1.ConditionList) nmstatev1alpha1.ConditionReason {
+	for _, conditionType := range []nmstatev1alpha1.ConditionType{
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing,
+		nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable,
+	} {
+		condition := conditions.Find(conditionType)
+		if condition != nil && condition.Status == corev1.ConditionTrue {
+			return condition.Reason
+		}
+	}
+	return ""
+}
+
+// recordRevision moves the enactment desired state to its history if the
+// policy generation or desired state replacing it are different, keeping
+// up to length revisions
+func recordRevision(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus, policyGeneration int64, desiredState nmstatev1alpha1.State, length int) {
+	if length <= 0 {
+		status.History = nil
+		return
+	}
+	if len(status.DesiredState.Raw) == 0 {
+		return
+	}
+	if status.PolicyGeneration == policyGeneration && reflect.DeepEqual(status.DesiredState, desiredState) {
+		return
+	}
+	status.History = append(status.History, nmstatev1alpha1.EnactmentRevision{
+		PolicyGeneration: status.PolicyGeneration,
+		DesiredState:     status.DesiredState,
+		FinishedAt:       status.FinishedAt,
+		Reason:           finishedReason(status.Conditions),
+	})
+	if len(status.History) > length {
+		status.History = status.History[len(status.History)-length:]
+	}
+}
@@ -0,0 +1,72 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("Enactment history", func() {
+	finishedAt := metav1.NewTime(time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC))
+	eth1Up := nmstatev1alpha1.NewState("interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n")
+	eth1Down := nmstatev1alpha1.NewState("interfaces:\n- name: eth1\n  type: ethernet\n  state: down\n")
+
+	status := func(generation int64, desiredState nmstatev1alpha1.State, setter func(*nmstatev1alpha1.ConditionList, string)) nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus {
+		status := nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus{
+			PolicyGeneration: generation,
+			DesiredState:     desiredState,
+			FinishedAt:       &finishedAt,
+		}
+		setter(&status.Conditions, "")
+		return status
+	}
+
+	It("should keep the replaced desired state with its result", func() {
+		s := status(1, eth1Up, enactmentconditions.SetSuccess)
+		recordRevision(&s, 2, eth1Down, 3)
+		Expect(s.History).To(Equal([]nmstatev1alpha1.EnactmentRevision{
+			{PolicyGeneration: 1, DesiredState: eth1Up, FinishedAt: &finishedAt, Reason: nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured},
+		}))
+	})
+
+	It("should keep the failed desired states too", func() {
+		s := status(1, eth1Up, enactmentconditions.SetFailedToConfigure)
+		recordRevision(&s, 2, eth1Down, 3)
+		Expect(s.History).To(HaveLen(1))
+		Expect(s.History[0].Reason).To(Equal(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailedToConfigure))
+	})
+
+	It("should not keep the desired state reconciled again", func() {
+		s := status(1, eth1Up, enactmentconditions.SetSuccess)
+		recordRevision(&s, 1, eth1Up, 3)
+		Expect(s.History).To(BeEmpty())
+	})
+
+	It("should not keep the empty desired state of new enactments", func() {
+		s := status(0, nmstatev1alpha1.NewState(""), enactmentconditions.SetSuccess)
+		recordRevision(&s, 1, eth1Up, 3)
+		Expect(s.History).To(BeEmpty())
+	})
+
+	It("should drop the oldest revisions beyond the length", func() {
+		s := status(3, eth1Up, enactmentconditions.SetSuccess)
+		s.History = []nmstatev1alpha1.EnactmentRevision{{PolicyGeneration: 1}, {PolicyGeneration: 2}}
+		recordRevision(&s, 4, eth1Down, 2)
+		Expect(s.History).To(HaveLen(2))
+		Expect(s.History[0].PolicyGeneration).To(Equal(int64(2)))
+		Expect(s.History[1].PolicyGeneration).To(Equal(int64(3)))
+	})
+
+	It("should drop the history if it's disabled", func() {
+		s := status(3, eth1Up, enactmentconditions.SetSuccess)
+		s.History = []nmstatev1alpha1.EnactmentRevision{{PolicyGeneration: 1}, {PolicyGeneration: 2}}
+		recordRevision(&s, 4, eth1Down, 0)
+		Expect(s.History).To(BeNil())
+	})
+})
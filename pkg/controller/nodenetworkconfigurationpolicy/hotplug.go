@@ -0,0 +1,278 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+var (
+	// Time to wait after a new interface appears at the node before
+	// reconciling the policies configuring it, interfaces appearing in the
+	// meantime, like a batch of VFs, are coalesced in a single reconcile
+	hotplugDebounce = 5 * time.Second
+)
+
+func init() {
+	debounce, isSet := os.LookupEnv("HOTPLUG_DEBOUNCE")
+	if !isSet || debounce == "" {
+		return
+	}
+	var err error
+	hotplugDebounce, err = time.ParseDuration(debounce)
+	if err != nil {
+		panic(fmt.Sprintf("Failed while converting evnironment variable to duration: %v", err))
+	}
+}
+
+func interfaceNames(state nmstatev1alpha1.State) (map[string]bool, error) {
+	stateJSON, err := yaml.YAMLToJSON(state.Raw)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for _, name := range gjson.ParseBytes(stateJSON).Get("interfaces.#.name").Array() {
+		names[name.String()] = true
+	}
+	return names, nil
+}
+
+// addedInterfaces returns the names of the interfaces present at the new
+// state but not at the old one
+func addedInterfaces(oldState nmstatev1alpha1.State, newState nmstatev1alpha1.State) (map[string]bool, error) {
+	oldNames, err := interfaceNames(oldState)
+	if err != nil {
+		return nil, err
+	}
+	newNames, err := interfaceNames(newState)
+	if err != nil {
+		return nil, err
+	}
+	added := map[string]bool{}
+	for name := range newNames {
+		if !oldNames[name] {
+			added[name] = true
+		}
+	}
+	return added, nil
+}
+
+// renderDesiredState renders the policy desired state like the handler
+// applies it, against the current state reported at the NodeNetworkState
+// instead of the one read from the node
+func renderDesiredState(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, reportedState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	if renderedByWebhook(policy) {
+		return policy.Spec.DesiredState, nil
+	}
+	desiredState := policy.Spec.DesiredState
+	if policy.Spec.DesiredStatePatch != nil {
+		currentState, err := render.ObservedState(reportedState)
+		if err != nil {
+			return desiredState, err
+		}
+		desiredState, err = render.DesiredState(currentState, *policy.Spec.DesiredStatePatch)
+		if err != nil {
+			return desiredState, err
+		}
+	}
+	if !render.HasInterfaceMatches(desiredState) {
+		return desiredState, nil
+	}
+	interfaces, err := render.NodeInterfaces(reportedState)
+	if err != nil {
+		return desiredState, err
+	}
+	return render.ResolveInterfaceMatches(desiredState, interfaces)
+}
+
+// patchedInterfaces returns the desired state with only the interfaces
+// that differ from the current state, the desired state patches keep the
+// rest of the current state as it is
+func patchedInterfaces(desiredState nmstatev1alpha1.State, reportedState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	currentState, err := render.ObservedState(reportedState)
+	if err != nil {
+		return desiredState, err
+	}
+	var current, desired map[string]interface{}
+	err = yaml.Unmarshal(currentState.Raw, &current)
+	if err != nil {
+		return desiredState, err
+	}
+	err = yaml.Unmarshal(desiredState.Raw, &desired)
+	if err != nil {
+		return desiredState, err
+	}
+
+	currentInterfaces := map[string]interface{}{}
+	interfaces, _ := current["interfaces"].([]interface{})
+	for _, iface := range interfaces {
+		ifaceMap, _ := iface.(map[string]interface{})
+		if name, named := ifaceMap["name"].(string); named {
+			currentInterfaces[name] = iface
+		}
+	}
+	patched := []interface{}{}
+	interfaces, _ = desired["interfaces"].([]interface{})
+	for _, iface := range interfaces {
+		ifaceMap, _ := iface.(map[string]interface{})
+		name, _ := ifaceMap["name"].(string)
+		if !reflect.DeepEqual(currentInterfaces[name], iface) {
+			patched = append(patched, iface)
+		}
+	}
+
+	patchedState, err := yaml.Marshal(map[string]interface{}{"interfaces": patched})
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: patchedState}, nil
+}
+
+// configuresInterfaces returns true if the policy desired state, rendered
+// with the node reported state, modifies any of the interfaces. The ones
+// identified by MAC or PCI address are matched by name once they are at the
+// node and the ones the desired state patches change once they are at the
+// current state they are applied to.
+func configuresInterfaces(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, reportedState nmstatev1alpha1.State, interfaces map[string]bool) (bool, error) {
+	desiredState, err := renderDesiredState(policy, reportedState)
+	if render.IsInterfaceNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if policy.Spec.DesiredStatePatch != nil && !renderedByWebhook(policy) {
+		desiredState, err = patchedInterfaces(desiredState, reportedState)
+		if err != nil {
+			return false, err
+		}
+	}
+	modified, err := render.ModifiedInterfaces(desiredState)
+	if err != nil {
+		return false, err
+	}
+	for name := range modified {
+		if interfaces[name] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hotplugDebouncer enqueues the policies once no new interface they
+// configure has appeared for the hotplug debounce, every new interface
+// extends the deadline of the policies configuring it
+type hotplugDebouncer struct {
+	mutex     sync.Mutex
+	deadlines map[reconcile.Request]time.Time
+}
+
+func newHotplugDebouncer() *hotplugDebouncer {
+	return &hotplugDebouncer{deadlines: map[reconcile.Request]time.Time{}}
+}
+
+// extend sets the request deadline, it returns true if the request was not
+// pending, so it has to be scheduled
+func (d *hotplugDebouncer) extend(request reconcile.Request, deadline time.Time) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	_, pending := d.deadlines[request]
+	d.deadlines[request] = deadline
+	return !pending
+}
+
+// remaining returns how long is left until the request deadline, once it
+// has expired the request is no longer pending
+func (d *hotplugDebouncer) remaining(request reconcile.Request, now time.Time) time.Duration {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	remaining := d.deadlines[request].Sub(now)
+	if remaining <= 0 {
+		delete(d.deadlines, request)
+		return 0
+	}
+	return remaining
+}
+
+// add enqueues the request after the hotplug debounce, or later if it's
+// extended in the meantime
+func (d *hotplugDebouncer) add(queue workqueue.RateLimitingInterface, request reconcile.Request) {
+	if hotplugDebounce <= 0 {
+		queue.Add(request)
+		return
+	}
+	if !d.extend(request, time.Now().Add(hotplugDebounce)) {
+		return
+	}
+	var wait func()
+	wait = func() {
+		if remaining := d.remaining(request, time.Now()); remaining > 0 {
+			time.AfterFunc(remaining, wait)
+			return
+		}
+		queue.Add(request)
+	}
+	time.AfterFunc(hotplugDebounce, wait)
+}
+
+// hotplugHandler returns an update event handler for the NodeNetworkState
+// of this node that enqueues the policies configuring the interfaces that
+// have appeared at it, after the hotplug debounce
+func hotplugHandler(cli client.Client) func(event.UpdateEvent, workqueue.RateLimitingInterface) {
+	debouncer := newHotplugDebouncer()
+	return func(updateEvent event.UpdateEvent, queue workqueue.RateLimitingInterface) {
+		logger := log.WithName("hotplugHandler")
+		oldState, ok := updateEvent.ObjectOld.(*nmstatev1alpha1.NodeNetworkState)
+		if !ok {
+			return
+		}
+		newState, ok := updateEvent.ObjectNew.(*nmstatev1alpha1.NodeNetworkState)
+		if !ok || newState.Name != nodeName {
+			return
+		}
+
+		added, err := addedInterfaces(oldState.Status.CurrentState, newState.Status.CurrentState)
+		if err != nil {
+			logger.Error(err, "failed calculating added interfaces")
+			return
+		}
+		if len(added) == 0 {
+			return
+		}
+
+		policyList := nmstatev1alpha1.NodeNetworkConfigurationPolicyList{}
+		err = cli.List(context.TODO(), &policyList)
+		if err != nil {
+			logger.Error(err, "failed listing policies")
+			return
+		}
+
+		for _, policy := range policyList.Items {
+			configures, err := configuresInterfaces(policy, newState.Status.CurrentState, added)
+			if err != nil {
+				logger.Error(err, "failed reading policy desired state", "policy", policy.Name)
+				continue
+			}
+			if !configures {
+				continue
+			}
+			logger.Info("new interfaces configured by policy, reconciling it", "policy", policy.Name, "debounce", hotplugDebounce)
+			debouncer.add(queue, reconcile.Request{NamespacedName: types.NamespacedName{Name: policy.Name}})
+		}
+	}
+}
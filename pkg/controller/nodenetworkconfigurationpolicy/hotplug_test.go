@@ -0,0 +1,170 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NodeNetworkConfigurationPolicy hotplug", func() {
+	var (
+		cli   client.Client
+		queue workqueue.RateLimitingInterface
+	)
+
+	nodeNetworkState := func(name string, state string) *nmstatev1alpha1.NodeNetworkState {
+		return &nmstatev1alpha1.NodeNetworkState{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: nmstatev1alpha1.NodeNetworkStateStatus{
+				CurrentState: nmstatev1alpha1.NewState(state),
+			},
+		}
+	}
+
+	policy := func(name string, desiredState string) *nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		return &nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+				DesiredState: nmstatev1alpha1.NewState(desiredState),
+			},
+		}
+	}
+
+	queued := func() []string {
+		names := []string{}
+		for queue.Len() > 0 {
+			item, _ := queue.Get()
+			names = append(names, item.(reconcile.Request).Name)
+			queue.Done(item)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		hotplugDebounce = 0
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicyList{},
+		)
+		cli = fake.NewFakeClientWithScheme(s,
+			policy("eth1-policy", "interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n"),
+			policy("eth2-policy", "interfaces:\n- name: eth2\n  type: ethernet\n  state: up\n"),
+			policy("dns-policy", "dns-resolver:\n  config:\n    server:\n    - 8.8.8.8\n"),
+		)
+		queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	})
+
+	AfterEach(func() {
+		queue.ShutDown()
+	})
+
+	update := func(nodeName string, oldState string, newState string) {
+		hotplugHandler(cli)(event.UpdateEvent{
+			ObjectOld: nodeNetworkState(nodeName, oldState),
+			ObjectNew: nodeNetworkState(nodeName, newState),
+		}, queue)
+	}
+
+	It("should enqueue the policies configuring new interfaces", func() {
+		update(nodeName,
+			"interfaces:\n- name: eth0\n",
+			"interfaces:\n- name: eth0\n- name: eth1\n")
+		Expect(queued()).To(ConsistOf("eth1-policy"))
+	})
+
+	It("should not enqueue policies if no interface appears", func() {
+		update(nodeName,
+			"interfaces:\n- name: eth0\n- name: eth1\n",
+			"interfaces:\n- name: eth1\n")
+		Expect(queued()).To(BeEmpty())
+	})
+
+	It("should ignore the NodeNetworkState of other nodes", func() {
+		update("node02",
+			"interfaces:\n- name: eth0\n",
+			"interfaces:\n- name: eth0\n- name: eth1\n")
+		Expect(queued()).To(BeEmpty())
+	})
+
+	It("should enqueue a policy once for many new interfaces", func() {
+		cli = fake.NewFakeClientWithScheme(scheme.Scheme,
+			policy("vfs-policy", "interfaces:\n- name: eth1v0\n- name: eth1v1\n"),
+		)
+		update(nodeName,
+			"interfaces:\n- name: eth0\n",
+			"interfaces:\n- name: eth0\n- name: eth1v0\n- name: eth1v1\n")
+		Expect(queued()).To(Equal([]string{"vfs-policy"}))
+	})
+
+	It("should enqueue the policies matching new interfaces by MAC or PCI address", func() {
+		cli = fake.NewFakeClientWithScheme(scheme.Scheme,
+			policy("mac-policy", "interfaces:\n- name: uplink\n  type: ethernet\n  state: up\n  match:\n    mac-address: 52:54:00:00:00:01\n"),
+			policy("pci-policy", "interfaces:\n- name: uplink\n  type: ethernet\n  state: up\n  match:\n    pci-address: 0000:03:00.0\n"),
+			policy("other-mac-policy", "interfaces:\n- name: uplink\n  type: ethernet\n  state: up\n  match:\n    mac-address: 52:54:00:00:00:02\n"),
+		)
+		update(nodeName,
+			"interfaces:\n- name: eth0\n",
+			`interfaces:
+- name: eth0
+- name: ens3
+  hardware:
+    mac-address: 52:54:00:00:00:01
+    pci-address: 0000:03:00.0
+`)
+		Expect(queued()).To(ConsistOf("mac-policy", "pci-policy"))
+	})
+
+	It("should enqueue the policies attaching new interfaces to their bridges", func() {
+		cli = fake.NewFakeClientWithScheme(scheme.Scheme,
+			policy("br1-policy", "interfaces:\n- name: br1\n  type: linux-bridge\n  state: up\n  bridge:\n    port:\n    - name: eth1\n"),
+		)
+		update(nodeName,
+			"interfaces:\n- name: br1\n",
+			"interfaces:\n- name: br1\n- name: eth1\n")
+		Expect(queued()).To(ConsistOf("br1-policy"))
+	})
+
+	It("should enqueue the policies whose desired state patch changes new interfaces", func() {
+		patchPolicy := func(name string, patch string) *nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+			return &nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+					DesiredStatePatch: &nmstatev1alpha1.StatePatch{Raw: []byte(patch)},
+				},
+			}
+		}
+		cli = fake.NewFakeClientWithScheme(scheme.Scheme,
+			patchPolicy("eth1-mtu-policy", "- op: replace\n  path: /interfaces/1/mtu\n  value: 9000\n"),
+			patchPolicy("eth0-mtu-policy", "- op: replace\n  path: /interfaces/0/mtu\n  value: 9000\n"),
+		)
+		update(nodeName,
+			"interfaces:\n- name: eth0\n  mtu: 1500\n",
+			"interfaces:\n- name: eth0\n  mtu: 1500\n- name: eth1\n  mtu: 1500\n")
+		Expect(queued()).To(ConsistOf("eth1-mtu-policy"))
+	})
+
+	It("should extend the debounce of a pending policy with every new interface", func() {
+		debouncer := newHotplugDebouncer()
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "vfs-policy"}}
+		start := time.Now()
+
+		Expect(debouncer.extend(request, start.Add(5*time.Second))).To(BeTrue())
+		Expect(debouncer.extend(request, start.Add(8*time.Second))).To(BeFalse())
+		Expect(debouncer.remaining(request, start.Add(5*time.Second))).To(Equal(3 * time.Second))
+		Expect(debouncer.remaining(request, start.Add(8*time.Second))).To(Equal(time.Duration(0)))
+		Expect(debouncer.extend(request, start.Add(15*time.Second))).To(BeTrue())
+	})
+})
@@ -0,0 +1,19 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"github.com/pkg/errors"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+// excludeIgnoredInterfaces removes the policy ignored interfaces from its
+// desired state, before it's set at the enactment, and returns them
+func excludeIgnoredInterfaces(policy *nmstatev1alpha1.NodeNetworkConfigurationPolicy) ([]string, error) {
+	desiredState, ignoredInterfaces, err := render.IgnoreInterfaces(policy.Spec.DesiredState, policy.Spec.IgnoredInterfaces)
+	if err != nil {
+		return ignoredInterfaces, errors.Wrap(err, "failed excluding ignored interfaces")
+	}
+	policy.Spec.DesiredState = desiredState
+	return ignoredInterfaces, nil
+}
@@ -0,0 +1,55 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// mtuLinkFlaps returns the links the desired state MTU changes are going to
+// bounce, they have to be taken before applying it to compare the MTUs
+func mtuLinkFlaps(desiredState nmstatev1alpha1.State, logger logr.Logger) []string {
+	linkFlaps, err := nmstate.MTULinkFlaps(desiredState)
+	if err != nil {
+		logger.Error(err, "failed computing the links bounced by MTU changes, not reporting them")
+		return nil
+	}
+	if len(linkFlaps) > 0 {
+		logger.Info("MTU changes bounce links, the flap cannot be avoided", "links", linkFlaps)
+	}
+	return linkFlaps
+}
+
+// stpManagementBridges returns the bridges carrying the node default route
+// the desired state enables the spanning tree at, their traffic stops for
+// the forwarding delay, it's applied anyway and reported at the enactment
+func stpManagementBridges(desiredState nmstatev1alpha1.State, logger logr.Logger) []string {
+	bridges, err := nmstate.STPManagementBridges(desiredState)
+	if err != nil {
+		logger.Error(err, "failed checking the spanning tree of the bridges carrying the default route, not reporting them")
+		return nil
+	}
+	if len(bridges) > 0 {
+		logger.Info("WARNING: enabling spanning tree at bridges carrying the node default route, their traffic stops for the forwarding delay", "bridges", bridges)
+	}
+	return bridges
+}
+
+// reportLinkFlaps reports at the policy enactment the links bounced by the
+// MTU changes of the applied desired state
+func reportLinkFlaps(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, linkFlaps []string) {
+	enactmentKey := nmstatev1alpha1.EnactmentKey(nodeName, policy.Name)
+	logger := log.WithName("reportLinkFlaps").WithValues("enactment", enactmentKey.Name)
+	if len(linkFlaps) == 0 {
+		linkFlaps = nil
+	}
+	err := enactmentstatus.Update(cli, enactmentKey, func(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) {
+		status.LinkFlaps = linkFlaps
+	})
+	if err != nil {
+		logger.Error(err, "failed reporting link flaps")
+	}
+}
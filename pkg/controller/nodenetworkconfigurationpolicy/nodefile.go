@@ -0,0 +1,42 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Policies missing their required node file are checked again after it,
+// the file is created by external provisioning without notice
+const nodeFileRetryInterval = 1 * time.Minute
+
+var (
+	// The host directory is mounted at the same path at the handler
+	nodeFilesDir = nmstatev1alpha1.NodeNetworkConfigurationPolicyNodeFilesDir
+)
+
+// nodeFileMissing tells if the policy required node file does not exist at
+// the node, paths outside of the node files directory are refused in case
+// the webhook was bypassed
+func nodeFileMissing(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) (bool, error) {
+	path := policy.Spec.RequireNodeFile
+	if path == "" {
+		return false, nil
+	}
+	relativePath := strings.TrimPrefix(filepath.Clean(path), nmstatev1alpha1.NodeNetworkConfigurationPolicyNodeFilesDir+"/")
+	if relativePath == filepath.Clean(path) || strings.HasPrefix(relativePath, "..") {
+		return false, fmt.Errorf("required node file %s is not at %s", path, nmstatev1alpha1.NodeNetworkConfigurationPolicyNodeFilesDir)
+	}
+	_, err := os.Stat(filepath.Join(nodeFilesDir, relativePath))
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed checking required node file %s: %v", path, err)
+	}
+	return false, nil
+}
@@ -0,0 +1,54 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Required node file", func() {
+	var originalNodeFilesDir string
+
+	policy := func(path string) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		return nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{RequireNodeFile: path},
+		}
+	}
+
+	BeforeEach(func() {
+		originalNodeFilesDir = nodeFilesDir
+		dir, err := ioutil.TempDir("", "node-files")
+		Expect(err).ToNot(HaveOccurred())
+		nodeFilesDir = dir
+		Expect(ioutil.WriteFile(filepath.Join(dir, "provisioned"), []byte{}, 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(nodeFilesDir)
+		nodeFilesDir = originalNodeFilesDir
+	})
+
+	It("should not require anything without node file", func() {
+		Expect(nodeFileMissing(policy(""))).To(BeFalse())
+	})
+
+	It("should find an existing node file", func() {
+		Expect(nodeFileMissing(policy("/var/lib/nmstate/node-files/provisioned"))).To(BeFalse())
+	})
+
+	It("should report a missing node file", func() {
+		Expect(nodeFileMissing(policy("/var/lib/nmstate/node-files/sriov-ready"))).To(BeTrue())
+	})
+
+	It("should refuse node files outside of the node files directory", func() {
+		for _, path := range []string{"/etc/hostname", "/var/lib/nmstate/node-files", "/var/lib/nmstate/node-files/../secret"} {
+			_, err := nodeFileMissing(policy(path))
+			Expect(err).To(HaveOccurred(), path)
+		}
+	})
+})
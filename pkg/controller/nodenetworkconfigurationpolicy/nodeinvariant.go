@@ -0,0 +1,15 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// renderedByWebhook returns true if the webhook found the policy desired
+// state node invariant, so the handlers skip rendering it and resolving its
+// secrets. The fingerprint tells the annotation is from the current desired
+// state, otherwise it's rendered like the rest of policies.
+func renderedByWebhook(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) bool {
+	fingerprint, ok := policy.Annotations[nmstatev1alpha1.NodeNetworkConfigurationPolicyNodeInvariantAnnotation]
+	return ok && fingerprint == nmstate.DesiredStateFingerprint(policy.Spec.DesiredState)
+}
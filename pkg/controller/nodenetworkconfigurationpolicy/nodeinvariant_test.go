@@ -0,0 +1,56 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/fake"
+)
+
+var _ = Describe("Node invariant policies", func() {
+	var (
+		fakeNmstatectl     *fake.Nmstatectl
+		previousNmstatectl nmstate.Nmstatectl
+		policy             nmstatev1alpha1.NodeNetworkConfigurationPolicy
+	)
+
+	BeforeEach(func() {
+		fakeNmstatectl = fake.NewNmstatectl("interfaces: []\n")
+		previousNmstatectl = nmstate.SetNmstatectl(fakeNmstatectl)
+		policy = nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState("interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n")
+		policy.Annotations = map[string]string{
+			nmstatev1alpha1.NodeNetworkConfigurationPolicyNodeInvariantAnnotation: nmstate.DesiredStateFingerprint(policy.Spec.DesiredState),
+		}
+	})
+
+	AfterEach(func() {
+		nmstate.SetNmstatectl(previousNmstatectl)
+	})
+
+	It("should take the desired state rendered by the webhook as is", func() {
+		Expect(renderedByWebhook(policy)).To(BeTrue())
+		desiredState, err := effectiveDesiredState(policy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(desiredState).To(Equal(policy.Spec.DesiredState))
+		Expect(fakeNmstatectl.Commands).To(BeEmpty())
+	})
+
+	It("should render the desired state if it changed since the webhook rendered it", func() {
+		patch := nmstatev1alpha1.NewStatePatch("- op: add\n  path: /interfaces/-\n  value: {name: eth2, type: ethernet, state: up}\n")
+		policy.Spec.DesiredStatePatch = &patch
+		policy.Spec.DesiredState = nmstatev1alpha1.State{}
+		Expect(renderedByWebhook(policy)).To(BeFalse())
+		desiredState, err := effectiveDesiredState(policy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(desiredState.String()).To(ContainSubstring("eth2"))
+		Expect(fakeNmstatectl.Commands).To(Equal([]string{"show"}))
+	})
+
+	It("should render the policies without annotation", func() {
+		delete(policy.Annotations, nmstatev1alpha1.NodeNetworkConfigurationPolicyNodeInvariantAnnotation)
+		Expect(renderedByWebhook(policy)).To(BeFalse())
+	})
+})
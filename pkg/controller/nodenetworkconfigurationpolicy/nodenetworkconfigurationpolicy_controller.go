@@ -9,9 +9,11 @@ import (
 	"github.com/pkg/errors"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -28,6 +30,7 @@ import (
 	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/policyconditions"
 	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/selectors"
 	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
 )
 
 var (
@@ -43,11 +46,17 @@ var (
 		UpdateFunc: func(updateEvent event.UpdateEvent) bool {
 			// [1] https://blog.openshift.com/kubernetes-operators-best-practices/
 			generationIsDifferent := updateEvent.MetaNew.GetGeneration() != updateEvent.MetaOld.GetGeneration()
-			return generationIsDifferent
+			deletionStarted := updateEvent.MetaOld.GetDeletionTimestamp() == nil && updateEvent.MetaNew.GetDeletionTimestamp() != nil
+			return generationIsDifferent || deletionStarted || quarantineResumeIsDifferent(updateEvent.MetaOld, updateEvent.MetaNew) || activateIsDifferent(updateEvent.MetaOld, updateEvent.MetaNew) || rolloutResumeIsDifferent(updateEvent.MetaOld, updateEvent.MetaNew)
 		},
 	}
 )
 
+func quarantineResumeIsDifferent(metaOld metav1.Object, metaNew metav1.Object) bool {
+	annotation := nmstatev1alpha1.NodeNetworkConfigurationPolicyQuarantineResumeAnnotation
+	return metaOld.GetAnnotations()[annotation] != metaNew.GetAnnotations()[annotation]
+}
+
 func init() {
 	var isSet = false
 	nodeName, isSet = os.LookupEnv("NODE_NAME")
@@ -59,12 +68,15 @@ func init() {
 // Add creates a new NodeNetworkConfigurationPolicy Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
+	if nmstate.RollbackCheckpointDisabled() {
+		log.Info("WARNING: rollback checkpoint is disabled, desired states breaking the node connectivity will not be rolled back, only use it with disposable nodes")
+	}
 	return add(mgr, newReconciler(mgr))
 }
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileNodeNetworkConfigurationPolicy{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return &ReconcileNodeNetworkConfigurationPolicy{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor("nmstate-handler")}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -81,6 +93,31 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to the rest of policies to reconcile the base
+	// policies they override
+	err = c.Watch(&source.Kind{Type: &nmstatev1alpha1.NodeNetworkConfigurationPolicy{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: baseRequestsForPolicy(mgr.GetClient())}, watchPredicate)
+	if err != nil {
+		return err
+	}
+
+	// Watch for new interfaces at the node NodeNetworkState to reconcile the
+	// policies configuring them
+	err = c.Watch(&source.Kind{Type: &nmstatev1alpha1.NodeNetworkState{}}, &handler.Funcs{UpdateFunc: hotplugHandler(mgr.GetClient())})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the secrets referenced by the policies to apply
+	// the rotated credentials
+	secrets, err := secretsSource(mgr)
+	if err != nil {
+		return err
+	}
+	err = c.Watch(secrets, &handler.EnqueueRequestsFromMapFunc{ToRequests: policyRequestsForSecret(mgr.GetClient())})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -91,8 +128,17 @@ var _ reconcile.Reconciler = &ReconcileNodeNetworkConfigurationPolicy{}
 type ReconcileNodeNetworkConfigurationPolicy struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client client.Client
-	scheme *runtime.Scheme
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+
+	// Last spec change seen per policy to apply the cooldown, only
+	// accessed from the reconcile loop
+	specChanges map[string]specChange
+
+	// Applies retried per policy, or bundle, while nmstate is busy, only
+	// accessed from the reconcile loop
+	busyAttempts map[string]int
 }
 
 func (r *ReconcileNodeNetworkConfigurationPolicy) waitEnactmentCreated(enactmentKey types.NamespacedName) error {
@@ -113,7 +159,7 @@ func (r *ReconcileNodeNetworkConfigurationPolicy) waitEnactmentCreated(enactment
 	return pollErr
 }
 
-func (r *ReconcileNodeNetworkConfigurationPolicy) initializeEnactment(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) error {
+func (r *ReconcileNodeNetworkConfigurationPolicy) initializeEnactment(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, staged bool, ignoredInterfaces []string, overriddenInterfaces []nmstatev1alpha1.InterfaceOverride) error {
 	enactmentKey := nmstatev1alpha1.EnactmentKey(nodeName, policy.Name)
 	logger := log.WithName("initializeEnactment").WithValues("policy", policy.Name, "enactment", enactmentKey.Name)
 	// Return if it's already initialize or we cannot retrieve it
@@ -125,6 +171,7 @@ func (r *ReconcileNodeNetworkConfigurationPolicy) initializeEnactment(policy nms
 	if err != nil && apierrors.IsNotFound(err) {
 		logger.Info("creating enactment")
 		enactment = nmstatev1alpha1.NewEnactment(nodeName, policy)
+		copyCorrelationAnnotation(policy, &enactment)
 		err = r.client.Create(context.TODO(), &enactment)
 		if err != nil {
 			return errors.Wrap(err, fmt.Sprintf("error creating NodeNetworkConfigurationEnactment: %+v", enactment))
@@ -134,15 +181,65 @@ func (r *ReconcileNodeNetworkConfigurationPolicy) initializeEnactment(policy nms
 			return errors.Wrap(err, fmt.Sprintf("error waitting for NodeNetworkConfigurationEnactment: %+v", enactment))
 		}
 	} else {
+		// A new policy generation may come from another change, with
+		// another correlation ID, and enactments created by older handlers
+		// miss the node label
+		correlationChanged := correlationChangedWithGeneration(policy, &enactment)
+		labelChanged := setEnactmentNodeLabel(&enactment)
+		if correlationChanged || labelChanged {
+			err = r.client.Update(context.TODO(), &enactment)
+			if err != nil {
+				return errors.Wrap(err, "error updating enactment labels and correlation annotation")
+			}
+		}
 		enactmentConditions := enactmentconditions.New(r.client, enactmentKey)
 		enactmentConditions.Reset()
 	}
 
 	return enactmentstatus.Update(r.client, enactmentKey, func(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) {
-		status.DesiredState = policy.Spec.DesiredState
+		initializeEnactmentStatus(status, policy, staged, ignoredInterfaces, overriddenInterfaces)
 	})
 }
 
+// generationChanged returns true if the enactment of this node is not
+// initialized yet for the policy generation
+func (r *ReconcileNodeNetworkConfigurationPolicy) generationChanged(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) bool {
+	enactment := nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+	err := r.client.Get(context.TODO(), nmstatev1alpha1.EnactmentKey(nodeName, policy.Name), &enactment)
+	if err != nil {
+		return true
+	}
+	return enactment.Status.PolicyGeneration != policy.Generation
+}
+
+// setEnactmentNodeLabel labels the enactment with the node it belongs to,
+// the enactments are listed by node with it. Returns whether it changed.
+func setEnactmentNodeLabel(enactment *nmstatev1alpha1.NodeNetworkConfigurationEnactment) bool {
+	if enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel] == nodeName {
+		return false
+	}
+	if enactment.Labels == nil {
+		enactment.Labels = map[string]string{}
+	}
+	enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel] = nodeName
+	return true
+}
+
+// initializeEnactmentStatus records the policy desired state about to be
+// applied at the enactment. The staged desired states are not applied, the
+// enactment keeps the one applied until the policy, or the staged policies
+// of its bundle, are activated.
+func initializeEnactmentStatus(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, staged bool, ignoredInterfaces []string, overriddenInterfaces []nmstatev1alpha1.InterfaceOverride) {
+	if !staged {
+		recordRevision(status, policy.Generation, policy.Spec.DesiredState, enactmentHistoryLength)
+		status.DesiredState = policy.Spec.DesiredState
+		status.IgnoredInterfaces = ignoredInterfaces
+		status.OverriddenInterfaces = overriddenInterfaces
+	}
+	status.PolicyGeneration = policy.Generation
+	status.RolloutResume = policyconditions.RolloutResume(policy)
+}
+
 // Reconcile reads that state of the cluster for a NodeNetworkConfigurationPolicy object and makes changes based on the state read
 // and what is in the NodeNetworkConfigurationPolicy.Spec
 // Note:
@@ -160,6 +257,7 @@ func (r *ReconcileNodeNetworkConfigurationPolicy) Reconcile(request reconcile.Re
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			r.forgetSpecChange(request.Name)
 			return reconcile.Result{}, nil
 		}
 		reqLogger.Error(err, "Error retrieving policy")
@@ -167,9 +265,94 @@ func (r *ReconcileNodeNetworkConfigurationPolicy) Reconcile(request reconcile.Re
 		return reconcile.Result{}, err
 	}
 
-	policyconditions.Reset(r.client, request.NamespacedName)
+	if instance.DeletionTimestamp != nil {
+		return r.finalize(*instance)
+	}
+
+	if !hasEnactmentsFinalizer(*instance) {
+		err = addEnactmentsFinalizer(r.client, request.NamespacedName)
+		if err != nil {
+			reqLogger.Error(err, "Error adding enactments finalizer")
+		}
+	}
 
-	err = r.initializeEnactment(*instance)
+	bundles, err := bundlesOf(r.client, instance.Name)
+	if err != nil {
+		reqLogger.Error(err, "Error retrieving policy bundles")
+		return reconcile.Result{}, err
+	}
+	if len(bundles) > 0 {
+		reqLogger.Info("Policy is part of a bundle, it will be applied with the rest of the bundle", "bundle", bundles[0].Name)
+		return reconcile.Result{}, nil
+	}
+
+	// Desired state patches are rendered against the node current state,
+	// and the interfaces identified by MAC or PCI address named like at the
+	// node, from here on the policy desired state is the rendered one. The
+	// node invariant policies were rendered once by the webhook.
+	nodeInvariant := renderedByWebhook(*instance)
+	desiredState, renderErr := effectiveDesiredState(*instance)
+	if renderErr == nil {
+		instance.Spec.DesiredState = desiredState
+	}
+
+	// Settings dropped from the policy, like dummy interfaces or sysctls,
+	// are removed from the node, the global forwarding it sets is taken
+	// before so it's restored once dropped
+	previousForwarding := map[string]bool{}
+	if renderErr == nil && !instance.Spec.Audit {
+		previousForwarding = r.forwardingBeforePolicy(*instance, reqLogger)
+		instance.Spec.DesiredState = r.removeDroppedSettings(*instance, reqLogger)
+	}
+
+	// Base policies leave the interfaces other policies configure at the
+	// node to them
+	overriddenInterfaces, overrideErr := []nmstatev1alpha1.InterfaceOverride{}, error(nil)
+	if renderErr == nil && instance.Spec.Base {
+		overriddenInterfaces, overrideErr = excludeOverriddenInterfaces(r.client, instance)
+	}
+
+	// Ignored interfaces are left alone, they are not part of the enactment
+	// desired state either so they are not taken as drift
+	ignoredInterfaces, ignoreErr := []string{}, error(nil)
+	if renderErr == nil {
+		ignoredInterfaces, ignoreErr = excludeIgnoredInterfaces(instance)
+	}
+
+	// The periodic reconciles only apply the desired state again if the
+	// node has drifted from it
+	if interval := reconcileInterval(*instance); interval > 0 && renderErr == nil && ignoreErr == nil && overrideErr == nil && !instance.Spec.Audit {
+		applied, err := r.desiredStateStillApplied(*instance)
+		if err != nil {
+			reqLogger.Error(err, "failed checking if desired state is still applied, applying it again")
+		} else if applied {
+			reqLogger.Info(fmt.Sprintf("Desired state still applied, checking it again in %s", interval))
+			return reconcile.Result{RequeueAfter: interval}, nil
+		}
+	}
+
+	// The conditions are only reset for new policy generations, the rest
+	// of reconciles keep them until they are updated
+	if r.generationChanged(*instance) {
+		policyconditions.Reset(r.client, request.NamespacedName)
+	}
+
+	// Taken before the enactment is initialized for the policy generation
+	generationApplied := r.generationApplied(*instance)
+
+	// A resumed rollout goes on at the nodes that had not applied the
+	// policy generation, the ones already configured are not applied again
+	if generationApplied && r.rolloutResumed(*instance) {
+		reqLogger.Info("Policy rollout resumed, desired state already applied at the node")
+		err = r.recordRolloutResume(*instance)
+		if err != nil {
+			reqLogger.Error(err, "Error recording policy rollout resume")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	err = r.initializeEnactment(*instance, isStaged(*instance), ignoredInterfaces, overriddenInterfaces)
 	if err != nil {
 		log.Error(err, "Error initializing enactment")
 	}
@@ -192,23 +375,156 @@ func (r *ReconcileNodeNetworkConfigurationPolicy) Reconcile(request reconcile.Re
 		enactmentConditions.NotifyNodeSelectorNotMatching(unmatchingNodeLabels)
 		return reconcile.Result{}, nil
 	}
+	missing, err := nodeFileMissing(*instance)
+	if err != nil {
+		reqLogger.Error(err, "failed checking required node file")
+		enactmentConditions.NotifyNodeSelectorFailure(err)
+		return reconcile.Result{}, nil
+	}
+	if missing {
+		reqLogger.Info(fmt.Sprintf("Required node file missing, checking it again in %s", nodeFileRetryInterval))
+		enactmentConditions.NotifyNodeFileMissing(instance.Spec.RequireNodeFile)
+		return reconcile.Result{RequeueAfter: nodeFileRetryInterval}, nil
+	}
 
 	enactmentConditions.NotifyMatching()
 
-	enactmentConditions.NotifyProgressing()
-	nmstateOutput, err := nmstate.ApplyDesiredState(instance.Spec.DesiredState)
+	if instance.Spec.Audit {
+		reqLogger.Info("Policy is audited, skipping desired state apply")
+		return r.audit(*instance, renderErr, &enactmentConditions)
+	}
+
+	if policyconditions.IsQuarantined(*instance) {
+		reqLogger.Info("Policy is quarantined, skipping desired state apply")
+		enactmentConditions.NotifyQuarantined()
+		return reconcile.Result{}, nil
+	}
+
+	// The rollout only halts, or holds until the previous zones are done,
+	// the nodes that have not applied the policy generation yet, the ones
+	// already configured keep reconciling it
+	if !generationApplied {
+		failedNode, err := rolloutFailedNode(r.client, *instance)
+		if err != nil {
+			reqLogger.Error(err, "failed checking policy rollout failures")
+		} else if failedNode != "" {
+			reqLogger.Info("Policy rollout halted, skipping desired state apply", "failedNode", failedNode)
+			enactmentConditions.NotifyRolloutHalted(failedNode)
+			return reconcile.Result{}, nil
+		}
+		waitingFor, err := rolloutWaitingFor(r.client, *instance)
+		if err != nil {
+			reqLogger.Error(err, "failed checking policy rollout by zone")
+		} else if waitingFor != "" {
+			reqLogger.Info(fmt.Sprintf("Policy rollout by zone waiting for %s, checking it again in %s", waitingFor, zoneRolloutRetryInterval))
+			enactmentConditions.NotifyWaitingForRollout(waitingFor)
+			return reconcile.Result{RequeueAfter: zoneRolloutRetryInterval}, nil
+		}
+	}
+
+	if remaining := r.cooldownRemaining(*instance, generationApplied, time.Now()); remaining > 0 {
+		reqLogger.Info(fmt.Sprintf("Policy changed recently, waiting %s before applying desired state", remaining))
+		enactmentConditions.NotifyCoolingDown(remaining)
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
+	if render.IsInterfaceNotFound(renderErr) {
+		reqLogger.Info("Policy desired state interfaces not found at node, skipping desired state apply", "error", renderErr.Error())
+		enactmentConditions.NotifyInterfaceNotFound(renderErr)
+		return reconcile.Result{}, nil
+	}
+	if renderErr != nil {
+		enactmentConditions.NotifyFailedToConfigure(errors.Wrap(renderErr, "failed rendering desired state patch"), nmstatev1alpha1.FailureCodeApplyFailed)
+		return reconcile.Result{}, nil
+	}
+	if ignoreErr != nil {
+		enactmentConditions.NotifyFailedToConfigure(ignoreErr, nmstatev1alpha1.FailureCodeApplyFailed)
+		return reconcile.Result{}, nil
+	}
+	if overrideErr != nil {
+		enactmentConditions.NotifyFailedToConfigure(overrideErr, nmstatev1alpha1.FailureCodeApplyFailed)
+		return reconcile.Result{}, nil
+	}
+
+	modifiedProtectedInterfaces, err := nmstate.ModifiedProtectedInterfaces(instance.Spec.DesiredState, instance.Spec.ProtectedInterfaces)
+	if err != nil {
+		enactmentConditions.NotifyFailedToConfigure(errors.Wrap(err, "failed checking protected interfaces"), nmstatev1alpha1.FailureCodeApplyFailed)
+		return reconcile.Result{}, nil
+	}
+	if len(modifiedProtectedInterfaces) > 0 {
+		reqLogger.Info("Policy desired state modifies protected interfaces, skipping desired state apply", "protectedInterfaces", modifiedProtectedInterfaces)
+		enactmentConditions.NotifyProtectedInterfacesModified(modifiedProtectedInterfaces)
+		return reconcile.Result{}, nil
+	}
+
+	notAllowedInterfaces, err := allowedInterfaces.NotAllowedInterfaces(instance.Spec.DesiredState)
 	if err != nil {
-		errmsg := fmt.Errorf("error reconciling NodeNetworkConfigurationPolicy at desired state apply: %s, %v", nmstateOutput, err)
+		enactmentConditions.NotifyFailedToConfigure(errors.Wrap(err, "failed checking allowed interfaces"), nmstatev1alpha1.FailureCodeApplyFailed)
+		return reconcile.Result{}, nil
+	}
+	if len(notAllowedInterfaces) > 0 {
+		reqLogger.Info("Policy desired state modifies interfaces not allowed, skipping desired state apply", "notAllowedInterfaces", notAllowedInterfaces, "allowedInterfaces", allowedInterfaces.String())
+		enactmentConditions.NotifyInterfacesNotAllowed(notAllowedInterfaces, allowedInterfaces.String())
+		return reconcile.Result{}, nil
+	}
 
-		enactmentConditions.NotifyFailedToConfigure(errmsg)
-		reqLogger.Error(errmsg, fmt.Sprintf("Rolling back network configuration, manual intervention needed: %s", nmstateOutput))
+	unmanagedInterfaces, err := nmstate.UnmanagedInterfaces(instance.Spec.DesiredState)
+	if err != nil {
+		reqLogger.Error(err, "failed checking NetworkManager unmanaged devices, applying desired state anyway")
+	} else if len(unmanagedInterfaces) > 0 {
+		reqLogger.Info("Policy desired state configures devices unmanaged by NetworkManager, skipping desired state apply", "unmanagedInterfaces", unmanagedInterfaces)
+		enactmentConditions.NotifyDevicesUnmanaged(unmanagedInterfaces)
 		return reconcile.Result{}, nil
 	}
-	reqLogger.Info("nmstate", "output", nmstateOutput)
 
-	enactmentConditions.NotifySuccess()
+	err = nmstate.CheckConnectionNames(r.client, nodeName, instance.Spec.DesiredState)
+	if err != nil {
+		enactmentConditions.NotifyFailedToConfigure(errors.Wrap(err, "failed checking connection names"), nmstatev1alpha1.FailureCodeApplyFailed)
+		return reconcile.Result{}, nil
+	}
 
-	return reconcile.Result{}, nil
+	uptime, err := nmstate.Uptime()
+	if err != nil {
+		reqLogger.Error(err, "failed retrieving node uptime, not waiting for post boot delay")
+	} else if remaining := postBootDelayRemaining(*instance, uptime); remaining > 0 {
+		reqLogger.Info(fmt.Sprintf("Node booted %s ago, waiting %s before applying desired state", uptime, remaining))
+		enactmentConditions.NotifyWaitingPostBoot(remaining)
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
+	// Secrets are only resolved to apply the desired state, the enactment
+	// keeps the references. The node invariant policies have none.
+	resolvedDesiredState, secretValues, err := instance.Spec.DesiredState, []string(nil), error(nil)
+	if !nodeInvariant {
+		resolvedDesiredState, secretValues, err = r.resolveSecrets(instance.Spec.DesiredState)
+	}
+	if err != nil {
+		if isSecretNotFound(err) {
+			enactmentConditions.NotifySecretNotFound(err)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Staged policies are checked at every node but only applied once
+	// they are activated, so all of them apply it at the same time
+	if isStaged(*instance) {
+		reqLogger.Info("Policy is staged, skipping desired state apply until it's activated")
+		enactmentConditions.NotifyStaged(instance.Generation)
+		return reconcile.Result{}, nil
+	}
+
+	apply := policyApply{policy: *instance, enactmentConditions: enactmentConditions, previousForwarding: previousForwarding}
+	return r.applyPolicies("policy", instance.Name, []policyApply{apply}, resolvedDesiredState, secretValues, instance.Spec.ReadinessChecks, applyTimeout(*instance), reqLogger), nil
+}
+
+// postBootDelayRemaining returns how long the node has still to be up
+// to fulfill the policy post boot delay
+func postBootDelayRemaining(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, uptime time.Duration) time.Duration {
+	if policy.Spec.PostBootDelay == nil || uptime >= policy.Spec.PostBootDelay.Duration {
+		return 0
+	}
+	return policy.Spec.PostBootDelay.Duration - uptime
 }
 
 func desiredState(object runtime.Object) (nmstatev1alpha1.State, error) {
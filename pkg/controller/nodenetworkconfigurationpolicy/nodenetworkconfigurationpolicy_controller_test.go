@@ -1,6 +1,8 @@
 package nodenetworkconfigurationpolicy
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -15,17 +17,23 @@ var _ = Describe("NodeNetworkConfigurationPolicy controller predicates", func()
 	type predicateCase struct {
 		GenerationOld   int64
 		GenerationNew   int64
+		AnnotationsOld  map[string]string
+		AnnotationsNew  map[string]string
+		DeletionNew     *metav1.Time
 		ReconcileCreate bool
 		ReconcileUpdate bool
 	}
 	DescribeTable("testing predicates",
 		func(c predicateCase) {
 			oldNodeNetworkConfigurationPolicyMeta := metav1.ObjectMeta{
-				Generation: c.GenerationOld,
+				Generation:  c.GenerationOld,
+				Annotations: c.AnnotationsOld,
 			}
 
 			newNodeNetworkConfigurationPolicyMeta := metav1.ObjectMeta{
-				Generation: c.GenerationNew,
+				Generation:        c.GenerationNew,
+				Annotations:       c.AnnotationsNew,
+				DeletionTimestamp: c.DeletionNew,
 			}
 
 			nodeNetworkConfigurationPolicy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
@@ -64,5 +72,90 @@ var _ = Describe("NodeNetworkConfigurationPolicy controller predicates", func()
 				ReconcileCreate: true,
 				ReconcileUpdate: true,
 			}),
+		Entry("generation remains the same but quarantine resume annotation is different",
+			predicateCase{
+				GenerationOld: 1,
+				GenerationNew: 1,
+				AnnotationsNew: map[string]string{
+					nmstatev1alpha1.NodeNetworkConfigurationPolicyQuarantineResumeAnnotation: "1",
+				},
+				ReconcileCreate: true,
+				ReconcileUpdate: true,
+			}),
+		Entry("generation remains the same but deletion has started",
+			predicateCase{
+				GenerationOld:   1,
+				GenerationNew:   1,
+				DeletionNew:     &metav1.Time{Time: time.Now()},
+				ReconcileCreate: true,
+				ReconcileUpdate: true,
+			}),
+		Entry("generation remains the same and other annotation is different",
+			predicateCase{
+				GenerationOld: 1,
+				GenerationNew: 1,
+				AnnotationsNew: map[string]string{
+					"foo": "bar",
+				},
+				ReconcileCreate: true,
+				ReconcileUpdate: false,
+			}),
+	)
+})
+
+var _ = Describe("NodeNetworkConfigurationPolicy post boot delay", func() {
+	DescribeTable("remaining time before applying",
+		func(postBootDelay *metav1.Duration, uptime time.Duration, expected time.Duration) {
+			policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+				Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+					PostBootDelay: postBootDelay,
+				},
+			}
+			Expect(postBootDelayRemaining(policy, uptime)).To(Equal(expected))
+		},
+		Entry("without post boot delay", nil, 5*time.Second, time.Duration(0)),
+		Entry("when node is up less than the delay", &metav1.Duration{Duration: 2 * time.Minute}, 30*time.Second, 90*time.Second),
+		Entry("when node is up more than the delay", &metav1.Duration{Duration: 2 * time.Minute}, 10*time.Minute, time.Duration(0)),
+	)
+})
+
+var _ = Describe("NodeNetworkConfigurationPolicy apply cooldown", func() {
+	var (
+		reconciler ReconcileNodeNetworkConfigurationPolicy
+		policy     nmstatev1alpha1.NodeNetworkConfigurationPolicy
+		start      time.Time
 	)
+	BeforeEach(func() {
+		applyCooldown = 10 * time.Second
+		reconciler = ReconcileNodeNetworkConfigurationPolicy{}
+		policy = nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy1", Generation: 1},
+		}
+		start = time.Now()
+	})
+	AfterEach(func() {
+		applyCooldown = 0
+	})
+	It("should wait for the policy to stop changing", func() {
+		Expect(reconciler.cooldownRemaining(policy, false, start)).To(Equal(10 * time.Second))
+		Expect(reconciler.cooldownRemaining(policy, false, start.Add(4*time.Second))).To(Equal(6 * time.Second))
+
+		policy.Generation = 2
+		Expect(reconciler.cooldownRemaining(policy, false, start.Add(5*time.Second))).To(Equal(10 * time.Second))
+		Expect(reconciler.cooldownRemaining(policy, false, start.Add(15*time.Second))).To(Equal(time.Duration(0)))
+	})
+	It("should not wait for the generations already applied and forget them", func() {
+		Expect(reconciler.cooldownRemaining(policy, false, start)).To(Equal(10 * time.Second))
+		Expect(reconciler.cooldownRemaining(policy, true, start.Add(4*time.Second))).To(Equal(time.Duration(0)))
+		Expect(reconciler.specChanges).ToNot(HaveKey(policy.Name))
+	})
+	It("should forget the spec changes of deleted policies", func() {
+		Expect(reconciler.cooldownRemaining(policy, false, start)).To(Equal(10 * time.Second))
+		reconciler.forgetSpecChange(policy.Name)
+		Expect(reconciler.specChanges).To(BeEmpty())
+	})
+	It("should not wait when disabled", func() {
+		applyCooldown = 0
+		Expect(reconciler.cooldownRemaining(policy, false, start)).To(Equal(time.Duration(0)))
+	})
 })
@@ -0,0 +1,512 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/policyconditions"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/selectors"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+var bundleLog = log.WithName("bundle")
+
+// AddBundle creates a new NodeNetworkConfigurationPolicyBundle Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func AddBundle(mgr manager.Manager) error {
+	return addBundle(mgr, newBundleReconciler(mgr))
+}
+
+// newBundleReconciler returns a new reconcile.Reconciler
+func newBundleReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileNodeNetworkConfigurationPolicyBundle{
+		ReconcileNodeNetworkConfigurationPolicy{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor("nmstate-handler")},
+	}
+}
+
+// addBundle adds a new Controller to mgr with r as the reconcile.Reconciler
+func addBundle(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("nodenetworkconfigurationpolicybundle-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource NodeNetworkConfigurationPolicyBundle
+	err = c.Watch(&source.Kind{Type: &nmstatev1alpha1.NodeNetworkConfigurationPolicyBundle{}}, &handler.EnqueueRequestForObject{}, watchPredicate)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the bundles policies
+	err = c.Watch(&source.Kind{Type: &nmstatev1alpha1.NodeNetworkConfigurationPolicy{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: bundleRequestsForPolicy(mgr.GetClient()),
+	}, watchPredicate)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the secrets referenced by the bundles policies
+	secrets, err := secretsSource(mgr)
+	if err != nil {
+		return err
+	}
+	err = c.Watch(secrets, &handler.EnqueueRequestsFromMapFunc{ToRequests: bundleRequestsForSecret(mgr.GetClient())})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func bundlesOf(cli client.Client, policyName string) ([]nmstatev1alpha1.NodeNetworkConfigurationPolicyBundle, error) {
+	bundles := nmstatev1alpha1.NodeNetworkConfigurationPolicyBundleList{}
+	err := cli.List(context.TODO(), &bundles)
+	if err != nil {
+		return nil, err
+	}
+	policyBundles := []nmstatev1alpha1.NodeNetworkConfigurationPolicyBundle{}
+	for _, bundle := range bundles.Items {
+		for _, bundlePolicyName := range bundle.Spec.Policies {
+			if bundlePolicyName == policyName {
+				policyBundles = append(policyBundles, bundle)
+				break
+			}
+		}
+	}
+	return policyBundles, nil
+}
+
+func bundleRequestsForPolicy(cli client.Client) handler.ToRequestsFunc {
+	return func(object handler.MapObject) []reconcile.Request {
+		bundles, err := bundlesOf(cli, object.Meta.GetName())
+		if err != nil {
+			bundleLog.Error(err, "failed listing policy bundles", "policy", object.Meta.GetName())
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for _, bundle := range bundles {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
+		}
+		return requests
+	}
+}
+
+// blank assignment to verify that ReconcileNodeNetworkConfigurationPolicyBundle implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileNodeNetworkConfigurationPolicyBundle{}
+
+// ReconcileNodeNetworkConfigurationPolicyBundle reconciles a NodeNetworkConfigurationPolicyBundle object,
+// it embeds the policy reconciler to initialize the bundle policies enactments
+type ReconcileNodeNetworkConfigurationPolicyBundle struct {
+	ReconcileNodeNetworkConfigurationPolicy
+}
+
+type bundlePolicy struct {
+	policyApply
+	ignoredInterfaces    []string
+	overriddenInterfaces []nmstatev1alpha1.InterfaceOverride
+	ignoreErr            error
+	overrideErr          error
+	// generationApplied is taken before the enactment is initialized for
+	// the policy generation
+	generationApplied bool
+}
+
+// Reconcile applies the desired state of all the bundle policies matching
+// the node in a single nmstate transaction, so all of them succeed or all
+// of them are rolled back together.
+func (r *ReconcileNodeNetworkConfigurationPolicyBundle) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := bundleLog.WithValues("Request.Name", request.Name)
+	reqLogger.Info("Reconciling NodeNetworkConfigurationPolicyBundle")
+
+	bundle := &nmstatev1alpha1.NodeNetworkConfigurationPolicyBundle{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, bundle)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Error retrieving bundle")
+		return reconcile.Result{}, err
+	}
+
+	defer policyconditions.UpdateBundle(r.client, request.NamespacedName)
+
+	policies := []nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+	for _, policyName := range bundle.Spec.Policies {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: policyName}, &policy)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				reqLogger.Info("Bundle policy not found, not applying the bundle", "policy", policyName)
+				return reconcile.Result{}, nil
+			}
+			return reconcile.Result{}, err
+		}
+		if policy.DeletionTimestamp != nil {
+			reqLogger.Info("Bundle policy is being deleted, not applying the bundle", "policy", policyName)
+			return reconcile.Result{}, nil
+		}
+		policies = append(policies, policy)
+	}
+
+	// The bundle policies desired states are handled like the ones of the
+	// policies on their own before they are merged
+	bundlePolicies := []bundlePolicy{}
+	for _, policy := range policies {
+		bundlePolicies = append(bundlePolicies, r.prepareBundlePolicy(policy, reqLogger))
+	}
+
+	// The bundle policies are applied together, a staged one holds all of
+	// them until it's activated
+	stagedPolicy := stagedBundlePolicy(policies)
+
+	// The periodic reconciles only apply the bundle desired state again if
+	// the node has drifted from any of its policies
+	if interval, applied := r.bundleStillApplied(bundlePolicies, reqLogger); applied {
+		reqLogger.Info(fmt.Sprintf("Bundle desired state still applied, checking it again in %s", interval))
+		return reconcile.Result{RequeueAfter: interval}, nil
+	}
+
+	// Like on their own, a resumed rollout is not applied again at the node
+	// once every bundle policy generation was applied at it
+	if resumed := r.bundleRolloutResumed(bundlePolicies); resumed {
+		reqLogger.Info("Bundle policies rollout resumed, desired state already applied at the node")
+		for _, bundlePolicy := range bundlePolicies {
+			err = r.recordRolloutResume(bundlePolicy.policy)
+			if err != nil {
+				reqLogger.Error(err, "Error recording policy rollout resume", "policy", bundlePolicy.policy.Name)
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	matchingPolicies := []bundlePolicy{}
+	// Bundle policies missing their required node file are left out of
+	// the bundle until it's created
+	result := reconcile.Result{}
+	for _, bundlePolicy := range bundlePolicies {
+		policy := bundlePolicy.policy
+		policyKey := types.NamespacedName{Name: policy.Name}
+		if r.generationChanged(policy) {
+			policyconditions.Reset(r.client, policyKey)
+		}
+		defer policyconditions.Update(r.client, policyKey)
+
+		bundlePolicy.generationApplied = r.generationApplied(policy)
+		err = r.initializeEnactment(policy, stagedPolicy != nil, bundlePolicy.ignoredInterfaces, bundlePolicy.overriddenInterfaces)
+		if err != nil {
+			reqLogger.Error(err, "Error initializing enactment", "policy", policy.Name)
+		}
+		enactmentConditions := enactmentconditions.New(r.client, nmstatev1alpha1.EnactmentKey(nodeName, policy.Name))
+
+		policySelectors := selectors.NewFromPolicy(r.client, policy)
+		unmatchingNodeLabels, err := policySelectors.UnmatchedNodeLabels(nodeName)
+		if err != nil {
+			reqLogger.Error(err, "failed checking node selectors", "policy", policy.Name)
+			enactmentConditions.NotifyNodeSelectorFailure(err)
+			continue
+		}
+		if len(unmatchingNodeLabels) > 0 {
+			enactmentConditions.NotifyNodeSelectorNotMatching(unmatchingNodeLabels)
+			continue
+		}
+		missing, err := nodeFileMissing(policy)
+		if err != nil {
+			reqLogger.Error(err, "failed checking required node file", "policy", policy.Name)
+			enactmentConditions.NotifyNodeSelectorFailure(err)
+			continue
+		}
+		if missing {
+			enactmentConditions.NotifyNodeFileMissing(policy.Spec.RequireNodeFile)
+			result.RequeueAfter = nodeFileRetryInterval
+			continue
+		}
+		enactmentConditions.NotifyMatching()
+		bundlePolicy.enactmentConditions = enactmentConditions
+		matchingPolicies = append(matchingPolicies, bundlePolicy)
+	}
+
+	if len(matchingPolicies) == 0 {
+		reqLogger.Info("No bundle policy matches the node")
+		return result, nil
+	}
+
+	for _, matchingPolicy := range matchingPolicies {
+		if policyconditions.IsQuarantined(matchingPolicy.policy) {
+			reqLogger.Info("Bundle policy is quarantined, skipping desired state apply", "policy", matchingPolicy.policy.Name)
+			for _, p := range matchingPolicies {
+				p.enactmentConditions.NotifyQuarantined()
+			}
+			return reconcile.Result{}, nil
+		}
+		// Like on their own the rollout only halts, or holds by zone, the
+		// nodes that have not applied the policy generation yet
+		if matchingPolicy.generationApplied {
+			continue
+		}
+		failedNode, err := rolloutFailedNode(r.client, matchingPolicy.policy)
+		if err != nil {
+			reqLogger.Error(err, "failed checking bundle policy rollout failures", "policy", matchingPolicy.policy.Name)
+		} else if failedNode != "" {
+			reqLogger.Info("Bundle policy rollout halted, skipping desired state apply", "policy", matchingPolicy.policy.Name, "failedNode", failedNode)
+			for _, p := range matchingPolicies {
+				p.enactmentConditions.NotifyRolloutHalted(failedNode)
+			}
+			return reconcile.Result{}, nil
+		}
+		waitingFor, err := rolloutWaitingFor(r.client, matchingPolicy.policy)
+		if err != nil {
+			reqLogger.Error(err, "failed checking bundle policy rollout by zone", "policy", matchingPolicy.policy.Name)
+		} else if waitingFor != "" {
+			reqLogger.Info(fmt.Sprintf("Bundle policy rollout by zone waiting for %s, checking it again in %s", waitingFor, zoneRolloutRetryInterval), "policy", matchingPolicy.policy.Name)
+			for _, p := range matchingPolicies {
+				p.enactmentConditions.NotifyWaitingForRollout(waitingFor)
+			}
+			return reconcile.Result{RequeueAfter: zoneRolloutRetryInterval}, nil
+		}
+	}
+
+	// The bundle waits for the cooldown of all its policies
+	cooldown := time.Duration(0)
+	now := time.Now()
+	for _, matchingPolicy := range matchingPolicies {
+		if remaining := r.cooldownRemaining(matchingPolicy.policy, matchingPolicy.generationApplied, now); remaining > cooldown {
+			cooldown = remaining
+		}
+	}
+	if cooldown > 0 {
+		reqLogger.Info(fmt.Sprintf("Bundle policies changed recently, waiting %s before applying desired state", cooldown))
+		for _, matchingPolicy := range matchingPolicies {
+			matchingPolicy.enactmentConditions.NotifyCoolingDown(cooldown)
+		}
+		return reconcile.Result{RequeueAfter: cooldown}, nil
+	}
+
+	for _, matchingPolicy := range matchingPolicies {
+		if matchingPolicy.policy.Spec.DesiredStatePatch != nil {
+			errmsg := fmt.Errorf("bundle %s policy %s has a desired state patch, it cannot be merged with the rest of policies", bundle.Name, matchingPolicy.policy.Name)
+			for _, p := range matchingPolicies {
+				p.enactmentConditions.NotifyFailedToConfigure(errmsg, nmstatev1alpha1.FailureCodeApplyFailed)
+			}
+			return reconcile.Result{}, nil
+		}
+		if matchingPolicy.ignoreErr != nil {
+			errmsg := fmt.Errorf("bundle %s policy %s %v", bundle.Name, matchingPolicy.policy.Name, matchingPolicy.ignoreErr)
+			for _, p := range matchingPolicies {
+				p.enactmentConditions.NotifyFailedToConfigure(errmsg, nmstatev1alpha1.FailureCodeApplyFailed)
+			}
+			return reconcile.Result{}, nil
+		}
+		if matchingPolicy.overrideErr != nil {
+			errmsg := fmt.Errorf("bundle %s policy %s %v", bundle.Name, matchingPolicy.policy.Name, matchingPolicy.overrideErr)
+			for _, p := range matchingPolicies {
+				p.enactmentConditions.NotifyFailedToConfigure(errmsg, nmstatev1alpha1.FailureCodeApplyFailed)
+			}
+			return reconcile.Result{}, nil
+		}
+		if matchingPolicy.policy.Spec.Audit {
+			errmsg := fmt.Errorf("bundle %s policy %s is an audit policy, it cannot be applied with the rest of policies", bundle.Name, matchingPolicy.policy.Name)
+			for _, p := range matchingPolicies {
+				p.enactmentConditions.NotifyFailedToConfigure(errmsg, nmstatev1alpha1.FailureCodeApplyFailed)
+			}
+			return reconcile.Result{}, nil
+		}
+	}
+
+	desiredStates := []nmstatev1alpha1.State{}
+	protectedInterfaces := []string{}
+	readinessChecks := []nmstatev1alpha1.ReadinessCheck{}
+	var postBootDelay, bundleApplyTimeout time.Duration
+	for _, matchingPolicy := range matchingPolicies {
+		desiredStates = append(desiredStates, matchingPolicy.policy.Spec.DesiredState)
+		protectedInterfaces = append(protectedInterfaces, matchingPolicy.policy.Spec.ProtectedInterfaces...)
+		readinessChecks = append(readinessChecks, matchingPolicy.policy.Spec.ReadinessChecks...)
+		if matchingPolicy.policy.Spec.PostBootDelay != nil && matchingPolicy.policy.Spec.PostBootDelay.Duration > postBootDelay {
+			postBootDelay = matchingPolicy.policy.Spec.PostBootDelay.Duration
+		}
+		if timeout := applyTimeout(matchingPolicy.policy); timeout > bundleApplyTimeout {
+			bundleApplyTimeout = timeout
+		}
+	}
+
+	desiredState, err := nmstate.MergeDesiredStates(desiredStates)
+	if err != nil {
+		errmsg := fmt.Errorf("error merging bundle %s desired states: %v", bundle.Name, err)
+		for _, matchingPolicy := range matchingPolicies {
+			matchingPolicy.enactmentConditions.NotifyFailedToConfigure(errmsg, nmstatev1alpha1.FailureCodeApplyFailed)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	desiredState, err = nmstate.ResolveInterfaceMatches(desiredState)
+	if err != nil {
+		reqLogger.Info("Bundle desired state interfaces not found at node, skipping desired state apply", "error", err.Error())
+		for _, matchingPolicy := range matchingPolicies {
+			if render.IsInterfaceNotFound(err) {
+				matchingPolicy.enactmentConditions.NotifyInterfaceNotFound(err)
+			} else {
+				matchingPolicy.enactmentConditions.NotifyFailedToConfigure(fmt.Errorf("error resolving bundle %s interfaces: %v", bundle.Name, err), nmstatev1alpha1.FailureCodeApplyFailed)
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	modifiedProtectedInterfaces, err := nmstate.ModifiedProtectedInterfaces(desiredState, protectedInterfaces)
+	if err != nil || len(modifiedProtectedInterfaces) > 0 {
+		reqLogger.Info("Bundle desired state modifies protected interfaces, skipping desired state apply", "protectedInterfaces", modifiedProtectedInterfaces)
+		for _, matchingPolicy := range matchingPolicies {
+			if err != nil {
+				matchingPolicy.enactmentConditions.NotifyFailedToConfigure(fmt.Errorf("failed checking protected interfaces: %v", err), nmstatev1alpha1.FailureCodeApplyFailed)
+			} else {
+				matchingPolicy.enactmentConditions.NotifyProtectedInterfacesModified(modifiedProtectedInterfaces)
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	notAllowedInterfaces, err := allowedInterfaces.NotAllowedInterfaces(desiredState)
+	if err != nil || len(notAllowedInterfaces) > 0 {
+		reqLogger.Info("Bundle desired state modifies interfaces not allowed, skipping desired state apply", "notAllowedInterfaces", notAllowedInterfaces, "allowedInterfaces", allowedInterfaces.String())
+		for _, matchingPolicy := range matchingPolicies {
+			if err != nil {
+				matchingPolicy.enactmentConditions.NotifyFailedToConfigure(fmt.Errorf("failed checking allowed interfaces: %v", err), nmstatev1alpha1.FailureCodeApplyFailed)
+			} else {
+				matchingPolicy.enactmentConditions.NotifyInterfacesNotAllowed(notAllowedInterfaces, allowedInterfaces.String())
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	unmanagedInterfaces, err := nmstate.UnmanagedInterfaces(desiredState)
+	if err != nil {
+		reqLogger.Error(err, "failed checking NetworkManager unmanaged devices, applying desired state anyway")
+	} else if len(unmanagedInterfaces) > 0 {
+		reqLogger.Info("Bundle desired state configures devices unmanaged by NetworkManager, skipping desired state apply", "unmanagedInterfaces", unmanagedInterfaces)
+		for _, matchingPolicy := range matchingPolicies {
+			matchingPolicy.enactmentConditions.NotifyDevicesUnmanaged(unmanagedInterfaces)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	err = nmstate.CheckConnectionNames(r.client, nodeName, desiredState)
+	if err != nil {
+		errmsg := fmt.Errorf("failed checking connection names: %v", err)
+		for _, matchingPolicy := range matchingPolicies {
+			matchingPolicy.enactmentConditions.NotifyFailedToConfigure(errmsg, nmstatev1alpha1.FailureCodeApplyFailed)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if postBootDelay > 0 {
+		uptime, err := nmstate.Uptime()
+		if err != nil {
+			reqLogger.Error(err, "failed retrieving node uptime, not waiting for post boot delay")
+		} else if uptime < postBootDelay {
+			remaining := postBootDelay - uptime
+			for _, matchingPolicy := range matchingPolicies {
+				matchingPolicy.enactmentConditions.NotifyWaitingPostBoot(remaining)
+			}
+			return reconcile.Result{RequeueAfter: remaining}, nil
+		}
+	}
+
+	resolvedDesiredState, secretValues, err := r.resolveSecrets(desiredState)
+	if err != nil {
+		if isSecretNotFound(err) {
+			for _, matchingPolicy := range matchingPolicies {
+				matchingPolicy.enactmentConditions.NotifySecretNotFound(err)
+			}
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if stagedPolicy != nil {
+		reqLogger.Info("Bundle policy is staged, skipping desired state apply until it's activated", "policy", stagedPolicy.Name)
+		for _, matchingPolicy := range matchingPolicies {
+			matchingPolicy.enactmentConditions.NotifyBundleStaged(stagedPolicy.Name, stagedPolicy.Generation)
+		}
+		return result, nil
+	}
+
+	applies := []policyApply{}
+	for _, matchingPolicy := range matchingPolicies {
+		applies = append(applies, matchingPolicy.policyApply)
+	}
+	applyResult := r.applyPolicies("bundle", bundle.Name, applies, resolvedDesiredState, secretValues, readinessChecks, bundleApplyTimeout, reqLogger)
+	applyResult.RequeueAfter = shortestInterval(applyResult.RequeueAfter, result.RequeueAfter)
+	return applyResult, nil
+}
+
+// prepareBundlePolicy handles the desired state of a bundle policy like the
+// one of a policy on its own: the settings dropped from it are removed from
+// the node, and the interfaces overridden by other policies, for base
+// policies, and the ignored ones are left out of it
+func (r *ReconcileNodeNetworkConfigurationPolicyBundle) prepareBundlePolicy(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, logger logr.Logger) bundlePolicy {
+	bundlePolicy := bundlePolicy{policyApply: policyApply{previousForwarding: map[string]bool{}}}
+
+	// The interfaces base policies identify by MAC or PCI address are
+	// resolved first to compare them. If they are not found the bundle
+	// desired state fails resolving them too.
+	if policy.Spec.Base {
+		if resolvedState, err := nmstate.ResolveInterfaceMatches(policy.Spec.DesiredState); err == nil {
+			policy.Spec.DesiredState = resolvedState
+		}
+	}
+
+	if !policy.Spec.Audit {
+		bundlePolicy.previousForwarding = r.forwardingBeforePolicy(policy, logger)
+		policy.Spec.DesiredState = r.removeDroppedSettings(policy, logger)
+	}
+
+	bundlePolicy.overriddenInterfaces = []nmstatev1alpha1.InterfaceOverride{}
+	if policy.Spec.Base {
+		bundlePolicy.overriddenInterfaces, bundlePolicy.overrideErr = excludeOverriddenInterfaces(r.client, &policy)
+	}
+	bundlePolicy.ignoredInterfaces, bundlePolicy.ignoreErr = excludeIgnoredInterfaces(&policy)
+	bundlePolicy.policy = policy
+	return bundlePolicy
+}
+
+// bundleStillApplied returns the shortest reconcile interval of the bundle
+// policies and true if they have one and all of them are still applied at
+// the node
+func (r *ReconcileNodeNetworkConfigurationPolicyBundle) bundleStillApplied(bundlePolicies []bundlePolicy, logger logr.Logger) (time.Duration, bool) {
+	interval := time.Duration(0)
+	for _, bundlePolicy := range bundlePolicies {
+		interval = shortestInterval(interval, reconcileInterval(bundlePolicy.policy))
+	}
+	if interval == 0 {
+		return 0, false
+	}
+	for _, bundlePolicy := range bundlePolicies {
+		if bundlePolicy.ignoreErr != nil || bundlePolicy.overrideErr != nil || bundlePolicy.policy.Spec.Audit {
+			return 0, false
+		}
+		applied, err := r.desiredStateStillApplied(bundlePolicy.policy)
+		if err != nil {
+			logger.Error(err, "failed checking if desired state is still applied, applying it again", "policy", bundlePolicy.policy.Name)
+			return 0, false
+		}
+		if !applied {
+			return 0, false
+		}
+	}
+	return interval, true
+}
@@ -0,0 +1,69 @@
+package policyconditions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// UpdateBundle calculates the bundle conditions from the conditions of
+// its policies, it's degraded if any of them is and available when all
+// of them are.
+func UpdateBundle(cli client.Client, bundleKey types.NamespacedName) error {
+	logger := log.WithValues("bundle", bundleKey.Name)
+	return retry.RetryOnConflict(conflictBackoff, func() error {
+		bundle := &nmstatev1alpha1.NodeNetworkConfigurationPolicyBundle{}
+		err := cli.Get(context.TODO(), bundleKey, bundle)
+		if err != nil {
+			return errors.Wrap(err, "getting bundle failed")
+		}
+
+		missing, degraded, progressing := []string{}, []string{}, []string{}
+		for _, policyName := range bundle.Spec.Policies {
+			policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+			err = cli.Get(context.TODO(), types.NamespacedName{Name: policyName}, &policy)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					missing = append(missing, policyName)
+					continue
+				}
+				return errors.Wrap(err, "getting bundle policy failed")
+			}
+			if policy.Status.Conditions.IsDegraded() {
+				degraded = append(degraded, policyName)
+			} else if !policy.Status.Conditions.IsAvailable() {
+				progressing = append(progressing, policyName)
+			}
+		}
+
+		if len(missing) > 0 {
+			setPolicyFailedToConfigure(&bundle.Status.Conditions, fmt.Sprintf("policies not found: %s", strings.Join(missing, ", ")))
+		} else if len(degraded) > 0 {
+			setPolicyFailedToConfigure(&bundle.Status.Conditions, fmt.Sprintf("policies failed to configure: %s", strings.Join(degraded, ", ")))
+		} else if len(progressing) > 0 {
+			setPolicyProgressing(&bundle.Status.Conditions, fmt.Sprintf("Bundle is progressing %d/%d policies finished", len(bundle.Spec.Policies)-len(progressing), len(bundle.Spec.Policies)))
+		} else {
+			setPolicySuccess(&bundle.Status.Conditions, fmt.Sprintf("%d/%d policies successfully configured", len(bundle.Spec.Policies), len(bundle.Spec.Policies)))
+		}
+
+		err = cli.Status().Update(context.TODO(), bundle)
+		if err != nil {
+			if apierrors.IsConflict(err) {
+				logger.Info("conflict updating bundle conditions, retrying")
+			} else {
+				logger.Error(err, "failed to update bundle conditions")
+			}
+			return err
+		}
+		return nil
+	})
+}
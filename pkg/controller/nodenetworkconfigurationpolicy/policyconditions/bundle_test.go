@@ -0,0 +1,97 @@
+package policyconditions
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Bundle Conditions", func() {
+	type bundleCase struct {
+		Policies []nmstatev1alpha1.NodeNetworkConfigurationPolicy
+		Bundle   nmstatev1alpha1.NodeNetworkConfigurationPolicyBundle
+	}
+
+	namedPolicy := func(name string, conditionsSetter func(*nmstatev1alpha1.ConditionList, string)) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		policy := p(conditionsSetter, "")
+		policy.Name = name
+		return policy
+	}
+
+	b := func(conditionsSetter func(*nmstatev1alpha1.ConditionList, string), message string) nmstatev1alpha1.NodeNetworkConfigurationPolicyBundle {
+		conditions := nmstatev1alpha1.ConditionList{}
+		conditionsSetter(&conditions, message)
+		return nmstatev1alpha1.NodeNetworkConfigurationPolicyBundle{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "bundle1",
+			},
+			Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicyBundleSpec{
+				Policies: []string{"bond", "vlans"},
+			},
+			Status: nmstatev1alpha1.NodeNetworkConfigurationPolicyBundleStatus{
+				Conditions: conditions,
+			},
+		}
+	}
+
+	DescribeTable("the bundle overall condition",
+		func(c bundleCase) {
+			s := scheme.Scheme
+			s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+				&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+				&nmstatev1alpha1.NodeNetworkConfigurationPolicyBundle{},
+			)
+			updatedBundle := c.Bundle.DeepCopy()
+			updatedBundle.Status.Conditions = nmstatev1alpha1.ConditionList{}
+			objs := []runtime.Object{updatedBundle}
+			for i, _ := range c.Policies {
+				objs = append(objs, &c.Policies[i])
+			}
+
+			client := fake.NewFakeClientWithScheme(s, objs...)
+			key := types.NamespacedName{Name: updatedBundle.Name}
+			err := UpdateBundle(client, key)
+			Expect(err).ToNot(HaveOccurred())
+			err = client.Get(context.TODO(), key, updatedBundle)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cleanTimestamps(updatedBundle.Status.Conditions)).To(ConsistOf(cleanTimestamps(c.Bundle.Status.Conditions)))
+		},
+		Entry("when all policies are available then bundle is available", bundleCase{
+			Policies: []nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+				namedPolicy("bond", setPolicySuccess),
+				namedPolicy("vlans", setPolicySuccess),
+			},
+			Bundle: b(setPolicySuccess, "2/2 policies successfully configured"),
+		}),
+		Entry("when a policy is progressing then bundle is progressing", bundleCase{
+			Policies: []nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+				namedPolicy("bond", setPolicySuccess),
+				namedPolicy("vlans", setPolicyProgressing),
+			},
+			Bundle: b(setPolicyProgressing, "Bundle is progressing 1/2 policies finished"),
+		}),
+		Entry("when a policy is degraded then bundle is degraded", bundleCase{
+			Policies: []nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+				namedPolicy("bond", setPolicyFailedToConfigure),
+				namedPolicy("vlans", setPolicyProgressing),
+			},
+			Bundle: b(setPolicyFailedToConfigure, "policies failed to configure: bond"),
+		}),
+		Entry("when a policy is missing then bundle is degraded", bundleCase{
+			Policies: []nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+				namedPolicy("bond", setPolicySuccess),
+			},
+			Bundle: b(setPolicyFailedToConfigure, "policies not found: vlans"),
+		}),
+	)
+})
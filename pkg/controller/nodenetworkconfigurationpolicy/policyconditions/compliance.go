@@ -0,0 +1,35 @@
+package policyconditions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// compliance summarizes the compliance reported by the enactments of an
+// audit policy, listing the non compliant nodes
+func compliance(enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList) string {
+	compliant, nonCompliant := 0, []string{}
+	for _, enactment := range enactments.Items {
+		condition := enactment.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionCompliant)
+		if condition == nil {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			compliant++
+		} else {
+			nonCompliant = append(nonCompliant, enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel])
+		}
+	}
+
+	summary := fmt.Sprintf("%d/%d nodes compliant", compliant, compliant+len(nonCompliant))
+	if len(nonCompliant) > 0 {
+		sort.Strings(nonCompliant)
+		summary = fmt.Sprintf("%s, non compliant: %s", summary, strings.Join(nonCompliant, ", "))
+	}
+	return summary
+}
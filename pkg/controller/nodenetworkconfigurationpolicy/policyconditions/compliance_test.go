@@ -0,0 +1,53 @@
+package policyconditions
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("Policy compliance", func() {
+	audited := func(drift ...string) func(*nmstatev1alpha1.ConditionList, string) {
+		return func(conditions *nmstatev1alpha1.ConditionList, _ string) {
+			enactmentconditions.SetAudited(conditions, drift)
+		}
+	}
+	auditedEnactment := func(node string, drift ...string) *nmstatev1alpha1.NodeNetworkConfigurationEnactment {
+		enactment := e(node, "policy1", enactmentconditions.SetMatching, audited(drift...))
+		enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel] = node
+		return &enactment
+	}
+
+	It("should summarize the compliance of the audit policy nodes", func() {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+		)
+		policy := p(setPolicyProgressing, "")
+		policy.Spec.Audit = true
+		nodes := newReadyNodes(3)
+		cli := fake.NewFakeClientWithScheme(s, &policy, &nodes[0], &nodes[1], &nodes[2],
+			auditedEnactment("node1"),
+			auditedEnactment("node2", "interfaces.eth1.mtu"),
+			auditedEnactment("node3"),
+		)
+
+		key := types.NamespacedName{Name: policy.Name}
+		Expect(Update(cli, key)).To(Succeed())
+		Expect(cli.Get(context.TODO(), key, &policy)).To(Succeed())
+		Expect(policy.Status.Compliance).To(Equal("2/3 nodes compliant, non compliant: node2"))
+		available := policy.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable)
+		Expect(available).ToNot(BeNil())
+		Expect(available.Message).To(Equal("3/3 nodes audited"))
+	})
+})
@@ -3,12 +3,16 @@ package policyconditions
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
@@ -19,6 +23,18 @@ import (
 
 var (
 	log = logf.Log.WithName("policyconditions")
+
+	// All the handlers update the same policy status, on conflict they
+	// back off exponentially with jitter so they don't retry at the same
+	// time. The steps bound the total retry time to a few seconds, after
+	// that the conflict error is returned.
+	conflictBackoff = wait.Backoff{
+		Steps:    8,
+		Duration: 10 * time.Millisecond,
+		Factor:   2.0,
+		Jitter:   0.5,
+		Cap:      1 * time.Second,
+	}
 )
 
 func setPolicyProgressing(conditions *nmstatev1alpha1.ConditionList, message string) {
@@ -85,17 +101,129 @@ func setPolicyFailedToConfigure(conditions *nmstatev1alpha1.ConditionList, messa
 	)
 }
 
+func setPolicyFewerNodesThanExpected(conditions *nmstatev1alpha1.ConditionList, message string) {
+	log.Info("setPolicyFewerNodesThanExpected")
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDegraded,
+		corev1.ConditionTrue,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionFewerNodesThanExpected,
+		message,
+	)
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable,
+		corev1.ConditionTrue,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionFewerNodesThanExpected,
+		message,
+	)
+}
+
+func setPolicyRolloutHalted(conditions *nmstatev1alpha1.ConditionList, message string) {
+	log.Info("setPolicyRolloutHalted")
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDegraded,
+		corev1.ConditionTrue,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionRolloutHalted,
+		message,
+	)
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable,
+		corev1.ConditionFalse,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionRolloutHalted,
+		"",
+	)
+}
+
+// setPolicyNoExpectedNodeMatching is set when nodes are expected but the
+// policy matches none, nothing is configured so it's not available
+func setPolicyNoExpectedNodeMatching(conditions *nmstatev1alpha1.ConditionList, message string) {
+	log.Info("setPolicyNoExpectedNodeMatching")
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDegraded,
+		corev1.ConditionTrue,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionFewerNodesThanExpected,
+		message,
+	)
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable,
+		corev1.ConditionFalse,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionFewerNodesThanExpected,
+		"",
+	)
+}
+
+func fewerNodesThanExpectedMessage(matching int, expected int) string {
+	return fmt.Sprintf("Policy matches %d nodes, fewer than the %d expected", matching, expected)
+}
+
+func summary(enactmentsCount enactmentconditions.ConditionCount) string {
+	return fmt.Sprintf("%d/%d Available, %d Failed, %d Progressing",
+		enactmentsCount.Available(), enactmentsCount.Matching(), enactmentsCount.Failed(), enactmentsCount.Progressing())
+}
+
+// linkDownSummary tells how many nodes are configured with interfaces
+// expected to have carrier down, it's empty without them
+func linkDownSummary(enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList) string {
+	linkDown := 0
+	for _, enactment := range enactments.Items {
+		condition := enactment.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable)
+		if condition != nil && condition.Status == corev1.ConditionTrue &&
+			condition.Reason == nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionConfiguredLinkDown {
+			linkDown++
+		}
+	}
+	if linkDown == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %d Link Down", linkDown)
+}
+
+// statusChanged compares the policy status ignoring the conditions
+// heartbeat, it's refreshed every time conditions are set
+func statusChanged(previous nmstatev1alpha1.NodeNetworkConfigurationPolicyStatus, current nmstatev1alpha1.NodeNetworkConfigurationPolicyStatus) bool {
+	previous = *previous.DeepCopy()
+	current = *current.DeepCopy()
+	for i := range previous.Conditions {
+		previous.Conditions[i].LastHeartbeatTime = metav1.Time{}
+	}
+	for i := range current.Conditions {
+		current.Conditions[i].LastHeartbeatTime = metav1.Time{}
+	}
+	return !reflect.DeepEqual(previous, current)
+}
+
+// rolloutHalted returns true if the policy is rolled out with Halt and
+// has already failed at some node since the rollout was last resumed
+func rolloutHalted(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList) bool {
+	if policy.Spec.Rollout == nil || policy.Spec.Rollout.OnFailure != nmstatev1alpha1.RolloutOnFailureHalt {
+		return false
+	}
+	for _, enactment := range enactments.Items {
+		if RolloutFailure(policy, enactment) {
+			return true
+		}
+	}
+	return false
+}
+
 func Update(cli client.Client, policyKey types.NamespacedName) error {
 	logger := log.WithValues("policy", policyKey.Name)
 	// On conflict we need to re-retrieve enactments since the
 	// conflict can denote that the calculated policy conditions
 	// are now not accurate.
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	return retry.RetryOnConflict(conflictBackoff, func() error {
 		policy := &nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
 		err := cli.Get(context.TODO(), policyKey, policy)
 		if err != nil {
+			if apierrors.IsNotFound(err) {
+				logger.Info("policy is gone, not updating its conditions")
+				return nil
+			}
 			return errors.Wrap(err, "getting policy failed")
 		}
+		if policy.DeletionTimestamp != nil {
+			logger.Info("policy is being deleted, not updating its conditions")
+			return nil
+		}
 
 		enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
 		policyLabelFilter := client.MatchingLabels{nmstatev1alpha1.EnactmentPolicyLabel: policy.Name}
@@ -124,22 +252,75 @@ func Update(cli client.Client, policyKey types.NamespacedName) error {
 
 		numberOfFinishedEnactments := enactmentsCount.Available() + enactmentsCount.Failed() + enactmentsCount.NotMatching()
 
+		previousStatus := policy.Status.DeepCopy()
+		previousOutcome := outcome(policy.Status.Conditions)
+
 		logger.Info(fmt.Sprintf("enactments count: %s", enactmentsCount))
-		if numberOfFinishedEnactments < numberOfReadyNodes {
+		staged := stagedEnactments(enactments)
+		policy.Status.Summary = summary(enactmentsCount) + stagedSummary(staged) + linkDownSummary(enactments)
+		policy.Status.Compliance = ""
+		if policy.Spec.Audit {
+			policy.Status.Compliance = compliance(enactments)
+		}
+		inProgress := numberOfFinishedEnactments < numberOfReadyNodes
+		halted := rolloutHalted(*policy, enactments)
+		// A halted rollout is reported until it's resumed, while there are
+		// nodes left to apply the policy or halted ones
+		rolloutStopped := halted && (inProgress || haltedEnactments(*policy, enactments) > 0)
+		policy.Status.Rollout, err = policyRollout(cli, *policy, nodes, enactments, enactmentsCount, inProgress, rolloutStopped)
+		if err != nil {
+			return err
+		}
+		policy.Status.ProgressingNodes = progressingNodes(enactments)
+		resumeQuarantine(policy)
+		if IsQuarantined(*policy) {
+			setPolicyQuarantined(&policy.Status.Conditions, quarantinedMessage(*policy))
+		} else if inProgress && halted {
+			// The nodes left are not going to apply it, no need to wait
+			// for them to report degraded
+			setPolicyRolloutHalted(&policy.Status.Conditions, fmt.Sprintf("Policy rollout halted, %d/%d nodes failed to configure", enactmentsCount.Failed(), enactmentsCount.Matching()))
+		} else if numberOfFinishedEnactments < numberOfReadyNodes && staged > 0 && numberOfFinishedEnactments+staged == numberOfReadyNodes {
+			// Every node left is waiting for the activation
+			setPolicyStaged(&policy.Status.Conditions, stagedMessage(staged, numberOfReadyNodes))
+		} else if numberOfFinishedEnactments < numberOfReadyNodes {
 			setPolicyProgressing(&policy.Status.Conditions, fmt.Sprintf("Policy is progressing %d/%d nodes finished", numberOfFinishedEnactments, numberOfReadyNodes))
 		} else {
-			if enactmentsCount.Matching() == 0 {
+			if enactmentsCount.Failed() == 0 && enactmentsCount.Matching() < policy.Spec.ExpectedNodeCount {
+				policy.Status.ConsecutiveFailures = 0
+				message := fewerNodesThanExpectedMessage(enactmentsCount.Matching(), policy.Spec.ExpectedNodeCount)
+				if enactmentsCount.Matching() == 0 {
+					setPolicyNoExpectedNodeMatching(&policy.Status.Conditions, message)
+				} else {
+					setPolicyFewerNodesThanExpected(&policy.Status.Conditions, message)
+				}
+			} else if enactmentsCount.Matching() == 0 {
 				message := "Policy does not match any node"
 				setPolicyNotMatching(&policy.Status.Conditions, message)
 			} else if enactmentsCount.Failed() > 0 {
-				message := fmt.Sprintf("%d/%d nodes failed to configure", enactmentsCount.Failed(), enactmentsCount.Matching())
-				setPolicyFailedToConfigure(&policy.Status.Conditions, message)
+				if enactmentsCount.Failed() == enactmentsCount.Matching() {
+					countFailure(policy)
+				}
+				if IsQuarantined(*policy) {
+					setPolicyQuarantined(&policy.Status.Conditions, quarantinedMessage(*policy))
+				} else {
+					message := fmt.Sprintf("%d/%d nodes failed to configure", enactmentsCount.Failed(), enactmentsCount.Matching())
+					setPolicyFailedToConfigure(&policy.Status.Conditions, message)
+				}
 			} else {
+				policy.Status.ConsecutiveFailures = 0
 				message := fmt.Sprintf("%d/%d nodes successfully configured", enactmentsCount.Available(), enactmentsCount.Available())
+				if policy.Spec.Audit {
+					message = fmt.Sprintf("%d/%d nodes audited", enactmentsCount.Available(), enactmentsCount.Available())
+				}
 				setPolicySuccess(&policy.Status.Conditions, message)
 			}
 		}
 
+		if !statusChanged(*previousStatus, policy.Status) {
+			logger.Info("policy conditions did not change, not updating them")
+			return nil
+		}
+
 		err = cli.Status().Update(context.TODO(), policy)
 		if err != nil {
 			if apierrors.IsConflict(err) {
@@ -149,18 +330,35 @@ func Update(cli client.Client, policyKey types.NamespacedName) error {
 			}
 			return err
 		}
+
+		// Only the handler transitioning the policy to a terminal
+		// condition succeeds updating it, so it's notified once
+		if currentOutcome := outcome(policy.Status.Conditions); currentOutcome != "" && currentOutcome != previousOutcome {
+			notify(*policy, enactments)
+		}
 		return nil
 	})
 }
 
 func Reset(cli client.Client, policyKey types.NamespacedName) error {
 	logger := log.WithValues("policy", policyKey.Name)
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	return retry.RetryOnConflict(conflictBackoff, func() error {
 		policy := &nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
 		err := cli.Get(context.TODO(), policyKey, policy)
 		if err != nil {
+			if apierrors.IsNotFound(err) {
+				logger.Info("policy is gone, not updating its conditions")
+				return nil
+			}
 			return errors.Wrap(err, "getting policy failed")
 		}
+		if policy.DeletionTimestamp != nil {
+			logger.Info("policy is being deleted, not updating its conditions")
+			return nil
+		}
+		if len(policy.Status.Conditions) == 0 {
+			return nil
+		}
 		policy.Status.Conditions = nmstatev1alpha1.ConditionList{}
 		err = cli.Status().Update(context.TODO(), policy)
 		if err != nil {
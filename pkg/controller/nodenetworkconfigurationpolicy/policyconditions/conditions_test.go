@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
@@ -51,6 +52,16 @@ func p(conditionsSetter func(*nmstatev1alpha1.ConditionList, string), message st
 	}
 }
 
+func withExpectedNodeCount(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, expectedNodeCount int) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+	policy.Spec.ExpectedNodeCount = expectedNodeCount
+	return policy
+}
+
+func withRolloutOnFailure(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, onFailure nmstatev1alpha1.RolloutOnFailure) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+	policy.Spec.Rollout = &nmstatev1alpha1.PolicyRollout{OnFailure: onFailure}
+	return policy
+}
+
 func newNode(idx int, conditions []corev1.NodeCondition) corev1.Node {
 	nodeName := fmt.Sprintf("node%d", idx)
 	node := corev1.Node{
@@ -249,5 +260,202 @@ var _ = Describe("Policy Conditions", func() {
 			},
 			Policy: p(setPolicySuccess, "3/3 nodes successfully configured"),
 		}),
+		Entry("when the policy matches the expected nodes then policy is success", ConditionsCase{
+			Enactments: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+				e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			},
+			Nodes:  newReadyNodes(2),
+			Policy: withExpectedNodeCount(p(setPolicySuccess, "2/2 nodes successfully configured"), 2),
+		}),
+		Entry("when the policy matches fewer nodes than expected then policy is degraded", ConditionsCase{
+			Enactments: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+				e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+				e("node3", "policy1", enactmentconditions.SetNodeSelectorNotMatching),
+			},
+			Nodes:  newReadyNodes(3),
+			Policy: withExpectedNodeCount(p(setPolicyFewerNodesThanExpected, "Policy matches 2 nodes, fewer than the 3 expected"), 3),
+		}),
+		Entry("when the policy does not match any node but some are expected then policy is degraded and not available", ConditionsCase{
+			Enactments: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetNodeSelectorNotMatching),
+			},
+			Nodes:  newReadyNodes(1),
+			Policy: withExpectedNodeCount(p(setPolicyNoExpectedNodeMatching, "Policy matches 0 nodes, fewer than the 1 expected"), 1),
+		}),
+		Entry("when the policy matches fewer nodes than expected and some failed then policy failed to configure", ConditionsCase{
+			Enactments: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetFailedToConfigure),
+				e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			},
+			Nodes:  newReadyNodes(2),
+			Policy: withExpectedNodeCount(p(setPolicyFailedToConfigure, "1/2 nodes failed to configure"), 3),
+		}),
+		Entry("when the policy halts on failure and an enactment failed while others are progressing then policy is degraded", ConditionsCase{
+			Enactments: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetFailedToConfigure),
+				e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetProgressing),
+				e("node3", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			},
+			Nodes:  newReadyNodes(3),
+			Policy: withRolloutOnFailure(p(setPolicyRolloutHalted, "Policy rollout halted, 1/3 nodes failed to configure"), nmstatev1alpha1.RolloutOnFailureHalt),
+		}),
+		Entry("when the policy continues on failure and an enactment failed while others are progressing then policy is progressing", ConditionsCase{
+			Enactments: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetFailedToConfigure),
+				e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetProgressing),
+				e("node3", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			},
+			Nodes:  newReadyNodes(3),
+			Policy: withRolloutOnFailure(p(setPolicyProgressing, "Policy is progressing 2/3 nodes finished"), nmstatev1alpha1.RolloutOnFailureContinue),
+		}),
+		Entry("when the policy halts on failure and the rest of the enactments halted then policy failed to configure", ConditionsCase{
+			Enactments: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetFailedToConfigure),
+				e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetRolloutHalted),
+			},
+			Nodes:  newReadyNodes(2),
+			Policy: withRolloutOnFailure(p(setPolicyFailedToConfigure, "2/2 nodes failed to configure"), nmstatev1alpha1.RolloutOnFailureHalt),
+		}),
+		Entry("when the enactments left are staged then policy is staged", ConditionsCase{
+			Enactments: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetStaged),
+				e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetStaged),
+				e("node3", "policy1", enactmentconditions.SetNodeSelectorNotMatching),
+			},
+			Nodes:  newReadyNodes(3),
+			Policy: p(setPolicyStaged, "Policy is staged at 2/3 nodes, waiting for the nmstate.io/activate annotation to activate it"),
+		}),
+		Entry("when some enactments are staged and others progressing then policy is progressing", ConditionsCase{
+			Enactments: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetStaged),
+				e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetProgressing),
+			},
+			Nodes:  newReadyNodes(2),
+			Policy: p(setPolicyProgressing, "Policy is progressing 0/2 nodes finished"),
+		}),
+	)
+})
+
+var _ = Describe("Policy Summary", func() {
+	DescribeTable("the policy summary",
+		func(enactments []nmstatev1alpha1.NodeNetworkConfigurationEnactment, expectedSummary string) {
+			enactmentsCount := enactmentconditions.Count(nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{Items: enactments})
+			Expect(summary(enactmentsCount)).To(Equal(expectedSummary))
+		},
+		Entry("when there are no enactments",
+			[]nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+			"0/0 Available, 0 Failed, 0 Progressing",
+		),
+		Entry("when enactments are failed/progressing/success",
+			[]nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+				e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetProgressing),
+				e("node3", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetFailedToConfigure),
+				e("node4", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			},
+			"2/4 Available, 1 Failed, 1 Progressing",
+		),
+		Entry("when some enactments are not matching",
+			[]nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+				e("node2", "policy1", enactmentconditions.SetNodeSelectorNotMatching),
+			},
+			"1/1 Available, 0 Failed, 0 Progressing",
+		),
 	)
 })
+
+var _ = Describe("Policy Staged Summary", func() {
+	It("should count the nodes holding the desired state staged", func() {
+		staged := stagedEnactments(nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{Items: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+			e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetStaged),
+			e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetProgressing),
+		}})
+		Expect(stagedSummary(staged)).To(Equal(", 1 Staged"))
+		Expect(stagedSummary(0)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Policy Link Down Summary", func() {
+	It("should be empty without nodes configured with links down", func() {
+		Expect(linkDownSummary(nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{Items: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+			e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+		}})).To(BeEmpty())
+	})
+
+	It("should count the nodes configured with links down", func() {
+		Expect(linkDownSummary(nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{Items: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+			e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetConfiguredLinkDown),
+			e("node3", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetConfiguredLinkDown),
+		}})).To(Equal(", 2 Link Down"))
+	})
+})
+
+var _ = Describe("Policy Conditions of a deleted policy", func() {
+	var client client.Client
+	BeforeEach(func() {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+		)
+		deletedPolicy := p(setPolicyProgressing, "")
+		deletedPolicy.Name = "deleted-policy"
+		deletedPolicy.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		client = fake.NewFakeClientWithScheme(s, &deletedPolicy)
+	})
+	It("should not update a policy that is gone", func() {
+		Expect(Update(client, types.NamespacedName{Name: "gone-policy"})).To(Succeed())
+		Expect(Reset(client, types.NamespacedName{Name: "gone-policy"})).To(Succeed())
+	})
+	It("should not update a policy being deleted", func() {
+		key := types.NamespacedName{Name: "deleted-policy"}
+		Expect(Update(client, key)).To(Succeed())
+		Expect(Reset(client, key)).To(Succeed())
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		Expect(client.Get(context.TODO(), key, &policy)).To(Succeed())
+		Expect(policy.Status.Conditions).ToNot(BeEmpty())
+		Expect(policy.Status.Summary).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Policy Conditions writes", func() {
+	var (
+		cli *conflictingClient
+		key types.NamespacedName
+	)
+	BeforeEach(func() {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+		)
+		policy := p(setPolicyProgressing, "")
+		enactments := []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+			e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+		}
+		nodes := newReadyNodes(2)
+		cli = &conflictingClient{Client: fake.NewFakeClientWithScheme(s, &policy, &enactments[0], &enactments[1], &nodes[0], &nodes[1])}
+		key = types.NamespacedName{Name: policy.Name}
+	})
+	It("should not write the policy status if it does not change", func() {
+		Expect(Update(cli, key)).To(Succeed())
+		Expect(cli.updates).To(Equal(1))
+
+		Expect(Update(cli, key)).To(Succeed())
+		Expect(cli.updates).To(Equal(1))
+	})
+	It("should not reset already empty conditions", func() {
+		Expect(Reset(cli, key)).To(Succeed())
+		Expect(cli.updates).To(Equal(1))
+
+		Expect(Reset(cli, key)).To(Succeed())
+		Expect(cli.updates).To(Equal(1))
+	})
+})
@@ -0,0 +1,179 @@
+package policyconditions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const (
+	notificationURLKey           = "url"
+	notificationAuthorizationKey = "authorization"
+)
+
+var (
+	// Namespace of the handler, where the notification secrets are looked up
+	notificationNamespace = ""
+
+	notificationBackoff = wait.Backoff{
+		Steps:    5,
+		Duration: 1 * time.Second,
+		Factor:   2.0,
+		Jitter:   0.1,
+	}
+
+	notificationClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+func init() {
+	notificationNamespace, _ = os.LookupEnv("POD_NAMESPACE")
+}
+
+type nodeNotification struct {
+	Node    string                          `json:"node"`
+	Status  nmstatev1alpha1.ConditionReason `json:"status"`
+	Message string                          `json:"message,omitempty"`
+}
+
+type notification struct {
+	Policy  string                          `json:"policy"`
+	Outcome nmstatev1alpha1.ConditionReason `json:"outcome"`
+	Message string                          `json:"message,omitempty"`
+	Summary string                          `json:"summary"`
+	Nodes   []nodeNotification              `json:"nodes"`
+}
+
+// outcome returns the reason of the policy terminal condition or empty
+// if the policy is still progressing
+func outcome(conditions nmstatev1alpha1.ConditionList) nmstatev1alpha1.ConditionReason {
+	for _, conditionType := range nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionTypes {
+		condition := conditions.Find(conditionType)
+		if condition != nil && condition.Status == corev1.ConditionTrue {
+			return condition.Reason
+		}
+	}
+	return ""
+}
+
+func newNotification(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList) notification {
+	n := notification{
+		Policy:  policy.Name,
+		Outcome: outcome(policy.Status.Conditions),
+		Summary: policy.Status.Summary,
+		Nodes:   []nodeNotification{},
+	}
+	for _, conditionType := range nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionTypes {
+		condition := policy.Status.Conditions.Find(conditionType)
+		if condition != nil && condition.Status == corev1.ConditionTrue {
+			n.Message = condition.Message
+		}
+	}
+	for _, enactment := range enactments.Items {
+		node := enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel]
+		if node == "" {
+			node = enactment.Name
+		}
+		nodeStatus := nodeNotification{Node: node}
+		if available := enactment.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable); available != nil {
+			nodeStatus.Status = available.Reason
+		}
+		nodeStatus.Message = enactment.Status.Conditions.FailureMessage()
+		n.Nodes = append(n.Nodes, nodeStatus)
+	}
+	return n
+}
+
+// notify posts the policy outcome to the webhook configured at the secret
+// referenced by the policy notification annotation. It's done in the
+// background and retried with backoff, failing to deliver it is only logged.
+func notify(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList) {
+	secretName := policy.Annotations[nmstatev1alpha1.NodeNetworkConfigurationPolicyNotificationSecretAnnotation]
+	if secretName == "" {
+		return
+	}
+	logger := log.WithValues("policy", policy.Name, "secret", secretName)
+	payload, err := json.Marshal(newNotification(policy, enactments))
+	if err != nil {
+		logger.Error(err, "failed marshaling policy notification")
+		return
+	}
+
+	go func() {
+		var lastErr error
+		err := wait.ExponentialBackoff(notificationBackoff, func() (bool, error) {
+			url, authorization, err := notificationTarget(secretName)
+			if err == nil {
+				err = deliverNotification(url, authorization, payload)
+			}
+			if err != nil {
+				lastErr = err
+				logger.Info(fmt.Sprintf("failed delivering policy notification, retrying: %v", err))
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil {
+			logger.Error(lastErr, "failed delivering policy notification, giving up")
+		}
+	}()
+}
+
+func notificationTarget(secretName string) (string, string, error) {
+	if notificationNamespace == "" {
+		return "", "", fmt.Errorf("POD_NAMESPACE is not set, cannot retrieve notification secret")
+	}
+	// Create new custom client to bypass cache, so we don't watch
+	// secrets at all the namespaces
+	config, err := config.GetConfig()
+	if err != nil {
+		return "", "", errors.Wrap(err, "getting config")
+	}
+	cli, err := client.New(config, client.Options{})
+	if err != nil {
+		return "", "", errors.Wrap(err, "creating new custom client")
+	}
+	secret := corev1.Secret{}
+	err = cli.Get(context.TODO(), types.NamespacedName{Namespace: notificationNamespace, Name: secretName}, &secret)
+	if err != nil {
+		return "", "", errors.Wrap(err, "getting notification secret")
+	}
+	url := string(secret.Data[notificationURLKey])
+	if url == "" {
+		return "", "", fmt.Errorf("notification secret %s has no %s", secretName, notificationURLKey)
+	}
+	return url, string(secret.Data[notificationAuthorizationKey]), nil
+}
+
+func deliverNotification(url string, authorization string, payload []byte) error {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "creating notification request")
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+	response, err := notificationClient.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "posting notification")
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("notification webhook responded with %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package policyconditions
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("Policy notification", func() {
+	It("should have no outcome while progressing", func() {
+		Expect(outcome(p(setPolicyProgressing, "").Status.Conditions)).To(BeEmpty())
+	})
+
+	It("should build the payload with the per node status", func() {
+		policy := p(setPolicyFailedToConfigure, "1/2 nodes failed to configure")
+		policy.Status.Summary = "1/2 Available, 1 Failed, 0 Progressing"
+		failed := e("node2", "policy1", enactmentconditions.SetMatching)
+		enactmentconditions.SetFailedToConfigure(&failed.Status.Conditions, "nmstatectl set failed")
+		failed.Labels[nmstatev1alpha1.EnactmentNodeLabel] = "node2"
+		enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{
+			Items: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+				failed,
+			},
+		}
+		Expect(newNotification(policy, enactments)).To(Equal(notification{
+			Policy:  "policy1",
+			Outcome: nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionFailedToConfigure,
+			Message: "1/2 nodes failed to configure",
+			Summary: "1/2 Available, 1 Failed, 0 Progressing",
+			Nodes: []nodeNotification{
+				{Node: "node1.policy1", Status: nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured},
+				{Node: "node2", Status: nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailedToConfigure, Message: "nmstatectl set failed"},
+			},
+		}))
+	})
+
+	Context("when delivering to the webhook", func() {
+		var (
+			server        *httptest.Server
+			status        int
+			authorization string
+			received      notification
+		)
+		BeforeEach(func() {
+			status = http.StatusOK
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorization = r.Header.Get("Authorization")
+				body, _ := ioutil.ReadAll(r.Body)
+				json.Unmarshal(body, &received)
+				w.WriteHeader(status)
+			}))
+		})
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should post the payload with the authorization header", func() {
+			payload, err := json.Marshal(notification{Policy: "policy1", Outcome: "SuccessfullyConfigured"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deliverNotification(server.URL, "Bearer foo", payload)).To(Succeed())
+			Expect(authorization).To(Equal("Bearer foo"))
+			Expect(received.Policy).To(Equal("policy1"))
+			Expect(received.Outcome).To(BeEquivalentTo("SuccessfullyConfigured"))
+		})
+
+		It("should fail if the webhook does not accept it", func() {
+			status = http.StatusInternalServerError
+			Expect(deliverNotification(server.URL, "", []byte("{}"))).ToNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,30 @@
+package policyconditions
+
+import (
+	"sort"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Upper bound of nodes listed as progressing, so a rollout at a big cluster
+// does not bloat the policy
+const maxProgressingNodes = 50
+
+// progressingNodes returns the sorted nodes of the enactments applying the
+// desired state right now, nil if there are none
+func progressingNodes(enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList) []string {
+	nodes := []string{}
+	for _, enactment := range enactments.Items {
+		if enactment.Status.Conditions.IsTrue(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionProgressing) {
+			nodes = append(nodes, enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel])
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	sort.Strings(nodes)
+	if len(nodes) > maxProgressingNodes {
+		nodes = nodes[:maxProgressingNodes]
+	}
+	return nodes
+}
@@ -0,0 +1,62 @@
+package policyconditions
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("Policy progressing nodes", func() {
+	nodeEnactment := func(node string, setters ...func(*nmstatev1alpha1.ConditionList, string)) nmstatev1alpha1.NodeNetworkConfigurationEnactment {
+		enactment := e(node, "policy1", setters...)
+		enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel] = node
+		return enactment
+	}
+
+	It("should list the nodes applying the desired state", func() {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+		)
+		policy := p(setPolicyProgressing, "")
+		nodes := newReadyNodes(3)
+		node3 := nodeEnactment("node3", enactmentconditions.SetMatching, enactmentconditions.SetProgressing)
+		node1 := nodeEnactment("node1", enactmentconditions.SetMatching, enactmentconditions.SetProgressing)
+		node2 := nodeEnactment("node2", enactmentconditions.SetMatching, enactmentconditions.SetSuccess)
+		cli := fake.NewFakeClientWithScheme(s, &policy, &nodes[0], &nodes[1], &nodes[2], &node3, &node1, &node2)
+
+		key := types.NamespacedName{Name: policy.Name}
+		Expect(Update(cli, key)).To(Succeed())
+		Expect(cli.Get(context.TODO(), key, &policy)).To(Succeed())
+		Expect(policy.Status.ProgressingNodes).To(Equal([]string{"node1", "node3"}))
+	})
+
+	It("should omit them when no node is progressing", func() {
+		Expect(progressingNodes(nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{
+			Items: []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+				nodeEnactment("node1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			},
+		})).To(BeNil())
+	})
+
+	It("should bound them", func() {
+		enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+		for i := 0; i < maxProgressingNodes+10; i++ {
+			enactments.Items = append(enactments.Items, nodeEnactment(fmt.Sprintf("node%03d", i), enactmentconditions.SetMatching, enactmentconditions.SetProgressing))
+		}
+		nodes := progressingNodes(enactments)
+		Expect(nodes).To(HaveLen(maxProgressingNodes))
+		Expect(nodes[0]).To(Equal("node000"))
+	})
+})
@@ -0,0 +1,89 @@
+package policyconditions
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var (
+	// Number of consecutive policy generations failing at all the matching
+	// nodes needed to quarantine the policy, zero disables quarantine.
+	quarantineThreshold = 0
+)
+
+func init() {
+	threshold, isSet := os.LookupEnv("POLICY_QUARANTINE_THRESHOLD")
+	if !isSet || threshold == "" {
+		return
+	}
+	var err error
+	quarantineThreshold, err = strconv.Atoi(threshold)
+	if err != nil {
+		panic(fmt.Sprintf("Failed while converting evnironment variable to int: %v", err))
+	}
+}
+
+func resumeAnnotation(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) string {
+	return policy.ObjectMeta.Annotations[nmstatev1alpha1.NodeNetworkConfigurationPolicyQuarantineResumeAnnotation]
+}
+
+// IsQuarantined returns true if the policy has failed at all the matching
+// nodes more times in a row than allowed and the user has not resumed it by
+// changing the quarantine resume annotation.
+func IsQuarantined(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) bool {
+	if quarantineThreshold <= 0 {
+		return false
+	}
+	if resumeAnnotation(policy) != policy.Status.QuarantineResume {
+		return false
+	}
+	return policy.Status.ConsecutiveFailures >= quarantineThreshold
+}
+
+// resumeQuarantine resets the failures count if the user has changed the
+// quarantine resume annotation since last time.
+func resumeQuarantine(policy *nmstatev1alpha1.NodeNetworkConfigurationPolicy) {
+	resume := resumeAnnotation(*policy)
+	if resume == policy.Status.QuarantineResume {
+		return
+	}
+	log.Info("resuming policy from quarantine", "policy", policy.Name)
+	policy.Status.QuarantineResume = resume
+	policy.Status.ConsecutiveFailures = 0
+}
+
+// countFailure accounts a policy generation failing at all the matching nodes,
+// every generation is accounted only once.
+func countFailure(policy *nmstatev1alpha1.NodeNetworkConfigurationPolicy) {
+	if policy.Status.LastFailedGeneration == policy.Generation {
+		return
+	}
+	policy.Status.LastFailedGeneration = policy.Generation
+	policy.Status.ConsecutiveFailures += 1
+}
+
+func setPolicyQuarantined(conditions *nmstatev1alpha1.ConditionList, message string) {
+	log.Info("setPolicyQuarantined")
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDegraded,
+		corev1.ConditionTrue,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionQuarantined,
+		message,
+	)
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable,
+		corev1.ConditionFalse,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionQuarantined,
+		"",
+	)
+}
+
+func quarantinedMessage(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) string {
+	return fmt.Sprintf("Policy quarantined after failing %d times in a row at all the matching nodes, change the %s annotation to resume it",
+		policy.Status.ConsecutiveFailures, nmstatev1alpha1.NodeNetworkConfigurationPolicyQuarantineResumeAnnotation)
+}
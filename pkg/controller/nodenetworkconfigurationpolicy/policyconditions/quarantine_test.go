@@ -0,0 +1,145 @@
+package policyconditions
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("Policy Quarantine", func() {
+	var (
+		policy     nmstatev1alpha1.NodeNetworkConfigurationPolicy
+		enactments []nmstatev1alpha1.NodeNetworkConfigurationEnactment
+	)
+
+	updatePolicy := func() nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+		)
+		objs := []runtime.Object{&policy}
+		for i, _ := range enactments {
+			objs = append(objs, &enactments[i])
+		}
+		nodes := newReadyNodes(len(enactments))
+		for i, _ := range nodes {
+			objs = append(objs, &nodes[i])
+		}
+		client := fake.NewFakeClientWithScheme(s, objs...)
+		key := types.NamespacedName{Name: policy.Name}
+		err := Update(client, key)
+		Expect(err).ToNot(HaveOccurred())
+		updatedPolicy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		err = client.Get(context.TODO(), key, &updatedPolicy)
+		Expect(err).ToNot(HaveOccurred())
+		return updatedPolicy
+	}
+
+	degradedReason := func(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) nmstatev1alpha1.ConditionReason {
+		condition := policy.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDegraded)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		return condition.Reason
+	}
+
+	BeforeEach(func() {
+		quarantineThreshold = 2
+		policy = p(setPolicyProgressing, "")
+		policy.Generation = 2
+		policy.Status.ConsecutiveFailures = 1
+		policy.Status.LastFailedGeneration = 1
+		enactments = []nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+			e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetFailedToConfigure),
+			e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetFailedToConfigure),
+		}
+	})
+
+	AfterEach(func() {
+		quarantineThreshold = 0
+	})
+
+	Context("when a new generation fails at all the nodes reaching the threshold", func() {
+		It("should quarantine the policy", func() {
+			updatedPolicy := updatePolicy()
+			Expect(updatedPolicy.Status.ConsecutiveFailures).To(Equal(2))
+			Expect(updatedPolicy.Status.LastFailedGeneration).To(Equal(int64(2)))
+			Expect(IsQuarantined(updatedPolicy)).To(BeTrue())
+			Expect(degradedReason(updatedPolicy)).To(Equal(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionQuarantined))
+		})
+	})
+
+	Context("when the failing generation was already accounted", func() {
+		BeforeEach(func() {
+			policy.Status.LastFailedGeneration = 2
+		})
+		It("should not count it again", func() {
+			updatedPolicy := updatePolicy()
+			Expect(updatedPolicy.Status.ConsecutiveFailures).To(Equal(1))
+			Expect(IsQuarantined(updatedPolicy)).To(BeFalse())
+			Expect(degradedReason(updatedPolicy)).To(Equal(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionFailedToConfigure))
+		})
+	})
+
+	Context("when only some nodes fail", func() {
+		BeforeEach(func() {
+			enactments[0] = e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess)
+		})
+		It("should not count the failure", func() {
+			updatedPolicy := updatePolicy()
+			Expect(updatedPolicy.Status.ConsecutiveFailures).To(Equal(1))
+			Expect(IsQuarantined(updatedPolicy)).To(BeFalse())
+		})
+	})
+
+	Context("when all the nodes succeed", func() {
+		BeforeEach(func() {
+			enactments[0] = e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess)
+			enactments[1] = e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess)
+		})
+		It("should reset the failures count", func() {
+			updatedPolicy := updatePolicy()
+			Expect(updatedPolicy.Status.ConsecutiveFailures).To(Equal(0))
+		})
+	})
+
+	Context("when the policy is quarantined", func() {
+		BeforeEach(func() {
+			policy.Status.ConsecutiveFailures = 2
+			policy.Status.LastFailedGeneration = 2
+		})
+		It("should keep it quarantined", func() {
+			updatedPolicy := updatePolicy()
+			Expect(IsQuarantined(updatedPolicy)).To(BeTrue())
+			Expect(degradedReason(updatedPolicy)).To(Equal(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionQuarantined))
+		})
+		Context("and the resume annotation is changed", func() {
+			BeforeEach(func() {
+				policy.Annotations = map[string]string{
+					nmstatev1alpha1.NodeNetworkConfigurationPolicyQuarantineResumeAnnotation: "1",
+				}
+			})
+			It("should not be quarantined even before status is updated", func() {
+				Expect(IsQuarantined(policy)).To(BeFalse())
+			})
+			It("should lift the quarantine and reset the failures count", func() {
+				updatedPolicy := updatePolicy()
+				Expect(updatedPolicy.Status.ConsecutiveFailures).To(Equal(0))
+				Expect(updatedPolicy.Status.QuarantineResume).To(Equal("1"))
+				Expect(IsQuarantined(updatedPolicy)).To(BeFalse())
+				Expect(degradedReason(updatedPolicy)).To(Equal(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionFailedToConfigure))
+			})
+		})
+	})
+})
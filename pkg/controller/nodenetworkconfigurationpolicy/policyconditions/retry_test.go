@@ -0,0 +1,104 @@
+package policyconditions
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// conflictingClient fails the first conflicts status updates with a
+// conflict error
+type conflictingClient struct {
+	client.Client
+	conflicts int
+	updates   int
+}
+
+func (c *conflictingClient) Status() client.StatusWriter {
+	return conflictingStatusWriter{c.Client.Status(), c}
+}
+
+type conflictingStatusWriter struct {
+	client.StatusWriter
+	client *conflictingClient
+}
+
+func (w conflictingStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	w.client.updates++
+	if w.client.updates <= w.client.conflicts {
+		return apierrors.NewConflict(schema.GroupResource{Resource: "nodenetworkconfigurationpolicies"}, "policy1", nil)
+	}
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+var _ = Describe("Policy Conditions conflict retry", func() {
+	var (
+		cli            *conflictingClient
+		key            types.NamespacedName
+		defaultBackoff wait.Backoff
+	)
+
+	BeforeEach(func() {
+		defaultBackoff = conflictBackoff
+		conflictBackoff = wait.Backoff{
+			Steps:    4,
+			Duration: time.Millisecond,
+			Factor:   2.0,
+			Jitter:   0.5,
+			Cap:      10 * time.Millisecond,
+		}
+
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+			&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+		)
+		policy := p(setPolicyProgressing, "")
+		cli = &conflictingClient{Client: fake.NewFakeClientWithScheme(s, &policy)}
+		key = types.NamespacedName{Name: policy.Name}
+	})
+
+	AfterEach(func() {
+		conflictBackoff = defaultBackoff
+	})
+
+	Context("when conflicts are transient", func() {
+		BeforeEach(func() {
+			cli.conflicts = 2
+		})
+		It("should retry until the update succeeds", func() {
+			Expect(Update(cli, key)).To(Succeed())
+			Expect(cli.updates).To(Equal(3))
+		})
+	})
+
+	Context("when conflicts persist", func() {
+		BeforeEach(func() {
+			cli.conflicts = 100
+		})
+		It("should give up after the backoff steps returning the conflict", func() {
+			err := Update(cli, key)
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+			Expect(cli.updates).To(Equal(4))
+		})
+		It("should give up resetting after the backoff steps returning the conflict", func() {
+			err := Reset(cli, key)
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+			Expect(cli.updates).To(Equal(4))
+		})
+	})
+})
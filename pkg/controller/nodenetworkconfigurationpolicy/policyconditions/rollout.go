@@ -0,0 +1,214 @@
+package policyconditions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/selectors"
+)
+
+// policyRollout describes the rollout of the policy, by zone or at all the
+// nodes at the same time
+func policyRollout(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, nodes corev1.NodeList, enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList, enactmentsCount enactmentconditions.ConditionCount, inProgress bool, halted bool) (string, error) {
+	if !ByZone(policy) {
+		return rollout(enactmentsCount, inProgress, halted), nil
+	}
+	policies := nmstatev1alpha1.NodeNetworkConfigurationPolicyList{}
+	err := cli.List(context.TODO(), &policies)
+	if err != nil {
+		return "", errors.Wrap(err, "getting policies failed")
+	}
+	higherPriority := []string{}
+	for _, higherPriorityPolicy := range HigherPriorityPolicies(policy, policies) {
+		higherPriority = append(higherPriority, higherPriorityPolicy.Name)
+	}
+	return zoneRollout(policy, NodeZones(nodes), enactments, higherPriority, enactmentsCount, inProgress, halted), nil
+}
+
+// rollout describes how the policy is rolled out at the matching nodes. The
+// handlers apply the policy at all of them at the same time, so the rollout
+// is a single batch with every matching node. A halted batch reports the
+// nodes already available, the ones a resumed rollout does not apply again.
+func rollout(enactmentsCount enactmentconditions.ConditionCount, inProgress bool, halted bool) string {
+	matching := enactmentsCount.Matching()
+	if matching == 0 {
+		return ""
+	}
+	batches, batchSize := 1, matching
+	return fmt.Sprintf("%d matching nodes in %d batch of %d, batch %d of %d %s",
+		matching, batches, batchSize, batches, batches, rolloutProgress(enactmentsCount, inProgress, halted))
+}
+
+func rolloutProgress(enactmentsCount enactmentconditions.ConditionCount, inProgress bool, halted bool) string {
+	if halted {
+		return fmt.Sprintf("halted with %d/%d nodes available", enactmentsCount.Available(), enactmentsCount.Matching())
+	}
+	if inProgress {
+		return "in progress"
+	}
+	return "completed"
+}
+
+// zoneRollout describes the rollout of a policy rolled out by zone, a batch
+// per zone with matching nodes, in the zones order, and the policies with
+// higher priority each batch waits for. The current batch is the first zone
+// the policy is not available at yet.
+func zoneRollout(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, nodeZones map[string]string, enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList, higherPriority []string, enactmentsCount enactmentconditions.ConditionCount, inProgress bool, halted bool) string {
+	zones := RolloutZones(nodeZones, enactments)
+	if len(zones) == 0 {
+		return ""
+	}
+	matchingNodes := map[string]int{}
+	for _, enactment := range enactments.Items {
+		if enactment.Status.Conditions.IsTrue(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionMatching) {
+			matchingNodes[nodeZones[enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel]]] += 1
+		}
+	}
+
+	current := len(zones)
+	batches := []string{}
+	for i, zone := range zones {
+		if current == len(zones) && !ZoneAvailable(policy, zone, nodeZones, enactments) {
+			current = i + 1
+		}
+		batches = append(batches, fmt.Sprintf("%s: %d", ZoneName(zone), matchingNodes[zone]))
+	}
+
+	plan := fmt.Sprintf("%d matching nodes in %d %s by zone [%s]", enactmentsCount.Matching(), len(zones), pluralize("batch", "batches", len(zones)), strings.Join(batches, ", "))
+	if len(higherPriority) > 0 {
+		plan += fmt.Sprintf(" after policies [%s]", strings.Join(higherPriority, ", "))
+	}
+	return fmt.Sprintf("%s, batch %d of %d %s", plan, current, len(zones), rolloutProgress(enactmentsCount, inProgress, halted))
+}
+
+func pluralize(singular string, plural string, count int) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+// ZoneName names the nodes without zone label like kubectl does
+func ZoneName(zone string) string {
+	if zone == "" {
+		return "<none>"
+	}
+	return zone
+}
+
+// ByZone returns true if the policy is rolled out zone by zone
+func ByZone(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) bool {
+	return policy.Spec.Rollout != nil && policy.Spec.Rollout.Topology == nmstatev1alpha1.RolloutTopologyZone
+}
+
+// rolloutPriority returns the priority of the policy within the zone
+// rollouts
+func rolloutPriority(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) int32 {
+	if policy.Spec.Rollout == nil {
+		return 0
+	}
+	return policy.Spec.Rollout.Priority
+}
+
+// NodeZones returns the zone of every node by name
+func NodeZones(nodes corev1.NodeList) map[string]string {
+	nodeZones := map[string]string{}
+	for _, node := range nodes.Items {
+		nodeZones[node.Name] = selectors.NodeZone(node.Labels)
+	}
+	return nodeZones
+}
+
+// RolloutZones returns the sorted zones of the nodes matching the policy of
+// the enactments, the nodes without zone label go first
+func RolloutZones(nodeZones map[string]string, enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList) []string {
+	found := map[string]bool{}
+	zones := []string{}
+	for _, enactment := range enactments.Items {
+		if !enactment.Status.Conditions.IsTrue(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionMatching) {
+			continue
+		}
+		zone := nodeZones[enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel]]
+		if !found[zone] {
+			found[zone] = true
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// ZoneAvailable returns true if the current policy generation is available
+// at all the nodes of the zone matching the policy, the enactments are the
+// policy ones
+func ZoneAvailable(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, zone string, nodeZones map[string]string, enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList) bool {
+	for _, enactment := range enactments.Items {
+		if nodeZones[enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel]] != zone ||
+			!enactment.Status.Conditions.IsTrue(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionMatching) {
+			continue
+		}
+		if enactment.Status.PolicyGeneration != policy.Generation ||
+			!enactment.Status.Conditions.IsTrue(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable) {
+			return false
+		}
+	}
+	return true
+}
+
+// HigherPriorityPolicies returns the policies rolled out by zone with higher
+// priority than the policy, sorted by name, the policy waits for them at
+// every zone
+func HigherPriorityPolicies(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, policies nmstatev1alpha1.NodeNetworkConfigurationPolicyList) []nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+	higherPriority := []nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+	if !ByZone(policy) {
+		return higherPriority
+	}
+	for _, other := range policies.Items {
+		if other.Name != policy.Name && ByZone(other) && rolloutPriority(other) > rolloutPriority(policy) {
+			higherPriority = append(higherPriority, other)
+		}
+	}
+	sort.Slice(higherPriority, func(i, j int) bool { return higherPriority[i].Name < higherPriority[j].Name })
+	return higherPriority
+}
+
+// RolloutResume returns the value of the rollout resume annotation of the
+// policy, changing it resumes a halted rollout
+func RolloutResume(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) string {
+	return policy.ObjectMeta.Annotations[nmstatev1alpha1.NodeNetworkConfigurationPolicyRolloutResumeAnnotation]
+}
+
+// RolloutFailure returns true if the enactment failed to configure the
+// current policy generation since its rollout was last resumed. Enactments
+// halted themselves are not failures.
+func RolloutFailure(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactment nmstatev1alpha1.NodeNetworkConfigurationEnactment) bool {
+	if enactment.Status.PolicyGeneration != policy.Generation || enactment.Status.RolloutResume != RolloutResume(policy) {
+		return false
+	}
+	failing := enactment.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing)
+	return failing != nil && failing.Status == corev1.ConditionTrue && failing.Reason != nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionRolloutHalted
+}
+
+// haltedEnactments returns how many enactments did not apply the current
+// policy generation because its rollout was halted
+func haltedEnactments(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList) int {
+	halted := 0
+	for _, enactment := range enactments.Items {
+		if enactment.Status.PolicyGeneration != policy.Generation {
+			continue
+		}
+		failing := enactment.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing)
+		if failing != nil && failing.Status == corev1.ConditionTrue && failing.Reason == nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionRolloutHalted {
+			halted += 1
+		}
+	}
+	return halted
+}
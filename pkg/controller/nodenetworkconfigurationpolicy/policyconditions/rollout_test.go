@@ -0,0 +1,137 @@
+package policyconditions
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("Policy rollout", func() {
+	s := scheme.Scheme
+	s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+		&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+		&nmstatev1alpha1.NodeNetworkConfigurationPolicyList{},
+		&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+		&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+	)
+
+	rolloutOfPolicy := func(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactments ...nmstatev1alpha1.NodeNetworkConfigurationEnactment) string {
+		nodes := newReadyNodes(3)
+		cli := fake.NewFakeClientWithScheme(s, &policy, &nodes[0], &nodes[1], &nodes[2])
+		for i := range enactments {
+			Expect(cli.Create(context.TODO(), &enactments[i])).To(Succeed())
+		}
+
+		key := types.NamespacedName{Name: policy.Name}
+		Expect(Update(cli, key)).To(Succeed())
+		Expect(cli.Get(context.TODO(), key, &policy)).To(Succeed())
+		return policy.Status.Rollout
+	}
+
+	rolloutOf := func(enactments ...nmstatev1alpha1.NodeNetworkConfigurationEnactment) string {
+		return rolloutOfPolicy(p(setPolicyProgressing, ""), enactments...)
+	}
+
+	It("should plan a single batch with the matching nodes while in progress", func() {
+		Expect(rolloutOf(
+			e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetProgressing),
+		)).To(Equal("2 matching nodes in 1 batch of 2, batch 1 of 1 in progress"))
+	})
+
+	It("should complete the batch once every node finished", func() {
+		Expect(rolloutOf(
+			e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			e("node3", "policy1", enactmentconditions.SetNodeSelectorNotMatching),
+		)).To(Equal("2 matching nodes in 1 batch of 2, batch 1 of 1 completed"))
+	})
+
+	It("should report the available nodes of a halted batch", func() {
+		Expect(rolloutOfPolicy(withRolloutOnFailure(p(setPolicyProgressing, ""), nmstatev1alpha1.RolloutOnFailureHalt),
+			e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetFailedToConfigure),
+			e("node3", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetRolloutHalted),
+		)).To(Equal("3 matching nodes in 1 batch of 3, batch 1 of 1 halted with 1/3 nodes available"))
+	})
+
+	It("should go on with the batch once the halted rollout is resumed", func() {
+		policy := withRolloutOnFailure(p(setPolicyProgressing, ""), nmstatev1alpha1.RolloutOnFailureHalt)
+		policy.Annotations = map[string]string{nmstatev1alpha1.NodeNetworkConfigurationPolicyRolloutResumeAnnotation: "1"}
+		Expect(rolloutOfPolicy(policy,
+			e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess),
+			e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetFailedToConfigure),
+			e("node3", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetProgressing),
+		)).To(Equal("3 matching nodes in 1 batch of 3, batch 1 of 1 in progress"))
+	})
+
+	It("should not plan a rollout without matching nodes", func() {
+		Expect(rolloutOf(
+			e("node1", "policy1", enactmentconditions.SetNodeSelectorNotMatching),
+			e("node2", "policy1", enactmentconditions.SetNodeSelectorNotMatching),
+			e("node3", "policy1", enactmentconditions.SetNodeSelectorNotMatching),
+		)).To(BeEmpty())
+	})
+
+	Context("when the policy is rolled out by zone", func() {
+		byZone := func(name string, priority int32) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+			policy := p(setPolicyProgressing, "")
+			policy.Name = name
+			policy.Spec.Rollout = &nmstatev1alpha1.PolicyRollout{Topology: nmstatev1alpha1.RolloutTopologyZone, Priority: priority}
+			return policy
+		}
+		onNode := func(enactment nmstatev1alpha1.NodeNetworkConfigurationEnactment, node string) nmstatev1alpha1.NodeNetworkConfigurationEnactment {
+			enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel] = node
+			return enactment
+		}
+		zoneRolloutOf := func(enactments ...nmstatev1alpha1.NodeNetworkConfigurationEnactment) string {
+			policy := byZone("policy1", 0)
+			uplinks := byZone("uplinks", 10)
+			nodes := newReadyNodes(3)
+			for i, zone := range []string{"zone-a", "zone-a", "zone-b"} {
+				nodes[i].Labels["topology.kubernetes.io/zone"] = zone
+			}
+			cli := fake.NewFakeClientWithScheme(s, &policy, &uplinks, &nodes[0], &nodes[1], &nodes[2])
+			for i := range enactments {
+				Expect(cli.Create(context.TODO(), &enactments[i])).To(Succeed())
+			}
+
+			key := types.NamespacedName{Name: policy.Name}
+			Expect(Update(cli, key)).To(Succeed())
+			Expect(cli.Get(context.TODO(), key, &policy)).To(Succeed())
+			return policy.Status.Rollout
+		}
+
+		It("should plan a batch per zone after the higher priority policies", func() {
+			Expect(zoneRolloutOf(
+				onNode(e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess), "node1"),
+				onNode(e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetProgressing), "node2"),
+				onNode(e("node3", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetWaitingForRollout), "node3"),
+			)).To(Equal("3 matching nodes in 2 batches by zone [zone-a: 2, zone-b: 1] after policies [uplinks], batch 1 of 2 in progress"))
+		})
+
+		It("should go on with the next zone once the previous one is available", func() {
+			Expect(zoneRolloutOf(
+				onNode(e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess), "node1"),
+				onNode(e("node2", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess), "node2"),
+				onNode(e("node3", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetProgressing), "node3"),
+			)).To(Equal("3 matching nodes in 2 batches by zone [zone-a: 2, zone-b: 1] after policies [uplinks], batch 2 of 2 in progress"))
+		})
+
+		It("should complete the last zone once every node finished", func() {
+			Expect(zoneRolloutOf(
+				onNode(e("node1", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess), "node1"),
+				onNode(e("node2", "policy1", enactmentconditions.SetNodeSelectorNotMatching), "node2"),
+				onNode(e("node3", "policy1", enactmentconditions.SetMatching, enactmentconditions.SetSuccess), "node3"),
+			)).To(Equal("2 matching nodes in 2 batches by zone [zone-a: 1, zone-b: 1] after policies [uplinks], batch 2 of 2 completed"))
+		})
+	})
+})
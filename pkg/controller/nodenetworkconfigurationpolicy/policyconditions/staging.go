@@ -0,0 +1,52 @@
+package policyconditions
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+func setPolicyStaged(conditions *nmstatev1alpha1.ConditionList, message string) {
+	log.Info("setPolicyStaged")
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDegraded,
+		corev1.ConditionUnknown,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionStaged,
+		"",
+	)
+	conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable,
+		corev1.ConditionUnknown,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionStaged,
+		message,
+	)
+}
+
+// stagedEnactments returns how many enactments hold the desired state
+// staged, waiting for the policy to be activated
+func stagedEnactments(enactments nmstatev1alpha1.NodeNetworkConfigurationEnactmentList) int {
+	staged := 0
+	for _, enactment := range enactments.Items {
+		condition := enactment.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionProgressing)
+		if condition != nil && condition.Status == corev1.ConditionTrue &&
+			condition.Reason == nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionStaged {
+			staged++
+		}
+	}
+	return staged
+}
+
+// stagedSummary tells how many of the progressing nodes are staged, it's
+// empty without them
+func stagedSummary(staged int) string {
+	if staged == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %d Staged", staged)
+}
+
+func stagedMessage(staged int, numberOfReadyNodes int) string {
+	return fmt.Sprintf("Policy is staged at %d/%d nodes, waiting for the %s annotation to activate it", staged, numberOfReadyNodes, nmstatev1alpha1.NodeNetworkConfigurationPolicyActivateAnnotation)
+}
@@ -0,0 +1,69 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/selectors"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// reconcileInterval returns how long to wait before reconciling the policy
+// again, 0 if it's only reconciled on changes
+func reconcileInterval(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) time.Duration {
+	if policy.Spec.ReconcileInterval == nil {
+		return 0
+	}
+	return policy.Spec.ReconcileInterval.Duration
+}
+
+// appliedEnactment tells if the enactment has successfully applied the
+// current generation of the policy desired state
+func appliedEnactment(enactment nmstatev1alpha1.NodeNetworkConfigurationEnactment, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) bool {
+	return enactment.Status.Conditions.IsAvailable() &&
+		enactment.Status.PolicyGeneration == policy.Generation &&
+		reflect.DeepEqual(enactment.Status.DesiredState, policy.Spec.DesiredState)
+}
+
+// desiredStateStillApplied tells if the policy desired state was applied at
+// the node and has not drifted since then, so the periodic reconciles do
+// not apply it again
+func (r *ReconcileNodeNetworkConfigurationPolicy) desiredStateStillApplied(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) (bool, error) {
+	enactment := nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+	err := r.client.Get(context.TODO(), nmstatev1alpha1.EnactmentKey(nodeName, policy.Name), &enactment)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed getting enactment")
+	}
+	if !appliedEnactment(enactment, policy) {
+		return false, nil
+	}
+
+	// The node may not match the policy anymore, the rest of the reconcile
+	// takes care of it
+	policySelectors := selectors.NewFromPolicy(r.client, policy)
+	unmatchingNodeLabels, err := policySelectors.UnmatchedNodeLabels(nodeName)
+	if err != nil || len(unmatchingNodeLabels) > 0 {
+		return false, err
+	}
+
+	// Compared like the drift detection, with the reported state, so the
+	// settings the handler applies besides nmstate are not drift
+	currentState, err := nmstate.ReportedCurrentState()
+	if err != nil {
+		return false, err
+	}
+	drift, err := nmstate.Drift(enactment.Status.DesiredState, currentState, policy.Spec.DriftIgnore)
+	if err != nil {
+		return false, errors.Wrap(err, "failed comparing desired state with current state")
+	}
+	return len(drift) == 0, nil
+}
@@ -0,0 +1,61 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("Policy reconcile interval", func() {
+	const eth1Up = "interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n"
+
+	policy := func() nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "eth1-policy", Generation: 2},
+		}
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState(eth1Up)
+		return policy
+	}
+
+	It("should only requeue policies with interval", func() {
+		p := policy()
+		Expect(reconcileInterval(p)).To(BeZero())
+		p.Spec.ReconcileInterval = &metav1.Duration{Duration: 5 * time.Minute}
+		Expect(reconcileInterval(p)).To(Equal(5 * time.Minute))
+	})
+
+	DescribeTable("requeueing policies applied together",
+		func(interval time.Duration, other time.Duration, expectedInterval time.Duration) {
+			Expect(shortestInterval(interval, other)).To(Equal(expectedInterval))
+		},
+		Entry("without intervals, should not requeue", time.Duration(0), time.Duration(0), time.Duration(0)),
+		Entry("with the first interval only, should requeue at it", 5*time.Minute, time.Duration(0), 5*time.Minute),
+		Entry("with the second interval only, should requeue at it", time.Duration(0), 5*time.Minute, 5*time.Minute),
+		Entry("with both intervals, should requeue at the shortest", 5*time.Minute, 2*time.Minute, 2*time.Minute),
+	)
+
+	DescribeTable("checking if the enactment applied the policy",
+		func(generation int64, desiredState string, setter func(*nmstatev1alpha1.ConditionList, string), expectedApplied bool) {
+			enactment := nmstatev1alpha1.NewEnactment(nodeName, policy())
+			enactment.Status.PolicyGeneration = generation
+			enactment.Status.DesiredState = nmstatev1alpha1.NewState(desiredState)
+			setter(&enactment.Status.Conditions, "")
+			Expect(appliedEnactment(enactment, policy())).To(Equal(expectedApplied))
+		},
+		Entry("when configured with the current generation, should be applied",
+			int64(2), eth1Up, enactmentconditions.SetSuccess, true),
+		Entry("when configured with a previous generation, should not be applied",
+			int64(1), eth1Up, enactmentconditions.SetSuccess, false),
+		Entry("when configured with a different desired state, should not be applied",
+			int64(2), "interfaces:\n- name: eth1\n  type: ethernet\n  state: down\n", enactmentconditions.SetSuccess, false),
+		Entry("when failed to configure, should not be applied",
+			int64(2), eth1Up, enactmentconditions.SetFailedToConfigure, false),
+	)
+})
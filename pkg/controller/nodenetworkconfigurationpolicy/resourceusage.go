@@ -0,0 +1,22 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus"
+)
+
+// reportResourceUsage reports at the policy enactment the CPU time the
+// desired state apply consumed
+func reportResourceUsage(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, usage *nmstatev1alpha1.ApplyResourceUsage) {
+	enactmentKey := nmstatev1alpha1.EnactmentKey(nodeName, policy.Name)
+	logger := log.WithName("reportResourceUsage").WithValues("enactment", enactmentKey.Name)
+	logger.Info("Desired state apply resource usage", "userCPUTime", usage.UserCPUTime.Duration.String(), "systemCPUTime", usage.SystemCPUTime.Duration.String())
+	err := enactmentstatus.Update(cli, enactmentKey, func(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) {
+		status.ResourceUsage = usage
+	})
+	if err != nil {
+		logger.Error(err, "failed reporting desired state apply resource usage")
+	}
+}
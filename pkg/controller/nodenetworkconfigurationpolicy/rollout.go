@@ -0,0 +1,141 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/policyconditions"
+)
+
+// Interval to check again the rollout by zone of a policy waiting for the
+// previous zones or for the policies with higher priority
+const zoneRolloutRetryInterval = 10 * time.Second
+
+func rolloutResumeIsDifferent(metaOld metav1.Object, metaNew metav1.Object) bool {
+	annotation := nmstatev1alpha1.NodeNetworkConfigurationPolicyRolloutResumeAnnotation
+	return metaOld.GetAnnotations()[annotation] != metaNew.GetAnnotations()[annotation]
+}
+
+func haltsOnFailure(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) bool {
+	return policy.Spec.Rollout != nil && policy.Spec.Rollout.OnFailure == nmstatev1alpha1.RolloutOnFailureHalt
+}
+
+// rolloutFailedNode returns the node, other than this one, the current
+// generation of a policy rolled out with Halt has failed to configure since
+// the rollout was last resumed, the rest of the nodes do not apply it then.
+// The first failed node by name is the one reported.
+func rolloutFailedNode(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) (string, error) {
+	if !haltsOnFailure(policy) {
+		return "", nil
+	}
+	enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+	err := cli.List(context.TODO(), &enactments, client.MatchingLabels{nmstatev1alpha1.EnactmentPolicyLabel: policy.Name})
+	if err != nil {
+		return "", err
+	}
+
+	failedNodes := []string{}
+	for _, enactment := range enactments.Items {
+		node := enactment.Labels[nmstatev1alpha1.EnactmentNodeLabel]
+		if node == nodeName || !policyconditions.RolloutFailure(policy, enactment) {
+			continue
+		}
+		failedNodes = append(failedNodes, node)
+	}
+	if len(failedNodes) == 0 {
+		return "", nil
+	}
+	sort.Strings(failedNodes)
+	return failedNodes[0], nil
+}
+
+// rolloutResumed returns true if the rollout resume annotation of the policy
+// has changed since the enactment of this node took the policy generation
+func (r *ReconcileNodeNetworkConfigurationPolicy) rolloutResumed(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) bool {
+	enactment := nmstatev1alpha1.NodeNetworkConfigurationEnactment{}
+	err := r.client.Get(context.TODO(), nmstatev1alpha1.EnactmentKey(nodeName, policy.Name), &enactment)
+	if err != nil {
+		return false
+	}
+	return enactment.Status.RolloutResume != policyconditions.RolloutResume(policy)
+}
+
+// recordRolloutResume takes the rollout resume annotation of the policy at
+// the enactment of this node without applying the desired state again, the
+// node had already applied the policy generation before the rollout halted
+func (r *ReconcileNodeNetworkConfigurationPolicy) recordRolloutResume(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) error {
+	return enactmentstatus.Update(r.client, nmstatev1alpha1.EnactmentKey(nodeName, policy.Name), func(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) {
+		status.RolloutResume = policyconditions.RolloutResume(policy)
+	})
+}
+
+// bundleRolloutResumed returns true if the rollout of some bundle policy
+// was resumed and every one of them has its generation applied at the node
+func (r *ReconcileNodeNetworkConfigurationPolicy) bundleRolloutResumed(bundlePolicies []bundlePolicy) bool {
+	resumed := false
+	for _, bundlePolicy := range bundlePolicies {
+		if !r.generationApplied(bundlePolicy.policy) {
+			return false
+		}
+		if r.rolloutResumed(bundlePolicy.policy) {
+			resumed = true
+		}
+	}
+	return resumed
+}
+
+// rolloutWaitingFor returns what the rollout by zone of the policy waits for
+// before applying it at the zone of this node, the previous zones of the
+// policy first and then the policies with higher priority at this zone.
+// It's empty if the policy can be applied.
+func rolloutWaitingFor(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) (string, error) {
+	if !policyconditions.ByZone(policy) {
+		return "", nil
+	}
+	nodes := corev1.NodeList{}
+	err := cli.List(context.TODO(), &nodes)
+	if err != nil {
+		return "", err
+	}
+	nodeZones := policyconditions.NodeZones(nodes)
+	zone := nodeZones[nodeName]
+
+	enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+	err = cli.List(context.TODO(), &enactments, client.MatchingLabels{nmstatev1alpha1.EnactmentPolicyLabel: policy.Name})
+	if err != nil {
+		return "", err
+	}
+	for _, previousZone := range policyconditions.RolloutZones(nodeZones, enactments) {
+		if previousZone >= zone {
+			break
+		}
+		if !policyconditions.ZoneAvailable(policy, previousZone, nodeZones, enactments) {
+			return fmt.Sprintf("policy %s at zone %s", policy.Name, policyconditions.ZoneName(previousZone)), nil
+		}
+	}
+
+	policies := nmstatev1alpha1.NodeNetworkConfigurationPolicyList{}
+	err = cli.List(context.TODO(), &policies)
+	if err != nil {
+		return "", err
+	}
+	for _, higherPriorityPolicy := range policyconditions.HigherPriorityPolicies(policy, policies) {
+		higherPriorityEnactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+		err = cli.List(context.TODO(), &higherPriorityEnactments, client.MatchingLabels{nmstatev1alpha1.EnactmentPolicyLabel: higherPriorityPolicy.Name})
+		if err != nil {
+			return "", err
+		}
+		if !policyconditions.ZoneAvailable(higherPriorityPolicy, zone, nodeZones, higherPriorityEnactments) {
+			return fmt.Sprintf("higher priority policy %s at zone %s", higherPriorityPolicy.Name, policyconditions.ZoneName(zone)), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,207 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("Policy rollout on failure", func() {
+	policy := func(onFailure nmstatev1alpha1.RolloutOnFailure) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "eth1-policy", Generation: 2},
+		}
+		if onFailure != "" {
+			policy.Spec.Rollout = &nmstatev1alpha1.PolicyRollout{OnFailure: onFailure}
+		}
+		return policy
+	}
+
+	resumed := func(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, resume string) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		policy.Annotations = map[string]string{nmstatev1alpha1.NodeNetworkConfigurationPolicyRolloutResumeAnnotation: resume}
+		return policy
+	}
+
+	resumedEnactment := func(enactment *nmstatev1alpha1.NodeNetworkConfigurationEnactment, resume string) *nmstatev1alpha1.NodeNetworkConfigurationEnactment {
+		enactment.Status.RolloutResume = resume
+		return enactment
+	}
+
+	enactment := func(node string, generation int64, setter func(*nmstatev1alpha1.ConditionList, string)) *nmstatev1alpha1.NodeNetworkConfigurationEnactment {
+		enactment := nmstatev1alpha1.NewEnactment(node, policy(""))
+		enactment.Status.PolicyGeneration = generation
+		setter(&enactment.Status.Conditions, "")
+		return &enactment
+	}
+
+	DescribeTable("checking the failed nodes",
+		func(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactments []runtime.Object, expectedFailedNode string) {
+			s := scheme.Scheme
+			s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+				&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+				&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+			)
+			cli := fake.NewFakeClientWithScheme(s, enactments...)
+			failedNode, err := rolloutFailedNode(cli, policy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(failedNode).To(Equal(expectedFailedNode))
+		},
+		Entry("with halt and another node failed, should halt",
+			policy(nmstatev1alpha1.RolloutOnFailureHalt),
+			[]runtime.Object{
+				enactment("node03", 2, enactmentconditions.SetFailedToConfigure),
+				enactment("node02", 2, enactmentconditions.SetFailedToConfigure),
+				enactment("node04", 2, enactmentconditions.SetSuccess),
+			},
+			"node02",
+		),
+		Entry("with continue and another node failed, should not halt",
+			policy(nmstatev1alpha1.RolloutOnFailureContinue),
+			[]runtime.Object{enactment("node02", 2, enactmentconditions.SetFailedToConfigure)},
+			"",
+		),
+		Entry("without rollout and another node failed, should not halt",
+			policy(""),
+			[]runtime.Object{enactment("node02", 2, enactmentconditions.SetFailedToConfigure)},
+			"",
+		),
+		Entry("with halt and another node failed a previous generation, should not halt",
+			policy(nmstatev1alpha1.RolloutOnFailureHalt),
+			[]runtime.Object{enactment("node02", 1, enactmentconditions.SetFailedToConfigure)},
+			"",
+		),
+		Entry("with halt and this node failed, should not halt",
+			policy(nmstatev1alpha1.RolloutOnFailureHalt),
+			[]runtime.Object{enactment(nodeName, 2, enactmentconditions.SetFailedToConfigure)},
+			"",
+		),
+		Entry("with halt and another node failed before resuming the rollout, should not halt",
+			resumed(policy(nmstatev1alpha1.RolloutOnFailureHalt), "1"),
+			[]runtime.Object{enactment("node02", 2, enactmentconditions.SetFailedToConfigure)},
+			"",
+		),
+		Entry("with halt and another node failed after resuming the rollout, should halt",
+			resumed(policy(nmstatev1alpha1.RolloutOnFailureHalt), "1"),
+			[]runtime.Object{resumedEnactment(enactment("node02", 2, enactmentconditions.SetFailedToConfigure), "1")},
+			"node02",
+		),
+		Entry("with halt and another node halted, should not halt",
+			policy(nmstatev1alpha1.RolloutOnFailureHalt),
+			[]runtime.Object{enactment("node02", 2, enactmentconditions.SetRolloutHalted)},
+			"",
+		),
+	)
+
+	Context("when the rollout is resumed", func() {
+		var (
+			reconciler ReconcileNodeNetworkConfigurationPolicy
+			halted     nmstatev1alpha1.NodeNetworkConfigurationPolicy
+		)
+		BeforeEach(func() {
+			s := scheme.Scheme
+			s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+				&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+				&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+			)
+			halted = policy(nmstatev1alpha1.RolloutOnFailureHalt)
+			reconciler = ReconcileNodeNetworkConfigurationPolicy{client: fake.NewFakeClientWithScheme(s, enactment(nodeName, 2, enactmentconditions.SetSuccess))}
+		})
+
+		It("should not be resumed without changing the annotation", func() {
+			Expect(reconciler.rolloutResumed(halted)).To(BeFalse())
+		})
+
+		It("should be resumed once, recording the annotation at the enactment", func() {
+			halted = resumed(halted, "1")
+			Expect(reconciler.rolloutResumed(halted)).To(BeTrue())
+			Expect(reconciler.recordRolloutResume(halted)).To(Succeed())
+			Expect(reconciler.rolloutResumed(halted)).To(BeFalse())
+		})
+	})
+
+	Context("when the policy is rolled out by zone", func() {
+		byZone := func(name string, priority int32) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+			return nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1},
+				Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+					Rollout: &nmstatev1alpha1.PolicyRollout{Topology: nmstatev1alpha1.RolloutTopologyZone, Priority: priority},
+				},
+			}
+		}
+		node := func(name string, zone string) *corev1.Node {
+			return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"topology.kubernetes.io/zone": zone}}}
+		}
+		zoneEnactment := func(node string, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, setter func(*nmstatev1alpha1.ConditionList, string)) *nmstatev1alpha1.NodeNetworkConfigurationEnactment {
+			enactment := nmstatev1alpha1.NewEnactment(node, policy)
+			enactment.Status.PolicyGeneration = policy.Generation
+			enactmentconditions.SetMatching(&enactment.Status.Conditions, "")
+			setter(&enactment.Status.Conditions, "")
+			return &enactment
+		}
+		bridges, uplinks := byZone("bridges", 0), byZone("uplinks", 10)
+
+		DescribeTable("checking what the rollout waits for",
+			func(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, enactments func() []runtime.Object, expectedWaitingFor string) {
+				s := scheme.Scheme
+				s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+					&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+					&nmstatev1alpha1.NodeNetworkConfigurationPolicyList{},
+					&nmstatev1alpha1.NodeNetworkConfigurationEnactment{},
+					&nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{},
+				)
+				objects := append([]runtime.Object{node(nodeName, "zone-b"), node("node02", "zone-a"), &bridges, &uplinks}, enactments()...)
+				waitingFor, err := rolloutWaitingFor(fake.NewFakeClientWithScheme(s, objects...), policy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(waitingFor).To(Equal(expectedWaitingFor))
+			},
+			Entry("without topology, should not wait",
+				policy(nmstatev1alpha1.RolloutOnFailureHalt),
+				func() []runtime.Object { return []runtime.Object{} },
+				"",
+			),
+			Entry("with a previous zone in progress, should wait for it",
+				bridges,
+				func() []runtime.Object {
+					return []runtime.Object{zoneEnactment("node02", bridges, enactmentconditions.SetProgressing)}
+				},
+				"policy bridges at zone zone-a",
+			),
+			Entry("with the previous zones available and a higher priority policy in progress at the zone, should wait for it",
+				bridges,
+				func() []runtime.Object {
+					return []runtime.Object{
+						zoneEnactment("node02", bridges, enactmentconditions.SetSuccess),
+						zoneEnactment(nodeName, uplinks, enactmentconditions.SetProgressing),
+					}
+				},
+				"higher priority policy uplinks at zone zone-b",
+			),
+			Entry("with a higher priority policy in progress at another zone, should not wait",
+				bridges,
+				func() []runtime.Object {
+					return []runtime.Object{
+						zoneEnactment("node02", bridges, enactmentconditions.SetSuccess),
+						zoneEnactment("node02", uplinks, enactmentconditions.SetProgressing),
+					}
+				},
+				"",
+			),
+			Entry("with a lower priority policy in progress at the zone, should not wait",
+				uplinks,
+				func() []runtime.Object {
+					return []runtime.Object{zoneEnactment(nodeName, bridges, enactmentconditions.SetProgressing)}
+				},
+				"",
+			),
+		)
+	})
+})
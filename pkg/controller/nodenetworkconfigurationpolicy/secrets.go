@@ -0,0 +1,168 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+var (
+	// Namespace of the secrets referenced by the policies desired state,
+	// the handler one, it cannot read the secrets of other namespaces
+	secretsNamespace = ""
+
+	// Secrets of the handler namespace, shared by the policy and bundle
+	// controllers
+	secretsCache cache.Cache
+)
+
+func init() {
+	secretsNamespace, _ = os.LookupEnv("POD_NAMESPACE")
+}
+
+// secretNotFoundError means a secret, or one of its keys, referenced by the
+// desired state does not exist, the desired state is not applied with empty
+// credentials
+type secretNotFoundError struct {
+	err error
+}
+
+func (e *secretNotFoundError) Error() string {
+	return e.err.Error()
+}
+
+func isSecretNotFound(err error) bool {
+	_, notFound := err.(*secretNotFoundError)
+	return notFound
+}
+
+// secretsSource returns the source of the secrets of the handler namespace,
+// the manager cache watches all the namespaces
+func secretsSource(mgr manager.Manager) (source.Source, error) {
+	if secretsCache == nil {
+		namespacedCache, err := cache.New(mgr.GetConfig(), cache.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper(), Namespace: secretsNamespace})
+		if err != nil {
+			return nil, err
+		}
+		err = mgr.Add(namespacedCache)
+		if err != nil {
+			return nil, err
+		}
+		secretsCache = namespacedCache
+	}
+	informer, err := secretsCache.GetInformer(&corev1.Secret{})
+	if err != nil {
+		return nil, err
+	}
+	return &source.Informer{Informer: informer}, nil
+}
+
+// secretsReader returns the reader of the referenced secrets, the secrets
+// cache once it's watched
+func (r *ReconcileNodeNetworkConfigurationPolicy) secretsReader() client.Reader {
+	if secretsCache != nil {
+		return secretsCache
+	}
+	return r.client
+}
+
+// policiesReferencingSecret returns the policies whose desired state
+// references the secret
+func policiesReferencingSecret(cli client.Client, secretName string) ([]nmstatev1alpha1.NodeNetworkConfigurationPolicy, error) {
+	policyList := nmstatev1alpha1.NodeNetworkConfigurationPolicyList{}
+	err := cli.List(context.TODO(), &policyList)
+	if err != nil {
+		return nil, err
+	}
+	policies := []nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+	for _, policy := range policyList.Items {
+		secretNames, err := nmstate.SecretReferences(policy.Spec.DesiredState)
+		if err != nil {
+			log.Error(err, "failed reading policy secret references", "policy", policy.Name)
+			continue
+		}
+		for _, name := range secretNames {
+			if name == secretName {
+				policies = append(policies, policy)
+				break
+			}
+		}
+	}
+	return policies, nil
+}
+
+// policyRequestsForSecret reconciles the policies referencing the secret so
+// rotated credentials are applied
+func policyRequestsForSecret(cli client.Client) handler.ToRequestsFunc {
+	return func(object handler.MapObject) []reconcile.Request {
+		policies, err := policiesReferencingSecret(cli, object.Meta.GetName())
+		if err != nil {
+			log.Error(err, "failed listing policies referencing secret", "secret", object.Meta.GetName())
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for _, policy := range policies {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: policy.Name}})
+		}
+		return requests
+	}
+}
+
+// bundleRequestsForSecret reconciles the bundles of the policies referencing
+// the secret
+func bundleRequestsForSecret(cli client.Client) handler.ToRequestsFunc {
+	policyRequests := policyRequestsForSecret(cli)
+	bundleRequests := bundleRequestsForPolicy(cli)
+	return func(object handler.MapObject) []reconcile.Request {
+		requests := []reconcile.Request{}
+		for _, policyRequest := range policyRequests(object) {
+			policy := &nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+			policy.Name = policyRequest.Name
+			requests = append(requests, bundleRequests(handler.MapObject{Meta: policy, Object: policy})...)
+		}
+		return requests
+	}
+}
+
+// resolveSecrets returns the desired state with the secret references
+// replaced by the secrets data, it's only used to apply it, the enactment
+// keeps the references. The secrets data is returned too, so it's redacted
+// from the nmstate output and errors.
+func (r *ReconcileNodeNetworkConfigurationPolicy) resolveSecrets(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, []string, error) {
+	secretNames, err := nmstate.SecretReferences(desiredState)
+	if err != nil || len(secretNames) == 0 {
+		return desiredState, nil, err
+	}
+
+	secrets := map[string]map[string][]byte{}
+	for _, name := range secretNames {
+		secret := corev1.Secret{}
+		err := r.secretsReader().Get(context.TODO(), types.NamespacedName{Namespace: secretsNamespace, Name: name}, &secret)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return desiredState, nil, &secretNotFoundError{fmt.Errorf("secret %s not found at namespace %s", name, secretsNamespace)}
+			}
+			return desiredState, nil, err
+		}
+		secrets[name] = secret.Data
+	}
+
+	resolvedState, secretValues, err := nmstate.ResolveSecretReferences(desiredState, secrets)
+	if err != nil {
+		return desiredState, nil, &secretNotFoundError{err}
+	}
+	return resolvedState, secretValues, nil
+}
@@ -0,0 +1,86 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Policy secrets", func() {
+	desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  802.1x:
+    identity: node01
+    password:
+      secretKeyRef:
+        name: eth1-8021x
+        key: password
+`)
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "eth1-8021x", Namespace: secretsNamespace},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	}
+
+	newReconciler := func() ReconcileNodeNetworkConfigurationPolicy {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicyList{},
+		)
+		cli := fake.NewFakeClientWithScheme(s, secret.DeepCopy())
+		return ReconcileNodeNetworkConfigurationPolicy{client: cli, scheme: s}
+	}
+
+	It("should resolve the secrets referenced by the desired state", func() {
+		reconciler := newReconciler()
+		resolvedState, secretValues, err := reconciler.resolveSecrets(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secretValues).To(Equal([]string{"s3cr3t"}))
+		Expect(resolvedState.String()).To(ContainSubstring("password: s3cr3t"))
+	})
+
+	It("should fail with secret not found if the secret is missing", func() {
+		reconciler := newReconciler()
+		_, _, err := reconciler.resolveSecrets(nmstatev1alpha1.NewState(`interfaces:
+- name: wg0
+  type: wireguard
+  state: up
+  wireguard:
+    private-key:
+      secretKeyRef:
+        name: wg0
+        key: private-key
+`))
+		Expect(isSecretNotFound(err)).To(BeTrue())
+	})
+
+	It("should reconcile the policies referencing a changed secret", func() {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicy{},
+			&nmstatev1alpha1.NodeNetworkConfigurationPolicyList{},
+		)
+		referencing := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "eth1-policy"},
+			Spec:       nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{DesiredState: desiredState},
+		}
+		other := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "eth2-policy"},
+			Spec:       nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{DesiredState: nmstatev1alpha1.NewState("interfaces: []\n")},
+		}
+		cli := fake.NewFakeClientWithScheme(s, &referencing, &other)
+
+		requests := policyRequestsForSecret(cli)(handler.MapObject{Meta: &secret, Object: &secret})
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Name).To(Equal("eth1-policy"))
+	})
+})
@@ -0,0 +1,67 @@
+package selectors
+
+import (
+	"strings"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// cloudLabel is a well-known label set by the cloud providers, older
+// clusters only have its deprecated beta version
+type cloudLabel struct {
+	name       string
+	deprecated string
+}
+
+var (
+	instanceTypeLabel = cloudLabel{name: "node.kubernetes.io/instance-type", deprecated: "beta.kubernetes.io/instance-type"}
+	regionLabel       = cloudLabel{name: "topology.kubernetes.io/region", deprecated: "failure-domain.beta.kubernetes.io/region"}
+	zoneLabel         = cloudLabel{name: "topology.kubernetes.io/zone", deprecated: "failure-domain.beta.kubernetes.io/zone"}
+)
+
+func (l cloudLabel) value(labels map[string]string) (string, bool) {
+	if value, hasLabel := labels[l.name]; hasLabel {
+		return value, true
+	}
+	value, hasLabel := labels[l.deprecated]
+	return value, hasLabel
+}
+
+// unmatchingCloudLabels returns the cloud labels of the node not having any
+// of the selector values, with the values separated by commas
+func unmatchingCloudLabels(cloudSelector *nmstatev1alpha1.CloudSelector, labels map[string]string) map[string]string {
+	unmatchingLabels := map[string]string{}
+	if cloudSelector == nil {
+		return unmatchingLabels
+	}
+	for label, values := range map[cloudLabel][]string{
+		instanceTypeLabel: cloudSelector.InstanceTypes,
+		regionLabel:       cloudSelector.Regions,
+		zoneLabel:         cloudSelector.Zones,
+	} {
+		if len(values) == 0 {
+			continue
+		}
+		if value, hasLabel := label.value(labels); hasLabel && containsValue(values, value) {
+			continue
+		}
+		unmatchingLabels[label.name] = strings.Join(values, ",")
+	}
+	return unmatchingLabels
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeZone returns the zone of the node from its labels, empty if it has
+// none
+func NodeZone(labels map[string]string) string {
+	zone, _ := zoneLabel.value(labels)
+	return zone
+}
@@ -0,0 +1,73 @@
+package selectors
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NodeNetworkConfigurationPolicy controller cloud selector", func() {
+	cloudNodeLabels := map[string]string{
+		"node.kubernetes.io/instance-type": "m5.xlarge",
+		"topology.kubernetes.io/region":    "us-east-1",
+		"topology.kubernetes.io/zone":      "us-east-1a",
+	}
+
+	DescribeTable("testing cloud selectors",
+		func(cloudSelector *nmstatev1alpha1.CloudSelector, nodeLabels map[string]string, expectedUnmatchedLabels map[string]string) {
+			node := corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node01", Labels: nodeLabels},
+			}
+			policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+				Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{CloudSelector: cloudSelector},
+			}
+			selectorsRequest := NewFromPolicy(fake.NewFakeClient(&node), policy)
+			unmatchedNodeLabels, err := selectorsRequest.UnmatchedNodeLabels("node01")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(unmatchedNodeLabels).To(Equal(expectedUnmatchedLabels))
+		},
+		Entry("without cloud selector, should match",
+			nil, map[string]string{}, map[string]string{}),
+		Entry("with one of the instance types, should match",
+			&nmstatev1alpha1.CloudSelector{InstanceTypes: []string{"m5.large", "m5.xlarge"}},
+			cloudNodeLabels, map[string]string{}),
+		Entry("with other instance type, should not match",
+			&nmstatev1alpha1.CloudSelector{InstanceTypes: []string{"t3.micro", "t3.small"}},
+			cloudNodeLabels, map[string]string{"node.kubernetes.io/instance-type": "t3.micro,t3.small"}),
+		Entry("with the region but other zone, should not match",
+			&nmstatev1alpha1.CloudSelector{Regions: []string{"us-east-1"}, Zones: []string{"us-east-1b"}},
+			cloudNodeLabels, map[string]string{"topology.kubernetes.io/zone": "us-east-1b"}),
+		Entry("without the cloud labels, should not match",
+			&nmstatev1alpha1.CloudSelector{Regions: []string{"us-east-1"}},
+			map[string]string{}, map[string]string{"topology.kubernetes.io/region": "us-east-1"}),
+		Entry("with the deprecated cloud labels, should match",
+			&nmstatev1alpha1.CloudSelector{InstanceTypes: []string{"m5.xlarge"}, Zones: []string{"us-east-1a"}},
+			map[string]string{
+				"beta.kubernetes.io/instance-type":       "m5.xlarge",
+				"failure-domain.beta.kubernetes.io/zone": "us-east-1a",
+			}, map[string]string{}),
+	)
+
+	It("should report both the node selector and cloud selector unmatching labels", func() {
+		node := corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node01", Labels: cloudNodeLabels},
+		}
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+				NodeSelector:  map[string]string{"node-role.kubernetes.io/edge": ""},
+				CloudSelector: &nmstatev1alpha1.CloudSelector{InstanceTypes: []string{"c5n.9xlarge"}},
+			},
+		}
+		selectorsRequest := NewFromPolicy(fake.NewFakeClient(&node), policy)
+		Expect(selectorsRequest.UnmatchedNodeLabels("node01")).To(Equal(map[string]string{
+			"node-role.kubernetes.io/edge":     "",
+			"node.kubernetes.io/instance-type": "c5n.9xlarge",
+		}))
+	})
+})
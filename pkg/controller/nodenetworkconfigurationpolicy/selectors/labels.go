@@ -26,5 +26,19 @@ func (s *Selectors) UnmatchedNodeLabels(nodeName string) (map[string]string, err
 		return map[string]string{}, err
 	}
 
-	return unmatchingLabels(s.policy.Spec.NodeSelector, node.ObjectMeta.Labels), nil
+	return s.unmatchedLabels(node.ObjectMeta.Labels), nil
+}
+
+// MatchesNode returns true if the node labels match the policy node and
+// cloud selectors
+func (s *Selectors) MatchesNode(node corev1.Node) bool {
+	return len(s.unmatchedLabels(node.ObjectMeta.Labels)) == 0
+}
+
+func (s *Selectors) unmatchedLabels(labels map[string]string) map[string]string {
+	unmatching := unmatchingLabels(s.policy.Spec.NodeSelector, labels)
+	for key, value := range unmatchingCloudLabels(s.policy.Spec.CloudSelector, labels) {
+		unmatching[key] = value
+	}
+	return unmatching
 }
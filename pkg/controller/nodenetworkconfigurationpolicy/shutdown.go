@@ -0,0 +1,89 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+// Time given to the desired state applies in flight to finish when the
+// handler is stopped, it has to be shorter than the pod termination grace
+// period, 30 seconds by default
+const shutdownGracePeriod = 20 * time.Second
+
+// Interval to check again if the applies in flight have finished
+const shutdownPollInterval = 500 * time.Millisecond
+
+// applyTracker keeps the enactments of the desired state applies in flight,
+// so the ones not finishing before the handler stops are not left
+// Progressing forever
+type applyTracker struct {
+	mutex    sync.Mutex
+	stopping bool
+	// enactment conditions of every apply holder in flight
+	applies map[string][]enactmentconditions.EnactmentConditions
+}
+
+var inFlightApplies = &applyTracker{}
+
+// begin tracks the apply of holder, it returns false if the handler is
+// stopping and no new apply has to be started
+func (t *applyTracker) begin(holder string, conditions ...enactmentconditions.EnactmentConditions) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.stopping {
+		return false
+	}
+	if t.applies == nil {
+		t.applies = map[string][]enactmentconditions.EnactmentConditions{}
+	}
+	t.applies[holder] = conditions
+	return true
+}
+
+// end stops tracking the apply of holder
+func (t *applyTracker) end(holder string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.applies, holder)
+}
+
+// stop refuses new applies and waits up to gracePeriod for the ones in
+// flight to finish, it returns the enactment conditions of the ones still
+// applying
+func (t *applyTracker) stop(gracePeriod time.Duration, pollInterval time.Duration) map[string][]enactmentconditions.EnactmentConditions {
+	t.mutex.Lock()
+	t.stopping = true
+	t.mutex.Unlock()
+
+	wait.PollImmediate(pollInterval, gracePeriod, func() (bool, error) {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		return len(t.applies) == 0, nil
+	})
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	interrupted := map[string][]enactmentconditions.EnactmentConditions{}
+	for holder, conditions := range t.applies {
+		interrupted[holder] = conditions
+	}
+	return interrupted
+}
+
+// Shutdown gives the desired state applies in flight a chance to finish
+// before the handler stops, the enactments of the ones that don't are
+// marked as Interrupted so they are not left Progressing, the next handler
+// at the node applies them again.
+func Shutdown() {
+	log.Info("Handler stopping, waiting for the desired state applies in flight to finish")
+	for holder, conditions := range inFlightApplies.stop(shutdownGracePeriod, shutdownPollInterval) {
+		log.Info("Desired state apply interrupted by handler stop", "holder", holder)
+		for _, enactmentConditions := range conditions {
+			enactmentConditions.NotifyInterrupted()
+		}
+	}
+}
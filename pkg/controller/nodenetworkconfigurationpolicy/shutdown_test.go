@@ -0,0 +1,51 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+)
+
+var _ = Describe("Handler shutdown", func() {
+	var tracker *applyTracker
+
+	BeforeEach(func() {
+		tracker = &applyTracker{}
+	})
+
+	conditions := enactmentconditions.New(nil, types.NamespacedName{Name: "node01.policy1"})
+
+	It("should not interrupt anything without applies in flight", func() {
+		Expect(tracker.begin("policy policy1", conditions)).To(BeTrue())
+		tracker.end("policy policy1")
+		Expect(tracker.stop(time.Second, 10*time.Millisecond)).To(BeEmpty())
+	})
+
+	It("should wait for the applies in flight to finish", func() {
+		Expect(tracker.begin("policy policy1", conditions)).To(BeTrue())
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			tracker.end("policy policy1")
+		}()
+		Expect(tracker.stop(time.Second, 10*time.Millisecond)).To(BeEmpty())
+	})
+
+	It("should return the applies not finishing within the grace period", func() {
+		Expect(tracker.begin("policy policy1", conditions)).To(BeTrue())
+		Expect(tracker.begin("bundle bundle1", conditions, conditions)).To(BeTrue())
+		tracker.end("policy policy1")
+		interrupted := tracker.stop(50*time.Millisecond, 10*time.Millisecond)
+		Expect(interrupted).To(HaveLen(1))
+		Expect(interrupted["bundle bundle1"]).To(HaveLen(2))
+	})
+
+	It("should refuse new applies once stopping", func() {
+		tracker.stop(time.Second, 10*time.Millisecond)
+		Expect(tracker.begin("policy policy1", conditions)).To(BeFalse())
+	})
+})
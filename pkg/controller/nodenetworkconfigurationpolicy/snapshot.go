@@ -0,0 +1,33 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// reportPreApplySnapshot reports at the policies enactments the node current
+// state right before their desired state is applied, applies failing to take
+// it go on without it
+func reportPreApplySnapshot(cli client.Client, policies ...nmstatev1alpha1.NodeNetworkConfigurationPolicy) {
+	logger := log.WithName("reportPreApplySnapshot")
+	snapshot, err := nmstate.PreApplySnapshot()
+	if err != nil {
+		logger.Error(err, "failed taking pre apply snapshot, not reporting it")
+		return
+	}
+	if snapshot.Omitted {
+		logger.Info("pre apply snapshot exceeds the size bound, it's omitted", "size", snapshot.Size)
+	}
+	for _, policy := range policies {
+		enactmentKey := nmstatev1alpha1.EnactmentKey(nodeName, policy.Name)
+		err = enactmentstatus.Update(cli, enactmentKey, func(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) {
+			status.PreApplySnapshot = snapshot.DeepCopy()
+		})
+		if err != nil {
+			logger.Error(err, "failed reporting pre apply snapshot", "enactment", enactmentKey.Name)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+func activateIsDifferent(metaOld metav1.Object, metaNew metav1.Object) bool {
+	annotation := nmstatev1alpha1.NodeNetworkConfigurationPolicyActivateAnnotation
+	return metaOld.GetAnnotations()[annotation] != metaNew.GetAnnotations()[annotation]
+}
+
+// isStaged returns true if the policy desired state has to be held staged
+// at the node, it's activated by setting the activate annotation to the
+// policy generation so every spec change is staged again
+func isStaged(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) bool {
+	if !policy.Spec.Stage {
+		return false
+	}
+	return policy.Annotations[nmstatev1alpha1.NodeNetworkConfigurationPolicyActivateAnnotation] != strconv.FormatInt(policy.Generation, 10)
+}
+
+// stagedBundlePolicy returns the first staged policy of the bundle, if any,
+// the bundle is not applied until all of them are activated
+func stagedBundlePolicy(policies []nmstatev1alpha1.NodeNetworkConfigurationPolicy) *nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+	for i := range policies {
+		if isStaged(policies[i]) {
+			return &policies[i]
+		}
+	}
+	return nil
+}
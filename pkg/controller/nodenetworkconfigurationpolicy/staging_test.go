@@ -0,0 +1,95 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Staged policies", func() {
+	policy := func(stage bool, activate string) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{Stage: stage},
+		}
+		if activate != "" {
+			policy.Annotations = map[string]string{nmstatev1alpha1.NodeNetworkConfigurationPolicyActivateAnnotation: activate}
+		}
+		return policy
+	}
+
+	DescribeTable("holding the desired state staged",
+		func(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, expectedStaged bool) {
+			Expect(isStaged(policy)).To(Equal(expectedStaged))
+		},
+		Entry("without staging", policy(false, ""), false),
+		Entry("when not activated yet", policy(true, ""), true),
+		Entry("when a previous generation was activated", policy(true, "1"), true),
+		Entry("when the generation is activated", policy(true, "2"), false),
+	)
+
+	It("should reconcile the policy when the activate annotation changes", func() {
+		Expect(activateIsDifferent(&metav1.ObjectMeta{}, &metav1.ObjectMeta{Annotations: map[string]string{
+			nmstatev1alpha1.NodeNetworkConfigurationPolicyActivateAnnotation: "2",
+		}})).To(BeTrue())
+		Expect(activateIsDifferent(&metav1.ObjectMeta{}, &metav1.ObjectMeta{})).To(BeFalse())
+	})
+
+	It("should keep the applied desired state at the enactment while staged", func() {
+		status := nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus{
+			DesiredState:      nmstatev1alpha1.NewState("interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n"),
+			IgnoredInterfaces: []string{"eth2"},
+			PolicyGeneration:  1,
+		}
+		stagedPolicy := policy(true, "1")
+		stagedPolicy.Spec.DesiredState = nmstatev1alpha1.NewState("interfaces:\n- name: eth1\n  type: ethernet\n  state: down\n")
+		initializeEnactmentStatus(&status, stagedPolicy, isStaged(stagedPolicy), []string{}, []nmstatev1alpha1.InterfaceOverride{})
+		Expect(status.DesiredState.String()).To(ContainSubstring("state: up"))
+		Expect(status.IgnoredInterfaces).To(Equal([]string{"eth2"}))
+		Expect(status.PolicyGeneration).To(Equal(int64(2)))
+
+		stagedPolicy.Annotations[nmstatev1alpha1.NodeNetworkConfigurationPolicyActivateAnnotation] = "2"
+		initializeEnactmentStatus(&status, stagedPolicy, isStaged(stagedPolicy), []string{}, []nmstatev1alpha1.InterfaceOverride{})
+		Expect(status.DesiredState).To(Equal(stagedPolicy.Spec.DesiredState))
+		Expect(status.IgnoredInterfaces).To(BeEmpty())
+	})
+
+	Context("when a policy of a bundle is staged", func() {
+		var (
+			stagedPolicy   nmstatev1alpha1.NodeNetworkConfigurationPolicy
+			bundlePolicies []nmstatev1alpha1.NodeNetworkConfigurationPolicy
+		)
+		BeforeEach(func() {
+			stagedPolicy = policy(true, "1")
+			stagedPolicy.Name = "vlan200"
+			activePolicy := policy(false, "")
+			activePolicy.Name = "bond0"
+			bundlePolicies = []nmstatev1alpha1.NodeNetworkConfigurationPolicy{activePolicy, stagedPolicy}
+		})
+
+		It("should hold the whole bundle until the staged policy is activated", func() {
+			held := stagedBundlePolicy(bundlePolicies)
+			Expect(held).ToNot(BeNil())
+			Expect(held.Name).To(Equal("vlan200"))
+
+			bundlePolicies[1].Annotations[nmstatev1alpha1.NodeNetworkConfigurationPolicyActivateAnnotation] = "2"
+			Expect(stagedBundlePolicy(bundlePolicies)).To(BeNil())
+		})
+
+		It("should keep the applied desired state at the enactments of the rest of bundle policies", func() {
+			status := nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus{
+				DesiredState:     nmstatev1alpha1.NewState("interfaces:\n- name: bond0\n  type: bond\n  state: up\n"),
+				PolicyGeneration: 1,
+			}
+			activePolicy := bundlePolicies[0]
+			activePolicy.Spec.DesiredState = nmstatev1alpha1.NewState("interfaces:\n- name: bond0\n  type: bond\n  state: down\n")
+			initializeEnactmentStatus(&status, activePolicy, stagedBundlePolicy(bundlePolicies) != nil, []string{}, []nmstatev1alpha1.InterfaceOverride{})
+			Expect(status.DesiredState.String()).To(ContainSubstring("state: up"))
+			Expect(status.PolicyGeneration).To(Equal(int64(2)))
+		})
+	})
+})
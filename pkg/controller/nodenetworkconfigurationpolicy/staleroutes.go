@@ -0,0 +1,27 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// reportRemovedStaleRoutes reports at the policy enactment the stale routes
+// the apply removed from the route tables, if the policy cleans them up
+func reportRemovedStaleRoutes(cli client.Client, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, routeTableCleanup *nmstate.RouteTableCleanup) {
+	enactmentKey := nmstatev1alpha1.EnactmentKey(nodeName, policy.Name)
+	logger := log.WithName("reportRemovedStaleRoutes").WithValues("enactment", enactmentKey.Name)
+	var removedRoutes []string
+	if policy.Spec.RouteTableCleanup && routeTableCleanup != nil && len(routeTableCleanup.RemovedRoutes) > 0 {
+		removedRoutes = routeTableCleanup.RemovedRoutes
+		logger.Info("Removed stale routes from the policy route tables", "routes", removedRoutes)
+	}
+	err := enactmentstatus.Update(cli, enactmentKey, func(status *nmstatev1alpha1.NodeNetworkConfigurationEnactmentStatus) {
+		status.RemovedStaleRoutes = removedRoutes
+	})
+	if err != nil {
+		logger.Error(err, "failed reporting removed stale routes")
+	}
+}
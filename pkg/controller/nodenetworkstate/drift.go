@@ -0,0 +1,77 @@
+package nodenetworkstate
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus"
+	enactmentconditions "github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/enactmentstatus/conditions"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/policyconditions"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// enactmentDrift compares the desired state applied, or audited, by the
+// enactment with the node current state
+func enactmentDrift(enactment nmstatev1alpha1.NodeNetworkConfigurationEnactment, policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, currentState nmstatev1alpha1.State) ([]string, error) {
+	drift, err := nmstate.Drift(enactment.Status.DesiredState, currentState, policy.Spec.DriftIgnore)
+	if err != nil || len(drift) == 0 {
+		return nil, err
+	}
+	return drift, nil
+}
+
+// updateDrift refreshes the drift of the node enactments with the current
+// state just reported at the node network state, enactments not
+// successfully configured have no drift. For audit policies the
+// compliance is refreshed too.
+func (r *ReconcileNodeNetworkState) updateDrift(nodeNetworkState nmstatev1alpha1.NodeNetworkState) error {
+	enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+	err := r.client.List(context.TODO(), &enactments, client.MatchingLabels{nmstatev1alpha1.EnactmentNodeLabel: nodeNetworkState.Name})
+	if err != nil {
+		return errors.Wrap(err, "failed listing node enactments")
+	}
+
+	for _, enactment := range enactments.Items {
+		if !enactment.Status.Conditions.IsAvailable() {
+			continue
+		}
+		policyKey := types.NamespacedName{Name: enactment.Labels[nmstatev1alpha1.EnactmentPolicyLabel]}
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		err := r.client.Get(context.TODO(), policyKey, &policy)
+		if err != nil {
+			log.Error(err, "failed getting enactment policy", "enactment", enactment.Name)
+			continue
+		}
+
+		drift, err := enactmentDrift(enactment, policy, nodeNetworkState.Status.CurrentState)
+		if err != nil {
+			log.Error(err, "failed detecting enactment drift", "enactment", enactment.Name)
+			continue
+		}
+		if reflect.DeepEqual(drift, enactment.Status.Drift) {
+			continue
+		}
+
+		log.Info("enactment drift changed", "enactment", enactment.Name, "drift", drift)
+		if policy.Spec.Audit {
+			enactmentConditions := enactmentconditions.New(r.client, types.NamespacedName{Name: enactment.Name})
+			enactmentConditions.NotifyAudited(drift)
+			err = policyconditions.Update(r.client, policyKey)
+			if err != nil {
+				return errors.Wrapf(err, "failed updating policy %s compliance", policy.Name)
+			}
+			continue
+		}
+		err = enactmentstatus.UpdateDrift(r.client, types.NamespacedName{Name: enactment.Name}, enactment.Status.DesiredState, drift)
+		if err != nil {
+			return errors.Wrapf(err, "failed updating enactment %s drift", enactment.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package nodenetworkstate
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const (
+	bondHealthy  = "healthy"
+	bondDegraded = "degraded"
+	bondDown     = "down"
+)
+
+var (
+	nodeInterfaces = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubernetes_nmstate_node_interfaces",
+			Help: "Number of interfaces reported at the node network state per type and state",
+		},
+		[]string{"node", "type", "state"},
+	)
+	nodeBonds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubernetes_nmstate_node_bonds",
+			Help: "Number of bonds reported at the node network state per health, they are healthy with all the slaves up, degraded with some of them down and down without slaves up",
+		},
+		[]string{"node", "health"},
+	)
+	handlerUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubernetes_nmstate_handler_up",
+			Help: "Presence of the handler at the node, 1 while it reports the node network state and 0 if it fails reporting it",
+		},
+		[]string{"node"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(nodeInterfaces, nodeBonds, handlerUp)
+}
+
+// reportHandlerUp sets the handler presence gauge of the node, the nodes
+// without handler have no series
+func reportHandlerUp(node string, up bool) {
+	value := 0.0
+	if up {
+		value = 1
+	}
+	handlerUp.WithLabelValues(node).Set(value)
+}
+
+func bondHealth(bond nmstatev1alpha1.BondStatus) string {
+	up := 0
+	for _, slave := range bond.Slaves {
+		if slave.MIIStatus == "up" {
+			up++
+		}
+	}
+	if up == 0 {
+		return bondDown
+	} else if up < len(bond.Slaves) {
+		return bondDegraded
+	}
+	return bondHealthy
+}
+
+// reportMetrics replaces the node network state gauges with the interfaces
+// and bonds of the given state, every handler only reports its own node so
+// the fleet view is the aggregation of all of them
+func reportMetrics(nodeNetworkState nmstatev1alpha1.NodeNetworkState) {
+	nodeInterfaces.Reset()
+	nodeBonds.Reset()
+
+	currentStateJSON, err := yaml.YAMLToJSON(nodeNetworkState.Status.CurrentState.Raw)
+	if err != nil {
+		log.Error(err, "failed converting current state to JSON, not reporting interfaces metrics")
+	} else {
+		for _, iface := range gjson.ParseBytes(currentStateJSON).Get("interfaces").Array() {
+			nodeInterfaces.WithLabelValues(nodeNetworkState.Name, iface.Get("type").String(), iface.Get("state").String()).Inc()
+		}
+	}
+
+	for _, health := range []string{bondHealthy, bondDegraded, bondDown} {
+		nodeBonds.WithLabelValues(nodeNetworkState.Name, health).Set(0)
+	}
+	for _, bond := range nodeNetworkState.Status.Bonds {
+		nodeBonds.WithLabelValues(nodeNetworkState.Name, bondHealth(bond)).Inc()
+	}
+}
@@ -0,0 +1,128 @@
+package nodenetworkstate
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NodeNetworkState metrics", func() {
+	// gauges collects the values of the gauge vector by the given labels
+	// joined with "/", without creating the missing ones
+	gauges := func(vector *prometheus.GaugeVec, labelNames ...string) map[string]float64 {
+		metrics := make(chan prometheus.Metric, 100)
+		vector.Collect(metrics)
+		close(metrics)
+		values := map[string]float64{}
+		for metric := range metrics {
+			written := dto.Metric{}
+			Expect(metric.Write(&written)).To(Succeed())
+			labels := map[string]string{}
+			for _, label := range written.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			key := ""
+			for i, name := range labelNames {
+				if i > 0 {
+					key += "/"
+				}
+				key += labels[name]
+			}
+			values[key] = written.GetGauge().GetValue()
+		}
+		return values
+	}
+
+	newNodeNetworkState := func(currentState string, bonds ...nmstatev1alpha1.BondStatus) nmstatev1alpha1.NodeNetworkState {
+		return nmstatev1alpha1.NodeNetworkState{
+			ObjectMeta: metav1.ObjectMeta{Name: "node01"},
+			Status: nmstatev1alpha1.NodeNetworkStateStatus{
+				CurrentState: nmstatev1alpha1.NewState(currentState),
+				Bonds:        bonds,
+			},
+		}
+	}
+
+	newBond := func(slavesMIIStatus ...string) nmstatev1alpha1.BondStatus {
+		bond := nmstatev1alpha1.BondStatus{Name: "bond0"}
+		for _, miiStatus := range slavesMIIStatus {
+			bond.Slaves = append(bond.Slaves, nmstatev1alpha1.BondSlaveStatus{MIIStatus: miiStatus})
+		}
+		return bond
+	}
+
+	It("should count the interfaces per type and state", func() {
+		reportMetrics(newNodeNetworkState(`interfaces:
+- name: eth0
+  type: ethernet
+  state: up
+- name: eth1
+  type: ethernet
+  state: up
+- name: eth2
+  type: ethernet
+  state: down
+- name: br1
+  type: linux-bridge
+  state: up
+`))
+		Expect(gauges(nodeInterfaces, "node", "type", "state")).To(Equal(map[string]float64{
+			"node01/ethernet/up":     2,
+			"node01/ethernet/down":   1,
+			"node01/linux-bridge/up": 1,
+		}))
+	})
+
+	It("should drop the interfaces the node does not report anymore", func() {
+		reportMetrics(newNodeNetworkState("interfaces:\n- name: br1\n  type: linux-bridge\n  state: up\n"))
+		reportMetrics(newNodeNetworkState("interfaces:\n- name: eth0\n  type: ethernet\n  state: up\n"))
+		Expect(gauges(nodeInterfaces, "node", "type", "state")).To(Equal(map[string]float64{
+			"node01/ethernet/up": 1,
+		}))
+	})
+
+	DescribeTable("bonds health",
+		func(bonds []nmstatev1alpha1.BondStatus, expected map[string]float64) {
+			reportMetrics(newNodeNetworkState("interfaces: []\n", bonds...))
+			Expect(gauges(nodeBonds, "node", "health")).To(Equal(expected))
+		},
+		Entry("without bonds all the health gauges are zero", nil, map[string]float64{
+			"node01/healthy":  0,
+			"node01/degraded": 0,
+			"node01/down":     0,
+		}),
+		Entry("with all the slaves up the bond is healthy", []nmstatev1alpha1.BondStatus{newBond("up", "up")}, map[string]float64{
+			"node01/healthy":  1,
+			"node01/degraded": 0,
+			"node01/down":     0,
+		}),
+		Entry("with some slaves down the bond is degraded", []nmstatev1alpha1.BondStatus{newBond("up", "down"), newBond("up")}, map[string]float64{
+			"node01/healthy":  1,
+			"node01/degraded": 1,
+			"node01/down":     0,
+		}),
+		Entry("without slaves up the bond is down", []nmstatev1alpha1.BondStatus{newBond("down", "down"), newBond()}, map[string]float64{
+			"node01/healthy":  0,
+			"node01/degraded": 0,
+			"node01/down":     2,
+		}),
+	)
+
+	It("should report the handler up while it reports the node network state", func() {
+		handlerUp.Reset()
+		Expect(gauges(handlerUp, "node")).To(BeEmpty())
+
+		reportHandlerUp("node01", true)
+		Expect(gauges(handlerUp, "node")).To(Equal(map[string]float64{"node01": 1}))
+
+		reportHandlerUp("node01", false)
+		Expect(gauges(handlerUp, "node")).To(Equal(map[string]float64{"node01": 0}))
+	})
+})
@@ -49,7 +49,7 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileNodeNetworkState{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return &ReconcileNodeNetworkState{client: mgr.GetClient(), scheme: mgr.GetScheme(), limiter: newUpdateLimiter(nodeNetworkStateMinUpdateInterval)}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -91,8 +91,9 @@ var _ reconcile.Reconciler = &ReconcileNodeNetworkState{}
 type ReconcileNodeNetworkState struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client client.Client
-	scheme *runtime.Scheme
+	client  client.Client
+	scheme  *runtime.Scheme
+	limiter *updateLimiter
 }
 
 // Reconcile reads that state of the cluster for a NodeNetworkState object and makes changes based on the state read
@@ -105,9 +106,16 @@ type ReconcileNodeNetworkState struct {
 func (r *ReconcileNodeNetworkState) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.V(1).Info("Reconciling NodeNetworkState")
+
+	// The delayed request is the only one kept at the queue, so once the
+	// interval is over the latest state is published
+	if delay := r.limiter.delay(request.Name, time.Now()); delay > 0 {
+		reqLogger.V(1).Info("NodeNetworkState updated recently, delaying update", "delay", delay)
+		return reconcile.Result{RequeueAfter: delay}, nil
+	}
+	instance := &nmstatev1alpha1.NodeNetworkState{}
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		// Fetch the NodeNetworkState instance
-		instance := &nmstatev1alpha1.NodeNetworkState{}
 		err := r.client.Get(context.TODO(), request.NamespacedName, instance)
 		if err != nil {
 			return err
@@ -126,7 +134,16 @@ func (r *ReconcileNodeNetworkState) Reconcile(request reconcile.Request) (reconc
 			// Return and don't requeue
 			return reconcile.Result{}, nil
 		}
+		reportHandlerUp(request.Name, false)
 		return reconcile.Result{}, err
 	}
+	r.limiter.updated(request.Name, time.Now())
+
+	reportHandlerUp(request.Name, true)
+	reportMetrics(*instance)
+	err = r.updateDrift(*instance)
+	if err != nil {
+		reqLogger.Error(err, "failed updating enactments drift")
+	}
 	return reconcile.Result{RequeueAfter: nodenetworkstateRefresh}, nil
 }
@@ -0,0 +1,16 @@
+package nodenetworkstate
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/onsi/ginkgo/reporters"
+)
+
+func TestUnit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter("junit.controller-nodenetworkstate-nodenetworkstate_suite_test.xml")
+	RunSpecsWithDefaultAndCustomReporters(t, "NodeNetworkState Controller Test Suite", []Reporter{junitReporter})
+}
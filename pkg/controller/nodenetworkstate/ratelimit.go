@@ -0,0 +1,60 @@
+package nodenetworkstate
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// Minimum time between consecutive updates of the NodeNetworkState of a
+	// node, the reconciles in the meantime, like the ones of a flapping
+	// link, are coalesced into a single update after it
+	nodeNetworkStateMinUpdateInterval = 1 * time.Second
+)
+
+func init() {
+	interval, isSet := os.LookupEnv("NODE_NETWORK_STATE_MIN_UPDATE_INTERVAL")
+	if !isSet || interval == "" {
+		return
+	}
+	var err error
+	nodeNetworkStateMinUpdateInterval, err = time.ParseDuration(interval)
+	if err != nil {
+		panic(fmt.Sprintf("Failed while converting evnironment variable to duration: %v", err))
+	}
+}
+
+// updateLimiter keeps the time of the last update of every NodeNetworkState
+type updateLimiter struct {
+	mutex       sync.Mutex
+	interval    time.Duration
+	lastUpdates map[string]time.Time
+}
+
+func newUpdateLimiter(interval time.Duration) *updateLimiter {
+	return &updateLimiter{interval: interval, lastUpdates: map[string]time.Time{}}
+}
+
+// delay returns how long the NodeNetworkState update has to wait to keep the
+// minimum interval since the previous one, zero if it can be updated now
+func (l *updateLimiter) delay(name string, now time.Time) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	lastUpdate, found := l.lastUpdates[name]
+	if !found {
+		return 0
+	}
+	elapsed := now.Sub(lastUpdate)
+	if elapsed >= l.interval {
+		return 0
+	}
+	return l.interval - elapsed
+}
+
+func (l *updateLimiter) updated(name string, now time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.lastUpdates[name] = now
+}
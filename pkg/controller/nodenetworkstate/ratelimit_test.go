@@ -0,0 +1,40 @@
+package nodenetworkstate
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NodeNetworkState update limiter", func() {
+	now := time.Date(2020, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	It("should not delay the first update of a node", func() {
+		limiter := newUpdateLimiter(time.Second)
+		Expect(limiter.delay("node01", now)).To(BeZero())
+	})
+
+	It("should delay the updates until the interval since the previous one is over", func() {
+		limiter := newUpdateLimiter(time.Second)
+		limiter.updated("node01", now)
+		Expect(limiter.delay("node01", now)).To(Equal(time.Second))
+		Expect(limiter.delay("node01", now.Add(300*time.Millisecond))).To(Equal(700 * time.Millisecond))
+		Expect(limiter.delay("node01", now.Add(time.Second))).To(BeZero())
+		Expect(limiter.delay("node01", now.Add(time.Minute))).To(BeZero())
+	})
+
+	It("should restart the interval at every update", func() {
+		limiter := newUpdateLimiter(time.Second)
+		limiter.updated("node01", now)
+		limiter.updated("node01", now.Add(2*time.Second))
+		Expect(limiter.delay("node01", now.Add(2500*time.Millisecond))).To(Equal(500 * time.Millisecond))
+		Expect(limiter.delay("node01", now.Add(3*time.Second))).To(BeZero())
+	})
+
+	It("should keep the interval of every node apart", func() {
+		limiter := newUpdateLimiter(time.Second)
+		limiter.updated("node01", now)
+		Expect(limiter.delay("node02", now)).To(BeZero())
+	})
+})
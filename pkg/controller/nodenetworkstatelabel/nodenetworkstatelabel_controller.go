@@ -0,0 +1,237 @@
+package nodenetworkstatelabel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+var (
+	log = logf.Log.WithName("controller_nodenetworkstatelabel")
+)
+
+// Add creates a new NodeNetworkStateLabel Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileNodeNetworkStateLabel{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("nodenetworkstatelabel-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	forThisNode := predicate.Funcs{
+		CreateFunc: func(createEvent event.CreateEvent) bool {
+			return nmstate.EventIsForThisNode(createEvent.Meta)
+		},
+		DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
+			return false
+		},
+		UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+			return nmstate.EventIsForThisNode(updateEvent.MetaNew)
+		},
+		GenericFunc: func(genericEvent event.GenericEvent) bool {
+			return nmstate.EventIsForThisNode(genericEvent.Meta)
+		},
+	}
+	// Watch for changes to the NodeNetworkState of this node, the labels
+	// follow its current state
+	err = c.Watch(&source.Kind{Type: &nmstatev1alpha1.NodeNetworkState{}}, &handler.EnqueueRequestForObject{}, forThisNode)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the NodeNetworkStateLabels to label this node
+	// again, also when they are deleted so their labels are removed
+	err = c.Watch(&source.Kind{Type: &nmstatev1alpha1.NodeNetworkStateLabel{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(thisNodeRequest)})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func thisNodeRequest(handler.MapObject) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: os.Getenv("NODE_NAME")}}}
+}
+
+// blank assignment to verify that ReconcileNodeNetworkStateLabel implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileNodeNetworkStateLabel{}
+
+// ReconcileNodeNetworkStateLabel labels the node of the handler after its
+// NodeNetworkState current state
+type ReconcileNodeNetworkStateLabel struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// Reconcile derives the node labels configured by the NodeNetworkStateLabels
+// from the node NodeNetworkState and sets them at the node
+// Note:
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileNodeNetworkStateLabel) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.V(1).Info("Reconciling NodeNetworkStateLabel")
+
+	nodeNetworkState, err := nmstate.GetNodeNetworkState(r.client, request.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The node is labeled once its state is reported
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	stateLabels := nmstatev1alpha1.NodeNetworkStateLabelList{}
+	err = r.client.List(context.TODO(), &stateLabels)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("error listing NodeNetworkStateLabels: %v", err)
+	}
+
+	labels, err := labelsFromState(stateLabels.Items, nodeNetworkState.Status.CurrentState)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	node := corev1.Node{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: request.Name}, &node)
+	if err == nil {
+		patch, changed := stateLabelsPatch(&node, labels)
+		if !changed {
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Info("Updating node labels derived from the NodeNetworkState", "labels", labels)
+		err = r.client.Patch(context.TODO(), &node, patch)
+	}
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("error updating node labels: %v", err)
+	}
+	return reconcile.Result{}, nil
+}
+
+// labelsFromState returns the labels of the NodeNetworkStateLabels whose
+// path is found at the current state. The ones with invalid keys or values
+// are left out, the first one by name is taken if several have the same
+// key.
+func labelsFromState(stateLabels []nmstatev1alpha1.NodeNetworkStateLabel, currentState nmstatev1alpha1.State) (map[string]string, error) {
+	labels := map[string]string{}
+	currentStateJSON, err := yaml.YAMLToJSON([]byte(currentState.Raw))
+	if err != nil {
+		return labels, fmt.Errorf("error converting currentState to JSON: %v", err)
+	}
+
+	sort.Slice(stateLabels, func(i, j int) bool { return stateLabels[i].Name < stateLabels[j].Name })
+	for _, stateLabel := range stateLabels {
+		logger := log.WithValues("NodeNetworkStateLabel", stateLabel.Name)
+		key := stateLabel.Spec.Key
+		if !strings.HasPrefix(key, nmstatev1alpha1.NodeNetworkStateLabelPrefix) || len(validation.IsQualifiedName(key)) > 0 {
+			logger.Info(fmt.Sprintf("Ignoring invalid label key %q, it has to be a label key under the %s prefix", key, nmstatev1alpha1.NodeNetworkStateLabelPrefix))
+			continue
+		}
+		if _, found := labels[key]; found {
+			logger.Info(fmt.Sprintf("Ignoring label key %q, it's already derived by another NodeNetworkStateLabel", key))
+			continue
+		}
+		result := gjson.GetBytes(currentStateJSON, stateLabel.Spec.Path)
+		if !result.Exists() {
+			continue
+		}
+		value := stateLabel.Spec.Value
+		if value == "" {
+			value = result.String()
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			logger.Info(fmt.Sprintf("Ignoring invalid label %s value %q: %s", key, value, strings.Join(errs, ", ")))
+			continue
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// stateLabelsPatch sets the labels at the node and returns the merge patch
+// of the changed labels and node state labels annotation, so the rest of
+// the node is not written and the handler only needs to patch it
+func stateLabelsPatch(node *corev1.Node, labels map[string]string) (client.Patch, bool) {
+	patch := client.MergeFrom(node.DeepCopy())
+	return patch, setStateLabels(node, labels)
+}
+
+// setStateLabels sets the labels at the node and removes the ones set
+// before that are not derived anymore, the keys are kept at the node state
+// labels annotation. It returns true if the node changed.
+func setStateLabels(node *corev1.Node, labels map[string]string) bool {
+	changed := false
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	for _, key := range strings.Split(node.Annotations[nmstatev1alpha1.NodeNetworkStateLabelsAnnotation], ",") {
+		if _, found := labels[key]; found || key == "" {
+			continue
+		}
+		if _, found := node.Labels[key]; found {
+			delete(node.Labels, key)
+			changed = true
+		}
+	}
+
+	keys := []string{}
+	for key, value := range labels {
+		keys = append(keys, key)
+		if current, found := node.Labels[key]; !found || current != value {
+			node.Labels[key] = value
+			changed = true
+		}
+	}
+	sort.Strings(keys)
+
+	annotation := strings.Join(keys, ",")
+	if node.Annotations[nmstatev1alpha1.NodeNetworkStateLabelsAnnotation] != annotation {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		if annotation == "" {
+			delete(node.Annotations, nmstatev1alpha1.NodeNetworkStateLabelsAnnotation)
+		} else {
+			node.Annotations[nmstatev1alpha1.NodeNetworkStateLabelsAnnotation] = annotation
+		}
+		changed = true
+	}
+	return changed
+}
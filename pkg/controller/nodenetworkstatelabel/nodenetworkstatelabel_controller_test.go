@@ -0,0 +1,171 @@
+package nodenetworkstatelabel
+
+import (
+	"context"
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// mergePatchClient applies the node merge patches to a new node, the fake
+// client unmarshals them into the stored one so it cannot remove labels
+type mergePatchClient struct {
+	client.Client
+}
+
+func (c mergePatchClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	node := corev1.Node{}
+	err = c.Client.Get(ctx, types.NamespacedName{Name: obj.(*corev1.Node).Name}, &node)
+	if err != nil {
+		return err
+	}
+	nodeJSON, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	patchedJSON, err := jsonpatch.MergePatch(nodeJSON, data)
+	if err != nil {
+		return err
+	}
+	patched := corev1.Node{}
+	err = json.Unmarshal(patchedJSON, &patched)
+	if err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, &patched)
+}
+
+var _ = Describe("NodeNetworkStateLabel controller reconcile", func() {
+	const nodeName = "node01"
+
+	stateLabel := func(name string, key string, path string, value string) *nmstatev1alpha1.NodeNetworkStateLabel {
+		return &nmstatev1alpha1.NodeNetworkStateLabel{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       nmstatev1alpha1.NodeNetworkStateLabelSpec{Key: key, Path: path, Value: value},
+		}
+	}
+
+	var (
+		cl         client.Client
+		reconciler ReconcileNodeNetworkStateLabel
+		request    = reconcile.Request{NamespacedName: types.NamespacedName{Name: nodeName}}
+	)
+
+	BeforeEach(func() {
+		s := scheme.Scheme
+		s.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkState{},
+			&nmstatev1alpha1.NodeNetworkStateLabel{},
+			&nmstatev1alpha1.NodeNetworkStateLabelList{},
+		)
+		node := corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: nodeName,
+				Labels: map[string]string{
+					"kubernetes.io/hostname": nodeName,
+					"nmstate.io/has-bond1":   "true",
+				},
+				Annotations: map[string]string{
+					nmstatev1alpha1.NodeNetworkStateLabelsAnnotation: "nmstate.io/has-bond1",
+				},
+			},
+		}
+		nodeNetworkState := nmstatev1alpha1.NodeNetworkState{
+			ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			Status: nmstatev1alpha1.NodeNetworkStateStatus{
+				CurrentState: nmstatev1alpha1.NewState(`interfaces:
+- name: eth0
+  type: ethernet
+  state: up
+  ethernet:
+    speed: 10000
+- name: bond0
+  type: bond
+  state: up
+`),
+			},
+		}
+		objs := []runtime.Object{
+			&node,
+			&nodeNetworkState,
+			stateLabel("has-bond0", "nmstate.io/has-bond0", `interfaces.#(name=="bond0")`, "true"),
+			stateLabel("has-bond2", "nmstate.io/has-bond2", `interfaces.#(name=="bond2")`, "true"),
+			stateLabel("uplink-speed", "nmstate.io/uplink-speed", `interfaces.#(name=="eth0").ethernet.speed`, ""),
+			stateLabel("uplink-speed-duplicate", "nmstate.io/uplink-speed", `interfaces.#(name=="eth1").ethernet.speed`, ""),
+			stateLabel("hostname", "kubernetes.io/hostname", "interfaces.0.name", ""),
+			stateLabel("interfaces", "nmstate.io/interfaces", "interfaces", ""),
+		}
+		cl = mergePatchClient{fake.NewFakeClientWithScheme(s, objs...)}
+		reconciler.client = cl
+	})
+
+	It("should label the node after its current state", func() {
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+
+		node := corev1.Node{}
+		Expect(cl.Get(context.TODO(), request.NamespacedName, &node)).To(Succeed())
+		Expect(node.Labels).To(Equal(map[string]string{
+			"kubernetes.io/hostname":  nodeName,
+			"nmstate.io/has-bond0":    "true",
+			"nmstate.io/uplink-speed": "10000",
+		}))
+		Expect(node.Annotations).To(HaveKeyWithValue(nmstatev1alpha1.NodeNetworkStateLabelsAnnotation, "nmstate.io/has-bond0,nmstate.io/uplink-speed"))
+	})
+
+	It("should remove the labels once their NodeNetworkStateLabels are gone", func() {
+		stateLabels := nmstatev1alpha1.NodeNetworkStateLabelList{}
+		Expect(cl.List(context.TODO(), &stateLabels)).To(Succeed())
+		for i := range stateLabels.Items {
+			Expect(cl.Delete(context.TODO(), &stateLabels.Items[i])).To(Succeed())
+		}
+
+		_, err := reconciler.Reconcile(request)
+		Expect(err).ToNot(HaveOccurred())
+
+		node := corev1.Node{}
+		Expect(cl.Get(context.TODO(), request.NamespacedName, &node)).To(Succeed())
+		Expect(node.Labels).To(Equal(map[string]string{"kubernetes.io/hostname": nodeName}))
+		Expect(node.Annotations).ToNot(HaveKey(nmstatev1alpha1.NodeNetworkStateLabelsAnnotation))
+	})
+
+	It("should patch only the node state labels and annotation", func() {
+		node := corev1.Node{}
+		Expect(cl.Get(context.TODO(), request.NamespacedName, &node)).To(Succeed())
+		patch, changed := stateLabelsPatch(&node, map[string]string{"nmstate.io/has-bond0": "true"})
+		Expect(changed).To(BeTrue())
+		Expect(patch.Type()).To(Equal(types.MergePatchType))
+		data, err := patch.Data(&node)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(MatchJSON(`{"metadata":{
+  "labels":{"nmstate.io/has-bond0":"true","nmstate.io/has-bond1":null},
+  "annotations":{"` + nmstatev1alpha1.NodeNetworkStateLabelsAnnotation + `":"nmstate.io/has-bond0"}
+}}`))
+	})
+
+	It("should not change a node that is already labeled", func() {
+		node := corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"nmstate.io/has-bond0": "true"},
+			Annotations: map[string]string{nmstatev1alpha1.NodeNetworkStateLabelsAnnotation: "nmstate.io/has-bond0"},
+		}}
+		Expect(setStateLabels(&node, map[string]string{"nmstate.io/has-bond0": "true"})).To(BeFalse())
+	})
+})
@@ -0,0 +1,16 @@
+package nodenetworkstatelabel
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/onsi/ginkgo/reporters"
+)
+
+func TestUnit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter("junit.controller-nodenetworkstatelabel-nodenetworkstatelabel_suite_test.xml")
+	RunSpecsWithDefaultAndCustomReporters(t, "NodeNetworkStateLabel Controller Test Suite", []Reporter{junitReporter})
+}
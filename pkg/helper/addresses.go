@@ -0,0 +1,53 @@
+package helper
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// StaticAddresses returns the static IP addresses set by the desired state
+// sorted and without duplicates. The addresses of families with DHCP or
+// autoconf enabled are dynamic, and loopback and link local addresses are
+// the same at every node, so they are left out.
+func StaticAddresses(desiredState nmstatev1alpha1.State) ([]string, error) {
+	addresses := []string{}
+	if len(desiredState.Raw) == 0 {
+		return addresses, nil
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return addresses, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	found := map[string]bool{}
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		if iface.Get("state").String() == "absent" {
+			continue
+		}
+		for _, family := range []string{"ipv4", "ipv6"} {
+			ip := iface.Get(family)
+			if ip.Get("enabled").Type == gjson.False || ip.Get("dhcp").Bool() || ip.Get("autoconf").Bool() {
+				continue
+			}
+			for _, address := range ip.Get("address.#.ip").Array() {
+				parsedAddress := net.ParseIP(address.String())
+				if parsedAddress == nil || parsedAddress.IsLoopback() || parsedAddress.IsLinkLocalUnicast() {
+					continue
+				}
+				found[parsedAddress.String()] = true
+			}
+		}
+	}
+	for address := range found {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
@@ -0,0 +1,56 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Static addresses", func() {
+	It("should take the static addresses of the desired state interfaces", func() {
+		addresses, err := StaticAddresses(nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  ipv4:
+    enabled: true
+    address:
+    - ip: 192.0.2.10
+      prefix-length: 24
+  ipv6:
+    enabled: true
+    address:
+    - ip: 2001:DB8::10
+      prefix-length: 64
+    - ip: fe80::1
+      prefix-length: 64
+- name: eth2
+  type: ethernet
+  state: up
+  ipv4:
+    enabled: true
+    dhcp: true
+    address:
+    - ip: 198.51.100.7
+      prefix-length: 24
+- name: lo
+  type: unknown
+  state: up
+  ipv4:
+    enabled: true
+    address:
+    - ip: 127.0.0.1
+      prefix-length: 8
+- name: eth3
+  type: ethernet
+  state: absent
+  ipv4:
+    address:
+    - ip: 192.0.2.11
+      prefix-length: 24
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(addresses).To(Equal([]string{"192.0.2.10", "2001:db8::10"}))
+	})
+})
@@ -0,0 +1,116 @@
+// Package allowlist checks the interfaces the policies desired state modify
+// against the operator wide allowed interfaces, it does not run anything at
+// the node so it is used by the webhook and the handler alike
+package allowlist
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+// AllowList is a list of interface name globs, an empty one allows all the
+// interfaces
+type AllowList struct {
+	patterns []string
+	globs    []glob.Glob
+}
+
+// New returns the allow list of the comma separated globs
+func New(patterns string) (AllowList, error) {
+	allowList := AllowList{}
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		compiled, err := glob.Compile(pattern)
+		if err != nil {
+			return AllowList{}, fmt.Errorf("invalid allowed interfaces pattern %q: %v", pattern, err)
+		}
+		allowList.patterns = append(allowList.patterns, pattern)
+		allowList.globs = append(allowList.globs, compiled)
+	}
+	return allowList, nil
+}
+
+// FromEnvironment returns the allow list configured at ALLOWED_INTERFACES
+func FromEnvironment() (AllowList, error) {
+	return New(os.Getenv("ALLOWED_INTERFACES"))
+}
+
+func (a AllowList) String() string {
+	return strings.Join(a.patterns, ", ")
+}
+
+// IsEmpty returns true if the allow list allows all the interfaces
+func (a AllowList) IsEmpty() bool {
+	return len(a.globs) == 0
+}
+
+func (a AllowList) allows(name string) bool {
+	if a.IsEmpty() {
+		return true
+	}
+	for _, allowed := range a.globs {
+		if allowed.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// NotAllowedInterfaces returns the interfaces the desired state modifies
+// and the allow list does not match, sorted. The modified interfaces are the
+// listed ones, removed ones included, the ones attached to its bridges and
+// bonds, and the ones its routes and route rules are configured at, the
+// route next hop interfaces and the route rule incoming interfaces. The
+// names of the interfaces identified by MAC or PCI address are aliases
+// until they are resolved at the node, they are not checked.
+func (a AllowList) NotAllowedInterfaces(desiredState nmstatev1alpha1.State) ([]string, error) {
+	notAllowed := []string{}
+	if a.IsEmpty() || len(desiredState.Raw) == 0 {
+		return notAllowed, nil
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return notAllowed, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	state := gjson.ParseBytes(desiredStateJSON)
+	interfaces := state.Get("interfaces").Array()
+	aliases := map[string]bool{}
+	for _, iface := range interfaces {
+		if iface.Get("match").Exists() {
+			aliases[iface.Get("name").String()] = true
+		}
+	}
+	names := []gjson.Result{}
+	for _, iface := range interfaces {
+		names = append(names, iface.Get("name"))
+		names = append(names, iface.Get("bridge.port.#.name").Array()...)
+		names = append(names, render.BondPorts(iface)...)
+	}
+	names = append(names, state.Get("routes.config.#.next-hop-interface").Array()...)
+	names = append(names, state.Get("route-rules.config.#.iif").Array()...)
+	found := map[string]bool{}
+	for _, name := range names {
+		if name.String() != "" && !aliases[name.String()] && !a.allows(name.String()) {
+			found[name.String()] = true
+		}
+	}
+	for name := range found {
+		notAllowed = append(notAllowed, name)
+	}
+	sort.Strings(notAllowed)
+	return notAllowed, nil
+}
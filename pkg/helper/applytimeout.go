@@ -0,0 +1,24 @@
+package helper
+
+import (
+	"fmt"
+	"time"
+)
+
+// applyTimeoutError is returned when nmstate does not finish applying the
+// desired state before the policy apply timeout, the nmstatectl process is
+// killed and the checkpoint, if taken, is rolled back
+type applyTimeoutError struct {
+	timeout time.Duration
+}
+
+func (e *applyTimeoutError) Error() string {
+	return fmt.Sprintf("nmstate did not finish applying desired state in %s, aborted", e.timeout)
+}
+
+// IsApplyTimeout returns true if the desired state was not applied because
+// nmstate took longer than the apply timeout
+func IsApplyTimeout(err error) bool {
+	_, timedOut := err.(*applyTimeoutError)
+	return timedOut
+}
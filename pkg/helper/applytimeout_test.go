@@ -0,0 +1,29 @@
+package helper
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Apply timeout", func() {
+	It("should kill the command if it does not return in time", func() {
+		started := time.Now()
+		_, err := runCommandWithTimeout(100*time.Millisecond, "sleep", []string{"10"}, "")
+		Expect(IsApplyTimeout(err)).To(BeTrue())
+		Expect(time.Since(started)).To(BeNumerically("<", 5*time.Second))
+	})
+
+	It("should return the command output if it returns in time", func() {
+		output, err := runCommandWithTimeout(5*time.Second, "cat", []string{}, "interfaces: []\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal("interfaces: []\n"))
+	})
+
+	It("should not report other command failures as timeouts", func() {
+		_, err := runCommandWithTimeout(5*time.Second, "false", []string{}, "")
+		Expect(err).To(HaveOccurred())
+		Expect(IsApplyTimeout(err)).To(BeFalse())
+	})
+})
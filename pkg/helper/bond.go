@@ -0,0 +1,153 @@
+package helper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gobwas/glob"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// The handler runs at the host network namespace so this is the host
+// bonding status
+const bondingProcDir = "/proc/net/bonding"
+
+const (
+	activeAggregatorSection = "Active Aggregator Info"
+	actorSection            = "details actor lacp pdu"
+	partnerSection          = "details partner lacp pdu"
+)
+
+// noActiveSlave is how the kernel reports the active slave of a bond
+// without any slave up
+const noActiveSlave = "None"
+
+var bondStatusSections = map[string]bool{
+	activeAggregatorSection: true,
+	actorSection:            true,
+	partnerSection:          true,
+}
+
+// parseBondStatus parses the content of /proc/net/bonding/<bond>, the
+// keys are repeated at the aggregator, actor and partner sections so the
+// current section is tracked to know which one they belong to
+func parseBondStatus(name string, content string) nmstatev1alpha1.BondStatus {
+	bond := nmstatev1alpha1.BondStatus{Name: name}
+	var slave *nmstatev1alpha1.BondSlaveStatus
+	section := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if bondStatusSections[strings.TrimSuffix(trimmedLine, ":")] {
+			section = strings.TrimSuffix(trimmedLine, ":")
+			continue
+		}
+		keyValue := strings.SplitN(trimmedLine, ":", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(keyValue[0]), strings.TrimSpace(keyValue[1])
+
+		if key == "Slave Interface" {
+			bond.Slaves = append(bond.Slaves, nmstatev1alpha1.BondSlaveStatus{Name: value})
+			slave = &bond.Slaves[len(bond.Slaves)-1]
+			section = ""
+			continue
+		}
+
+		if slave == nil {
+			switch {
+			case key == "Bonding Mode":
+				bond.Mode = value
+			case key == "LACP rate":
+				bond.LACPRate = value
+			case key == "Currently Active Slave":
+				bond.ActiveSlave = value
+			case section == activeAggregatorSection && key == "Aggregator ID":
+				bond.AggregatorID = value
+			case section == activeAggregatorSection && key == "Actor Key":
+				bond.ActorKey = value
+			case section == activeAggregatorSection && key == "Partner Key":
+				bond.PartnerKey = value
+			case section == activeAggregatorSection && key == "Partner Mac Address":
+				bond.PartnerMacAddress = value
+			}
+			continue
+		}
+
+		switch {
+		case key == "MII Status" && section == "":
+			slave.MIIStatus = value
+		case key == "Aggregator ID" && section == "":
+			slave.AggregatorID = value
+		case key == "Link Failure Count" && section == "":
+			slave.LinkFailureCount = value
+		case key == "port state" && section == actorSection:
+			slave.ActorPortState = value
+		case key == "port state" && section == partnerSection:
+			slave.PartnerPortState = value
+		}
+	}
+
+	// Modes without an active slave have no active slave either when all
+	// of them are down
+	anySlaveUp := false
+	for _, slave := range bond.Slaves {
+		if slave.MIIStatus == "up" {
+			anySlaveUp = true
+		}
+	}
+	if !anySlaveUp {
+		bond.ActiveSlave = noActiveSlave
+	}
+	return bond
+}
+
+// updateBondFailovers takes the last failover of the bonds from the
+// previous report, a change of the active slave since then is a failover
+// happening now. Bonds seen for the first time have no failover, nor the
+// ones of modes without an active slave losing or recovering all of them.
+func updateBondFailovers(previous []nmstatev1alpha1.BondStatus, bonds []nmstatev1alpha1.BondStatus, now time.Time) []nmstatev1alpha1.BondStatus {
+	previousBonds := map[string]nmstatev1alpha1.BondStatus{}
+	for _, bond := range previous {
+		previousBonds[bond.Name] = bond
+	}
+	for i, bond := range bonds {
+		previousBond, found := previousBonds[bond.Name]
+		if !found {
+			continue
+		}
+		if previousBond.ActiveSlave != "" && bond.ActiveSlave != "" && previousBond.ActiveSlave != bond.ActiveSlave {
+			bonds[i].PreviousActiveSlave = previousBond.ActiveSlave
+			bonds[i].LastFailoverTime = &metav1.Time{Time: now}
+			continue
+		}
+		bonds[i].PreviousActiveSlave = previousBond.PreviousActiveSlave
+		bonds[i].LastFailoverTime = previousBond.LastFailoverTime
+	}
+	return bonds
+}
+
+func showBonds(previous []nmstatev1alpha1.BondStatus, interfacesFilterGlob glob.Glob) ([]nmstatev1alpha1.BondStatus, error) {
+	bonds := []nmstatev1alpha1.BondStatus{}
+	bondFiles, err := filepath.Glob(filepath.Join(bondingProcDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, bondFile := range bondFiles {
+		name := filepath.Base(bondFile)
+		if !interfacesFilterGlob.Match("") && interfacesFilterGlob.Match(name) {
+			continue
+		}
+		content, err := ioutil.ReadFile(bondFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading bond %s status: %v", name, err)
+		}
+		bonds = append(bonds, parseBondStatus(name, string(content)))
+	}
+	return updateBondFailovers(previous, bonds, time.Now()), nil
+}
@@ -0,0 +1,162 @@
+package helper
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Bond status", func() {
+	It("should parse the negotiated LACP state", func() {
+		content := `Ethernet Channel Bonding Driver: v3.7.1 (April 27, 2011)
+
+Bonding Mode: IEEE 802.3ad Dynamic link aggregation
+Transmit Hash Policy: layer2 (0)
+MII Status: up
+MII Polling Interval (ms): 100
+Up Delay (ms): 200
+Down Delay (ms): 200
+
+802.3ad info
+LACP rate: fast
+Min links: 0
+Aggregator selection policy (ad_select): stable
+System priority: 65535
+System MAC address: 52:55:00:d1:55:01
+Active Aggregator Info:
+	Aggregator ID: 1
+	Number of ports: 2
+	Actor Key: 9
+	Other Key: 0
+	Partner Key: 32768
+	Partner Mac Address: 00:1c:73:aa:bb:cc
+
+Slave Interface: eth1
+MII Status: up
+Speed: 1000 Mbps
+Duplex: full
+Link Failure Count: 0
+Permanent HW addr:
+Slave queue ID: 0
+Aggregator ID: 1
+Actor Churn State: none
+Partner Churn State: none
+details actor lacp pdu:
+    system priority: 65535
+    system mac address: 52:55:00:d1:55:01
+    port key: 9
+    port priority: 255
+    port number: 1
+    port state: 63
+details partner lacp pdu:
+    system priority: 32768
+    system mac address: 00:1c:73:aa:bb:cc
+    oper key: 32768
+    port priority: 32768
+    port number: 7
+    port state: 61
+
+Slave Interface: eth2
+MII Status: down
+Speed: Unknown
+Duplex: Unknown
+Link Failure Count: 1
+Permanent HW addr: 52:55:00:d1:56:02
+Slave queue ID: 0
+Aggregator ID: 2
+details actor lacp pdu:
+    port state: 69
+details partner lacp pdu:
+    port state: 1
+`
+		Expect(parseBondStatus("bond0", content)).To(Equal(nmstatev1alpha1.BondStatus{
+			Name:              "bond0",
+			Mode:              "IEEE 802.3ad Dynamic link aggregation",
+			LACPRate:          "fast",
+			AggregatorID:      "1",
+			ActorKey:          "9",
+			PartnerKey:        "32768",
+			PartnerMacAddress: "00:1c:73:aa:bb:cc",
+			Slaves: []nmstatev1alpha1.BondSlaveStatus{
+				{Name: "eth1", MIIStatus: "up", AggregatorID: "1", LinkFailureCount: "0", ActorPortState: "63", PartnerPortState: "61"},
+				{Name: "eth2", MIIStatus: "down", AggregatorID: "2", LinkFailureCount: "1", ActorPortState: "69", PartnerPortState: "1"},
+			},
+		}))
+	})
+
+	It("should parse non LACP bonds", func() {
+		content := `Bonding Mode: fault-tolerance (active-backup)
+Primary Slave: None
+Currently Active Slave: eth1
+MII Status: up
+
+Slave Interface: eth1
+MII Status: up
+`
+		Expect(parseBondStatus("bond1", content)).To(Equal(nmstatev1alpha1.BondStatus{
+			Name:        "bond1",
+			Mode:        "fault-tolerance (active-backup)",
+			ActiveSlave: "eth1",
+			Slaves: []nmstatev1alpha1.BondSlaveStatus{
+				{Name: "eth1", MIIStatus: "up"},
+			},
+		}))
+	})
+
+	It("should report no active slave if all the slaves are down", func() {
+		content := `Bonding Mode: load balancing (round-robin)
+MII Status: down
+
+Slave Interface: eth1
+MII Status: down
+Link Failure Count: 3
+
+Slave Interface: eth2
+MII Status: down
+Link Failure Count: 2
+`
+		Expect(parseBondStatus("bond2", content).ActiveSlave).To(Equal("None"))
+	})
+
+	Context("failovers", func() {
+		failoverTime := metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+		now := failoverTime.Add(time.Hour)
+
+		It("should report them when the active slave changes", func() {
+			bonds := updateBondFailovers(
+				[]nmstatev1alpha1.BondStatus{{Name: "bond1", ActiveSlave: "eth1"}},
+				[]nmstatev1alpha1.BondStatus{{Name: "bond1", ActiveSlave: "eth2"}, {Name: "bond2", ActiveSlave: "eth3"}},
+				now,
+			)
+			Expect(bonds).To(Equal([]nmstatev1alpha1.BondStatus{
+				{Name: "bond1", ActiveSlave: "eth2", PreviousActiveSlave: "eth1", LastFailoverTime: &metav1.Time{Time: now}},
+				{Name: "bond2", ActiveSlave: "eth3"},
+			}))
+		})
+
+		It("should keep the last one while the active slave does not change", func() {
+			bonds := updateBondFailovers(
+				[]nmstatev1alpha1.BondStatus{{Name: "bond1", ActiveSlave: "eth2", PreviousActiveSlave: "eth1", LastFailoverTime: &failoverTime}},
+				[]nmstatev1alpha1.BondStatus{{Name: "bond1", ActiveSlave: "eth2"}},
+				now,
+			)
+			Expect(bonds).To(Equal([]nmstatev1alpha1.BondStatus{
+				{Name: "bond1", ActiveSlave: "eth2", PreviousActiveSlave: "eth1", LastFailoverTime: &failoverTime},
+			}))
+		})
+
+		It("should not report them for modes without an active slave", func() {
+			bonds := updateBondFailovers(
+				[]nmstatev1alpha1.BondStatus{{Name: "bond0", ActiveSlave: "None"}},
+				[]nmstatev1alpha1.BondStatus{{Name: "bond0"}},
+				now,
+			)
+			Expect(bonds).To(Equal([]nmstatev1alpha1.BondStatus{{Name: "bond0"}}))
+		})
+	})
+})
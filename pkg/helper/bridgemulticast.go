@@ -0,0 +1,357 @@
+package helper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// nmstate configures the linux bridges multicast snooping but not the rest
+// of their multicast options, they are written to
+// /sys/class/net/<bridge>/bridge/<sysfs file>
+const multicastSnoopingKey = "multicast-snooping"
+
+// bridgeMulticastOption is a linux bridge multicast option not supported by
+// nmstate, values are the allowed ones, any non negative number if empty
+type bridgeMulticastOption struct {
+	sysfsFile    string
+	boolean      bool
+	values       []int64
+	defaultValue interface{}
+}
+
+var bridgeMulticastOptions = map[string]bridgeMulticastOption{
+	"multicast-querier":        {sysfsFile: "multicast_querier", boolean: true, defaultValue: false},
+	"multicast-query-interval": {sysfsFile: "multicast_query_interval", defaultValue: int64(12500)},
+	"multicast-igmp-version":   {sysfsFile: "multicast_igmp_version", values: []int64{2, 3}, defaultValue: int64(2)},
+	"multicast-mld-version":    {sysfsFile: "multicast_mld_version", values: []int64{1, 2}, defaultValue: int64(1)},
+}
+
+// bridgeMulticastDefaults are the values of the kernel new bridges, the
+// multicast options dropped from the policies are reset to them
+var bridgeMulticastDefaults = map[string]interface{}{
+	multicastSnoopingKey: true,
+}
+
+func init() {
+	for name, option := range bridgeMulticastOptions {
+		bridgeMulticastDefaults[name] = option.defaultValue
+	}
+}
+
+// bridgeMulticastSetting is a desired state bridge multicast option, value
+// is the one written to sysfs
+type bridgeMulticastSetting struct {
+	bridge string
+	option string
+	value  string
+}
+
+func (s bridgeMulticastSetting) path(netDir string) string {
+	return filepath.Join(netDir, s.bridge, "bridge", bridgeMulticastOptions[s.option].sysfsFile)
+}
+
+func parseBridgeMulticastValue(option bridgeMulticastOption, value gjson.Result) (string, bool) {
+	if option.boolean {
+		if value.Type != gjson.True && value.Type != gjson.False {
+			return "", false
+		}
+		if value.Bool() {
+			return "1", true
+		}
+		return "0", true
+	}
+	if value.Type != gjson.Number || value.Int() < 0 || float64(value.Int()) != value.Float() {
+		return "", false
+	}
+	if len(option.values) == 0 {
+		return value.Raw, true
+	}
+	for _, allowed := range option.values {
+		if value.Int() == allowed {
+			return value.Raw, true
+		}
+	}
+	return "", false
+}
+
+// getBridgesMulticast returns the desired state linux bridges multicast
+// options nmstate does not support sorted by bridge, failing with invalid
+// values. Absent bridges are ignored.
+func getBridgesMulticast(desiredState nmstatev1alpha1.State) ([]bridgeMulticastSetting, error) {
+	settings := []bridgeMulticastSetting{}
+	if len(desiredState.Raw) == 0 {
+		return settings, nil
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return settings, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		if iface.Get("type").String() != "linux-bridge" || iface.Get("state").String() == "absent" {
+			continue
+		}
+		name := iface.Get("name").String()
+		options := iface.Get("bridge.options")
+		optionNames := []string{}
+		for optionName := range bridgeMulticastOptions {
+			if options.Get(optionName).Exists() {
+				optionNames = append(optionNames, optionName)
+			}
+		}
+		sort.Strings(optionNames)
+		for _, optionName := range optionNames {
+			value, valid := parseBridgeMulticastValue(bridgeMulticastOptions[optionName], options.Get(optionName))
+			if !valid {
+				return settings, fmt.Errorf("invalid bridge %s %s value %s", name, optionName, options.Get(optionName).Raw)
+			}
+			settings = append(settings, bridgeMulticastSetting{bridge: name, option: optionName, value: value})
+		}
+	}
+	return settings, nil
+}
+
+// desiredBridgeOptions returns the options maps of the desired state linux
+// bridges by name, the state has to be unmarshaled already. With create
+// the bridges without options get an empty one.
+func desiredBridgeOptions(state map[string]interface{}, create bool) map[string]map[string]interface{} {
+	bridgeOptions := map[string]map[string]interface{}{}
+	interfaces, _ := state["interfaces"].([]interface{})
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap || iface["type"] != "linux-bridge" || iface["state"] == "absent" {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		bridge, isMap := iface["bridge"].(map[string]interface{})
+		if !isMap && !create {
+			continue
+		}
+		if !isMap {
+			bridge = map[string]interface{}{}
+			iface["bridge"] = bridge
+		}
+		options, isMap := bridge["options"].(map[string]interface{})
+		if !isMap && !create {
+			continue
+		}
+		if !isMap {
+			options = map[string]interface{}{}
+			bridge["options"] = options
+		}
+		bridgeOptions[name] = options
+	}
+	return bridgeOptions
+}
+
+// stripBridgesMulticast removes the multicast options nmstate does not
+// support from the desired state bridges
+func stripBridgesMulticast(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	settings, err := getBridgesMulticast(desiredState)
+	if err != nil || len(settings) == 0 {
+		return desiredState, err
+	}
+
+	var state map[string]interface{}
+	err = yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	bridgeOptions := desiredBridgeOptions(state, false)
+	for _, setting := range settings {
+		delete(bridgeOptions[setting.bridge], setting.option)
+	}
+
+	strippedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: strippedState}, nil
+}
+
+// applyBridgesMulticast writes the bridges multicast options, the bridges
+// have to be created by nmstate first
+func applyBridgesMulticast(netDir string, settings []bridgeMulticastSetting) (string, error) {
+	output := ""
+	for _, setting := range settings {
+		err := ioutil.WriteFile(setting.path(netDir), []byte(setting.value), 0644)
+		if err != nil {
+			return output, fmt.Errorf("failed setting bridge %s %s: %v", setting.bridge, setting.option, err)
+		}
+		output += fmt.Sprintf("bridge %s %s set to %s\n", setting.bridge, setting.option, setting.value)
+	}
+	return output, nil
+}
+
+// readBridgesMulticast returns the current value of the bridges multicast
+// options given, the ones of bridges the kernel does not have yet are left
+// out, nmstate removes these bridges on rollback
+func readBridgesMulticast(netDir string, settings []bridgeMulticastSetting) []bridgeMulticastSetting {
+	current := []bridgeMulticastSetting{}
+	for _, setting := range settings {
+		content, err := ioutil.ReadFile(setting.path(netDir))
+		if err != nil {
+			continue
+		}
+		setting.value = strings.TrimSpace(string(content))
+		current = append(current, setting)
+	}
+	return current
+}
+
+// restoreBridgesMulticast writes back the bridges multicast options read
+// before applying the desired state, they are not part of the nmstate
+// checkpoint
+func restoreBridgesMulticast(netDir string, previousSettings []bridgeMulticastSetting) string {
+	output, err := applyBridgesMulticast(netDir, previousSettings)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed restoring bridges multicast options: %v", err))
+	}
+	return output
+}
+
+// bridgesMulticastOptions returns the multicast options, snooping included,
+// set at the desired state bridges
+func bridgesMulticastOptions(desiredState nmstatev1alpha1.State) (map[string]map[string]bool, error) {
+	bridges := map[string]map[string]bool{}
+	if len(desiredState.Raw) == 0 {
+		return bridges, nil
+	}
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return bridges, err
+	}
+	for name, options := range desiredBridgeOptions(state, true) {
+		bridges[name] = map[string]bool{}
+		for option := range bridgeMulticastDefaults {
+			if _, isSet := options[option]; isSet {
+				bridges[name][option] = true
+			}
+		}
+	}
+	return bridges, nil
+}
+
+// RemoveDroppedBridgesMulticast sets to the kernel default at the desired
+// state the bridges multicast options, snooping included, present at the
+// previously applied one that are not at the desired state anymore, so
+// dropping them from the policy reverts them. Bridges dropped from the
+// desired state are left as they are.
+func RemoveDroppedBridgesMulticast(previousState nmstatev1alpha1.State, desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	previousBridges, err := bridgesMulticastOptions(previousState)
+	if err != nil || len(previousBridges) == 0 {
+		return desiredState, err
+	}
+	desiredBridges, err := bridgesMulticastOptions(desiredState)
+	if err != nil {
+		return desiredState, err
+	}
+
+	dropped := false
+	for bridge, options := range previousBridges {
+		desiredOptions, found := desiredBridges[bridge]
+		if !found {
+			continue
+		}
+		for option := range options {
+			if !desiredOptions[option] {
+				dropped = true
+			}
+		}
+	}
+	if !dropped {
+		return desiredState, nil
+	}
+
+	var state map[string]interface{}
+	err = yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	for bridge, options := range desiredBridgeOptions(state, true) {
+		for option := range previousBridges[bridge] {
+			if _, isSet := options[option]; !isSet {
+				options[option] = bridgeMulticastDefaults[option]
+			}
+		}
+	}
+
+	removedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: removedState}, nil
+}
+
+// readBridgeMulticast returns the multicast options nmstate does not report
+// of the bridge, the ones the kernel does not have are left out
+func readBridgeMulticast(netDir string, bridge string) map[string]interface{} {
+	options := map[string]interface{}{}
+	for name, option := range bridgeMulticastOptions {
+		value, err := strconv.ParseInt(readSysfsValue(filepath.Join(netDir, bridge, "bridge", option.sysfsFile)), 10, 64)
+		if err != nil {
+			continue
+		}
+		if option.boolean {
+			options[name] = value != 0
+		} else {
+			options[name] = value
+		}
+	}
+	return options
+}
+
+// addBridgesMulticast reports the multicast options nmstate does not report
+// at the current state linux bridges
+func addBridgesMulticast(currentState nmstatev1alpha1.State, netDir string) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return currentState, nil
+	}
+
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap || iface["type"] != "linux-bridge" {
+			continue
+		}
+		bridge, isMap := iface["bridge"].(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		options, isMap := bridge["options"].(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		for option, value := range readBridgeMulticast(netDir, name) {
+			options[option] = value
+		}
+	}
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+func reportBridgesMulticast(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return addBridgesMulticast(currentState, sysClassNetDir)
+}
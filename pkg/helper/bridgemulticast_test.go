@@ -0,0 +1,153 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Bridges multicast options", func() {
+	const br1 = `- name: br1
+  type: linux-bridge
+  state: up
+  bridge:
+    options:
+      multicast-snooping: true
+`
+	const br1Multicast = br1 + `      multicast-querier: true
+      multicast-query-interval: 6000
+      multicast-igmp-version: 3
+`
+
+	It("should take the multicast options and strip them from the nmstate desired state", func() {
+		desiredState := nmstatev1alpha1.NewState("interfaces:\n" + br1Multicast)
+
+		settings, err := getBridgesMulticast(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(Equal([]bridgeMulticastSetting{
+			{bridge: "br1", option: "multicast-igmp-version", value: "3"},
+			{bridge: "br1", option: "multicast-querier", value: "1"},
+			{bridge: "br1", option: "multicast-query-interval", value: "6000"},
+		}))
+
+		strippedState, err := stripBridgesMulticast(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML("interfaces:\n" + br1))
+	})
+
+	It("should ignore absent bridges", func() {
+		settings, err := getBridgesMulticast(nmstatev1alpha1.NewState("interfaces:\n- name: br1\n  type: linux-bridge\n  state: absent\n  bridge:\n    options:\n      multicast-querier: true\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settings).To(BeEmpty())
+	})
+
+	DescribeTable("with invalid multicast options",
+		func(option string) {
+			_, err := getBridgesMulticast(nmstatev1alpha1.NewState("interfaces:\n- name: br1\n  type: linux-bridge\n  state: up\n  bridge:\n    options:\n" + option))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("querier not a boolean", "      multicast-querier: 1\n"),
+		Entry("negative query interval", "      multicast-query-interval: -1\n"),
+		Entry("unsupported IGMP version", "      multicast-igmp-version: 1\n"),
+		Entry("unsupported MLD version", "      multicast-mld-version: 3\n"),
+	)
+
+	DescribeTable("dropped from the desired state",
+		func(previousState string, desiredState string, expectedState string) {
+			removedState, err := RemoveDroppedBridgesMulticast(nmstatev1alpha1.NewState(previousState), nmstatev1alpha1.NewState(desiredState))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removedState.String()).To(MatchYAML(expectedState))
+		},
+		Entry("without previous desired state, should keep it",
+			"",
+			"interfaces:\n"+br1Multicast,
+			"interfaces:\n"+br1Multicast,
+		),
+		Entry("still setting the options, should keep them",
+			"interfaces:\n"+br1Multicast,
+			"interfaces:\n"+br1Multicast,
+			"interfaces:\n"+br1Multicast,
+		),
+		Entry("without snooping disabled, should enable it again",
+			"interfaces:\n- name: br1\n  type: linux-bridge\n  state: up\n  bridge:\n    options:\n      multicast-snooping: false\n",
+			"interfaces:\n- name: br1\n  type: linux-bridge\n  state: up\n",
+			"interfaces:\n"+br1,
+		),
+		Entry("without some of the options, should reset them",
+			"interfaces:\n"+br1Multicast,
+			"interfaces:\n"+br1+"      multicast-igmp-version: 3\n",
+			"interfaces:\n"+br1+"      multicast-igmp-version: 3\n      multicast-querier: false\n      multicast-query-interval: 12500\n",
+		),
+		Entry("without the bridge, should leave it",
+			"interfaces:\n"+br1Multicast,
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n",
+		),
+	)
+
+	Context("at the node", func() {
+		var netDir string
+
+		writeOption := func(bridge string, file string, value string) {
+			dir := filepath.Join(netDir, bridge, "bridge")
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, file), []byte(value+"\n"), 0644)).To(Succeed())
+		}
+
+		readOption := func(bridge string, file string) string {
+			content, err := ioutil.ReadFile(filepath.Join(netDir, bridge, "bridge", file))
+			Expect(err).ToNot(HaveOccurred())
+			return string(content)
+		}
+
+		BeforeEach(func() {
+			var err error
+			netDir, err = ioutil.TempDir("", "sys-class-net")
+			Expect(err).ToNot(HaveOccurred())
+			writeOption("br1", "multicast_querier", "0")
+			writeOption("br1", "multicast_query_interval", "12500")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(netDir)
+		})
+
+		It("should write the options", func() {
+			_, err := applyBridgesMulticast(netDir, []bridgeMulticastSetting{
+				{bridge: "br1", option: "multicast-querier", value: "1"},
+				{bridge: "br1", option: "multicast-query-interval", value: "6000"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(readOption("br1", "multicast_querier")).To(Equal("1"))
+			Expect(readOption("br1", "multicast_query_interval")).To(Equal("6000"))
+		})
+
+		It("should restore the options read before writing them", func() {
+			settings := []bridgeMulticastSetting{
+				{bridge: "br1", option: "multicast-querier", value: "1"},
+				{bridge: "br2", option: "multicast-querier", value: "1"},
+			}
+			previousSettings := readBridgesMulticast(netDir, settings)
+			Expect(previousSettings).To(Equal([]bridgeMulticastSetting{{bridge: "br1", option: "multicast-querier", value: "0"}}))
+
+			_, err := applyBridgesMulticast(netDir, settings[:1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(readOption("br1", "multicast_querier")).To(Equal("1"))
+
+			restoreBridgesMulticast(netDir, previousSettings)
+			Expect(readOption("br1", "multicast_querier")).To(Equal("0"))
+		})
+
+		It("should report the current options at the current state bridges", func() {
+			reportedState, err := addBridgesMulticast(nmstatev1alpha1.NewState("interfaces:\n"+br1+"- name: eth1\n  type: ethernet\n  state: up\n"), netDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reportedState.String()).To(MatchYAML("interfaces:\n" + br1 + "      multicast-querier: false\n      multicast-query-interval: 12500\n- name: eth1\n  type: ethernet\n  state: up\n"))
+		})
+	})
+})
@@ -0,0 +1,43 @@
+package helper
+
+import (
+	"strings"
+)
+
+// busyMessages are the errors nmstate returns when another transaction is
+// being applied at the node, like a checkpoint not committed yet. Generic
+// messages, like the EBUSY ones of the kernel, are not transient so they
+// are not taken as busy.
+var busyMessages = []string{
+	"another checkpoint",
+	"checkpoint already exists",
+	"a checkpoint for device",
+}
+
+// busyError is a transient nmstate failure, the desired state can be
+// applied once the other transaction finishes
+type busyError struct {
+	err error
+}
+
+func (e *busyError) Error() string {
+	return e.err.Error()
+}
+
+func isBusyOutput(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, busyMessage := range busyMessages {
+		if strings.Contains(message, busyMessage) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBusy returns true if the desired state was not applied because nmstate
+// was busy with another transaction, nothing was changed at the node so it
+// can be applied again later
+func IsBusy(err error) bool {
+	_, busy := err.(*busyError)
+	return busy
+}
@@ -0,0 +1,187 @@
+package helper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobwas/glob"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// The handler runs at the host network namespace so these are the host
+// interfaces
+const sysClassNetDir = "/sys/class/net"
+
+// Upper bound of carrier transitions kept per interface, so a flapping link
+// does not bloat the NodeNetworkState
+const maxCarrierHistoryLength = 20
+
+var (
+	// Carrier transitions kept per interface at NodeNetworkState, the
+	// history is not reported with 0
+	carrierHistoryLength = 0
+)
+
+func init() {
+	length, isSet := os.LookupEnv("CARRIER_HISTORY_LENGTH")
+	if !isSet || length == "" {
+		return
+	}
+	var err error
+	carrierHistoryLength, err = strconv.Atoi(length)
+	if err != nil {
+		panic(fmt.Sprintf("Failed while converting evnironment variable to int: %v", err))
+	}
+	if carrierHistoryLength > maxCarrierHistoryLength {
+		carrierHistoryLength = maxCarrierHistoryLength
+	}
+}
+
+// carrierObservation is the carrier of an interface at a report, along with
+// the kernel count of carrier changes since it was created
+type carrierObservation struct {
+	name           string
+	carrier        string
+	operState      string
+	carrierChanges int64
+}
+
+// parseCarrier translates /sys/class/net/<iface>/carrier, the kernel fails
+// reading it for interfaces administratively down, so they are down too
+func parseCarrier(content string) string {
+	if strings.TrimSpace(content) == "1" {
+		return "up"
+	}
+	return "down"
+}
+
+func observeCarrier(interfaceDir string) (carrierObservation, error) {
+	name := filepath.Base(interfaceDir)
+	observation := carrierObservation{name: name, carrier: "down"}
+
+	changes, err := ioutil.ReadFile(filepath.Join(interfaceDir, "carrier_changes"))
+	if err != nil {
+		return observation, fmt.Errorf("failed reading interface %s carrier changes: %v", name, err)
+	}
+	observation.carrierChanges, err = strconv.ParseInt(strings.TrimSpace(string(changes)), 10, 64)
+	if err != nil {
+		return observation, fmt.Errorf("failed parsing interface %s carrier changes: %v", name, err)
+	}
+
+	carrier, err := ioutil.ReadFile(filepath.Join(interfaceDir, "carrier"))
+	if err == nil {
+		observation.carrier = parseCarrier(string(carrier))
+	}
+	operState, err := ioutil.ReadFile(filepath.Join(interfaceDir, "operstate"))
+	if err == nil {
+		observation.operState = strings.TrimSpace(string(operState))
+	}
+	return observation, nil
+}
+
+// updateCarrierHistory adds a transition to the history of the interfaces
+// whose kernel carrier changes count increased since the previous report,
+// the count tells the transitions missed between reports. The histories are
+// bounded to length transitions and only the observed interfaces are kept.
+func updateCarrierHistory(previous []nmstatev1alpha1.InterfaceCarrierHistory, observations []carrierObservation, now time.Time, length int) []nmstatev1alpha1.InterfaceCarrierHistory {
+	previousByName := map[string]nmstatev1alpha1.InterfaceCarrierHistory{}
+	for _, history := range previous {
+		previousByName[history.Name] = history
+	}
+
+	histories := []nmstatev1alpha1.InterfaceCarrierHistory{}
+	for _, observation := range observations {
+		history, found := previousByName[observation.name]
+		if !found {
+			history = nmstatev1alpha1.InterfaceCarrierHistory{Name: observation.name}
+		} else if observation.carrierChanges > history.CarrierChanges {
+			history.Transitions = append(history.Transitions, nmstatev1alpha1.CarrierTransition{
+				Time:      metav1.Time{Time: now},
+				Carrier:   observation.carrier,
+				OperState: observation.operState,
+				Changes:   observation.carrierChanges - history.CarrierChanges,
+			})
+		}
+		// The count starts again if the interface is created again
+		history.CarrierChanges = observation.carrierChanges
+		if len(history.Transitions) > length {
+			history.Transitions = history.Transitions[len(history.Transitions)-length:]
+		}
+		histories = append(histories, history)
+	}
+	return histories
+}
+
+func showCarrierHistory(previous []nmstatev1alpha1.InterfaceCarrierHistory, interfacesFilterGlob glob.Glob) ([]nmstatev1alpha1.InterfaceCarrierHistory, error) {
+	observations, err := observeCarriers(sysClassNetDir, interfacesFilterGlob)
+	if err != nil {
+		return nil, err
+	}
+	return updateCarrierHistory(previous, observations, time.Now(), carrierHistoryLength), nil
+}
+
+// observeCarriers observes the carrier of the interfaces at the net class
+// directory. Its files, like bonding_masters, are not interfaces, and the
+// interfaces that cannot be read, like the ones removed meanwhile, are left
+// out of the history.
+func observeCarriers(netDir string, interfacesFilterGlob glob.Glob) ([]carrierObservation, error) {
+	interfaceDirs, err := filepath.Glob(filepath.Join(netDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	observations := []carrierObservation{}
+	for _, interfaceDir := range interfaceDirs {
+		name := filepath.Base(interfaceDir)
+		if !interfacesFilterGlob.Match("") && interfacesFilterGlob.Match(name) {
+			continue
+		}
+		info, err := os.Stat(interfaceDir)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		observation, err := observeCarrier(interfaceDir)
+		if err != nil {
+			log.Info(fmt.Sprintf("skipping interface carrier history: %v", err))
+			continue
+		}
+		observations = append(observations, observation)
+	}
+	return observations, nil
+}
+
+// Links renegotiate after the desired state is applied, they are given some
+// time to get carrier before they are reported down
+const linkDownTimeout = 10 * time.Second
+const linkDownPollInterval = 1 * time.Second
+
+// linkDownInterfaces returns the interfaces without carrier at the net class
+// directory, missing interfaces have no carrier either
+func linkDownInterfaces(netDir string, names []string) []string {
+	down := []string{}
+	for _, name := range names {
+		carrier, _ := ioutil.ReadFile(filepath.Join(netDir, name, "carrier"))
+		if parseCarrier(string(carrier)) != "up" {
+			down = append(down, name)
+		}
+	}
+	return down
+}
+
+// LinkDownInterfaces returns the interfaces still without carrier after
+// waiting for them to negotiate their links
+func LinkDownInterfaces(names []string) []string {
+	down := linkDownInterfaces(sysClassNetDir, names)
+	wait.PollImmediate(linkDownPollInterval, linkDownTimeout, func() (bool, error) {
+		down = linkDownInterfaces(sysClassNetDir, names)
+		return len(down) == 0, nil
+	})
+	return down
+}
@@ -0,0 +1,113 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gobwas/glob"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Carrier history", func() {
+	now := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+	earlier := metav1.Time{Time: now.Add(-time.Minute)}
+
+	It("should translate the kernel carrier", func() {
+		Expect(parseCarrier("1\n")).To(Equal("up"))
+		Expect(parseCarrier("0\n")).To(Equal("down"))
+		Expect(parseCarrier("")).To(Equal("down"))
+	})
+
+	It("should not report transitions for interfaces seen for the first time", func() {
+		histories := updateCarrierHistory(nil, []carrierObservation{
+			{name: "eth1", carrier: "up", operState: "up", carrierChanges: 7},
+		}, now, 5)
+		Expect(histories).To(Equal([]nmstatev1alpha1.InterfaceCarrierHistory{
+			{Name: "eth1", CarrierChanges: 7},
+		}))
+	})
+
+	It("should report the transitions counted by the kernel since the previous report", func() {
+		previous := []nmstatev1alpha1.InterfaceCarrierHistory{
+			{Name: "eth1", CarrierChanges: 7},
+			{Name: "eth2", CarrierChanges: 2},
+		}
+		histories := updateCarrierHistory(previous, []carrierObservation{
+			{name: "eth1", carrier: "up", operState: "up", carrierChanges: 10},
+			{name: "eth2", carrier: "up", operState: "up", carrierChanges: 2},
+		}, now, 5)
+		Expect(histories).To(Equal([]nmstatev1alpha1.InterfaceCarrierHistory{
+			{Name: "eth1", CarrierChanges: 10, Transitions: []nmstatev1alpha1.CarrierTransition{
+				{Time: metav1.Time{Time: now}, Carrier: "up", OperState: "up", Changes: 3},
+			}},
+			{Name: "eth2", CarrierChanges: 2},
+		}))
+	})
+
+	It("should keep only the latest transitions", func() {
+		previous := []nmstatev1alpha1.InterfaceCarrierHistory{
+			{Name: "eth1", CarrierChanges: 2, Transitions: []nmstatev1alpha1.CarrierTransition{
+				{Time: earlier, Carrier: "down", OperState: "down", Changes: 1},
+				{Time: earlier, Carrier: "up", OperState: "up", Changes: 1},
+			}},
+		}
+		histories := updateCarrierHistory(previous, []carrierObservation{
+			{name: "eth1", carrier: "down", operState: "lowerlayerdown", carrierChanges: 3},
+		}, now, 2)
+		Expect(histories[0].Transitions).To(Equal([]nmstatev1alpha1.CarrierTransition{
+			{Time: earlier, Carrier: "up", OperState: "up", Changes: 1},
+			{Time: metav1.Time{Time: now}, Carrier: "down", OperState: "lowerlayerdown", Changes: 1},
+		}))
+	})
+
+	It("should drop the history of removed interfaces and restart the count of recreated ones", func() {
+		previous := []nmstatev1alpha1.InterfaceCarrierHistory{
+			{Name: "eth1", CarrierChanges: 9},
+			{Name: "eth2", CarrierChanges: 4},
+		}
+		histories := updateCarrierHistory(previous, []carrierObservation{
+			{name: "eth1", carrier: "up", operState: "up", carrierChanges: 1},
+		}, now, 5)
+		Expect(histories).To(Equal([]nmstatev1alpha1.InterfaceCarrierHistory{
+			{Name: "eth1", CarrierChanges: 1},
+		}))
+	})
+})
+
+var _ = Describe("Carrier observations", func() {
+	It("should skip the files and the interfaces that cannot be read", func() {
+		netDir, err := ioutil.TempDir("", "sys-class-net")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(netDir)
+		Expect(os.MkdirAll(filepath.Join(netDir, "eth1"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(netDir, "eth1", "carrier_changes"), []byte("3\n"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(netDir, "eth1", "carrier"), []byte("1\n"), 0644)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(netDir, "eth2"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(netDir, "bonding_masters"), []byte("bond0\n"), 0644)).To(Succeed())
+
+		observations, err := observeCarriers(netDir, glob.MustCompile(""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(observations).To(Equal([]carrierObservation{{name: "eth1", carrier: "up", carrierChanges: 3}}))
+	})
+})
+
+var _ = Describe("Link down interfaces", func() {
+	It("should return the interfaces without carrier", func() {
+		netDir, err := ioutil.TempDir("", "sys-class-net")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(netDir)
+		for name, carrier := range map[string]string{"eth1": "1\n", "eth2": "0\n"} {
+			Expect(os.MkdirAll(filepath.Join(netDir, name), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(netDir, name, "carrier"), []byte(carrier), 0644)).To(Succeed())
+		}
+		Expect(linkDownInterfaces(netDir, []string{"eth1", "eth2", "eth3"})).To(Equal([]string{"eth2", "eth3"}))
+	})
+})
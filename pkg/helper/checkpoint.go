@@ -0,0 +1,84 @@
+package helper
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// checkpointFailedMessages are the errors nmstate returns when it fails
+// taking the checkpoint to roll the desired state back
+var checkpointFailedMessages = []string{
+	"checkpoint create failed",
+	"checkpointcreate",
+	"failed to create checkpoint",
+	"failed to create a checkpoint",
+}
+
+// Values of the rollback checkpoint setting, there is no boolean nor
+// default parsing so a typo fails instead of disabling it
+const (
+	rollbackCheckpointEnabled = "enabled"
+	// Applies the desired state committing it right away when nmstate
+	// fails taking the checkpoint, it's not rolled back if the node loses
+	// connectivity
+	rollbackCheckpointUnsafeFallback = "unsafe-fallback"
+	// Applies every desired state without checkpoint, for disposable
+	// nodes where waiting for the rollback checkpoint only slows applies
+	rollbackCheckpointUnsafeDisabled = "unsafe-disabled"
+)
+
+var rollbackCheckpoint = rollbackCheckpointEnabled
+
+func init() {
+	var err error
+	rollbackCheckpoint, err = parseRollbackCheckpoint(os.Getenv("ROLLBACK_CHECKPOINT"))
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// parseRollbackCheckpoint returns the rollback checkpoint setting, enabled
+// if it's not set, only the exact values are taken
+func parseRollbackCheckpoint(value string) (string, error) {
+	switch value {
+	case "":
+		return rollbackCheckpointEnabled, nil
+	case rollbackCheckpointEnabled, rollbackCheckpointUnsafeFallback, rollbackCheckpointUnsafeDisabled:
+		return value, nil
+	}
+	return rollbackCheckpointEnabled, fmt.Errorf("invalid ROLLBACK_CHECKPOINT %q, it has to be %s, %s or %s", value, rollbackCheckpointEnabled, rollbackCheckpointUnsafeFallback, rollbackCheckpointUnsafeDisabled)
+}
+
+// RollbackCheckpointDisabled returns true if the handler is configured to
+// apply the desired states without rollback checkpoint
+func RollbackCheckpointDisabled() bool {
+	return rollbackCheckpoint == rollbackCheckpointUnsafeDisabled
+}
+
+// checkpointError is an nmstate failure taking the checkpoint, nothing was
+// applied at the node so it can be applied again later
+type checkpointError struct {
+	err error
+}
+
+func (e *checkpointError) Error() string {
+	return fmt.Sprintf("failed creating nmstate checkpoint, not applying desired state without it: %v", e.err)
+}
+
+func isCheckpointFailedOutput(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, checkpointFailedMessage := range checkpointFailedMessages {
+		if strings.Contains(message, checkpointFailedMessage) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCheckpointFailed returns true if the desired state was not applied
+// because nmstate failed taking the checkpoint to roll it back
+func IsCheckpointFailed(err error) bool {
+	_, checkpointFailed := err.(*checkpointError)
+	return checkpointFailed
+}
@@ -7,7 +7,6 @@ import (
 	"io"
 	"os"
 	"os/exec"
-	"strconv"
 	"strings"
 	"time"
 
@@ -51,15 +50,8 @@ func init() {
 	interfacesFilterGlob = glob.MustCompile(interfacesFilter)
 }
 
-func show(arguments ...string) (string, error) {
-	cmd := exec.Command(nmstateCommand, "show")
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to execute nmstatectl show: '%v', '%s', '%s'", err, stdout.String(), stderr.String())
-	}
-	return stdout.String(), nil
+func show() (string, error) {
+	return nmstatectl.Show()
 }
 
 func applyVlanFiltering(bridgeName string, ports []string) (string, error) {
@@ -70,21 +62,33 @@ func applyVlanFiltering(bridgeName string, ports []string) (string, error) {
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	if err := runCommand(cmd); err != nil {
 		return "", fmt.Errorf("failed to execute %s: '%v', '%s', '%s'", vlanFilteringCommand, err, stdout.String(), stderr.String())
 	}
 	return stdout.String(), nil
 }
 
-func nmstatectl(arguments []string, input string) (string, error) {
-	cmd := exec.Command(nmstateCommand, arguments...)
+func runNmstatectl(arguments []string, input string) (string, error) {
+	return runCommandWithTimeout(0, nmstateCommand, arguments, input)
+}
+
+// runCommandWithTimeout runs the command killing it if it does not return
+// before the timeout, without timeout if it's 0
+func runCommandWithTimeout(timeout time.Duration, command string, arguments []string, input string) (string, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, command, arguments...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	cmd.Stdout = &stdout
 	if input != "" {
 		stdin, err := cmd.StdinPipe()
 		if err != nil {
-			return "", fmt.Errorf("failed to create pipe for writing into %s: %v", nmstateCommand, err)
+			return "", fmt.Errorf("failed to create pipe for writing into %s: %v", command, err)
 		}
 		go func() {
 			defer stdin.Close()
@@ -95,16 +99,30 @@ func nmstatectl(arguments []string, input string) (string, error) {
 		}()
 
 	}
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to execute %s %s: '%v' '%s' '%s'", nmstateCommand, strings.Join(arguments, " "), err, stdout.String(), stderr.String())
+	if err := runCommand(cmd); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return stdout.String(), &applyTimeoutError{timeout: timeout}
+		}
+		return "", fmt.Errorf("failed to execute %s %s: '%v' '%s' '%s'", command, strings.Join(arguments, " "), err, stdout.String(), stderr.String())
 	}
 	return stdout.String(), nil
 
 }
 
-func set(desiredState nmstatev1alpha1.State) (string, error) {
+// set applies the desired state without committing it, it returns false if
+// it was applied and committed without a checkpoint. The checkpoint is kept
+// for dhcpWait longer, the time waiting for DHCP leases before the probes.
+func set(desiredState nmstatev1alpha1.State, dhcpWait time.Duration, applyTimeout time.Duration) (string, bool, error) {
 	output := ""
 	var err error = nil
+	if rollbackCheckpoint == rollbackCheckpointUnsafeDisabled {
+		log.Info("WARNING: rollback checkpoint is disabled, applying desired state without it, it will not be rolled back if the node loses connectivity")
+		output, err = nmstatectl.SetWithoutCheckpoint(string(desiredState.Raw), applyTimeout)
+		if err != nil && isBusyOutput(err) {
+			return output, false, &busyError{err: err}
+		}
+		return output, false, err
+	}
 	// FIXME: Remove this retries after nmstate team fixes
 	//        https://nmstate.atlassian.net/browse/NMSTATE-247
 	retries := 3
@@ -112,25 +130,76 @@ func set(desiredState nmstatev1alpha1.State) (string, error) {
 		// commit timeout doubles the default gw ping probe timeout, to
 		// ensure the Checkpoint is alive before rolling it back
 		// https://nmstate.github.io/cli_guide#manual-transaction-control
-		output, err = nmstatectl([]string{"set", "--no-commit", "--timeout", strconv.Itoa(defaultGwProbeTimeout * 2)}, string(desiredState.Raw))
+		output, err = nmstatectl.Set(string(desiredState.Raw), defaultGwProbeTimeout*2*time.Second+dhcpWait, applyTimeout)
 		if err == nil {
-			log.Info(fmt.Sprintf("nmstatectl set recovered, output: %s", output))
+			log.Info(fmt.Sprintf("nmstatectl set recovered, output: %s", redactSensitiveOutput(output)))
 			break
 		}
+		if IsApplyTimeout(err) {
+			// nmstatectl may have been killed after taking the checkpoint,
+			// it's rolled back instead of waiting for it to expire
+			_, rollbackErr := nmstatectl.Rollback()
+			if rollbackErr != nil {
+				log.Info(fmt.Sprintf("failed rolling back checkpoint after apply timeout, it may not have been taken: %v", rollbackErr))
+			}
+			return output, true, err
+		}
+		if isBusyOutput(err) {
+			// Retrying right away would hit the same transaction, the
+			// caller has to back off
+			return output, true, &busyError{err: err}
+		}
+		if isCheckpointFailedOutput(err) {
+			if rollbackCheckpoint != rollbackCheckpointUnsafeFallback {
+				return output, true, &checkpointError{err: err}
+			}
+			log.Info(fmt.Sprintf("nmstate failed creating checkpoint, applying desired state without it: %s", redactSensitiveOutput(err.Error())))
+			output, err = nmstatectl.SetWithoutCheckpoint(string(desiredState.Raw), applyTimeout)
+			return output, false, err
+		}
 		retries--
 		time.Sleep(1 * time.Second)
-		log.Info(fmt.Sprintf("%d retries left after nmstatectl set command error: %v", retries, err))
+		log.Info(fmt.Sprintf("%d retries left after nmstatectl set command error: %s", retries, redactSensitiveOutput(err.Error())))
 	}
-	return output, err
+	return output, true, err
 }
 
 func commit() (string, error) {
-	return nmstatectl([]string{"commit"}, "")
+	return nmstatectl.Commit()
 }
 
-func rollback(cause error) error {
-	_, err := nmstatectl([]string{"rollback"}, "")
-	return fmt.Errorf("rollback cause: %v, rollback error: %v", cause, err)
+// outOfBandRestores write back the settings the handler applies besides
+// nmstate to the values they had before, the nmstate checkpoint does not
+// cover them
+type outOfBandRestores []func() string
+
+func (r *outOfBandRestores) add(restore func() string) {
+	*r = append(*r, restore)
+}
+
+// restore runs the restores in the reverse order they were added
+func (r outOfBandRestores) restore() string {
+	output := ""
+	for i := len(r) - 1; i >= 0; i-- {
+		output += r[i]()
+	}
+	return output
+}
+
+// rollback rolls the checkpoint back after a failure applying the desired
+// state, restoring first the settings applied besides nmstate. Without
+// checkpoint there is nothing to roll back to so the failure is returned as
+// is. Either way it keeps the failure code of the cause.
+func rollback(checkpointed bool, restores outOfBandRestores, cause error) error {
+	if !checkpointed {
+		return &failureError{code: FailureCode(cause), err: fmt.Errorf("%v, not rolled back, desired state was applied without nmstate checkpoint", cause)}
+	}
+	restoreOutput := restores.restore()
+	if restoreOutput != "" {
+		log.Info(fmt.Sprintf("restored settings applied besides nmstate: %s", restoreOutput))
+	}
+	_, err := nmstatectl.Rollback()
+	return &failureError{code: FailureCode(cause), err: fmt.Errorf("rollback cause: %v, rollback error: %v", cause, err)}
 }
 
 func GetNodeNetworkState(client client.Client, nodeName string) (nmstatev1alpha1.NodeNetworkState, error) {
@@ -162,19 +231,84 @@ func InitializeNodeNeworkState(client client.Client, node *corev1.Node, scheme *
 }
 
 func UpdateCurrentState(client client.Client, nodeNetworkState *nmstatev1alpha1.NodeNetworkState) error {
+	previousHash, err := reportedStateHash(nodeNetworkState.Status)
+	if err != nil {
+		log.Error(err, "failed calculating previous reported state hash")
+	}
+
 	observedStateRaw, err := show()
 	if err != nil {
 		return fmt.Errorf("error running nmstatectl show: %v", err)
 	}
 	observedState := nmstatev1alpha1.State{Raw: []byte(observedStateRaw)}
+	stateToReport := reportedState(observedState)
 
-	stateToReport, err := filterOut(observedState, interfacesFilterGlob)
+	previousCarrierHistory := nodeNetworkState.Status.CarrierHistory
+	previousBonds := nodeNetworkState.Status.Bonds
+	nodeNetworkState.Status.CurrentState = stateToReport
+	nodeNetworkState.Status.Connections = nil
+	nodeNetworkState.Status.Devices = nil
+	nodeNetworkState.Status.RunningRoutes = nil
+	nodeNetworkState.Status.StaticNeighbors = nil
+	nodeNetworkState.Status.Bonds = nil
+	nodeNetworkState.Status.CarrierHistory = nil
+	nodeNetworkState.Status.InterfaceOwners = nil
+	nodeNetworkState.Status.SystemInfo = showSystemInfo()
+
+	devices, err := showDevices()
 	if err != nil {
-		fmt.Printf("failed filtering out interfaces from NodeNetworkState, keeping orignal content, please fix the glob: %v", err)
-		stateToReport = observedState
+		log.Error(err, "failed retrieving NetworkManager devices, not reporting them")
+	} else {
+		nodeNetworkState.Status.Devices = filterOutDevices(devices, interfacesFilterGlob)
 	}
 
-	nodeNetworkState.Status.CurrentState = stateToReport
+	bonds, err := showBonds(previousBonds, interfacesFilterGlob)
+	if err != nil {
+		log.Error(err, "failed retrieving bonds status, not reporting them")
+	} else {
+		nodeNetworkState.Status.Bonds = bonds
+	}
+
+	if carrierHistoryLength > 0 {
+		carrierHistory, err := showCarrierHistory(previousCarrierHistory, interfacesFilterGlob)
+		if err != nil {
+			log.Error(err, "failed retrieving interfaces carrier, not reporting their history")
+		} else {
+			nodeNetworkState.Status.CarrierHistory = carrierHistory
+		}
+	}
+
+	runningRoutes, err := showRunningRoutes(interfacesFilterGlob)
+	if err != nil {
+		log.Error(err, "failed retrieving running routes, not reporting them")
+	} else {
+		nodeNetworkState.Status.RunningRoutes = runningRoutes
+	}
+
+	staticNeighbors, err := showStaticNeighbors(interfacesFilterGlob)
+	if err != nil {
+		log.Error(err, "failed retrieving static neighbors, not reporting them")
+	} else {
+		nodeNetworkState.Status.StaticNeighbors = staticNeighbors
+	}
+
+	connections, err := showConnections()
+	if err != nil {
+		log.Error(err, "failed retrieving NetworkManager connections, not reporting them")
+	} else if interfaces, err := enactedInterfaces(client, nodeNetworkState.Name); err != nil {
+		// Without them the connections externally managed are unknown
+		log.Error(err, "failed retrieving enacted interfaces, not reporting NetworkManager connections")
+	} else {
+		nodeNetworkState.Status.Connections = filterOutConnections(connections, interfacesFilterGlob, interfaces)
+	}
+
+	owners, err := showInterfaceOwners(client, nodeNetworkState.Name, interfacesFilterGlob)
+	if err != nil {
+		log.Error(err, "failed retrieving interface owners, not reporting them")
+	} else {
+		nodeNetworkState.Status.InterfaceOwners = owners
+	}
+	bumpConfigSerial(&nodeNetworkState.Status, previousHash)
 	nodeNetworkState.Status.LastSuccessfulUpdateTime = metav1.Time{Time: time.Now()}
 
 	err = client.Status().Update(context.Background(), nodeNetworkState)
@@ -185,6 +319,127 @@ func UpdateCurrentState(client client.Client, nodeNetworkState *nmstatev1alpha1.
 	return nil
 }
 
+// reportedState returns the observed state as it's reported at the
+// NodeNetworkState, without the filtered out interfaces and with the
+// settings the handler applies besides nmstate, so the desired states
+// setting them can be compared with it
+func reportedState(observedState nmstatev1alpha1.State) nmstatev1alpha1.State {
+	stateToReport, err := filterOut(observedState, interfacesFilterGlob)
+	if err != nil {
+		fmt.Printf("failed filtering out interfaces from NodeNetworkState, keeping orignal content, please fix the glob: %v", err)
+		stateToReport = observedState
+	}
+
+	stateWithPromiscFlags, err := reportPromiscFlags(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting interfaces promiscuous mode at NodeNetworkState")
+	} else {
+		stateToReport = stateWithPromiscFlags
+	}
+
+	stateWithWakeOnLan, err := reportWakeOnLan(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting interfaces wake-on-lan at NodeNetworkState")
+	} else {
+		stateToReport = stateWithWakeOnLan
+	}
+
+	stateWithQdiscs, err := reportQdiscs(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting interfaces qdiscs at NodeNetworkState")
+	} else {
+		stateToReport = stateWithQdiscs
+	}
+
+	stateWithFirewalldZones, err := reportFirewalldZones(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting interfaces firewalld zones at NodeNetworkState")
+	} else {
+		stateToReport = stateWithFirewalldZones
+	}
+
+	stateWithOvsExternalIDs, err := reportOvsExternalIDs(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting Open vSwitch external ids at NodeNetworkState")
+	} else {
+		stateToReport = stateWithOvsExternalIDs
+	}
+
+	stateWithDHCPLeases, err := reportDHCPLeases(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting interfaces DHCP leases at NodeNetworkState")
+	} else {
+		stateToReport = stateWithDHCPLeases
+	}
+
+	stateWithHardware, err := reportHardware(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting interfaces hardware at NodeNetworkState")
+	} else {
+		stateToReport = stateWithHardware
+	}
+
+	stateWithTimestamping, err := reportTimestamping(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting interfaces timestamping at NodeNetworkState")
+	} else {
+		stateToReport = stateWithTimestamping
+	}
+
+	stateWithBridgesSTP, err := reportBridgesSTP(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting bridges spanning tree state at NodeNetworkState")
+	} else {
+		stateToReport = stateWithBridgesSTP
+	}
+
+	stateWithSysctls, err := reportSysctls(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting interfaces sysctls at NodeNetworkState")
+	} else {
+		stateToReport = stateWithSysctls
+	}
+
+	stateWithForwarding, err := reportForwarding(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting global forwarding at NodeNetworkState")
+	} else {
+		stateToReport = stateWithForwarding
+	}
+
+	stateWithDisableIPv6, err := reportDisableIPv6(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting global IPv6 disable at NodeNetworkState")
+	} else {
+		stateToReport = stateWithDisableIPv6
+	}
+
+	stateWithBridgesMulticast, err := reportBridgesMulticast(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting bridges multicast options at NodeNetworkState")
+	} else {
+		stateToReport = stateWithBridgesMulticast
+	}
+
+	stateWithTunnels, err := reportTunnels(stateToReport)
+	if err != nil {
+		log.Error(err, "failed reporting tunnels at NodeNetworkState")
+	} else {
+		stateToReport = stateWithTunnels
+	}
+	return stateToReport
+}
+
+// ReportedCurrentState returns the node current state as it's reported at
+// the NodeNetworkState
+func ReportedCurrentState() (nmstatev1alpha1.State, error) {
+	observedStateRaw, err := show()
+	if err != nil {
+		return nmstatev1alpha1.State{}, fmt.Errorf("error running nmstatectl show: %v", err)
+	}
+	return reportedState(nmstatev1alpha1.State{Raw: []byte(observedStateRaw)}), nil
+}
+
 func ping(target string, timeout time.Duration) (string, error) {
 	output := ""
 	return output, wait.PollImmediate(1*time.Second, timeout, func() (bool, error) {
@@ -192,7 +447,7 @@ func ping(target string, timeout time.Duration) (string, error) {
 		var outputBuffer bytes.Buffer
 		cmd.Stdout = &outputBuffer
 		cmd.Stderr = &outputBuffer
-		err := cmd.Run()
+		err := runCommand(cmd)
 		output = fmt.Sprintf("cmd output: '%s'", outputBuffer.String())
 		if err != nil {
 			return false, nil
@@ -239,21 +494,267 @@ func defaultGw() (string, error) {
 		defaultGw = gjson.ParseBytes([]byte(currentState)).
 			Get("routes.running.#(destination==\"0.0.0.0/0\").next-hop-address").String()
 		if defaultGw == "" {
-			return false, fmt.Errorf("Impossible to retrieve default gw, state: %s", string(observedStateRaw))
+			return false, fmt.Errorf("Impossible to retrieve default gw, state: %s", redactState(string(observedStateRaw)))
 		}
 
 		return true, nil
 	})
 }
 
-func ApplyDesiredState(desiredState nmstatev1alpha1.State) (string, error) {
+// ApplyDesiredState applies the desired state with nmstate, the values of the
+// sensitive keys echoed by nmstate are redacted from its output and errors.
+// With route table cleanup the stale routes of the desired state route
+// tables are removed too, and collected at it.
+func ApplyDesiredState(desiredState nmstatev1alpha1.State, readinessChecks []nmstatev1alpha1.ReadinessCheck, applyTimeout time.Duration, routeTableCleanup *RouteTableCleanup) (string, error) {
+	output, err := applyDesiredState(desiredState, readinessChecks, applyTimeout, routeTableCleanup)
+	return redactSensitiveOutput(output), redactSensitiveError(err)
+}
+
+func applyDesiredState(desiredState nmstatev1alpha1.State, readinessChecks []nmstatev1alpha1.ReadinessCheck, applyTimeout time.Duration, routeTableCleanup *RouteTableCleanup) (string, error) {
 	if len(string(desiredState.Raw)) == 0 {
 		return "Ignoring empty desired state", nil
 	}
 
-	setOutput, err := set(desiredState)
+	// The desired state may come as JSON from programmatic producers, it's
+	// normalized so the handler and nmstate get the same YAML
+	desiredState, err := nmstatev1alpha1.NormalizeState(desiredState)
+	if err != nil {
+		return "", fmt.Errorf("error normalizing desired state: %v", err)
+	}
+
+	err = checkVxlanIDs(desiredState)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkInfiniband(desiredState)
+	if err != nil {
+		return "", err
+	}
+
+	desiredState, err = checkTeardown(desiredState)
+	if err != nil {
+		return "", err
+	}
+
+	// nmstate does not support the interfaces sysctls, they are removed
+	// first so the interfaces added only to reset them are not seen by
+	// the rest of the desired state handling
+	sysctls, err := getSysctls(desiredState)
+	if err != nil {
+		return "", err
+	}
+	desiredState, err = stripSysctls(desiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing sysctls from desired state: %v", err)
+	}
+
+	// Nor the global forwarding
+	forwarding, err := getForwarding(desiredState)
+	if err != nil {
+		return "", err
+	}
+	desiredState, err = stripForwarding(desiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing forwarding from desired state: %v", err)
+	}
+
+	// Nor the global IPv6 disable
+	disableIPv6, err := getDisableIPv6(desiredState)
+	if err != nil {
+		return "", err
+	}
+	desiredState, err = stripDisableIPv6(desiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing disable-ipv6 from desired state: %v", err)
+	}
+
+	// Nor the bridges multicast options besides snooping
+	bridgesMulticast, err := getBridgesMulticast(desiredState)
+	if err != nil {
+		return "", err
+	}
+	desiredState, err = stripBridgesMulticast(desiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing bridges multicast options from desired state: %v", err)
+	}
+
+	// nmstate does not support promiscuous mode, it's removed from the
+	// desired state and applied after it with iproute
+	promiscFlags, err := getPromiscFlags(desiredState)
+	if err != nil {
+		return "", err
+	}
+	nmstateDesiredState, err := stripPromiscFlags(desiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing promiscuous mode from desired state: %v", err)
+	}
+
+	// Neither it supports traffic control, the interfaces qdiscs are
+	// applied with tc
+	qdiscs, err := getQdiscs(desiredState)
+	if err != nil {
+		return "", err
+	}
+	nmstateDesiredState, err = stripQdiscs(nmstateDesiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing qdiscs from desired state: %v", err)
+	}
+
+	// Nor Wake-on-LAN, it's set with ethtool, failing before applying
+	// anything if the interfaces drivers do not support the modes
+	wakeOnLan, err := getWakeOnLan(desiredState)
+	if err != nil {
+		return "", err
+	}
+	err = checkWakeOnLan(wakeOnLan)
+	if err != nil {
+		return "", err
+	}
+	nmstateDesiredState, err = stripWakeOnLan(nmstateDesiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing wake-on-lan from desired state: %v", err)
+	}
+
+	// Nor firewalld zones, they are set at the interfaces connections with
+	// nmcli, failing before applying anything if firewalld is not running
+	firewalldZones, err := getFirewalldZones(desiredState)
+	if err != nil {
+		return "", err
+	}
+	err = checkFirewalld(firewalldZones)
+	if err != nil {
+		return "", err
+	}
+	nmstateDesiredState, err = stripFirewalldZones(nmstateDesiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing firewalld zones from desired state: %v", err)
+	}
+
+	// Connection names either, nmstate names the connections after the
+	// interfaces, they are renamed with nmcli
+	connectionNames, err := getConnectionNames(desiredState)
+	if err != nil {
+		return "", err
+	}
+	nmstateDesiredState, err = stripConnectionNames(nmstateDesiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing connection names from desired state: %v", err)
+	}
+
+	// Neither the DHCP fallbacks, the connections without lease are
+	// configured with the static addresses with nmcli
+	dhcpFallbacks, err := getDHCPFallbacks(desiredState)
+	if err != nil {
+		return "", err
+	}
+	nmstateDesiredState, err = stripDHCPFallbacks(nmstateDesiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing DHCP fallbacks from desired state: %v", err)
+	}
+
+	// Nor the Open vSwitch external ids, they are set with ovs-vsctl,
+	// failing before applying anything if Open vSwitch is not running
+	ovsExternalIDs, err := getOvsExternalIDs(desiredState)
+	if err != nil {
+		return "", err
+	}
+	err = checkOvs(ovsExternalIDs)
+	if err != nil {
+		return "", err
+	}
+	nmstateDesiredState, err = stripOvsExternalIDs(nmstateDesiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing Open vSwitch external ids from desired state: %v", err)
+	}
+
+	// Neither the route preferred source addresses and scopes, they are set
+	// at the next hop interfaces connections with nmcli
+	routeAttributes, err := getRouteAttributes(desiredState)
+	if err != nil {
+		return "", err
+	}
+	nmstateDesiredState, err = stripRouteAttributes(nmstateDesiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing route attributes from desired state: %v", err)
+	}
+
+	// Nor static neighbors, they are configured with iproute
+	neighbors, err := getNeighbors(desiredState)
+	if err != nil {
+		return "", err
+	}
+	nmstateDesiredState, err = stripNeighbors(nmstateDesiredState)
 	if err != nil {
-		return setOutput, err
+		return "", fmt.Errorf("error removing neighbors from desired state: %v", err)
+	}
+
+	// Nor the GRE and IPIP tunnels, they are created with iproute too,
+	// failing before applying anything if their base interfaces are missing
+	tunnels, err := getTunnels(desiredState)
+	if err != nil {
+		return "", err
+	}
+	err = checkTunnels(desiredState, tunnels)
+	if err != nil {
+		return "", err
+	}
+	nmstateDesiredState, err = stripTunnels(nmstateDesiredState)
+	if err != nil {
+		return "", fmt.Errorf("error removing tunnels from desired state: %v", err)
+	}
+
+	// nmstate does not change the interfaces MTU in the desired state order,
+	// they are changed one by one before applying it, so base interfaces are
+	// raised before the VLANs on top of them and lowered after them
+	mtuInterfaces, err := getMTUInterfaces(desiredState)
+	if err != nil {
+		return "", err
+	}
+	currentMTUs := readMTUs(mtuInterfaces)
+	mtuChanges := mtuSteps(mtuInterfaces, currentMTUs)
+
+	mtuOutput, err := applyMTUSteps(mtuChanges, currentMTUs)
+	if err != nil {
+		return mtuOutput, fmt.Errorf("error changing MTU before applying desired state: %v", err)
+	}
+
+	// The settings applied besides nmstate are read before it applies the
+	// desired state, they are restored to these values on rollback
+	restores := outOfBandRestores{}
+	restores.add(func() string { return restoreMTUs(mtuChanges, currentMTUs) })
+	previousTunnels := readTunnels(tunnels)
+	previousPromiscFlags := readPromiscFlags(promiscFlags)
+	previousQdiscs := readQdiscs(qdiscs)
+	previousWakeOnLan := readWakeOnLan(wakeOnLan)
+	previousNeighbors := readNeighbors(neighbors)
+	previousForwarding := readForwarding(sysctlNetDir, forwarding)
+	previousDisableIPv6 := readDisableIPv6(sysctlNetDir, disableIPv6)
+	previousSysctls := readInterfacesSysctls(sysctlNetDir, sysctls)
+	previousBridgesMulticast := readBridgesMulticast(sysClassNetDir, bridgesMulticast)
+	previousOvsExternalIDs := readOvsExternalIDs(ovsExternalIDs)
+
+	setOutput, checkpointed, err := set(nmstateDesiredState, dhcpFallbacksTimeout(dhcpFallbacks), applyTimeout)
+	if err != nil {
+		return setOutput + restoreMTUs(mtuChanges, currentMTUs), err
+	}
+
+	// The tunnels are created once nmstate has created their base
+	// interfaces, so the rest of the settings can be applied to them
+	commandOutput := mtuOutput
+	restores.add(func() string { return restoreTunnels(previousTunnels) })
+	outputTunnels, err := applyTunnels(tunnels)
+	commandOutput += outputTunnels
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	// Falling back reactivates the connections, it goes before the
+	// settings applied to the running interfaces so they are not lost
+	outputDHCPFallbacks, err := applyDHCPFallbacks(dhcpFallbacks)
+	commandOutput += outputDHCPFallbacks
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
 	}
 
 	// Future versions of nmstate/NM will support vlan-filtering meanwhile
@@ -262,43 +763,152 @@ func ApplyDesiredState(desiredState nmstatev1alpha1.State) (string, error) {
 	// set
 	bridgesUpWithPorts, err := getBridgesUp(desiredState)
 	if err != nil {
-		return "", rollback(fmt.Errorf("error retrieving up bridges from desired state"))
+		return "", rollback(checkpointed, restores, fmt.Errorf("error retrieving up bridges from desired state"))
 	}
 
-	commandOutput := ""
 	for bridge, ports := range bridgesUpWithPorts {
 		outputVlanFiltering, err := applyVlanFiltering(bridge, ports)
 		commandOutput += fmt.Sprintf("bridge %s ports %v applyVlanFiltering command output: %s\n", bridge, ports, outputVlanFiltering)
 		if err != nil {
-			return commandOutput, rollback(err)
+			return commandOutput, rollback(checkpointed, restores, err)
+		}
+	}
+
+	restores.add(func() string { return restorePromiscFlags(previousPromiscFlags) })
+	outputPromisc, err := applyPromiscFlags(promiscFlags)
+	commandOutput += outputPromisc
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	restores.add(func() string { return restoreQdiscs(previousQdiscs) })
+	outputQdiscs, err := applyQdiscs(qdiscs)
+	commandOutput += outputQdiscs
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	restores.add(func() string { return restoreWakeOnLan(previousWakeOnLan) })
+	outputWakeOnLan, err := applyWakeOnLan(wakeOnLan)
+	commandOutput += outputWakeOnLan
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	// The firewalld zones, connection names and route attributes are set
+	// at the NetworkManager connections, the connections are part of the
+	// nmstate checkpoint so they are rolled back with it without restores
+	outputFirewalldZones, err := applyFirewalldZones(firewalldZones)
+	commandOutput += outputFirewalldZones
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	outputConnectionNames, err := applyConnectionNames(connectionNames)
+	commandOutput += outputConnectionNames
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	outputRouteAttributes, err := applyRouteAttributes(routeAttributes)
+	commandOutput += outputRouteAttributes
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	restores.add(func() string { return restoreNeighbors(previousNeighbors) })
+	outputNeighbors, err := applyNeighbors(neighbors)
+	commandOutput += outputNeighbors
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	restores.add(func() string { return restoreForwarding(sysctlNetDir, previousForwarding) })
+	outputForwarding, err := applyForwarding(sysctlNetDir, forwarding)
+	commandOutput += outputForwarding
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	restores.add(func() string { return restoreDisableIPv6(sysctlNetDir, previousDisableIPv6) })
+	outputDisableIPv6, err := applyDisableIPv6(sysctlNetDir, disableIPv6)
+	commandOutput += outputDisableIPv6
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	restores.add(func() string { return restoreSysctls(sysctlNetDir, previousSysctls) })
+	outputSysctls, err := applySysctls(sysctlNetDir, sysctls)
+	commandOutput += outputSysctls
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	restores.add(func() string { return restoreBridgesMulticast(sysClassNetDir, previousBridgesMulticast) })
+	outputBridgesMulticast, err := applyBridgesMulticast(sysClassNetDir, bridgesMulticast)
+	commandOutput += outputBridgesMulticast
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	// The Open vSwitch external ids are applied before the connectivity
+	// checks too, OVN may remap its networks with them
+	restores.add(func() string { return restoreOvsExternalIDs(previousOvsExternalIDs) })
+	outputOvsExternalIDs, err := applyOvsExternalIDs(ovsExternalIDs)
+	commandOutput += outputOvsExternalIDs
+	if err != nil {
+		return commandOutput, rollback(checkpointed, restores, err)
+	}
+
+	// The stale routes are removed before the connectivity checks, so
+	// they are checked without them and put back on rollback
+	if routeTableCleanup != nil {
+		removedRoutes := []staleRoute{}
+		restores.add(func() string { return restoreStaleRoutes(removedRoutes) })
+		removedRoutes, err = removeStaleRoutes(desiredState)
+		if err != nil {
+			log.Info(fmt.Sprintf("failed removing stale routes: %v", err))
+		}
+		routeTableCleanup.RemovedRoutes = []string{}
+		for _, removed := range removedRoutes {
+			routeTableCleanup.RemovedRoutes = append(routeTableCleanup.RemovedRoutes, removed.String())
 		}
 	}
 
 	defaultGw, err := defaultGw()
 	if err != nil {
-		return commandOutput, rollback(err)
+		return commandOutput, rollback(checkpointed, restores, err)
 	}
 
 	currentState, err := show()
 	if err != nil {
-		return "", rollback(err)
+		return "", rollback(checkpointed, restores, err)
 	}
 
 	// TODO: Make ping timeout configurable with a config map
 	pingOutput, err := ping(defaultGw, defaultGwProbeTimeout*time.Second)
 	if err != nil {
-		return pingOutput, rollback(fmt.Errorf("error pinging external address after network reconfiguration -> error: %v, currentState: %s", err, currentState))
+		return pingOutput, rollback(checkpointed, restores, &failureError{code: nmstatev1alpha1.FailureCodeConnectivityLost, err: fmt.Errorf("error pinging external address after network reconfiguration -> error: %v, currentState: %s", err, redactState(currentState))})
 	}
 
 	err = checkApiServerConnectivity(apiServerProbeTimeout * time.Second)
 	if err != nil {
-		return "", rollback(fmt.Errorf("error checking api server connectivity after network reconfiguration -> error: %v, currentState: %s", err, currentState))
+		return "", rollback(checkpointed, restores, &failureError{code: nmstatev1alpha1.FailureCodeConnectivityLost, err: fmt.Errorf("error checking api server connectivity after network reconfiguration -> error: %v, currentState: %s", err, redactState(currentState))})
 	}
 
-	commitOutput, err := commit()
+	err = checkReadiness(readinessChecks, readinessCheckTimeout*time.Second)
 	if err != nil {
-		// We cannot rollback if commit fails, just return the error
-		return commitOutput, err
+		return "", rollback(checkpointed, restores, &failureError{code: nmstatev1alpha1.FailureCodeReadinessFailed, err: fmt.Errorf("error checking readiness after network reconfiguration -> error: %v, currentState: %s", err, redactState(currentState))})
+	}
+
+	if !checkpointed {
+		commandOutput += "desired state applied without nmstate checkpoint, it was not rolled back on failure\n"
+	} else {
+		commitOutput, err := commit()
+		if err != nil {
+			// We cannot rollback if commit fails, just return the error
+			return commitOutput, err
+		}
 	}
 
 	commandOutput += fmt.Sprintf("setOutput: %s \n", setOutput)
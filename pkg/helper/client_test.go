@@ -115,3 +115,81 @@ var _ = Describe("FilterOut", func() {
 		})
 	})
 })
+
+var _ = Describe("Desired state formats", func() {
+	yamlState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  promisc: true
+  mtu: 9000
+routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-address: 192.0.2.1
+    next-hop-interface: eth1
+    source: 192.0.2.11
+`)
+	jsonState := nmstatev1alpha1.NewState(`{
+	"interfaces": [
+		{"name": "eth1", "type": "ethernet", "state": "up", "promisc": true, "mtu": 9000}
+	],
+	"routes": {
+		"config": [
+			{
+				"destination": "198.51.100.0/24",
+				"next-hop-address": "192.0.2.1",
+				"next-hop-interface": "eth1",
+				"source": "192.0.2.11"
+			}
+		]
+	}
+}`)
+
+	nmstateDesiredState := func(desiredState nmstatev1alpha1.State) nmstatev1alpha1.State {
+		normalizedState, err := nmstatev1alpha1.NormalizeState(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		strippedState, err := stripPromiscFlags(normalizedState)
+		Expect(err).ToNot(HaveOccurred())
+		strippedState, err = stripRouteAttributes(strippedState)
+		Expect(err).ToNot(HaveOccurred())
+		return strippedState
+	}
+
+	It("should render the same nmstate desired state from YAML and JSON", func() {
+		Expect(nmstateDesiredState(jsonState)).To(Equal(nmstateDesiredState(yamlState)))
+		Expect(nmstateDesiredState(jsonState).String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  mtu: 9000
+routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-address: 192.0.2.1
+    next-hop-interface: eth1
+`))
+	})
+
+	It("should take the same options not supported by nmstate from YAML and JSON", func() {
+		yamlPromiscFlags, err := getPromiscFlags(yamlState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getPromiscFlags(jsonState)).To(Equal(yamlPromiscFlags))
+
+		yamlRouteAttributes, err := getRouteAttributes(yamlState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getRouteAttributes(jsonState)).To(Equal(yamlRouteAttributes))
+	})
+
+	It("should render the same desired state from YAML and JSON patches", func() {
+		currentState := nmstatev1alpha1.NewState(`{"interfaces": [{"name": "eth1", "type": "ethernet", "state": "down"}]}`)
+		yamlRendered, err := RenderDesiredState(currentState, nmstatev1alpha1.NewStatePatch(`- op: replace
+  path: /interfaces/0/state
+  value: up
+`))
+		Expect(err).ToNot(HaveOccurred())
+		jsonRendered, err := RenderDesiredState(currentState, nmstatev1alpha1.NewStatePatch(`[{"op": "replace", "path": "/interfaces/0/state", "value": "up"}]`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(jsonRendered).To(Equal(yamlRendered))
+	})
+})
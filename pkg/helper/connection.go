@@ -0,0 +1,156 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const connectionNameKey = "connection-name"
+
+// getConnectionNames returns the NetworkManager connection name of the
+// desired state interfaces that configure it, the rest keep the name given
+// by nmstate, the interface name. Absent interfaces are ignored.
+func getConnectionNames(desiredState nmstatev1alpha1.State) (map[string]string, error) {
+	names := map[string]string{}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return names, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		name := iface.Get(connectionNameKey)
+		if !name.Exists() || iface.Get("state").String() == "absent" {
+			continue
+		}
+		names[iface.Get("name").String()] = name.String()
+	}
+	return names, nil
+}
+
+// stripConnectionNames removes the connection names, not supported by
+// nmstate, from the desired state
+func stripConnectionNames(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return stripInterfacesKeys(desiredState, connectionNameKey)
+}
+
+func sortedConnectionInterfaces(names map[string]string) []string {
+	interfaces := []string{}
+	for iface := range names {
+		interfaces = append(interfaces, iface)
+	}
+	sort.Strings(interfaces)
+	return interfaces
+}
+
+// applyConnectionNames renames the connection activated by nmstate at the
+// interfaces
+func applyConnectionNames(names map[string]string) (string, error) {
+	output := ""
+	for _, iface := range sortedConnectionInterfaces(names) {
+		name := names[iface]
+		activeConnection, err := nmcli("-g", "GENERAL.CONNECTION", "device", "show", iface)
+		if err != nil {
+			return output, err
+		}
+		activeConnection = strings.TrimSpace(activeConnection)
+		if activeConnection == "" {
+			return output, fmt.Errorf("interface %s has no active connection to name %s", iface, name)
+		}
+		if activeConnection == name {
+			continue
+		}
+		nmcliOutput, err := nmcli("connection", "modify", activeConnection, "connection.id", name)
+		output += fmt.Sprintf("interface %s connection %s renamed to %s output: %s\n", iface, activeConnection, name, nmcliOutput)
+		if err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+// connectionNameConflicts fails if any of the connection names is already
+// taken by a connection not named by the node's enactments, renaming the
+// interface connection would clobber it
+func connectionNameConflicts(names map[string]string, connections []nmstatev1alpha1.NetworkManagerConnection, enactedUUIDs map[string]bool) error {
+	for _, iface := range sortedConnectionInterfaces(names) {
+		for _, connection := range connections {
+			if connection.Name == names[iface] && !enactedUUIDs[connection.UUID] {
+				return fmt.Errorf("connection %s requested for interface %s already exists and is externally managed", connection.Name, iface)
+			}
+		}
+	}
+	return nil
+}
+
+// enactedConnectionUUIDs returns the UUID of the connections named by the
+// node's enactments
+func enactedConnectionUUIDs(cli client.Client, nodeName string) (map[string]bool, error) {
+	enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+	err := cli.List(context.TODO(), &enactments, client.MatchingLabels{nmstatev1alpha1.EnactmentNodeLabel: nodeName})
+	if err != nil {
+		return nil, fmt.Errorf("failed listing node enactments: %v", err)
+	}
+
+	uuids := map[string]bool{}
+	for _, enactment := range enactments.Items {
+		for _, connection := range enactment.Status.Connections {
+			uuids[connection.UUID] = true
+		}
+	}
+	return uuids, nil
+}
+
+// CheckConnectionNames fails if the desired state names the connection of an
+// interface after an existing connection that is externally managed
+func CheckConnectionNames(cli client.Client, nodeName string, desiredState nmstatev1alpha1.State) error {
+	names, err := getConnectionNames(desiredState)
+	if err != nil || len(names) == 0 {
+		return err
+	}
+	connections, err := showConnections()
+	if err != nil {
+		return err
+	}
+	uuids, err := enactedConnectionUUIDs(cli, nodeName)
+	if err != nil {
+		return err
+	}
+	return connectionNameConflicts(names, connections, uuids)
+}
+
+// namedConnections returns the connections of the interfaces named at the
+// desired state
+func namedConnections(names map[string]string, connections []nmstatev1alpha1.NetworkManagerConnection) []nmstatev1alpha1.NetworkManagerConnection {
+	named := []nmstatev1alpha1.NetworkManagerConnection{}
+	for _, iface := range sortedConnectionInterfaces(names) {
+		for _, connection := range connections {
+			if connection.Name == names[iface] && connection.Device == iface {
+				named = append(named, connection)
+			}
+		}
+	}
+	return named
+}
+
+// NamedConnections returns the connections named by the desired state, to
+// report their UUID
+func NamedConnections(desiredState nmstatev1alpha1.State) ([]nmstatev1alpha1.NetworkManagerConnection, error) {
+	names, err := getConnectionNames(desiredState)
+	if err != nil || len(names) == 0 {
+		return nil, err
+	}
+	connections, err := showConnections()
+	if err != nil {
+		return nil, err
+	}
+	return namedConnections(names, connections), nil
+}
@@ -0,0 +1,73 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Connection names", func() {
+	desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  connection-name: inventory-eth1
+- name: eth2
+  type: ethernet
+  state: up
+- name: eth3
+  type: ethernet
+  state: absent
+  connection-name: inventory-eth3
+`)
+
+	connections := []nmstatev1alpha1.NetworkManagerConnection{
+		{Name: "inventory-eth1", UUID: "6b2e4c7a-0c7d-4f7e-9a53-1d1a2a0c1e01", Type: "802-3-ethernet", Device: "eth1"},
+		{Name: "eth2", UUID: "6b2e4c7a-0c7d-4f7e-9a53-1d1a2a0c1e02", Type: "802-3-ethernet", Device: "eth2"},
+		{Name: "inventory-eth4", UUID: "6b2e4c7a-0c7d-4f7e-9a53-1d1a2a0c1e04", Type: "802-3-ethernet"},
+	}
+
+	It("should name only the connections of the interfaces with connection name", func() {
+		names, err := getConnectionNames(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(names).To(Equal(map[string]string{
+			"eth1": "inventory-eth1",
+		}))
+	})
+
+	It("should remove the connection names from the desired state passed to nmstate", func() {
+		strippedState, err := stripConnectionNames(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+- name: eth2
+  type: ethernet
+  state: up
+- name: eth3
+  type: ethernet
+  state: absent
+`))
+	})
+
+	It("should allow names not taken yet", func() {
+		Expect(connectionNameConflicts(map[string]string{"eth2": "inventory-eth2"}, connections, map[string]bool{})).To(Succeed())
+	})
+
+	It("should allow names given by the enactments", func() {
+		enactedUUIDs := map[string]bool{"6b2e4c7a-0c7d-4f7e-9a53-1d1a2a0c1e01": true}
+		Expect(connectionNameConflicts(map[string]string{"eth1": "inventory-eth1"}, connections, enactedUUIDs)).To(Succeed())
+	})
+
+	It("should fail for names of externally managed connections", func() {
+		err := connectionNameConflicts(map[string]string{"eth4": "inventory-eth4"}, connections, map[string]bool{})
+		Expect(err).To(MatchError("connection inventory-eth4 requested for interface eth4 already exists and is externally managed"))
+	})
+
+	It("should report the named connections of the interfaces", func() {
+		names := map[string]string{"eth1": "inventory-eth1", "eth4": "inventory-eth4"}
+		Expect(namedConnections(names, connections)).To(Equal([]nmstatev1alpha1.NetworkManagerConnection{connections[0]}))
+	})
+})
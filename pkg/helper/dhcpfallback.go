@@ -0,0 +1,219 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"k8s.io/apimachinery/pkg/util/wait"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// nmstate keeps waiting for a DHCP lease, the interfaces with dhcp-fallback
+// are configured with its static IPv4 addresses if they get no lease within
+// its dhcp-timeout
+const dhcpFallbackKey = "dhcp-fallback"
+
+const (
+	defaultDHCPFallbackTimeout = 30 * time.Second
+	maxDHCPFallbackTimeout     = 5 * time.Minute
+	dhcpFallbackPollInterval   = 1 * time.Second
+)
+
+// dhcpFallback is the static configuration of an interface without lease
+type dhcpFallback struct {
+	timeout   time.Duration
+	addresses []string
+}
+
+// getDHCPFallbacks returns the DHCP fallback of the desired state interfaces,
+// failing if they do not enable DHCPv4 or their addresses are not IPv4
+func getDHCPFallbacks(desiredState nmstatev1alpha1.State) (map[string]dhcpFallback, error) {
+	fallbacks := map[string]dhcpFallback{}
+	if len(desiredState.Raw) == 0 {
+		return fallbacks, nil
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return fallbacks, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		fallback := iface.Get(dhcpFallbackKey)
+		if !fallback.Exists() || iface.Get("state").String() == "absent" {
+			continue
+		}
+		name := iface.Get("name").String()
+		if !iface.Get("ipv4.dhcp").Bool() {
+			return fallbacks, fmt.Errorf("invalid interface %s dhcp-fallback, it needs ipv4 dhcp enabled", name)
+		}
+
+		timeout := defaultDHCPFallbackTimeout
+		if dhcpTimeout := fallback.Get("dhcp-timeout"); dhcpTimeout.Exists() {
+			if dhcpTimeout.Type != gjson.Number || dhcpTimeout.Int() <= 0 || float64(dhcpTimeout.Int()) != dhcpTimeout.Float() {
+				return fallbacks, fmt.Errorf("invalid interface %s dhcp-fallback dhcp-timeout %s, it has to be a positive number of seconds", name, dhcpTimeout.Raw)
+			}
+			timeout = time.Duration(dhcpTimeout.Int()) * time.Second
+			if timeout > maxDHCPFallbackTimeout {
+				return fallbacks, fmt.Errorf("invalid interface %s dhcp-fallback dhcp-timeout %s, it cannot be longer than %s", name, dhcpTimeout.Raw, maxDHCPFallbackTimeout)
+			}
+		}
+
+		addresses := []string{}
+		for _, address := range fallback.Get("address").Array() {
+			ip := net.ParseIP(address.Get("ip").String())
+			prefixLength := address.Get("prefix-length")
+			if ip == nil || ip.To4() == nil {
+				return fallbacks, fmt.Errorf("invalid interface %s dhcp-fallback address %s, it has to be IPv4", name, address.Get("ip").Raw)
+			}
+			if prefixLength.Type != gjson.Number || prefixLength.Int() < 0 || prefixLength.Int() > 32 {
+				return fallbacks, fmt.Errorf("invalid interface %s dhcp-fallback address %s prefix-length %s", name, ip, prefixLength.Raw)
+			}
+			addresses = append(addresses, fmt.Sprintf("%s/%d", ip, prefixLength.Int()))
+		}
+		if len(addresses) == 0 {
+			return fallbacks, fmt.Errorf("invalid interface %s dhcp-fallback, it has no address", name)
+		}
+		fallbacks[name] = dhcpFallback{timeout: timeout, addresses: addresses}
+	}
+	return fallbacks, nil
+}
+
+// stripDHCPFallbacks removes the DHCP fallbacks, not supported by nmstate,
+// from the desired state
+func stripDHCPFallbacks(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return stripInterfacesKeys(desiredState, dhcpFallbackKey)
+}
+
+// hasDHCPv4Address returns true if the output of "ip -j addr show dev" has
+// a dynamic IPv4 address, the ones leased by DHCP
+func hasDHCPv4Address(output string) (bool, error) {
+	links := []ipAddressLink{}
+	err := json.Unmarshal([]byte(output), &links)
+	if err != nil {
+		return false, fmt.Errorf("failed parsing ip addresses: %v", err)
+	}
+	for _, link := range links {
+		for _, address := range link.Addresses {
+			if address.Family == "inet" && address.Dynamic {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func sortedDHCPFallbackInterfaces(fallbacks map[string]dhcpFallback) []string {
+	interfaces := []string{}
+	for iface := range fallbacks {
+		interfaces = append(interfaces, iface)
+	}
+	sort.Strings(interfaces)
+	return interfaces
+}
+
+// activeConnectionUUID returns the connection NetworkManager has active at
+// the interface, the one activated by nmstate
+func activeConnectionUUID(iface string) (string, error) {
+	output, err := nmcli("-g", "GENERAL.CON-UUID", "device", "show", iface)
+	if err != nil {
+		return "", err
+	}
+	uuid := strings.TrimSpace(output)
+	if uuid == "" {
+		return "", fmt.Errorf("interface %s has no active connection", iface)
+	}
+	return uuid, nil
+}
+
+// dhcpFallbacksTimeout returns how long applying the DHCP fallbacks waits
+// for the leases at most, their timeouts start at the same time so it's the
+// longest of them
+func dhcpFallbacksTimeout(fallbacks map[string]dhcpFallback) time.Duration {
+	timeout := time.Duration(0)
+	for _, fallback := range fallbacks {
+		if fallback.timeout > timeout {
+			timeout = fallback.timeout
+		}
+	}
+	return timeout
+}
+
+// applyDHCPFallbacks waits for the interfaces to get a DHCP lease and
+// configures the ones without it with their static addresses, the timeouts
+// start at the same time so they are not added up. The connections are part
+// of the nmstate checkpoint, so they are rolled back with it.
+func applyDHCPFallbacks(fallbacks map[string]dhcpFallback) (string, error) {
+	output := ""
+	started := time.Now()
+	for _, iface := range sortedDHCPFallbackInterfaces(fallbacks) {
+		fallback := fallbacks[iface]
+		leased := false
+		remaining := fallback.timeout - time.Since(started)
+		if remaining < dhcpFallbackPollInterval {
+			remaining = dhcpFallbackPollInterval
+		}
+		pollErr := wait.PollImmediate(dhcpFallbackPollInterval, remaining, func() (bool, error) {
+			ipOutput, err := ip("-j", "addr", "show", "dev", iface)
+			if err != nil {
+				return false, err
+			}
+			leased, err = hasDHCPv4Address(ipOutput)
+			return leased, err
+		})
+		if pollErr != nil && pollErr != wait.ErrWaitTimeout {
+			return output, fmt.Errorf("failed waiting for interface %s DHCP lease: %v", iface, pollErr)
+		}
+		if leased {
+			output += fmt.Sprintf("interface %s got a DHCP lease\n", iface)
+			continue
+		}
+
+		uuid, err := activeConnectionUUID(iface)
+		if err != nil {
+			return output, fmt.Errorf("failed falling back interface %s to static addresses: %v", iface, err)
+		}
+		nmcliOutput, err := nmcli("connection", "modify", uuid, "ipv4.method", "manual", "ipv4.addresses", strings.Join(fallback.addresses, ","))
+		output += fmt.Sprintf("interface %s without DHCP lease after %s, falling back to %s output: %s\n", iface, fallback.timeout, strings.Join(fallback.addresses, ", "), nmcliOutput)
+		if err != nil {
+			return output, err
+		}
+		nmcliOutput, err = nmcli("connection", "up", uuid)
+		output += fmt.Sprintf("interface %s connection up output: %s\n", iface, nmcliOutput)
+		if err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+// DHCPFellBackInterfaces returns the desired state interfaces with DHCP
+// fallback whose connection is configured with the static addresses
+func DHCPFellBackInterfaces(desiredState nmstatev1alpha1.State) ([]string, error) {
+	fallbacks, err := getDHCPFallbacks(desiredState)
+	if err != nil {
+		return nil, err
+	}
+	fellBack := []string{}
+	for _, iface := range sortedDHCPFallbackInterfaces(fallbacks) {
+		uuid, err := activeConnectionUUID(iface)
+		if err != nil {
+			return nil, err
+		}
+		method, err := nmcli("-g", "ipv4.method", "connection", "show", uuid)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(method) == "manual" {
+			fellBack = append(fellBack, iface)
+		}
+	}
+	return fellBack, nil
+}
@@ -0,0 +1,164 @@
+package helper
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("DHCP fallback", func() {
+	It("should return the static addresses and timeout of the interfaces", func() {
+		fallbacks, err := getDHCPFallbacks(nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  ipv4:
+    enabled: true
+    dhcp: true
+  dhcp-fallback:
+    dhcp-timeout: 10
+    address:
+    - ip: 192.0.2.10
+      prefix-length: 24
+    - ip: 198.51.100.10
+      prefix-length: 32
+- name: eth2
+  type: ethernet
+  state: up
+  ipv4:
+    enabled: true
+    dhcp: true
+  dhcp-fallback:
+    address:
+    - ip: 192.0.2.20
+      prefix-length: 24
+- name: eth3
+  type: ethernet
+  state: absent
+  dhcp-fallback:
+    address:
+    - ip: 192.0.2.30
+      prefix-length: 24
+- name: eth4
+  type: ethernet
+  state: up
+  ipv4:
+    enabled: true
+    dhcp: true
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fallbacks).To(Equal(map[string]dhcpFallback{
+			"eth1": {timeout: 10 * time.Second, addresses: []string{"192.0.2.10/24", "198.51.100.10/32"}},
+			"eth2": {timeout: defaultDHCPFallbackTimeout, addresses: []string{"192.0.2.20/24"}},
+		}))
+		Expect(dhcpFallbacksTimeout(fallbacks)).To(Equal(defaultDHCPFallbackTimeout))
+	})
+
+	It("should not wait for leases without fallbacks", func() {
+		Expect(dhcpFallbacksTimeout(map[string]dhcpFallback{})).To(BeZero())
+	})
+
+	DescribeTable("invalid fallbacks",
+		func(desiredState string, expectedError string) {
+			_, err := getDHCPFallbacks(nmstatev1alpha1.NewState(desiredState))
+			Expect(err).To(MatchError(ContainSubstring(expectedError)))
+		},
+		Entry("without DHCP", `interfaces:
+- name: eth1
+  ipv4:
+    enabled: true
+  dhcp-fallback:
+    address:
+    - ip: 192.0.2.10
+      prefix-length: 24
+`, "it needs ipv4 dhcp enabled"),
+		Entry("without addresses", `interfaces:
+- name: eth1
+  ipv4:
+    enabled: true
+    dhcp: true
+  dhcp-fallback:
+    dhcp-timeout: 10
+`, "it has no address"),
+		Entry("with IPv6 addresses", `interfaces:
+- name: eth1
+  ipv4:
+    enabled: true
+    dhcp: true
+  dhcp-fallback:
+    address:
+    - ip: 2001:db8::10
+      prefix-length: 64
+`, "it has to be IPv4"),
+		Entry("with a wrong prefix length", `interfaces:
+- name: eth1
+  ipv4:
+    enabled: true
+    dhcp: true
+  dhcp-fallback:
+    address:
+    - ip: 192.0.2.10
+      prefix-length: 33
+`, "prefix-length 33"),
+		Entry("with a negative timeout", `interfaces:
+- name: eth1
+  ipv4:
+    enabled: true
+    dhcp: true
+  dhcp-fallback:
+    dhcp-timeout: -1
+    address:
+    - ip: 192.0.2.10
+      prefix-length: 24
+`, "it has to be a positive number of seconds"),
+		Entry("with a too long timeout", `interfaces:
+- name: eth1
+  ipv4:
+    enabled: true
+    dhcp: true
+  dhcp-fallback:
+    dhcp-timeout: 3600
+    address:
+    - ip: 192.0.2.10
+      prefix-length: 24
+`, "it cannot be longer than 5m0s"),
+	)
+
+	It("should remove the fallbacks from the desired state", func() {
+		state, err := stripDHCPFallbacks(nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  ipv4:
+    dhcp: true
+    enabled: true
+  dhcp-fallback:
+    address:
+    - ip: 192.0.2.10
+      prefix-length: 24
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  ipv4:
+    dhcp: true
+    enabled: true
+`))
+	})
+
+	DescribeTable("DHCP lease",
+		func(output string, expectedLeased bool) {
+			leased, err := hasDHCPv4Address(output)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(leased).To(Equal(expectedLeased))
+		},
+		Entry("with a dynamic IPv4 address, should be leased",
+			`[{"ifname":"eth1","addr_info":[{"family":"inet","local":"192.0.2.10","scope":"global","dynamic":true}]}]`, true),
+		Entry("with a static IPv4 address, should not be leased",
+			`[{"ifname":"eth1","addr_info":[{"family":"inet","local":"192.0.2.10","scope":"global"}]}]`, false),
+		Entry("with only a dynamic IPv6 address, should not be leased",
+			`[{"ifname":"eth1","addr_info":[{"family":"inet6","local":"2001:db8::10","scope":"global","dynamic":true}]}]`, false),
+	)
+})
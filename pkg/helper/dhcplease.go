@@ -0,0 +1,157 @@
+package helper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const dhcpLeaseKey = "dhcp-lease"
+
+// dhcpLeaseOption is how a NetworkManager DHCP option is reported at the
+// interfaces dhcp-lease
+type dhcpLeaseOption struct {
+	key  string
+	kind string
+}
+
+const (
+	dhcpOptionString = "string"
+	dhcpOptionList   = "list"
+	dhcpOptionNumber = "number"
+	dhcpOptionTime   = "time"
+)
+
+// dhcpLeaseOptions are the NetworkManager DHCP options reported per
+// address family, the rest of options are not useful to debug leases
+var dhcpLeaseOptions = map[string]map[string]dhcpLeaseOption{
+	"ipv4": {
+		"dhcp_server_identifier": {"server", dhcpOptionString},
+		"ip_address":             {"address", dhcpOptionString},
+		"dhcp_lease_time":        {"lease-time", dhcpOptionNumber},
+		"dhcp_renewal_time":      {"renewal-time", dhcpOptionNumber},
+		"dhcp_rebinding_time":    {"rebinding-time", dhcpOptionNumber},
+		"expiry":                 {"expiry", dhcpOptionTime},
+		"routers":                {"routers", dhcpOptionList},
+		"domain_name_servers":    {"dns-servers", dhcpOptionList},
+		"domain_name":            {"domain-name", dhcpOptionString},
+		"ntp_servers":            {"ntp-servers", dhcpOptionList},
+	},
+	"ipv6": {
+		"dhcp6_server_id":     {"server-id", dhcpOptionString},
+		"ip6_address":         {"address", dhcpOptionString},
+		"preferred_life":      {"preferred-lifetime", dhcpOptionNumber},
+		"max_life":            {"valid-lifetime", dhcpOptionNumber},
+		"renew":               {"renewal-time", dhcpOptionNumber},
+		"rebind":              {"rebinding-time", dhcpOptionNumber},
+		"dhcp6_name_servers":  {"dns-servers", dhcpOptionList},
+		"dhcp6_domain_search": {"domain-search", dhcpOptionList},
+	},
+}
+
+var dhcpOptionFamilies = map[string]string{
+	"DHCP4.OPTION": "ipv4",
+	"DHCP6.OPTION": "ipv6",
+}
+
+func dhcpLeaseValue(option dhcpLeaseOption, value string) interface{} {
+	switch option.kind {
+	case dhcpOptionList:
+		return strings.Fields(value)
+	case dhcpOptionNumber:
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return number
+		}
+	case dhcpOptionTime:
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+		}
+	}
+	return value
+}
+
+// parseDHCPLeases returns the lease of every interface and address family
+// from "nmcli -t -f GENERAL.DEVICE,DHCP4,DHCP6 device show", the options of
+// every device follow its name as "DHCP4.OPTION[1]:name = value"
+func parseDHCPLeases(output string) map[string]map[string]map[string]interface{} {
+	leases := map[string]map[string]map[string]interface{}{}
+	device := ""
+	for _, line := range strings.Split(output, "\n") {
+		fields := splitTerseFields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		field, value := fields[0], strings.Join(fields[1:], ":")
+		if field == "GENERAL.DEVICE" {
+			device = value
+			continue
+		}
+		family, isOption := dhcpOptionFamilies[strings.SplitN(field, "[", 2)[0]]
+		if !isOption || device == "" {
+			continue
+		}
+		nameValue := strings.SplitN(value, "=", 2)
+		if len(nameValue) != 2 {
+			continue
+		}
+		option, reported := dhcpLeaseOptions[family][strings.TrimSpace(nameValue[0])]
+		if !reported {
+			continue
+		}
+		if leases[device] == nil {
+			leases[device] = map[string]map[string]interface{}{}
+		}
+		if leases[device][family] == nil {
+			leases[device][family] = map[string]interface{}{}
+		}
+		leases[device][family][option.key] = dhcpLeaseValue(option, strings.TrimSpace(nameValue[1]))
+	}
+	return leases
+}
+
+// addDHCPLeases reports the active DHCP lease at the address family of the
+// current state interfaces
+func addDHCPLeases(currentState nmstatev1alpha1.State, leases map[string]map[string]map[string]interface{}) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return currentState, nil
+	}
+
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		for family, lease := range leases[name] {
+			if familyState, isMap := iface[family].(map[string]interface{}); isMap {
+				familyState[dhcpLeaseKey] = lease
+			}
+		}
+	}
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+func reportDHCPLeases(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	output, err := nmcli("-t", "-f", "GENERAL.DEVICE,DHCP4,DHCP6", "device", "show")
+	if err != nil {
+		return currentState, fmt.Errorf("failed retrieving DHCP leases: %v", err)
+	}
+	return addDHCPLeases(currentState, parseDHCPLeases(output))
+}
@@ -0,0 +1,106 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("DHCP leases", func() {
+	output := `GENERAL.DEVICE:eth0
+DHCP4.OPTION[1]:broadcast_address = 192.168.66.255
+DHCP4.OPTION[2]:dhcp_lease_time = 3600
+DHCP4.OPTION[3]:dhcp_rebinding_time = 3150
+DHCP4.OPTION[4]:dhcp_renewal_time = 1800
+DHCP4.OPTION[5]:dhcp_server_identifier = 192.168.66.2
+DHCP4.OPTION[6]:domain_name_servers = 192.168.66.2 192.168.66.3
+DHCP4.OPTION[7]:expiry = 1583060400
+DHCP4.OPTION[8]:ip_address = 192.168.66.101
+DHCP4.OPTION[9]:routers = 192.168.66.2
+DHCP4.OPTION[10]:subnet_mask = 255.255.255.0
+DHCP6.OPTION[1]:dhcp6_name_servers = fd00\:\:2
+DHCP6.OPTION[2]:dhcp6_server_id = 0\:3\:0\:1\:52\:55\:0\:d1\:55\:2
+DHCP6.OPTION[3]:ip6_address = fd00\:\:101
+DHCP6.OPTION[4]:max_life = 7200
+
+GENERAL.DEVICE:eth1
+
+GENERAL.DEVICE:lo
+`
+
+	It("should parse the reported options of the active leases", func() {
+		leases := parseDHCPLeases(output)
+		Expect(leases).To(Equal(map[string]map[string]map[string]interface{}{
+			"eth0": {
+				"ipv4": {
+					"server":         "192.168.66.2",
+					"address":        "192.168.66.101",
+					"lease-time":     int64(3600),
+					"renewal-time":   int64(1800),
+					"rebinding-time": int64(3150),
+					"expiry":         "2020-03-01T11:00:00Z",
+					"routers":        []string{"192.168.66.2"},
+					"dns-servers":    []string{"192.168.66.2", "192.168.66.3"},
+				},
+				"ipv6": {
+					"server-id":      "0:3:0:1:52:55:0:d1:55:2",
+					"address":        "fd00::101",
+					"valid-lifetime": int64(7200),
+					"dns-servers":    []string{"fd00::2"},
+				},
+			},
+		}))
+	})
+
+	It("should report the leases at the interfaces address families", func() {
+		currentState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth0
+  type: ethernet
+  ipv4:
+    enabled: true
+    dhcp: true
+  ipv6:
+    enabled: true
+    dhcp: true
+- name: eth1
+  type: ethernet
+  ipv4:
+    enabled: false
+`)
+		reportedState, err := addDHCPLeases(currentState, parseDHCPLeases(output))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(reportedState.Raw)).To(Equal(`interfaces:
+- ipv4:
+    dhcp: true
+    dhcp-lease:
+      address: 192.168.66.101
+      dns-servers:
+      - 192.168.66.2
+      - 192.168.66.3
+      expiry: "2020-03-01T11:00:00Z"
+      lease-time: 3600
+      rebinding-time: 3150
+      renewal-time: 1800
+      routers:
+      - 192.168.66.2
+      server: 192.168.66.2
+    enabled: true
+  ipv6:
+    dhcp: true
+    dhcp-lease:
+      address: fd00::101
+      dns-servers:
+      - fd00::2
+      server-id: 0:3:0:1:52:55:0:d1:55:2
+      valid-lifetime: 7200
+    enabled: true
+  name: eth0
+  type: ethernet
+- ipv4:
+    enabled: false
+  name: eth1
+  type: ethernet
+`))
+	})
+})
@@ -0,0 +1,184 @@
+package helper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// nmstate does not disable IPv6 at the kernel, it is set at the desired
+// state top level disable-ipv6 key and written to the IPv6 all and default
+// disable_ipv6, so it applies to the current interfaces and the new ones
+const disableIPv6Key = "disable-ipv6"
+
+var disableIPv6Confs = []string{sysctlAllConf, sysctlDefaultConf}
+
+func disableIPv6Path(netDir string, conf string) string {
+	return filepath.Join(netDir, "ipv6", "conf", conf, "disable_ipv6")
+}
+
+// getDisableIPv6 returns the desired state global IPv6 disable, nil if it
+// is not set, failing with values not booleans
+func getDisableIPv6(desiredState nmstatev1alpha1.State) (*bool, error) {
+	if len(desiredState.Raw) == 0 {
+		return nil, nil
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return nil, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	disable := gjson.GetBytes(desiredStateJSON, disableIPv6Key)
+	if !disable.Exists() {
+		return nil, nil
+	}
+	if disable.Type != gjson.True && disable.Type != gjson.False {
+		return nil, fmt.Errorf("invalid disable-ipv6 %s, it has to be true or false", disable.Raw)
+	}
+	disabled := disable.Bool()
+	return &disabled, nil
+}
+
+// stripDisableIPv6 removes the global IPv6 disable from the desired state
+func stripDisableIPv6(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	if _, hasDisable := state[disableIPv6Key]; !hasDisable {
+		return desiredState, nil
+	}
+	delete(state, disableIPv6Key)
+
+	strippedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: strippedState}, nil
+}
+
+// applyDisableIPv6 writes the global IPv6 disable, the kernel sets it at
+// every interface too so it has to go before the interfaces sysctls
+func applyDisableIPv6(netDir string, disabled *bool) (string, error) {
+	if disabled == nil {
+		return "", nil
+	}
+	value := "0"
+	if *disabled {
+		value = "1"
+	}
+	output := ""
+	for _, conf := range disableIPv6Confs {
+		err := ioutil.WriteFile(disableIPv6Path(netDir, conf), []byte(value), 0644)
+		if err != nil {
+			return output, fmt.Errorf("failed setting %s disable_ipv6: %v", conf, err)
+		}
+		output += fmt.Sprintf("%s disable_ipv6 set to %s\n", conf, value)
+	}
+	return output, nil
+}
+
+// readDisableIPv6 returns the IPv6 all and default disable_ipv6 values if
+// the desired state sets the global IPv6 disable, the ones the kernel does
+// not have are left out
+func readDisableIPv6(netDir string, disabled *bool) map[string]string {
+	current := map[string]string{}
+	if disabled == nil {
+		return current
+	}
+	for _, conf := range disableIPv6Confs {
+		content, err := ioutil.ReadFile(disableIPv6Path(netDir, conf))
+		if err != nil {
+			continue
+		}
+		current[conf] = strings.TrimSpace(string(content))
+	}
+	return current
+}
+
+// restoreDisableIPv6 writes back the disable_ipv6 values read before
+// applying the desired state, they are not part of the nmstate checkpoint
+func restoreDisableIPv6(netDir string, previousDisableIPv6 map[string]string) string {
+	output := ""
+	for _, conf := range disableIPv6Confs {
+		value, found := previousDisableIPv6[conf]
+		if !found {
+			continue
+		}
+		err := ioutil.WriteFile(disableIPv6Path(netDir, conf), []byte(value), 0644)
+		if err != nil {
+			log.Info(fmt.Sprintf("failed restoring %s disable_ipv6: %v", conf, err))
+			continue
+		}
+		output += fmt.Sprintf("%s disable_ipv6 set to %s\n", conf, value)
+	}
+	return output
+}
+
+// RemoveDroppedDisableIPv6 enables IPv6 again at the desired state if the
+// previously applied one disabled it and the desired state does not set it
+// anymore. Once enabled the previous desired state does not disable it, so
+// it is not enabled at the following applies.
+func RemoveDroppedDisableIPv6(previousState nmstatev1alpha1.State, desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	previousDisabled, err := getDisableIPv6(previousState)
+	if err != nil || previousDisabled == nil || !*previousDisabled {
+		return desiredState, err
+	}
+	disabled, err := getDisableIPv6(desiredState)
+	if err != nil || disabled != nil {
+		return desiredState, err
+	}
+
+	var state map[string]interface{}
+	err = yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	state[disableIPv6Key] = false
+
+	removedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: removedState}, nil
+}
+
+// addDisableIPv6 reports the global IPv6 disable at the current state, it
+// is left out if the kernel does not have IPv6 at all
+func addDisableIPv6(currentState nmstatev1alpha1.State, netDir string) (nmstatev1alpha1.State, error) {
+	content, err := ioutil.ReadFile(disableIPv6Path(netDir, sysctlAllConf))
+	if err != nil {
+		return currentState, nil
+	}
+
+	var state map[string]interface{}
+	err = yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	state[disableIPv6Key] = strings.TrimSpace(string(content)) != "0"
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+func reportDisableIPv6(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return addDisableIPv6(currentState, sysctlNetDir)
+}
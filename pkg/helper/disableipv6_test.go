@@ -0,0 +1,115 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Global IPv6 disable", func() {
+	const eth1 = `- name: eth1
+  type: ethernet
+  state: up
+`
+
+	It("should take the global IPv6 disable and strip it from the nmstate desired state", func() {
+		desiredState := nmstatev1alpha1.NewState("disable-ipv6: true\ninterfaces:\n" + eth1)
+
+		disabled, err := getDisableIPv6(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(disabled).ToNot(BeNil())
+		Expect(*disabled).To(BeTrue())
+
+		strippedState, err := stripDisableIPv6(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML("interfaces:\n" + eth1))
+	})
+
+	It("should fail with a value not a boolean", func() {
+		_, err := getDisableIPv6(nmstatev1alpha1.NewState("disable-ipv6: 1\n"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("enabling IPv6 again once dropped",
+		func(previousState string, desiredState string, expectedState string) {
+			removedState, err := RemoveDroppedDisableIPv6(nmstatev1alpha1.NewState(previousState), nmstatev1alpha1.NewState(desiredState))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removedState.String()).To(MatchYAML(expectedState))
+		},
+		Entry("when dropped", "disable-ipv6: true\ninterfaces:\n"+eth1, "interfaces:\n"+eth1, "disable-ipv6: false\ninterfaces:\n"+eth1),
+		Entry("when still disabled", "disable-ipv6: true\n", "disable-ipv6: true\n", "disable-ipv6: true\n"),
+		Entry("when already enabled", "disable-ipv6: false\ninterfaces:\n"+eth1, "interfaces:\n"+eth1, "interfaces:\n"+eth1),
+		Entry("when never disabled", "interfaces:\n"+eth1, "interfaces:\n"+eth1, "interfaces:\n"+eth1),
+	)
+
+	Context("at the node", func() {
+		var netDir string
+
+		writeDisableIPv6 := func(conf string, value string) {
+			dir := filepath.Join(netDir, "ipv6", "conf", conf)
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "disable_ipv6"), []byte(value+"\n"), 0644)).To(Succeed())
+		}
+
+		disableIPv6Value := func(conf string) string {
+			content, err := ioutil.ReadFile(filepath.Join(netDir, "ipv6", "conf", conf, "disable_ipv6"))
+			Expect(err).ToNot(HaveOccurred())
+			return string(content)
+		}
+
+		BeforeEach(func() {
+			var err error
+			netDir, err = ioutil.TempDir("", "proc-sys-net")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(netDir)
+		})
+
+		It("should set it at the current and new interfaces", func() {
+			writeDisableIPv6("all", "0")
+			writeDisableIPv6("default", "0")
+			disabled := true
+			_, err := applyDisableIPv6(netDir, &disabled)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(disableIPv6Value("all")).To(Equal("1"))
+			Expect(disableIPv6Value("default")).To(Equal("1"))
+		})
+
+		It("should restore the values read before setting it", func() {
+			writeDisableIPv6("all", "0")
+			writeDisableIPv6("default", "1")
+			disabled := false
+			previousDisableIPv6 := readDisableIPv6(netDir, &disabled)
+			Expect(previousDisableIPv6).To(Equal(map[string]string{"all": "0", "default": "1"}))
+
+			_, err := applyDisableIPv6(netDir, &disabled)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(disableIPv6Value("default")).To(Equal("0"))
+
+			restoreDisableIPv6(netDir, previousDisableIPv6)
+			Expect(disableIPv6Value("all")).To(Equal("0"))
+			Expect(disableIPv6Value("default")).To(Equal("1"))
+		})
+
+		It("should report it at the current state", func() {
+			writeDisableIPv6("all", "1")
+			reportedState, err := addDisableIPv6(nmstatev1alpha1.NewState("interfaces:\n"+eth1), netDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reportedState.String()).To(MatchYAML("disable-ipv6: true\ninterfaces:\n" + eth1))
+		})
+
+		It("should not report it without IPv6 at the kernel", func() {
+			reportedState, err := addDisableIPv6(nmstatev1alpha1.NewState("interfaces:\n"+eth1), netDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reportedState.String()).To(MatchYAML("interfaces:\n" + eth1))
+		})
+	})
+})
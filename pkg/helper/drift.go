@@ -0,0 +1,256 @@
+package helper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// driftPathSeparator separates the keys of the drift paths, the drift ignore
+// globs match whole keys with * and any number of them with **
+const driftPathSeparator = '.'
+
+// dynamicAddressFamilies returns the address families of the desired
+// interface whose addresses are learned by DHCP or autoconf and are not
+// expected to match the desired state
+func dynamicAddressFamilies(iface map[string]interface{}) map[string]bool {
+	families := map[string]bool{}
+	for _, family := range []string{"ipv4", "ipv6"} {
+		ip, ok := iface[family].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ip["dhcp"] == true || ip["autoconf"] == true {
+			families[family] = true
+		}
+	}
+	return families
+}
+
+func scalarEqual(desired interface{}, current interface{}) bool {
+	return strings.EqualFold(fmt.Sprint(desired), fmt.Sprint(current))
+}
+
+func itemName(item interface{}) (string, bool) {
+	itemMap, ok := item.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	name, ok := itemMap["name"].(string)
+	return name, ok
+}
+
+// contains returns true if the desired value is a subset of the current one
+func contains(desired interface{}, current interface{}) bool {
+	drift := []string{}
+	compareDrift("", desired, current, map[string]bool{}, &drift)
+	return len(drift) == 0
+}
+
+// compareDrift appends to drift the paths of the desired value that are not
+// present at the current one. Maps are compared by the desired keys, lists of
+// named items, like interfaces, by name and the rest of the lists need
+// to contain the desired items.
+func compareDrift(path string, desired interface{}, current interface{}, skip map[string]bool, drift *[]string) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + string(driftPathSeparator) + key
+	}
+
+	// Secrets are resolved when applying the desired state and nmstate
+	// hides them at the current state
+	if _, _, isRef := secretKeyRef(desired); isRef {
+		return
+	}
+
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		currentValue, ok := current.(map[string]interface{})
+		if !ok {
+			*drift = append(*drift, path)
+			return
+		}
+		for key, value := range desiredValue {
+			if !skip[join(key)] {
+				compareDrift(join(key), value, currentValue[key], skip, drift)
+			}
+		}
+	case []interface{}:
+		currentValue, ok := current.([]interface{})
+		if !ok {
+			*drift = append(*drift, path)
+			return
+		}
+		currentByName := map[string]interface{}{}
+		for _, item := range currentValue {
+			if name, named := itemName(item); named {
+				currentByName[name] = item
+			}
+		}
+		for i, item := range desiredValue {
+			name, named := itemName(item)
+			if !named {
+				// Removed items, like routes, are not present at the
+				// current state to compare with
+				if itemMap, isMap := item.(map[string]interface{}); isMap && itemMap["state"] == "absent" {
+					continue
+				}
+				found := false
+				for _, currentItem := range currentValue {
+					if contains(item, currentItem) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					*drift = append(*drift, join(fmt.Sprint(i)))
+				}
+				continue
+			}
+
+			currentItem, found := currentByName[name]
+			if item.(map[string]interface{})["state"] == "absent" {
+				if found {
+					*drift = append(*drift, join(name))
+				}
+				continue
+			}
+			if !found {
+				*drift = append(*drift, join(name))
+				continue
+			}
+			itemSkip := skip
+			families := dynamicAddressFamilies(item.(map[string]interface{}))
+			if len(families) > 0 {
+				itemSkip = copySkip(skip)
+				for family := range families {
+					itemSkip[join(name+string(driftPathSeparator)+family+string(driftPathSeparator)+"address")] = true
+				}
+			}
+			// Interfaces falling back from DHCP to static addresses are
+			// reported with DHCP disabled, and the fallback is not reported
+			if _, hasFallback := item.(map[string]interface{})[dhcpFallbackKey]; hasFallback {
+				itemSkip = copySkip(itemSkip)
+				itemSkip[join(name+string(driftPathSeparator)+dhcpFallbackKey)] = true
+				itemSkip[join(name+string(driftPathSeparator)+"ipv4"+string(driftPathSeparator)+"dhcp")] = true
+			}
+			compareDrift(join(name), item, currentItem, itemSkip, drift)
+		}
+	default:
+		if current == nil || !scalarEqual(desired, current) {
+			*drift = append(*drift, path)
+		}
+	}
+}
+
+func copySkip(skip map[string]bool) map[string]bool {
+	copied := map[string]bool{}
+	for path := range skip {
+		copied[path] = true
+	}
+	return copied
+}
+
+// ignoredPath returns true if the path, or any of its parents, matches the
+// ignore globs
+func ignoredPath(path string, ignoreGlobs []glob.Glob) bool {
+	keys := strings.Split(path, string(driftPathSeparator))
+	for i := range keys {
+		parent := strings.Join(keys[:i+1], string(driftPathSeparator))
+		for _, ignoreGlob := range ignoreGlobs {
+			if ignoreGlob.Match(parent) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Drift returns the paths of the desired state that do not match the current
+// state, sorted and excluding the ones under the ignore globs. The
+// addresses of the interfaces address families configured with DHCP or
+// autoconf, the qdiscs, the connection names, the static neighbors and the
+// DHCP fallbacks are not compared.
+func Drift(desiredState nmstatev1alpha1.State, currentState nmstatev1alpha1.State, ignore []string) ([]string, error) {
+	ignoreGlobs := []glob.Glob{}
+	for _, pattern := range ignore {
+		ignoreGlob, err := glob.Compile(pattern, driftPathSeparator)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling drift ignore path %q: %v", pattern, err)
+		}
+		ignoreGlobs = append(ignoreGlobs, ignoreGlob)
+	}
+
+	// The qdiscs are reported with the tc options instead of the desired
+	// attributes, they cannot be compared
+	desiredState, err := stripQdiscs(desiredState)
+	if err != nil {
+		return nil, fmt.Errorf("error removing qdiscs from desired state: %v", err)
+	}
+
+	// Neither the connection names, they are not reported at the current
+	// state
+	desiredState, err = stripConnectionNames(desiredState)
+	if err != nil {
+		return nil, fmt.Errorf("error removing connection names from desired state: %v", err)
+	}
+
+	// Nor the route preferred source addresses and scopes, nmstate does
+	// not report them
+	desiredState, err = stripRouteAttributes(desiredState)
+	if err != nil {
+		return nil, fmt.Errorf("error removing route attributes from desired state: %v", err)
+	}
+
+	// Neither the static neighbors, they are reported at the
+	// NodeNetworkState status instead of the current state
+	desiredState, err = stripNeighbors(desiredState)
+	if err != nil {
+		return nil, fmt.Errorf("error removing neighbors from desired state: %v", err)
+	}
+
+	// Nor the sysctls reset to default, the interfaces added to reset the
+	// sysctls of interfaces dropped from the policy go with them
+	desiredState, err = stripDefaultSysctls(desiredState)
+	if err != nil {
+		return nil, fmt.Errorf("error removing default sysctls from desired state: %v", err)
+	}
+
+	// Neither the interfaces added to turn off the promiscuous mode of the
+	// interfaces dropped from the policy
+	desiredState, err = stripDroppedPromisc(desiredState)
+	if err != nil {
+		return nil, fmt.Errorf("error removing dropped interfaces promiscuous mode from desired state: %v", err)
+	}
+
+	var desired, current interface{}
+	err = yaml.Unmarshal(desiredState.Raw, &desired)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling desired state: %v", err)
+	}
+	err = yaml.Unmarshal(currentState.Raw, &current)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling current state: %v", err)
+	}
+
+	drift := []string{}
+	if desired == nil {
+		return drift, nil
+	}
+	paths := []string{}
+	compareDrift("", desired, current, map[string]bool{}, &paths)
+	for _, path := range paths {
+		if !ignoredPath(path, ignoreGlobs) {
+			drift = append(drift, path)
+		}
+	}
+	sort.Strings(drift)
+	return drift, nil
+}
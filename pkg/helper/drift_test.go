@@ -0,0 +1,199 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Drift", func() {
+	currentState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth0
+  type: ethernet
+  state: up
+  mtu: 1500
+  ipv4:
+    enabled: true
+    dhcp: true
+    address:
+    - ip: 192.168.66.101
+      prefix-length: 24
+  ipv6:
+    enabled: true
+    autoconf: true
+    dhcp: true
+    address:
+    - ip: fd00::101
+      prefix-length: 128
+    - ip: fe80::5054:ff:fe12:3456
+      prefix-length: 64
+- name: eth1
+  type: ethernet
+  state: up
+  mtu: 9000
+  mac-address: 52:55:00:D1:56:01
+  ipv4:
+    enabled: true
+    dhcp: false
+    address:
+    - ip: 192.0.2.1
+      prefix-length: 24
+  ipv6:
+    enabled: true
+    autoconf: false
+    dhcp: false
+    address:
+    - ip: 2001:db8::1
+      prefix-length: 64
+    - ip: fe80::5054:ff:fe12:3457
+      prefix-length: 64
+  qdisc:
+    kind: tbf
+    options:
+      rate: 12500000
+  sysctl:
+    ipv4:
+      forwarding: 1
+      rp_filter: 1
+routes:
+  config:
+  - destination: 10.0.0.0/8
+    next-hop-address: 192.0.2.254
+    next-hop-interface: eth1
+`)
+	DescribeTable("desired state",
+		func(desiredState string, ignore []string, expectedDrift []string) {
+			drift, err := Drift(nmstatev1alpha1.NewState(desiredState), currentState, ignore)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift).To(Equal(expectedDrift))
+		},
+		Entry("matching", `interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  mtu: 9000
+  mac-address: 52:55:00:d1:56:01
+  ipv4:
+    enabled: true
+    address:
+    - ip: 192.0.2.1
+      prefix-length: 24
+  qdisc:
+    kind: tbf
+    rate: 100mbit
+    burst: 32kbit
+    latency: 400ms
+routes:
+  config:
+  - destination: 10.0.0.0/8
+    next-hop-address: 192.0.2.254
+    next-hop-interface: eth1
+  - destination: 10.1.0.0/16
+    state: absent
+`, nil, []string{}),
+		Entry("with route preferred source address and scope", `routes:
+  config:
+  - destination: 10.0.0.0/8
+    next-hop-address: 192.0.2.254
+    next-hop-interface: eth1
+    source: 192.0.2.1
+    scope: global
+`, nil, []string{}),
+		Entry("with sysctls reset to default", `interfaces:
+- name: eth1
+  sysctl:
+    ipv4:
+      forwarding: 1
+      rp_filter: default
+- name: eth3
+  sysctl:
+    ipv4:
+      rp_filter: default
+`, nil, []string{}),
+		Entry("with changed sysctls", `interfaces:
+- name: eth1
+  sysctl:
+    ipv4:
+      rp_filter: 2
+`, nil, []string{"interfaces.eth1.sysctl.ipv4.rp_filter"}),
+		Entry("with changed values", `interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  mtu: 1500
+  ipv4:
+    enabled: true
+    address:
+    - ip: 192.0.2.2
+      prefix-length: 24
+`, nil, []string{"interfaces.eth1.ipv4.address.0", "interfaces.eth1.mtu"}),
+		Entry("with missing and not removed interfaces", `interfaces:
+- name: eth2
+  type: ethernet
+  state: up
+- name: eth1
+  type: ethernet
+  state: absent
+`, nil, []string{"interfaces.eth1", "interfaces.eth2"}),
+		Entry("with DHCP and autoconf addresses", `interfaces:
+- name: eth0
+  type: ethernet
+  state: up
+  ipv4:
+    enabled: true
+    dhcp: true
+    address:
+    - ip: 192.168.66.200
+      prefix-length: 24
+  ipv6:
+    enabled: true
+    autoconf: true
+    dhcp: true
+    address:
+    - ip: fd00::200
+      prefix-length: 128
+`, nil, []string{}),
+		Entry("with DHCP fallen back to static addresses", `interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  ipv4:
+    enabled: true
+    dhcp: true
+  dhcp-fallback:
+    dhcp-timeout: 10
+    address:
+    - ip: 192.0.2.1
+      prefix-length: 24
+`, nil, []string{}),
+		Entry("with ignored paths", `interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  mtu: 1500
+  ipv6:
+    enabled: true
+    address:
+    - ip: 2001:db8::2
+      prefix-length: 64
+`, []string{"interfaces.*.ipv6.address", "interfaces.*.mtu"}, []string{}),
+		Entry("with ignored subtrees", `interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  mtu: 1500
+  ipv6:
+    enabled: true
+    address:
+    - ip: 2001:db8::2
+      prefix-length: 64
+`, []string{"interfaces.eth1.ipv6.**"}, []string{"interfaces.eth1.mtu"}),
+	)
+
+	It("should fail with invalid ignore paths", func() {
+		_, err := Drift(nmstatev1alpha1.NewState("interfaces: []\n"), currentState, []string{"interfaces.[eth1"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,87 @@
+package helper
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const dummyInterfaceType = "dummy"
+
+// dummyInterfaces returns the names of the dummy interfaces present at the
+// state, absent ones are not included
+func dummyInterfaces(state nmstatev1alpha1.State) (map[string]bool, error) {
+	dummies := map[string]bool{}
+	if len(state.Raw) == 0 {
+		return dummies, nil
+	}
+	stateJSON, err := yaml.YAMLToJSON(state.Raw)
+	if err != nil {
+		return dummies, fmt.Errorf("error converting state to JSON: %v", err)
+	}
+	for _, iface := range gjson.ParseBytes(stateJSON).Get("interfaces").Array() {
+		if iface.Get("type").String() == dummyInterfaceType && iface.Get("state").String() != "absent" {
+			dummies[iface.Get("name").String()] = true
+		}
+	}
+	return dummies, nil
+}
+
+// RemoveDroppedDummies sets as absent at the desired state the dummy
+// interfaces present at the previously applied one that are not at the
+// desired state anymore. Unlike the rest of interfaces, the dummy ones only
+// exist because a policy created them, so dropping them from the policy
+// deletes them.
+func RemoveDroppedDummies(previousState nmstatev1alpha1.State, desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	previousDummies, err := dummyInterfaces(previousState)
+	if err != nil || len(previousDummies) == 0 {
+		return desiredState, err
+	}
+
+	var state map[string]interface{}
+	err = yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	interfaces, _ := state["interfaces"].([]interface{})
+
+	desiredInterfaces := map[string]bool{}
+	for _, iface := range interfaces {
+		if name, named := itemName(iface); named {
+			desiredInterfaces[name] = true
+		}
+	}
+
+	droppedDummies := []string{}
+	for name := range previousDummies {
+		if !desiredInterfaces[name] {
+			droppedDummies = append(droppedDummies, name)
+		}
+	}
+	if len(droppedDummies) == 0 {
+		return desiredState, nil
+	}
+	sort.Strings(droppedDummies)
+
+	for _, name := range droppedDummies {
+		interfaces = append(interfaces, map[string]interface{}{
+			"name":  name,
+			"type":  dummyInterfaceType,
+			"state": "absent",
+		})
+	}
+	state["interfaces"] = interfaces
+
+	removedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: removedState}, nil
+}
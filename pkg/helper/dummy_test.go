@@ -0,0 +1,64 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Dummy interfaces", func() {
+	const vipsDummy = `- name: vips0
+  type: dummy
+  state: up
+  ipv4:
+    enabled: true
+    address:
+    - ip: 192.0.2.10
+      prefix-length: 32
+    - ip: 192.0.2.11
+      prefix-length: 32
+`
+	const eth1 = `- name: eth1
+  type: ethernet
+  state: up
+`
+	DescribeTable("dropped from the desired state",
+		func(previousState string, desiredState string, expectedState string) {
+			removedState, err := RemoveDroppedDummies(nmstatev1alpha1.NewState(previousState), nmstatev1alpha1.NewState(desiredState))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removedState.String()).To(MatchYAML(expectedState))
+		},
+		Entry("without previous desired state, should keep it",
+			"",
+			"interfaces:\n"+vipsDummy,
+			"interfaces:\n"+vipsDummy,
+		),
+		Entry("still configuring the dummy, should keep its addresses",
+			"interfaces:\n"+vipsDummy,
+			"interfaces:\n"+vipsDummy+eth1,
+			"interfaces:\n"+vipsDummy+eth1,
+		),
+		Entry("without the dummy, should remove it",
+			"interfaces:\n"+vipsDummy+eth1,
+			"interfaces:\n"+eth1,
+			"interfaces:\n"+eth1+"- name: vips0\n  type: dummy\n  state: absent\n",
+		),
+		Entry("without interfaces, should remove the dummy",
+			"interfaces:\n"+vipsDummy,
+			"dns-resolver:\n  config:\n    server:\n    - 192.0.2.53\n",
+			"dns-resolver:\n  config:\n    server:\n    - 192.0.2.53\ninterfaces:\n- name: vips0\n  type: dummy\n  state: absent\n",
+		),
+		Entry("without other interfaces, should not remove them",
+			"interfaces:\n"+eth1,
+			"interfaces: []\n",
+			"interfaces: []\n",
+		),
+		Entry("with the dummy already absent, should not remove it again",
+			"interfaces:\n- name: vips0\n  type: dummy\n  state: absent\n",
+			"interfaces:\n"+eth1,
+			"interfaces:\n"+eth1,
+		),
+	)
+})
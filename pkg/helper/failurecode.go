@@ -0,0 +1,58 @@
+package helper
+
+import (
+	"strings"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// nmstateFailureCodes maps the nmstate error classes, found at the
+// nmstatectl output, to their failure codes
+var nmstateFailureCodes = []struct {
+	errorClass string
+	code       nmstatev1alpha1.FailureCode
+}{
+	{"NmstateVerificationError", nmstatev1alpha1.FailureCodeVerificationFailed},
+	{"NmstateValueError", nmstatev1alpha1.FailureCodeInvalidDesiredState},
+	{"NmstateKernelIntegerRoundedError", nmstatev1alpha1.FailureCodeInvalidDesiredState},
+	{"NmstateNotImplementedError", nmstatev1alpha1.FailureCodeNotSupported},
+	{"NmstateNotSupportedError", nmstatev1alpha1.FailureCodeNotSupported},
+	{"NmstateLibnmError", nmstatev1alpha1.FailureCodeNetworkManagerFailed},
+	{"NmstatePermissionError", nmstatev1alpha1.FailureCodePermissionDenied},
+	{"NmstateDependencyError", nmstatev1alpha1.FailureCodeDependencyMissing},
+	{"NmstateTimeoutError", nmstatev1alpha1.FailureCodeTimeout},
+	{"NmstateInternalError", nmstatev1alpha1.FailureCodeInternalError},
+}
+
+// failureError is a failure the handler tells apart itself, like the
+// connectivity or readiness probes after applying the desired state
+type failureError struct {
+	code nmstatev1alpha1.FailureCode
+	err  error
+}
+
+func (e *failureError) Error() string {
+	return e.err.Error()
+}
+
+// FailureCode returns the code of the desired state apply failure, the one
+// of the handler typed errors or of the nmstate error class at the
+// nmstatectl output, ApplyFailed for the rest
+func FailureCode(err error) nmstatev1alpha1.FailureCode {
+	switch typedErr := err.(type) {
+	case nil:
+		return ""
+	case *failureError:
+		return typedErr.code
+	case *applyTimeoutError:
+		return nmstatev1alpha1.FailureCodeTimeout
+	case *checkpointError:
+		return nmstatev1alpha1.FailureCodeCheckpointFailed
+	}
+	for _, nmstateFailureCode := range nmstateFailureCodes {
+		if strings.Contains(err.Error(), nmstateFailureCode.errorClass) {
+			return nmstateFailureCode.code
+		}
+	}
+	return nmstatev1alpha1.FailureCodeApplyFailed
+}
@@ -0,0 +1,39 @@
+package helper
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Failure codes", func() {
+	DescribeTable("of the apply failures",
+		func(err error, expectedCode nmstatev1alpha1.FailureCode) {
+			Expect(FailureCode(err)).To(Equal(expectedCode))
+		},
+		Entry("without failure", nil, nmstatev1alpha1.FailureCode("")),
+		Entry("with a nmstate error", errors.New("failed to execute nmstatectl set --no-commit --timeout 480: 'exit status 1' '' 'libnmstate.error.NmstateVerificationError: desired: mtu: 9000 current: mtu: 1500'"), nmstatev1alpha1.FailureCodeVerificationFailed),
+		Entry("with the apply timeout", &applyTimeoutError{timeout: time.Minute}, nmstatev1alpha1.FailureCodeTimeout),
+		Entry("with the checkpoint failure", &checkpointError{err: errors.New("failed")}, nmstatev1alpha1.FailureCodeCheckpointFailed),
+		Entry("with the readiness checks", rollback(false, outOfBandRestores{}, &failureError{code: nmstatev1alpha1.FailureCodeReadinessFailed, err: errors.New("error checking readiness after network reconfiguration")}), nmstatev1alpha1.FailureCodeReadinessFailed),
+		Entry("with the connectivity probes", rollback(false, outOfBandRestores{}, &failureError{code: nmstatev1alpha1.FailureCodeConnectivityLost, err: errors.New("error checking api server connectivity after network reconfiguration")}), nmstatev1alpha1.FailureCodeConnectivityLost),
+		Entry("with an unknown error", errors.New("invalid interface eth1 qdisc"), nmstatev1alpha1.FailureCodeApplyFailed),
+	)
+
+	It("should keep the code of the failures with secrets redacted", func() {
+		err := RedactSecretValuesError(&failureError{code: nmstatev1alpha1.FailureCodeReadinessFailed, err: errors.New("readiness check with s3cr3t failed")}, []string{"s3cr3t"})
+		Expect(err.Error()).ToNot(ContainSubstring("s3cr3t"))
+		Expect(FailureCode(err)).To(Equal(nmstatev1alpha1.FailureCodeReadinessFailed))
+	})
+
+	It("should keep the code of the failures with sensitive values redacted", func() {
+		err := redactSensitiveError(&failureError{code: nmstatev1alpha1.FailureCodeConnectivityLost, err: errors.New("ping failed, psk: s3cr3t")})
+		Expect(err).To(MatchError("ping failed, psk: <redacted>"))
+		Expect(FailureCode(err)).To(Equal(nmstatev1alpha1.FailureCodeConnectivityLost))
+	})
+})
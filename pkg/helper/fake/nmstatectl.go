@@ -0,0 +1,88 @@
+package fake
+
+import (
+	"time"
+)
+
+// Nmstatectl is an in memory helper.Nmstatectl, the state set replaces the
+// current one until it is rolled back, and it records the commands run so
+// tests can check the apply, commit and rollback sequence
+type Nmstatectl struct {
+	// CurrentState is the state reported by Show
+	CurrentState string
+	// Checkpoint is the state before the last set, while it is not
+	// committed nor rolled back
+	Checkpoint *string
+
+	ShowErr     error
+	SetErr      error
+	CommitErr   error
+	RollbackErr error
+
+	// SetErrors are returned by the next sets, one each, before SetErr
+	SetErrors []error
+
+	// Commands are the commands run, show, set, set-without-checkpoint,
+	// commit and rollback
+	Commands []string
+}
+
+// NewNmstatectl returns a fake Nmstatectl reporting currentState
+func NewNmstatectl(currentState string) *Nmstatectl {
+	return &Nmstatectl{CurrentState: currentState}
+}
+
+func (n *Nmstatectl) Show() (string, error) {
+	n.Commands = append(n.Commands, "show")
+	if n.ShowErr != nil {
+		return "", n.ShowErr
+	}
+	return n.CurrentState, nil
+}
+
+func (n *Nmstatectl) Set(desiredState string, timeout time.Duration, applyTimeout time.Duration) (string, error) {
+	n.Commands = append(n.Commands, "set")
+	if len(n.SetErrors) > 0 {
+		err := n.SetErrors[0]
+		n.SetErrors = n.SetErrors[1:]
+		if err != nil {
+			return "", err
+		}
+	} else if n.SetErr != nil {
+		return "", n.SetErr
+	}
+	checkpoint := n.CurrentState
+	n.Checkpoint = &checkpoint
+	n.CurrentState = desiredState
+	return "", nil
+}
+
+func (n *Nmstatectl) SetWithoutCheckpoint(desiredState string, applyTimeout time.Duration) (string, error) {
+	n.Commands = append(n.Commands, "set-without-checkpoint")
+	if n.SetErr != nil {
+		return "", n.SetErr
+	}
+	n.CurrentState = desiredState
+	return "", nil
+}
+
+func (n *Nmstatectl) Commit() (string, error) {
+	n.Commands = append(n.Commands, "commit")
+	if n.CommitErr != nil {
+		return "", n.CommitErr
+	}
+	n.Checkpoint = nil
+	return "", nil
+}
+
+func (n *Nmstatectl) Rollback() (string, error) {
+	n.Commands = append(n.Commands, "rollback")
+	if n.RollbackErr != nil {
+		return "", n.RollbackErr
+	}
+	if n.Checkpoint != nil {
+		n.CurrentState = *n.Checkpoint
+		n.Checkpoint = nil
+	}
+	return "", nil
+}
@@ -0,0 +1,178 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const firewallCmdCommand = "firewall-cmd"
+
+const firewalldZoneKey = "firewalld-zone"
+
+func firewallCmd(arguments ...string) (string, error) {
+	cmd := exec.Command(firewallCmdCommand, arguments...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := runCommand(cmd); err != nil {
+		return "", fmt.Errorf("failed to execute %s %v: '%v', '%s', '%s'", firewallCmdCommand, arguments, err, stdout.String(), stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// getFirewalldZones returns the firewalld zone of the desired state
+// interfaces that configure it, the rest of interfaces keep their current
+// zone and an empty zone moves the interface to the default one. Absent
+// interfaces are ignored.
+func getFirewalldZones(desiredState nmstatev1alpha1.State) (map[string]string, error) {
+	zones := map[string]string{}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return zones, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		zone := iface.Get(firewalldZoneKey)
+		if !zone.Exists() || iface.Get("state").String() == "absent" {
+			continue
+		}
+		zones[iface.Get("name").String()] = zone.String()
+	}
+	return zones, nil
+}
+
+// stripFirewalldZones removes the firewalld zones, not supported by nmstate,
+// from the desired state
+func stripFirewalldZones(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return stripInterfacesKeys(desiredState, firewalldZoneKey)
+}
+
+func sortedZoneInterfaces(zones map[string]string) []string {
+	interfaces := []string{}
+	for iface := range zones {
+		interfaces = append(interfaces, iface)
+	}
+	sort.Strings(interfaces)
+	return interfaces
+}
+
+// checkFirewalld fails if the desired state configures firewalld zones and
+// firewalld is not running at the node, so nothing is applied
+func checkFirewalld(zones map[string]string) error {
+	if len(zones) == 0 {
+		return nil
+	}
+	if _, err := firewallCmd("--state"); err != nil {
+		return fmt.Errorf("firewalld is not running at the node, cannot set the firewalld zone of interfaces %s: %v", strings.Join(sortedZoneInterfaces(zones), ", "), err)
+	}
+	return nil
+}
+
+// applyFirewalldZones sets the zone at the active NetworkManager connection
+// of the interfaces, and reapplies it so NetworkManager moves the interface
+// to the zone
+func applyFirewalldZones(zones map[string]string) (string, error) {
+	output := ""
+	for _, iface := range sortedZoneInterfaces(zones) {
+		zone := zones[iface]
+		uuid, err := activeConnectionUUID(iface)
+		if err != nil {
+			return output, err
+		}
+		nmcliOutput, err := nmcli("connection", "modify", uuid, "connection.zone", zone)
+		output += fmt.Sprintf("interface %s firewalld zone %s output: %s\n", iface, zone, nmcliOutput)
+		if err != nil {
+			return output, err
+		}
+		nmcliOutput, err = nmcli("device", "reapply", iface)
+		output += fmt.Sprintf("interface %s reapply output: %s\n", iface, nmcliOutput)
+		if err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+// parseActiveZones returns the zone of the interfaces from the output of
+// "firewall-cmd --get-active-zones", zones are listed followed by
+// their indented attributes, like interfaces and sources
+func parseActiveZones(output string) map[string]string {
+	zones := map[string]string{}
+	zone := ""
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			zone = strings.TrimSpace(line)
+			continue
+		}
+		attribute := strings.TrimSpace(line)
+		if !strings.HasPrefix(attribute, "interfaces:") {
+			continue
+		}
+		for _, iface := range strings.Fields(strings.TrimPrefix(attribute, "interfaces:")) {
+			zones[iface] = zone
+		}
+	}
+	return zones
+}
+
+// addFirewalldZones reports the firewalld zone at the current state
+// interfaces that are in one
+func addFirewalldZones(currentState nmstatev1alpha1.State, zones map[string]string) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return currentState, nil
+	}
+
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		zone, found := zones[name]
+		if !found {
+			continue
+		}
+		iface[firewalldZoneKey] = zone
+	}
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+// reportFirewalldZones reports the interfaces firewalld zones if firewalld is
+// running at the node, nodes without it report none
+func reportFirewalldZones(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	if _, err := exec.LookPath(firewallCmdCommand); err != nil {
+		return currentState, nil
+	}
+	if _, err := firewallCmd("--state"); err != nil {
+		return currentState, nil
+	}
+	output, err := firewallCmd("--get-active-zones")
+	if err != nil {
+		return currentState, err
+	}
+	return addFirewalldZones(currentState, parseActiveZones(output))
+}
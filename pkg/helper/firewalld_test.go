@@ -0,0 +1,94 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Firewalld zones", func() {
+	desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  firewalld-zone: trusted
+- name: eth2
+  type: ethernet
+  state: up
+  firewalld-zone: ""
+- name: eth3
+  type: ethernet
+  state: up
+- name: eth4
+  type: ethernet
+  state: absent
+  firewalld-zone: public
+`)
+
+	It("should configure only the interfaces with firewalld zone", func() {
+		zones, err := getFirewalldZones(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(zones).To(Equal(map[string]string{
+			"eth1": "trusted",
+			"eth2": "",
+		}))
+	})
+
+	It("should not check firewalld without zones", func() {
+		Expect(checkFirewalld(map[string]string{})).To(Succeed())
+	})
+
+	It("should remove the zones from the desired state passed to nmstate", func() {
+		strippedState, err := stripFirewalldZones(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+- name: eth2
+  type: ethernet
+  state: up
+- name: eth3
+  type: ethernet
+  state: up
+- name: eth4
+  type: ethernet
+  state: absent
+`))
+	})
+
+	It("should report the zones at current state", func() {
+		zones := parseActiveZones(`public
+  interfaces: eth0 eth2
+trusted
+  sources: 192.0.2.0/24
+  interfaces: eth1
+`)
+		Expect(zones).To(Equal(map[string]string{
+			"eth0": "public",
+			"eth1": "trusted",
+			"eth2": "public",
+		}))
+
+		currentState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+- name: eth3
+  type: ethernet
+  state: up
+`)
+		reportedState, err := addFirewalldZones(currentState, zones)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reportedState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  firewalld-zone: trusted
+- name: eth3
+  type: ethernet
+  state: up
+`))
+	})
+})
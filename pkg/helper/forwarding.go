@@ -0,0 +1,229 @@
+package helper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// nmstate does not configure the node global forwarding, it is set at the
+// desired state top level forwarding key and written to
+// /proc/sys/net/<family>/conf/all/forwarding
+const (
+	forwardingKey = "forwarding"
+
+	// Directory of the per family configuration of all the interfaces,
+	// writing its forwarding sets the one of every interface
+	sysctlAllConf = "all"
+)
+
+// globalForwarding is a desired state global forwarding of a family
+type globalForwarding struct {
+	family  string
+	enabled bool
+}
+
+func (f globalForwarding) path(netDir string) string {
+	return filepath.Join(netDir, f.family, "conf", sysctlAllConf, "forwarding")
+}
+
+// getForwarding returns the desired state global forwarding sorted by
+// family, failing with unknown families or values not booleans
+func getForwarding(desiredState nmstatev1alpha1.State) ([]globalForwarding, error) {
+	forwarding := []globalForwarding{}
+	if len(desiredState.Raw) == 0 {
+		return forwarding, nil
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return forwarding, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	desiredForwarding := gjson.GetBytes(desiredStateJSON, forwardingKey)
+	if !desiredForwarding.Exists() {
+		return forwarding, nil
+	}
+	if !desiredForwarding.IsObject() {
+		return forwarding, fmt.Errorf("invalid forwarding %s, it has to map families to true or false", desiredForwarding.Raw)
+	}
+	var familyErr error
+	desiredForwarding.ForEach(func(family, enabled gjson.Result) bool {
+		if family.String() != "ipv4" && family.String() != "ipv6" {
+			familyErr = fmt.Errorf("unsupported forwarding family %s", family.String())
+			return false
+		}
+		if enabled.Type != gjson.True && enabled.Type != gjson.False {
+			familyErr = fmt.Errorf("invalid %s forwarding %s, it has to be true or false", family.String(), enabled.Raw)
+			return false
+		}
+		return true
+	})
+	if familyErr != nil {
+		return forwarding, familyErr
+	}
+	for _, family := range sysctlFamilies {
+		if enabled := desiredForwarding.Get(family); enabled.Exists() {
+			forwarding = append(forwarding, globalForwarding{family: family, enabled: enabled.Bool()})
+		}
+	}
+	return forwarding, nil
+}
+
+// stripForwarding removes the global forwarding from the desired state
+func stripForwarding(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	if _, hasForwarding := state[forwardingKey]; !hasForwarding {
+		return desiredState, nil
+	}
+	delete(state, forwardingKey)
+
+	strippedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: strippedState}, nil
+}
+
+// applyForwarding writes the global forwarding, the kernel sets it at every
+// interface too so it has to go before the interfaces sysctls
+func applyForwarding(netDir string, forwarding []globalForwarding) (string, error) {
+	output := ""
+	for _, familyForwarding := range forwarding {
+		value := "0"
+		if familyForwarding.enabled {
+			value = "1"
+		}
+		err := ioutil.WriteFile(familyForwarding.path(netDir), []byte(value), 0644)
+		if err != nil {
+			return output, fmt.Errorf("failed setting %s forwarding: %v", familyForwarding.family, err)
+		}
+		output += fmt.Sprintf("%s forwarding set to %s\n", familyForwarding.family, value)
+	}
+	return output, nil
+}
+
+// readForwarding returns the node global forwarding of the families given,
+// the ones the kernel does not have are left out
+func readForwarding(netDir string, forwarding []globalForwarding) []globalForwarding {
+	current := []globalForwarding{}
+	for _, familyForwarding := range forwarding {
+		content, err := ioutil.ReadFile(familyForwarding.path(netDir))
+		if err != nil {
+			continue
+		}
+		current = append(current, globalForwarding{family: familyForwarding.family, enabled: strings.TrimSpace(string(content)) != "0"})
+	}
+	return current
+}
+
+// restoreForwarding writes back the global forwarding read before applying
+// the desired state, it's not part of the nmstate checkpoint
+func restoreForwarding(netDir string, previousForwarding []globalForwarding) string {
+	output, err := applyForwarding(netDir, previousForwarding)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed restoring global forwarding: %v", err))
+	}
+	return output
+}
+
+// CurrentForwarding returns the node global forwarding of the families the
+// desired state sets
+func CurrentForwarding(desiredState nmstatev1alpha1.State) (map[string]bool, error) {
+	current := map[string]bool{}
+	forwarding, err := getForwarding(desiredState)
+	if err != nil {
+		return current, err
+	}
+	for _, familyForwarding := range readForwarding(sysctlNetDir, forwarding) {
+		current[familyForwarding.family] = familyForwarding.enabled
+	}
+	return current, nil
+}
+
+// RemoveDroppedForwarding sets at the desired state the global forwarding
+// of the families it does not set anymore to the value the node had before
+// the policy set them, the kernel default would disable the IPv4 forwarding
+// the pods need
+func RemoveDroppedForwarding(previousForwarding map[string]bool, desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	if len(previousForwarding) == 0 {
+		return desiredState, nil
+	}
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	forwarding, hasForwarding := state[forwardingKey].(map[string]interface{})
+	if !hasForwarding {
+		forwarding = map[string]interface{}{}
+	}
+	dropped := false
+	for _, family := range sysctlFamilies {
+		enabled, recorded := previousForwarding[family]
+		if _, desired := forwarding[family]; !recorded || desired {
+			continue
+		}
+		forwarding[family] = enabled
+		dropped = true
+	}
+	if !dropped {
+		return desiredState, nil
+	}
+	state[forwardingKey] = forwarding
+
+	removedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: removedState}, nil
+}
+
+// addForwarding reports the global forwarding at the current state, the
+// families the kernel does not have, like IPv6 if disabled, are left out
+func addForwarding(currentState nmstatev1alpha1.State, netDir string) (nmstatev1alpha1.State, error) {
+	forwarding := map[string]interface{}{}
+	for _, family := range sysctlFamilies {
+		content, err := ioutil.ReadFile(globalForwarding{family: family}.path(netDir))
+		if err != nil {
+			continue
+		}
+		forwarding[family] = strings.TrimSpace(string(content)) != "0"
+	}
+	if len(forwarding) == 0 {
+		return currentState, nil
+	}
+
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	state[forwardingKey] = forwarding
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+func reportForwarding(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return addForwarding(currentState, sysctlNetDir)
+}
@@ -0,0 +1,119 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Global forwarding", func() {
+	const eth1 = `- name: eth1
+  type: ethernet
+  state: up
+`
+
+	It("should take the global forwarding and strip it from the nmstate desired state", func() {
+		desiredState := nmstatev1alpha1.NewState("forwarding:\n  ipv6: false\n  ipv4: true\ninterfaces:\n" + eth1)
+
+		forwarding, err := getForwarding(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(forwarding).To(Equal([]globalForwarding{
+			{family: "ipv4", enabled: true},
+			{family: "ipv6", enabled: false},
+		}))
+
+		strippedState, err := stripForwarding(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML("interfaces:\n" + eth1))
+	})
+
+	DescribeTable("with invalid forwarding",
+		func(forwarding string) {
+			_, err := getForwarding(nmstatev1alpha1.NewState("forwarding:" + forwarding))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("not a map", " true\n"),
+		Entry("unsupported family", "\n  mpls: true\n"),
+		Entry("value not a boolean", "\n  ipv4: 1\n"),
+	)
+
+	Context("at the node", func() {
+		var netDir string
+
+		writeForwarding := func(family string, value string) {
+			dir := filepath.Join(netDir, family, "conf", "all")
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "forwarding"), []byte(value+"\n"), 0644)).To(Succeed())
+		}
+
+		forwardingValue := func(family string) string {
+			content, err := ioutil.ReadFile(filepath.Join(netDir, family, "conf", "all", "forwarding"))
+			Expect(err).ToNot(HaveOccurred())
+			return string(content)
+		}
+
+		BeforeEach(func() {
+			var err error
+			netDir, err = ioutil.TempDir("", "proc-sys-net")
+			Expect(err).ToNot(HaveOccurred())
+			writeForwarding("ipv4", "0")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(netDir)
+		})
+
+		It("should set it", func() {
+			writeForwarding("ipv6", "1")
+			_, err := applyForwarding(netDir, []globalForwarding{{family: "ipv4", enabled: true}, {family: "ipv6", enabled: false}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(forwardingValue("ipv4")).To(Equal("1"))
+			Expect(forwardingValue("ipv6")).To(Equal("0"))
+		})
+
+		It("should report it at the current state without the missing families", func() {
+			reportedState, err := addForwarding(nmstatev1alpha1.NewState("interfaces:\n"+eth1), netDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reportedState.String()).To(MatchYAML("forwarding:\n  ipv4: false\ninterfaces:\n" + eth1))
+		})
+
+		It("should restore the forwarding read before setting it", func() {
+			forwarding := []globalForwarding{{family: "ipv4", enabled: true}, {family: "ipv6", enabled: true}}
+			previousForwarding := readForwarding(netDir, forwarding)
+			Expect(previousForwarding).To(Equal([]globalForwarding{{family: "ipv4", enabled: false}}))
+
+			_, err := applyForwarding(netDir, forwarding[:1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(forwardingValue("ipv4")).To(Equal("1"))
+
+			restoreForwarding(netDir, previousForwarding)
+			Expect(forwardingValue("ipv4")).To(Equal("0"))
+		})
+	})
+
+	DescribeTable("when the policy drops it",
+		func(previousForwarding map[string]bool, desiredState string, expectedState string) {
+			removedState, err := RemoveDroppedForwarding(previousForwarding, nmstatev1alpha1.NewState(desiredState))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removedState.String()).To(MatchYAML(expectedState))
+		},
+		Entry("should restore the dropped families to their previous value",
+			map[string]bool{"ipv4": true, "ipv6": false},
+			"forwarding:\n  ipv4: true\ninterfaces:\n"+eth1,
+			"forwarding:\n  ipv4: true\n  ipv6: false\ninterfaces:\n"+eth1),
+		Entry("should restore them once the whole forwarding is dropped",
+			map[string]bool{"ipv6": false},
+			"interfaces:\n"+eth1,
+			"forwarding:\n  ipv6: false\ninterfaces:\n"+eth1),
+		Entry("should keep the desired state without previous forwarding",
+			map[string]bool{},
+			"interfaces:\n"+eth1,
+			"interfaces:\n"+eth1),
+	)
+})
@@ -0,0 +1,115 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+const hardwareKey = render.HardwareKey
+
+// deviceHardwareFields are the NetworkManager device fields reported at the
+// interfaces hardware
+var deviceHardwareFields = map[string]string{
+	"GENERAL.DRIVER":           "driver",
+	"GENERAL.DRIVER-VERSION":   "driver-version",
+	"GENERAL.FIRMWARE-VERSION": "firmware-version",
+}
+
+// parseDeviceHardware returns the driver and firmware of every device from
+// "nmcli -t -f GENERAL.DEVICE,GENERAL.DRIVER,GENERAL.DRIVER-VERSION,GENERAL.FIRMWARE-VERSION device show",
+// the fields of every device follow its name and empty ones are left out
+func parseDeviceHardware(output string) map[string]map[string]interface{} {
+	hardware := map[string]map[string]interface{}{}
+	device := ""
+	for _, line := range strings.Split(output, "\n") {
+		fields := splitTerseFields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		field, value := fields[0], strings.TrimSpace(strings.Join(fields[1:], ":"))
+		if field == "GENERAL.DEVICE" {
+			device = value
+			continue
+		}
+		key, reported := deviceHardwareFields[field]
+		if !reported || device == "" || value == "" || value == "--" {
+			continue
+		}
+		if hardware[device] == nil {
+			hardware[device] = map[string]interface{}{}
+		}
+		hardware[device][key] = value
+	}
+	return hardware
+}
+
+// interfacesHardware returns the hardware of the physical interfaces, the
+// MAC and PCI addresses they are matched by and their driver and firmware.
+// Virtual interfaces have no device, they have no hardware either.
+func interfacesHardware(interfaces []render.Interface, devices map[string]map[string]interface{}) map[string]map[string]interface{} {
+	hardware := map[string]map[string]interface{}{}
+	for _, iface := range interfaces {
+		interfaceHardware := map[string]interface{}{}
+		if iface.MACAddress != "" {
+			interfaceHardware[render.HardwareMACAddressKey] = iface.MACAddress
+		}
+		if iface.PCIAddress != "" {
+			interfaceHardware[render.HardwarePCIAddressKey] = iface.PCIAddress
+		}
+		for key, value := range devices[iface.Name] {
+			interfaceHardware[key] = value
+		}
+		if len(interfaceHardware) > 0 {
+			hardware[iface.Name] = interfaceHardware
+		}
+	}
+	return hardware
+}
+
+// addHardware reports the hardware of the current state interfaces
+func addHardware(currentState nmstatev1alpha1.State, hardware map[string]map[string]interface{}) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return currentState, nil
+	}
+
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		if interfaceHardware, found := hardware[name]; found {
+			iface[hardwareKey] = interfaceHardware
+		}
+	}
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+func reportHardware(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	interfaces, err := listPhysicalInterfaces(sysClassNetDir)
+	if err != nil {
+		return currentState, fmt.Errorf("failed listing physical interfaces: %v", err)
+	}
+	output, err := nmcli("-t", "-f", "GENERAL.DEVICE,GENERAL.DRIVER,GENERAL.DRIVER-VERSION,GENERAL.FIRMWARE-VERSION", "device", "show")
+	if err != nil {
+		return currentState, fmt.Errorf("failed retrieving devices driver: %v", err)
+	}
+	return addHardware(currentState, interfacesHardware(interfaces, parseDeviceHardware(output)))
+}
@@ -0,0 +1,71 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+var _ = Describe("Interfaces hardware", func() {
+	It("should parse the devices driver and firmware", func() {
+		devices := parseDeviceHardware(`GENERAL.DEVICE:ens1f0
+GENERAL.DRIVER:i40e
+GENERAL.DRIVER-VERSION:2.8.20-k
+GENERAL.FIRMWARE-VERSION:6.80 0x80003d05 1.2007.0
+
+GENERAL.DEVICE:eth0
+GENERAL.DRIVER:virtio_net
+GENERAL.DRIVER-VERSION:1.0.0
+GENERAL.FIRMWARE-VERSION:
+
+GENERAL.DEVICE:br1
+GENERAL.DRIVER:bridge
+GENERAL.DRIVER-VERSION:2.3
+GENERAL.FIRMWARE-VERSION:N/A
+`)
+		Expect(devices).To(Equal(map[string]map[string]interface{}{
+			"ens1f0": {"driver": "i40e", "driver-version": "2.8.20-k", "firmware-version": "6.80 0x80003d05 1.2007.0"},
+			"eth0":   {"driver": "virtio_net", "driver-version": "1.0.0"},
+			"br1":    {"driver": "bridge", "driver-version": "2.3", "firmware-version": "N/A"},
+		}))
+	})
+
+	It("should report the hardware of the physical interfaces only", func() {
+		hardware := interfacesHardware([]render.Interface{
+			{Name: "ens1f0", MACAddress: "52:55:00:d1:56:01", PCIAddress: "0000:03:00.0"},
+			{Name: "eth0", MACAddress: "52:55:00:d1:56:02", PCIAddress: "0000:00:03.0"},
+		}, map[string]map[string]interface{}{
+			"ens1f0": {"driver": "i40e", "firmware-version": "6.80"},
+			"br1":    {"driver": "bridge"},
+		})
+		Expect(hardware).To(Equal(map[string]map[string]interface{}{
+			"ens1f0": {"mac-address": "52:55:00:d1:56:01", "pci-address": "0000:03:00.0", "driver": "i40e", "firmware-version": "6.80"},
+			"eth0":   {"mac-address": "52:55:00:d1:56:02", "pci-address": "0000:00:03.0"},
+		}))
+
+		reportedState, err := addHardware(nmstatev1alpha1.NewState(`interfaces:
+- name: ens1f0
+  type: ethernet
+  state: up
+- name: br1
+  type: linux-bridge
+  state: up
+`), hardware)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reportedState.String()).To(MatchYAML(`interfaces:
+- name: ens1f0
+  type: ethernet
+  state: up
+  hardware:
+    mac-address: 52:55:00:d1:56:01
+    pci-address: "0000:03:00.0"
+    driver: i40e
+    firmware-version: "6.80"
+- name: br1
+  type: linux-bridge
+  state: up
+`))
+	})
+})
@@ -0,0 +1,132 @@
+package helper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const (
+	infinibandInterfaceType = "infiniband"
+
+	// Default partition key, the one of the InfiniBand interface itself, it
+	// does not create a child
+	infinibandDefaultPkey = 0xffff
+
+	// ARPHRD_INFINIBAND, the kernel link type of the IPoIB interfaces at
+	// /sys/class/net/<iface>/type
+	arphrdInfiniband = "32"
+)
+
+var infinibandModes = map[string]bool{
+	"datagram":  true,
+	"connected": true,
+}
+
+// ipoibInterface is an InfiniBand interface of the desired state, the child
+// of the base interface at a partition key when the pkey is not the default
+type ipoibInterface struct {
+	name     string
+	baseName string
+	pkey     uint64
+	mode     string
+}
+
+// hardwareName returns the interface that has to be an InfiniBand one at
+// the node for the IPoIB interface to be applied
+func (i ipoibInterface) hardwareName() string {
+	if i.pkey == infinibandDefaultPkey {
+		return i.name
+	}
+	return i.baseName
+}
+
+// parsePkey takes the partition key as nmstate does, a number or a string
+// with its hex or decimal representation
+func parsePkey(pkey gjson.Result) (uint64, error) {
+	if !pkey.Exists() {
+		return infinibandDefaultPkey, nil
+	}
+	value := pkey.String()
+	if pkey.Type == gjson.Number {
+		value = pkey.Raw
+	}
+	parsed, err := strconv.ParseUint(value, 0, 16)
+	if err != nil || parsed == 0 {
+		return 0, fmt.Errorf("invalid pkey %s, it has to be between 0x0001 and 0xffff", value)
+	}
+	return parsed, nil
+}
+
+// getIPoIBInterfaces returns the InfiniBand interfaces configured by the
+// desired state, it fails with the ones nmstate would not be able to apply
+func getIPoIBInterfaces(desiredState nmstatev1alpha1.State) ([]ipoibInterface, error) {
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting desired state to JSON: %v", err)
+	}
+
+	ipoibs := []ipoibInterface{}
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		if iface.Get("type").String() != infinibandInterfaceType || iface.Get("state").String() == "absent" {
+			continue
+		}
+		name := iface.Get("name").String()
+		pkey, err := parsePkey(iface.Get("infiniband.pkey"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid InfiniBand interface %s: %v", name, err)
+		}
+		ipoib := ipoibInterface{
+			name:     name,
+			baseName: iface.Get("infiniband.base-iface").String(),
+			pkey:     pkey,
+			mode:     iface.Get("infiniband.mode").String(),
+		}
+		if !infinibandModes[ipoib.mode] {
+			return nil, fmt.Errorf("invalid InfiniBand interface %s: mode has to be datagram or connected, not '%s'", name, ipoib.mode)
+		}
+		if ipoib.pkey != infinibandDefaultPkey && ipoib.baseName == "" {
+			return nil, fmt.Errorf("invalid InfiniBand interface %s: base-iface is needed to create a child at pkey 0x%04x", name, ipoib.pkey)
+		}
+		ipoibs = append(ipoibs, ipoib)
+	}
+	return ipoibs, nil
+}
+
+// checkInfinibandHardware fails if the interfaces the IPoIB ones need are
+// not InfiniBand interfaces at the node, nmstate would fail with an error
+// not saying so
+func checkInfinibandHardware(netDir string, ipoibs []ipoibInterface) error {
+	for _, ipoib := range ipoibs {
+		hardwareName := ipoib.hardwareName()
+		linkType, err := ioutil.ReadFile(filepath.Join(netDir, hardwareName, "type"))
+		if os.IsNotExist(err) {
+			return fmt.Errorf("InfiniBand interface %s needs %s, not found at the node, is there InfiniBand hardware at it?", ipoib.name, hardwareName)
+		}
+		if err != nil {
+			return fmt.Errorf("failed reading %s link type: %v", hardwareName, err)
+		}
+		if strings.TrimSpace(string(linkType)) != arphrdInfiniband {
+			return fmt.Errorf("InfiniBand interface %s needs %s to be an InfiniBand interface, link type is %s", ipoib.name, hardwareName, strings.TrimSpace(string(linkType)))
+		}
+	}
+	return nil
+}
+
+// checkInfiniband fails if the desired state InfiniBand interfaces are not
+// valid or the node lacks the InfiniBand hardware for them
+func checkInfiniband(desiredState nmstatev1alpha1.State) error {
+	ipoibs, err := getIPoIBInterfaces(desiredState)
+	if err != nil {
+		return err
+	}
+	return checkInfinibandHardware(sysClassNetDir, ipoibs)
+}
@@ -0,0 +1,91 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("InfiniBand interfaces", func() {
+	const ib0Child = `interfaces:
+- name: ib0.8001
+  type: infiniband
+  state: up
+  infiniband:
+    base-iface: ib0
+    mode: datagram
+    pkey: "0x8001"
+`
+
+	It("should take the IPoIB child created on a pkey", func() {
+		ipoibs, err := getIPoIBInterfaces(nmstatev1alpha1.NewState(ib0Child))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ipoibs).To(ConsistOf(ipoibInterface{name: "ib0.8001", baseName: "ib0", pkey: 0x8001, mode: "datagram"}))
+		Expect(ipoibs[0].hardwareName()).To(Equal("ib0"))
+	})
+
+	It("should take the InfiniBand interface itself without pkey", func() {
+		ipoibs, err := getIPoIBInterfaces(nmstatev1alpha1.NewState("interfaces:\n- name: ib0\n  type: infiniband\n  state: up\n  infiniband:\n    mode: connected\n- name: eth1\n  type: ethernet\n  state: up\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ipoibs).To(ConsistOf(ipoibInterface{name: "ib0", pkey: infinibandDefaultPkey, mode: "connected"}))
+		Expect(ipoibs[0].hardwareName()).To(Equal("ib0"))
+	})
+
+	DescribeTable("with invalid InfiniBand interfaces",
+		func(infiniband string) {
+			_, err := getIPoIBInterfaces(nmstatev1alpha1.NewState("interfaces:\n- name: ib0.8001\n  type: infiniband\n  state: up\n  infiniband:\n" + infiniband))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unknown mode", "    base-iface: ib0\n    mode: bonded\n    pkey: 0x8001\n"),
+		Entry("without mode", "    base-iface: ib0\n    pkey: 0x8001\n"),
+		Entry("pkey out of range", "    base-iface: ib0\n    mode: datagram\n    pkey: 0x10000\n"),
+		Entry("zero pkey", "    base-iface: ib0\n    mode: datagram\n    pkey: 0\n"),
+		Entry("pkey without base interface", "    mode: datagram\n    pkey: 0x8001\n"),
+	)
+
+	Context("at the node", func() {
+		var netDir string
+
+		writeLinkType := func(name string, linkType string) {
+			dir := filepath.Join(netDir, name)
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "type"), []byte(linkType+"\n"), 0644)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			var err error
+			netDir, err = ioutil.TempDir("", "sys-class-net")
+			Expect(err).ToNot(HaveOccurred())
+			writeLinkType("eth0", "1")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(netDir)
+		})
+
+		It("should create the IPoIB child with InfiniBand hardware", func() {
+			writeLinkType("ib0", arphrdInfiniband)
+			ipoibs, err := getIPoIBInterfaces(nmstatev1alpha1.NewState(ib0Child))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checkInfinibandHardware(netDir, ipoibs)).To(Succeed())
+		})
+
+		It("should fail clearly without InfiniBand hardware", func() {
+			ipoibs, err := getIPoIBInterfaces(nmstatev1alpha1.NewState(ib0Child))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checkInfinibandHardware(netDir, ipoibs)).To(MatchError(ContainSubstring("is there InfiniBand hardware at it?")))
+		})
+
+		It("should fail with a base interface that is not InfiniBand", func() {
+			ipoibs, err := getIPoIBInterfaces(nmstatev1alpha1.NewState("interfaces:\n- name: eth0.8001\n  type: infiniband\n  state: up\n  infiniband:\n    base-iface: eth0\n    mode: datagram\n    pkey: 0x8001\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checkInfinibandHardware(netDir, ipoibs)).To(MatchError(ContainSubstring("needs eth0 to be an InfiniBand interface")))
+		})
+	})
+})
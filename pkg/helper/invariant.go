@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+// NodeInvariant returns true if the policy desired state is rendered the
+// same at every node: it has no desired state patch rendered against the
+// node state, no interfaces matched by MAC or PCI address and no secret
+// references resolved by the handler
+func NodeInvariant(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec) (bool, error) {
+	if policySpec.DesiredStatePatch != nil || render.HasInterfaceMatches(policySpec.DesiredState) {
+		return false, nil
+	}
+	secretNames, err := SecretReferences(policySpec.DesiredState)
+	if err != nil {
+		return false, err
+	}
+	return len(secretNames) == 0, nil
+}
+
+// DesiredStateFingerprint returns the fingerprint of the desired state, the
+// policies desired states are decoded to the same YAML at the webhook and the
+// handlers so it does not need to be parsed
+func DesiredStateFingerprint(desiredState nmstatev1alpha1.State) string {
+	return fmt.Sprintf("%x", sha256.Sum256(desiredState.Raw))
+}
@@ -0,0 +1,47 @@
+package helper
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Node invariant policies", func() {
+	DescribeTable("detecting the desired states rendered the same at every node",
+		func(desiredState string, desiredStatePatch string, expectedNodeInvariant bool) {
+			policySpec := nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{DesiredState: nmstatev1alpha1.NewState(desiredState)}
+			if desiredStatePatch != "" {
+				patch := nmstatev1alpha1.NewStatePatch(desiredStatePatch)
+				policySpec.DesiredStatePatch = &patch
+			}
+			nodeInvariant, err := NodeInvariant(policySpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodeInvariant).To(Equal(expectedNodeInvariant))
+		},
+		Entry("static desired state", "interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n", "", true),
+		Entry("desired state patch", "", "- op: replace\n  path: /interfaces/0/mtu\n  value: 9000\n", false),
+		Entry("interfaces matched by address", "interfaces:\n- name: uplink\n  type: ethernet\n  state: up\n  match:\n    mac-address: 02:00:5e:10:00:99\n", "", false),
+		Entry("secret references", "interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  802.1x:\n    password:\n      secretKeyRef:\n        name: eth1-8021x\n        key: password\n", "", false),
+	)
+
+	It("should fingerprint the desired state the same at the webhook and the handlers", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState(`{"interfaces": [{"state": "up", "name": "eth1", "type": "ethernet"}]}`)
+		webhookPolicy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policyJSON, err := json.Marshal(policy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(json.Unmarshal(policyJSON, &webhookPolicy)).To(Succeed())
+
+		handlerPolicy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policyJSON, err = json.Marshal(webhookPolicy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(json.Unmarshal(policyJSON, &handlerPolicy)).To(Succeed())
+
+		Expect(DesiredStateFingerprint(handlerPolicy.Spec.DesiredState)).To(Equal(DesiredStateFingerprint(webhookPolicy.Spec.DesiredState)))
+		Expect(DesiredStateFingerprint(handlerPolicy.Spec.DesiredState)).ToNot(Equal(DesiredStateFingerprint(nmstatev1alpha1.NewState("interfaces: []\n"))))
+	})
+})
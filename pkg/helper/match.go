@@ -0,0 +1,65 @@
+package helper
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+// PCI addresses as domain:bus:slot.function, like 0000:03:00.0
+var pciAddressRegexp = regexp.MustCompile(`^[0-9a-f]{4}:[0-9a-f]{2}:[0-9a-f]{2}\.[0-7]$`)
+
+// pciAddress returns the PCI address closest to the device, virtio devices
+// are children of the PCI one
+func pciAddress(devicePath string) string {
+	components := strings.Split(devicePath, string(filepath.Separator))
+	for i := len(components) - 1; i >= 0; i-- {
+		if pciAddressRegexp.MatchString(components[i]) {
+			return components[i]
+		}
+	}
+	return ""
+}
+
+// listPhysicalInterfaces returns the interfaces with device at the net class
+// directory. Bond slaves take the bond MAC address, their permanent one is
+// used instead.
+func listPhysicalInterfaces(netDir string) ([]render.Interface, error) {
+	interfaceDirs, err := filepath.Glob(filepath.Join(netDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	interfaces := []render.Interface{}
+	for _, interfaceDir := range interfaceDirs {
+		device, err := filepath.EvalSymlinks(filepath.Join(interfaceDir, "device"))
+		if err != nil {
+			continue
+		}
+		iface := render.Interface{Name: filepath.Base(interfaceDir), PCIAddress: pciAddress(device)}
+		address, err := ioutil.ReadFile(filepath.Join(interfaceDir, "bonding_slave", "perm_hwaddr"))
+		if err != nil {
+			address, _ = ioutil.ReadFile(filepath.Join(interfaceDir, "address"))
+		}
+		iface.MACAddress = strings.ToLower(strings.TrimSpace(string(address)))
+		interfaces = append(interfaces, iface)
+	}
+	return interfaces, nil
+}
+
+// ResolveInterfaceMatches returns the desired state with the interfaces
+// identified by MAC or PCI address named as at the node, failing with
+// render.IsInterfaceNotFound if any of them is missing
+func ResolveInterfaceMatches(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	if !render.HasInterfaceMatches(desiredState) {
+		return desiredState, nil
+	}
+	interfaces, err := listPhysicalInterfaces(sysClassNetDir)
+	if err != nil {
+		return desiredState, err
+	}
+	return render.ResolveInterfaceMatches(desiredState, interfaces)
+}
@@ -0,0 +1,45 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+var _ = Describe("Interface match", func() {
+	It("should list the interfaces with device and their addresses", func() {
+		sysDir, err := ioutil.TempDir("", "match")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(sysDir)
+
+		netDir := filepath.Join(sysDir, "class", "net")
+		addInterface := func(name string, address string, device string) {
+			interfaceDir := filepath.Join(netDir, name)
+			Expect(os.MkdirAll(interfaceDir, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(interfaceDir, "address"), []byte(address+"\n"), 0644)).To(Succeed())
+			if device != "" {
+				deviceDir := filepath.Join(sysDir, "devices", device)
+				Expect(os.MkdirAll(deviceDir, 0755)).To(Succeed())
+				Expect(os.Symlink(deviceDir, filepath.Join(interfaceDir, "device"))).To(Succeed())
+			}
+		}
+		addInterface("ens1f0", "52:55:00:D1:56:01", "pci0000:00/0000:03:00.0")
+		addInterface("eth0", "52:55:00:d1:56:02", "pci0000:00/0000:00:03.0/virtio0")
+		addInterface("bond0", "52:55:00:d1:56:01", "")
+		bondingSlaveDir := filepath.Join(netDir, "ens1f0", "bonding_slave")
+		Expect(os.MkdirAll(bondingSlaveDir, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(bondingSlaveDir, "perm_hwaddr"), []byte("52:55:00:d1:56:09\n"), 0644)).To(Succeed())
+
+		interfaces, err := listPhysicalInterfaces(netDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(interfaces).To(ConsistOf(
+			render.Interface{Name: "ens1f0", MACAddress: "52:55:00:d1:56:09", PCIAddress: "0000:03:00.0"},
+			render.Interface{Name: "eth0", MACAddress: "52:55:00:d1:56:02", PCIAddress: "0000:00:03.0"},
+		))
+	})
+})
@@ -0,0 +1,87 @@
+package helper
+
+import (
+	"fmt"
+	"reflect"
+
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// MergeDesiredStates merges desired states so they can be applied in a
+// single nmstate transaction. Interfaces, route and neighbor configs are
+// appended, an interface cannot be configured by more than one of them and
+// the rest of the attributes have to be equal if present at several of them.
+func MergeDesiredStates(desiredStates []nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	merged := map[string]interface{}{}
+	interfaces := []interface{}{}
+	routes := []interface{}{}
+	neighbors := []interface{}{}
+	interfaceNames := map[string]bool{}
+
+	for _, desiredState := range desiredStates {
+		state := map[string]interface{}{}
+		err := yaml.Unmarshal(desiredState.Raw, &state)
+		if err != nil {
+			return nmstatev1alpha1.State{}, err
+		}
+		for key, value := range state {
+			switch key {
+			case "interfaces":
+				stateInterfaces, _ := value.([]interface{})
+				for _, iface := range stateInterfaces {
+					ifaceAttributes, _ := iface.(map[string]interface{})
+					name, _ := ifaceAttributes["name"].(string)
+					if interfaceNames[name] {
+						return nmstatev1alpha1.State{}, fmt.Errorf("interface %s is configured by more than one desired state", name)
+					}
+					interfaceNames[name] = true
+					interfaces = append(interfaces, iface)
+				}
+			case "routes":
+				stateRoutes, _ := value.(map[string]interface{})
+				for routesKey, routesValue := range stateRoutes {
+					if routesKey != "config" {
+						return nmstatev1alpha1.State{}, fmt.Errorf("unexpected routes attribute %s", routesKey)
+					}
+					config, _ := routesValue.([]interface{})
+					routes = append(routes, config...)
+				}
+			case neighborsKey:
+				stateNeighbors, _ := value.(map[string]interface{})
+				for neighborsAttribute, neighborsValue := range stateNeighbors {
+					if neighborsAttribute != "config" {
+						return nmstatev1alpha1.State{}, fmt.Errorf("unexpected neighbors attribute %s", neighborsAttribute)
+					}
+					config, _ := neighborsValue.([]interface{})
+					neighbors = append(neighbors, config...)
+				}
+			default:
+				if mergedValue, found := merged[key]; found && !reflect.DeepEqual(mergedValue, value) {
+					return nmstatev1alpha1.State{}, fmt.Errorf("%s differs between desired states", key)
+				}
+				merged[key] = value
+			}
+		}
+	}
+
+	if len(interfaces) > 0 {
+		merged["interfaces"] = interfaces
+	}
+	if len(routes) > 0 {
+		merged["routes"] = map[string]interface{}{"config": routes}
+	}
+	if len(neighbors) > 0 {
+		merged[neighborsKey] = map[string]interface{}{"config": neighbors}
+	}
+	if len(merged) == 0 {
+		return nmstatev1alpha1.NewState(""), nil
+	}
+
+	mergedState, err := yaml.Marshal(merged)
+	if err != nil {
+		return nmstatev1alpha1.State{}, err
+	}
+	return nmstatev1alpha1.State{Raw: mergedState}, nil
+}
@@ -0,0 +1,97 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("MergeDesiredStates", func() {
+	bond := nmstatev1alpha1.NewState(`interfaces:
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: active-backup
+    slaves:
+    - eth1
+    - eth2
+`)
+	vlan := nmstatev1alpha1.NewState(`interfaces:
+- name: bond0.102
+  type: vlan
+  state: up
+  vlan:
+    base-iface: bond0
+    id: 102
+routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-interface: bond0.102
+`)
+
+	It("should append interfaces and routes", func() {
+		merged, err := MergeDesiredStates([]nmstatev1alpha1.State{bond, vlan})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(merged.String()).To(MatchYAML(`interfaces:
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: active-backup
+    slaves:
+    - eth1
+    - eth2
+- name: bond0.102
+  type: vlan
+  state: up
+  vlan:
+    base-iface: bond0
+    id: 102
+routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-interface: bond0.102
+`))
+	})
+
+	It("should append neighbors", func() {
+		neighbor := func(ip string) nmstatev1alpha1.State {
+			return nmstatev1alpha1.NewState("neighbors:\n  config:\n  - ip: " + ip + "\n    link-layer-address: 52:54:00:12:b6:a1\n    interface: br1\n")
+		}
+		merged, err := MergeDesiredStates([]nmstatev1alpha1.State{neighbor("192.0.2.1"), neighbor("192.0.2.2")})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(merged.String()).To(MatchYAML(`neighbors:
+  config:
+  - ip: 192.0.2.1
+    link-layer-address: 52:54:00:12:b6:a1
+    interface: br1
+  - ip: 192.0.2.2
+    link-layer-address: 52:54:00:12:b6:a1
+    interface: br1
+`))
+	})
+
+	It("should fail if an interface is configured twice", func() {
+		_, err := MergeDesiredStates([]nmstatev1alpha1.State{bond, bond})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if other attributes differ", func() {
+		dns1 := nmstatev1alpha1.NewState(`dns-resolver:
+  config:
+    server:
+    - 192.0.2.1
+`)
+		dns2 := nmstatev1alpha1.NewState(`dns-resolver:
+  config:
+    server:
+    - 192.0.2.2
+`)
+		_, err := MergeDesiredStates([]nmstatev1alpha1.State{dns1, dns1})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = MergeDesiredStates([]nmstatev1alpha1.State{dns1, dns2})
+		Expect(err).To(HaveOccurred())
+	})
+})
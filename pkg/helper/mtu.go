@@ -0,0 +1,253 @@
+package helper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+// mtuInterface is a desired state interface along with the interfaces its
+// MTU depends on, a VLAN or VXLAN MTU cannot be higher than the base
+// interface one and a bond sets its MTU at its slaves
+type mtuInterface struct {
+	name   string
+	kind   string
+	mtu    int
+	bases  []string
+	slaves []string
+}
+
+func getMTUInterfaces(desiredState nmstatev1alpha1.State) ([]mtuInterface, error) {
+	interfaces := []mtuInterface{}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return interfaces, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		mtuIface := mtuInterface{
+			name: iface.Get("name").String(),
+			kind: iface.Get("type").String(),
+		}
+		if iface.Get("state").String() != "absent" {
+			mtuIface.mtu = int(iface.Get("mtu").Int())
+		}
+		for _, base := range []gjson.Result{iface.Get("vlan.base-iface"), iface.Get("vxlan.base-iface")} {
+			if base.String() != "" {
+				mtuIface.bases = append(mtuIface.bases, base.String())
+			}
+		}
+		for _, slave := range render.BondPorts(iface) {
+			mtuIface.slaves = append(mtuIface.slaves, slave.String())
+		}
+		interfaces = append(interfaces, mtuIface)
+	}
+	return interfaces, nil
+}
+
+// readMTUs returns the MTU of the interfaces and bond slaves at the node,
+// interfaces not created yet are missing
+func readMTUs(interfaces []mtuInterface) map[string]int {
+	mtus := map[string]int{}
+	names := []string{}
+	for _, iface := range interfaces {
+		names = append(names, iface.name)
+		names = append(names, iface.slaves...)
+	}
+	for _, name := range names {
+		content, err := ioutil.ReadFile(filepath.Join(sysClassNetDir, name, "mtu"))
+		if err != nil {
+			continue
+		}
+		mtu, err := strconv.Atoi(strings.TrimSpace(string(content)))
+		if err != nil {
+			continue
+		}
+		mtus[name] = mtu
+	}
+	return mtus
+}
+
+// mtuDirection tells if the desired state raises, 1, or lowers, -1, the
+// interface MTU, it's 0 for interfaces without MTU, keeping it or not
+// created yet
+func mtuDirection(iface mtuInterface, currentMTUs map[string]int) int {
+	current, exists := currentMTUs[iface.name]
+	if iface.mtu == 0 || !exists || iface.mtu == current {
+		return 0
+	}
+	if iface.mtu > current {
+		return 1
+	}
+	return -1
+}
+
+// mtuDepth returns how many levels of desired state interfaces are below
+// the interface, like 2 for a VLAN over a bond over the desired state
+// ethernet interfaces
+func mtuDepth(name string, interfacesByName map[string]mtuInterface, visited map[string]bool) int {
+	iface, found := interfacesByName[name]
+	if !found || visited[name] {
+		return 0
+	}
+	visited[name] = true
+	defer delete(visited, name)
+
+	depth := 0
+	for _, lower := range append(append([]string{}, iface.bases...), iface.slaves...) {
+		if _, found := interfacesByName[lower]; !found {
+			continue
+		}
+		if lowerDepth := mtuDepth(lower, interfacesByName, visited) + 1; lowerDepth > depth {
+			depth = lowerDepth
+		}
+	}
+	return depth
+}
+
+// mtuOrder returns the desired state interfaces names in the order they
+// change their MTU without exceeding the base interfaces one: the
+// interfaces lowering it go first, from the top ones down to their bases,
+// then the ones not changing it and last the ones raising it, from the
+// bases up. Otherwise the order of the desired state is kept.
+func mtuOrder(interfaces []mtuInterface, currentMTUs map[string]int) []string {
+	interfacesByName := map[string]mtuInterface{}
+	for _, iface := range interfaces {
+		interfacesByName[iface.name] = iface
+	}
+
+	type rank struct {
+		name  string
+		group int
+		depth int
+	}
+	ranks := []rank{}
+	for _, iface := range interfaces {
+		direction := mtuDirection(iface, currentMTUs)
+		depth := mtuDepth(iface.name, interfacesByName, map[string]bool{})
+		switch direction {
+		case -1:
+			ranks = append(ranks, rank{name: iface.name, group: 0, depth: -depth})
+		case 0:
+			ranks = append(ranks, rank{name: iface.name, group: 1})
+		case 1:
+			ranks = append(ranks, rank{name: iface.name, group: 2, depth: depth})
+		}
+	}
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].group != ranks[j].group {
+			return ranks[i].group < ranks[j].group
+		}
+		return ranks[i].depth < ranks[j].depth
+	})
+
+	order := []string{}
+	for _, r := range ranks {
+		order = append(order, r.name)
+	}
+	return order
+}
+
+// mtuSteps returns the desired state interfaces changing their MTU at the
+// node, following mtuOrder
+func mtuSteps(interfaces []mtuInterface, currentMTUs map[string]int) []mtuInterface {
+	interfacesByName := map[string]mtuInterface{}
+	for _, iface := range interfaces {
+		interfacesByName[iface.name] = iface
+	}
+	steps := []mtuInterface{}
+	for _, name := range mtuOrder(interfaces, currentMTUs) {
+		if iface := interfacesByName[name]; mtuDirection(iface, currentMTUs) != 0 {
+			steps = append(steps, iface)
+		}
+	}
+	return steps
+}
+
+var setMTU = func(name string, mtu int) (string, error) {
+	return ip("link", "set", "dev", name, "mtu", strconv.Itoa(mtu))
+}
+
+// applyMTUSteps changes the MTU of the interfaces one at a time, in the
+// mtuSteps order, before nmstate applies the desired state. nmstate
+// changes them in its own order, not the desired state one, once they
+// are already changed it only persists them. If a step fails the ones
+// already applied are restored.
+func applyMTUSteps(steps []mtuInterface, currentMTUs map[string]int) (string, error) {
+	output := ""
+	for i, iface := range steps {
+		ipOutput, err := setMTU(iface.name, iface.mtu)
+		output += fmt.Sprintf("interface %s mtu %d output: %s\n", iface.name, iface.mtu, ipOutput)
+		if err != nil {
+			output += restoreMTUs(steps[:i], currentMTUs)
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+// restoreMTUs sets back the MTUs changed by applyMTUSteps, in the reverse
+// order, when nmstate fails without applying the desired state or when it's
+// rolled back, the steps are not part of the nmstate checkpoint.
+func restoreMTUs(steps []mtuInterface, currentMTUs map[string]int) string {
+	output := ""
+	for i := len(steps) - 1; i >= 0; i-- {
+		name := steps[i].name
+		ipOutput, err := setMTU(name, currentMTUs[name])
+		output += fmt.Sprintf("interface %s mtu %d restore output: %s\n", name, currentMTUs[name], ipOutput)
+		if err != nil {
+			log.Info(fmt.Sprintf("failed restoring interface %s MTU %d: %v", name, currentMTUs[name], err))
+		}
+	}
+	return output
+}
+
+// mtuLinkFlaps returns the links bounced by the desired state MTU changes
+// whatever the order. Most NIC drivers reset the link to resize their
+// receive buffers when the MTU changes, so changing the MTU of an ethernet
+// interface, or of the bond it's a slave of, flaps it.
+func mtuLinkFlaps(interfaces []mtuInterface, currentMTUs map[string]int) []string {
+	flaps := map[string]bool{}
+	for _, iface := range interfaces {
+		if mtuDirection(iface, currentMTUs) == 0 {
+			continue
+		}
+		switch iface.kind {
+		case "ethernet":
+			flaps[iface.name] = true
+		case "bond":
+			for _, slave := range iface.slaves {
+				if current, exists := currentMTUs[slave]; exists && current != iface.mtu {
+					flaps[slave] = true
+				}
+			}
+		}
+	}
+
+	links := []string{}
+	for link := range flaps {
+		links = append(links, link)
+	}
+	sort.Strings(links)
+	return links
+}
+
+// MTULinkFlaps returns the links at the node the desired state MTU changes
+// cannot avoid bouncing, it has to be called before applying it
+func MTULinkFlaps(desiredState nmstatev1alpha1.State) ([]string, error) {
+	interfaces, err := getMTUInterfaces(desiredState)
+	if err != nil {
+		return nil, err
+	}
+	return mtuLinkFlaps(interfaces, readMTUs(interfaces)), nil
+}
@@ -0,0 +1,100 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("MTU changes order", func() {
+	desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: bond0.102
+  type: vlan
+  state: up
+  mtu: 9000
+  vlan:
+    base-iface: bond0
+    id: 102
+- name: bond0
+  type: bond
+  state: up
+  mtu: 9000
+  link-aggregation:
+    mode: active-backup
+    slaves:
+    - eth1
+    - eth2
+- name: eth3
+  type: ethernet
+  state: up
+`)
+
+	stepNames := func(steps []mtuInterface) []string {
+		names := []string{}
+		for _, step := range steps {
+			names = append(names, step.name)
+		}
+		return names
+	}
+
+	It("should raise the bases MTU before the VLANs on top of them", func() {
+		interfaces, err := getMTUInterfaces(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		raising := map[string]int{"bond0.102": 1500, "bond0": 1500, "eth1": 1500, "eth2": 1500, "eth3": 1500}
+		Expect(mtuOrder(interfaces, raising)).To(Equal([]string{"eth3", "bond0", "bond0.102"}))
+		Expect(stepNames(mtuSteps(interfaces, raising))).To(Equal([]string{"bond0", "bond0.102"}))
+	})
+
+	It("should lower the VLANs MTU before their bases", func() {
+		lowering := map[string]int{"bond0.102": 9200, "bond0": 9200}
+		interfaces, err := getMTUInterfaces(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mtuOrder(interfaces, lowering)).To(Equal([]string{"bond0.102", "bond0", "eth3"}))
+	})
+
+	It("should not change the MTU of desired states keeping it", func() {
+		interfaces, err := getMTUInterfaces(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mtuSteps(interfaces, map[string]int{"bond0.102": 9000, "bond0": 9000})).To(BeEmpty())
+	})
+
+	It("should not change the MTU of interfaces not created yet", func() {
+		interfaces, err := getMTUInterfaces(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mtuOrder(interfaces, map[string]int{})).To(Equal([]string{"bond0.102", "bond0", "eth3"}))
+		Expect(mtuSteps(interfaces, map[string]int{})).To(BeEmpty())
+	})
+
+	It("should report the links the MTU changes cannot avoid bouncing", func() {
+		interfaces, err := getMTUInterfaces(nmstatev1alpha1.NewState(`interfaces:
+- name: bond0
+  type: bond
+  state: up
+  mtu: 9000
+  link-aggregation:
+    mode: active-backup
+    slaves:
+    - eth1
+    - eth2
+- name: bond0.102
+  type: vlan
+  state: up
+  mtu: 9000
+  vlan:
+    base-iface: bond0
+    id: 102
+- name: eth3
+  type: ethernet
+  state: up
+  mtu: 1400
+- name: eth4
+  type: ethernet
+  state: up
+  mtu: 1500
+`))
+		Expect(err).ToNot(HaveOccurred())
+		currentMTUs := map[string]int{"bond0": 1500, "eth1": 1500, "eth2": 9000, "bond0.102": 1500, "eth3": 1500, "eth4": 1500}
+		Expect(mtuLinkFlaps(interfaces, currentMTUs)).To(Equal([]string{"eth1", "eth3"}))
+	})
+})
@@ -0,0 +1,257 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const neighborsKey = "neighbors"
+
+// staticNeighbor is a desired state permanent neighbor entry, an ARP entry
+// for IPv4 addresses and an NDP one for IPv6 addresses
+type staticNeighbor struct {
+	ip               string
+	linkLayerAddress string
+	iface            string
+	absent           bool
+}
+
+func (n staticNeighbor) key() string {
+	return n.iface + "/" + n.ip
+}
+
+// getNeighbors returns the desired state static neighbors, nmstate does
+// not support them, they are configured with iproute
+func getNeighbors(desiredState nmstatev1alpha1.State) ([]staticNeighbor, error) {
+	neighbors := []staticNeighbor{}
+	if len(desiredState.Raw) == 0 {
+		return neighbors, nil
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return neighbors, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, neighbor := range gjson.ParseBytes(desiredStateJSON).Get(neighborsKey + ".config").Array() {
+		staticNeighbor := staticNeighbor{
+			ip:               neighbor.Get("ip").String(),
+			linkLayerAddress: neighbor.Get("link-layer-address").String(),
+			iface:            neighbor.Get("interface").String(),
+			absent:           neighbor.Get("state").String() == "absent",
+		}
+		if net.ParseIP(staticNeighbor.ip) == nil {
+			return neighbors, fmt.Errorf("invalid neighbor ip %q", staticNeighbor.ip)
+		}
+		if staticNeighbor.iface == "" {
+			return neighbors, fmt.Errorf("neighbor %s has no interface", staticNeighbor.ip)
+		}
+		if !staticNeighbor.absent {
+			_, err := net.ParseMAC(staticNeighbor.linkLayerAddress)
+			if err != nil {
+				return neighbors, fmt.Errorf("invalid link layer address %q at neighbor %s: %v", staticNeighbor.linkLayerAddress, staticNeighbor.ip, err)
+			}
+		}
+		neighbors = append(neighbors, staticNeighbor)
+	}
+	return neighbors, nil
+}
+
+// stripNeighbors removes the static neighbors from the desired state
+func stripNeighbors(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	if _, hasNeighbors := state[neighborsKey]; !hasNeighbors {
+		return desiredState, nil
+	}
+	delete(state, neighborsKey)
+
+	strippedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: strippedState}, nil
+}
+
+// applyNeighbors replaces the present neighbors with permanent entries and
+// deletes the absent ones, the ones already missing are fine
+func applyNeighbors(neighbors []staticNeighbor) (string, error) {
+	output := ""
+	for _, neighbor := range neighbors {
+		if neighbor.absent {
+			ipOutput, err := ip("neigh", "del", neighbor.ip, "dev", neighbor.iface)
+			output += fmt.Sprintf("neighbor %s dev %s del output: %s\n", neighbor.ip, neighbor.iface, ipOutput)
+			if err != nil && !strings.Contains(err.Error(), "No such file or directory") {
+				return output, err
+			}
+			continue
+		}
+		ipOutput, err := ip("neigh", "replace", neighbor.ip, "lladdr", neighbor.linkLayerAddress, "dev", neighbor.iface, "nud", "permanent")
+		output += fmt.Sprintf("neighbor %s lladdr %s dev %s replace output: %s\n", neighbor.ip, neighbor.linkLayerAddress, neighbor.iface, ipOutput)
+		if err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+// readNeighbors returns the neighbors restoring the desired ones to the
+// node entries, the permanent entries are replaced back with their link
+// layer address and the rest are deleted
+func readNeighbors(neighbors []staticNeighbor) []staticNeighbor {
+	if len(neighbors) == 0 {
+		return []staticNeighbor{}
+	}
+	currentNeighbors, err := showStaticNeighbors(glob.MustCompile(""))
+	if err != nil {
+		log.Info(fmt.Sprintf("failed reading static neighbors: %v", err))
+		return []staticNeighbor{}
+	}
+	return previousNeighbors(neighbors, currentNeighbors)
+}
+
+func previousNeighbors(neighbors []staticNeighbor, currentNeighbors []nmstatev1alpha1.StaticNeighbor) []staticNeighbor {
+	current := map[string]nmstatev1alpha1.StaticNeighbor{}
+	for _, neighbor := range currentNeighbors {
+		current[staticNeighbor{ip: neighbor.IP, iface: neighbor.Interface}.key()] = neighbor
+	}
+	previous := []staticNeighbor{}
+	for _, neighbor := range neighbors {
+		currentNeighbor, exists := current[neighbor.key()]
+		if !exists {
+			previous = append(previous, staticNeighbor{ip: neighbor.ip, iface: neighbor.iface, absent: true})
+			continue
+		}
+		previous = append(previous, staticNeighbor{ip: neighbor.ip, linkLayerAddress: currentNeighbor.LinkLayerAddress, iface: neighbor.iface})
+	}
+	return previous
+}
+
+// restoreNeighbors sets back the neighbors read before applying the desired
+// state, they are not part of the nmstate checkpoint
+func restoreNeighbors(previousNeighbors []staticNeighbor) string {
+	output, err := applyNeighbors(previousNeighbors)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed restoring static neighbors: %v", err))
+	}
+	return output
+}
+
+// RemoveDroppedNeighbors sets as absent at the desired state the static
+// neighbors present at the previously applied one that are not at the
+// desired state anymore, so dropping them from the policy deletes them
+// from the node.
+func RemoveDroppedNeighbors(previousState nmstatev1alpha1.State, desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	previousNeighbors, err := getNeighbors(previousState)
+	if err != nil || len(previousNeighbors) == 0 {
+		return desiredState, err
+	}
+	neighbors, err := getNeighbors(desiredState)
+	if err != nil {
+		return desiredState, err
+	}
+
+	desiredNeighbors := map[string]bool{}
+	for _, neighbor := range neighbors {
+		desiredNeighbors[neighbor.key()] = true
+	}
+
+	droppedNeighbors := []staticNeighbor{}
+	for _, neighbor := range previousNeighbors {
+		if !neighbor.absent && !desiredNeighbors[neighbor.key()] {
+			droppedNeighbors = append(droppedNeighbors, neighbor)
+		}
+	}
+	if len(droppedNeighbors) == 0 {
+		return desiredState, nil
+	}
+	sort.Slice(droppedNeighbors, func(i, j int) bool {
+		return droppedNeighbors[i].key() < droppedNeighbors[j].key()
+	})
+
+	var state map[string]interface{}
+	err = yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	stateNeighbors, _ := state[neighborsKey].(map[string]interface{})
+	if stateNeighbors == nil {
+		stateNeighbors = map[string]interface{}{}
+	}
+	config, _ := stateNeighbors["config"].([]interface{})
+	for _, neighbor := range droppedNeighbors {
+		config = append(config, map[string]interface{}{
+			"ip":        neighbor.ip,
+			"interface": neighbor.iface,
+			"state":     "absent",
+		})
+	}
+	stateNeighbors["config"] = config
+	state[neighborsKey] = stateNeighbors
+
+	removedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: removedState}, nil
+}
+
+type ipNeighbor struct {
+	Destination string `json:"dst"`
+	Device      string `json:"dev"`
+	Lladdr      string `json:"lladdr"`
+}
+
+// parseStaticNeighbors converts the neighbors from
+// "ip -j neigh show nud permanent"
+func parseStaticNeighbors(output string, interfacesFilterGlob glob.Glob) ([]nmstatev1alpha1.StaticNeighbor, error) {
+	neighbors := []ipNeighbor{}
+	err := json.Unmarshal([]byte(output), &neighbors)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing ip neighbors: %v", err)
+	}
+
+	staticNeighbors := []nmstatev1alpha1.StaticNeighbor{}
+	for _, neighbor := range neighbors {
+		if !interfacesFilterGlob.Match("") && interfacesFilterGlob.Match(neighbor.Device) {
+			continue
+		}
+		staticNeighbors = append(staticNeighbors, nmstatev1alpha1.StaticNeighbor{
+			IP:               neighbor.Destination,
+			LinkLayerAddress: neighbor.Lladdr,
+			Interface:        neighbor.Device,
+		})
+	}
+	return staticNeighbors, nil
+}
+
+func showStaticNeighbors(interfacesFilterGlob glob.Glob) ([]nmstatev1alpha1.StaticNeighbor, error) {
+	staticNeighbors := []nmstatev1alpha1.StaticNeighbor{}
+	for _, family := range []string{"-4", "-6"} {
+		output, err := ip(family, "-j", "neigh", "show", "nud", "permanent")
+		if err != nil {
+			return nil, err
+		}
+		familyNeighbors, err := parseStaticNeighbors(output, interfacesFilterGlob)
+		if err != nil {
+			return nil, err
+		}
+		staticNeighbors = append(staticNeighbors, familyNeighbors...)
+	}
+	return staticNeighbors, nil
+}
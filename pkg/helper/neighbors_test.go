@@ -0,0 +1,119 @@
+package helper
+
+import (
+	"github.com/gobwas/glob"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Static neighbors", func() {
+	const bridgeNeighbor = `  - ip: 192.0.2.1
+    link-layer-address: 52:54:00:12:b6:a1
+    interface: br1
+`
+	const ipv6Neighbor = `  - ip: 2001:db8::1
+    link-layer-address: 52:54:00:12:b6:a2
+    interface: br1
+`
+	const br1 = `interfaces:
+- name: br1
+  type: linux-bridge
+  state: up
+`
+
+	It("should take a static ARP entry on a bridge and strip it from the nmstate desired state", func() {
+		desiredState := nmstatev1alpha1.NewState(br1 + "neighbors:\n  config:\n" + bridgeNeighbor)
+
+		neighbors, err := getNeighbors(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(neighbors).To(Equal([]staticNeighbor{
+			{ip: "192.0.2.1", linkLayerAddress: "52:54:00:12:b6:a1", iface: "br1"},
+		}))
+
+		strippedState, err := stripNeighbors(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML(br1))
+	})
+
+	It("should take absent entries without link layer address", func() {
+		neighbors, err := getNeighbors(nmstatev1alpha1.NewState("neighbors:\n  config:\n  - ip: 2001:db8::1\n    interface: br1\n    state: absent\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(neighbors).To(Equal([]staticNeighbor{
+			{ip: "2001:db8::1", iface: "br1", absent: true},
+		}))
+	})
+
+	DescribeTable("with invalid entries",
+		func(neighbor string) {
+			_, err := getNeighbors(nmstatev1alpha1.NewState("neighbors:\n  config:\n" + neighbor))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("invalid ip", "  - ip: 192.0.2\n    link-layer-address: 52:54:00:12:b6:a1\n    interface: br1\n"),
+		Entry("without interface", "  - ip: 192.0.2.1\n    link-layer-address: 52:54:00:12:b6:a1\n"),
+		Entry("without link layer address", "  - ip: 192.0.2.1\n    interface: br1\n"),
+		Entry("invalid link layer address", "  - ip: 192.0.2.1\n    link-layer-address: 52:54:00\n    interface: br1\n"),
+	)
+
+	DescribeTable("dropped from the desired state",
+		func(previousState string, desiredState string, expectedState string) {
+			removedState, err := RemoveDroppedNeighbors(nmstatev1alpha1.NewState(previousState), nmstatev1alpha1.NewState(desiredState))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removedState.String()).To(MatchYAML(expectedState))
+		},
+		Entry("without previous desired state, should keep it",
+			"",
+			"neighbors:\n  config:\n"+bridgeNeighbor,
+			"neighbors:\n  config:\n"+bridgeNeighbor,
+		),
+		Entry("still configuring the neighbor, should keep it",
+			"neighbors:\n  config:\n"+bridgeNeighbor,
+			"neighbors:\n  config:\n"+bridgeNeighbor+ipv6Neighbor,
+			"neighbors:\n  config:\n"+bridgeNeighbor+ipv6Neighbor,
+		),
+		Entry("without the neighbor, should remove it",
+			"neighbors:\n  config:\n"+bridgeNeighbor+ipv6Neighbor,
+			"neighbors:\n  config:\n"+ipv6Neighbor,
+			"neighbors:\n  config:\n"+ipv6Neighbor+"  - ip: 192.0.2.1\n    interface: br1\n    state: absent\n",
+		),
+		Entry("without neighbors, should remove them",
+			"neighbors:\n  config:\n"+bridgeNeighbor,
+			br1,
+			br1+"neighbors:\n  config:\n  - ip: 192.0.2.1\n    interface: br1\n    state: absent\n",
+		),
+		Entry("with the neighbor already absent, should not remove it again",
+			"neighbors:\n  config:\n  - ip: 192.0.2.1\n    interface: br1\n    state: absent\n",
+			br1,
+			br1,
+		),
+	)
+
+	It("should report permanent entries skipping filtered interfaces", func() {
+		output := `[{"dst":"192.0.2.1","dev":"br1","lladdr":"52:54:00:12:b6:a1","state":["PERMANENT"]},
+{"dst":"10.244.0.5","dev":"veth1234","lladdr":"52:54:00:12:b6:a3","state":["PERMANENT"]}]`
+		staticNeighbors, err := parseStaticNeighbors(output, glob.MustCompile("veth*"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(staticNeighbors).To(Equal([]nmstatev1alpha1.StaticNeighbor{
+			{IP: "192.0.2.1", LinkLayerAddress: "52:54:00:12:b6:a1", Interface: "br1"},
+		}))
+	})
+
+	It("should restore the permanent entries and delete the rest", func() {
+		previous := previousNeighbors([]staticNeighbor{
+			{ip: "192.0.2.1", linkLayerAddress: "52:54:00:12:b6:a2", iface: "br1"},
+			{ip: "192.0.2.2", iface: "br1", absent: true},
+			{ip: "192.0.2.3", linkLayerAddress: "52:54:00:12:b6:a3", iface: "br1"},
+		}, []nmstatev1alpha1.StaticNeighbor{
+			{IP: "192.0.2.1", LinkLayerAddress: "52:54:00:12:b6:a1", Interface: "br1"},
+			{IP: "192.0.2.2", LinkLayerAddress: "52:54:00:12:b6:a4", Interface: "br1"},
+			{IP: "192.0.2.3", LinkLayerAddress: "52:54:00:12:b6:a5", Interface: "br2"},
+		})
+		Expect(previous).To(Equal([]staticNeighbor{
+			{ip: "192.0.2.1", linkLayerAddress: "52:54:00:12:b6:a1", iface: "br1"},
+			{ip: "192.0.2.2", linkLayerAddress: "52:54:00:12:b6:a4", iface: "br1"},
+			{ip: "192.0.2.3", iface: "br1", absent: true},
+		}))
+	})
+})
@@ -0,0 +1,197 @@
+package helper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/tidwall/gjson"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const nmcliCommand = "nmcli"
+
+func nmcli(arguments ...string) (string, error) {
+	cmd := exec.Command(nmcliCommand, arguments...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := runCommand(cmd); err != nil {
+		return "", fmt.Errorf("failed to execute %s %s: '%v', '%s', '%s'", nmcliCommand, strings.Join(arguments, " "), err, stdout.String(), stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// splitTerseFields splits a line of nmcli terse output, fields are
+// separated by ':' and literal ':' and '\' are escaped with '\'
+func splitTerseFields(line string) []string {
+	fields := []string{}
+	var field strings.Builder
+	escaped := false
+	for _, c := range line {
+		switch {
+		case escaped:
+			field.WriteRune(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == ':':
+			fields = append(fields, field.String())
+			field.Reset()
+		default:
+			field.WriteRune(c)
+		}
+	}
+	return append(fields, field.String())
+}
+
+func parseConnections(output string) []nmstatev1alpha1.NetworkManagerConnection {
+	connections := []nmstatev1alpha1.NetworkManagerConnection{}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := splitTerseFields(line)
+		if len(fields) != 4 {
+			log.Info(fmt.Sprintf("ignoring unexpected nmcli connection line: %s", line))
+			continue
+		}
+		connections = append(connections, nmstatev1alpha1.NetworkManagerConnection{
+			Name:   fields[0],
+			UUID:   fields[1],
+			Type:   fields[2],
+			Device: fields[3],
+		})
+	}
+	return connections
+}
+
+func showConnections() ([]nmstatev1alpha1.NetworkManagerConnection, error) {
+	output, err := nmcli("-t", "-f", "NAME,UUID,TYPE,DEVICE", "connection", "show")
+	if err != nil {
+		return nil, err
+	}
+	return parseConnections(output), nil
+}
+
+// enactedInterfaces returns the name of the interfaces present at the
+// desired state of the node's enactments
+func enactedInterfaces(cli client.Client, nodeName string) (map[string]bool, error) {
+	enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+	err := cli.List(context.TODO(), &enactments, client.MatchingLabels{nmstatev1alpha1.EnactmentNodeLabel: nodeName})
+	if err != nil {
+		return nil, fmt.Errorf("failed listing node enactments: %v", err)
+	}
+
+	interfaces := map[string]bool{}
+	for _, enactment := range enactments.Items {
+		desiredState, err := yaml.YAMLToJSON(enactment.Status.DesiredState.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed converting enactment %s desired state to JSON: %v", enactment.Name, err)
+		}
+		for _, name := range gjson.GetBytes(desiredState, "interfaces.#.name").Array() {
+			interfaces[name.String()] = true
+		}
+	}
+	return interfaces, nil
+}
+
+// filterOutConnections removes the connections of devices not matching
+// the interfaces filter and marks as externally managed the ones not
+// configured by the enacted interfaces
+func filterOutConnections(connections []nmstatev1alpha1.NetworkManagerConnection, interfacesFilterGlob glob.Glob, enactedInterfaces map[string]bool) []nmstatev1alpha1.NetworkManagerConnection {
+	filteredConnections := []nmstatev1alpha1.NetworkManagerConnection{}
+	for _, connection := range connections {
+		if connection.Device != "" && interfacesFilterGlob.Match(connection.Device) {
+			continue
+		}
+		// Interfaces configured by nmstate have connections named after the interface
+		connection.ExternallyManaged = !enactedInterfaces[connection.Name] && (connection.Device == "" || !enactedInterfaces[connection.Device])
+		filteredConnections = append(filteredConnections, connection)
+	}
+	return filteredConnections
+}
+
+const unmanagedDeviceState = "unmanaged"
+
+func parseDevices(output string) []nmstatev1alpha1.NetworkManagerDevice {
+	devices := []nmstatev1alpha1.NetworkManagerDevice{}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := splitTerseFields(line)
+		if len(fields) != 3 {
+			log.Info(fmt.Sprintf("ignoring unexpected nmcli device line: %s", line))
+			continue
+		}
+		devices = append(devices, nmstatev1alpha1.NetworkManagerDevice{
+			Name:    fields[0],
+			Type:    fields[1],
+			State:   fields[2],
+			Managed: fields[2] != unmanagedDeviceState,
+		})
+	}
+	return devices
+}
+
+func showDevices() ([]nmstatev1alpha1.NetworkManagerDevice, error) {
+	output, err := nmcli("-t", "-f", "DEVICE,TYPE,STATE", "device", "status")
+	if err != nil {
+		return nil, err
+	}
+	return parseDevices(output), nil
+}
+
+// filterOutDevices removes the devices matching the interfaces filter
+func filterOutDevices(devices []nmstatev1alpha1.NetworkManagerDevice, interfacesFilterGlob glob.Glob) []nmstatev1alpha1.NetworkManagerDevice {
+	filteredDevices := []nmstatev1alpha1.NetworkManagerDevice{}
+	for _, device := range devices {
+		if !interfacesFilterGlob.Match(device.Name) {
+			filteredDevices = append(filteredDevices, device)
+		}
+	}
+	return filteredDevices
+}
+
+// unmanagedInterfaces returns the desired state interfaces that are
+// devices not managed by NetworkManager
+func unmanagedInterfaces(desiredState nmstatev1alpha1.State, devices []nmstatev1alpha1.NetworkManagerDevice) ([]string, error) {
+	unmanaged := []string{}
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return unmanaged, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	unmanagedDevices := map[string]bool{}
+	for _, device := range devices {
+		if !device.Managed {
+			unmanagedDevices[device.Name] = true
+		}
+	}
+	for _, name := range gjson.GetBytes(desiredStateJSON, "interfaces.#.name").Array() {
+		if unmanagedDevices[name.String()] {
+			unmanaged = append(unmanaged, name.String())
+		}
+	}
+	return unmanaged, nil
+}
+
+// UnmanagedInterfaces returns the desired state interfaces that
+// NetworkManager would not configure since it does not manage them
+func UnmanagedInterfaces(desiredState nmstatev1alpha1.State) ([]string, error) {
+	if len(desiredState.Raw) == 0 {
+		return []string{}, nil
+	}
+	devices, err := showDevices()
+	if err != nil {
+		return nil, err
+	}
+	return unmanagedInterfaces(desiredState, devices)
+}
@@ -0,0 +1,94 @@
+package helper
+
+import (
+	"github.com/gobwas/glob"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NetworkManager connections", func() {
+	type parseCase struct {
+		output   string
+		expected []string
+	}
+	DescribeTable("splitting nmcli terse fields",
+		func(c parseCase) {
+			Expect(splitTerseFields(c.output)).To(Equal(c.expected))
+		},
+		Entry("plain fields", parseCase{
+			output:   "eth1:d94dcb4f-8d5f-4b0c-a3ba-8d1b9c6e3e34:802-3-ethernet:eth1",
+			expected: []string{"eth1", "d94dcb4f-8d5f-4b0c-a3ba-8d1b9c6e3e34", "802-3-ethernet", "eth1"},
+		}),
+		Entry("escaped colon and backslash", parseCase{
+			output:   `my\:conn\\1:d94dcb4f:802-3-ethernet:`,
+			expected: []string{`my:conn\1`, "d94dcb4f", "802-3-ethernet", ""},
+		}),
+	)
+
+	It("should parse the connections ignoring malformed lines", func() {
+		output := `eth1:d94dcb4f:802-3-ethernet:eth1
+Wired connection 1:1d3c1c0a:802-3-ethernet:
+malformed
+`
+		Expect(parseConnections(output)).To(Equal([]nmstatev1alpha1.NetworkManagerConnection{
+			{Name: "eth1", UUID: "d94dcb4f", Type: "802-3-ethernet", Device: "eth1"},
+			{Name: "Wired connection 1", UUID: "1d3c1c0a", Type: "802-3-ethernet"},
+		}))
+	})
+
+	It("should filter out and mark externally managed connections", func() {
+		connections := []nmstatev1alpha1.NetworkManagerConnection{
+			{Name: "eth1", UUID: "1", Device: "eth1"},
+			{Name: "br1", UUID: "2"},
+			{Name: "Wired connection 1", UUID: "3", Device: "eth2"},
+			{Name: "legacy", UUID: "4", Device: "eth3"},
+			{Name: "veth", UUID: "5", Device: "vethab6030bd"},
+		}
+		enacted := map[string]bool{"eth1": true, "br1": true, "eth2": true}
+		Expect(filterOutConnections(connections, glob.MustCompile("veth*"), enacted)).To(Equal([]nmstatev1alpha1.NetworkManagerConnection{
+			{Name: "eth1", UUID: "1", Device: "eth1"},
+			{Name: "br1", UUID: "2"},
+			{Name: "Wired connection 1", UUID: "3", Device: "eth2"},
+			{Name: "legacy", UUID: "4", Device: "eth3", ExternallyManaged: true},
+		}))
+	})
+})
+
+var _ = Describe("NetworkManager devices", func() {
+	devices := []nmstatev1alpha1.NetworkManagerDevice{
+		{Name: "eth0", Type: "ethernet", State: "connected", Managed: true},
+		{Name: "eth1", Type: "ethernet", State: "unmanaged"},
+		{Name: "vethab6030bd", Type: "ethernet", State: "unmanaged"},
+	}
+
+	It("should parse the devices ignoring malformed lines", func() {
+		output := `eth0:ethernet:connected
+eth1:ethernet:unmanaged
+vethab6030bd:ethernet:unmanaged
+malformed
+`
+		Expect(parseDevices(output)).To(Equal(devices))
+	})
+
+	It("should filter out devices", func() {
+		Expect(filterOutDevices(devices, glob.MustCompile("veth*"))).To(Equal(devices[:2]))
+	})
+
+	It("should return the desired state unmanaged interfaces", func() {
+		desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth0
+  type: ethernet
+  state: up
+- name: eth1
+  type: ethernet
+  state: up
+- name: br1
+  type: linux-bridge
+  state: up
+`)
+		Expect(unmanagedInterfaces(desiredState, devices)).To(Equal([]string{"eth1"}))
+	})
+})
@@ -0,0 +1,66 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// Nmstatectl runs the nmstate commands used to report and apply the node
+// network state, the desired state is set without committing it, so it is
+// rolled back if it is neither committed nor rolled back before the
+// timeout. SetWithoutCheckpoint commits the desired state right away, it's
+// only used when nmstate fails taking the checkpoint and the unsafe
+// override is set. The sets are aborted if they do not return before the
+// apply timeout, they are not bounded if it's 0.
+type Nmstatectl interface {
+	Show() (string, error)
+	Set(desiredState string, timeout time.Duration, applyTimeout time.Duration) (string, error)
+	SetWithoutCheckpoint(desiredState string, applyTimeout time.Duration) (string, error)
+	Commit() (string, error)
+	Rollback() (string, error)
+}
+
+// nmstatectlCommand runs the nmstatectl binary
+type nmstatectlCommand struct{}
+
+var (
+	nmstatectl Nmstatectl = nmstatectlCommand{}
+)
+
+// SetNmstatectl replaces the Nmstatectl used by the helpers, for example with
+// a fake one at unit tests, and returns the previous one to restore it
+func SetNmstatectl(n Nmstatectl) Nmstatectl {
+	previous := nmstatectl
+	nmstatectl = n
+	return previous
+}
+
+func (nmstatectlCommand) Show() (string, error) {
+	cmd := exec.Command(nmstateCommand, "show")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := runCommand(cmd); err != nil {
+		return "", fmt.Errorf("failed to execute nmstatectl show: '%v', '%s', '%s'", err, stdout.String(), stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+func (nmstatectlCommand) Set(desiredState string, timeout time.Duration, applyTimeout time.Duration) (string, error) {
+	return runCommandWithTimeout(applyTimeout, nmstateCommand, []string{"set", "--no-commit", "--timeout", strconv.Itoa(int(timeout.Seconds()))}, desiredState)
+}
+
+func (nmstatectlCommand) SetWithoutCheckpoint(desiredState string, applyTimeout time.Duration) (string, error) {
+	return runCommandWithTimeout(applyTimeout, nmstateCommand, []string{"set"}, desiredState)
+}
+
+func (nmstatectlCommand) Commit() (string, error) {
+	return runNmstatectl([]string{"commit"}, "")
+}
+
+func (nmstatectlCommand) Rollback() (string, error) {
+	return runNmstatectl([]string{"rollback"}, "")
+}
@@ -0,0 +1,233 @@
+package helper
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/fake"
+)
+
+var _ = Describe("ApplyDesiredState", func() {
+	var (
+		fakeNmstatectl     *fake.Nmstatectl
+		previousNmstatectl Nmstatectl
+	)
+
+	const currentState = `interfaces:
+- name: eth1
+  type: ethernet
+  state: down
+- name: vxlan10
+  type: vxlan
+  state: up
+  vxlan:
+    base-iface: eth1
+    id: 10
+`
+	// Without interfaces, nothing but nmstatectl is run to apply it
+	const desiredState = `dns-resolver:
+  config:
+    server:
+    - 192.0.2.53
+`
+
+	BeforeEach(func() {
+		fakeNmstatectl = fake.NewNmstatectl(currentState)
+		previousNmstatectl = SetNmstatectl(fakeNmstatectl)
+	})
+
+	AfterEach(func() {
+		SetNmstatectl(previousNmstatectl)
+	})
+
+	It("should ignore an empty desired state", func() {
+		_, err := ApplyDesiredState(nmstatev1alpha1.State{}, nil, 0, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fakeNmstatectl.Commands).To(BeEmpty())
+	})
+
+	It("should not set the desired state if set fails", func() {
+		fakeNmstatectl.SetErr = fmt.Errorf("set failed")
+		_, err := ApplyDesiredState(nmstatev1alpha1.NewState(desiredState), nil, 0, nil)
+		Expect(err).To(MatchError("set failed"))
+		Expect(fakeNmstatectl.Commands).To(Equal([]string{"set", "set", "set"}))
+		Expect(fakeNmstatectl.CurrentState).To(Equal(currentState))
+	})
+
+	It("should report nmstate busy without retrying nor rolling back", func() {
+		fakeNmstatectl.SetErrors = []error{fmt.Errorf("failed to execute nmstatectl set: Another checkpoint exists")}
+		_, err := ApplyDesiredState(nmstatev1alpha1.NewState(desiredState), nil, 0, nil)
+		Expect(IsBusy(err)).To(BeTrue())
+		Expect(fakeNmstatectl.Commands).To(Equal([]string{"set"}))
+		Expect(fakeNmstatectl.CurrentState).To(Equal(currentState))
+
+		By("applying it once nmstate is not busy anymore")
+		_, err = ApplyDesiredState(nmstatev1alpha1.NewState(desiredState), nil, 0, nil)
+		Expect(IsBusy(err)).To(BeFalse())
+		Expect(fakeNmstatectl.Commands).To(Equal([]string{"set", "set", "show", "rollback"}))
+	})
+
+	It("should roll back the checkpoint without retrying if the apply times out", func() {
+		fakeNmstatectl.SetErrors = []error{&applyTimeoutError{timeout: time.Minute}}
+		_, err := ApplyDesiredState(nmstatev1alpha1.NewState(desiredState), nil, time.Minute, nil)
+		Expect(IsApplyTimeout(err)).To(BeTrue())
+		Expect(err).To(MatchError("nmstate did not finish applying desired state in 1m0s, aborted"))
+		Expect(fakeNmstatectl.Commands).To(Equal([]string{"set", "rollback"}))
+	})
+
+	It("should not report other set failures as busy", func() {
+		fakeNmstatectl.SetErr = fmt.Errorf("set failed")
+		_, err := ApplyDesiredState(nmstatev1alpha1.NewState(desiredState), nil, 0, nil)
+		Expect(IsBusy(err)).To(BeFalse())
+	})
+
+	It("should not report the device busy failures as nmstate busy", func() {
+		fakeNmstatectl.SetErr = fmt.Errorf("failed to execute nmstatectl set: NmstateLibnmError: Device or resource busy")
+		_, err := ApplyDesiredState(nmstatev1alpha1.NewState(desiredState), nil, 0, nil)
+		Expect(IsBusy(err)).To(BeFalse())
+		Expect(fakeNmstatectl.Commands).To(Equal([]string{"set", "set", "set"}))
+	})
+
+	Context("when nmstate fails creating the checkpoint", func() {
+		BeforeEach(func() {
+			fakeNmstatectl.SetErrors = []error{fmt.Errorf("failed to execute nmstatectl set: NmstateLibnmError: Checkpoint create failed")}
+		})
+
+		AfterEach(func() {
+			rollbackCheckpoint = rollbackCheckpointEnabled
+		})
+
+		It("should refuse applying the desired state without it", func() {
+			_, err := ApplyDesiredState(nmstatev1alpha1.NewState(desiredState), nil, 0, nil)
+			Expect(IsCheckpointFailed(err)).To(BeTrue())
+			Expect(IsBusy(err)).To(BeFalse())
+			Expect(fakeNmstatectl.Commands).To(Equal([]string{"set"}))
+			Expect(fakeNmstatectl.CurrentState).To(Equal(currentState))
+		})
+
+		It("should apply the desired state without it with the unsafe override", func() {
+			rollbackCheckpoint = rollbackCheckpointUnsafeFallback
+			_, err := ApplyDesiredState(nmstatev1alpha1.NewState(desiredState), nil, 0, nil)
+			Expect(IsCheckpointFailed(err)).To(BeFalse())
+			Expect(fakeNmstatectl.Commands).To(Equal([]string{"set", "set-without-checkpoint", "show"}))
+			Expect(fakeNmstatectl.CurrentState).To(Equal(desiredState))
+		})
+	})
+
+	Context("when the rollback checkpoint is disabled", func() {
+		BeforeEach(func() {
+			rollbackCheckpoint = rollbackCheckpointUnsafeDisabled
+		})
+
+		AfterEach(func() {
+			rollbackCheckpoint = rollbackCheckpointEnabled
+		})
+
+		It("should apply the desired state without taking the checkpoint nor rolling it back", func() {
+			_, err := ApplyDesiredState(nmstatev1alpha1.NewState(desiredState), nil, 0, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not rolled back"))
+			Expect(fakeNmstatectl.Commands).To(Equal([]string{"set-without-checkpoint", "show"}))
+			Expect(fakeNmstatectl.CurrentState).To(Equal(desiredState))
+		})
+	})
+
+	DescribeTable("rollback checkpoint setting",
+		func(value string, expectedSetting string, expectedError string) {
+			setting, err := parseRollbackCheckpoint(value)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+			Expect(setting).To(Equal(expectedSetting))
+		},
+		Entry("unset", "", "enabled", ""),
+		Entry("enabled", "enabled", "enabled", ""),
+		Entry("unsafe fallback", "unsafe-fallback", "unsafe-fallback", ""),
+		Entry("unsafe disabled", "unsafe-disabled", "unsafe-disabled", ""),
+		Entry("with a typo", "unsafe-disable", "enabled", `invalid ROLLBACK_CHECKPOINT "unsafe-disable", it has to be enabled, unsafe-fallback or unsafe-disabled`),
+		Entry("with a boolean", "false", "enabled", `invalid ROLLBACK_CHECKPOINT "false", it has to be enabled, unsafe-fallback or unsafe-disabled`),
+	)
+
+	It("should rollback the desired state if the default gateway is lost", func() {
+		_, err := ApplyDesiredState(nmstatev1alpha1.NewState(desiredState), nil, 0, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("rollback cause: Impossible to retrieve default gw"))
+		Expect(fakeNmstatectl.Commands).To(Equal([]string{"set", "show", "rollback"}))
+		Expect(fakeNmstatectl.CurrentState).To(Equal(currentState))
+		Expect(fakeNmstatectl.Checkpoint).To(BeNil())
+	})
+
+	Context("when the desired state changes an MTU", func() {
+		var (
+			previousSetMTU func(string, int) (string, error)
+			mtus           []string
+			loMTU          int
+		)
+
+		BeforeEach(func() {
+			mtus = []string{}
+			previousSetMTU = setMTU
+			setMTU = func(name string, mtu int) (string, error) {
+				mtus = append(mtus, fmt.Sprintf("%s %d", name, mtu))
+				return "", nil
+			}
+			loMTU = readMTUs([]mtuInterface{{name: "lo"}})["lo"]
+			if loMTU == 0 {
+				Skip("the node has no loopback interface MTU")
+			}
+		})
+
+		AfterEach(func() {
+			setMTU = previousSetMTU
+		})
+
+		It("should restore the MTU if the desired state is rolled back", func() {
+			_, err := ApplyDesiredState(nmstatev1alpha1.NewState(fmt.Sprintf(`interfaces:
+- name: lo
+  type: unknown
+  state: up
+  mtu: %d
+`, loMTU-1)), nil, 0, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("rollback cause: Impossible to retrieve default gw"))
+			Expect(fakeNmstatectl.Commands).To(Equal([]string{"set", "show", "rollback"}))
+			Expect(mtus).To(Equal([]string{fmt.Sprintf("lo %d", loMTU-1), fmt.Sprintf("lo %d", loMTU)}))
+		})
+	})
+
+	It("should report the rollback error", func() {
+		fakeNmstatectl.RollbackErr = fmt.Errorf("rollback failed")
+		_, err := ApplyDesiredState(nmstatev1alpha1.NewState(desiredState), nil, 0, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HaveSuffix("rollback error: rollback failed"))
+		Expect(fakeNmstatectl.Checkpoint).ToNot(BeNil())
+	})
+
+	It("should not set the desired state with conflicting VXLAN ids", func() {
+		_, err := ApplyDesiredState(nmstatev1alpha1.NewState(`interfaces:
+- name: vxlan11
+  type: vxlan
+  state: up
+  vxlan:
+    base-iface: eth1
+    id: 10
+`), nil, 0, nil)
+		Expect(err).To(MatchError("VXLAN id 10 would be used by both vxlan10 and vxlan11 at the node"))
+		Expect(fakeNmstatectl.Commands).To(Equal([]string{"show"}))
+	})
+
+	It("should report the node state set", func() {
+		_, err := fakeNmstatectl.Set(desiredState, 0, 0)
+		Expect(err).ToNot(HaveOccurred())
+		state, err := CurrentState()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state).To(Equal(nmstatev1alpha1.NewState(desiredState)))
+	})
+})
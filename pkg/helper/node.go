@@ -1,11 +1,18 @@
 package helper
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const uptimeFile = "/proc/uptime"
+
 // Return true if the event name is the name of
 // the pods's node (reading the env var NODE_NAME)
 func EventIsForThisNode(meta v1.Object) bool {
@@ -14,3 +21,25 @@ func EventIsForThisNode(meta v1.Object) bool {
 	// Only reconcile is it's for this pod
 	return createdNodeName == podNodeName
 }
+
+// Uptime returns the time since the node booted, /proc/uptime is not
+// namespaced so it's the host uptime also from inside the pod
+func Uptime() (time.Duration, error) {
+	content, err := ioutil.ReadFile(uptimeFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed reading %s: %v", uptimeFile, err)
+	}
+	return parseUptime(string(content))
+}
+
+func parseUptime(content string) (time.Duration, error) {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty uptime")
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed parsing uptime '%s': %v", fields[0], err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,28 @@
+package helper
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Node uptime", func() {
+	DescribeTable("parsing /proc/uptime",
+		func(content string, expected time.Duration) {
+			uptime, err := parseUptime(content)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uptime).To(Equal(expected))
+		},
+		Entry("with fractional seconds", "350735.47 234388.90\n", 350735*time.Second+470*time.Millisecond),
+		Entry("just after boot", "7.00 12.01\n", 7*time.Second),
+	)
+
+	It("should fail on malformed content", func() {
+		_, err := parseUptime("")
+		Expect(err).To(HaveOccurred())
+		_, err = parseUptime("foo 1.0")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,417 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const ovsVsctlCommand = "ovs-vsctl"
+
+const (
+	ovsDBKey          = "ovs-db"
+	externalIDsKey    = "external_ids"
+	ovnKey            = "ovn"
+	bridgeMappingsKey = "bridge-mappings"
+
+	// Open vSwitch external id read by OVN to map its localnet networks to
+	// the node bridges
+	ovnBridgeMappingsExternalID = "ovn-bridge-mappings"
+
+	ovsBridgeInterfaceType = "ovs-bridge"
+)
+
+func ovsVsctl(arguments ...string) (string, error) {
+	cmd := exec.Command(ovsVsctlCommand, arguments...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := runCommand(cmd); err != nil {
+		return "", fmt.Errorf("failed to execute %s %v: '%v', '%s', '%s'", ovsVsctlCommand, arguments, err, stdout.String(), stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// ovsExternalIDs are the external ids of the Open vSwitch database, global
+// ones at the Open_vSwitch table and the ones of every bridge
+type ovsExternalIDs struct {
+	global  map[string]string
+	bridges map[string]map[string]string
+	// bridges the OVN bridge mappings point to
+	mappedBridges []string
+}
+
+func (ids ovsExternalIDs) empty() bool {
+	return len(ids.global) == 0 && len(ids.bridges) == 0
+}
+
+func stringMap(value interface{}) map[string]string {
+	values := map[string]string{}
+	valueMap, _ := value.(map[string]interface{})
+	for key, value := range valueMap {
+		values[key] = fmt.Sprint(value)
+	}
+	return values
+}
+
+// getOvsExternalIDs returns the Open vSwitch external ids of the desired
+// state, global ones are set at the ovs-db section, bridge ones at the
+// ovs-db section of the ovs-bridge interfaces and the ovn bridge-mappings
+// are translated to the ovn-bridge-mappings global external id
+func getOvsExternalIDs(desiredState nmstatev1alpha1.State) (ovsExternalIDs, error) {
+	ids := ovsExternalIDs{global: map[string]string{}, bridges: map[string]map[string]string{}}
+
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return ids, fmt.Errorf("error unmarshaling desired state: %v", err)
+	}
+
+	if ovsDB, hasOvsDB := state[ovsDBKey].(map[string]interface{}); hasOvsDB {
+		ids.global = stringMap(ovsDB[externalIDsKey])
+	}
+
+	if ovn, hasOvn := state[ovnKey].(map[string]interface{}); hasOvn {
+		mappings, _ := ovn[bridgeMappingsKey].([]interface{})
+		if _, found := ids.global[ovnBridgeMappingsExternalID]; found && len(mappings) > 0 {
+			return ids, fmt.Errorf("ovn bridge-mappings cannot be set together with the %s external id", ovnBridgeMappingsExternalID)
+		}
+		bridgeMappings := []string{}
+		for _, mapping := range mappings {
+			mappingMap := stringMap(mapping)
+			if mappingMap["localnet"] == "" || mappingMap["bridge"] == "" {
+				return ids, fmt.Errorf("ovn bridge mapping %v needs localnet and bridge", mapping)
+			}
+			bridgeMappings = append(bridgeMappings, mappingMap["localnet"]+":"+mappingMap["bridge"])
+			ids.mappedBridges = append(ids.mappedBridges, mappingMap["bridge"])
+		}
+		if len(bridgeMappings) > 0 {
+			ids.global[ovnBridgeMappingsExternalID] = strings.Join(bridgeMappings, ",")
+		}
+	}
+
+	interfaces, _ := state["interfaces"].([]interface{})
+	for _, iface := range interfaces {
+		ifaceMap, isMap := iface.(map[string]interface{})
+		if !isMap || ifaceMap["state"] == "absent" {
+			continue
+		}
+		ovsDB, hasOvsDB := ifaceMap[ovsDBKey].(map[string]interface{})
+		if !hasOvsDB {
+			continue
+		}
+		name, _ := ifaceMap["name"].(string)
+		if ifaceMap["type"] != ovsBridgeInterfaceType {
+			return ids, fmt.Errorf("interface %s is not an Open vSwitch bridge, cannot set its external ids", name)
+		}
+		ids.bridges[name] = stringMap(ovsDB[externalIDsKey])
+	}
+	return ids, nil
+}
+
+// stripOvsExternalIDs removes the Open vSwitch external ids and OVN bridge
+// mappings, not supported by nmstate, from the desired state
+func stripOvsExternalIDs(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	strippedState, err := stripInterfacesKeys(desiredState, ovsDBKey)
+	if err != nil {
+		return desiredState, err
+	}
+
+	var state map[string]interface{}
+	err = yaml.Unmarshal(strippedState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	_, hasOvsDB := state[ovsDBKey]
+	_, hasOvn := state[ovnKey]
+	if !hasOvsDB && !hasOvn {
+		return strippedState, nil
+	}
+	delete(state, ovsDBKey)
+	delete(state, ovnKey)
+
+	raw, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: raw}, nil
+}
+
+// checkOvs fails if the desired state sets Open vSwitch external ids and
+// Open vSwitch is not running at the node, so nothing is applied
+func checkOvs(ids ovsExternalIDs) error {
+	if ids.empty() {
+		return nil
+	}
+	if _, err := ovsVsctl("show"); err != nil {
+		return fmt.Errorf("Open vSwitch is not running at the node, cannot set its external ids: %v", err)
+	}
+	return nil
+}
+
+func sortedExternalIDs(ids map[string]string) []string {
+	keys := []string{}
+	for key := range ids {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// applyOvsExternalIDs sets the external ids at the Open vSwitch database,
+// empty values remove them. The database is not part of the nmstate
+// checkpoint, so they are restored to their previous values on rollback.
+func applyOvsExternalIDs(ids ovsExternalIDs) (string, error) {
+	output := ""
+	bridges := []string{}
+	for bridge := range ids.bridges {
+		bridges = append(bridges, bridge)
+	}
+	sort.Strings(bridges)
+	for _, bridge := range append(bridges, ids.mappedBridges...) {
+		if _, err := ovsVsctl("br-exists", bridge); err != nil {
+			return output, fmt.Errorf("bridge %s is not an Open vSwitch bridge at the node, Open vSwitch is not its backend", bridge)
+		}
+	}
+
+	for _, key := range sortedExternalIDs(ids.global) {
+		arguments := []string{"set", "Open_vSwitch", ".", fmt.Sprintf("%s:%s=%q", externalIDsKey, key, ids.global[key])}
+		if ids.global[key] == "" {
+			arguments = []string{"remove", "Open_vSwitch", ".", externalIDsKey, key}
+		}
+		ovsOutput, err := ovsVsctl(arguments...)
+		output += fmt.Sprintf("external id %s output: %s\n", key, ovsOutput)
+		if err != nil {
+			return output, err
+		}
+	}
+
+	for _, bridge := range bridges {
+		for _, key := range sortedExternalIDs(ids.bridges[bridge]) {
+			arguments := []string{"br-set-external-id", bridge, key}
+			if ids.bridges[bridge][key] != "" {
+				arguments = append(arguments, ids.bridges[bridge][key])
+			}
+			ovsOutput, err := ovsVsctl(arguments...)
+			output += fmt.Sprintf("bridge %s external id %s output: %s\n", bridge, key, ovsOutput)
+			if err != nil {
+				return output, err
+			}
+		}
+	}
+	return output, nil
+}
+
+// readOvsExternalIDs reads the current values of the external ids set by
+// the desired state, to restore them on rollback
+func readOvsExternalIDs(ids ovsExternalIDs) ovsExternalIDs {
+	previous := ovsExternalIDs{global: map[string]string{}, bridges: map[string]map[string]string{}}
+	if ids.empty() {
+		return previous
+	}
+	output, err := ovsVsctl("--format=json", "--columns=external_ids", "list", "Open_vSwitch")
+	if err != nil {
+		log.Info(fmt.Sprintf("failed reading Open vSwitch external ids: %v", err))
+		return previous
+	}
+	globalIDs, err := parseExternalIDs(output)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed reading Open vSwitch external ids: %v", err))
+		return previous
+	}
+	output, err = ovsVsctl("--format=json", "--columns=name,external_ids", "list", "Bridge")
+	if err != nil {
+		log.Info(fmt.Sprintf("failed reading Open vSwitch bridges external ids: %v", err))
+		return previous
+	}
+	bridgeIDs, err := parseExternalIDs(output)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed reading Open vSwitch bridges external ids: %v", err))
+		return previous
+	}
+	return previousOvsExternalIDs(ids, globalIDs[""], bridgeIDs)
+}
+
+// previousOvsExternalIDs returns the current values of the external ids set
+// by the desired state, empty for the ones not set so they are removed on
+// restore. The bridges created by the desired state are removed by the
+// nmstate rollback, so only the existing ones are restored.
+func previousOvsExternalIDs(ids ovsExternalIDs, globalIDs map[string]string, bridgeIDs map[string]map[string]string) ovsExternalIDs {
+	previous := ovsExternalIDs{global: map[string]string{}, bridges: map[string]map[string]string{}}
+	for key := range ids.global {
+		previous.global[key] = globalIDs[key]
+	}
+	for bridge, bridgeKeys := range ids.bridges {
+		currentIDs, exists := bridgeIDs[bridge]
+		if !exists {
+			continue
+		}
+		previous.bridges[bridge] = map[string]string{}
+		for key := range bridgeKeys {
+			previous.bridges[bridge][key] = currentIDs[key]
+		}
+	}
+	return previous
+}
+
+func restoreOvsExternalIDs(previousOvsExternalIDs ovsExternalIDs) string {
+	output, err := applyOvsExternalIDs(previousOvsExternalIDs)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed restoring Open vSwitch external ids: %v", err))
+	}
+	return output
+}
+
+// ovsdbTable is the JSON format of ovs-vsctl list
+type ovsdbTable struct {
+	Headings []string            `json:"headings"`
+	Data     [][]json.RawMessage `json:"data"`
+}
+
+// parseOvsdbMap parses an OVSDB map, encoded as ["map", [[key, value], ...]]
+func parseOvsdbMap(raw json.RawMessage) (map[string]string, error) {
+	var encoded []json.RawMessage
+	err := json.Unmarshal(raw, &encoded)
+	if err != nil || len(encoded) != 2 {
+		return nil, fmt.Errorf("failed parsing OVSDB map %s: %v", string(raw), err)
+	}
+	pairs := [][]string{}
+	err = json.Unmarshal(encoded[1], &pairs)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing OVSDB map %s: %v", string(raw), err)
+	}
+	values := map[string]string{}
+	for _, pair := range pairs {
+		if len(pair) == 2 {
+			values[pair[0]] = pair[1]
+		}
+	}
+	return values, nil
+}
+
+// parseExternalIDs returns the external ids per row name from the output of
+// "ovs-vsctl --format=json --columns=name,external_ids list", the rows of
+// tables without name, like Open_vSwitch, are returned with an empty one
+func parseExternalIDs(output string) (map[string]map[string]string, error) {
+	table := ovsdbTable{}
+	err := json.Unmarshal([]byte(output), &table)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing ovs-vsctl output: %v", err)
+	}
+	nameColumn, idsColumn := -1, -1
+	for i, heading := range table.Headings {
+		switch heading {
+		case "name":
+			nameColumn = i
+		case externalIDsKey:
+			idsColumn = i
+		}
+	}
+	if idsColumn < 0 {
+		return nil, fmt.Errorf("ovs-vsctl output has no %s column", externalIDsKey)
+	}
+
+	externalIDs := map[string]map[string]string{}
+	for _, row := range table.Data {
+		name := ""
+		if nameColumn >= 0 {
+			err = json.Unmarshal(row[nameColumn], &name)
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing ovs-vsctl row name: %v", err)
+			}
+		}
+		ids, err := parseOvsdbMap(row[idsColumn])
+		if err != nil {
+			return nil, err
+		}
+		externalIDs[name] = ids
+	}
+	return externalIDs, nil
+}
+
+func interfaceMap(ids map[string]string) map[string]interface{} {
+	values := map[string]interface{}{}
+	for key, value := range ids {
+		values[key] = value
+	}
+	return values
+}
+
+// addOvsExternalIDs reports the global external ids, with the OVN bridge
+// mappings parsed, and the ones of the bridges at the current state
+func addOvsExternalIDs(currentState nmstatev1alpha1.State, globalIDs map[string]string, bridgeIDs map[string]map[string]string) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+
+	state[ovsDBKey] = map[string]interface{}{externalIDsKey: interfaceMap(globalIDs)}
+	if bridgeMappings, found := globalIDs[ovnBridgeMappingsExternalID]; found {
+		mappings := []interface{}{}
+		for _, mapping := range strings.Split(bridgeMappings, ",") {
+			fields := strings.SplitN(mapping, ":", 2)
+			if len(fields) != 2 {
+				continue
+			}
+			mappings = append(mappings, map[string]interface{}{"localnet": fields[0], "bridge": fields[1]})
+		}
+		state[ovnKey] = map[string]interface{}{bridgeMappingsKey: mappings}
+	}
+
+	interfaces, _ := state["interfaces"].([]interface{})
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap || iface["type"] != ovsBridgeInterfaceType {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		if ids, found := bridgeIDs[name]; found {
+			iface[ovsDBKey] = map[string]interface{}{externalIDsKey: interfaceMap(ids)}
+		}
+	}
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+// reportOvsExternalIDs reports the Open vSwitch external ids if Open vSwitch
+// is running at the node, nodes without it report none
+func reportOvsExternalIDs(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	if _, err := exec.LookPath(ovsVsctlCommand); err != nil {
+		return currentState, nil
+	}
+	if _, err := ovsVsctl("show"); err != nil {
+		return currentState, nil
+	}
+	output, err := ovsVsctl("--format=json", "--columns=external_ids", "list", "Open_vSwitch")
+	if err != nil {
+		return currentState, err
+	}
+	globalIDs, err := parseExternalIDs(output)
+	if err != nil {
+		return currentState, err
+	}
+	output, err = ovsVsctl("--format=json", "--columns=name,external_ids", "list", "Bridge")
+	if err != nil {
+		return currentState, err
+	}
+	bridgeIDs, err := parseExternalIDs(output)
+	if err != nil {
+		return currentState, err
+	}
+	return addOvsExternalIDs(currentState, globalIDs[""], bridgeIDs)
+}
@@ -0,0 +1,162 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Open vSwitch external ids", func() {
+	desiredState := nmstatev1alpha1.NewState(`ovs-db:
+  external_ids:
+    ovn-encap-type: geneve
+ovn:
+  bridge-mappings:
+  - localnet: physnet1
+    bridge: br-ex
+  - localnet: physnet2
+    bridge: br1
+interfaces:
+- name: br-ex
+  type: ovs-bridge
+  state: up
+  ovs-db:
+    external_ids:
+      bridge-id: br-ex
+- name: eth1
+  type: ethernet
+  state: up
+`)
+
+	It("should collect the global and bridge external ids", func() {
+		ids, err := getOvsExternalIDs(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ids.global).To(Equal(map[string]string{
+			"ovn-encap-type":      "geneve",
+			"ovn-bridge-mappings": "physnet1:br-ex,physnet2:br1",
+		}))
+		Expect(ids.bridges).To(Equal(map[string]map[string]string{
+			"br-ex": {"bridge-id": "br-ex"},
+		}))
+		Expect(ids.mappedBridges).To(Equal([]string{"br-ex", "br1"}))
+	})
+
+	It("should fail setting external ids of interfaces that are not Open vSwitch bridges", func() {
+		_, err := getOvsExternalIDs(nmstatev1alpha1.NewState(`interfaces:
+- name: br1
+  type: linux-bridge
+  state: up
+  ovs-db:
+    external_ids:
+      bridge-id: br1
+`))
+		Expect(err).To(MatchError("interface br1 is not an Open vSwitch bridge, cannot set its external ids"))
+	})
+
+	It("should fail setting the bridge mappings twice", func() {
+		_, err := getOvsExternalIDs(nmstatev1alpha1.NewState(`ovs-db:
+  external_ids:
+    ovn-bridge-mappings: physnet1:br-ex
+ovn:
+  bridge-mappings:
+  - localnet: physnet1
+    bridge: br-ex
+`))
+		Expect(err).To(MatchError("ovn bridge-mappings cannot be set together with the ovn-bridge-mappings external id"))
+	})
+
+	It("should not check Open vSwitch without external ids", func() {
+		ids, err := getOvsExternalIDs(nmstatev1alpha1.NewState("interfaces: []\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(checkOvs(ids)).To(Succeed())
+	})
+
+	It("should remove the external ids from the desired state passed to nmstate", func() {
+		strippedState, err := stripOvsExternalIDs(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML(`interfaces:
+- name: br-ex
+  type: ovs-bridge
+  state: up
+- name: eth1
+  type: ethernet
+  state: up
+`))
+	})
+
+	It("should restore the previous values of the external ids set", func() {
+		ids, err := getOvsExternalIDs(nmstatev1alpha1.NewState(`ovs-db:
+  external_ids:
+    ovn-encap-type: geneve
+    ovn-encap-ip: 192.0.2.1
+interfaces:
+- name: br-ex
+  type: ovs-bridge
+  state: up
+  ovs-db:
+    external_ids:
+      bridge-id: br-ex
+      bridge-uplink: eth1
+- name: br-new
+  type: ovs-bridge
+  state: up
+  ovs-db:
+    external_ids:
+      bridge-id: br-new
+`))
+		Expect(err).ToNot(HaveOccurred())
+
+		previous := previousOvsExternalIDs(ids,
+			map[string]string{"ovn-encap-type": "vxlan", "system-id": "node01"},
+			map[string]map[string]string{"br-ex": {"bridge-id": "br-ex-old"}})
+		Expect(previous.global).To(Equal(map[string]string{
+			"ovn-encap-type": "vxlan",
+			"ovn-encap-ip":   "",
+		}))
+		Expect(previous.bridges).To(Equal(map[string]map[string]string{
+			"br-ex": {"bridge-id": "br-ex-old", "bridge-uplink": ""},
+		}))
+		Expect(previous.mappedBridges).To(BeEmpty())
+	})
+
+	It("should report the external ids at current state", func() {
+		globalIDs, err := parseExternalIDs(`{"data":[[["map",[["ovn-bridge-mappings","physnet1:br-ex"],["system-id","node01"]]]]],"headings":["external_ids"]}`)
+		Expect(err).ToNot(HaveOccurred())
+		bridgeIDs, err := parseExternalIDs(`{"data":[["br-ex",["map",[["bridge-id","br-ex"]]]],["br1",["map",[]]]],"headings":["name","external_ids"]}`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bridgeIDs).To(Equal(map[string]map[string]string{
+			"br-ex": {"bridge-id": "br-ex"},
+			"br1":   {},
+		}))
+
+		reportedState, err := addOvsExternalIDs(nmstatev1alpha1.NewState(`interfaces:
+- name: br-ex
+  type: ovs-bridge
+  state: up
+- name: eth1
+  type: ethernet
+  state: up
+`), globalIDs[""], bridgeIDs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reportedState.String()).To(MatchYAML(`ovs-db:
+  external_ids:
+    ovn-bridge-mappings: physnet1:br-ex
+    system-id: node01
+ovn:
+  bridge-mappings:
+  - localnet: physnet1
+    bridge: br-ex
+interfaces:
+- name: br-ex
+  type: ovs-bridge
+  state: up
+  ovs-db:
+    external_ids:
+      bridge-id: br-ex
+- name: eth1
+  type: ethernet
+  state: up
+`))
+	})
+})
@@ -0,0 +1,80 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gobwas/glob"
+	"github.com/tidwall/gjson"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// interfaceOwners returns the policy that last configured each interface
+// sorted by interface, taken from the desired state of the enactments
+// applied successfully. Absent interfaces and the ones not matching the
+// interfaces filter are left out. If more than one policy configures an
+// interface the latest applied one owns it.
+func interfaceOwners(enactments []nmstatev1alpha1.NodeNetworkConfigurationEnactment, interfacesFilterGlob glob.Glob) ([]nmstatev1alpha1.InterfaceOwner, error) {
+	owners := map[string]nmstatev1alpha1.InterfaceOwner{}
+	for _, enactment := range enactments {
+		if !enactment.Status.Conditions.IsAvailable() {
+			continue
+		}
+		policy := enactment.Labels[nmstatev1alpha1.EnactmentPolicyLabel]
+		desiredState, err := yaml.YAMLToJSON(enactment.Status.DesiredState.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed converting enactment %s desired state to JSON: %v", enactment.Name, err)
+		}
+		for _, iface := range gjson.GetBytes(desiredState, "interfaces").Array() {
+			name := iface.Get("name").String()
+			if name == "" || iface.Get("state").String() == "absent" || interfacesFilterGlob.Match(name) {
+				continue
+			}
+			owner, found := owners[name]
+			if found && !appliedLater(enactment.Status.FinishedAt, policy, owner) {
+				continue
+			}
+			owners[name] = nmstatev1alpha1.InterfaceOwner{
+				Interface: name,
+				Policy:    policy,
+				AppliedAt: enactment.Status.FinishedAt,
+			}
+		}
+	}
+
+	sortedOwners := []nmstatev1alpha1.InterfaceOwner{}
+	for _, owner := range owners {
+		sortedOwners = append(sortedOwners, owner)
+	}
+	sort.Slice(sortedOwners, func(i, j int) bool {
+		return sortedOwners[i].Interface < sortedOwners[j].Interface
+	})
+	return sortedOwners, nil
+}
+
+// appliedLater returns true if the policy desired state applied at
+// finishedAt is newer than the owner one, ties go to the first policy
+// by name so the owner does not depend on the enactments order
+func appliedLater(finishedAt *metav1.Time, policy string, owner nmstatev1alpha1.InterfaceOwner) bool {
+	if finishedAt == nil {
+		return false
+	}
+	if owner.AppliedAt == nil || owner.AppliedAt.Before(finishedAt) {
+		return true
+	}
+	return owner.AppliedAt.Equal(finishedAt) && policy < owner.Policy
+}
+
+func showInterfaceOwners(cli client.Client, nodeName string, interfacesFilterGlob glob.Glob) ([]nmstatev1alpha1.InterfaceOwner, error) {
+	enactments := nmstatev1alpha1.NodeNetworkConfigurationEnactmentList{}
+	err := cli.List(context.TODO(), &enactments, client.MatchingLabels{nmstatev1alpha1.EnactmentNodeLabel: nodeName})
+	if err != nil {
+		return nil, fmt.Errorf("failed listing node enactments: %v", err)
+	}
+	return interfaceOwners(enactments.Items, interfacesFilterGlob)
+}
@@ -0,0 +1,59 @@
+package helper
+
+import (
+	"time"
+
+	"github.com/gobwas/glob"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Interface owners", func() {
+	appliedAt := metav1.NewTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	laterAppliedAt := metav1.NewTime(appliedAt.Add(time.Minute))
+
+	enactment := func(policy string, available bool, finishedAt *metav1.Time, desiredState string) nmstatev1alpha1.NodeNetworkConfigurationEnactment {
+		enactment := nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "node01." + policy,
+				Labels: map[string]string{nmstatev1alpha1.EnactmentPolicyLabel: policy},
+			},
+		}
+		enactment.Status.DesiredState = nmstatev1alpha1.NewState(desiredState)
+		enactment.Status.FinishedAt = finishedAt
+		if available {
+			enactment.Status.Conditions.Set(nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionAvailable, corev1.ConditionTrue, nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionSuccessfullyConfigured, "")
+		}
+		return enactment
+	}
+
+	It("should report the latest policy applied successfully at each interface", func() {
+		owners, err := interfaceOwners([]nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+			enactment("bridge", true, &laterAppliedAt, "interfaces:\n- name: br1\n  type: linux-bridge\n  state: up\n- name: eth1\n  type: ethernet\n  state: up\n"),
+			enactment("eth1", true, &appliedAt, "interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n- name: eth2\n  type: ethernet\n  state: absent\n"),
+			enactment("failing", false, &laterAppliedAt, "interfaces:\n- name: eth3\n  type: ethernet\n  state: up\n"),
+			enactment("veth", true, &appliedAt, "interfaces:\n- name: veth1\n  type: veth\n  state: up\n"),
+		}, glob.MustCompile("veth*"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(owners).To(Equal([]nmstatev1alpha1.InterfaceOwner{
+			{Interface: "br1", Policy: "bridge", AppliedAt: &laterAppliedAt},
+			{Interface: "eth1", Policy: "bridge", AppliedAt: &laterAppliedAt},
+		}))
+	})
+
+	It("should give the interface to the first policy by name if applied at the same time", func() {
+		owners, err := interfaceOwners([]nmstatev1alpha1.NodeNetworkConfigurationEnactment{
+			enactment("mtu", true, &appliedAt, "interfaces:\n- name: eth1\n  mtu: 9000\n"),
+			enactment("addresses", true, &appliedAt, "interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n"),
+		}, glob.MustCompile(""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(owners).To(Equal([]nmstatev1alpha1.InterfaceOwner{
+			{Interface: "eth1", Policy: "addresses", AppliedAt: &appliedAt},
+		}))
+	})
+})
@@ -0,0 +1,43 @@
+package helper
+
+import (
+	"fmt"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+// RenderDesiredState applies the desired state patch to the current state
+func RenderDesiredState(currentState nmstatev1alpha1.State, desiredStatePatch nmstatev1alpha1.StatePatch) (nmstatev1alpha1.State, error) {
+	return render.DesiredState(currentState, desiredStatePatch)
+}
+
+// EffectiveDesiredState returns the policy desired state, rendering it
+// from the node current state, as reported at NodeNetworkState, if the
+// policy has a desired state patch
+func EffectiveDesiredState(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec) (nmstatev1alpha1.State, error) {
+	if policySpec.DesiredStatePatch == nil {
+		return policySpec.DesiredState, nil
+	}
+
+	currentState, err := CurrentState()
+	if err != nil {
+		return nmstatev1alpha1.State{}, err
+	}
+	return RenderDesiredState(currentState, *policySpec.DesiredStatePatch)
+}
+
+// CurrentState returns the node current state as nmstate reports it, without
+// the interfaces matching the interfaces filter. It's the NodeNetworkState
+// one without what the handler reports on top, see render.ObservedState.
+func CurrentState() (nmstatev1alpha1.State, error) {
+	observedStateRaw, err := show()
+	if err != nil {
+		return nmstatev1alpha1.State{}, fmt.Errorf("error running nmstatectl show: %v", err)
+	}
+	currentState, err := filterOut(nmstatev1alpha1.State{Raw: []byte(observedStateRaw)}, interfacesFilterGlob)
+	if err != nil {
+		return nmstatev1alpha1.State{}, fmt.Errorf("error filtering out interfaces from current state: %v", err)
+	}
+	return currentState, nil
+}
@@ -0,0 +1,96 @@
+package helper
+
+import (
+	"github.com/gobwas/glob"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+var _ = Describe("Desired state patch", func() {
+	currentState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth0
+  type: ethernet
+  state: up
+  mtu: 1500
+- name: eth1
+  type: ethernet
+  state: down
+  mtu: 1500
+`)
+
+	It("should render the desired state from the current state", func() {
+		desiredState, err := RenderDesiredState(currentState, nmstatev1alpha1.NewStatePatch(`- op: replace
+  path: /interfaces/1/state
+  value: up
+- op: replace
+  path: /interfaces/1/mtu
+  value: 9000
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(desiredState.String()).To(MatchYAML(`interfaces:
+- name: eth0
+  type: ethernet
+  state: up
+  mtu: 1500
+- name: eth1
+  type: ethernet
+  state: up
+  mtu: 9000
+`))
+	})
+
+	It("should fail if the patch is not a list of operations", func() {
+		_, err := RenderDesiredState(currentState, nmstatev1alpha1.NewStatePatch(`interfaces: []`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail if the patch does not apply to the current state", func() {
+		_, err := RenderDesiredState(currentState, nmstatev1alpha1.NewStatePatch(`- op: replace
+  path: /interfaces/5/state
+  value: up
+`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should render out of the handler against the reported state without what the handler reports", func() {
+		observedState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth0
+  type: ethernet
+  state: up
+  ipv4:
+    enabled: true
+    dhcp: true
+- name: gre1
+  type: unknown
+  state: up
+  ipv4:
+    enabled: false
+`)
+		reportedState, err := addPromiscFlags(observedState, map[string]bool{"eth0": true})
+		Expect(err).ToNot(HaveOccurred())
+		reportedState, err = addWakeOnLan(reportedState, map[string]string{"eth0": "g"})
+		Expect(err).ToNot(HaveOccurred())
+		reportedState, err = addFirewalldZones(reportedState, map[string]string{"eth0": "public"})
+		Expect(err).ToNot(HaveOccurred())
+		reportedState, err = addDHCPLeases(reportedState, map[string]map[string]map[string]interface{}{"eth0": {"ipv4": {"address": "192.0.2.10"}}})
+		Expect(err).ToNot(HaveOccurred())
+		reportedState, err = addHardware(reportedState, map[string]map[string]interface{}{"eth0": {"mac-address": "52:55:00:d1:56:01"}})
+		Expect(err).ToNot(HaveOccurred())
+		reportedState, err = addTimestamping(reportedState, map[string]map[string]interface{}{"eth0": {"software": true}})
+		Expect(err).ToNot(HaveOccurred())
+		links, err := parseTunnelLinks(`[{"ifname":"gre1","flags":["UP"],"linkinfo":{"info_kind":"gre","info_data":{"remote":"198.51.100.1","local":"192.0.2.1"}}},
+{"ifname":"ipip1","flags":["UP"],"linkinfo":{"info_kind":"ipip","info_data":{"remote":"198.51.100.2","local":"192.0.2.1"}}}]`)
+		Expect(err).ToNot(HaveOccurred())
+		reportedState, err = addTunnels(reportedState, links, glob.MustCompile(""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reportedState.String()).ToNot(MatchYAML(observedState.String()))
+
+		renderedState, err := render.ObservedState(reportedState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(renderedState.String()).To(MatchYAML(observedState.String()))
+	})
+})
@@ -0,0 +1,353 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const ipCommand = "ip"
+
+const (
+	promiscKey      = "promisc"
+	acceptAllMacKey = "accept-all-mac"
+)
+
+func ip(arguments ...string) (string, error) {
+	cmd := exec.Command(ipCommand, arguments...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := runCommand(cmd); err != nil {
+		return "", fmt.Errorf("failed to execute %s %v: '%v', '%s', '%s'", ipCommand, arguments, err, stdout.String(), stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// getPromiscFlags returns the promiscuous mode of the desired state
+// interfaces, accept-all-mac is implemented by the kernel with the
+// promiscuous mode so any of them enables it. Only the interfaces setting
+// them are changed, the rest keep the promiscuous mode set by other tools,
+// like tcpdump or the CNI plugins. Absent interfaces are ignored.
+func getPromiscFlags(desiredState nmstatev1alpha1.State) (map[string]bool, error) {
+	promiscFlags := map[string]bool{}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return promiscFlags, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		if iface.Get("state").String() == "absent" {
+			continue
+		}
+		promisc, acceptAllMac := iface.Get(promiscKey), iface.Get(acceptAllMacKey)
+		if !promisc.Exists() && !acceptAllMac.Exists() {
+			continue
+		}
+		promiscFlags[iface.Get("name").String()] = promisc.Bool() || acceptAllMac.Bool()
+	}
+	return promiscFlags, nil
+}
+
+// stripPromiscFlags removes the flags not supported by nmstate from the
+// desired state, with the interfaces only there to set them, like the ones
+// dropped from the policy turning the mode off
+func stripPromiscFlags(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	strippedState, err := stripInterfacesKeys(desiredState, promiscKey, acceptAllMacKey)
+	if err != nil {
+		return desiredState, err
+	}
+	return stripInterfaces(strippedState, func(ifaceMap map[string]interface{}) bool {
+		_, named := ifaceMap["name"]
+		return named && len(ifaceMap) == 1
+	})
+}
+
+// stripDroppedPromisc removes the interfaces added to turn off the
+// promiscuous mode of the interfaces dropped from the policy, they may be
+// gone from the node
+func stripDroppedPromisc(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return stripInterfaces(desiredState, func(ifaceMap map[string]interface{}) bool {
+		_, named := ifaceMap["name"]
+		return named && len(ifaceMap) == 2 && ifaceMap[promiscKey] == false
+	})
+}
+
+// stripInterfaces removes the desired state interfaces matching strip
+func stripInterfaces(desiredState nmstatev1alpha1.State, strip func(map[string]interface{}) bool) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return desiredState, nil
+	}
+
+	keptInterfaces := []interface{}{}
+	for _, iface := range interfaces {
+		if ifaceMap, isMap := iface.(map[string]interface{}); isMap && strip(ifaceMap) {
+			continue
+		}
+		keptInterfaces = append(keptInterfaces, iface)
+	}
+	if len(keptInterfaces) == len(interfaces) {
+		return desiredState, nil
+	}
+	state["interfaces"] = keptInterfaces
+
+	strippedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: strippedState}, nil
+}
+
+// stripInterfacesKeys removes the keys from all the desired state interfaces
+func stripInterfacesKeys(desiredState nmstatev1alpha1.State, keys ...string) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return desiredState, nil
+	}
+
+	for _, iface := range interfaces {
+		if iface, isMap := iface.(map[string]interface{}); isMap {
+			for _, key := range keys {
+				delete(iface, key)
+			}
+		}
+	}
+
+	strippedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: strippedState}, nil
+}
+
+// RemoveDroppedPromisc disables at the desired state the promiscuous mode
+// of the interfaces enabling it at the previously applied one that do not
+// set the flags anymore, so dropping them from the policy turns it off. The
+// interfaces dropped from the desired state altogether are added back only
+// to turn it off, nmstate does not get them. The interfaces setting it off
+// before keep the mode other tools set.
+func RemoveDroppedPromisc(previousState nmstatev1alpha1.State, desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	previousFlags, err := getPromiscFlags(previousState)
+	if err != nil || len(previousFlags) == 0 {
+		return desiredState, err
+	}
+	flags, err := getPromiscFlags(desiredState)
+	if err != nil {
+		return desiredState, err
+	}
+
+	var state map[string]interface{}
+	err = yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	interfaces, _ := state["interfaces"].([]interface{})
+
+	dropped := false
+	desiredInterfaces := map[string]bool{}
+	for _, iface := range interfaces {
+		name, named := itemName(iface)
+		if !named {
+			continue
+		}
+		desiredInterfaces[name] = true
+		if !previousFlags[name] {
+			continue
+		}
+		if _, isSet := flags[name]; isSet {
+			continue
+		}
+		ifaceMap := iface.(map[string]interface{})
+		if ifaceMap["state"] == "absent" {
+			continue
+		}
+		ifaceMap[promiscKey] = false
+		dropped = true
+	}
+	for _, name := range sortedPromiscInterfaces(previousFlags) {
+		if !previousFlags[name] || desiredInterfaces[name] {
+			continue
+		}
+		interfaces = append(interfaces, map[string]interface{}{"name": name, promiscKey: false})
+		dropped = true
+	}
+	if !dropped {
+		return desiredState, nil
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	state["interfaces"] = interfaces
+
+	removedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: removedState}, nil
+}
+
+func sortedPromiscInterfaces(promiscFlags map[string]bool) []string {
+	interfaces := []string{}
+	for iface := range promiscFlags {
+		interfaces = append(interfaces, iface)
+	}
+	sort.Strings(interfaces)
+	return interfaces
+}
+
+// applyPromiscFlags sets the promiscuous mode of the interfaces, turning it
+// off at interfaces gone from the node is not needed
+func applyPromiscFlags(promiscFlags map[string]bool) (string, error) {
+	output := ""
+	for _, iface := range sortedPromiscInterfaces(promiscFlags) {
+		promisc := promiscFlags[iface]
+		mode := "off"
+		if promisc {
+			mode = "on"
+		}
+		ipOutput, err := ip("link", "set", "dev", iface, "promisc", mode)
+		output += fmt.Sprintf("interface %s promisc %s output: %s\n", iface, mode, ipOutput)
+		if err != nil && !promisc && strings.Contains(err.Error(), "Cannot find device") {
+			continue
+		}
+		if err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+// readPromiscFlags returns the promiscuous mode of the interfaces given
+// that exist at the node, the ones created by the desired state are left
+// out, nmstate removes them on rollback
+func readPromiscFlags(promiscFlags map[string]bool) map[string]bool {
+	current := map[string]bool{}
+	if len(promiscFlags) == 0 {
+		return current
+	}
+	output, err := ip("-j", "link", "show")
+	if err != nil {
+		log.Info(fmt.Sprintf("failed reading interfaces promiscuous mode: %v", err))
+		return current
+	}
+	promiscLinks, err := parsePromiscLinks(output)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed reading interfaces promiscuous mode: %v", err))
+		return current
+	}
+	return previousPromiscFlags(promiscFlags, promiscLinks)
+}
+
+func previousPromiscFlags(promiscFlags map[string]bool, promiscLinks map[string]bool) map[string]bool {
+	previous := map[string]bool{}
+	for iface := range promiscFlags {
+		if promisc, exists := promiscLinks[iface]; exists {
+			previous[iface] = promisc
+		}
+	}
+	return previous
+}
+
+// restorePromiscFlags sets back the promiscuous mode read before applying
+// the desired state, it's not part of the nmstate checkpoint
+func restorePromiscFlags(previousPromiscFlags map[string]bool) string {
+	output, err := applyPromiscFlags(previousPromiscFlags)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed restoring interfaces promiscuous mode: %v", err))
+	}
+	return output
+}
+
+type ipLink struct {
+	Name  string   `json:"ifname"`
+	Flags []string `json:"flags"`
+}
+
+// parsePromiscLinks returns the promiscuous mode of the interfaces from
+// the output of "ip -j link show"
+func parsePromiscLinks(output string) (map[string]bool, error) {
+	links := []ipLink{}
+	err := json.Unmarshal([]byte(output), &links)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing ip links: %v", err)
+	}
+
+	promiscLinks := map[string]bool{}
+	for _, link := range links {
+		promiscLinks[link.Name] = false
+		for _, flag := range link.Flags {
+			if flag == "PROMISC" {
+				promiscLinks[link.Name] = true
+			}
+		}
+	}
+	return promiscLinks, nil
+}
+
+// addPromiscFlags reports the promiscuous mode at the current state
+// interfaces
+func addPromiscFlags(currentState nmstatev1alpha1.State, promiscLinks map[string]bool) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return currentState, nil
+	}
+
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		promisc, found := promiscLinks[name]
+		if !found {
+			continue
+		}
+		iface[promiscKey] = promisc
+		iface[acceptAllMacKey] = promisc
+	}
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+func reportPromiscFlags(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	output, err := ip("-j", "link", "show")
+	if err != nil {
+		return currentState, err
+	}
+	promiscLinks, err := parsePromiscLinks(output)
+	if err != nil {
+		return currentState, err
+	}
+	return addPromiscFlags(currentState, promiscLinks)
+}
@@ -0,0 +1,161 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Promiscuous mode", func() {
+	desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  promisc: true
+- name: eth2
+  type: ethernet
+  state: up
+  accept-all-mac: true
+- name: eth3
+  type: ethernet
+  state: up
+- name: eth4
+  type: ethernet
+  state: absent
+  promisc: true
+- name: eth5
+  type: ethernet
+  state: up
+  promisc: false
+`)
+
+	It("should take the interfaces setting the flags and leave the rest alone", func() {
+		promiscFlags, err := getPromiscFlags(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promiscFlags).To(Equal(map[string]bool{
+			"eth1": true,
+			"eth2": true,
+			"eth5": false,
+		}))
+	})
+
+	It("should remove the flags from the desired state passed to nmstate", func() {
+		strippedState, err := stripPromiscFlags(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+- name: eth2
+  type: ethernet
+  state: up
+- name: eth3
+  type: ethernet
+  state: up
+- name: eth4
+  type: ethernet
+  state: absent
+- name: eth5
+  type: ethernet
+  state: up
+`))
+	})
+
+	DescribeTable("dropped from the desired state",
+		func(previousState string, desiredState string, expectedState string) {
+			removedState, err := RemoveDroppedPromisc(nmstatev1alpha1.NewState(previousState), nmstatev1alpha1.NewState(desiredState))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removedState.String()).To(MatchYAML(expectedState))
+		},
+		Entry("should disable it at the interfaces enabling it before",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  promisc: true\n",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  promisc: false\n"),
+		Entry("should disable it once accept-all-mac is dropped",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  accept-all-mac: true\n",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  promisc: false\n"),
+		Entry("should keep the flags still set",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  promisc: true\n",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  accept-all-mac: true\n",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  accept-all-mac: true\n"),
+		Entry("should leave alone the interfaces disabling it before",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  promisc: false\n",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n"),
+		Entry("should disable it at the interfaces dropped from the desired state",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  promisc: true\n- name: eth3\n  type: ethernet\n  state: up\n  promisc: false\n",
+			"interfaces:\n- name: eth2\n  type: ethernet\n  state: up\n",
+			"interfaces:\n- name: eth2\n  type: ethernet\n  state: up\n- name: eth1\n  promisc: false\n"),
+		Entry("should disable it once the desired state drops all the interfaces",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  accept-all-mac: true\n",
+			"routes:\n  config: []\n",
+			"routes:\n  config: []\ninterfaces:\n- name: eth1\n  promisc: false\n"),
+		Entry("should leave alone the interfaces removed",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  promisc: true\n",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: absent\n",
+			"interfaces:\n- name: eth1\n  type: ethernet\n  state: absent\n"),
+	)
+
+	It("should not pass the interfaces dropped from the desired state to nmstate", func() {
+		removedState, err := RemoveDroppedPromisc(
+			nmstatev1alpha1.NewState("interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n  promisc: true\n"),
+			nmstatev1alpha1.NewState("interfaces:\n- name: eth2\n  type: ethernet\n  state: up\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		promiscFlags, err := getPromiscFlags(removedState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promiscFlags).To(Equal(map[string]bool{"eth1": false}))
+
+		strippedState, err := stripPromiscFlags(removedState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML("interfaces:\n- name: eth2\n  type: ethernet\n  state: up\n"))
+
+		drift, err := Drift(removedState, nmstatev1alpha1.NewState("interfaces:\n- name: eth2\n  type: ethernet\n  state: up\n"), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(drift).To(BeEmpty())
+	})
+
+	It("should report the flags at current state", func() {
+		promiscLinks, err := parsePromiscLinks(`[{"ifindex":1,"ifname":"lo","flags":["LOOPBACK","UP","LOWER_UP"]},
+{"ifindex":2,"ifname":"eth1","flags":["BROADCAST","MULTICAST","PROMISC","UP","LOWER_UP"]}]`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promiscLinks).To(Equal(map[string]bool{"lo": false, "eth1": true}))
+
+		currentState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+- name: lo
+  type: unknown
+  state: down
+- name: eth2
+  type: ethernet
+  state: down
+`)
+		reportedState, err := addPromiscFlags(currentState, promiscLinks)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reportedState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  promisc: true
+  accept-all-mac: true
+- name: lo
+  type: unknown
+  state: down
+  promisc: false
+  accept-all-mac: false
+- name: eth2
+  type: ethernet
+  state: down
+`))
+	})
+
+	It("should restore the flags of the interfaces at the node", func() {
+		previous := previousPromiscFlags(map[string]bool{"eth1": false, "br1": true}, map[string]bool{"lo": false, "eth1": true})
+		Expect(previous).To(Equal(map[string]bool{"eth1": true}))
+	})
+})
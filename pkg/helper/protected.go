@@ -0,0 +1,27 @@
+package helper
+
+import (
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+// ModifiedProtectedInterfaces returns the protected interfaces that would
+// be modified by applying the desired state
+func ModifiedProtectedInterfaces(desiredState nmstatev1alpha1.State, protectedInterfaces []string) ([]string, error) {
+	modifiedProtectedInterfaces := []string{}
+	if len(protectedInterfaces) == 0 {
+		return modifiedProtectedInterfaces, nil
+	}
+
+	modified, err := render.ModifiedInterfaces(desiredState)
+	if err != nil {
+		return modifiedProtectedInterfaces, err
+	}
+
+	for _, protectedInterface := range protectedInterfaces {
+		if modified[protectedInterface] {
+			modifiedProtectedInterfaces = append(modifiedProtectedInterfaces, protectedInterface)
+		}
+	}
+	return modifiedProtectedInterfaces, nil
+}
@@ -0,0 +1,77 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Protected interfaces", func() {
+	type protectedCase struct {
+		desiredState        string
+		protectedInterfaces []string
+		expected            []string
+	}
+	DescribeTable("modified by desired state",
+		func(c protectedCase) {
+			modified, err := ModifiedProtectedInterfaces(nmstatev1alpha1.NewState(c.desiredState), c.protectedInterfaces)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(modified).To(ConsistOf(c.expected))
+		},
+		Entry("without protected interfaces", protectedCase{
+			desiredState: `interfaces:
+- name: eth0
+  type: ethernet
+  state: down
+`,
+			expected: []string{},
+		}),
+		Entry("when protected interface is not touched", protectedCase{
+			desiredState: `interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+`,
+			protectedInterfaces: []string{"eth0"},
+			expected:            []string{},
+		}),
+		Entry("when protected interface is listed", protectedCase{
+			desiredState: `interfaces:
+- name: eth0
+  type: ethernet
+  state: down
+`,
+			protectedInterfaces: []string{"eth0"},
+			expected:            []string{"eth0"},
+		}),
+		Entry("when protected interface is a bridge port", protectedCase{
+			desiredState: `interfaces:
+- name: br1
+  type: linux-bridge
+  state: up
+  bridge:
+    port:
+    - name: eth1
+    - name: eth0
+`,
+			protectedInterfaces: []string{"eth0", "eth2"},
+			expected:            []string{"eth0"},
+		}),
+		Entry("when protected interface is a bond slave", protectedCase{
+			desiredState: `interfaces:
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: active-backup
+    slaves:
+    - eth0
+    - eth1
+`,
+			protectedInterfaces: []string{"eth0", "eth1"},
+			expected:            []string{"eth0", "eth1"},
+		}),
+	)
+})
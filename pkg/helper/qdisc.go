@@ -0,0 +1,237 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const tcCommand = "tc"
+
+const (
+	qdiscKey = "qdisc"
+
+	// Token bucket filter, shapes the interface egress rate
+	tbfQdiscKind = "tbf"
+
+	// Removes the shaping, the kernel default qdisc is restored
+	noneQdiscKind = "none"
+)
+
+type qdisc struct {
+	Kind    string `json:"kind"`
+	Rate    string `json:"rate,omitempty"`
+	Burst   string `json:"burst,omitempty"`
+	Latency string `json:"latency,omitempty"`
+}
+
+func tc(arguments ...string) (string, error) {
+	cmd := exec.Command(tcCommand, arguments...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := runCommand(cmd); err != nil {
+		return "", fmt.Errorf("failed to execute %s %v: '%v', '%s', '%s'", tcCommand, arguments, err, stdout.String(), stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// getQdiscs returns the root qdisc of the desired state interfaces that
+// configure it, the rest of interfaces keep their current qdisc. Absent
+// interfaces are ignored.
+func getQdiscs(desiredState nmstatev1alpha1.State) (map[string]qdisc, error) {
+	qdiscs := map[string]qdisc{}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return qdiscs, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		ifaceQdisc := iface.Get(qdiscKey)
+		if !ifaceQdisc.Exists() || iface.Get("state").String() == "absent" {
+			continue
+		}
+		qdiscs[iface.Get("name").String()] = qdisc{
+			Kind:    ifaceQdisc.Get("kind").String(),
+			Rate:    ifaceQdisc.Get("rate").String(),
+			Burst:   ifaceQdisc.Get("burst").String(),
+			Latency: ifaceQdisc.Get("latency").String(),
+		}
+	}
+	return qdiscs, nil
+}
+
+// stripQdiscs removes the qdiscs, not supported by nmstate, from the desired
+// state
+func stripQdiscs(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return stripInterfacesKeys(desiredState, qdiscKey)
+}
+
+func qdiscArguments(iface string, ifaceQdisc qdisc) ([]string, error) {
+	switch ifaceQdisc.Kind {
+	case tbfQdiscKind:
+		return []string{"qdisc", "replace", "dev", iface, "root", tbfQdiscKind, "rate", ifaceQdisc.Rate, "burst", ifaceQdisc.Burst, "latency", ifaceQdisc.Latency}, nil
+	case noneQdiscKind:
+		return []string{"qdisc", "del", "dev", iface, "root"}, nil
+	}
+	return nil, fmt.Errorf("interface %s qdisc kind %q is not supported", iface, ifaceQdisc.Kind)
+}
+
+func applyQdiscs(qdiscs map[string]qdisc) (string, error) {
+	output := ""
+	for iface, ifaceQdisc := range qdiscs {
+		arguments, err := qdiscArguments(iface, ifaceQdisc)
+		if err != nil {
+			return output, err
+		}
+		tcOutput, err := tc(arguments...)
+		output += fmt.Sprintf("interface %s qdisc %s output: %s\n", iface, ifaceQdisc.Kind, tcOutput)
+		// There is nothing to delete if the interface has already the
+		// default qdisc
+		if err != nil && ifaceQdisc.Kind != noneQdiscKind {
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+type tcQdisc struct {
+	Kind    string                 `json:"kind"`
+	Device  string                 `json:"dev"`
+	Root    bool                   `json:"root"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// parseRootQdiscs returns the root qdisc of the interfaces from the output
+// of "tc -j qdisc show"
+func parseRootQdiscs(output string) (map[string]tcQdisc, error) {
+	qdiscs := []tcQdisc{}
+	err := json.Unmarshal([]byte(output), &qdiscs)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing tc qdiscs: %v", err)
+	}
+
+	rootQdiscs := map[string]tcQdisc{}
+	for _, qdisc := range qdiscs {
+		if qdisc.Root {
+			rootQdiscs[qdisc.Device] = qdisc
+		}
+	}
+	return rootQdiscs, nil
+}
+
+// readQdiscs returns the root qdisc of the interfaces given that exist at
+// the node, as the desired state qdiscs restoring them. The token bucket
+// filters are restored with their rate, burst and latency, the rest of
+// qdiscs are set by the kernel by default so restoring them removes the
+// desired one.
+func readQdiscs(qdiscs map[string]qdisc) map[string]qdisc {
+	current := map[string]qdisc{}
+	if len(qdiscs) == 0 {
+		return current
+	}
+	output, err := tc("-j", "qdisc", "show")
+	if err != nil {
+		log.Info(fmt.Sprintf("failed reading interfaces qdiscs: %v", err))
+		return current
+	}
+	rootQdiscs, err := parseRootQdiscs(output)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed reading interfaces qdiscs: %v", err))
+		return current
+	}
+	return previousQdiscs(qdiscs, rootQdiscs)
+}
+
+// previousQdiscs converts the root qdiscs of the interfaces given, tc
+// reports the token bucket filters rate in bytes per second, burst in
+// bytes and latency in microseconds
+func previousQdiscs(qdiscs map[string]qdisc, rootQdiscs map[string]tcQdisc) map[string]qdisc {
+	previous := map[string]qdisc{}
+	for iface := range qdiscs {
+		rootQdisc, exists := rootQdiscs[iface]
+		if !exists {
+			continue
+		}
+		rate, hasRate := rootQdisc.Options["rate"].(float64)
+		burst, hasBurst := rootQdisc.Options["burst"].(float64)
+		latency, hasLatency := rootQdisc.Options["lat"].(float64)
+		if rootQdisc.Kind != tbfQdiscKind || !hasRate || !hasBurst || !hasLatency {
+			previous[iface] = qdisc{Kind: noneQdiscKind}
+			continue
+		}
+		previous[iface] = qdisc{
+			Kind:    tbfQdiscKind,
+			Rate:    fmt.Sprintf("%.0fbps", rate),
+			Burst:   fmt.Sprintf("%.0fb", burst),
+			Latency: fmt.Sprintf("%.0fus", latency),
+		}
+	}
+	return previous
+}
+
+// restoreQdiscs sets back the root qdiscs read before applying the desired
+// state, they are not part of the nmstate checkpoint
+func restoreQdiscs(previousQdiscs map[string]qdisc) string {
+	output, err := applyQdiscs(previousQdiscs)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed restoring interfaces qdiscs: %v", err))
+	}
+	return output
+}
+
+// addQdiscs reports the root qdisc at the current state interfaces
+func addQdiscs(currentState nmstatev1alpha1.State, rootQdiscs map[string]tcQdisc) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return currentState, nil
+	}
+
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		rootQdisc, found := rootQdiscs[name]
+		if !found {
+			continue
+		}
+		reportedQdisc := map[string]interface{}{"kind": rootQdisc.Kind}
+		if len(rootQdisc.Options) > 0 {
+			reportedQdisc["options"] = rootQdisc.Options
+		}
+		iface[qdiscKey] = reportedQdisc
+	}
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+func reportQdiscs(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	output, err := tc("-j", "qdisc", "show")
+	if err != nil {
+		return currentState, err
+	}
+	rootQdiscs, err := parseRootQdiscs(output)
+	if err != nil {
+		return currentState, err
+	}
+	return addQdiscs(currentState, rootQdiscs)
+}
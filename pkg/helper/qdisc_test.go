@@ -0,0 +1,128 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Traffic control qdiscs", func() {
+	desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  qdisc:
+    kind: tbf
+    rate: 100mbit
+    burst: 32kbit
+    latency: 400ms
+- name: eth2
+  type: ethernet
+  state: up
+  qdisc:
+    kind: none
+- name: eth3
+  type: ethernet
+  state: up
+- name: eth4
+  type: ethernet
+  state: absent
+  qdisc:
+    kind: none
+`)
+
+	It("should configure only the interfaces with qdisc", func() {
+		qdiscs, err := getQdiscs(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(qdiscs).To(Equal(map[string]qdisc{
+			"eth1": qdisc{Kind: "tbf", Rate: "100mbit", Burst: "32kbit", Latency: "400ms"},
+			"eth2": qdisc{Kind: "none"},
+		}))
+	})
+
+	It("should remove the qdiscs from the desired state passed to nmstate", func() {
+		strippedState, err := stripQdiscs(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+- name: eth2
+  type: ethernet
+  state: up
+- name: eth3
+  type: ethernet
+  state: up
+- name: eth4
+  type: ethernet
+  state: absent
+`))
+	})
+
+	It("should translate the qdiscs to tc arguments", func() {
+		arguments, err := qdiscArguments("eth1", qdisc{Kind: "tbf", Rate: "100mbit", Burst: "32kbit", Latency: "400ms"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(arguments).To(Equal([]string{"qdisc", "replace", "dev", "eth1", "root", "tbf", "rate", "100mbit", "burst", "32kbit", "latency", "400ms"}))
+
+		arguments, err = qdiscArguments("eth2", qdisc{Kind: "none"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(arguments).To(Equal([]string{"qdisc", "del", "dev", "eth2", "root"}))
+
+		_, err = qdiscArguments("eth3", qdisc{Kind: "htb"})
+		Expect(err).To(MatchError(`interface eth3 qdisc kind "htb" is not supported`))
+	})
+
+	It("should restore the token bucket filters and remove the rest", func() {
+		rootQdiscs, err := parseRootQdiscs(`[{"kind":"fq_codel","handle":"0:","dev":"eth2","root":true,"refcnt":2,"options":{}},
+{"kind":"tbf","handle":"8001:","dev":"eth1","root":true,"refcnt":2,"options":{"rate":12500000,"burst":4096,"lat":400000}}]`)
+		Expect(err).ToNot(HaveOccurred())
+		previous := previousQdiscs(map[string]qdisc{"eth1": {Kind: "none"}, "eth2": {Kind: "tbf"}, "eth3": {Kind: "tbf"}}, rootQdiscs)
+		Expect(previous).To(Equal(map[string]qdisc{
+			"eth1": {Kind: "tbf", Rate: "12500000bps", Burst: "4096b", Latency: "400000us"},
+			"eth2": {Kind: "none"},
+		}))
+	})
+
+	It("should report the root qdiscs at current state", func() {
+		rootQdiscs, err := parseRootQdiscs(`[{"kind":"noqueue","handle":"0:","dev":"lo","root":true,"refcnt":2,"options":{}},
+{"kind":"tbf","handle":"8001:","dev":"eth1","root":true,"refcnt":2,"options":{"rate":12500000,"burst":4096,"lat":400000}},
+{"kind":"ingress","handle":"ffff:","dev":"eth1","parent":"ffff:fff1","options":{}}]`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rootQdiscs).To(HaveLen(2))
+		Expect(rootQdiscs["eth1"].Kind).To(Equal("tbf"))
+
+		currentState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+- name: lo
+  type: unknown
+  state: down
+- name: eth2
+  type: ethernet
+  state: down
+`)
+		reportedState, err := addQdiscs(currentState, rootQdiscs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reportedState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  qdisc:
+    kind: tbf
+    options:
+      rate: 12500000
+      burst: 4096
+      lat: 400000
+- name: lo
+  type: unknown
+  state: down
+  qdisc:
+    kind: noqueue
+- name: eth2
+  type: ethernet
+  state: down
+`))
+	})
+})
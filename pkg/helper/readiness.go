@@ -0,0 +1,146 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const readinessCheckTimeout = 60
+
+// Each attempt of the TCP and HTTP checks is bounded, so an unreachable
+// target is retried within the readiness check timeout
+const readinessProbeTimeout = 5 * time.Second
+
+type ipAddressInfo struct {
+	Family  string `json:"family"`
+	Local   string `json:"local"`
+	Scope   string `json:"scope"`
+	Dynamic bool   `json:"dynamic"`
+}
+
+type ipAddressLink struct {
+	Name      string          `json:"ifname"`
+	Flags     []string        `json:"flags"`
+	Addresses []ipAddressInfo `json:"addr_info"`
+}
+
+// parseReadyInterfaces returns the interfaces with carrier, the LOWER_UP
+// flag, and a global address from the output of "ip -j addr show"
+func parseReadyInterfaces(output string) (map[string]bool, error) {
+	links := []ipAddressLink{}
+	err := json.Unmarshal([]byte(output), &links)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing ip addresses: %v", err)
+	}
+
+	ready := map[string]bool{}
+	for _, link := range links {
+		carrier := false
+		for _, flag := range link.Flags {
+			if flag == "LOWER_UP" {
+				carrier = true
+			}
+		}
+		if !carrier {
+			continue
+		}
+		for _, address := range link.Addresses {
+			if address.Scope == "global" {
+				ready[link.Name] = true
+			}
+		}
+	}
+	return ready, nil
+}
+
+// failedReadinessChecks returns why the checks do not pass with the ready
+// interfaces
+func failedReadinessChecks(checks []nmstatev1alpha1.ReadinessCheck, readyInterfaces map[string]bool) []string {
+	failed := []string{}
+	for _, check := range checks {
+		if len(check.AnyInterfaceUp) == 0 {
+			continue
+		}
+		anyUp := false
+		for _, iface := range check.AnyInterfaceUp {
+			if readyInterfaces[iface] {
+				anyUp = true
+				break
+			}
+		}
+		if !anyUp {
+			failed = append(failed, fmt.Sprintf("none of the interfaces %s is up with carrier and an IP address", strings.Join(check.AnyInterfaceUp, ", ")))
+		}
+	}
+	return failed
+}
+
+// probeTarget connects to the TCP or HTTP target of the check, if any,
+// from the node
+func probeTarget(check nmstatev1alpha1.ReadinessCheck, timeout time.Duration) error {
+	if check.TCPConnect != "" {
+		connection, err := net.DialTimeout("tcp", check.TCPConnect, timeout)
+		if err != nil {
+			return fmt.Errorf("TCP connection to %s failed: %v", check.TCPConnect, err)
+		}
+		connection.Close()
+	}
+	if check.HTTPGet != "" {
+		client := http.Client{Timeout: timeout}
+		response, err := client.Get(check.HTTPGet)
+		if err != nil {
+			return fmt.Errorf("HTTP GET %s failed: %v", check.HTTPGet, err)
+		}
+		response.Body.Close()
+		if response.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("HTTP GET %s returned status %d", check.HTTPGet, response.StatusCode)
+		}
+	}
+	return nil
+}
+
+// failedProbes returns why the TCP and HTTP checks do not pass
+func failedProbes(checks []nmstatev1alpha1.ReadinessCheck, timeout time.Duration) []string {
+	failed := []string{}
+	for _, check := range checks {
+		err := probeTarget(check, timeout)
+		if err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	return failed
+}
+
+// checkReadiness waits for the readiness checks to pass, the interfaces can
+// take a while to negotiate the carrier and get their addresses, and the
+// routes to the targets to be usable
+func checkReadiness(checks []nmstatev1alpha1.ReadinessCheck, timeout time.Duration) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	failed := []string{}
+	pollErr := wait.PollImmediate(1*time.Second, timeout, func() (bool, error) {
+		output, err := ip("-j", "addr", "show")
+		if err != nil {
+			return false, err
+		}
+		readyInterfaces, err := parseReadyInterfaces(output)
+		if err != nil {
+			return false, err
+		}
+		failed = append(failedReadinessChecks(checks, readyInterfaces), failedProbes(checks, readinessProbeTimeout)...)
+		return len(failed) == 0, nil
+	})
+	if pollErr == wait.ErrWaitTimeout {
+		return fmt.Errorf("readiness checks failed: %s", strings.Join(failed, "; "))
+	}
+	return pollErr
+}
@@ -0,0 +1,87 @@
+package helper
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Readiness checks", func() {
+	It("should report the interfaces with carrier and a global address as ready", func() {
+		readyInterfaces, err := parseReadyInterfaces(`[
+{"ifname":"lo","flags":["LOOPBACK","UP","LOWER_UP"],"addr_info":[{"family":"inet","local":"127.0.0.1","scope":"host"}]},
+{"ifname":"eth1","flags":["BROADCAST","MULTICAST","UP","LOWER_UP"],"addr_info":[{"family":"inet","local":"192.0.2.1","scope":"global"}]},
+{"ifname":"eth2","flags":["NO-CARRIER","BROADCAST","MULTICAST","UP"],"addr_info":[{"family":"inet","local":"192.0.2.2","scope":"global"}]},
+{"ifname":"eth3","flags":["BROADCAST","MULTICAST","UP","LOWER_UP"],"addr_info":[{"family":"inet6","local":"fe80::1","scope":"link"}]}]`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readyInterfaces).To(Equal(map[string]bool{"eth1": true}))
+	})
+
+	DescribeTable("any interface up",
+		func(anyInterfaceUp []string, readyInterfaces map[string]bool, expectedFailed []string) {
+			checks := []nmstatev1alpha1.ReadinessCheck{{AnyInterfaceUp: anyInterfaceUp}}
+			Expect(failedReadinessChecks(checks, readyInterfaces)).To(Equal(expectedFailed))
+		},
+		Entry("with all the interfaces ready, should pass",
+			[]string{"eth1", "eth2"}, map[string]bool{"eth1": true, "eth2": true}, []string{}),
+		Entry("with one of the interfaces ready, should pass",
+			[]string{"eth1", "eth2"}, map[string]bool{"eth2": true}, []string{}),
+		Entry("without interfaces ready, should fail",
+			[]string{"eth1", "eth2"}, map[string]bool{"eth3": true},
+			[]string{"none of the interfaces eth1, eth2 is up with carrier and an IP address"}),
+	)
+
+	It("should pass without checks", func() {
+		Expect(checkReadiness(nil, 0)).To(Succeed())
+	})
+
+	Context("with targets", func() {
+		var listener net.Listener
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			var err error
+			listener, err = net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/healthz" {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+			listener.Close()
+		})
+
+		It("should pass if they are reachable", func() {
+			checks := []nmstatev1alpha1.ReadinessCheck{
+				{TCPConnect: listener.Addr().String()},
+				{HTTPGet: server.URL + "/healthz"},
+			}
+			Expect(failedProbes(checks, time.Second)).To(BeEmpty())
+		})
+
+		It("should fail if the TCP target does not accept connections", func() {
+			address := listener.Addr().String()
+			listener.Close()
+			failed := failedProbes([]nmstatev1alpha1.ReadinessCheck{{TCPConnect: address}}, time.Second)
+			Expect(failed).To(HaveLen(1))
+			Expect(failed[0]).To(HavePrefix("TCP connection to " + address + " failed"))
+		})
+
+		It("should fail if the HTTP target returns an error status", func() {
+			Expect(failedProbes([]nmstatev1alpha1.ReadinessCheck{{HTTPGet: server.URL + "/ready"}}, time.Second)).To(Equal([]string{
+				"HTTP GET " + server.URL + "/ready returned status 503",
+			}))
+		})
+	})
+})
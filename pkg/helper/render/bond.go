@@ -0,0 +1,32 @@
+package render
+
+import (
+	"github.com/tidwall/gjson"
+)
+
+// Bonds list their ports at link-aggregation port since nmstate 1.0, which
+// deprecates slaves, the desired states may use either of them
+const (
+	bondPortsKey       = "port"
+	bondSlavesKey      = "slaves"
+	linkAggregationKey = "link-aggregation"
+)
+
+// BondPorts returns the ports of the desired state bond interface, or OVS
+// bridge bond port, taking link-aggregation port before slaves
+func BondPorts(iface gjson.Result) []gjson.Result {
+	if ports := iface.Get(linkAggregationKey + "." + bondPortsKey); ports.Exists() {
+		return ports.Array()
+	}
+	return iface.Get(linkAggregationKey + "." + bondSlavesKey).Array()
+}
+
+// bondPorts returns the ports list of the bond link aggregation, the same
+// way as BondPorts, nil if it has none
+func bondPorts(linkAggregation map[string]interface{}) []interface{} {
+	if ports, isList := linkAggregation[bondPortsKey].([]interface{}); isList {
+		return ports
+	}
+	slaves, _ := linkAggregation[bondSlavesKey].([]interface{})
+	return slaves
+}
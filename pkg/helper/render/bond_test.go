@@ -0,0 +1,63 @@
+package render
+
+import (
+	"github.com/tidwall/gjson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Bond ports", func() {
+	DescribeTable("of the desired state bonds",
+		func(iface string, expectedPorts []string) {
+			ports := []string{}
+			for _, port := range BondPorts(gjson.Parse(iface)) {
+				ports = append(ports, port.String())
+			}
+			Expect(ports).To(Equal(expectedPorts))
+		},
+		Entry("with port", `{"link-aggregation":{"port":["eth1","eth2"]}}`, []string{"eth1", "eth2"}),
+		Entry("with deprecated slaves", `{"link-aggregation":{"slaves":["eth1"]}}`, []string{"eth1"}),
+		Entry("with both, should take port", `{"link-aggregation":{"port":["eth2"],"slaves":["eth1"]}}`, []string{"eth2"}),
+		Entry("without ports", `{"link-aggregation":{"mode":"active-backup"}}`, []string{}),
+	)
+
+	It("should name the bond ports like at the node", func() {
+		state, err := ResolveInterfaceMatches(nmstatev1alpha1.NewState(`interfaces:
+- name: uplink1
+  type: ethernet
+  state: up
+  match:
+    mac-address: 52:55:00:d1:56:01
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: active-backup
+    port:
+    - uplink1
+`), []Interface{{Name: "ens1f0", MACAddress: "52:55:00:d1:56:01"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state.String()).To(MatchYAML(`interfaces:
+- name: ens1f0
+  type: ethernet
+  state: up
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: active-backup
+    port:
+    - ens1f0
+`))
+	})
+
+	It("should take the bond ports as modified interfaces", func() {
+		modified, err := ModifiedInterfaces(nmstatev1alpha1.NewState("interfaces:\n- name: bond0\n  type: bond\n  link-aggregation:\n    port:\n    - eth1\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(modified).To(Equal(map[string]bool{"bond0": true, "eth1": true}))
+	})
+})
@@ -0,0 +1,81 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// IgnoreInterfaces removes the ignored interfaces from the desired state so
+// nmstate leaves them alone, and returns the ones removed. Desired states
+// attaching ignored interfaces to their bridges, bonds or VLANs cannot
+// leave them alone, so they fail.
+func IgnoreInterfaces(desiredState nmstatev1alpha1.State, ignoredInterfaces []string) (nmstatev1alpha1.State, []string, error) {
+	removed := []string{}
+	if len(ignoredInterfaces) == 0 {
+		return desiredState, removed, nil
+	}
+	ignored := map[string]bool{}
+	for _, ignoredInterface := range ignoredInterfaces {
+		ignored[ignoredInterface] = true
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return desiredState, removed, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	attached := []string{}
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		if ignored[iface.Get("name").String()] {
+			continue
+		}
+		references := iface.Get("bridge.port.#.name").Array()
+		references = append(references, BondPorts(iface)...)
+		references = append(references, iface.Get("vlan.base-iface"), iface.Get("vxlan.base-iface"))
+		for _, reference := range references {
+			if ignored[reference.String()] {
+				attached = append(attached, reference.String())
+			}
+		}
+	}
+	if len(attached) > 0 {
+		sort.Strings(attached)
+		return desiredState, removed, fmt.Errorf("desired state attaches the ignored interfaces %v to other interfaces", attached)
+	}
+
+	var state map[string]interface{}
+	err = yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, removed, err
+	}
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return desiredState, removed, nil
+	}
+	keptInterfaces := []interface{}{}
+	for _, iface := range interfaces {
+		if ifaceMap, isMap := iface.(map[string]interface{}); isMap {
+			if name, _ := ifaceMap["name"].(string); ignored[name] {
+				removed = append(removed, name)
+				continue
+			}
+		}
+		keptInterfaces = append(keptInterfaces, iface)
+	}
+	if len(removed) == 0 {
+		return desiredState, removed, nil
+	}
+	sort.Strings(removed)
+	state["interfaces"] = keptInterfaces
+
+	ignoredState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, removed, err
+	}
+	return nmstatev1alpha1.State{Raw: ignoredState}, removed, nil
+}
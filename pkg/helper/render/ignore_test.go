@@ -0,0 +1,63 @@
+package render
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Ignored interfaces", func() {
+	desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+- name: cni0
+  type: linux-bridge
+  state: absent
+routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-interface: eth1
+`)
+
+	It("should keep the desired state without ignored interfaces", func() {
+		ignoredState, ignored, err := IgnoreInterfaces(desiredState, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ignored).To(BeEmpty())
+		Expect(ignoredState).To(Equal(desiredState))
+	})
+
+	It("should remove the ignored interfaces from the desired state", func() {
+		ignoredState, ignored, err := IgnoreInterfaces(desiredState, []string{"cni0", "storage0"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ignored).To(Equal([]string{"cni0"}))
+		Expect(ignoredState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-interface: eth1
+`))
+	})
+
+	It("should fail if the desired state attaches ignored interfaces", func() {
+		_, _, err := IgnoreInterfaces(nmstatev1alpha1.NewState(`interfaces:
+- name: br1
+  type: linux-bridge
+  state: up
+  bridge:
+    port:
+    - name: eth1
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    slaves:
+    - eth2
+`), []string{"eth2", "eth1"})
+		Expect(err).To(MatchError("desired state attaches the ignored interfaces [eth1 eth2] to other interfaces"))
+	})
+})
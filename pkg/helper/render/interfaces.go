@@ -0,0 +1,65 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// ModifiedInterfaces returns the interfaces changed by the desired state,
+// the ones listed at it and the ones attached to its bridges and bonds
+func ModifiedInterfaces(desiredState nmstatev1alpha1.State) (map[string]bool, error) {
+	modified := map[string]bool{}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return modified, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		modified[iface.Get("name").String()] = true
+		for _, port := range iface.Get("bridge.port.#.name").Array() {
+			modified[port.String()] = true
+		}
+		for _, port := range BondPorts(iface) {
+			modified[port.String()] = true
+		}
+	}
+	return modified, nil
+}
+
+// InterfaceOverrides returns the interfaces of the base desired state that
+// the overriding policies modify too, sorted by interface. If more than one
+// policy modifies an interface the first one by name is reported.
+func InterfaceOverrides(baseDesiredState nmstatev1alpha1.State, overridingPolicies []nmstatev1alpha1.NodeNetworkConfigurationPolicy) ([]nmstatev1alpha1.InterfaceOverride, error) {
+	overrides := []nmstatev1alpha1.InterfaceOverride{}
+	baseInterfaces, err := ModifiedInterfaces(baseDesiredState)
+	if err != nil {
+		return overrides, err
+	}
+
+	sort.Slice(overridingPolicies, func(i, j int) bool {
+		return overridingPolicies[i].Name < overridingPolicies[j].Name
+	})
+	overridden := map[string]bool{}
+	for _, policy := range overridingPolicies {
+		policyInterfaces, err := ModifiedInterfaces(policy.Spec.DesiredState)
+		if err != nil {
+			return overrides, fmt.Errorf("failed reading policy %s interfaces: %v", policy.Name, err)
+		}
+		for iface := range policyInterfaces {
+			if baseInterfaces[iface] && !overridden[iface] {
+				overridden[iface] = true
+				overrides = append(overrides, nmstatev1alpha1.InterfaceOverride{Interface: iface, Policy: policy.Name})
+			}
+		}
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		return overrides[i].Interface < overrides[j].Interface
+	})
+	return overrides, nil
+}
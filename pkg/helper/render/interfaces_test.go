@@ -0,0 +1,54 @@
+package render
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Interface overrides", func() {
+	policy := func(name string, desiredState string) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+		}
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState(desiredState)
+		return policy
+	}
+
+	baseDesiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  mtu: 9000
+- name: eth2
+  type: ethernet
+  state: up
+  mtu: 9000
+- name: eth3
+  type: ethernet
+  state: up
+  mtu: 9000
+`)
+
+	It("should report the interfaces other policies configure or attach", func() {
+		overrides, err := InterfaceOverrides(baseDesiredState, []nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			policy("storage", "interfaces:\n- name: eth3\n  type: ethernet\n  state: up\n  mtu: 1500\n"),
+			policy("bond0", "interfaces:\n- name: bond0\n  type: bond\n  state: up\n  link-aggregation:\n    mode: active-backup\n    slaves:\n    - eth1\n    - eth3\n"),
+			policy("dns", "dns-resolver:\n  config:\n    server:\n    - 192.0.2.1\n"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(overrides).To(Equal([]nmstatev1alpha1.InterfaceOverride{
+			{Interface: "eth1", Policy: "bond0"},
+			{Interface: "eth3", Policy: "bond0"},
+		}))
+	})
+
+	It("should not report anything without overriding policies", func() {
+		overrides, err := InterfaceOverrides(baseDesiredState, []nmstatev1alpha1.NodeNetworkConfigurationPolicy{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(overrides).To(BeEmpty())
+	})
+})
@@ -0,0 +1,166 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Physical interfaces are named differently at each node, a desired state
+// interface with match is identified by its MAC or PCI address and its name
+// is only an alias the rest of the desired state refers to it with
+const interfaceMatchKey = "match"
+
+// Interface is a node interface backed by a device, the desired state
+// interfaces with match are identified among them
+type Interface struct {
+	Name       string
+	MACAddress string
+	PCIAddress string
+}
+
+type interfaceNotFoundError struct {
+	err error
+}
+
+func (e *interfaceNotFoundError) Error() string {
+	return e.err.Error()
+}
+
+// IsInterfaceNotFound returns true if the desired state was not applied
+// because none of the node interfaces matches one of its interfaces
+func IsInterfaceNotFound(err error) bool {
+	_, notFound := err.(*interfaceNotFoundError)
+	return notFound
+}
+
+// HasInterfaceMatches returns true if the desired state may identify
+// interfaces by MAC or PCI address, so the node interfaces are needed
+func HasInterfaceMatches(desiredState nmstatev1alpha1.State) bool {
+	return strings.Contains(string(desiredState.Raw), interfaceMatchKey)
+}
+
+// matchInterface returns the name of the only interface with the matched
+// MAC or PCI address
+func matchInterface(alias string, match map[string]interface{}, interfaces []Interface) (string, error) {
+	macAddress, hasMAC := match["mac-address"].(string)
+	pciAddress, hasPCI := match["pci-address"].(string)
+	if len(match) != 1 || hasMAC == hasPCI {
+		return "", fmt.Errorf("invalid interface %s match, it needs either mac-address or pci-address", alias)
+	}
+	by, address := "MAC", strings.ToLower(macAddress)
+	if hasPCI {
+		by, address = "PCI", strings.ToLower(pciAddress)
+	}
+
+	matched := []string{}
+	for _, iface := range interfaces {
+		if (hasMAC && iface.MACAddress == address) || (hasPCI && iface.PCIAddress == address) {
+			matched = append(matched, iface.Name)
+		}
+	}
+	sort.Strings(matched)
+	if len(matched) == 0 {
+		return "", &interfaceNotFoundError{fmt.Errorf("no interface with %s address %s for interface %s", by, address, alias)}
+	}
+	if len(matched) > 1 {
+		return "", fmt.Errorf("interfaces %s have %s address %s of interface %s, it has to identify only one", strings.Join(matched, ", "), by, address, alias)
+	}
+	return matched[0], nil
+}
+
+func renameInterfaceReference(value map[string]interface{}, key string, names map[string]string) {
+	if reference, isString := value[key].(string); isString {
+		if name, found := names[reference]; found {
+			value[key] = name
+		}
+	}
+}
+
+// ResolveInterfaceMatches replaces the aliases of the interfaces with match
+// by the name of the interface they identify, at the interfaces and at the
+// ports, slaves, base interfaces and routes referring to them
+func ResolveInterfaceMatches(desiredState nmstatev1alpha1.State, interfaces []Interface) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	desiredInterfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return desiredState, nil
+	}
+
+	names := map[string]string{}
+	for _, iface := range desiredInterfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		match, hasMatch := iface[interfaceMatchKey]
+		if !hasMatch {
+			continue
+		}
+		alias, _ := iface["name"].(string)
+		matchMap, isMap := match.(map[string]interface{})
+		if !isMap {
+			return desiredState, fmt.Errorf("invalid interface %s match %v, it needs either mac-address or pci-address", alias, match)
+		}
+		name, err := matchInterface(alias, matchMap, interfaces)
+		if err != nil {
+			return desiredState, err
+		}
+		names[alias] = name
+		delete(iface, interfaceMatchKey)
+	}
+	if len(names) == 0 {
+		return desiredState, nil
+	}
+
+	for _, iface := range desiredInterfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		renameInterfaceReference(iface, "name", names)
+		if bridge, isMap := iface["bridge"].(map[string]interface{}); isMap {
+			ports, _ := bridge["port"].([]interface{})
+			for _, port := range ports {
+				if port, isMap := port.(map[string]interface{}); isMap {
+					renameInterfaceReference(port, "name", names)
+				}
+			}
+		}
+		if bond, isMap := iface[linkAggregationKey].(map[string]interface{}); isMap {
+			ports := bondPorts(bond)
+			for i, port := range ports {
+				if name, found := names[fmt.Sprint(port)]; found {
+					ports[i] = name
+				}
+			}
+		}
+		for _, kind := range []string{"vlan", "vxlan"} {
+			if config, isMap := iface[kind].(map[string]interface{}); isMap {
+				renameInterfaceReference(config, "base-iface", names)
+			}
+		}
+	}
+	if routes, isMap := state["routes"].(map[string]interface{}); isMap {
+		config, _ := routes["config"].([]interface{})
+		for _, route := range config {
+			if route, isMap := route.(map[string]interface{}); isMap {
+				renameInterfaceReference(route, "next-hop-interface", names)
+			}
+		}
+	}
+
+	resolvedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: resolvedState}, nil
+}
@@ -0,0 +1,104 @@
+package render
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Interface match", func() {
+	interfaces := []Interface{
+		{Name: "ens1f0", MACAddress: "52:55:00:d1:56:01", PCIAddress: "0000:03:00.0"},
+		{Name: "ens1f1", MACAddress: "52:55:00:d1:56:02", PCIAddress: "0000:03:00.1"},
+		{Name: "ens2", MACAddress: "52:55:00:d1:56:03", PCIAddress: "0000:04:00.0"},
+	}
+
+	It("should name the interfaces and their references like at the node", func() {
+		state, err := ResolveInterfaceMatches(nmstatev1alpha1.NewState(`interfaces:
+- name: uplink1
+  type: ethernet
+  state: up
+  match:
+    mac-address: 52:55:00:D1:56:01
+- name: uplink2
+  type: ethernet
+  state: up
+  match:
+    pci-address: 0000:03:00.1
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: active-backup
+    slaves:
+    - uplink1
+    - uplink2
+- name: storage
+  type: vlan
+  state: up
+  match:
+    pci-address: 0000:04:00.0
+- name: br1
+  type: linux-bridge
+  state: up
+  bridge:
+    port:
+    - name: storage
+routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-interface: uplink1
+`), interfaces)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(state.String()).To(MatchYAML(`interfaces:
+- name: ens1f0
+  type: ethernet
+  state: up
+- name: ens1f1
+  type: ethernet
+  state: up
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: active-backup
+    slaves:
+    - ens1f0
+    - ens1f1
+- name: ens2
+  type: vlan
+  state: up
+- name: br1
+  type: linux-bridge
+  state: up
+  bridge:
+    port:
+    - name: ens2
+routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-interface: ens1f0
+`))
+	})
+
+	It("should fail as not found if no interface matches", func() {
+		_, err := ResolveInterfaceMatches(nmstatev1alpha1.NewState(`interfaces:
+- name: uplink1
+  match:
+    mac-address: 52:55:00:d1:56:09
+`), interfaces)
+		Expect(IsInterfaceNotFound(err)).To(BeTrue())
+		Expect(err).To(MatchError("no interface with MAC address 52:55:00:d1:56:09 for interface uplink1"))
+	})
+
+	It("should fail if more than one interface matches", func() {
+		_, err := ResolveInterfaceMatches(nmstatev1alpha1.NewState(`interfaces:
+- name: uplink1
+  match:
+    mac-address: 52:55:00:d1:56:01
+`), append(interfaces, Interface{Name: "ens1f0v0", MACAddress: "52:55:00:d1:56:01"}))
+		Expect(IsInterfaceNotFound(err)).To(BeFalse())
+		Expect(err).To(MatchError("interfaces ens1f0, ens1f0v0 have MAC address 52:55:00:d1:56:01 of interface uplink1, it has to identify only one"))
+	})
+})
@@ -0,0 +1,136 @@
+package render
+
+import (
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// The physical interfaces are reported with the addresses interface match
+// identifies them with at hardware
+const (
+	HardwareKey           = "hardware"
+	HardwareMACAddressKey = "mac-address"
+	HardwarePCIAddressKey = "pci-address"
+)
+
+// The handler reports at NodeNetworkState what nmstate does not, the desired
+// state patches are rendered against the state nmstate reports so these are
+// left out before rendering
+var (
+	reportedStateKeys     = []string{"forwarding", "disable-ipv6", "ovs-db", "ovn"}
+	reportedInterfaceKeys = []string{"promisc", "accept-all-mac", "wake-on-lan", "qdisc", "firewalld-zone", "ovs-db", HardwareKey, "timestamping", "sysctl"}
+	reportedIPKeys        = []string{"dhcp-lease"}
+	reportedBridgeKeys    = []string{"stp-status"}
+	reportedPortKeys      = []string{"stp-state"}
+	reportedBridgeOptions = []string{"multicast-querier", "multicast-query-interval", "multicast-igmp-version", "multicast-mld-version"}
+)
+
+// tunnelInterfaceTypes are reported by nmstate as unknown interfaces, the
+// handler reports them with their type and configuration, and the ones
+// nmstate does not report at all are added
+var tunnelInterfaceTypes = map[string]bool{"gre": true, "ipip": true}
+
+// tunnelInterfaceKeys are the only ones of the tunnels reported by the
+// handler alone
+var tunnelInterfaceKeys = map[string]bool{"name": true, "type": true, "state": true}
+
+func deleteKeys(value map[string]interface{}, keys []string) {
+	for _, key := range keys {
+		delete(value, key)
+	}
+}
+
+// observedInterface returns the interface as nmstate reports it, false if
+// nmstate does not report it
+func observedInterface(iface map[string]interface{}) bool {
+	deleteKeys(iface, reportedInterfaceKeys)
+	for _, family := range []string{"ipv4", "ipv6"} {
+		if familyState, isMap := iface[family].(map[string]interface{}); isMap {
+			deleteKeys(familyState, reportedIPKeys)
+		}
+	}
+	if bridge, isMap := iface["bridge"].(map[string]interface{}); isMap && iface["type"] == "linux-bridge" {
+		deleteKeys(bridge, reportedBridgeKeys)
+		if options, isMap := bridge["options"].(map[string]interface{}); isMap {
+			deleteKeys(options, reportedBridgeOptions)
+		}
+		ports, _ := bridge["port"].([]interface{})
+		for _, port := range ports {
+			if port, isMap := port.(map[string]interface{}); isMap {
+				deleteKeys(port, reportedPortKeys)
+			}
+		}
+	}
+
+	kind, _ := iface["type"].(string)
+	if _, hasTunnel := iface[kind]; !tunnelInterfaceTypes[kind] || !hasTunnel {
+		return true
+	}
+	delete(iface, kind)
+	for key := range iface {
+		if !tunnelInterfaceKeys[key] {
+			iface["type"] = "unknown"
+			return true
+		}
+	}
+	return false
+}
+
+// ObservedState returns the state nmstate reports from the current state
+// reported at NodeNetworkState, it's the one the handler renders the desired
+// state patches against
+func ObservedState(reportedState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(reportedState.Raw, &state)
+	if err != nil {
+		return reportedState, err
+	}
+	if state == nil {
+		return reportedState, nil
+	}
+	deleteKeys(state, reportedStateKeys)
+	if interfaces, hasInterfaces := state["interfaces"].([]interface{}); hasInterfaces {
+		observedInterfaces := []interface{}{}
+		for _, iface := range interfaces {
+			if ifaceMap, isMap := iface.(map[string]interface{}); isMap && !observedInterface(ifaceMap) {
+				continue
+			}
+			observedInterfaces = append(observedInterfaces, iface)
+		}
+		state["interfaces"] = observedInterfaces
+	}
+
+	observedState, err := yaml.Marshal(state)
+	if err != nil {
+		return reportedState, err
+	}
+	return nmstatev1alpha1.State{Raw: observedState}, nil
+}
+
+// NodeInterfaces returns the physical interfaces of the current state
+// reported at NodeNetworkState with the addresses they are matched by
+func NodeInterfaces(reportedState nmstatev1alpha1.State) ([]Interface, error) {
+	interfaces := []Interface{}
+	var state map[string]interface{}
+	err := yaml.Unmarshal(reportedState.Raw, &state)
+	if err != nil {
+		return interfaces, err
+	}
+	reportedInterfaces, _ := state["interfaces"].([]interface{})
+	for _, iface := range reportedInterfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		hardware, isMap := iface[HardwareKey].(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		macAddress, _ := hardware[HardwareMACAddressKey].(string)
+		pciAddress, _ := hardware[HardwarePCIAddressKey].(string)
+		interfaces = append(interfaces, Interface{Name: name, MACAddress: macAddress, PCIAddress: pciAddress})
+	}
+	return interfaces, nil
+}
@@ -0,0 +1,87 @@
+package render
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Observed state", func() {
+	reportedState := nmstatev1alpha1.NewState(`forwarding:
+  ipv4: true
+disable-ipv6: false
+ovs-db:
+  external_ids:
+    system-id: node01
+interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  mac-address: 52:55:00:D1:56:01
+  promisc: false
+  sysctl:
+    ipv4:
+      rp_filter: 1
+  hardware:
+    mac-address: 52:55:00:d1:56:01
+    pci-address: "0000:03:00.0"
+  ipv4:
+    enabled: true
+    dhcp: true
+    dhcp-lease:
+      address: 192.0.2.10
+- name: br1
+  type: linux-bridge
+  state: up
+  bridge:
+    options:
+      multicast-snooping: true
+      multicast-querier: false
+      stp:
+        enabled: true
+    stp-status:
+      root-port: 0
+    port:
+    - name: eth1
+      stp-state: forwarding
+- name: gre2
+  type: gre
+  state: up
+  gre:
+    local: 192.0.2.1
+    remote: 198.51.100.1
+`)
+
+	It("should leave out what the handler reports on top of nmstate", func() {
+		observedState, err := ObservedState(reportedState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(observedState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  mac-address: 52:55:00:D1:56:01
+  ipv4:
+    enabled: true
+    dhcp: true
+- name: br1
+  type: linux-bridge
+  state: up
+  bridge:
+    options:
+      multicast-snooping: true
+      stp:
+        enabled: true
+    port:
+    - name: eth1
+`))
+	})
+
+	It("should return the physical interfaces with their addresses", func() {
+		interfaces, err := NodeInterfaces(reportedState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(interfaces).To(Equal([]Interface{
+			{Name: "eth1", MACAddress: "52:55:00:d1:56:01", PCIAddress: "0000:03:00.0"},
+		}))
+	})
+})
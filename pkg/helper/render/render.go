@@ -0,0 +1,40 @@
+// Package render renders the policies desired state, their patches, the
+// interfaces they match and the ones they leave out. It does not run
+// anything at the node so it can be used out of the handler too.
+package render
+
+import (
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// DesiredState applies the desired state patch to the current state
+func DesiredState(currentState nmstatev1alpha1.State, desiredStatePatch nmstatev1alpha1.StatePatch) (nmstatev1alpha1.State, error) {
+	patchJSON, err := yaml.YAMLToJSON(desiredStatePatch.Raw)
+	if err != nil {
+		return nmstatev1alpha1.State{}, fmt.Errorf("error converting desired state patch to JSON: %v", err)
+	}
+	patch, err := jsonpatch.DecodePatch(patchJSON)
+	if err != nil {
+		return nmstatev1alpha1.State{}, fmt.Errorf("error decoding desired state patch: %v", err)
+	}
+
+	currentStateJSON, err := yaml.YAMLToJSON(currentState.Raw)
+	if err != nil {
+		return nmstatev1alpha1.State{}, fmt.Errorf("error converting current state to JSON: %v", err)
+	}
+	desiredStateJSON, err := patch.Apply(currentStateJSON)
+	if err != nil {
+		return nmstatev1alpha1.State{}, fmt.Errorf("error applying desired state patch to current state: %v", err)
+	}
+
+	desiredState, err := yaml.JSONToYAML(desiredStateJSON)
+	if err != nil {
+		return nmstatev1alpha1.State{}, fmt.Errorf("error converting desired state to YAML: %v", err)
+	}
+	return nmstatev1alpha1.State{Raw: desiredState}, nil
+}
@@ -0,0 +1,16 @@
+package render
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/onsi/ginkgo/reporters"
+)
+
+func TestUnit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	junitReporter := reporters.NewJUnitReporter("junit.render-render_suite_test.xml")
+	RunSpecsWithDefaultAndCustomReporters(t, "Render Test Suite", []Reporter{junitReporter})
+}
@@ -0,0 +1,72 @@
+package helper
+
+import (
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// commandsUsage sums the CPU time of the commands run while it's measuring,
+// read from their process state once they finish
+type commandsUsage struct {
+	mutex     sync.Mutex
+	measuring bool
+	user      time.Duration
+	system    time.Duration
+}
+
+// The node applies are serialized by the node apply lock, so there is one
+// desired state apply measured at most
+var applyCommandsUsage = commandsUsage{}
+
+// runCommand runs the command and records its CPU time at the desired state
+// apply in progress
+func runCommand(cmd *exec.Cmd) error {
+	err := cmd.Run()
+	applyCommandsUsage.record(cmd)
+	return err
+}
+
+func (u *commandsUsage) record(cmd *exec.Cmd) {
+	if cmd.ProcessState == nil {
+		return
+	}
+	rusage, ok := cmd.ProcessState.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return
+	}
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if !u.measuring {
+		return
+	}
+	u.user += time.Duration(rusage.Utime.Nano())
+	u.system += time.Duration(rusage.Stime.Nano())
+}
+
+// StartApplyResourceUsage starts measuring the CPU time of the commands run
+// by a desired state apply
+func StartApplyResourceUsage() {
+	applyCommandsUsage.mutex.Lock()
+	defer applyCommandsUsage.mutex.Unlock()
+	applyCommandsUsage.measuring = true
+	applyCommandsUsage.user = 0
+	applyCommandsUsage.system = 0
+}
+
+// StopApplyResourceUsage returns the CPU time of the commands run since the
+// apply started
+func StopApplyResourceUsage() *nmstatev1alpha1.ApplyResourceUsage {
+	applyCommandsUsage.mutex.Lock()
+	defer applyCommandsUsage.mutex.Unlock()
+	applyCommandsUsage.measuring = false
+	return &nmstatev1alpha1.ApplyResourceUsage{
+		UserCPUTime:   metav1.Duration{Duration: applyCommandsUsage.user},
+		SystemCPUTime: metav1.Duration{Duration: applyCommandsUsage.system},
+	}
+}
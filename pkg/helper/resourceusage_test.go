@@ -0,0 +1,29 @@
+package helper
+
+import (
+	"os/exec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Desired state apply resource usage", func() {
+	busyCommand := func() *exec.Cmd {
+		return exec.Command("sh", "-c", "i=0; while [ $i -lt 200000 ]; do i=$((i+1)); done")
+	}
+
+	It("should sum the CPU time of the commands run while measuring", func() {
+		StartApplyResourceUsage()
+		Expect(runCommand(busyCommand())).To(Succeed())
+		usage := StopApplyResourceUsage()
+		Expect(usage.UserCPUTime.Duration + usage.SystemCPUTime.Duration).To(BeNumerically(">", 0))
+	})
+
+	It("should not sum the commands run before the apply started or after it finished", func() {
+		Expect(runCommand(busyCommand())).To(Succeed())
+		StartApplyResourceUsage()
+		usage := StopApplyResourceUsage()
+		Expect(runCommand(busyCommand())).To(Succeed())
+		Expect(usage.UserCPUTime.Duration + usage.SystemCPUTime.Duration).To(BeZero())
+	})
+})
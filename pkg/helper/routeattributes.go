@@ -0,0 +1,206 @@
+package helper
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const (
+	routeSourceKey = "source"
+	routeScopeKey  = "scope"
+)
+
+// routeScopes translates the scope names used by iproute to the numbers
+// NetworkManager takes at the route scope attribute
+var routeScopes = map[string]int{
+	"global":   0,
+	"universe": 0,
+	"site":     200,
+	"link":     253,
+	"host":     254,
+	"nowhere":  255,
+}
+
+// routeAttributes is a desired state route with a preferred source address
+// or scope, the route itself is configured by nmstate
+type routeAttributes struct {
+	destination      string
+	nextHopAddress   string
+	nextHopInterface string
+	metric           string
+	tableID          string
+	source           string
+	scope            string
+}
+
+func (r routeAttributes) family() string {
+	if strings.Contains(r.destination, ":") {
+		return "ipv6"
+	}
+	return "ipv4"
+}
+
+// nmcliRoute formats the route like nmcli takes it at ipvX.routes, the
+// attributes are not compared when removing a route so the one configured
+// by nmstate is found without them
+func (r routeAttributes) nmcliRoute(withAttributes bool) string {
+	fields := []string{r.destination}
+	if r.nextHopAddress != "" {
+		fields = append(fields, r.nextHopAddress)
+	}
+	if r.metric != "" {
+		fields = append(fields, r.metric)
+	}
+	if !withAttributes {
+		return strings.Join(fields, " ")
+	}
+	if r.tableID != "" {
+		fields = append(fields, "table="+r.tableID)
+	}
+	if r.source != "" {
+		fields = append(fields, "src="+r.source)
+	}
+	if r.scope != "" {
+		fields = append(fields, "scope="+r.scope)
+	}
+	return strings.Join(fields, " ")
+}
+
+// parseRouteScope returns the NetworkManager scope number of a scope name
+// or number
+func parseRouteScope(scope string) (string, error) {
+	if number, isName := routeScopes[scope]; isName {
+		return strconv.Itoa(number), nil
+	}
+	number, err := strconv.Atoi(scope)
+	if err != nil || number < 0 || number > 255 {
+		return "", fmt.Errorf("invalid route scope %s, it has to be global, site, link, host, nowhere or a number up to 255", scope)
+	}
+	return scope, nil
+}
+
+// getRouteAttributes returns the desired state routes with preferred source
+// address or scope, nmstate does not support them, they are set at the
+// connection of the next hop interface. Absent routes are ignored.
+func getRouteAttributes(desiredState nmstatev1alpha1.State) ([]routeAttributes, error) {
+	routes := []routeAttributes{}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return routes, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, route := range gjson.ParseBytes(desiredStateJSON).Get("routes.config").Array() {
+		source, scope := route.Get(routeSourceKey), route.Get(routeScopeKey)
+		if (!source.Exists() && !scope.Exists()) || route.Get("state").String() == "absent" {
+			continue
+		}
+		attributes := routeAttributes{
+			destination:      route.Get("destination").String(),
+			nextHopAddress:   route.Get("next-hop-address").String(),
+			nextHopInterface: route.Get("next-hop-interface").String(),
+			tableID:          route.Get("table-id").String(),
+			source:           source.String(),
+		}
+		if route.Get("metric").Exists() {
+			attributes.metric = route.Get("metric").String()
+		}
+		if attributes.nextHopInterface == "" {
+			return routes, fmt.Errorf("route %s has a preferred source address or scope without next-hop-interface", attributes.destination)
+		}
+		if source.Exists() {
+			sourceIP := net.ParseIP(attributes.source)
+			if sourceIP == nil {
+				return routes, fmt.Errorf("route %s preferred source address %s is not an IP address", attributes.destination, attributes.source)
+			}
+			if (sourceIP.To4() == nil) != (attributes.family() == "ipv6") {
+				return routes, fmt.Errorf("route %s preferred source address %s is not of the route family", attributes.destination, attributes.source)
+			}
+		}
+		if scope.Exists() {
+			attributes.scope, err = parseRouteScope(scope.String())
+			if err != nil {
+				return routes, fmt.Errorf("route %s: %v", attributes.destination, err)
+			}
+		}
+		routes = append(routes, attributes)
+	}
+	return routes, nil
+}
+
+// stripRouteAttributes removes the route preferred source addresses and
+// scopes, not supported by nmstate, from the desired state
+func stripRouteAttributes(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+
+	routes, hasRoutes := state["routes"].(map[string]interface{})
+	if !hasRoutes {
+		return desiredState, nil
+	}
+	config, hasConfig := routes["config"].([]interface{})
+	if !hasConfig {
+		return desiredState, nil
+	}
+
+	for _, route := range config {
+		if route, isMap := route.(map[string]interface{}); isMap {
+			delete(route, routeSourceKey)
+			delete(route, routeScopeKey)
+		}
+	}
+
+	strippedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: strippedState}, nil
+}
+
+// applyRouteAttributes replaces the routes configured by nmstate at the
+// active connections of the next hop interfaces with the same routes along
+// with their attributes, and reapplies the connections
+func applyRouteAttributes(routes []routeAttributes) (string, error) {
+	output := ""
+	interfaces := map[string]bool{}
+	for _, route := range routes {
+		property := route.family() + ".routes"
+		uuid, err := activeConnectionUUID(route.nextHopInterface)
+		if err != nil {
+			return output, err
+		}
+		nmcliOutput, err := nmcli("connection", "modify", uuid,
+			"-"+property, route.nmcliRoute(false),
+			"+"+property, route.nmcliRoute(true))
+		output += fmt.Sprintf("route %s attributes output: %s\n", route.nmcliRoute(true), nmcliOutput)
+		if err != nil {
+			return output, err
+		}
+		interfaces[route.nextHopInterface] = true
+	}
+
+	sortedInterfaces := []string{}
+	for iface := range interfaces {
+		sortedInterfaces = append(sortedInterfaces, iface)
+	}
+	sort.Strings(sortedInterfaces)
+	for _, iface := range sortedInterfaces {
+		nmcliOutput, err := nmcli("device", "reapply", iface)
+		output += fmt.Sprintf("interface %s reapply output: %s\n", iface, nmcliOutput)
+		if err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
@@ -0,0 +1,90 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Route attributes", func() {
+	desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-address: 192.0.2.1
+    next-hop-interface: eth1
+    metric: 150
+    table-id: 200
+    source: 192.0.2.10
+  - destination: 192.0.2.0/24
+    next-hop-interface: eth1
+    scope: link
+  - destination: 2001:db8:1::/64
+    next-hop-address: 2001:db8::1
+    next-hop-interface: eth1
+    source: 2001:db8::10
+  - destination: 203.0.113.0/24
+    next-hop-address: 192.0.2.1
+    next-hop-interface: eth1
+  - destination: 10.0.0.0/8
+    next-hop-interface: eth1
+    source: 192.0.2.10
+    state: absent
+`)
+
+	It("should return the routes with preferred source address or scope", func() {
+		routes, err := getRouteAttributes(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routes).To(Equal([]routeAttributes{
+			{destination: "198.51.100.0/24", nextHopAddress: "192.0.2.1", nextHopInterface: "eth1", metric: "150", tableID: "200", source: "192.0.2.10"},
+			{destination: "192.0.2.0/24", nextHopInterface: "eth1", scope: "253"},
+			{destination: "2001:db8:1::/64", nextHopAddress: "2001:db8::1", nextHopInterface: "eth1", source: "2001:db8::10"},
+		}))
+	})
+
+	It("should format the routes like nmcli takes them", func() {
+		routes, err := getRouteAttributes(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(routes[0].family()).To(Equal("ipv4"))
+		Expect(routes[0].nmcliRoute(false)).To(Equal("198.51.100.0/24 192.0.2.1 150"))
+		Expect(routes[0].nmcliRoute(true)).To(Equal("198.51.100.0/24 192.0.2.1 150 table=200 src=192.0.2.10"))
+		Expect(routes[1].nmcliRoute(true)).To(Equal("192.0.2.0/24 scope=253"))
+		Expect(routes[2].family()).To(Equal("ipv6"))
+		Expect(routes[2].nmcliRoute(true)).To(Equal("2001:db8:1::/64 2001:db8::1 src=2001:db8::10"))
+	})
+
+	It("should remove the preferred source addresses and scopes from the desired state", func() {
+		strippedState, err := stripRouteAttributes(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(strippedState.Raw)).ToNot(ContainSubstring("source"))
+		Expect(string(strippedState.Raw)).ToNot(ContainSubstring("scope"))
+		Expect(string(strippedState.Raw)).To(ContainSubstring("table-id: 200"))
+	})
+
+	DescribeTable("invalid route attributes",
+		func(route string, expectedError string) {
+			_, err := getRouteAttributes(nmstatev1alpha1.NewState("routes:\n  config:\n" + route))
+			Expect(err).To(MatchError(expectedError))
+		},
+		Entry("without next hop interface", `  - destination: 198.51.100.0/24
+    source: 192.0.2.10
+`, "route 198.51.100.0/24 has a preferred source address or scope without next-hop-interface"),
+		Entry("with source not being an IP", `  - destination: 198.51.100.0/24
+    next-hop-interface: eth1
+    source: eth1
+`, "route 198.51.100.0/24 preferred source address eth1 is not an IP address"),
+		Entry("with source of another family", `  - destination: 198.51.100.0/24
+    next-hop-interface: eth1
+    source: 2001:db8::10
+`, "route 198.51.100.0/24 preferred source address 2001:db8::10 is not of the route family"),
+		Entry("with unknown scope", `  - destination: 198.51.100.0/24
+    next-hop-interface: eth1
+    scope: planet
+`, "route 198.51.100.0/24: invalid route scope planet, it has to be global, site, link, host, nowhere or a number up to 255"),
+	)
+})
@@ -0,0 +1,95 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gobwas/glob"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const (
+	mainRouteTable  = "main"
+	localRouteTable = "local"
+)
+
+type ipRoute struct {
+	Destination string `json:"dst"`
+	Gateway     string `json:"gateway"`
+	Device      string `json:"dev"`
+	Metric      int    `json:"metric"`
+	Table       string `json:"table"`
+	Protocol    string `json:"protocol"`
+	Prefsrc     string `json:"prefsrc"`
+	Scope       string `json:"scope"`
+}
+
+// parseRunningRoutes converts the routes from "ip -j route show table all",
+// the local table is skipped since it only contains the addresses of the
+// node and broadcast routes
+func parseRunningRoutes(output string, defaultDestination string, interfacesFilterGlob glob.Glob) ([]nmstatev1alpha1.RunningRoute, error) {
+	routes := []ipRoute{}
+	err := json.Unmarshal([]byte(output), &routes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing ip routes: %v", err)
+	}
+
+	runningRoutes := []nmstatev1alpha1.RunningRoute{}
+	for _, route := range routes {
+		if route.Table == localRouteTable {
+			continue
+		}
+		if route.Device != "" && !interfacesFilterGlob.Match("") && interfacesFilterGlob.Match(route.Device) {
+			continue
+		}
+		runningRoute := nmstatev1alpha1.RunningRoute{
+			Destination:      route.Destination,
+			NextHopAddress:   route.Gateway,
+			NextHopInterface: route.Device,
+			Metric:           route.Metric,
+			Table:            route.Table,
+			PreferredSource:  route.Prefsrc,
+			Scope:            route.Scope,
+			// ip omits the protocol of routes added at boot
+			Protocol: route.Protocol,
+		}
+		if runningRoute.Destination == "default" {
+			runningRoute.Destination = defaultDestination
+		}
+		if runningRoute.Table == "" {
+			runningRoute.Table = mainRouteTable
+		}
+		if runningRoute.Protocol == "" {
+			runningRoute.Protocol = "boot"
+		}
+		// and the global scope
+		if runningRoute.Scope == "" {
+			runningRoute.Scope = "global"
+		}
+		runningRoutes = append(runningRoutes, runningRoute)
+	}
+	return runningRoutes, nil
+}
+
+func showRunningRoutes(interfacesFilterGlob glob.Glob) ([]nmstatev1alpha1.RunningRoute, error) {
+	runningRoutes := []nmstatev1alpha1.RunningRoute{}
+	for _, family := range []struct {
+		flag               string
+		defaultDestination string
+	}{
+		{"-4", "0.0.0.0/0"},
+		{"-6", "::/0"},
+	} {
+		output, err := ip(family.flag, "-j", "route", "show", "table", "all")
+		if err != nil {
+			return nil, err
+		}
+		familyRoutes, err := parseRunningRoutes(output, family.defaultDestination, interfacesFilterGlob)
+		if err != nil {
+			return nil, err
+		}
+		runningRoutes = append(runningRoutes, familyRoutes...)
+	}
+	return runningRoutes, nil
+}
@@ -0,0 +1,35 @@
+package helper
+
+import (
+	"github.com/gobwas/glob"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Running routes", func() {
+	output := `[{"dst":"default","gateway":"192.168.66.2","dev":"eth0","protocol":"dhcp","metric":100,"flags":[]},
+{"dst":"10.0.0.0/8","gateway":"192.168.66.3","dev":"eth1","flags":[]},
+{"dst":"192.168.66.0/24","dev":"eth0","protocol":"kernel","scope":"link","prefsrc":"192.168.66.101","metric":100,"flags":[]},
+{"dst":"198.51.100.0/24","dev":"eth1","table":"200","protocol":"static","metric":150,"flags":[]},
+{"dst":"10.244.0.5","dev":"veth1234","scope":"link","flags":[]},
+{"type":"local","dst":"192.168.66.101","dev":"eth0","table":"local","protocol":"kernel","scope":"host","prefsrc":"192.168.66.101","flags":[]}]`
+
+	It("should report metric, table, protocol, preferred source and scope skipping the local table and filtered interfaces", func() {
+		runningRoutes, err := parseRunningRoutes(output, "0.0.0.0/0", glob.MustCompile("veth*"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(runningRoutes).To(Equal([]nmstatev1alpha1.RunningRoute{
+			{Destination: "0.0.0.0/0", NextHopAddress: "192.168.66.2", NextHopInterface: "eth0", Metric: 100, Table: "main", Protocol: "dhcp", Scope: "global"},
+			{Destination: "10.0.0.0/8", NextHopAddress: "192.168.66.3", NextHopInterface: "eth1", Table: "main", Protocol: "boot", Scope: "global"},
+			{Destination: "192.168.66.0/24", NextHopInterface: "eth0", Metric: 100, Table: "main", Protocol: "kernel", PreferredSource: "192.168.66.101", Scope: "link"},
+			{Destination: "198.51.100.0/24", NextHopInterface: "eth1", Metric: 150, Table: "200", Protocol: "static", Scope: "global"},
+		}))
+	})
+
+	It("should keep all the interfaces with empty filter", func() {
+		runningRoutes, err := parseRunningRoutes(output, "0.0.0.0/0", glob.MustCompile(""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(runningRoutes).To(HaveLen(5))
+	})
+})
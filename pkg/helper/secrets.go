@@ -0,0 +1,149 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Desired state values like {secretKeyRef: {name: eth1-8021x, key: password}}
+// are replaced with the key of the secret when the desired state is applied,
+// so the secret material is never part of the policy nor the enactment
+const secretKeyRefKey = "secretKeyRef"
+
+// secretKeyRef returns the secret and key referenced by the desired state
+// value, if it's a secret reference
+func secretKeyRef(value interface{}) (string, string, bool) {
+	valueMap, isMap := value.(map[string]interface{})
+	if !isMap || len(valueMap) != 1 {
+		return "", "", false
+	}
+	ref, isMap := valueMap[secretKeyRefKey].(map[string]interface{})
+	if !isMap {
+		return "", "", false
+	}
+	name, _ := ref["name"].(string)
+	key, _ := ref["key"].(string)
+	return name, key, true
+}
+
+// replaceSecretKeyRefs calls replace with every secret reference of the
+// value and sets the value returned in its place
+func replaceSecretKeyRefs(value interface{}, replace func(name string, key string) (interface{}, error)) (interface{}, error) {
+	if name, key, isRef := secretKeyRef(value); isRef {
+		return replace(name, key)
+	}
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for k, v := range typedValue {
+			replaced, err := replaceSecretKeyRefs(v, replace)
+			if err != nil {
+				return nil, err
+			}
+			typedValue[k] = replaced
+		}
+	case []interface{}:
+		for i, v := range typedValue {
+			replaced, err := replaceSecretKeyRefs(v, replace)
+			if err != nil {
+				return nil, err
+			}
+			typedValue[i] = replaced
+		}
+	}
+	return value, nil
+}
+
+// SecretReferences returns the names of the secrets referenced by the
+// desired state
+func SecretReferences(desiredState nmstatev1alpha1.State) ([]string, error) {
+	names := []string{}
+	var state interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return names, err
+	}
+	referenced := map[string]bool{}
+	_, err = replaceSecretKeyRefs(state, func(name string, key string) (interface{}, error) {
+		referenced[name] = true
+		return nil, nil
+	})
+	if err != nil {
+		return names, err
+	}
+	for name := range referenced {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ResolveSecretReferences replaces the secret references of the desired
+// state with the data of the secrets, missing secrets or keys fail instead
+// of applying empty credentials
+func ResolveSecretReferences(desiredState nmstatev1alpha1.State, secrets map[string]map[string][]byte) (nmstatev1alpha1.State, []string, error) {
+	var state interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, nil, err
+	}
+	resolvedValues := []string{}
+	state, err = replaceSecretKeyRefs(state, func(name string, key string) (interface{}, error) {
+		data, found := secrets[name]
+		if !found {
+			return nil, fmt.Errorf("secret %s not found", name)
+		}
+		value, found := data[key]
+		if !found || len(value) == 0 {
+			return nil, fmt.Errorf("secret %s has no %s", name, key)
+		}
+		resolvedValues = append(resolvedValues, string(value))
+		return string(value), nil
+	})
+	if err != nil {
+		return desiredState, nil, err
+	}
+	if len(resolvedValues) == 0 {
+		return desiredState, resolvedValues, nil
+	}
+	resolvedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, nil, err
+	}
+	return nmstatev1alpha1.State{Raw: resolvedState}, resolvedValues, nil
+}
+
+// RedactSecretValues replaces the resolved secret values found at the text,
+// like the nmstate output, so they are not kept at the enactment status,
+// events or logs
+func RedactSecretValues(text string, values []string) string {
+	for _, value := range values {
+		text = strings.Replace(text, value, redactedValue, -1)
+	}
+	return text
+}
+
+// RedactSecretValuesError returns the error with the resolved secret values
+// redacted, keeping its type so it is still told apart
+func RedactSecretValuesError(err error, values []string) error {
+	switch typedErr := err.(type) {
+	case nil, *applyTimeoutError:
+		return err
+	case *busyError:
+		return &busyError{err: RedactSecretValuesError(typedErr.err, values)}
+	case *checkpointError:
+		return &checkpointError{err: RedactSecretValuesError(typedErr.err, values)}
+	case *failureError:
+		return &failureError{code: typedErr.code, err: RedactSecretValuesError(typedErr.err, values)}
+	}
+	redactedMessage := RedactSecretValues(err.Error(), values)
+	if redactedMessage == err.Error() {
+		return err
+	}
+	return errors.New(redactedMessage)
+}
@@ -0,0 +1,107 @@
+package helper
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Desired state secret references", func() {
+	desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  802.1x:
+    identity: node01
+    password:
+      secretKeyRef:
+        name: eth1-8021x
+        key: password
+- name: wg0
+  type: wireguard
+  state: up
+  wireguard:
+    private-key:
+      secretKeyRef:
+        name: wg0
+        key: private-key
+`)
+
+	It("should list the referenced secrets", func() {
+		names, err := SecretReferences(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(names).To(Equal([]string{"eth1-8021x", "wg0"}))
+	})
+
+	It("should replace the references with the secrets data", func() {
+		resolvedState, secretValues, err := ResolveSecretReferences(desiredState, map[string]map[string][]byte{
+			"eth1-8021x": {"password": []byte("s3cr3t")},
+			"wg0":        {"private-key": []byte("cHJpdmF0ZQ==")},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secretValues).To(ConsistOf("s3cr3t", "cHJpdmF0ZQ=="))
+		Expect(resolvedState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  802.1x:
+    identity: node01
+    password: s3cr3t
+- name: wg0
+  type: wireguard
+  state: up
+  wireguard:
+    private-key: cHJpdmF0ZQ==
+`))
+	})
+
+	It("should fail with missing secrets instead of applying empty credentials", func() {
+		_, _, err := ResolveSecretReferences(desiredState, map[string]map[string][]byte{
+			"eth1-8021x": {"password": []byte("s3cr3t")},
+		})
+		Expect(err).To(MatchError("secret wg0 not found"))
+	})
+
+	It("should fail with missing secret keys", func() {
+		_, _, err := ResolveSecretReferences(desiredState, map[string]map[string][]byte{
+			"eth1-8021x": {},
+			"wg0":        {"private-key": []byte("cHJpdmF0ZQ==")},
+		})
+		Expect(err).To(MatchError("secret eth1-8021x has no password"))
+	})
+
+	It("should redact the secrets data from the nmstate output and errors", func() {
+		secretValues := []string{"s3cr3t", "cHJpdmF0ZQ=="}
+		Expect(RedactSecretValues("identity: node01\nkey: s3cr3t\nwg: cHJpdmF0ZQ==", secretValues)).To(Equal("identity: node01\nkey: <redacted>\nwg: <redacted>"))
+		Expect(RedactSecretValues("s3cr3t", nil)).To(Equal("s3cr3t"))
+
+		err := RedactSecretValuesError(&busyError{err: fmt.Errorf("busy applying s3cr3t")}, secretValues)
+		Expect(IsBusy(err)).To(BeTrue())
+		Expect(err).To(MatchError("busy applying <redacted>"))
+		err = RedactSecretValuesError(&checkpointError{err: fmt.Errorf("failed applying s3cr3t")}, secretValues)
+		Expect(IsCheckpointFailed(err)).To(BeTrue())
+		Expect(err.Error()).ToNot(ContainSubstring("s3cr3t"))
+		Expect(RedactSecretValuesError(nil, secretValues)).To(BeNil())
+	})
+
+	It("should not report the references as drift", func() {
+		drift, err := Drift(desiredState, nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  802.1x:
+    identity: node01
+    password: <_password_hid_by_nmstate>
+- name: wg0
+  type: wireguard
+  state: up
+  wireguard:
+    private-key: <_password_hid_by_nmstate>
+`), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(drift).To(BeEmpty())
+	})
+})
@@ -0,0 +1,62 @@
+package helper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// reportedStateHash returns a hash of the state reported at the
+// NodeNetworkState status, the current state is unmarshaled so the YAML
+// formatting does not change it, the update time and conditions are not part
+// of it
+func reportedStateHash(status nmstatev1alpha1.NodeNetworkStateStatus) (string, error) {
+	var currentState interface{}
+	err := yaml.Unmarshal(status.CurrentState.Raw, &currentState)
+	if err != nil {
+		return "", err
+	}
+	reportedState, err := json.Marshal(struct {
+		CurrentState    interface{}
+		Connections     []nmstatev1alpha1.NetworkManagerConnection
+		Devices         []nmstatev1alpha1.NetworkManagerDevice
+		RunningRoutes   []nmstatev1alpha1.RunningRoute
+		StaticNeighbors []nmstatev1alpha1.StaticNeighbor
+		Bonds           []nmstatev1alpha1.BondStatus
+		CarrierHistory  []nmstatev1alpha1.InterfaceCarrierHistory
+		SystemInfo      *nmstatev1alpha1.SystemInfo
+		InterfaceOwners []nmstatev1alpha1.InterfaceOwner
+	}{
+		CurrentState:    currentState,
+		Connections:     status.Connections,
+		Devices:         status.Devices,
+		RunningRoutes:   status.RunningRoutes,
+		StaticNeighbors: status.StaticNeighbors,
+		Bonds:           status.Bonds,
+		CarrierHistory:  status.CarrierHistory,
+		SystemInfo:      status.SystemInfo,
+		InterfaceOwners: status.InterfaceOwners,
+	})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(reportedState)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// bumpConfigSerial increases the status config serial if the reported state
+// is different from the previous one, with the previous hash empty it is
+// always increased
+func bumpConfigSerial(status *nmstatev1alpha1.NodeNetworkStateStatus, previousHash string) {
+	currentHash, err := reportedStateHash(*status)
+	if err != nil {
+		log.Error(err, "failed calculating reported state hash, increasing config serial anyway")
+	}
+	if err != nil || previousHash == "" || currentHash != previousHash {
+		status.ConfigSerial++
+	}
+}
@@ -0,0 +1,62 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Config serial", func() {
+	status := func(currentState string) nmstatev1alpha1.NodeNetworkStateStatus {
+		return nmstatev1alpha1.NodeNetworkStateStatus{
+			CurrentState: nmstatev1alpha1.NewState(currentState),
+			Devices:      []nmstatev1alpha1.NetworkManagerDevice{{Name: "eth1", Type: "ethernet", State: "connected", Managed: true}},
+			ConfigSerial: 3,
+		}
+	}
+
+	hash := func(status nmstatev1alpha1.NodeNetworkStateStatus) string {
+		stateHash, err := reportedStateHash(status)
+		Expect(err).ToNot(HaveOccurred())
+		return stateHash
+	}
+
+	It("should not change the hash with the state formatting nor update time", func() {
+		previous := status("interfaces:\n- name: eth1\n  state: up\n  type: ethernet\n")
+		current := status("interfaces:\n  - type: ethernet\n    name: eth1\n    state: up\n")
+		current.LastSuccessfulUpdateTime = metav1.Now()
+		Expect(hash(current)).To(Equal(hash(previous)))
+	})
+
+	It("should keep the serial if the reported state does not change", func() {
+		current := status("interfaces:\n- name: eth1\n  state: up\n")
+		bumpConfigSerial(&current, hash(status("interfaces:\n- name: eth1\n  state: up\n")))
+		Expect(current.ConfigSerial).To(Equal(int64(3)))
+	})
+
+	It("should increase the serial if the current state changes", func() {
+		current := status("interfaces:\n- name: eth1\n  state: down\n")
+		bumpConfigSerial(&current, hash(status("interfaces:\n- name: eth1\n  state: up\n")))
+		Expect(current.ConfigSerial).To(Equal(int64(4)))
+	})
+
+	It("should increase the serial if the devices change", func() {
+		previous := status("interfaces:\n- name: eth1\n  state: up\n")
+		current := status("interfaces:\n- name: eth1\n  state: up\n")
+		current.Devices[0].State = "disconnected"
+		bumpConfigSerial(&current, hash(previous))
+		Expect(current.ConfigSerial).To(Equal(int64(4)))
+	})
+
+	It("should increase the serial if the system info changes", func() {
+		previous := status("interfaces:\n- name: eth1\n  state: up\n")
+		previous.SystemInfo = &nmstatev1alpha1.SystemInfo{KernelVersion: "4.18.0-147.el8.x86_64", NmstateVersion: "0.2.6"}
+		current := status("interfaces:\n- name: eth1\n  state: up\n")
+		current.SystemInfo = &nmstatev1alpha1.SystemInfo{KernelVersion: "4.18.0-193.el8.x86_64", NmstateVersion: "0.2.6"}
+		bumpConfigSerial(&current, hash(previous))
+		Expect(current.ConfigSerial).To(Equal(int64(4)))
+	})
+})
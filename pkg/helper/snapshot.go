@@ -0,0 +1,146 @@
+package helper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Upper bound of the compressed pre apply snapshot kept at the enactment, so
+// it does not get close to the object size limit
+const maxSnapshotSize = 64 * 1024
+
+const redactedValue = "<redacted>"
+
+// Current state keys with secret material, like 802.1x passwords or
+// pre-shared keys, any key ending with them is redacted too
+var sensitiveKeySuffixes = []string{"password", "psk", "secret", "pin", "private-key"}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, suffix := range sensitiveKeySuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSensitiveValues replaces the values of the sensitive keys of the
+// state, at any depth
+func redactSensitiveValues(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for k, v := range typedValue {
+			if isSensitiveKey(k) {
+				typedValue[k] = redactedValue
+			} else {
+				typedValue[k] = redactSensitiveValues(v)
+			}
+		}
+	case []interface{}:
+		for i, v := range typedValue {
+			typedValue[i] = redactSensitiveValues(v)
+		}
+	}
+	return value
+}
+
+// Sensitive keys along with their values at text like the nmstate output,
+// YAML or JSON, the value is the last group
+var sensitiveValuePattern = regexp.MustCompile(`(?i)("?[\w.-]*(?:` + strings.Join(sensitiveKeySuffixes, "|") + `)"?\s*:\s*)("[^"]*"|'[^']*'|[^\s,}\]]+)`)
+
+// redactState returns the state with the values of the sensitive keys
+// redacted, all of it if it cannot be parsed
+func redactState(state string) string {
+	var stateMap map[string]interface{}
+	err := yaml.Unmarshal([]byte(state), &stateMap)
+	if err != nil {
+		return redactedValue
+	}
+	redactedState, err := yaml.Marshal(redactSensitiveValues(stateMap))
+	if err != nil {
+		return redactedValue
+	}
+	return string(redactedState)
+}
+
+// redactSensitiveOutput replaces the values of the sensitive keys found at
+// the text, like the states echoed by nmstate at its output and errors
+func redactSensitiveOutput(text string) string {
+	return sensitiveValuePattern.ReplaceAllString(text, "${1}"+redactedValue)
+}
+
+// redactSensitiveError returns the error with the values of the sensitive
+// keys redacted, keeping its type so it is still told apart
+func redactSensitiveError(err error) error {
+	switch typedErr := err.(type) {
+	case nil, *applyTimeoutError:
+		return err
+	case *busyError:
+		return &busyError{err: redactSensitiveError(typedErr.err)}
+	case *checkpointError:
+		return &checkpointError{err: redactSensitiveError(typedErr.err)}
+	case *failureError:
+		return &failureError{code: typedErr.code, err: redactSensitiveError(typedErr.err)}
+	}
+	redactedMessage := redactSensitiveOutput(err.Error())
+	if redactedMessage == err.Error() {
+		return err
+	}
+	return errors.New(redactedMessage)
+}
+
+// newStateSnapshot redacts and compresses the state, omitting it if it's
+// still bigger than the bound
+func newStateSnapshot(state string, now time.Time, maxSize int) (nmstatev1alpha1.StateSnapshot, error) {
+	snapshot := nmstatev1alpha1.StateSnapshot{Time: metav1.Time{Time: now}}
+
+	var stateMap map[string]interface{}
+	err := yaml.Unmarshal([]byte(state), &stateMap)
+	if err != nil {
+		return snapshot, fmt.Errorf("failed parsing state snapshot: %v", err)
+	}
+	redactedState, err := yaml.Marshal(redactSensitiveValues(stateMap))
+	if err != nil {
+		return snapshot, fmt.Errorf("failed redacting state snapshot: %v", err)
+	}
+	snapshot.Size = len(redactedState)
+
+	compressed := bytes.Buffer{}
+	writer := gzip.NewWriter(&compressed)
+	_, err = writer.Write(redactedState)
+	if err != nil {
+		return snapshot, fmt.Errorf("failed compressing state snapshot: %v", err)
+	}
+	err = writer.Close()
+	if err != nil {
+		return snapshot, fmt.Errorf("failed compressing state snapshot: %v", err)
+	}
+
+	if compressed.Len() > maxSize {
+		snapshot.Omitted = true
+		return snapshot, nil
+	}
+	snapshot.Data = compressed.Bytes()
+	return snapshot, nil
+}
+
+// PreApplySnapshot takes the node current state to keep it along with the
+// apply about to start
+func PreApplySnapshot() (nmstatev1alpha1.StateSnapshot, error) {
+	state, err := show()
+	if err != nil {
+		return nmstatev1alpha1.StateSnapshot{}, err
+	}
+	return newStateSnapshot(state, time.Now(), maxSnapshotSize)
+}
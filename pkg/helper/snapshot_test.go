@@ -0,0 +1,98 @@
+package helper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pre apply snapshot", func() {
+	now := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+	state := `interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  802.1x:
+    identity: node01
+    password: s3cr3t
+    private-key-password: s3cr3t
+- name: wlan0
+  type: wifi
+  state: up
+  wifi:
+    psk: s3cr3t
+`
+
+	decompress := func(data []byte) string {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		Expect(err).ToNot(HaveOccurred())
+		content, err := ioutil.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		return string(content)
+	}
+
+	It("should compress the state with its sensitive values redacted", func() {
+		snapshot, err := newStateSnapshot(state, now, maxSnapshotSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshot.Time.Time).To(Equal(now))
+		Expect(snapshot.Omitted).To(BeFalse())
+		content := decompress(snapshot.Data)
+		Expect(snapshot.Size).To(Equal(len(content)))
+		Expect(content).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  802.1x:
+    identity: node01
+    password: <redacted>
+    private-key-password: <redacted>
+- name: wlan0
+  type: wifi
+  state: up
+  wifi:
+    psk: <redacted>
+`))
+		Expect(content).ToNot(ContainSubstring("s3cr3t"))
+	})
+
+	It("should omit snapshots exceeding the bound", func() {
+		snapshot, err := newStateSnapshot(state, now, 16)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(snapshot.Omitted).To(BeTrue())
+		Expect(snapshot.Data).To(BeEmpty())
+		Expect(snapshot.Size).ToNot(BeZero())
+	})
+
+	It("should fail with invalid states", func() {
+		_, err := newStateSnapshot("interfaces: [", now, maxSnapshotSize)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should redact the sensitive values of the states embedded at errors", func() {
+		Expect(redactState(state)).ToNot(ContainSubstring("s3cr3t"))
+		Expect(redactState(state)).To(ContainSubstring("identity: node01"))
+		Expect(redactState("interfaces: [")).To(Equal(redactedValue))
+	})
+
+	It("should redact the sensitive values of the nmstate output and errors", func() {
+		output := `desired: {"802.1x": {"identity": "node01", "password": "s3cr3t"}}
+    private-key-password: s3cr3t
+    psk: 's3cr3t', mtu: 1500`
+		redactedOutput := redactSensitiveOutput(output)
+		Expect(redactedOutput).ToNot(ContainSubstring("s3cr3t"))
+		Expect(redactedOutput).To(ContainSubstring(`"identity": "node01"`))
+		Expect(redactedOutput).To(ContainSubstring("mtu: 1500"))
+
+		err := redactSensitiveError(&busyError{err: errors.New("busy, psk: s3cr3t")})
+		Expect(IsBusy(err)).To(BeTrue())
+		Expect(err).To(MatchError("busy, psk: <redacted>"))
+		Expect(IsCheckpointFailed(redactSensitiveError(&checkpointError{err: errors.New("psk: s3cr3t")}))).To(BeTrue())
+		Expect(IsApplyTimeout(redactSensitiveError(&applyTimeoutError{timeout: time.Minute}))).To(BeTrue())
+		Expect(redactSensitiveError(nil)).To(BeNil())
+	})
+})
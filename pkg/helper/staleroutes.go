@@ -0,0 +1,259 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Protocol of the routes NetworkManager adds from the connections static
+// routes, the routes of other protocols belong to other components like
+// DHCP clients or routing daemons
+const staticRouteProtocol = "static"
+
+// The route tables that are never cleaned up, the unspecified one nmstate
+// takes as main, the default, main and local ones
+var reservedRouteTables = map[int]bool{0: true, 253: true, 254: true, 255: true}
+
+// tableRoute is a route of a route table managed by a policy, desired or
+// running at the node
+type tableRoute struct {
+	destination      string
+	nextHopAddress   string
+	nextHopInterface string
+	metric           int
+}
+
+// matches returns true if the running route is the desired one, the next
+// hop of the desired routes is optional
+func (desired tableRoute) matches(running tableRoute) bool {
+	return desired.destination == running.destination &&
+		(desired.nextHopAddress == "" || desired.nextHopAddress == running.nextHopAddress) &&
+		(desired.nextHopInterface == "" || desired.nextHopInterface == running.nextHopInterface)
+}
+
+func (r tableRoute) String() string {
+	route := r.destination
+	if r.nextHopAddress != "" {
+		route += " via " + r.nextHopAddress
+	}
+	if r.nextHopInterface != "" {
+		route += " dev " + r.nextHopInterface
+	}
+	return route
+}
+
+// normalizeRouteDestination returns the destination as a network, like
+// nmstate takes it, ip shows the default routes as default and the host
+// routes without prefix length
+func normalizeRouteDestination(destination string, ipv6 bool) (string, error) {
+	if destination == "default" {
+		if ipv6 {
+			return "::/0", nil
+		}
+		return "0.0.0.0/0", nil
+	}
+	if !strings.Contains(destination, "/") {
+		if ipv6 {
+			destination += "/128"
+		} else {
+			destination += "/32"
+		}
+	}
+	_, network, err := net.ParseCIDR(destination)
+	if err != nil {
+		return "", fmt.Errorf("invalid route destination %q: %v", destination, err)
+	}
+	return network.String(), nil
+}
+
+func normalizeNextHopAddress(address string) string {
+	if ip := net.ParseIP(address); ip != nil {
+		return ip.String()
+	}
+	return address
+}
+
+// getManagedRouteTables returns the route tables of the desired state
+// routes that are cleaned up, with their desired routes. The tables of the
+// absent routes are managed too, so removing all the routes of a table
+// cleans it up.
+func getManagedRouteTables(desiredState nmstatev1alpha1.State) (map[int][]tableRoute, error) {
+	tables := map[int][]tableRoute{}
+	if len(desiredState.Raw) == 0 {
+		return tables, nil
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return tables, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, route := range gjson.ParseBytes(desiredStateJSON).Get("routes.config").Array() {
+		table := int(route.Get("table-id").Int())
+		if reservedRouteTables[table] {
+			continue
+		}
+		if _, found := tables[table]; !found {
+			tables[table] = []tableRoute{}
+		}
+		if route.Get("state").String() == "absent" {
+			continue
+		}
+		destination := route.Get("destination").String()
+		desiredRoute := tableRoute{
+			nextHopAddress:   normalizeNextHopAddress(route.Get("next-hop-address").String()),
+			nextHopInterface: route.Get("next-hop-interface").String(),
+		}
+		desiredRoute.destination, err = normalizeRouteDestination(destination, strings.Contains(destination, ":"))
+		if err != nil {
+			return tables, err
+		}
+		tables[table] = append(tables[table], desiredRoute)
+	}
+	return tables, nil
+}
+
+// parseStaleRoutes returns the static routes from
+// "ip -j route show table <table>" that are not desired
+func parseStaleRoutes(output string, ipv6 bool, desiredRoutes []tableRoute) ([]tableRoute, error) {
+	staleRoutes := []tableRoute{}
+	// ip prints nothing for the tables without routes of the family
+	if strings.TrimSpace(output) == "" {
+		return staleRoutes, nil
+	}
+	routes := []ipRoute{}
+	err := json.Unmarshal([]byte(output), &routes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing ip routes: %v", err)
+	}
+
+	for _, route := range routes {
+		if route.Protocol != staticRouteProtocol {
+			continue
+		}
+		runningRoute := tableRoute{
+			nextHopAddress:   normalizeNextHopAddress(route.Gateway),
+			nextHopInterface: route.Device,
+			metric:           route.Metric,
+		}
+		runningRoute.destination, err = normalizeRouteDestination(route.Destination, ipv6)
+		if err != nil {
+			return nil, err
+		}
+		desired := false
+		for _, desiredRoute := range desiredRoutes {
+			if desiredRoute.matches(runningRoute) {
+				desired = true
+				break
+			}
+		}
+		if !desired {
+			staleRoutes = append(staleRoutes, runningRoute)
+		}
+	}
+	return staleRoutes, nil
+}
+
+// staleRoute is a static route removed from a route table cleaned up
+type staleRoute struct {
+	family string
+	table  int
+	route  tableRoute
+}
+
+func (r staleRoute) String() string {
+	return fmt.Sprintf("%s table %d", r.route, r.table)
+}
+
+// arguments returns the iproute arguments of the route action, the metric
+// is given so only the stale one is deleted if the table has the same route
+// with a different metric
+func (r staleRoute) arguments(action string) []string {
+	arguments := []string{r.family, "route", action, r.route.destination}
+	if r.route.nextHopAddress != "" {
+		arguments = append(arguments, "via", r.route.nextHopAddress)
+	}
+	if r.route.nextHopInterface != "" {
+		arguments = append(arguments, "dev", r.route.nextHopInterface)
+	}
+	return append(arguments, "table", strconv.Itoa(r.table), "metric", strconv.Itoa(r.route.metric), "proto", staticRouteProtocol)
+}
+
+// RouteTableCleanup collects the stale routes removed while applying a
+// desired state
+type RouteTableCleanup struct {
+	RemovedRoutes []string
+}
+
+// removeStaleRoutes deletes the static routes of the desired state route
+// tables, besides the main one, that are not at the desired state, like
+// the ones left by a previous desired state. It returns the removed ones,
+// along with the error if it fails removing the rest.
+func removeStaleRoutes(desiredState nmstatev1alpha1.State) ([]staleRoute, error) {
+	removedRoutes := []staleRoute{}
+	tables, err := getManagedRouteTables(desiredState)
+	if err != nil {
+		return removedRoutes, err
+	}
+
+	sortedTables := []int{}
+	for table := range tables {
+		sortedTables = append(sortedTables, table)
+	}
+	sort.Ints(sortedTables)
+
+	for _, table := range sortedTables {
+		for _, family := range []struct {
+			flag string
+			ipv6 bool
+		}{
+			{"-4", false},
+			{"-6", true},
+		} {
+			output, err := ip(family.flag, "-j", "route", "show", "table", strconv.Itoa(table))
+			// Recent kernels fail listing the tables that were never used
+			if err != nil && strings.Contains(err.Error(), "FIB table does not exist") {
+				continue
+			}
+			if err != nil {
+				return removedRoutes, fmt.Errorf("failed listing routes of table %d: %v", table, err)
+			}
+			staleRoutes, err := parseStaleRoutes(output, family.ipv6, tables[table])
+			if err != nil {
+				return removedRoutes, err
+			}
+			for _, route := range staleRoutes {
+				removed := staleRoute{family: family.flag, table: table, route: route}
+				_, err := ip(removed.arguments("del")...)
+				if err != nil {
+					return removedRoutes, fmt.Errorf("failed removing stale route %s: %v", removed, err)
+				}
+				removedRoutes = append(removedRoutes, removed)
+			}
+		}
+	}
+	return removedRoutes, nil
+}
+
+// restoreStaleRoutes adds back the stale routes removed from the route
+// tables, they are not part of the nmstate checkpoint
+func restoreStaleRoutes(removedRoutes []staleRoute) string {
+	output := ""
+	for _, removed := range removedRoutes {
+		ipOutput, err := ip(removed.arguments("replace")...)
+		output += fmt.Sprintf("stale route %s replace output: %s\n", removed, ipOutput)
+		if err != nil {
+			log.Info(fmt.Sprintf("failed restoring stale route %s: %v", removed, err))
+		}
+	}
+	return output
+}
@@ -0,0 +1,91 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Stale routes", func() {
+	It("should take the route tables of the desired state routes besides the main one", func() {
+		tables, err := getManagedRouteTables(nmstatev1alpha1.NewState(`routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-address: 192.0.2.1
+    next-hop-interface: eth1
+    table-id: 100
+  - destination: 2001:db8:1::/64
+    next-hop-interface: eth1
+    table-id: 100
+  - destination: 203.0.113.1
+    next-hop-interface: eth2
+    table-id: 200
+    state: absent
+  - destination: 0.0.0.0/0
+    next-hop-address: 192.0.2.1
+    next-hop-interface: eth1
+  - destination: 10.0.0.0/8
+    next-hop-interface: eth1
+    table-id: 254
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tables).To(Equal(map[int][]tableRoute{
+			100: {
+				{destination: "198.51.100.0/24", nextHopAddress: "192.0.2.1", nextHopInterface: "eth1"},
+				{destination: "2001:db8:1::/64", nextHopInterface: "eth1"},
+			},
+			200: {},
+		}))
+	})
+
+	It("should fail with invalid route destinations", func() {
+		_, err := getManagedRouteTables(nmstatev1alpha1.NewState(`routes:
+  config:
+  - destination: 198.51.100.0/33
+    table-id: 100
+`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return the static routes of the table that are not desired", func() {
+		staleRoutes, err := parseStaleRoutes(`[
+{"dst":"198.51.100.0/24","gateway":"192.0.2.1","dev":"eth1","protocol":"static","metric":150,"flags":[]},
+{"dst":"203.0.113.0/24","gateway":"192.0.2.1","dev":"eth1","protocol":"static","metric":150,"flags":[]},
+{"dst":"203.0.113.1","dev":"eth2","protocol":"static","metric":150,"flags":[]},
+{"dst":"default","gateway":"192.0.2.254","dev":"eth1","protocol":"bird","metric":32,"flags":[]},
+{"dst":"192.0.2.0/24","dev":"eth1","protocol":"kernel","scope":"link","metric":100,"flags":[]}
+]`, false, []tableRoute{
+			{destination: "198.51.100.0/24", nextHopAddress: "192.0.2.1", nextHopInterface: "eth1"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(staleRoutes).To(Equal([]tableRoute{
+			{destination: "203.0.113.0/24", nextHopAddress: "192.0.2.1", nextHopInterface: "eth1", metric: 150},
+			{destination: "203.0.113.1/32", nextHopInterface: "eth2", metric: 150},
+		}))
+		Expect(staleRoutes[0].String()).To(Equal("203.0.113.0/24 via 192.0.2.1 dev eth1"))
+	})
+
+	It("should match the desired routes without next hop whatever the running one", func() {
+		staleRoutes, err := parseStaleRoutes(`[
+{"dst":"2001:db8:1::/64","gateway":"2001:db8::1","dev":"eth1","protocol":"static","metric":1024,"flags":[]}
+]`, true, []tableRoute{
+			{destination: "2001:db8:1::/64"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(staleRoutes).To(BeEmpty())
+	})
+
+	It("should remove the stale routes and put them back with the same metric", func() {
+		removed := staleRoute{family: "-4", table: 100, route: tableRoute{destination: "203.0.113.0/24", nextHopAddress: "192.0.2.1", nextHopInterface: "eth1", metric: 150}}
+		Expect(removed.String()).To(Equal("203.0.113.0/24 via 192.0.2.1 dev eth1 table 100"))
+		Expect(removed.arguments("del")).To(Equal([]string{"-4", "route", "del", "203.0.113.0/24", "via", "192.0.2.1", "dev", "eth1", "table", "100", "metric", "150", "proto", "static"}))
+		Expect(removed.arguments("replace")).To(Equal([]string{"-4", "route", "replace", "203.0.113.0/24", "via", "192.0.2.1", "dev", "eth1", "table", "100", "metric", "150", "proto", "static"}))
+	})
+
+	It("should return no stale routes for tables without routes", func() {
+		staleRoutes, err := parseStaleRoutes("\n", false, []tableRoute{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(staleRoutes).To(BeEmpty())
+	})
+})
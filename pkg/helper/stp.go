@@ -0,0 +1,215 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// nmstate configures the linux bridges spanning tree but it does not report
+// its state, it's added to the current state bridges
+const stpStatusKey = "stp-status"
+const stpStateKey = "stp-state"
+
+// Kernel port states at /sys/class/net/<bridge>/brif/<port>/state
+var stpPortStates = map[string]string{
+	"0": "disabled",
+	"1": "listening",
+	"2": "learning",
+	"3": "forwarding",
+	"4": "blocking",
+}
+
+// bridgeSTP is the spanning tree state of a linux bridge, ports maps the
+// bridge ports to their state
+type bridgeSTP struct {
+	enabled      bool
+	bridgeID     string
+	rootID       string
+	rootPort     int
+	rootPathCost int
+	ports        map[string]string
+}
+
+func readSysfsValue(path string) string {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+func parseSTPPortState(state string) string {
+	if name, found := stpPortStates[state]; found {
+		return name
+	}
+	return "unknown"
+}
+
+// readBridgesSTP returns the spanning tree state of the linux bridges at the
+// net class directory
+func readBridgesSTP(netDir string) (map[string]bridgeSTP, error) {
+	bridgeDirs, err := filepath.Glob(filepath.Join(netDir, "*", "bridge"))
+	if err != nil {
+		return nil, fmt.Errorf("failed listing bridges: %v", err)
+	}
+
+	bridges := map[string]bridgeSTP{}
+	for _, bridgeDir := range bridgeDirs {
+		interfaceDir := filepath.Dir(bridgeDir)
+		stp := bridgeSTP{
+			enabled:  readSysfsValue(filepath.Join(bridgeDir, "stp_state")) != "0",
+			bridgeID: readSysfsValue(filepath.Join(bridgeDir, "bridge_id")),
+			rootID:   readSysfsValue(filepath.Join(bridgeDir, "root_id")),
+			ports:    map[string]string{},
+		}
+		stp.rootPort, _ = strconv.Atoi(readSysfsValue(filepath.Join(bridgeDir, "root_port")))
+		stp.rootPathCost, _ = strconv.Atoi(readSysfsValue(filepath.Join(bridgeDir, "root_path_cost")))
+
+		portDirs, err := filepath.Glob(filepath.Join(interfaceDir, "brif", "*"))
+		if err != nil {
+			return nil, fmt.Errorf("failed listing bridge %s ports: %v", filepath.Base(interfaceDir), err)
+		}
+		for _, portDir := range portDirs {
+			stp.ports[filepath.Base(portDir)] = parseSTPPortState(readSysfsValue(filepath.Join(portDir, "state")))
+		}
+		bridges[filepath.Base(interfaceDir)] = stp
+	}
+	return bridges, nil
+}
+
+// addBridgesSTP reports the spanning tree state at the current state linux
+// bridges with it enabled and at their ports
+func addBridgesSTP(currentState nmstatev1alpha1.State, bridges map[string]bridgeSTP) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return currentState, nil
+	}
+
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap || iface["type"] != "linux-bridge" {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		stp, found := bridges[name]
+		if !found || !stp.enabled {
+			continue
+		}
+		bridge, isMap := iface["bridge"].(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		bridge[stpStatusKey] = map[string]interface{}{
+			"bridge-id":      stp.bridgeID,
+			"root-id":        stp.rootID,
+			"root-port":      stp.rootPort,
+			"root-path-cost": stp.rootPathCost,
+		}
+		ports, _ := bridge["port"].([]interface{})
+		for _, port := range ports {
+			if port, isMap := port.(map[string]interface{}); isMap {
+				portName, _ := port["name"].(string)
+				if portState, found := stp.ports[portName]; found {
+					port[stpStateKey] = portState
+				}
+			}
+		}
+	}
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+func reportBridgesSTP(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	bridges, err := readBridgesSTP(sysClassNetDir)
+	if err != nil {
+		return currentState, err
+	}
+	return addBridgesSTP(currentState, bridges)
+}
+
+// parseDefaultRouteInterfaces returns the interfaces of the default routes
+// listed by ip -j route show default
+func parseDefaultRouteInterfaces(output string) (map[string]bool, error) {
+	routes := []struct {
+		Dev string `json:"dev"`
+	}{}
+	err := json.Unmarshal([]byte(output), &routes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing default routes: %v", err)
+	}
+	interfaces := map[string]bool{}
+	for _, route := range routes {
+		if route.Dev != "" {
+			interfaces[route.Dev] = true
+		}
+	}
+	return interfaces, nil
+}
+
+// stpManagementBridges returns the desired state bridges enabling the
+// spanning tree while they carry the node default route, their ports take
+// the forwarding delay to learn before forwarding the node traffic again
+func stpManagementBridges(desiredState nmstatev1alpha1.State, bridges map[string]bridgeSTP, defaultRouteInterfaces map[string]bool) ([]string, error) {
+	managementBridges := []string{}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return managementBridges, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		name := iface.Get("name").String()
+		if iface.Get("type").String() != "linux-bridge" || !iface.Get("bridge.options.stp.enabled").Bool() {
+			continue
+		}
+		current, found := bridges[name]
+		if found && !current.enabled && defaultRouteInterfaces[name] {
+			managementBridges = append(managementBridges, name)
+		}
+	}
+	return managementBridges, nil
+}
+
+// STPManagementBridges returns the bridges carrying the node default route
+// the desired state enables the spanning tree at, it has to be called before
+// applying it to compare with the node spanning tree
+func STPManagementBridges(desiredState nmstatev1alpha1.State) ([]string, error) {
+	bridges, err := readBridgesSTP(sysClassNetDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed checking bridges spanning tree: %v", err)
+	}
+	defaultRouteInterfaces := map[string]bool{}
+	for _, family := range []string{"-4", "-6"} {
+		output, err := ip(family, "-j", "route", "show", "default")
+		if err != nil {
+			return nil, fmt.Errorf("failed checking default routes interfaces: %v", err)
+		}
+		familyInterfaces, err := parseDefaultRouteInterfaces(output)
+		if err != nil {
+			return nil, err
+		}
+		for iface := range familyInterfaces {
+			defaultRouteInterfaces[iface] = true
+		}
+	}
+	return stpManagementBridges(desiredState, bridges, defaultRouteInterfaces)
+}
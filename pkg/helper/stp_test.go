@@ -0,0 +1,142 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Bridges spanning tree", func() {
+	var netDir string
+
+	writeSysfs := func(path string, value string) {
+		Expect(os.MkdirAll(filepath.Dir(filepath.Join(netDir, path)), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(netDir, path), []byte(value+"\n"), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		netDir, err = ioutil.TempDir("", "sys-class-net")
+		Expect(err).ToNot(HaveOccurred())
+		writeSysfs("br1/bridge/stp_state", "1")
+		writeSysfs("br1/bridge/bridge_id", "8000.52540012b6a1")
+		writeSysfs("br1/bridge/root_id", "1000.525400aa0001")
+		writeSysfs("br1/bridge/root_port", "1")
+		writeSysfs("br1/bridge/root_path_cost", "100")
+		writeSysfs("br1/brif/eth1/state", "3")
+		writeSysfs("br1/brif/eth2/state", "4")
+		writeSysfs("br2/bridge/stp_state", "0")
+		writeSysfs("br2/brif/eth3/state", "3")
+		writeSysfs("eth1/carrier", "1")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(netDir)
+	})
+
+	It("should read the bridges spanning tree state", func() {
+		bridges, err := readBridgesSTP(netDir)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bridges).To(HaveLen(2))
+		Expect(bridges["br1"]).To(Equal(bridgeSTP{
+			enabled:      true,
+			bridgeID:     "8000.52540012b6a1",
+			rootID:       "1000.525400aa0001",
+			rootPort:     1,
+			rootPathCost: 100,
+			ports:        map[string]string{"eth1": "forwarding", "eth2": "blocking"},
+		}))
+		Expect(bridges["br2"].enabled).To(BeFalse())
+	})
+
+	It("should report the spanning tree state at the bridges with it enabled", func() {
+		bridges, err := readBridgesSTP(netDir)
+		Expect(err).ToNot(HaveOccurred())
+		reportedState, err := addBridgesSTP(nmstatev1alpha1.NewState(`interfaces:
+- name: br1
+  type: linux-bridge
+  state: up
+  bridge:
+    options:
+      stp:
+        enabled: true
+    port:
+    - name: eth1
+      stp-priority: 32
+    - name: eth2
+      stp-priority: 32
+- name: br2
+  type: linux-bridge
+  state: up
+  bridge:
+    port:
+    - name: eth3
+`), bridges)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reportedState.String()).To(MatchYAML(`interfaces:
+- name: br1
+  type: linux-bridge
+  state: up
+  bridge:
+    options:
+      stp:
+        enabled: true
+    stp-status:
+      bridge-id: 8000.52540012b6a1
+      root-id: 1000.525400aa0001
+      root-port: 1
+      root-path-cost: 100
+    port:
+    - name: eth1
+      stp-priority: 32
+      stp-state: forwarding
+    - name: eth2
+      stp-priority: 32
+      stp-state: blocking
+- name: br2
+  type: linux-bridge
+  state: up
+  bridge:
+    port:
+    - name: eth3
+`))
+	})
+
+	It("should warn enabling the spanning tree only at bridges carrying the default route", func() {
+		bridges := map[string]bridgeSTP{"br1": {enabled: true}, "br2": {}, "br3": {}}
+		defaultRouteInterfaces, err := parseDefaultRouteInterfaces(`[{"dst":"default","gateway":"192.168.66.2","dev":"br2","protocol":"dhcp"},{"dst":"default","gateway":"192.168.66.2","dev":"br1"}]`)
+		Expect(err).ToNot(HaveOccurred())
+		desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: br1
+  type: linux-bridge
+  bridge:
+    options:
+      stp:
+        enabled: true
+- name: br2
+  type: linux-bridge
+  bridge:
+    options:
+      stp:
+        enabled: true
+- name: br3
+  type: linux-bridge
+  bridge:
+    options:
+      stp:
+        enabled: true
+- name: br4
+  type: linux-bridge
+  bridge:
+    options:
+      stp:
+        enabled: false
+`)
+		Expect(stpManagementBridges(desiredState, bridges, defaultRouteInterfaces)).To(Equal([]string{"br2"}))
+	})
+})
@@ -0,0 +1,383 @@
+package helper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const (
+	sysctlKey = "sysctl"
+
+	// Value resetting the interface sysctl to the one new interfaces get
+	sysctlDefault = "default"
+
+	// Directory of the per family configuration with the defaults of
+	// new interfaces
+	sysctlDefaultConf = "default"
+)
+
+// The handler runs at the host network namespace so these are the host
+// interfaces sysctls
+var sysctlNetDir = "/proc/sys/net"
+
+// sysctlFamilies are the address families with the interface sysctls the
+// desired state can set, NetworkManager sets the rest of them at the
+// interfaces it manages. accept_ra is there for the ones it does not manage,
+// the IPv6 global forwarding stops their router advertisements otherwise.
+var sysctlFamilies = []string{"ipv4", "ipv6"}
+
+var interfaceSysctls = map[string][]string{
+	"ipv4": {"arp_announce", "arp_ignore", "forwarding", "proxy_arp", "rp_filter"},
+	"ipv6": {"accept_dad", "accept_ra", "dad_transmits", "disable_ipv6", "forwarding"},
+}
+
+func isInterfaceSysctl(family string, name string) bool {
+	for _, sysctl := range interfaceSysctls[family] {
+		if sysctl == name {
+			return true
+		}
+	}
+	return false
+}
+
+// interfaceSysctl is a desired state interface sysctl, nmstate does not
+// support them, they are written to /proc/sys/net/<family>/conf/<iface>
+type interfaceSysctl struct {
+	iface  string
+	family string
+	name   string
+	value  string
+}
+
+func (s interfaceSysctl) path(netDir string, conf string) string {
+	return filepath.Join(netDir, s.family, "conf", conf, s.name)
+}
+
+// getSysctls returns the desired state interfaces sysctls, failing with
+// the ones not supported or without a number or default value. Absent
+// interfaces are ignored.
+func getSysctls(desiredState nmstatev1alpha1.State) ([]interfaceSysctl, error) {
+	sysctls := []interfaceSysctl{}
+	if len(desiredState.Raw) == 0 {
+		return sysctls, nil
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return sysctls, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		if iface.Get("state").String() == "absent" {
+			continue
+		}
+		name := iface.Get("name").String()
+		if iface.Get(sysctlKey).Exists() && (name == "" || name == "." || name == ".." || strings.Contains(name, "/")) {
+			return sysctls, fmt.Errorf("invalid interface name %q with sysctls", name)
+		}
+		var familyErr error
+		iface.Get(sysctlKey).ForEach(func(family, familySysctls gjson.Result) bool {
+			familySysctls.ForEach(func(sysctl, value gjson.Result) bool {
+				if !isInterfaceSysctl(family.String(), sysctl.String()) {
+					familyErr = fmt.Errorf("unsupported sysctl %s.%s at interface %s", family.String(), sysctl.String(), name)
+					return false
+				}
+				if value.Type != gjson.Number && value.String() != sysctlDefault {
+					familyErr = fmt.Errorf("invalid sysctl %s.%s value %q at interface %s, it has to be a number or %s", family.String(), sysctl.String(), value.String(), name, sysctlDefault)
+					return false
+				}
+				sysctls = append(sysctls, interfaceSysctl{iface: name, family: family.String(), name: sysctl.String(), value: value.String()})
+				return true
+			})
+			return familyErr == nil
+		})
+		if familyErr != nil {
+			return sysctls, familyErr
+		}
+	}
+	return sysctls, nil
+}
+
+// stripSysctls removes the sysctls from the desired state interfaces, the
+// interfaces left with only their name, added to reset the sysctls of
+// interfaces dropped from the desired state, are removed too
+func stripSysctls(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return stripInterfacesSysctls(desiredState, func(map[string]interface{}) bool { return true })
+}
+
+// stripDefaultSysctls removes the sysctls reset to default from the
+// desired state interfaces, they cannot be compared with the reported
+// values
+func stripDefaultSysctls(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return stripInterfacesSysctls(desiredState, func(ifaceSysctls map[string]interface{}) bool {
+		for family, familySysctls := range ifaceSysctls {
+			familySysctlsMap, _ := familySysctls.(map[string]interface{})
+			for name, value := range familySysctlsMap {
+				if value == sysctlDefault {
+					delete(familySysctlsMap, name)
+				}
+			}
+			if len(familySysctlsMap) == 0 {
+				delete(ifaceSysctls, family)
+			}
+		}
+		return len(ifaceSysctls) == 0
+	})
+}
+
+// stripInterfacesSysctls strips the sysctls of the interfaces, strip
+// removes the ones to remove and returns true if the interface is left
+// without sysctls
+func stripInterfacesSysctls(desiredState nmstatev1alpha1.State, strip func(map[string]interface{}) bool) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return desiredState, nil
+	}
+
+	strippedInterfaces := []interface{}{}
+	stripped := false
+	for _, iface := range interfaces {
+		ifaceMap, isMap := iface.(map[string]interface{})
+		if !isMap {
+			strippedInterfaces = append(strippedInterfaces, iface)
+			continue
+		}
+		if _, hasSysctl := ifaceMap[sysctlKey]; !hasSysctl {
+			strippedInterfaces = append(strippedInterfaces, iface)
+			continue
+		}
+		stripped = true
+		ifaceSysctls, _ := ifaceMap[sysctlKey].(map[string]interface{})
+		if ifaceSysctls != nil && !strip(ifaceSysctls) {
+			strippedInterfaces = append(strippedInterfaces, iface)
+			continue
+		}
+		delete(ifaceMap, sysctlKey)
+		if _, named := ifaceMap["name"]; named && len(ifaceMap) == 1 {
+			continue
+		}
+		strippedInterfaces = append(strippedInterfaces, iface)
+	}
+	if !stripped {
+		return desiredState, nil
+	}
+	state["interfaces"] = strippedInterfaces
+
+	strippedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: strippedState}, nil
+}
+
+// applySysctls writes the interfaces sysctls, the default ones take the
+// value of the per family default configuration
+func applySysctls(netDir string, sysctls []interfaceSysctl) (string, error) {
+	output := ""
+	for _, sysctl := range sysctls {
+		value := sysctl.value
+		if value == sysctlDefault {
+			defaultValue, err := ioutil.ReadFile(sysctl.path(netDir, sysctlDefaultConf))
+			if err != nil {
+				return output, fmt.Errorf("failed reading default sysctl %s.%s: %v", sysctl.family, sysctl.name, err)
+			}
+			value = strings.TrimSpace(string(defaultValue))
+		}
+		err := ioutil.WriteFile(sysctl.path(netDir, sysctl.iface), []byte(value), 0644)
+		if err != nil {
+			return output, fmt.Errorf("failed setting interface %s sysctl %s.%s: %v", sysctl.iface, sysctl.family, sysctl.name, err)
+		}
+		output += fmt.Sprintf("interface %s sysctl %s.%s set to %s\n", sysctl.iface, sysctl.family, sysctl.name, value)
+	}
+	return output, nil
+}
+
+// readInterfacesSysctls returns the current value of the sysctls given, the
+// ones of interfaces the kernel does not have yet are left out, nmstate
+// removes these interfaces on rollback
+func readInterfacesSysctls(netDir string, sysctls []interfaceSysctl) []interfaceSysctl {
+	current := []interfaceSysctl{}
+	for _, sysctl := range sysctls {
+		content, err := ioutil.ReadFile(sysctl.path(netDir, sysctl.iface))
+		if err != nil {
+			continue
+		}
+		sysctl.value = strings.TrimSpace(string(content))
+		current = append(current, sysctl)
+	}
+	return current
+}
+
+// restoreSysctls writes back the interfaces sysctls read before applying
+// the desired state, they are not part of the nmstate checkpoint
+func restoreSysctls(netDir string, previousSysctls []interfaceSysctl) string {
+	output, err := applySysctls(netDir, previousSysctls)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed restoring interfaces sysctls: %v", err))
+	}
+	return output
+}
+
+// RemoveDroppedSysctls sets to default at the desired state the interfaces
+// sysctls present at the previously applied one that are not at the
+// desired state anymore, so dropping them from the policy reverts them.
+// The interfaces dropped from the desired state are added back with only
+// the sysctls to reset.
+func RemoveDroppedSysctls(previousState nmstatev1alpha1.State, desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	previousSysctls, err := getSysctls(previousState)
+	if err != nil || len(previousSysctls) == 0 {
+		return desiredState, err
+	}
+	sysctls, err := getSysctls(desiredState)
+	if err != nil {
+		return desiredState, err
+	}
+
+	desiredSysctls := map[interfaceSysctl]bool{}
+	for _, sysctl := range sysctls {
+		sysctl.value = ""
+		desiredSysctls[sysctl] = true
+	}
+
+	droppedSysctls := []interfaceSysctl{}
+	for _, sysctl := range previousSysctls {
+		// Already reset, no need to reset it again
+		if sysctl.value == sysctlDefault {
+			continue
+		}
+		sysctl.value = ""
+		if !desiredSysctls[sysctl] {
+			droppedSysctls = append(droppedSysctls, sysctl)
+		}
+	}
+	if len(droppedSysctls) == 0 {
+		return desiredState, nil
+	}
+
+	var state map[string]interface{}
+	err = yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	interfaces, _ := state["interfaces"].([]interface{})
+
+	desiredInterfaces := map[string]map[string]interface{}{}
+	for _, iface := range interfaces {
+		if name, named := itemName(iface); named {
+			desiredInterfaces[name] = iface.(map[string]interface{})
+		}
+	}
+
+	addedInterfaces := []string{}
+	for _, sysctl := range droppedSysctls {
+		iface, found := desiredInterfaces[sysctl.iface]
+		if !found {
+			iface = map[string]interface{}{"name": sysctl.iface}
+			desiredInterfaces[sysctl.iface] = iface
+			addedInterfaces = append(addedInterfaces, sysctl.iface)
+		}
+		ifaceSysctls, _ := iface[sysctlKey].(map[string]interface{})
+		if ifaceSysctls == nil {
+			ifaceSysctls = map[string]interface{}{}
+			iface[sysctlKey] = ifaceSysctls
+		}
+		familySysctls, _ := ifaceSysctls[sysctl.family].(map[string]interface{})
+		if familySysctls == nil {
+			familySysctls = map[string]interface{}{}
+			ifaceSysctls[sysctl.family] = familySysctls
+		}
+		familySysctls[sysctl.name] = sysctlDefault
+	}
+	sort.Strings(addedInterfaces)
+	for _, name := range addedInterfaces {
+		interfaces = append(interfaces, desiredInterfaces[name])
+	}
+	state["interfaces"] = interfaces
+
+	removedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: removedState}, nil
+}
+
+// readSysctls returns the supported sysctls of the interface, it has none
+// if the kernel does not have its configuration, like the ipv6 one of
+// interfaces with IPv6 disabled
+func readSysctls(netDir string, iface string) map[string]interface{} {
+	sysctls := map[string]interface{}{}
+	for _, family := range sysctlFamilies {
+		familySysctls := map[string]interface{}{}
+		for _, name := range interfaceSysctls[family] {
+			sysctl := interfaceSysctl{iface: iface, family: family, name: name}
+			content, err := ioutil.ReadFile(sysctl.path(netDir, iface))
+			if err != nil {
+				continue
+			}
+			value, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+			if err != nil {
+				continue
+			}
+			familySysctls[name] = value
+		}
+		if len(familySysctls) > 0 {
+			sysctls[family] = familySysctls
+		}
+	}
+	return sysctls
+}
+
+// addSysctls reports the supported sysctls at the current state interfaces
+func addSysctls(currentState nmstatev1alpha1.State, netDir string) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return currentState, nil
+	}
+
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		sysctls := readSysctls(netDir, name)
+		if len(sysctls) == 0 {
+			continue
+		}
+		iface[sysctlKey] = sysctls
+	}
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+func reportSysctls(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return addSysctls(currentState, sysctlNetDir)
+}
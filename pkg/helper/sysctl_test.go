@@ -0,0 +1,167 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Interfaces sysctls", func() {
+	const eth1 = `- name: eth1
+  type: ethernet
+  state: up
+`
+	const eth1Sysctls = `- name: eth1
+  type: ethernet
+  state: up
+  sysctl:
+    ipv4:
+      rp_filter: 2
+      forwarding: 1
+    ipv6:
+      forwarding: 1
+`
+
+	It("should take the interfaces sysctls and strip them from the nmstate desired state", func() {
+		desiredState := nmstatev1alpha1.NewState("interfaces:\n" + eth1Sysctls)
+
+		sysctls, err := getSysctls(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sysctls).To(ConsistOf(
+			interfaceSysctl{iface: "eth1", family: "ipv4", name: "rp_filter", value: "2"},
+			interfaceSysctl{iface: "eth1", family: "ipv4", name: "forwarding", value: "1"},
+			interfaceSysctl{iface: "eth1", family: "ipv6", name: "forwarding", value: "1"},
+		))
+
+		strippedState, err := stripSysctls(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML("interfaces:\n" + eth1))
+	})
+
+	It("should remove the interfaces with only sysctls from the nmstate desired state", func() {
+		strippedState, err := stripSysctls(nmstatev1alpha1.NewState("interfaces:\n" + eth1 + "- name: eth2\n  sysctl:\n    ipv4:\n      rp_filter: default\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML("interfaces:\n" + eth1))
+	})
+
+	DescribeTable("with invalid sysctls",
+		func(sysctl string) {
+			_, err := getSysctls(nmstatev1alpha1.NewState("interfaces:\n- name: eth1\n  sysctl:\n" + sysctl))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unsupported sysctl", "    ipv4:\n      accept_local: 1\n"),
+		Entry("unsupported family", "    mpls:\n      input: 1\n"),
+		Entry("value not a number", "    ipv4:\n      rp_filter: strict\n"),
+	)
+
+	It("should fail with sysctls at interfaces with path names", func() {
+		_, err := getSysctls(nmstatev1alpha1.NewState("interfaces:\n- name: ../all\n  sysctl:\n    ipv4:\n      forwarding: 1\n"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	DescribeTable("dropped from the desired state",
+		func(previousState string, desiredState string, expectedState string) {
+			removedState, err := RemoveDroppedSysctls(nmstatev1alpha1.NewState(previousState), nmstatev1alpha1.NewState(desiredState))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removedState.String()).To(MatchYAML(expectedState))
+		},
+		Entry("without previous desired state, should keep it",
+			"",
+			"interfaces:\n"+eth1Sysctls,
+			"interfaces:\n"+eth1Sysctls,
+		),
+		Entry("still setting the sysctls, should keep them",
+			"interfaces:\n"+eth1Sysctls,
+			"interfaces:\n"+eth1Sysctls,
+			"interfaces:\n"+eth1Sysctls,
+		),
+		Entry("without some of the sysctls, should reset them",
+			"interfaces:\n"+eth1Sysctls,
+			"interfaces:\n"+eth1+"  sysctl:\n    ipv4:\n      rp_filter: 2\n",
+			"interfaces:\n"+eth1+"  sysctl:\n    ipv4:\n      rp_filter: 2\n      forwarding: default\n    ipv6:\n      forwarding: default\n",
+		),
+		Entry("without the interface, should add it back to reset them",
+			"interfaces:\n"+eth1Sysctls,
+			"interfaces:\n- name: eth2\n  type: ethernet\n  state: up\n",
+			"interfaces:\n- name: eth2\n  type: ethernet\n  state: up\n- name: eth1\n  sysctl:\n    ipv4:\n      rp_filter: default\n      forwarding: default\n    ipv6:\n      forwarding: default\n",
+		),
+		Entry("with the sysctls already reset, should not reset them again",
+			"interfaces:\n- name: eth1\n  sysctl:\n    ipv4:\n      rp_filter: default\n",
+			"interfaces:\n"+eth1,
+			"interfaces:\n"+eth1,
+		),
+	)
+
+	Context("at the node", func() {
+		var netDir string
+
+		writeSysctl := func(family string, conf string, name string, value string) {
+			dir := filepath.Join(netDir, family, "conf", conf)
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, name), []byte(value+"\n"), 0644)).To(Succeed())
+		}
+
+		readSysctl := func(family string, conf string, name string) string {
+			content, err := ioutil.ReadFile(filepath.Join(netDir, family, "conf", conf, name))
+			Expect(err).ToNot(HaveOccurred())
+			return string(content)
+		}
+
+		BeforeEach(func() {
+			var err error
+			netDir, err = ioutil.TempDir("", "proc-sys-net")
+			Expect(err).ToNot(HaveOccurred())
+			writeSysctl("ipv4", "default", "rp_filter", "1")
+			writeSysctl("ipv4", "eth1", "rp_filter", "1")
+			writeSysctl("ipv4", "eth1", "forwarding", "0")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(netDir)
+		})
+
+		It("should set the values and reset the default ones", func() {
+			_, err := applySysctls(netDir, []interfaceSysctl{
+				{iface: "eth1", family: "ipv4", name: "rp_filter", value: "2"},
+				{iface: "eth1", family: "ipv4", name: "forwarding", value: "1"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(readSysctl("ipv4", "eth1", "rp_filter")).To(Equal("2"))
+			Expect(readSysctl("ipv4", "eth1", "forwarding")).To(Equal("1"))
+
+			_, err = applySysctls(netDir, []interfaceSysctl{
+				{iface: "eth1", family: "ipv4", name: "rp_filter", value: sysctlDefault},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(readSysctl("ipv4", "eth1", "rp_filter")).To(Equal("1"))
+		})
+
+		It("should restore the values read before setting them", func() {
+			sysctls := []interfaceSysctl{
+				{iface: "eth1", family: "ipv4", name: "rp_filter", value: "2"},
+				{iface: "eth2", family: "ipv4", name: "rp_filter", value: "2"},
+			}
+			previousSysctls := readInterfacesSysctls(netDir, sysctls)
+			Expect(previousSysctls).To(Equal([]interfaceSysctl{{iface: "eth1", family: "ipv4", name: "rp_filter", value: "1"}}))
+
+			_, err := applySysctls(netDir, sysctls[:1])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(readSysctl("ipv4", "eth1", "rp_filter")).To(Equal("2"))
+
+			restoreSysctls(netDir, previousSysctls)
+			Expect(readSysctl("ipv4", "eth1", "rp_filter")).To(Equal("1"))
+		})
+
+		It("should report the current values at the current state interfaces", func() {
+			reportedState, err := addSysctls(nmstatev1alpha1.NewState("interfaces:\n"+eth1+"- name: lo\n  type: unknown\n  state: up\n"), netDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reportedState.String()).To(MatchYAML("interfaces:\n" + eth1 + "  sysctl:\n    ipv4:\n      forwarding: 0\n      rp_filter: 1\n- name: lo\n  type: unknown\n  state: up\n"))
+		})
+	})
+})
@@ -0,0 +1,74 @@
+package helper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// The handler does not run at its own UTS namespace, this is the host kernel
+const kernelReleaseFile = "/proc/sys/kernel/osrelease"
+
+var (
+	// nmstate is installed at the handler image, its version does not
+	// change while it's running so it's only taken once
+	nmstateVersion     string
+	nmstateVersionOnce sync.Once
+	nmstateVersionErr  error
+)
+
+func readKernelVersion(path string) (string, error) {
+	release, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed reading kernel release: %v", err)
+	}
+	return strings.TrimSpace(string(release)), nil
+}
+
+// showNetworkManagerVersion returns the version of the NetworkManager
+// daemon running at the node, not the one of the handler nmcli
+func showNetworkManagerVersion() (string, error) {
+	output, err := nmcli("-g", "VERSION", "general")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+func showNmstateVersion() (string, error) {
+	nmstateVersionOnce.Do(func() {
+		var output string
+		output, nmstateVersionErr = runNmstatectl([]string{"--version"}, "")
+		nmstateVersion = strings.TrimSpace(output)
+	})
+	return nmstateVersion, nmstateVersionErr
+}
+
+// NmstateVersion returns the version of the nmstate installed at the handler
+// image, the one applying the desired states
+func NmstateVersion() (string, error) {
+	return showNmstateVersion()
+}
+
+// showSystemInfo returns the versions of the node network stack, the ones
+// failing to be retrieved are left empty
+func showSystemInfo() *nmstatev1alpha1.SystemInfo {
+	systemInfo := nmstatev1alpha1.SystemInfo{}
+	var err error
+	systemInfo.KernelVersion, err = readKernelVersion(kernelReleaseFile)
+	if err != nil {
+		log.Error(err, "failed retrieving kernel version, not reporting it")
+	}
+	systemInfo.NetworkManagerVersion, err = showNetworkManagerVersion()
+	if err != nil {
+		log.Error(err, "failed retrieving NetworkManager version, not reporting it")
+	}
+	systemInfo.NmstateVersion, err = showNmstateVersion()
+	if err != nil {
+		log.Error(err, "failed retrieving nmstate version, not reporting it")
+	}
+	return &systemInfo
+}
@@ -0,0 +1,37 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("System info", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "systeminfo")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should read the kernel version without the trailing new line", func() {
+		releaseFile := filepath.Join(dir, "osrelease")
+		Expect(ioutil.WriteFile(releaseFile, []byte("4.18.0-193.el8.x86_64\n"), 0644)).To(Succeed())
+		version, err := readKernelVersion(releaseFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).To(Equal("4.18.0-193.el8.x86_64"))
+	})
+
+	It("should fail if the kernel release cannot be read", func() {
+		_, err := readKernelVersion(filepath.Join(dir, "missing"))
+		Expect(err).To(HaveOccurred())
+	})
+})
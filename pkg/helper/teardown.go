@@ -0,0 +1,153 @@
+package helper
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Interface types created on top of a base interface, they have to be
+// removed before it
+var baseInterfaceTypes = []string{"vlan", "vxlan", "infiniband", "mac-vlan", "mac-vtap", greInterfaceType, ipipInterfaceType}
+
+// baseInterface returns the interface the given one is created on top of,
+// empty if it has none
+func baseInterface(iface map[string]interface{}) string {
+	for _, interfaceType := range baseInterfaceTypes {
+		config, hasConfig := iface[interfaceType].(map[string]interface{})
+		if !hasConfig {
+			continue
+		}
+		if base, hasBase := config["base-iface"].(string); hasBase {
+			return base
+		}
+	}
+	return ""
+}
+
+func stateInterfaces(state nmstatev1alpha1.State) (map[string]interface{}, []interface{}, error) {
+	var stateMap map[string]interface{}
+	err := yaml.Unmarshal(state.Raw, &stateMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	interfaces, _ := stateMap["interfaces"].([]interface{})
+	return stateMap, interfaces, nil
+}
+
+// orderTeardown returns the desired state with the absent interfaces
+// sorted so the ones on top of others are removed first. It fails if an
+// absent interface has interfaces on top of it that are kept at the node.
+func orderTeardown(desiredState nmstatev1alpha1.State, currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	desiredStateMap, desiredInterfaces, err := stateInterfaces(desiredState)
+	if err != nil {
+		return desiredState, fmt.Errorf("error parsing desired state: %v", err)
+	}
+	_, currentInterfaces, err := stateInterfaces(currentState)
+	if err != nil {
+		return desiredState, fmt.Errorf("error parsing current state: %v", err)
+	}
+
+	// Base interfaces once the desired state is applied, the desired state
+	// interfaces not setting it keep the current one
+	bases := map[string]string{}
+	for _, iface := range currentInterfaces {
+		iface, _ := iface.(map[string]interface{})
+		name, _ := iface["name"].(string)
+		if base := baseInterface(iface); base != "" {
+			bases[name] = base
+		}
+	}
+	absent := map[string]bool{}
+	absentIndexes := []int{}
+	for i, iface := range desiredInterfaces {
+		iface, _ := iface.(map[string]interface{})
+		name, _ := iface["name"].(string)
+		if iface["state"] == "absent" {
+			absent[name] = true
+			absentIndexes = append(absentIndexes, i)
+		}
+		if base := baseInterface(iface); base != "" {
+			bases[name] = base
+		}
+	}
+	if len(absent) == 0 {
+		return desiredState, nil
+	}
+
+	children := []string{}
+	for child := range bases {
+		children = append(children, child)
+	}
+	sort.Strings(children)
+	for _, child := range children {
+		base := bases[child]
+		if absent[base] && !absent[child] {
+			return desiredState, fmt.Errorf("interface %s cannot be removed, %s is on top of it and is not removed, set it as absent too", base, child)
+		}
+	}
+
+	// Absent interfaces on top of more absent ones are removed first, the
+	// depth is bounded in case of loops at the base interfaces
+	depth := func(name string) int {
+		depth := 0
+		for base := bases[name]; absent[base] && depth < len(absent); base = bases[base] {
+			depth++
+		}
+		return depth
+	}
+	orderedAbsent := make([]interface{}, len(absentIndexes))
+	for i, index := range absentIndexes {
+		orderedAbsent[i] = desiredInterfaces[index]
+	}
+	sort.SliceStable(orderedAbsent, func(i, j int) bool {
+		iName, _ := orderedAbsent[i].(map[string]interface{})["name"].(string)
+		jName, _ := orderedAbsent[j].(map[string]interface{})["name"].(string)
+		return depth(iName) > depth(jName)
+	})
+
+	reordered := false
+	for i, index := range absentIndexes {
+		if desiredInterfaces[index].(map[string]interface{})["name"] != orderedAbsent[i].(map[string]interface{})["name"] {
+			reordered = true
+		}
+		desiredInterfaces[index] = orderedAbsent[i]
+	}
+	if !reordered {
+		return desiredState, nil
+	}
+	desiredStateMap["interfaces"] = desiredInterfaces
+	orderedState, err := yaml.Marshal(desiredStateMap)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: orderedState}, nil
+}
+
+// hasAbsentInterfaces returns true if the desired state removes interfaces
+func hasAbsentInterfaces(desiredState nmstatev1alpha1.State) (bool, error) {
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return false, fmt.Errorf("error converting desired state to JSON: %v", err)
+	}
+	return gjson.ParseBytes(desiredStateJSON).Get("interfaces.#(state==absent)").Exists(), nil
+}
+
+// checkTeardown orders the removal of the desired state absent interfaces
+// against the node current state
+func checkTeardown(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	removes, err := hasAbsentInterfaces(desiredState)
+	if err != nil || !removes {
+		return desiredState, err
+	}
+
+	currentState, err := show()
+	if err != nil {
+		return desiredState, err
+	}
+	return orderTeardown(desiredState, nmstatev1alpha1.State{Raw: []byte(currentState)})
+}
@@ -0,0 +1,109 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Interfaces teardown order", func() {
+	const currentState = `interfaces:
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: active-backup
+    slaves:
+    - eth1
+    - eth2
+- name: bond0.100
+  type: vlan
+  state: up
+  vlan:
+    base-iface: bond0
+    id: 100
+- name: eth1
+  type: ethernet
+  state: up
+`
+
+	It("should remove the VLAN before the bond under it", func() {
+		orderedState, err := orderTeardown(nmstatev1alpha1.NewState(`interfaces:
+- name: bond0
+  type: bond
+  state: absent
+- name: bond0.100
+  type: vlan
+  state: absent
+`), nmstatev1alpha1.NewState(currentState))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(orderedState.String()).To(MatchYAML(`interfaces:
+- name: bond0.100
+  type: vlan
+  state: absent
+- name: bond0
+  type: bond
+  state: absent
+`))
+	})
+
+	It("should remove the whole stack from the top and keep the rest in place", func() {
+		orderedState, err := orderTeardown(nmstatev1alpha1.NewState(`interfaces:
+- name: bond0
+  state: absent
+- name: eth1
+  type: ethernet
+  state: up
+- name: bond0.100
+  state: absent
+- name: macvlan0
+  type: mac-vlan
+  state: absent
+  mac-vlan:
+    base-iface: bond0.100
+`), nmstatev1alpha1.NewState(currentState))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(orderedState.String()).To(MatchYAML(`interfaces:
+- name: macvlan0
+  type: mac-vlan
+  state: absent
+  mac-vlan:
+    base-iface: bond0.100
+- name: eth1
+  type: ethernet
+  state: up
+- name: bond0.100
+  state: absent
+- name: bond0
+  state: absent
+`))
+	})
+
+	It("should keep the desired state already in order as it is", func() {
+		desiredState := nmstatev1alpha1.NewState("interfaces:\n- name: bond0.100\n  state: absent\n- name: bond0\n  state: absent\n")
+		orderedState, err := orderTeardown(desiredState, nmstatev1alpha1.NewState(currentState))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(orderedState).To(Equal(desiredState))
+	})
+
+	It("should fail removing a bond with a VLAN kept on top of it", func() {
+		_, err := orderTeardown(nmstatev1alpha1.NewState("interfaces:\n- name: bond0\n  type: bond\n  state: absent\n"), nmstatev1alpha1.NewState(currentState))
+		Expect(err).To(MatchError("interface bond0 cannot be removed, bond0.100 is on top of it and is not removed, set it as absent too"))
+	})
+
+	It("should allow removing a bond with its VLAN moved to another base interface", func() {
+		_, err := orderTeardown(nmstatev1alpha1.NewState(`interfaces:
+- name: bond0
+  type: bond
+  state: absent
+- name: bond0.100
+  type: vlan
+  state: up
+  vlan:
+    base-iface: eth1
+    id: 100
+`), nmstatev1alpha1.NewState(currentState))
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
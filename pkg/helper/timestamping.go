@@ -0,0 +1,115 @@
+package helper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const timestampingKey = "timestamping"
+
+// The capabilities PTP needs to timestamp in hardware and in software, like
+// ptp4l -H and -S do
+var (
+	hardwareTimestamping = []string{"hardware-transmit", "hardware-receive", "hardware-raw-clock"}
+	softwareTimestamping = []string{"software-transmit", "software-receive", "software-system-clock"}
+)
+
+// parseTimestamping returns the timestamping of the interface from the
+// output of "ethtool -T <iface>": its capabilities, if they are enough for
+// hardware and software timestamping and the index of its PTP hardware
+// clock, missing without it
+func parseTimestamping(output string) map[string]interface{} {
+	capabilities := []string{}
+	phc := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.Contains(line, "(SOF_TIMESTAMPING_") {
+			capabilities = append(capabilities, strings.Fields(line)[0])
+			continue
+		}
+		keyValue := strings.SplitN(line, ":", 2)
+		if len(keyValue) == 2 && strings.TrimSpace(keyValue[0]) == "PTP Hardware Clock" {
+			phc = strings.TrimSpace(keyValue[1])
+		}
+	}
+
+	timestamping := map[string]interface{}{
+		"capabilities": capabilities,
+		"hardware":     hasCapabilities(capabilities, hardwareTimestamping),
+		"software":     hasCapabilities(capabilities, softwareTimestamping),
+	}
+	if index, err := strconv.Atoi(phc); err == nil {
+		timestamping["ptp-hardware-clock"] = index
+	}
+	return timestamping
+}
+
+func hasCapabilities(capabilities []string, needed []string) bool {
+	for _, capability := range needed {
+		found := false
+		for _, current := range capabilities {
+			if current == capability {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// addTimestamping reports the timestamping of the current state interfaces
+func addTimestamping(currentState nmstatev1alpha1.State, timestamping map[string]map[string]interface{}) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return currentState, nil
+	}
+
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		if interfaceTimestamping, found := timestamping[name]; found {
+			iface[timestampingKey] = interfaceTimestamping
+		}
+	}
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+// reportTimestamping reports the timestamping capabilities of the physical
+// interfaces, the interfaces ethtool fails with are left out
+func reportTimestamping(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	interfaces, err := listPhysicalInterfaces(sysClassNetDir)
+	if err != nil {
+		return currentState, fmt.Errorf("failed listing physical interfaces: %v", err)
+	}
+	timestamping := map[string]map[string]interface{}{}
+	for _, iface := range interfaces {
+		output, err := ethtool("-T", iface.Name)
+		if err != nil {
+			continue
+		}
+		timestamping[iface.Name] = parseTimestamping(output)
+	}
+	return addTimestamping(currentState, timestamping)
+}
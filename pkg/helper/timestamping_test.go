@@ -0,0 +1,77 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Interfaces timestamping", func() {
+	It("should parse the hardware timestamping capabilities and clock", func() {
+		Expect(parseTimestamping(`Time stamping parameters for ens1f0:
+Capabilities:
+	hardware-transmit     (SOF_TIMESTAMPING_TX_HARDWARE)
+	software-transmit     (SOF_TIMESTAMPING_TX_SOFTWARE)
+	hardware-receive      (SOF_TIMESTAMPING_RX_HARDWARE)
+	software-receive      (SOF_TIMESTAMPING_RX_SOFTWARE)
+	software-system-clock (SOF_TIMESTAMPING_SOFTWARE)
+	hardware-raw-clock    (SOF_TIMESTAMPING_RAW_HARDWARE)
+PTP Hardware Clock: 2
+Hardware Transmit Timestamp Modes:
+	off                   (HWTSTAMP_TX_OFF)
+	on                    (HWTSTAMP_TX_ON)
+Hardware Receive Filter Modes:
+	none                  (HWTSTAMP_FILTER_NONE)
+	all                   (HWTSTAMP_FILTER_ALL)
+`)).To(Equal(map[string]interface{}{
+			"capabilities":       []string{"hardware-transmit", "software-transmit", "hardware-receive", "software-receive", "software-system-clock", "hardware-raw-clock"},
+			"hardware":           true,
+			"software":           true,
+			"ptp-hardware-clock": 2,
+		}))
+	})
+
+	It("should parse the software only timestamping without clock", func() {
+		Expect(parseTimestamping(`Time stamping parameters for eth0:
+Capabilities:
+	software-transmit     (SOF_TIMESTAMPING_TX_SOFTWARE)
+	software-receive      (SOF_TIMESTAMPING_RX_SOFTWARE)
+	software-system-clock (SOF_TIMESTAMPING_SOFTWARE)
+PTP Hardware Clock: none
+Hardware Transmit Timestamp Modes: none
+Hardware Receive Filter Modes: none
+`)).To(Equal(map[string]interface{}{
+			"capabilities": []string{"software-transmit", "software-receive", "software-system-clock"},
+			"hardware":     false,
+			"software":     true,
+		}))
+	})
+
+	It("should report the timestamping at the current state", func() {
+		reportedState, err := addTimestamping(nmstatev1alpha1.NewState(`interfaces:
+- name: eth0
+  type: ethernet
+  state: up
+- name: br1
+  type: linux-bridge
+  state: up
+`), map[string]map[string]interface{}{
+			"eth0": {"capabilities": []string{"software-receive"}, "hardware": false, "software": false},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reportedState.String()).To(MatchYAML(`interfaces:
+- name: eth0
+  type: ethernet
+  state: up
+  timestamping:
+    capabilities:
+    - software-receive
+    hardware: false
+    software: false
+- name: br1
+  type: linux-bridge
+  state: up
+`))
+	})
+})
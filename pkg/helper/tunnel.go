@@ -0,0 +1,640 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// nmstate does not support the GRE and IPIP tunnels, they are created with
+// iproute, their local and remote addresses, ttl, key and base interface
+// are configured at the key named after the interface type
+const (
+	greInterfaceType  = "gre"
+	ipipInterfaceType = "ipip"
+)
+
+// tunnelModes are the ip tunnel modes of the tunnel interface types
+var tunnelModes = map[string]string{
+	greInterfaceType:  "gre",
+	ipipInterfaceType: "ipip",
+}
+
+// tunnelFallbackDevices are created by the kernel tunnel modules, they can
+// be neither configured nor removed
+var tunnelFallbackDevices = map[string]bool{
+	"gre0":  true,
+	"tunl0": true,
+}
+
+// tunnelInterface is a desired state GRE or IPIP tunnel, key is only
+// supported by GRE and ttl 0 inherits the one of the encapsulated packets
+type tunnelInterface struct {
+	name      string
+	kind      string
+	local     string
+	remote    string
+	ttl       int64
+	key       string
+	baseIface string
+	addresses []string
+	down      bool
+	absent    bool
+}
+
+// tunnelArguments returns the ip tunnel arguments configuring the tunnel
+func (t tunnelInterface) tunnelArguments() []string {
+	arguments := []string{t.name, "mode", tunnelModes[t.kind], "local", t.local, "remote", t.remote, "ttl", strconv.FormatInt(t.ttl, 10)}
+	if t.key != "" {
+		arguments = append(arguments, "key", t.key)
+	}
+	if t.baseIface != "" {
+		arguments = append(arguments, "dev", t.baseIface)
+	}
+	return arguments
+}
+
+func isTunnelType(interfaceType string) bool {
+	_, isTunnel := tunnelModes[interfaceType]
+	return isTunnel
+}
+
+func parseIPv4(value gjson.Result) (string, bool) {
+	ip := net.ParseIP(value.String())
+	if value.Type != gjson.String || ip == nil || ip.To4() == nil {
+		return "", false
+	}
+	return ip.String(), true
+}
+
+func parseBoundedInt(value gjson.Result, max int64) (int64, bool) {
+	if value.Type != gjson.Number || value.Int() < 0 || value.Int() > max || float64(value.Int()) != value.Float() {
+		return 0, false
+	}
+	return value.Int(), true
+}
+
+// getTunnels returns the desired state GRE and IPIP tunnels sorted by name,
+// failing with invalid configurations
+func getTunnels(desiredState nmstatev1alpha1.State) ([]tunnelInterface, error) {
+	tunnels := []tunnelInterface{}
+	if len(desiredState.Raw) == 0 {
+		return tunnels, nil
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return tunnels, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		kind := iface.Get("type").String()
+		if !isTunnelType(kind) {
+			continue
+		}
+		name := iface.Get("name").String()
+		if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+			return tunnels, fmt.Errorf("invalid %s tunnel name %q", kind, name)
+		}
+		if tunnelFallbackDevices[name] {
+			return tunnels, fmt.Errorf("%s is the %s fallback device created by the kernel, it cannot be configured", name, kind)
+		}
+		tunnel := tunnelInterface{name: name, kind: kind}
+		if iface.Get("state").String() == "absent" {
+			tunnel.absent = true
+			tunnels = append(tunnels, tunnel)
+			continue
+		}
+		tunnel.down = iface.Get("state").String() == "down"
+
+		config := iface.Get(kind)
+		var valid bool
+		tunnel.local, valid = parseIPv4(config.Get("local"))
+		if !valid {
+			return tunnels, fmt.Errorf("invalid %s tunnel %s local address %s, it has to be an IPv4 address", kind, name, config.Get("local").Raw)
+		}
+		tunnel.remote, valid = parseIPv4(config.Get("remote"))
+		if !valid {
+			return tunnels, fmt.Errorf("invalid %s tunnel %s remote address %s, it has to be an IPv4 address", kind, name, config.Get("remote").Raw)
+		}
+		if ttl := config.Get("ttl"); ttl.Exists() {
+			tunnel.ttl, valid = parseBoundedInt(ttl, 255)
+			if !valid {
+				return tunnels, fmt.Errorf("invalid %s tunnel %s ttl %s, it has to be from 0 to 255", kind, name, ttl.Raw)
+			}
+		}
+		if key := config.Get("key"); key.Exists() {
+			if kind != greInterfaceType {
+				return tunnels, fmt.Errorf("invalid %s tunnel %s key, only GRE tunnels support it", kind, name)
+			}
+			keyValue, valid := parseBoundedInt(key, 4294967295)
+			if !valid {
+				return tunnels, fmt.Errorf("invalid %s tunnel %s key %s, it has to be from 0 to 4294967295", kind, name, key.Raw)
+			}
+			tunnel.key = strconv.FormatInt(keyValue, 10)
+		}
+		tunnel.baseIface = config.Get("base-iface").String()
+
+		if iface.Get("ipv4.enabled").Bool() {
+			for _, address := range iface.Get("ipv4.address").Array() {
+				ip, valid := parseIPv4(address.Get("ip"))
+				if !valid {
+					return tunnels, fmt.Errorf("invalid %s tunnel %s address %s, it has to be an IPv4 address", kind, name, address.Get("ip").Raw)
+				}
+				prefixLength, valid := parseBoundedInt(address.Get("prefix-length"), 32)
+				if !valid {
+					return tunnels, fmt.Errorf("invalid %s tunnel %s address %s prefix length %s", kind, name, ip, address.Get("prefix-length").Raw)
+				}
+				tunnel.addresses = append(tunnel.addresses, fmt.Sprintf("%s/%d", ip, prefixLength))
+			}
+		}
+		tunnels = append(tunnels, tunnel)
+	}
+	sort.Slice(tunnels, func(i, j int) bool {
+		return tunnels[i].name < tunnels[j].name
+	})
+	return tunnels, nil
+}
+
+// stripTunnels removes the GRE and IPIP tunnels from the desired state
+func stripTunnels(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return desiredState, nil
+	}
+
+	keptInterfaces := []interface{}{}
+	for _, iface := range interfaces {
+		if ifaceMap, isMap := iface.(map[string]interface{}); isMap {
+			if interfaceType, _ := ifaceMap["type"].(string); isTunnelType(interfaceType) {
+				continue
+			}
+		}
+		keptInterfaces = append(keptInterfaces, iface)
+	}
+	if len(keptInterfaces) == len(interfaces) {
+		return desiredState, nil
+	}
+	state["interfaces"] = keptInterfaces
+
+	strippedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: strippedState}, nil
+}
+
+// checkTunnelBases fails if the base interface of a tunnel is neither at
+// the node nor created by the desired state, so nothing is applied
+func checkTunnelBases(netDir string, desiredState nmstatev1alpha1.State, tunnels []tunnelInterface) error {
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+	// Present or absent by name
+	desiredInterfaces := map[string]bool{}
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		desiredInterfaces[iface.Get("name").String()] = iface.Get("state").String() != "absent"
+	}
+
+	for _, tunnel := range tunnels {
+		if tunnel.absent || tunnel.baseIface == "" {
+			continue
+		}
+		present, desired := desiredInterfaces[tunnel.baseIface]
+		if desired && !present {
+			return fmt.Errorf("%s tunnel %s base interface %s is removed by the desired state", tunnel.kind, tunnel.name, tunnel.baseIface)
+		}
+		if desired {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(netDir, tunnel.baseIface)); err != nil {
+			return fmt.Errorf("%s tunnel %s base interface %s not found at the node nor at the desired state", tunnel.kind, tunnel.name, tunnel.baseIface)
+		}
+	}
+	return nil
+}
+
+func checkTunnels(desiredState nmstatev1alpha1.State, tunnels []tunnelInterface) error {
+	return checkTunnelBases(sysClassNetDir, desiredState, tunnels)
+}
+
+// ipTunnelLink is a GRE or IPIP link from "ip -d -j link show"
+type ipTunnelLink struct {
+	Name     string   `json:"ifname"`
+	Link     string   `json:"link"`
+	Flags    []string `json:"flags"`
+	LinkInfo struct {
+		Kind string `json:"info_kind"`
+		Data struct {
+			Local  string `json:"local"`
+			Remote string `json:"remote"`
+			TTL    int64  `json:"ttl"`
+			IKey   string `json:"ikey"`
+		} `json:"info_data"`
+	} `json:"linkinfo"`
+}
+
+// key returns the tunnel key as a number, iproute reports it as a dotted
+// quad, empty if the tunnel has no key
+func (l ipTunnelLink) key() string {
+	ip := net.ParseIP(l.LinkInfo.Data.IKey).To4()
+	if ip == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(ip[0])<<24|uint64(ip[1])<<16|uint64(ip[2])<<8|uint64(ip[3]), 10)
+}
+
+func (l ipTunnelLink) isUp() bool {
+	for _, flag := range l.Flags {
+		if flag == "UP" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTunnelLinks returns the GRE and IPIP links by name from the output
+// of "ip -d -j link show", without the fallback devices
+func parseTunnelLinks(output string) (map[string]ipTunnelLink, error) {
+	links := []ipTunnelLink{}
+	err := json.Unmarshal([]byte(output), &links)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing ip links: %v", err)
+	}
+	tunnelLinks := map[string]ipTunnelLink{}
+	for _, link := range links {
+		if isTunnelType(link.LinkInfo.Kind) && !tunnelFallbackDevices[link.Name] {
+			tunnelLinks[link.Name] = link
+		}
+	}
+	return tunnelLinks, nil
+}
+
+func showTunnelLinks() (map[string]ipTunnelLink, error) {
+	output, err := ip("-d", "-j", "link", "show")
+	if err != nil {
+		return nil, err
+	}
+	return parseTunnelLinks(output)
+}
+
+// tunnelAddresses returns the IPv4 addresses of the tunnel as ip/prefix
+// from the output of "ip -4 -j addr show dev <tunnel>"
+func tunnelAddresses(output string) ([]string, error) {
+	links := []struct {
+		AddrInfo []struct {
+			Local     string `json:"local"`
+			PrefixLen int    `json:"prefixlen"`
+		} `json:"addr_info"`
+	}{}
+	err := json.Unmarshal([]byte(output), &links)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing ip addresses: %v", err)
+	}
+	addresses := []string{}
+	for _, link := range links {
+		for _, address := range link.AddrInfo {
+			addresses = append(addresses, fmt.Sprintf("%s/%d", address.Local, address.PrefixLen))
+		}
+	}
+	return addresses, nil
+}
+
+// applyTunnelAddresses replaces the tunnel IPv4 addresses with the desired
+// ones
+func applyTunnelAddresses(tunnel tunnelInterface) (string, error) {
+	output := ""
+	addrOutput, err := ip("-4", "-j", "addr", "show", "dev", tunnel.name)
+	if err != nil {
+		return output, err
+	}
+	currentAddresses, err := tunnelAddresses(addrOutput)
+	if err != nil {
+		return output, err
+	}
+	desiredAddresses := map[string]bool{}
+	for _, address := range tunnel.addresses {
+		desiredAddresses[address] = true
+		ipOutput, err := ip("addr", "replace", address, "dev", tunnel.name)
+		output += fmt.Sprintf("tunnel %s address %s replace output: %s\n", tunnel.name, address, ipOutput)
+		if err != nil {
+			return output, err
+		}
+	}
+	for _, address := range currentAddresses {
+		if desiredAddresses[address] {
+			continue
+		}
+		ipOutput, err := ip("addr", "del", address, "dev", tunnel.name)
+		output += fmt.Sprintf("tunnel %s address %s del output: %s\n", tunnel.name, address, ipOutput)
+		if err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+// applyTunnels creates or updates the present tunnels and deletes the
+// absent ones, the ones already missing are fine. Tunnels changing their
+// type or key are recreated, ip tunnel cannot change them.
+func applyTunnels(tunnels []tunnelInterface) (string, error) {
+	output := ""
+	if len(tunnels) == 0 {
+		return output, nil
+	}
+	currentTunnels, err := showTunnelLinks()
+	if err != nil {
+		return output, err
+	}
+	for _, tunnel := range tunnels {
+		current, exists := currentTunnels[tunnel.name]
+		if exists && (tunnel.absent || current.LinkInfo.Kind != tunnel.kind || current.key() != tunnel.key) {
+			ipOutput, err := ip("link", "del", tunnel.name)
+			output += fmt.Sprintf("tunnel %s del output: %s\n", tunnel.name, ipOutput)
+			if err != nil {
+				return output, err
+			}
+			exists = false
+		}
+		if tunnel.absent {
+			continue
+		}
+		if !exists {
+			if _, err := os.Stat(filepath.Join(sysClassNetDir, tunnel.name)); err == nil {
+				return output, fmt.Errorf("cannot create %s tunnel %s, another interface with that name exists at the node", tunnel.kind, tunnel.name)
+			}
+		}
+
+		operation := "add"
+		if exists {
+			operation = "change"
+		}
+		ipOutput, err := ip(append([]string{"tunnel", operation}, tunnel.tunnelArguments()...)...)
+		output += fmt.Sprintf("tunnel %s %s output: %s\n", tunnel.name, operation, ipOutput)
+		if err != nil {
+			return output, err
+		}
+
+		addressesOutput, err := applyTunnelAddresses(tunnel)
+		output += addressesOutput
+		if err != nil {
+			return output, err
+		}
+
+		linkState := "up"
+		if tunnel.down {
+			linkState = "down"
+		}
+		ipOutput, err = ip("link", "set", tunnel.name, linkState)
+		output += fmt.Sprintf("tunnel %s set %s output: %s\n", tunnel.name, linkState, ipOutput)
+		if err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+// readTunnels returns the tunnels restoring the desired ones to the node
+// links, the tunnels that did not exist are deleted and the rest are
+// configured back with their addresses
+func readTunnels(tunnels []tunnelInterface) []tunnelInterface {
+	if len(tunnels) == 0 {
+		return []tunnelInterface{}
+	}
+	currentTunnels, err := showTunnelLinks()
+	if err != nil {
+		log.Info(fmt.Sprintf("failed reading tunnels: %v", err))
+		return []tunnelInterface{}
+	}
+	currentAddresses := map[string][]string{}
+	for _, tunnel := range tunnels {
+		if _, exists := currentTunnels[tunnel.name]; !exists {
+			continue
+		}
+		output, err := ip("-4", "-j", "addr", "show", "dev", tunnel.name)
+		if err != nil {
+			log.Info(fmt.Sprintf("failed reading tunnel %s addresses: %v", tunnel.name, err))
+			continue
+		}
+		addresses, err := tunnelAddresses(output)
+		if err != nil {
+			log.Info(fmt.Sprintf("failed reading tunnel %s addresses: %v", tunnel.name, err))
+			continue
+		}
+		currentAddresses[tunnel.name] = addresses
+	}
+	return previousTunnels(tunnels, currentTunnels, currentAddresses)
+}
+
+func previousTunnels(tunnels []tunnelInterface, currentTunnels map[string]ipTunnelLink, currentAddresses map[string][]string) []tunnelInterface {
+	previous := []tunnelInterface{}
+	for _, tunnel := range tunnels {
+		link, exists := currentTunnels[tunnel.name]
+		if !exists {
+			previous = append(previous, tunnelInterface{name: tunnel.name, kind: tunnel.kind, absent: true})
+			continue
+		}
+		addresses, found := currentAddresses[tunnel.name]
+		if !found {
+			addresses = []string{}
+		}
+		previous = append(previous, tunnelInterface{
+			name:      link.Name,
+			kind:      link.LinkInfo.Kind,
+			local:     link.LinkInfo.Data.Local,
+			remote:    link.LinkInfo.Data.Remote,
+			ttl:       link.LinkInfo.Data.TTL,
+			key:       link.key(),
+			baseIface: link.Link,
+			addresses: addresses,
+			down:      !link.isUp(),
+		})
+	}
+	return previous
+}
+
+// restoreTunnels sets back the tunnels read before applying the desired
+// state, they are not part of the nmstate checkpoint
+func restoreTunnels(previousTunnels []tunnelInterface) string {
+	output, err := applyTunnels(previousTunnels)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed restoring tunnels: %v", err))
+	}
+	return output
+}
+
+// tunnelInterfaces returns the type of the tunnels present at the state by
+// name, absent ones are not included
+func tunnelInterfaces(state nmstatev1alpha1.State) (map[string]string, error) {
+	tunnels := map[string]string{}
+	if len(state.Raw) == 0 {
+		return tunnels, nil
+	}
+	stateJSON, err := yaml.YAMLToJSON(state.Raw)
+	if err != nil {
+		return tunnels, fmt.Errorf("error converting state to JSON: %v", err)
+	}
+	for _, iface := range gjson.ParseBytes(stateJSON).Get("interfaces").Array() {
+		if kind := iface.Get("type").String(); isTunnelType(kind) && iface.Get("state").String() != "absent" {
+			tunnels[iface.Get("name").String()] = kind
+		}
+	}
+	return tunnels, nil
+}
+
+// RemoveDroppedTunnels sets as absent at the desired state the GRE and IPIP
+// tunnels present at the previously applied one that are not at the desired
+// state anymore, like the dummy interfaces they only exist because a policy
+// created them, so dropping them from the policy deletes them.
+func RemoveDroppedTunnels(previousState nmstatev1alpha1.State, desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	previousTunnels, err := tunnelInterfaces(previousState)
+	if err != nil || len(previousTunnels) == 0 {
+		return desiredState, err
+	}
+
+	var state map[string]interface{}
+	err = yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return desiredState, err
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	interfaces, _ := state["interfaces"].([]interface{})
+
+	desiredInterfaces := map[string]bool{}
+	for _, iface := range interfaces {
+		if name, named := itemName(iface); named {
+			desiredInterfaces[name] = true
+		}
+	}
+
+	droppedTunnels := []string{}
+	for name := range previousTunnels {
+		if !desiredInterfaces[name] {
+			droppedTunnels = append(droppedTunnels, name)
+		}
+	}
+	if len(droppedTunnels) == 0 {
+		return desiredState, nil
+	}
+	sort.Strings(droppedTunnels)
+
+	for _, name := range droppedTunnels {
+		interfaces = append(interfaces, map[string]interface{}{
+			"name":  name,
+			"type":  previousTunnels[name],
+			"state": "absent",
+		})
+	}
+	state["interfaces"] = interfaces
+
+	removedState, err := yaml.Marshal(state)
+	if err != nil {
+		return desiredState, err
+	}
+	return nmstatev1alpha1.State{Raw: removedState}, nil
+}
+
+// addTunnels reports the GRE and IPIP tunnels configuration at the current
+// state, the ones nmstate does not report are added
+func addTunnels(currentState nmstatev1alpha1.State, tunnelLinks map[string]ipTunnelLink, interfacesFilterGlob glob.Glob) (nmstatev1alpha1.State, error) {
+	if len(tunnelLinks) == 0 {
+		return currentState, nil
+	}
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	interfaces, _ := state["interfaces"].([]interface{})
+
+	reported := map[string]bool{}
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		link, isTunnel := tunnelLinks[name]
+		if !isTunnel {
+			continue
+		}
+		iface["type"] = link.LinkInfo.Kind
+		iface[link.LinkInfo.Kind] = reportedTunnel(link)
+		reported[name] = true
+	}
+
+	names := []string{}
+	for name := range tunnelLinks {
+		if reported[name] || (!interfacesFilterGlob.Match("") && interfacesFilterGlob.Match(name)) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		link := tunnelLinks[name]
+		linkState := "down"
+		if link.isUp() {
+			linkState = "up"
+		}
+		interfaces = append(interfaces, map[string]interface{}{
+			"name":             name,
+			"type":             link.LinkInfo.Kind,
+			"state":            linkState,
+			link.LinkInfo.Kind: reportedTunnel(link),
+		})
+	}
+	state["interfaces"] = interfaces
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+func reportedTunnel(link ipTunnelLink) map[string]interface{} {
+	tunnel := map[string]interface{}{
+		"local":  link.LinkInfo.Data.Local,
+		"remote": link.LinkInfo.Data.Remote,
+		"ttl":    link.LinkInfo.Data.TTL,
+	}
+	if key := link.key(); key != "" {
+		tunnel["key"], _ = strconv.ParseUint(key, 10, 32)
+	}
+	if link.Link != "" {
+		tunnel["base-iface"] = link.Link
+	}
+	return tunnel
+}
+
+func reportTunnels(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	tunnelLinks, err := showTunnelLinks()
+	if err != nil {
+		return currentState, err
+	}
+	return addTunnels(currentState, tunnelLinks, interfacesFilterGlob)
+}
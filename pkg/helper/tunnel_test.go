@@ -0,0 +1,208 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gobwas/glob"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("GRE and IPIP tunnels", func() {
+	const eth1 = `- name: eth1
+  type: ethernet
+  state: up
+`
+	const gre1 = `- name: gre1
+  type: gre
+  state: up
+  gre:
+    local: 192.0.2.1
+    remote: 198.51.100.1
+    ttl: 64
+    key: 10
+    base-iface: eth1
+  ipv4:
+    enabled: true
+    address:
+    - ip: 10.0.0.1
+      prefix-length: 30
+`
+	const ipip1 = `- name: ipip1
+  type: ipip
+  state: down
+  ipip:
+    local: 192.0.2.1
+    remote: 198.51.100.2
+`
+
+	It("should take the tunnels and strip them from the nmstate desired state", func() {
+		desiredState := nmstatev1alpha1.NewState("interfaces:\n" + ipip1 + eth1 + gre1 + "- name: gre2\n  type: gre\n  state: absent\n")
+
+		tunnels, err := getTunnels(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tunnels).To(Equal([]tunnelInterface{
+			{name: "gre1", kind: "gre", local: "192.0.2.1", remote: "198.51.100.1", ttl: 64, key: "10", baseIface: "eth1", addresses: []string{"10.0.0.1/30"}},
+			{name: "gre2", kind: "gre", absent: true},
+			{name: "ipip1", kind: "ipip", local: "192.0.2.1", remote: "198.51.100.2", down: true},
+		}))
+		Expect(tunnels[0].tunnelArguments()).To(Equal([]string{"gre1", "mode", "gre", "local", "192.0.2.1", "remote", "198.51.100.1", "ttl", "64", "key", "10", "dev", "eth1"}))
+		Expect(tunnels[2].tunnelArguments()).To(Equal([]string{"ipip1", "mode", "ipip", "local", "192.0.2.1", "remote", "198.51.100.2", "ttl", "0"}))
+
+		strippedState, err := stripTunnels(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML("interfaces:\n" + eth1))
+	})
+
+	DescribeTable("with invalid tunnels",
+		func(tunnel string) {
+			_, err := getTunnels(nmstatev1alpha1.NewState("interfaces:\n" + tunnel))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("without local address", "- name: gre1\n  type: gre\n  gre:\n    remote: 198.51.100.1\n"),
+		Entry("with IPv6 remote address", "- name: gre1\n  type: gre\n  gre:\n    local: 192.0.2.1\n    remote: 2001:db8::1\n"),
+		Entry("with ttl out of range", "- name: gre1\n  type: gre\n  gre:\n    local: 192.0.2.1\n    remote: 198.51.100.1\n    ttl: 256\n"),
+		Entry("with key at an IPIP tunnel", "- name: ipip1\n  type: ipip\n  ipip:\n    local: 192.0.2.1\n    remote: 198.51.100.1\n    key: 10\n"),
+		Entry("with key not a number", "- name: gre1\n  type: gre\n  gre:\n    local: 192.0.2.1\n    remote: 198.51.100.1\n    key: ten\n"),
+		Entry("with invalid address", "- name: gre1\n  type: gre\n  gre:\n    local: 192.0.2.1\n    remote: 198.51.100.1\n  ipv4:\n    enabled: true\n    address:\n    - ip: 10.0.0.1\n      prefix-length: 33\n"),
+		Entry("with a path name", "- name: ../gre1\n  type: gre\n  state: absent\n"),
+		Entry("with the fallback device name", "- name: gre0\n  type: gre\n  state: absent\n"),
+	)
+
+	Context("with base interfaces", func() {
+		var netDir string
+
+		BeforeEach(func() {
+			var err error
+			netDir, err = ioutil.TempDir("", "sys-class-net")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.MkdirAll(filepath.Join(netDir, "eth1"), 0755)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(netDir)
+		})
+
+		It("should pass if they are at the node or at the desired state", func() {
+			desiredState := nmstatev1alpha1.NewState("interfaces:\n" + gre1 + "- name: vlan10\n  type: vlan\n  state: up\n")
+			tunnels := []tunnelInterface{
+				{name: "gre1", kind: "gre", baseIface: "eth1"},
+				{name: "gre2", kind: "gre", baseIface: "vlan10"},
+				{name: "gre3", kind: "gre", baseIface: "eth2", absent: true},
+			}
+			Expect(checkTunnelBases(netDir, desiredState, tunnels)).To(Succeed())
+		})
+
+		It("should fail if they are removed by the desired state", func() {
+			desiredState := nmstatev1alpha1.NewState("interfaces:\n- name: eth1\n  type: ethernet\n  state: absent\n")
+			err := checkTunnelBases(netDir, desiredState, []tunnelInterface{{name: "gre1", kind: "gre", baseIface: "eth1"}})
+			Expect(err).To(MatchError("gre tunnel gre1 base interface eth1 is removed by the desired state"))
+		})
+
+		It("should fail if they are missing", func() {
+			desiredState := nmstatev1alpha1.NewState("interfaces:\n" + eth1)
+			err := checkTunnelBases(netDir, desiredState, []tunnelInterface{{name: "gre1", kind: "gre", baseIface: "eth2"}})
+			Expect(err).To(MatchError("gre tunnel gre1 base interface eth2 not found at the node nor at the desired state"))
+		})
+	})
+
+	DescribeTable("dropped from the desired state",
+		func(previousState string, desiredState string, expectedState string) {
+			removedState, err := RemoveDroppedTunnels(nmstatev1alpha1.NewState(previousState), nmstatev1alpha1.NewState(desiredState))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removedState.String()).To(MatchYAML(expectedState))
+		},
+		Entry("without previous desired state, should keep it",
+			"",
+			"interfaces:\n"+gre1,
+			"interfaces:\n"+gre1,
+		),
+		Entry("still configuring the tunnels, should keep them",
+			"interfaces:\n"+gre1+ipip1,
+			"interfaces:\n"+gre1+ipip1,
+			"interfaces:\n"+gre1+ipip1,
+		),
+		Entry("without some of the tunnels, should remove them",
+			"interfaces:\n"+gre1+ipip1,
+			"interfaces:\n"+eth1,
+			"interfaces:\n"+eth1+"- name: gre1\n  type: gre\n  state: absent\n- name: ipip1\n  type: ipip\n  state: absent\n",
+		),
+		Entry("with the tunnels already absent, should not remove them again",
+			"interfaces:\n- name: gre1\n  type: gre\n  state: absent\n",
+			"interfaces:\n"+eth1,
+			"interfaces:\n"+eth1,
+		),
+	)
+
+	Context("at the node", func() {
+		const ipLinks = `[{"ifname":"lo","flags":["LOOPBACK","UP"]},
+{"ifname":"gre0","flags":["NOARP"],"linkinfo":{"info_kind":"gre","info_data":{"remote":"any","local":"any"}}},
+{"ifname":"gre1","link":"eth1","flags":["POINTOPOINT","NOARP","UP"],"linkinfo":{"info_kind":"gre","info_data":{"remote":"198.51.100.1","local":"192.0.2.1","ttl":64,"ikey":"0.0.0.10","okey":"0.0.0.10"}}},
+{"ifname":"ipip1","flags":["POINTOPOINT","NOARP"],"linkinfo":{"info_kind":"ipip","info_data":{"remote":"198.51.100.2","local":"192.0.2.1"}}}]`
+
+		It("should parse the tunnels without the fallback devices", func() {
+			links, err := parseTunnelLinks(ipLinks)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(links).To(HaveLen(2))
+			Expect(links["gre1"].key()).To(Equal("10"))
+			Expect(links["ipip1"].key()).To(Equal(""))
+		})
+
+		It("should report them at the current state", func() {
+			links, err := parseTunnelLinks(ipLinks)
+			Expect(err).ToNot(HaveOccurred())
+			reportedState, err := addTunnels(nmstatev1alpha1.NewState("interfaces:\n"+eth1+"- name: gre1\n  type: unknown\n  state: up\n"), links, glob.MustCompile(""))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reportedState.String()).To(MatchYAML("interfaces:\n" + eth1 + `- name: gre1
+  type: gre
+  state: up
+  gre:
+    local: 192.0.2.1
+    remote: 198.51.100.1
+    ttl: 64
+    key: 10
+    base-iface: eth1
+- name: ipip1
+  type: ipip
+  state: down
+  ipip:
+    local: 192.0.2.1
+    remote: 198.51.100.2
+    ttl: 0
+`))
+		})
+
+		It("should not add the filtered out ones", func() {
+			links, err := parseTunnelLinks(ipLinks)
+			Expect(err).ToNot(HaveOccurred())
+			reportedState, err := addTunnels(nmstatev1alpha1.NewState("interfaces:\n"+eth1), links, glob.MustCompile("{gre*,ipip*}"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reportedState.String()).To(MatchYAML("interfaces:\n" + eth1))
+		})
+
+		It("should restore the tunnels at the node and delete the rest", func() {
+			links, err := parseTunnelLinks(ipLinks)
+			Expect(err).ToNot(HaveOccurred())
+			previous := previousTunnels([]tunnelInterface{
+				{name: "gre1", kind: "ipip", local: "192.0.2.5", remote: "198.51.100.5"},
+				{name: "gre2", kind: "gre", local: "192.0.2.1", remote: "198.51.100.3"},
+			}, links, map[string][]string{"gre1": {"10.0.0.1/30"}})
+			Expect(previous).To(Equal([]tunnelInterface{
+				{name: "gre1", kind: "gre", local: "192.0.2.1", remote: "198.51.100.1", ttl: 64, key: "10", baseIface: "eth1", addresses: []string{"10.0.0.1/30"}},
+				{name: "gre2", kind: "gre", absent: true},
+			}))
+		})
+
+		It("should parse the tunnel addresses", func() {
+			addresses, err := tunnelAddresses(`[{"ifname":"gre1","addr_info":[{"family":"inet","local":"10.0.0.1","prefixlen":30}]}]`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(addresses).To(Equal([]string{"10.0.0.1/30"}))
+		})
+	})
+})
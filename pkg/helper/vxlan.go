@@ -0,0 +1,99 @@
+package helper
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const vxlanInterfaceType = "vxlan"
+
+// vxlanIDs returns the VXLAN ids, the VNIs, of the state by interface name,
+// the ones removed by it are returned with id -1
+func vxlanIDs(state nmstatev1alpha1.State) (map[string]int64, error) {
+	stateJSON, err := yaml.YAMLToJSON(state.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting state to JSON: %v", err)
+	}
+
+	ids := map[string]int64{}
+	for _, iface := range gjson.ParseBytes(stateJSON).Get("interfaces").Array() {
+		name := iface.Get("name").String()
+		if iface.Get("state").String() == "absent" {
+			ids[name] = -1
+			continue
+		}
+		if iface.Get("type").String() != vxlanInterfaceType {
+			continue
+		}
+		id := iface.Get("vxlan.id")
+		if id.Exists() {
+			ids[name] = id.Int()
+		}
+	}
+	return ids, nil
+}
+
+// vxlanIDConflicts returns an error if applying the desired state on top of
+// the current state would leave more than one VXLAN interface with the same
+// id at the node
+func vxlanIDConflicts(currentState nmstatev1alpha1.State, desiredState nmstatev1alpha1.State) error {
+	currentIDs, err := vxlanIDs(currentState)
+	if err != nil {
+		return err
+	}
+	desiredIDs, err := vxlanIDs(desiredState)
+	if err != nil {
+		return err
+	}
+	for name, id := range desiredIDs {
+		currentIDs[name] = id
+	}
+
+	names := []string{}
+	for name := range currentIDs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	interfaceByID := map[int64]string{}
+	for _, name := range names {
+		id := currentIDs[name]
+		if id < 0 {
+			continue
+		}
+		if other, found := interfaceByID[id]; found {
+			return fmt.Errorf("VXLAN id %d would be used by both %s and %s at the node", id, other, name)
+		}
+		interfaceByID[id] = name
+	}
+	return nil
+}
+
+// hasVxlans returns true if the desired state configures VXLAN interfaces
+func hasVxlans(desiredState nmstatev1alpha1.State) (bool, error) {
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return false, fmt.Errorf("error converting desired state to JSON: %v", err)
+	}
+	return gjson.ParseBytes(desiredStateJSON).Get("interfaces.#(type==vxlan)").Exists(), nil
+}
+
+// checkVxlanIDs fails if the desired state VXLAN interfaces reuse the id of
+// another VXLAN interface at the node
+func checkVxlanIDs(desiredState nmstatev1alpha1.State) error {
+	vxlans, err := hasVxlans(desiredState)
+	if err != nil || !vxlans {
+		return err
+	}
+
+	currentState, err := show()
+	if err != nil {
+		return err
+	}
+	return vxlanIDConflicts(nmstatev1alpha1.State{Raw: []byte(currentState)}, desiredState)
+}
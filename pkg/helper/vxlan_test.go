@@ -0,0 +1,53 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("VXLAN ids", func() {
+	currentState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+- name: vxlan10
+  type: vxlan
+  state: up
+  vxlan:
+    base-iface: eth1
+    id: 10
+    remote: 192.0.2.2
+    destination-port: 4789
+`)
+	vxlan := func(name string, id string) string {
+		return `- name: ` + name + `
+  type: vxlan
+  state: up
+  vxlan:
+    base-iface: eth1
+    id: ` + id + `
+`
+	}
+	DescribeTable("conflicts with the node",
+		func(desiredState string, expectedErr string) {
+			err := vxlanIDConflicts(currentState, nmstatev1alpha1.NewState("interfaces:\n"+desiredState))
+			if expectedErr == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedErr))
+			}
+		},
+		Entry("with a new id", vxlan("vxlan20", "20"), ""),
+		Entry("with the id of the same interface", vxlan("vxlan10", "10"), ""),
+		Entry("with the id of another node interface", vxlan("vxlan20", "10"), "VXLAN id 10 would be used by both vxlan10 and vxlan20 at the node"),
+		Entry("with the id of another node interface being removed", vxlan("vxlan20", "10")+`- name: vxlan10
+  type: vxlan
+  state: absent
+`, ""),
+		Entry("with the id of a node interface being changed", vxlan("vxlan20", "10")+vxlan("vxlan10", "11"), ""),
+		Entry("with the same id twice", vxlan("vxlan20", "20")+vxlan("vxlan21", "20"), "VXLAN id 20 would be used by both vxlan20 and vxlan21 at the node"),
+	)
+})
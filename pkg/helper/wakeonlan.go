@@ -0,0 +1,291 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const ethtoolCommand = "ethtool"
+
+// nmstate does not configure Wake-on-LAN, the interfaces wake-on-lan modes
+// are set with ethtool after applying the rest of the desired state, an
+// empty list disables it
+const wakeOnLanKey = "wake-on-lan"
+
+// wakeOnLanModes are the Wake-on-LAN modes with their ethtool letters, in
+// the ethtool order
+var wakeOnLanModes = []struct {
+	name   string
+	letter string
+}{
+	{"phy", "p"},
+	{"unicast", "u"},
+	{"multicast", "m"},
+	{"broadcast", "b"},
+	{"arp", "a"},
+	{"magic", "g"},
+	{"secureon", "s"},
+}
+
+// ethtool letter of the disabled Wake-on-LAN
+const wakeOnLanDisabled = "d"
+
+func ethtool(arguments ...string) (string, error) {
+	cmd := exec.Command(ethtoolCommand, arguments...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := runCommand(cmd); err != nil {
+		return "", fmt.Errorf("failed to execute %s %s: '%v', '%s', '%s'", ethtoolCommand, strings.Join(arguments, " "), err, stdout.String(), stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// wakeOnLanLetters returns the ethtool letters of the modes, in the ethtool
+// order, failing with unknown modes
+func wakeOnLanLetters(iface string, modes []gjson.Result) (string, error) {
+	desired := map[string]bool{}
+	for _, mode := range modes {
+		desired[mode.String()] = true
+	}
+	letters := ""
+	for _, mode := range wakeOnLanModes {
+		if desired[mode.name] {
+			letters += mode.letter
+			delete(desired, mode.name)
+		}
+	}
+	for _, mode := range modes {
+		if desired[mode.String()] {
+			return "", fmt.Errorf("unsupported interface %s wake-on-lan mode %q", iface, mode.String())
+		}
+	}
+	if letters == "" {
+		letters = wakeOnLanDisabled
+	}
+	return letters, nil
+}
+
+// wakeOnLanNames returns the modes of the ethtool letters, in the ethtool
+// order, without the disabled one
+func wakeOnLanNames(letters string) []string {
+	names := []string{}
+	for _, mode := range wakeOnLanModes {
+		if strings.Contains(letters, mode.letter) {
+			names = append(names, mode.name)
+		}
+	}
+	return names
+}
+
+// getWakeOnLan returns the ethtool letters of the desired state interfaces
+// Wake-on-LAN modes, failing with unknown modes. Absent interfaces are
+// ignored.
+func getWakeOnLan(desiredState nmstatev1alpha1.State) (map[string]string, error) {
+	wakeOnLan := map[string]string{}
+	if len(desiredState.Raw) == 0 {
+		return wakeOnLan, nil
+	}
+
+	desiredStateJSON, err := yaml.YAMLToJSON([]byte(desiredState.Raw))
+	if err != nil {
+		return wakeOnLan, fmt.Errorf("error converting desiredState to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		modes := iface.Get(wakeOnLanKey)
+		if !modes.Exists() || iface.Get("state").String() == "absent" {
+			continue
+		}
+		name := iface.Get("name").String()
+		if !modes.IsArray() {
+			return wakeOnLan, fmt.Errorf("invalid interface %s wake-on-lan %s, it has to be a list of modes", name, modes.Raw)
+		}
+		letters, err := wakeOnLanLetters(name, modes.Array())
+		if err != nil {
+			return wakeOnLan, err
+		}
+		wakeOnLan[name] = letters
+	}
+	return wakeOnLan, nil
+}
+
+// stripWakeOnLan removes the Wake-on-LAN modes, not supported by nmstate,
+// from the desired state
+func stripWakeOnLan(desiredState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	return stripInterfacesKeys(desiredState, wakeOnLanKey)
+}
+
+// parseWakeOnLan returns the supported and the current Wake-on-LAN letters
+// from the output of "ethtool <iface>", the supported ones are empty if the
+// driver does not support Wake-on-LAN at all
+func parseWakeOnLan(output string) (string, string) {
+	supported, current := "", ""
+	for _, line := range strings.Split(output, "\n") {
+		keyValue := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+		switch strings.TrimSpace(keyValue[0]) {
+		case "Supports Wake-on":
+			supported = strings.TrimSpace(keyValue[1])
+		case "Wake-on":
+			current = strings.TrimSpace(keyValue[1])
+		}
+	}
+	return supported, current
+}
+
+func sortedWakeOnLanInterfaces(wakeOnLan map[string]string) []string {
+	interfaces := []string{}
+	for iface := range wakeOnLan {
+		interfaces = append(interfaces, iface)
+	}
+	sort.Strings(interfaces)
+	return interfaces
+}
+
+// unsupportedWakeOnLan returns the modes of the letters not supported by
+// the interface driver
+func unsupportedWakeOnLan(letters string, supported string) []string {
+	unsupported := []string{}
+	for _, mode := range wakeOnLanModes {
+		if strings.Contains(letters, mode.letter) && !strings.Contains(supported, mode.letter) {
+			unsupported = append(unsupported, mode.name)
+		}
+	}
+	return unsupported
+}
+
+// checkWakeOnLan fails if the desired state Wake-on-LAN modes are not
+// supported by the interfaces drivers, before applying anything
+func checkWakeOnLan(wakeOnLan map[string]string) error {
+	for _, iface := range sortedWakeOnLanInterfaces(wakeOnLan) {
+		letters := wakeOnLan[iface]
+		if letters == wakeOnLanDisabled {
+			continue
+		}
+		output, err := ethtool(iface)
+		if err != nil {
+			return fmt.Errorf("failed checking interface %s wake-on-lan support: %v", iface, err)
+		}
+		supported, _ := parseWakeOnLan(output)
+		supportedModes := wakeOnLanNames(supported)
+		if len(supportedModes) == 0 {
+			return fmt.Errorf("interface %s driver does not support wake-on-lan", iface)
+		}
+		if unsupported := unsupportedWakeOnLan(letters, supported); len(unsupported) > 0 {
+			return fmt.Errorf("interface %s driver does not support wake-on-lan %s, it supports %s", iface, strings.Join(unsupported, ", "), strings.Join(supportedModes, ", "))
+		}
+	}
+	return nil
+}
+
+// applyWakeOnLan sets the interfaces Wake-on-LAN modes, they are not part
+// of the nmstate checkpoint so they are restored apart on rollback
+func applyWakeOnLan(wakeOnLan map[string]string) (string, error) {
+	output := ""
+	for _, iface := range sortedWakeOnLanInterfaces(wakeOnLan) {
+		letters := wakeOnLan[iface]
+		ethtoolOutput, err := ethtool("-s", iface, "wol", letters)
+		output += fmt.Sprintf("interface %s wake-on-lan %s output: %s\n", iface, letters, ethtoolOutput)
+		if err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
+
+// readWakeOnLan returns the Wake-on-LAN modes of the interfaces given, the
+// ones without Wake-on-LAN support are left out
+func readWakeOnLan(wakeOnLan map[string]string) map[string]string {
+	current := map[string]string{}
+	for _, iface := range sortedWakeOnLanInterfaces(wakeOnLan) {
+		output, err := ethtool(iface)
+		if err != nil {
+			continue
+		}
+		supported, letters := parseWakeOnLan(output)
+		if supported == "" || letters == "" {
+			continue
+		}
+		current[iface] = letters
+	}
+	return current
+}
+
+// restoreWakeOnLan sets back the Wake-on-LAN modes read before applying the
+// desired state
+func restoreWakeOnLan(previousWakeOnLan map[string]string) string {
+	output, err := applyWakeOnLan(previousWakeOnLan)
+	if err != nil {
+		log.Info(fmt.Sprintf("failed restoring interfaces wake-on-lan: %v", err))
+	}
+	return output
+}
+
+// addWakeOnLan reports the Wake-on-LAN modes at the current state
+// interfaces, the ones of interfaces without Wake-on-LAN support are
+// missing
+func addWakeOnLan(currentState nmstatev1alpha1.State, wakeOnLan map[string]string) (nmstatev1alpha1.State, error) {
+	var state map[string]interface{}
+	err := yaml.Unmarshal(currentState.Raw, &state)
+	if err != nil {
+		return currentState, err
+	}
+
+	interfaces, hasInterfaces := state["interfaces"].([]interface{})
+	if !hasInterfaces {
+		return currentState, nil
+	}
+
+	for _, iface := range interfaces {
+		iface, isMap := iface.(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		name, _ := iface["name"].(string)
+		letters, found := wakeOnLan[name]
+		if !found {
+			continue
+		}
+		iface[wakeOnLanKey] = wakeOnLanNames(letters)
+	}
+
+	reportedState, err := yaml.Marshal(state)
+	if err != nil {
+		return currentState, err
+	}
+	return nmstatev1alpha1.State{Raw: reportedState}, nil
+}
+
+// reportWakeOnLan reports the Wake-on-LAN modes of the ethernet interfaces,
+// the interfaces ethtool fails with are left out
+func reportWakeOnLan(currentState nmstatev1alpha1.State) (nmstatev1alpha1.State, error) {
+	currentStateJSON, err := yaml.YAMLToJSON([]byte(currentState.Raw))
+	if err != nil {
+		return currentState, fmt.Errorf("error converting currentState to JSON: %v", err)
+	}
+
+	wakeOnLan := map[string]string{}
+	for _, name := range gjson.ParseBytes(currentStateJSON).Get("interfaces.#(type==ethernet)#.name").Array() {
+		output, err := ethtool(name.String())
+		if err != nil {
+			continue
+		}
+		supported, current := parseWakeOnLan(output)
+		if supported == "" || current == "" {
+			continue
+		}
+		wakeOnLan[name.String()] = current
+	}
+	return addWakeOnLan(currentState, wakeOnLan)
+}
@@ -0,0 +1,110 @@
+package helper
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("Wake-on-LAN", func() {
+	desiredState := nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  wake-on-lan:
+  - unicast
+  - magic
+- name: eth2
+  type: ethernet
+  state: up
+  wake-on-lan: []
+- name: eth3
+  type: ethernet
+  state: absent
+  wake-on-lan:
+  - magic
+`)
+
+	It("should take the modes as ethtool letters and strip them from the nmstate desired state", func() {
+		wakeOnLan, err := getWakeOnLan(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wakeOnLan).To(Equal(map[string]string{
+			"eth1": "ug",
+			"eth2": "d",
+		}))
+
+		strippedState, err := stripWakeOnLan(desiredState)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strippedState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+- name: eth2
+  type: ethernet
+  state: up
+- name: eth3
+  type: ethernet
+  state: absent
+`))
+	})
+
+	DescribeTable("with invalid modes",
+		func(wakeOnLan string) {
+			_, err := getWakeOnLan(nmstatev1alpha1.NewState("interfaces:\n- name: eth1\n  wake-on-lan: " + wakeOnLan + "\n"))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("not a list", "magic"),
+		Entry("unknown mode", "[magic, lightning]"),
+	)
+
+	It("should parse the supported and current modes of the ethtool output", func() {
+		supported, current := parseWakeOnLan(`Settings for eth1:
+	Supported ports: [ TP ]
+	Auto-negotiation: on
+	Supports Wake-on: pumbg
+	Wake-on: g
+	Current message level: 0x00000007 (7)
+	Link detected: yes
+`)
+		Expect(supported).To(Equal("pumbg"))
+		Expect(current).To(Equal("g"))
+		Expect(wakeOnLanNames(supported)).To(Equal([]string{"phy", "unicast", "multicast", "broadcast", "magic"}))
+	})
+
+	It("should return the modes not supported by the driver", func() {
+		Expect(unsupportedWakeOnLan("ugs", "pumbg")).To(Equal([]string{"secureon"}))
+		Expect(unsupportedWakeOnLan("g", "pumbg")).To(BeEmpty())
+	})
+
+	It("should report the modes at the current state", func() {
+		reportedState, err := addWakeOnLan(nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+- name: eth2
+  type: ethernet
+  state: up
+- name: veth1
+  type: ethernet
+  state: up
+`), map[string]string{"eth1": "ug", "eth2": "d"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reportedState.String()).To(MatchYAML(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  wake-on-lan:
+  - unicast
+  - magic
+- name: eth2
+  type: ethernet
+  state: up
+  wake-on-lan: []
+- name: veth1
+  type: ethernet
+  state: up
+`))
+	})
+})
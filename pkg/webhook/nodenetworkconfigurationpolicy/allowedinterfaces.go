@@ -0,0 +1,34 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"strings"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/allowlist"
+)
+
+// The operator wide allowed interfaces, the same ones the handler enforces
+var allowedInterfaces allowlist.AllowList
+
+func init() {
+	var err error
+	allowedInterfaces, err = allowlist.FromEnvironment()
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// validateAllowedInterfaces denies the desired states modifying interfaces
+// outside the allow list, the desired state patches are rendered per node
+// so they are checked by the handler only
+func validateAllowedInterfaces(desiredState nmstatev1alpha1.State, allowList allowlist.AllowList) error {
+	notAllowed, err := allowList.NotAllowedInterfaces(desiredState)
+	if err != nil {
+		return err
+	}
+	if len(notAllowed) > 0 {
+		return fmt.Errorf("desired state modifies interfaces %s outside the allowed interfaces %s", strings.Join(notAllowed, ", "), allowList)
+	}
+	return nil
+}
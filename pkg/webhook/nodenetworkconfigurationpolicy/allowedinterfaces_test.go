@@ -0,0 +1,109 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/allowlist"
+)
+
+var _ = Describe("NNCP allowed interfaces validation", func() {
+	DescribeTable("desired state interfaces",
+		func(patterns string, interfaces string, expectedError string) {
+			allowList, err := allowlist.New(patterns)
+			Expect(err).ToNot(HaveOccurred())
+			err = validateAllowedInterfaces(nmstatev1alpha1.NewState("interfaces:\n"+interfaces), allowList)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+		Entry("without allow list, should allow all", "", "- name: eth0\n  type: ethernet\n", ""),
+		Entry("with allowed interfaces, should allow", "bond*, vlan*", `- name: bond0
+  type: bond
+  link-aggregation:
+    slaves:
+    - bond0s1
+- name: vlan100
+  type: vlan
+  vlan:
+    base-iface: bond0
+    id: 100
+`, ""),
+		Entry("with a bond slave not allowed, should deny", "bond*, vlan*", `- name: bond0
+  type: bond
+  link-aggregation:
+    slaves:
+    - eth1
+    - eth0
+`, "desired state modifies interfaces eth0, eth1 outside the allowed interfaces bond*, vlan*"),
+		Entry("with a bridge port not allowed, should deny", "br*", `- name: br1
+  type: linux-bridge
+  bridge:
+    port:
+    - name: eth0
+`, "desired state modifies interfaces eth0 outside the allowed interfaces br*"),
+		Entry("with an interface identified by address, should not check the alias", "bond*", `- name: bond0
+  type: bond
+  link-aggregation:
+    slaves:
+    - uplink1
+- name: uplink1
+  match:
+    mac-address: 52:55:00:d1:56:01
+`, ""),
+		Entry("with a removed interface not allowed, should deny", "bond*", `- name: eth0
+  state: absent
+`, "desired state modifies interfaces eth0 outside the allowed interfaces bond*"),
+	)
+
+	DescribeTable("desired state routes and route rules",
+		func(patterns string, desiredState string, expectedError string) {
+			allowList, err := allowlist.New(patterns)
+			Expect(err).ToNot(HaveOccurred())
+			err = validateAllowedInterfaces(nmstatev1alpha1.NewState(desiredState), allowList)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+		Entry("with routes at allowed interfaces, should allow", "bond*", `routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-address: 192.0.2.1
+    next-hop-interface: bond0
+    table-id: 100
+`, ""),
+		Entry("with a route at an interface not allowed, should deny", "bond*", `routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-address: 192.0.2.1
+    next-hop-interface: eth0
+`, "desired state modifies interfaces eth0 outside the allowed interfaces bond*"),
+		Entry("with a removed route at an interface not allowed, should deny", "bond*", `routes:
+  config:
+  - destination: 0.0.0.0/0
+    next-hop-interface: eth0
+    state: absent
+`, "desired state modifies interfaces eth0 outside the allowed interfaces bond*"),
+		Entry("with a route rule from an interface not allowed, should deny", "bond*", `route-rules:
+  config:
+  - iif: eth0
+    route-table: 100
+`, "desired state modifies interfaces eth0 outside the allowed interfaces bond*"),
+		Entry("with route rules without incoming interface, should allow", "bond*", `route-rules:
+  config:
+  - ip-from: 192.0.2.0/24
+    route-table: 100
+`, ""),
+	)
+
+	It("should fail with invalid patterns", func() {
+		_, err := allowlist.New("bond*, vlan[")
+		Expect(err).To(MatchError(ContainSubstring(`invalid allowed interfaces pattern "vlan["`)))
+	})
+})
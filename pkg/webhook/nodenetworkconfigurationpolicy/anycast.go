@@ -0,0 +1,19 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"net"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// validateAnycastAddresses checks that the anycast addresses are plain IP
+// addresses, the prefix length is the desired state one
+func validateAnycastAddresses(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec) error {
+	for _, address := range policySpec.AnycastAddresses {
+		if net.ParseIP(address) == nil {
+			return fmt.Errorf("anycast address %s is not an IP address", address)
+		}
+	}
+	return nil
+}
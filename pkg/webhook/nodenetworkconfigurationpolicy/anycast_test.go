@@ -0,0 +1,24 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP anycast addresses validation", func() {
+	It("should allow IPv4 and IPv6 addresses", func() {
+		Expect(validateAnycastAddresses(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{AnycastAddresses: []string{"192.0.2.1", "2001:db8::1"}})).To(Succeed())
+	})
+
+	It("should deny policies with addresses with prefix length", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.AnycastAddresses = []string{"192.0.2.1/32"}
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("anycast address 192.0.2.1/32 is not an IP address"))
+	})
+})
@@ -0,0 +1,24 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"time"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// nmstate takes several seconds applying bonds and bridges, shorter
+// timeouts would abort healthy applies
+const minApplyTimeout = 30 * time.Second
+
+// validateApplyTimeout checks that nmstate is given at least the minimum
+// time to apply the desired state
+func validateApplyTimeout(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec) error {
+	if policySpec.ApplyTimeout == nil {
+		return nil
+	}
+	if timeout := policySpec.ApplyTimeout.Duration; timeout < minApplyTimeout {
+		return fmt.Errorf("apply timeout %s is shorter than the minimum %s", timeout, minApplyTimeout)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP apply timeout validation", func() {
+	It("should allow timeouts from the minimum on", func() {
+		Expect(validateApplyTimeout(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{})).To(Succeed())
+		for _, timeout := range []time.Duration{30 * time.Second, 10 * time.Minute} {
+			Expect(validateApplyTimeout(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{ApplyTimeout: &metav1.Duration{Duration: timeout}})).To(Succeed(), timeout.String())
+		}
+	})
+
+	It("should deny policies with timeouts shorter than the minimum", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.ApplyTimeout = &metav1.Duration{Duration: 5 * time.Second}
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("apply timeout 5s is shorter than the minimum 30s"))
+	})
+})
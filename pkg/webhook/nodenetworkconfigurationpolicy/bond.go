@@ -0,0 +1,49 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const lacpBondMode = "802.3ad"
+
+// bondWarnings returns the bond option combinations of the desired state
+// that are accepted but slow down or prevent the failover
+func bondWarnings(desiredState nmstatev1alpha1.State) []string {
+	warnings := []string{}
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return warnings
+	}
+
+	for _, bond := range gjson.ParseBytes(desiredStateJSON).Get("interfaces.#(type==bond)#").Array() {
+		name := bond.Get("name").String()
+		mode := bond.Get("link-aggregation.mode").String()
+		options := bond.Get("link-aggregation.options")
+		miimon := options.Get("miimon")
+		arpInterval := options.Get("arp_interval").Int()
+
+		if miimon.Exists() && miimon.Int() == 0 && arpInterval == 0 {
+			warnings = append(warnings, fmt.Sprintf("bond %s has miimon 0 and no arp_interval, link failures will not be detected and %s will not fail over", name, mode))
+		}
+		if options.Get("lacp_rate").Exists() && mode != lacpBondMode {
+			warnings = append(warnings, fmt.Sprintf("bond %s lacp_rate is ignored with mode %s", name, mode))
+		}
+		for _, delay := range []string{"updelay", "downdelay"} {
+			value := options.Get(delay).Int()
+			if value == 0 {
+				continue
+			}
+			if miimon.Exists() && miimon.Int() == 0 {
+				warnings = append(warnings, fmt.Sprintf("bond %s %s is ignored with miimon 0", name, delay))
+			} else if miimon.Int() > 0 && value%miimon.Int() != 0 {
+				warnings = append(warnings, fmt.Sprintf("bond %s %s %d is not a multiple of miimon %d, it will be rounded down", name, delay, value, miimon.Int()))
+			}
+		}
+	}
+	return warnings
+}
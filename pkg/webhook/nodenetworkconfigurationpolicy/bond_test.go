@@ -0,0 +1,50 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP bond options warnings", func() {
+	bond := func(mode string, options string) nmstatev1alpha1.State {
+		return nmstatev1alpha1.NewState(`interfaces:
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: ` + mode + `
+    slaves:
+    - eth1
+    - eth2
+    options:
+` + options)
+	}
+	DescribeTable("dangerous combinations",
+		func(desiredState nmstatev1alpha1.State, expected []string) {
+			Expect(bondWarnings(desiredState)).To(Equal(expected))
+		},
+		Entry("with sane LACP options", bond("802.3ad", `      miimon: '100'
+      lacp_rate: fast
+      updelay: 200
+      downdelay: 200
+`), []string{}),
+		Entry("with miimon 0 at active-backup", bond("active-backup", `      miimon: '0'
+`), []string{"bond bond0 has miimon 0 and no arp_interval, link failures will not be detected and active-backup will not fail over"}),
+		Entry("with miimon 0 and arp monitoring", bond("active-backup", `      miimon: '0'
+      arp_interval: 100
+      arp_ip_target: 192.0.2.1
+`), []string{}),
+		Entry("with lacp_rate on other mode", bond("balance-rr", `      lacp_rate: fast
+`), []string{"bond bond0 lacp_rate is ignored with mode balance-rr"}),
+		Entry("with delays and miimon 0", bond("balance-rr", `      miimon: '0'
+      arp_interval: 100
+      updelay: 200
+`), []string{"bond bond0 updelay is ignored with miimon 0"}),
+		Entry("with delays not multiple of miimon", bond("802.3ad", `      miimon: '100'
+      downdelay: 250
+`), []string{"bond bond0 downdelay 250 is not a multiple of miimon 100, it will be rounded down"}),
+	)
+})
@@ -25,7 +25,7 @@ func setConditionsUnknown(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy)
 			"", "")
 	}
 	policy.Status.Conditions = unknownConditions
-	return policy
+	return setDeprecatedCondition(policy)
 }
 
 func atEmptyConditions(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) bool {
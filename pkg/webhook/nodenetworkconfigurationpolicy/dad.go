@@ -0,0 +1,36 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// dadWarnings returns the interfaces of the desired state with IPv6
+// duplicate address detection disabled, their addresses can conflict with
+// the rest of the hosts at the segment unnoticed. Dummy interfaces have no
+// segment, they are not warned about.
+func dadWarnings(desiredState nmstatev1alpha1.State) []string {
+	warnings := []string{}
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return warnings
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		if iface.Get("type").String() == "dummy" || iface.Get("state").String() == "absent" {
+			continue
+		}
+		name := iface.Get("name").String()
+		for _, sysctl := range []string{"accept_dad", "dad_transmits"} {
+			value := iface.Get("sysctl.ipv6." + sysctl)
+			if value.Type == gjson.Number && value.Int() == 0 {
+				warnings = append(warnings, fmt.Sprintf("interface %s has IPv6 duplicate address detection disabled with %s 0, its addresses can conflict with other hosts at the segment unnoticed", name, sysctl))
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,36 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP duplicate address detection warnings", func() {
+	iface := func(ifaceType string, sysctls string) nmstatev1alpha1.State {
+		return nmstatev1alpha1.NewState(`interfaces:
+- name: vip0
+  type: ` + ifaceType + `
+  state: up
+  sysctl:
+    ipv6:
+` + sysctls)
+	}
+	DescribeTable("disabled detection",
+		func(desiredState nmstatev1alpha1.State, expected []string) {
+			Expect(dadWarnings(desiredState)).To(Equal(expected))
+		},
+		Entry("with fewer transmits", iface("ethernet", `      dad_transmits: 1
+`), []string{}),
+		Entry("with no transmits", iface("ethernet", `      dad_transmits: 0
+`), []string{"interface vip0 has IPv6 duplicate address detection disabled with dad_transmits 0, its addresses can conflict with other hosts at the segment unnoticed"}),
+		Entry("with DAD not accepted", iface("vlan", `      accept_dad: 0
+`), []string{"interface vip0 has IPv6 duplicate address detection disabled with accept_dad 0, its addresses can conflict with other hosts at the segment unnoticed"}),
+		Entry("with DAD reset to default", iface("ethernet", `      accept_dad: default
+`), []string{}),
+		Entry("at a dummy interface", iface("dummy", `      dad_transmits: 0
+`), []string{}),
+	)
+})
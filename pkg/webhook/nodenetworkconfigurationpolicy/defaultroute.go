@@ -0,0 +1,127 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+const (
+	ipv4DefaultDestination = "0.0.0.0/0"
+	ipv6DefaultDestination = "::/0"
+)
+
+// dynamicRouteProtocols maps the default destinations to the protocols of the
+// default routes installed by DHCP or autoconf and the interface setting that
+// disables them
+var dynamicRouteProtocols = map[string]struct {
+	protocols []string
+	family    string
+}{
+	ipv4DefaultDestination: {protocols: []string{"dhcp"}, family: "ipv4"},
+	ipv6DefaultDestination: {protocols: []string{"dhcp", "ra"}, family: "ipv6"},
+}
+
+// defaultRoute is a default route configured at the desired state
+type defaultRoute struct {
+	destination      string
+	nextHopInterface string
+	metric           int64
+	hasMetric        bool
+}
+
+func (r defaultRoute) String() string {
+	return fmt.Sprintf("default route %s via %s", r.destination, r.nextHopInterface)
+}
+
+func desiredDefaultRoutes(desiredState gjson.Result) []defaultRoute {
+	routes := []defaultRoute{}
+	for _, route := range desiredState.Get("routes.config").Array() {
+		destination := route.Get("destination").String()
+		if _, isDefault := dynamicRouteProtocols[destination]; !isDefault || route.Get("state").String() == "absent" {
+			continue
+		}
+		metric := route.Get("metric")
+		routes = append(routes, defaultRoute{
+			destination:      destination,
+			nextHopInterface: route.Get("next-hop-interface").String(),
+			metric:           metric.Int(),
+			hasMetric:        metric.Exists(),
+		})
+	}
+	return routes
+}
+
+// autoGatewayDisabled returns true if the desired state stops the address
+// autoconfiguration of the interface from installing a default route
+func autoGatewayDisabled(desiredState gjson.Result, interfaceName string, family string) bool {
+	for _, iface := range desiredState.Get("interfaces").Array() {
+		if iface.Get("name").String() != interfaceName {
+			continue
+		}
+		autoGateway := iface.Get(family + ".auto-gateway")
+		return autoGateway.Exists() && !autoGateway.Bool()
+	}
+	return false
+}
+
+func isDynamicRoute(route nmstatev1alpha1.RunningRoute) bool {
+	for _, protocol := range dynamicRouteProtocols[route.Destination].protocols {
+		if route.Protocol == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultRouteWarnings returns the default routes of the desired state that
+// do not set their next hop interface and metric, or that would be installed
+// next to another default route with unpredictable precedence, either from
+// the desired state itself or from DHCP and autoconf at the given nodes
+func defaultRouteWarnings(desiredState nmstatev1alpha1.State, nodeStates []nmstatev1alpha1.NodeNetworkState) []string {
+	warnings := []string{}
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return warnings
+	}
+	desired := gjson.ParseBytes(desiredStateJSON)
+
+	routes := desiredDefaultRoutes(desired)
+	for i, route := range routes {
+		if route.nextHopInterface == "" {
+			warnings = append(warnings, fmt.Sprintf("default route %s has no next-hop-interface", route.destination))
+			continue
+		}
+		if !route.hasMetric {
+			warnings = append(warnings, fmt.Sprintf("%s has no metric, its precedence over other default routes is unpredictable", route))
+			continue
+		}
+		for _, other := range routes[:i] {
+			if other.destination == route.destination && other.hasMetric && other.metric == route.metric {
+				warnings = append(warnings, fmt.Sprintf("%s has the same metric %d as the %s", route, route.metric, other))
+			}
+		}
+
+		family := dynamicRouteProtocols[route.destination].family
+		for _, nodeState := range nodeStates {
+			for _, runningRoute := range nodeState.Status.RunningRoutes {
+				if runningRoute.Destination != route.destination || runningRoute.NextHopInterface == route.nextHopInterface || !isDynamicRoute(runningRoute) {
+					continue
+				}
+				if autoGatewayDisabled(desired, runningRoute.NextHopInterface, family) {
+					continue
+				}
+				metric := int64(runningRoute.Metric)
+				if metric < route.metric {
+					warnings = append(warnings, fmt.Sprintf("node %s %s default route via %s metric %d takes precedence over the %s metric %d", nodeState.Name, runningRoute.Protocol, runningRoute.NextHopInterface, metric, route, route.metric))
+				} else if metric == route.metric {
+					warnings = append(warnings, fmt.Sprintf("node %s %s default route via %s has the same metric %d as the %s", nodeState.Name, runningRoute.Protocol, runningRoute.NextHopInterface, metric, route))
+				}
+			}
+		}
+	}
+	return warnings
+}
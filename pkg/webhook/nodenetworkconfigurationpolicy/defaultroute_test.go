@@ -0,0 +1,100 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP default route warnings", func() {
+	dhcpNodeState := nmstatev1alpha1.NodeNetworkState{
+		ObjectMeta: metav1.ObjectMeta{Name: "node01"},
+		Status: nmstatev1alpha1.NodeNetworkStateStatus{
+			RunningRoutes: []nmstatev1alpha1.RunningRoute{
+				{Destination: "0.0.0.0/0", NextHopAddress: "192.168.66.2", NextHopInterface: "eth0", Metric: 100, Table: "main", Protocol: "dhcp"},
+				{Destination: "::/0", NextHopAddress: "fe80::1", NextHopInterface: "eth0", Metric: 100, Table: "main", Protocol: "ra"},
+				{Destination: "10.0.0.0/8", NextHopAddress: "192.168.66.3", NextHopInterface: "eth1", Table: "main", Protocol: "dhcp"},
+			},
+		},
+	}
+	DescribeTable("conflicting default routes",
+		func(desiredState string, expected []string) {
+			Expect(defaultRouteWarnings(nmstatev1alpha1.NewState(desiredState), []nmstatev1alpha1.NodeNetworkState{dhcpNodeState})).To(Equal(expected))
+		},
+		Entry("without default routes", `routes:
+  config:
+  - destination: 10.0.0.0/8
+    next-hop-address: 192.168.66.3
+    next-hop-interface: eth1
+`, []string{}),
+		Entry("with explicit preferred default route", `routes:
+  config:
+  - destination: 0.0.0.0/0
+    next-hop-address: 192.168.67.1
+    next-hop-interface: eth1
+    metric: 50
+`, []string{}),
+		Entry("with default route removal", `routes:
+  config:
+  - destination: 0.0.0.0/0
+    next-hop-interface: eth0
+    state: absent
+`, []string{}),
+		Entry("without next-hop-interface", `routes:
+  config:
+  - destination: 0.0.0.0/0
+    next-hop-address: 192.168.67.1
+    metric: 50
+`, []string{"default route 0.0.0.0/0 has no next-hop-interface"}),
+		Entry("without metric", `routes:
+  config:
+  - destination: 0.0.0.0/0
+    next-hop-address: 192.168.67.1
+    next-hop-interface: eth1
+`, []string{"default route 0.0.0.0/0 via eth1 has no metric, its precedence over other default routes is unpredictable"}),
+		Entry("with the same metric twice", `routes:
+  config:
+  - destination: 0.0.0.0/0
+    next-hop-address: 192.168.67.1
+    next-hop-interface: eth1
+    metric: 50
+  - destination: 0.0.0.0/0
+    next-hop-address: 192.168.68.1
+    next-hop-interface: eth2
+    metric: 50
+`, []string{"default route 0.0.0.0/0 via eth2 has the same metric 50 as the default route 0.0.0.0/0 via eth1"}),
+		Entry("with lower precedence than DHCP", `routes:
+  config:
+  - destination: 0.0.0.0/0
+    next-hop-address: 192.168.67.1
+    next-hop-interface: eth1
+    metric: 200
+`, []string{"node node01 dhcp default route via eth0 metric 100 takes precedence over the default route 0.0.0.0/0 via eth1 metric 200"}),
+		Entry("with the same metric as router advertisement", `routes:
+  config:
+  - destination: ::/0
+    next-hop-address: 2001:db8::1
+    next-hop-interface: eth1
+    metric: 100
+`, []string{"node node01 ra default route via eth0 has the same metric 100 as the default route ::/0 via eth1"}),
+		Entry("with DHCP auto-gateway disabled", `interfaces:
+- name: eth0
+  type: ethernet
+  state: up
+  ipv4:
+    enabled: true
+    dhcp: true
+    auto-gateway: false
+routes:
+  config:
+  - destination: 0.0.0.0/0
+    next-hop-address: 192.168.67.1
+    next-hop-interface: eth1
+    metric: 200
+`, []string{}),
+	)
+})
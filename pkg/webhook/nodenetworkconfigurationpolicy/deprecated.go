@@ -0,0 +1,121 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	corev1 "k8s.io/api/core/v1"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// The webhook runs at the handlers, this is the nmstate version they apply
+// the desired states with
+var nmstateVersion = nmstate.NmstateVersion
+
+// deprecatedField is a desired state field that nmstate still accepts but
+// has replaced since version, the path is relative to the interfaces and to
+// the OVS bridges ports, that configure bonds the same way
+type deprecatedField struct {
+	path        string
+	replacement string
+	version     string
+}
+
+// The nmstate deprecated fields, they fail to apply once the nodes run the
+// nmstate version removing them
+var deprecatedFields = []deprecatedField{
+	{path: "link-aggregation.slaves", replacement: "link-aggregation.port", version: "1.0"},
+}
+
+// replaced returns true if the nmstate version has the field replacement,
+// before that there is nothing to switch to
+func (f deprecatedField) replaced(version string) bool {
+	current, currentErr := majorMinorVersion(version)
+	replacement, replacementErr := majorMinorVersion(f.version)
+	if currentErr != nil || replacementErr != nil {
+		return false
+	}
+	return current[0] > replacement[0] || (current[0] == replacement[0] && current[1] >= replacement[1])
+}
+
+// majorMinorVersion returns the major and minor numbers of the version,
+// like 1.0 for 1.0.2
+func majorMinorVersion(version string) ([2]int, error) {
+	numbers := [2]int{}
+	parts := strings.SplitN(strings.TrimSpace(version), ".", 3)
+	if len(parts) < 2 {
+		return numbers, fmt.Errorf("invalid version %q", version)
+	}
+	for i := range numbers {
+		number, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return numbers, fmt.Errorf("invalid version %q: %v", version, err)
+		}
+		numbers[i] = number
+	}
+	return numbers, nil
+}
+
+func (f deprecatedField) warning(owner string) string {
+	return fmt.Sprintf("%s %s is deprecated since nmstate %s, replaced by %s", owner, f.path, f.version, f.replacement)
+}
+
+// deprecatedFieldWarnings returns the deprecated fields the desired state
+// uses, naming their replacement, only the ones the handlers nmstate has
+// replaced are reported
+func deprecatedFieldWarnings(desiredState nmstatev1alpha1.State) []string {
+	warnings := []string{}
+	version, err := nmstateVersion()
+	if err != nil {
+		log.Info(fmt.Sprintf("failed retrieving nmstate version, not checking deprecated fields: %v", err))
+		return warnings
+	}
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return warnings
+	}
+	fields := []deprecatedField{}
+	for _, field := range deprecatedFields {
+		if field.replaced(version) {
+			fields = append(fields, field)
+		}
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		name := iface.Get("name").String()
+		for _, field := range fields {
+			if iface.Get(field.path).Exists() {
+				warnings = append(warnings, field.warning(fmt.Sprintf("interface %s", name)))
+			}
+		}
+		for _, port := range iface.Get("bridge.port").Array() {
+			for _, field := range fields {
+				if port.Get(field.path).Exists() {
+					warnings = append(warnings, field.warning(fmt.Sprintf("interface %s port %s", name, port.Get("name").String())))
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// setDeprecatedCondition sets the Deprecated condition if the policy
+// desired state uses deprecated fields, it's not set otherwise
+func setDeprecatedCondition(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+	warnings := deprecatedFieldWarnings(policy.Spec.DesiredState)
+	if len(warnings) == 0 {
+		return policy
+	}
+	policy.Status.Conditions.Set(
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDeprecated,
+		corev1.ConditionTrue,
+		nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDeprecatedFields,
+		fmt.Sprintf("Desired state uses deprecated fields: %s", strings.Join(warnings, "; ")),
+	)
+	return policy
+}
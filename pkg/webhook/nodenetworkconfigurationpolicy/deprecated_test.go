@@ -0,0 +1,93 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP deprecated fields warnings", func() {
+	linuxBond := nmstatev1alpha1.NewState(`interfaces:
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: active-backup
+    slaves:
+    - eth1
+    - eth2
+`)
+	var previousNmstateVersion func() (string, error)
+
+	BeforeEach(func() {
+		previousNmstateVersion = nmstateVersion
+		nmstateVersion = func() (string, error) { return "1.0.2", nil }
+	})
+
+	AfterEach(func() {
+		nmstateVersion = previousNmstateVersion
+	})
+
+	DescribeTable("desired states",
+		func(desiredState nmstatev1alpha1.State, expected []string) {
+			Expect(deprecatedFieldWarnings(desiredState)).To(Equal(expected))
+		},
+		Entry("without deprecated fields", nmstatev1alpha1.NewState(`interfaces:
+- name: br1
+  type: linux-bridge
+  state: up
+  bridge:
+    port:
+    - name: eth1
+`), []string{}),
+		Entry("with linux bond slaves", linuxBond, []string{"interface bond0 link-aggregation.slaves is deprecated since nmstate 1.0, replaced by link-aggregation.port"}),
+		Entry("with OVS bond slaves", nmstatev1alpha1.NewState(`interfaces:
+- name: br1
+  type: ovs-bridge
+  state: up
+  bridge:
+    port:
+    - name: bond1
+      link-aggregation:
+        mode: balance-slb
+        slaves:
+        - name: eth1
+        - name: eth2
+`), []string{"interface br1 port bond1 link-aggregation.slaves is deprecated since nmstate 1.0, replaced by link-aggregation.port"}),
+	)
+
+	DescribeTable("with the handlers nmstate version",
+		func(version string, expectedWarnings int) {
+			nmstateVersion = func() (string, error) { return version, nil }
+			Expect(deprecatedFieldWarnings(linuxBond)).To(HaveLen(expectedWarnings))
+		},
+		Entry("replacing the field, should warn", "1.0.0", 1),
+		Entry("of a later major version, should warn", "2.1.0", 1),
+		Entry("not having the replacement yet, should not warn", "0.2.6", 0),
+		Entry("unknown, should not warn", "unknown", 0),
+	)
+
+	It("should not warn if the nmstate version cannot be retrieved", func() {
+		nmstateVersion = func() (string, error) { return "", fmt.Errorf("nmstatectl not found") }
+		Expect(deprecatedFieldWarnings(linuxBond)).To(BeEmpty())
+	})
+
+	It("should set the Deprecated condition along with the unknown ones", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{DesiredState: linuxBond},
+		}
+		patchedPolicy := patchPolicy(policy, callSetConditionsUnknown(policy))
+		condition := patchedPolicy.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDeprecated)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionDeprecatedFields))
+		Expect(condition.Message).To(Equal("Desired state uses deprecated fields: interface bond0 link-aggregation.slaves is deprecated since nmstate 1.0, replaced by link-aggregation.port"))
+		Expect(patchedPolicy.Status.Conditions.Find(nmstatev1alpha1.NodeNetworkConfigurationPolicyConditionAvailable).Status).To(Equal(corev1.ConditionUnknown))
+	})
+})
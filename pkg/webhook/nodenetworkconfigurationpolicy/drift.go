@@ -0,0 +1,21 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+
+	"github.com/gobwas/glob"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// validateDriftIgnore checks that the drift ignore paths are valid globs,
+// keys are separated by dots like at the handler drift detection
+func validateDriftIgnore(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec) error {
+	for _, path := range policySpec.DriftIgnore {
+		_, err := glob.Compile(path, '.')
+		if err != nil {
+			return fmt.Errorf("driftIgnore path %q is not a valid glob: %v", path, err)
+		}
+	}
+	return nil
+}
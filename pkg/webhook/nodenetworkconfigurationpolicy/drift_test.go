@@ -0,0 +1,26 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP drift ignore validation", func() {
+	It("should allow valid globs", func() {
+		Expect(validateDriftIgnore(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+			DriftIgnore: []string{"interfaces.*.ipv6.address", "interfaces.eth1.**"},
+		})).To(Succeed())
+	})
+
+	It("should deny policies with invalid globs", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.DriftIgnore = []string{"interfaces.[eth1"}
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring(`driftIgnore path "interfaces.[eth1" is not a valid glob`))
+	})
+})
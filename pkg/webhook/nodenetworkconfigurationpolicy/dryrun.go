@@ -0,0 +1,228 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/controller/nodenetworkconfigurationpolicy/selectors"
+)
+
+const (
+	dryRunSummaryAuditAnnotation = "dry-run-summary"
+	warningsAuditAnnotation      = "warnings"
+
+	// DryRunSummaryAnnotation has the dry-run summary at the policy returned
+	// by server side dry-run requests, dry-run policies are never persisted
+	// so it's never stored
+	DryRunSummaryAnnotation = "nmstate.io/dry-run-summary"
+)
+
+// dryRunHandler summarizes the network impact of a policy for server side
+// dry-run requests and reports the desired state warnings for all of them,
+// it does not mutate the policy.
+type dryRunHandler struct {
+	client client.Client
+}
+
+// InjectClient injects the manager client into the handler
+func (h *dryRunHandler) InjectClient(c client.Client) error {
+	h.client = c
+	return nil
+}
+
+func (h *dryRunHandler) Handle(ctx context.Context, req webhook.AdmissionRequest) webhook.AdmissionResponse {
+	policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+	err := json.Unmarshal(req.Object.Raw, &policy)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrapf(err, "failed decoding policy: %s", string(req.Object.Raw)))
+	}
+
+	warnings := strings.Join(h.warnings(policy), "; ")
+	if warnings != "" {
+		log.Info(fmt.Sprintf("policy %s warnings: %s", policy.Name, warnings))
+	}
+
+	if req.DryRun == nil || !*req.DryRun {
+		response := admission.Allowed("not a dry-run")
+		if warnings != "" {
+			response.AuditAnnotations = map[string]string{warningsAuditAnnotation: warnings}
+		}
+		return response
+	}
+
+	summary, err := h.dryRunSummaryWithWarnings(policy, warnings)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	log.Info(fmt.Sprintf("policy %s %s", policy.Name, summary))
+
+	response := admission.Allowed(summary)
+	response.Result.Message = summary
+	response.AuditAnnotations = map[string]string{dryRunSummaryAuditAnnotation: summary}
+	if warnings != "" {
+		response.AuditAnnotations[warningsAuditAnnotation] = warnings
+	}
+	return response
+}
+
+// dryRunSummaryAnnotationHandler sets the dry-run summary as annotation of
+// the policy of server side dry-run requests, so it's returned to the user,
+// the admission warnings are not supported by the API server version
+// kubernetes-nmstate is built with.
+type dryRunSummaryAnnotationHandler struct {
+	dryRunHandler
+}
+
+func (h *dryRunSummaryAnnotationHandler) Handle(ctx context.Context, req webhook.AdmissionRequest) webhook.AdmissionResponse {
+	if req.DryRun == nil || !*req.DryRun {
+		return admission.Allowed("not a dry-run")
+	}
+
+	original := req.Object.Raw
+	policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+	err := json.Unmarshal(original, &policy)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrapf(err, "failed decoding policy: %s", string(original)))
+	}
+
+	summary, err := h.dryRunSummaryWithWarnings(policy, strings.Join(h.warnings(policy), "; "))
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if policy.Annotations == nil {
+		policy.Annotations = map[string]string{}
+	}
+	policy.Annotations[DryRunSummaryAnnotation] = summary
+
+	current, err := json.Marshal(policy)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrapf(err, "failed encoding policy: %+v", policy))
+	}
+	return admission.PatchResponseFromRaw(original, current)
+}
+
+func (h *dryRunHandler) warnings(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) []string {
+	nodeStates, err := h.matchingNodeStates(policy)
+	if err != nil {
+		log.Error(err, "failed checking the policy default routes against the nodes")
+	}
+	warnings := append(deprecatedFieldWarnings(policy.Spec.DesiredState), bondWarnings(policy.Spec.DesiredState)...)
+	warnings = append(warnings, dadWarnings(policy.Spec.DesiredState)...)
+	return append(warnings, defaultRouteWarnings(policy.Spec.DesiredState, nodeStates)...)
+}
+
+// matchingNodeStates returns the NodeNetworkStates of the nodes selected by
+// the policy
+func (h *dryRunHandler) matchingNodeStates(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) ([]nmstatev1alpha1.NodeNetworkState, error) {
+	nodes := corev1.NodeList{}
+	err := h.client.List(context.TODO(), &nodes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed listing nodes")
+	}
+	nodeStates := nmstatev1alpha1.NodeNetworkStateList{}
+	err = h.client.List(context.TODO(), &nodeStates)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed listing node network states")
+	}
+
+	policySelectors := selectors.NewFromPolicy(h.client, policy)
+	matchingNodes := map[string]bool{}
+	for _, node := range nodes.Items {
+		if policySelectors.MatchesNode(node) {
+			matchingNodes[node.Name] = true
+		}
+	}
+	matchingNodeStates := []nmstatev1alpha1.NodeNetworkState{}
+	for _, nodeState := range nodeStates.Items {
+		if matchingNodes[nodeState.Name] {
+			matchingNodeStates = append(matchingNodeStates, nodeState)
+		}
+	}
+	return matchingNodeStates, nil
+}
+
+func (h *dryRunHandler) dryRunSummary(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) (string, error) {
+	nodes := corev1.NodeList{}
+	err := h.client.List(context.TODO(), &nodes)
+	if err != nil {
+		return "", errors.Wrap(err, "failed listing nodes")
+	}
+
+	policySelectors := selectors.NewFromPolicy(h.client, policy)
+	matchingNodes := 0
+	for _, node := range nodes.Items {
+		if policySelectors.MatchesNode(node) {
+			matchingNodes++
+		}
+	}
+
+	validation := "desired state is valid"
+	if err := validateDesiredState(policy.Spec.DesiredState); err != nil {
+		validation = fmt.Sprintf("desired state is invalid: %v", err)
+	}
+	return fmt.Sprintf("dry-run: policy would affect %d/%d nodes, %s", matchingNodes, len(nodes.Items), validation), nil
+}
+
+func (h *dryRunHandler) dryRunSummaryWithWarnings(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, warnings string) (string, error) {
+	summary, err := h.dryRunSummary(policy)
+	if err != nil {
+		return "", err
+	}
+	if warnings != "" {
+		summary = fmt.Sprintf("%s, warnings: %s", summary, warnings)
+	}
+	return summary, nil
+}
+
+// validateDesiredState checks the structure of the desired state, the
+// nmstate schema is only validated at the nodes.
+func validateDesiredState(desiredState nmstatev1alpha1.State) error {
+	state := map[string]interface{}{}
+	err := yaml.Unmarshal(desiredState.Raw, &state)
+	if err != nil {
+		return errors.Wrap(err, "failed parsing desired state")
+	}
+
+	interfaces, found := state["interfaces"]
+	if !found {
+		return nil
+	}
+	interfaceList, isList := interfaces.([]interface{})
+	if !isList {
+		return fmt.Errorf("interfaces is not a list")
+	}
+	for i, iface := range interfaceList {
+		ifaceAttributes, isMap := iface.(map[string]interface{})
+		if !isMap {
+			return fmt.Errorf("interface %d is not a map", i)
+		}
+		if name, _ := ifaceAttributes["name"].(string); name == "" {
+			return fmt.Errorf("interface %d has no name", i)
+		}
+	}
+	return nil
+}
+
+func dryRunHook() *webhook.Admission {
+	return &webhook.Admission{
+		Handler: &dryRunHandler{},
+	}
+}
+
+func dryRunSummaryAnnotationHook() *webhook.Admission {
+	return &webhook.Admission{
+		Handler: &dryRunSummaryAnnotationHandler{},
+	}
+}
@@ -0,0 +1,163 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP dry-run Admission Webhook", func() {
+	var (
+		handler *dryRunHandler
+		policy  nmstatev1alpha1.NodeNetworkConfigurationPolicy
+	)
+
+	node := func(name string, labels map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	callDryRun := func(dryRun bool) webhook.AdmissionResponse {
+		request := requestForPolicy(policy)
+		request.DryRun = &dryRun
+		response := handler.Handle(context.TODO(), request)
+		ExpectWithOffset(1, response.Allowed).To(BeTrue())
+		ExpectWithOffset(1, response.Patches).To(BeEmpty())
+		return response
+	}
+
+	BeforeEach(func() {
+		scheme.Scheme.AddKnownTypes(nmstatev1alpha1.SchemeGroupVersion,
+			&nmstatev1alpha1.NodeNetworkState{},
+			&nmstatev1alpha1.NodeNetworkStateList{},
+		)
+		handler = &dryRunHandler{}
+		handler.InjectClient(fake.NewFakeClientWithScheme(scheme.Scheme,
+			&nmstatev1alpha1.NodeNetworkState{
+				ObjectMeta: metav1.ObjectMeta{Name: "node01"},
+				Status: nmstatev1alpha1.NodeNetworkStateStatus{
+					RunningRoutes: []nmstatev1alpha1.RunningRoute{
+						{Destination: "0.0.0.0/0", NextHopInterface: "eth0", Metric: 100, Protocol: "dhcp"},
+					},
+				},
+			},
+			&nmstatev1alpha1.NodeNetworkState{
+				ObjectMeta: metav1.ObjectMeta{Name: "node03"},
+				Status: nmstatev1alpha1.NodeNetworkStateStatus{
+					RunningRoutes: []nmstatev1alpha1.RunningRoute{
+						{Destination: "0.0.0.0/0", NextHopInterface: "eth0", Metric: 10, Protocol: "dhcp"},
+					},
+				},
+			},
+			node("node01", map[string]string{"node-role.kubernetes.io/worker": ""}),
+			node("node02", map[string]string{"node-role.kubernetes.io/worker": "", "topology.kubernetes.io/zone": "us-east-1a"}),
+			node("node03", map[string]string{"node-role.kubernetes.io/master": ""}),
+		))
+		policy = nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy1"},
+			Spec: nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+				NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""},
+				DesiredState: nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+`),
+			},
+		}
+	})
+
+	It("should not summarize requests that are not dry-run", func() {
+		response := callDryRun(false)
+		Expect(response.AuditAnnotations).To(BeEmpty())
+	})
+
+	It("should report warnings for all the requests", func() {
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState(`interfaces:
+- name: bond0
+  type: bond
+  state: up
+  link-aggregation:
+    mode: balance-rr
+    options:
+      lacp_rate: fast
+`)
+		response := callDryRun(false)
+		Expect(response.AuditAnnotations).To(HaveKeyWithValue(warningsAuditAnnotation, "bond bond0 lacp_rate is ignored with mode balance-rr"))
+		response = callDryRun(true)
+		Expect(response.Result.Message).To(Equal("dry-run: policy would affect 2/3 nodes, desired state is valid, warnings: bond bond0 lacp_rate is ignored with mode balance-rr"))
+	})
+
+	It("should warn about default routes conflicting with the selected nodes", func() {
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState(`routes:
+  config:
+  - destination: 0.0.0.0/0
+    next-hop-address: 192.168.67.1
+    next-hop-interface: eth1
+    metric: 100
+`)
+		response := callDryRun(false)
+		Expect(response.AuditAnnotations).To(HaveKeyWithValue(warningsAuditAnnotation, "node node01 dhcp default route via eth0 has the same metric 100 as the default route 0.0.0.0/0 via eth1"))
+	})
+
+	It("should summarize the affected nodes and validation", func() {
+		response := callDryRun(true)
+		summary := "dry-run: policy would affect 2/3 nodes, desired state is valid"
+		Expect(response.Result.Message).To(Equal(summary))
+		Expect(response.AuditAnnotations).To(HaveKeyWithValue(dryRunSummaryAuditAnnotation, summary))
+	})
+
+	It("should count only the nodes matching the cloud selector", func() {
+		policy.Spec.NodeSelector = nil
+		policy.Spec.CloudSelector = &nmstatev1alpha1.CloudSelector{Zones: []string{"us-east-1a"}}
+		response := callDryRun(true)
+		Expect(response.Result.Message).To(Equal("dry-run: policy would affect 1/3 nodes, desired state is valid"))
+	})
+
+	It("should report invalid desired states", func() {
+		policy.Spec.NodeSelector = nil
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState(`interfaces:
+- type: ethernet
+  state: up
+`)
+		response := callDryRun(true)
+		Expect(response.Result.Message).To(Equal("dry-run: policy would affect 3/3 nodes, desired state is invalid: interface 0 has no name"))
+	})
+
+	Context("when setting the summary at the policy", func() {
+		var annotationHandler *dryRunSummaryAnnotationHandler
+
+		callDryRunAnnotation := func(dryRun bool) webhook.AdmissionResponse {
+			request := requestForPolicy(policy)
+			request.DryRun = &dryRun
+			response := annotationHandler.Handle(context.TODO(), request)
+			ExpectWithOffset(1, response.Allowed).To(BeTrue())
+			return response
+		}
+
+		BeforeEach(func() {
+			annotationHandler = &dryRunSummaryAnnotationHandler{}
+			annotationHandler.InjectClient(handler.client)
+		})
+
+		It("should not mutate requests that are not dry-run", func() {
+			response := callDryRunAnnotation(false)
+			Expect(response.Patches).To(BeEmpty())
+		})
+
+		It("should return the summary as the policy annotation", func() {
+			response := callDryRunAnnotation(true)
+			Expect(response.Patches).To(HaveLen(1))
+			Expect(response.Patches[0].Operation).To(Equal("add"))
+			Expect(response.Patches[0].Path).To(Equal("/metadata/annotations"))
+			Expect(response.Patches[0].Value).To(HaveKeyWithValue(DryRunSummaryAnnotation, "dry-run: policy would affect 2/3 nodes, desired state is valid"))
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// validateForwarding denies desired states disabling the node IPv4 global
+// forwarding, the pod network and the services need it at every node so
+// disabling it breaks the cluster
+func validateForwarding(desiredState nmstatev1alpha1.State) error {
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return fmt.Errorf("failed converting desired state to JSON: %v", err)
+	}
+
+	ipv4Forwarding := gjson.GetBytes(desiredStateJSON, "forwarding.ipv4")
+	if ipv4Forwarding.Type == gjson.False {
+		return fmt.Errorf("forwarding ipv4 cannot be disabled, the pod network needs it at every node")
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP forwarding validation", func() {
+	DescribeTable("desired state global forwarding",
+		func(desiredState string, expectedError string) {
+			err := validateForwarding(nmstatev1alpha1.NewState(desiredState))
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+		Entry("without forwarding", "interfaces: []\n", ""),
+		Entry("enabling it", "forwarding:\n  ipv4: true\n  ipv6: true\n", ""),
+		Entry("disabling IPv6 only", "forwarding:\n  ipv6: false\n", ""),
+		Entry("disabling IPv4", "forwarding:\n  ipv4: false\n", "forwarding ipv4 cannot be disabled, the pod network needs it at every node"),
+	)
+
+	It("should deny policies disabling IPv4 forwarding", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState("forwarding:\n  ipv4: false\n")
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("forwarding ipv4 cannot be disabled"))
+	})
+})
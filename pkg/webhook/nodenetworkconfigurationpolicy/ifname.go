@@ -0,0 +1,78 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	"github.com/nmstate/kubernetes-nmstate/pkg/helper/render"
+)
+
+// Kernel interface names are limited to IFNAMSIZ bytes including the
+// terminating NUL
+const maxInterfaceNameLength = 15
+
+// checkInterfaceName checks the name like the kernel does, it has to be
+// short enough and without slashes, colons nor whitespace
+func checkInterfaceName(name string) error {
+	if name == "." || name == ".." {
+		return fmt.Errorf("%q is a reserved name", name)
+	}
+	if len(name) > maxInterfaceNameLength {
+		return fmt.Errorf("%q is %d characters long, the kernel allows up to %d", name, len(name), maxInterfaceNameLength)
+	}
+	for _, character := range name {
+		if character == '/' || character == ':' || unicode.IsSpace(character) || character > unicode.MaxASCII || !unicode.IsPrint(character) {
+			return fmt.Errorf("%q has the invalid character %q", name, character)
+		}
+	}
+	return nil
+}
+
+// validateInterfaceNames checks that the desired state interfaces, and the
+// ports, slaves and base interfaces they refer to, have names the kernel
+// accepts, instead of failing at nmstate at every node
+func validateInterfaceNames(desiredState nmstatev1alpha1.State) error {
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return fmt.Errorf("failed converting desired state to JSON: %v", err)
+	}
+
+	for i, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		name := iface.Get("name").String()
+		if name == "" {
+			return fmt.Errorf("interface %d has no name", i)
+		}
+		err := checkInterfaceName(name)
+		if err != nil {
+			if iface.Get("type").String() == "vlan" && len(name) > maxInterfaceNameLength {
+				return fmt.Errorf("invalid vlan interface name %v, use a name shorter than <base-iface>.<id>, like vlan%d", err, iface.Get("vlan.id").Int())
+			}
+			return fmt.Errorf("invalid interface name %v", err)
+		}
+
+		references := []struct {
+			kind   string
+			values []gjson.Result
+		}{
+			{"port", iface.Get("bridge.port.#.name").Array()},
+			{"bond port", render.BondPorts(iface)},
+			{"base-iface", []gjson.Result{iface.Get("vlan.base-iface"), iface.Get("vxlan.base-iface")}},
+		}
+		for _, reference := range references {
+			for _, value := range reference.values {
+				if !value.Exists() {
+					continue
+				}
+				err := checkInterfaceName(value.String())
+				if err != nil {
+					return fmt.Errorf("invalid interface %s %s name %v", name, reference.kind, err)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP interface name validation", func() {
+	DescribeTable("desired state interface names",
+		func(interfaces string, expectedError string) {
+			err := validateInterfaceNames(nmstatev1alpha1.NewState("interfaces:\n" + interfaces))
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+		Entry("with valid names", `- name: eth1
+  type: ethernet
+- name: br1.100
+  type: linux-bridge
+  bridge:
+    port:
+    - name: eth2
+`, ""),
+		Entry("with the longest name", "- name: abcdefghijklmno\n", ""),
+		Entry("without name", "- type: ethernet\n", "interface 0 has no name"),
+		Entry("with too long name", "- name: abcdefghijklmnop\n", `invalid interface name "abcdefghijklmnop" is 16 characters long, the kernel allows up to 15`),
+		Entry("with slash", "- name: eth/1\n", `invalid interface name "eth/1" has the invalid character '/'`),
+		Entry("with colon", "- name: 'eth1:0'\n", `invalid interface name "eth1:0" has the invalid character ':'`),
+		Entry("with space", "- name: eth 1\n", `invalid interface name "eth 1" has the invalid character ' '`),
+		Entry("with reserved name", "- name: ..\n", `invalid interface name ".." is a reserved name`),
+		Entry("with too long vlan name", `- name: enp0s20f0u1.1000
+  type: vlan
+  vlan:
+    base-iface: enp0s20f0u1
+    id: 1000
+`, `invalid vlan interface name "enp0s20f0u1.1000" is 16 characters long, the kernel allows up to 15, use a name shorter than <base-iface>.<id>, like vlan1000`),
+		Entry("with invalid bridge port", `- name: br1
+  type: linux-bridge
+  bridge:
+    port:
+    - name: eth/2
+`, `invalid interface br1 port name "eth/2" has the invalid character '/'`),
+		Entry("with invalid bond slave", `- name: bond0
+  type: bond
+  link-aggregation:
+    slaves:
+    - abcdefghijklmnop
+`, `invalid interface bond0 bond port name "abcdefghijklmnop" is 16 characters long, the kernel allows up to 15`),
+		Entry("with invalid bond port", `- name: bond0
+  type: bond
+  link-aggregation:
+    port:
+    - eth 2
+`, `invalid interface bond0 bond port name "eth 2" has the invalid character ' '`),
+		Entry("with invalid vxlan base interface", `- name: vxlan10
+  type: vxlan
+  vxlan:
+    base-iface: eth 1
+    id: 10
+`, `invalid interface vxlan10 base-iface name "eth 1" has the invalid character ' '`),
+	)
+
+	It("should deny policies with invalid interface names", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState("interfaces:\n- name: abcdefghijklmnop\n  type: ethernet\n")
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring(`invalid interface name "abcdefghijklmnop"`))
+	})
+})
@@ -0,0 +1,46 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// PCI addresses as domain:bus:slot.function, like 0000:03:00.0
+var pciAddressRegexp = regexp.MustCompile(`^[0-9a-fA-F]{4}:[0-9a-fA-F]{2}:[0-9a-fA-F]{2}\.[0-7]$`)
+
+// validateInterfaceMatches checks that the interfaces identified by address
+// have either a MAC or a PCI address, the handler fails at every node if
+// they cannot identify any interface
+func validateInterfaceMatches(desiredState nmstatev1alpha1.State) error {
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return fmt.Errorf("failed converting desired state to JSON: %v", err)
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		match := iface.Get("match")
+		if !match.Exists() {
+			continue
+		}
+		name := iface.Get("name").String()
+		macAddress, pciAddress := match.Get("mac-address"), match.Get("pci-address")
+		if !match.IsObject() || len(match.Map()) != 1 || macAddress.Exists() == pciAddress.Exists() {
+			return fmt.Errorf("invalid interface %s match %s, it needs either mac-address or pci-address", name, match.Raw)
+		}
+		if macAddress.Exists() {
+			if _, err := net.ParseMAC(macAddress.String()); err != nil {
+				return fmt.Errorf("invalid interface %s match mac-address %s", name, macAddress.Raw)
+			}
+		}
+		if pciAddress.Exists() && !pciAddressRegexp.MatchString(pciAddress.String()) {
+			return fmt.Errorf("invalid interface %s match pci-address %s, it has to be like 0000:03:00.0", name, pciAddress.Raw)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP interface match validation", func() {
+	DescribeTable("desired state interfaces match",
+		func(interfaces string, expectedError string) {
+			err := validateInterfaceMatches(nmstatev1alpha1.NewState("interfaces:\n" + interfaces))
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+		Entry("with MAC and PCI addresses, should allow", `- name: uplink1
+  match:
+    mac-address: 52:55:00:D1:56:01
+- name: uplink2
+  match:
+    pci-address: 0000:03:00.1
+`, ""),
+		Entry("with both addresses, should deny", `- name: uplink1
+  match:
+    mac-address: 52:55:00:d1:56:01
+    pci-address: 0000:03:00.1
+`, `invalid interface uplink1 match {"mac-address":"52:55:00:d1:56:01","pci-address":"0000:03:00.1"}, it needs either mac-address or pci-address`),
+		Entry("without address, should deny", `- name: uplink1
+  match: {}
+`, "invalid interface uplink1 match {}, it needs either mac-address or pci-address"),
+		Entry("with invalid MAC address, should deny", `- name: uplink1
+  match:
+    mac-address: 52:55:00:d1:56
+`, `invalid interface uplink1 match mac-address "52:55:00:d1:56"`),
+		Entry("with invalid PCI address, should deny", `- name: uplink1
+  match:
+    pci-address: 03:00.1
+`, `invalid interface uplink1 match pci-address "03:00.1", it has to be like 0000:03:00.0`),
+	)
+})
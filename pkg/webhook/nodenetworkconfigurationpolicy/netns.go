@@ -0,0 +1,35 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Interface attributes targeting a network namespace, statically or by a pod
+// resolved at apply time, NetworkManager only manages the host network
+// namespace so the handler cannot apply them
+var netnsAttributes = []string{"netns", "net-namespace", "network-namespace", "netns-selector"}
+
+// validateNetworkNamespaces denies the desired state interfaces targeting a
+// network namespace other than the host one, nmstate would otherwise fail
+// at the node with an unknown attribute or configure the host interface.
+func validateNetworkNamespaces(desiredState nmstatev1alpha1.State) error {
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return errors.Wrap(err, "failed parsing desired state")
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		for _, attribute := range netnsAttributes {
+			if iface.Get(attribute).Exists() {
+				return fmt.Errorf("interface %s %s is not supported, policies only configure the node host network namespace", iface.Get("name").String(), attribute)
+			}
+		}
+	}
+	return nil
+}
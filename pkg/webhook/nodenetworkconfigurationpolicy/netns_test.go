@@ -0,0 +1,47 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP network namespace validation", func() {
+	withAttribute := func(attribute string) nmstatev1alpha1.State {
+		return nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+` + attribute)
+	}
+	DescribeTable("desired state network namespaces",
+		func(desiredState nmstatev1alpha1.State, expectedError string) {
+			err := validateNetworkNamespaces(desiredState)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+		Entry("without network namespace", withAttribute(""), ""),
+		Entry("with static netns", withAttribute(`  netns: dpdk0
+`), "interface eth1 netns is not supported, policies only configure the node host network namespace"),
+		Entry("with netns selector", withAttribute(`  netns-selector:
+    pod-labels:
+      app: vpp
+`), "interface eth1 netns-selector is not supported, policies only configure the node host network namespace"),
+	)
+
+	It("should deny policies targeting a network namespace", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.DesiredState = withAttribute(`  netns: dpdk0
+`)
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("interface eth1 netns is not supported"))
+	})
+})
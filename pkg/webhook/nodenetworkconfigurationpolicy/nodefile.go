@@ -0,0 +1,23 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// validateRequireNodeFile checks that the required node file is a plain
+// path under the node files directory, the only one mounted at the handler
+func validateRequireNodeFile(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec) error {
+	path := policySpec.RequireNodeFile
+	if path == "" {
+		return nil
+	}
+	dir := nmstatev1alpha1.NodeNetworkConfigurationPolicyNodeFilesDir
+	if filepath.Clean(path) != path || !strings.HasPrefix(path, dir+"/") {
+		return fmt.Errorf("required node file %s has to be a file under %s", path, dir)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP required node file validation", func() {
+	It("should allow files under the node files directory", func() {
+		for _, path := range []string{"", "/var/lib/nmstate/node-files/provisioned", "/var/lib/nmstate/node-files/sriov/ready"} {
+			Expect(validateRequireNodeFile(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{RequireNodeFile: path})).To(Succeed(), path)
+		}
+	})
+
+	It("should deny files outside of the node files directory", func() {
+		for _, path := range []string{"/etc/hostname", "provisioned", "/var/lib/nmstate/node-files", "/var/lib/nmstate/node-files/", "/var/lib/nmstate/node-files/../secret"} {
+			Expect(validateRequireNodeFile(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{RequireNodeFile: path})).ToNot(Succeed(), path)
+		}
+	})
+
+	It("should deny policies requiring files outside of the node files directory", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.RequireNodeFile = "/etc/shadow"
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("required node file /etc/shadow has to be a file under /var/lib/nmstate/node-files"))
+	})
+})
@@ -0,0 +1,41 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+// setNodeInvariantAnnotation renders the policies whose desired state is the
+// same at every node once for all the handlers, annotating them with the
+// desired state fingerprint. The annotation is removed from the rest of
+// policies, so it's never stale nor set by hand.
+func setNodeInvariantAnnotation(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+	annotation := nmstatev1alpha1.NodeNetworkConfigurationPolicyNodeInvariantAnnotation
+	nodeInvariant, err := nmstate.NodeInvariant(policy.Spec)
+	if err != nil {
+		logf.Log.WithName("webhook/nodenetworkconfigurationpolicy/nodeinvariant").Error(err, "failed checking if the policy is node invariant, the handlers will render it", "policy", policy.Name)
+	}
+	if !nodeInvariant {
+		delete(policy.ObjectMeta.Annotations, annotation)
+		return policy
+	}
+	if policy.ObjectMeta.Annotations == nil {
+		policy.ObjectMeta.Annotations = map[string]string{}
+	}
+	policy.ObjectMeta.Annotations[annotation] = nmstate.DesiredStateFingerprint(policy.Spec.DesiredState)
+	return policy
+}
+
+func setNodeInvariantAnnotationHook() *webhook.Admission {
+	return &webhook.Admission{
+		Handler: admission.HandlerFunc(
+			mutatePolicyHandler(
+				always,
+				setNodeInvariantAnnotation,
+			)),
+	}
+}
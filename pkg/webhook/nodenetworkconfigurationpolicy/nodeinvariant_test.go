@@ -0,0 +1,35 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+	nmstate "github.com/nmstate/kubernetes-nmstate/pkg/helper"
+)
+
+var _ = Describe("NNCP node invariant Mutating Admission Webhook", func() {
+	annotation := nmstatev1alpha1.NodeNetworkConfigurationPolicyNodeInvariantAnnotation
+
+	mutate := func(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		response := setNodeInvariantAnnotationHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeTrue())
+		return patchPolicy(policy, response)
+	}
+
+	It("should annotate the node invariant policies with their desired state fingerprint", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState("interfaces:\n- name: eth1\n  type: ethernet\n  state: up\n")
+		mutated := mutate(policy)
+		Expect(mutated.Annotations).To(HaveKeyWithValue(annotation, nmstate.DesiredStateFingerprint(mutated.Spec.DesiredState)))
+	})
+
+	It("should remove the annotation from the policies rendered per node", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Annotations = map[string]string{annotation: "forged"}
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState("interfaces:\n- name: uplink\n  type: ethernet\n  state: up\n  match:\n    pci-address: 0000:3b:00.0\n")
+		Expect(mutate(policy).Annotations).ToNot(HaveKey(annotation))
+	})
+})
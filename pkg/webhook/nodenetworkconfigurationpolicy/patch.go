@@ -0,0 +1,33 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// validateDesiredStatePatch checks that the policy desired state patch is
+// a JSON patch and that it's not set together with the desired state
+func validateDesiredStatePatch(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec) error {
+	if policySpec.DesiredStatePatch == nil {
+		return nil
+	}
+	if desiredState := strings.TrimSpace(policySpec.DesiredState.String()); desiredState != "" && desiredState != "null" {
+		return fmt.Errorf("desiredState and desiredStatePatch cannot be set together")
+	}
+
+	patchJSON, err := yaml.YAMLToJSON(policySpec.DesiredStatePatch.Raw)
+	if err != nil {
+		return errors.Wrap(err, "failed parsing desiredStatePatch")
+	}
+	_, err = jsonpatch.DecodePatch(patchJSON)
+	if err != nil {
+		return errors.Wrap(err, "desiredStatePatch is not a JSON patch")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP desired state patch validation", func() {
+	patch := nmstatev1alpha1.NewStatePatch(`- op: replace
+  path: /interfaces/1/state
+  value: up
+`)
+	DescribeTable("policy spec",
+		func(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec, expectedError string) {
+			err := validateDesiredStatePatch(policySpec)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			}
+		},
+		Entry("with desired state only", nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+			DesiredState: nmstatev1alpha1.NewState("interfaces: []"),
+		}, ""),
+		Entry("with desired state patch only", nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+			DesiredStatePatch: &patch,
+		}, ""),
+		Entry("with both", nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+			DesiredState:      nmstatev1alpha1.NewState("interfaces: []"),
+			DesiredStatePatch: &patch,
+		}, "desiredState and desiredStatePatch cannot be set together"),
+		Entry("with a patch that is not a JSON patch", nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+			DesiredStatePatch: &nmstatev1alpha1.StatePatch{Raw: []byte("interfaces: []")},
+		}, "desiredStatePatch is not a JSON patch"),
+	)
+
+	It("should deny policies setting both", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState("interfaces: []")
+		policy.Spec.DesiredStatePatch = &patch
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+	})
+
+	It("should decode policies without desired state", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.DesiredStatePatch = &patch
+		data, err := json.Marshal(policy)
+		Expect(err).ToNot(HaveOccurred())
+		decoded := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+		Expect(validateDesiredStatePatch(decoded.Spec)).To(Succeed())
+		Expect(decoded.Spec.DesiredStatePatch.String()).To(MatchYAML(patch.String()))
+	})
+})
@@ -0,0 +1,63 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Attributes of every qdisc kind the handler knows how to apply
+var qdiscAttributes = map[string][]string{
+	"tbf":  []string{"kind", "rate", "burst", "latency"},
+	"none": []string{"kind"},
+}
+
+// validateQdiscs checks that the desired state interfaces qdiscs have a
+// supported kind and all of its attributes and only them.
+func validateQdiscs(desiredState nmstatev1alpha1.State) error {
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return errors.Wrap(err, "failed parsing desired state")
+	}
+
+	for _, iface := range gjson.ParseBytes(desiredStateJSON).Get("interfaces").Array() {
+		name := iface.Get("name").String()
+		qdisc := iface.Get("qdisc")
+		if !qdisc.Exists() {
+			continue
+		}
+		if !qdisc.IsObject() {
+			return fmt.Errorf("interface %s qdisc is not a map", name)
+		}
+
+		kind := qdisc.Get("kind").String()
+		attributes, supported := qdiscAttributes[kind]
+		if !supported {
+			kinds := []string{}
+			for kind := range qdiscAttributes {
+				kinds = append(kinds, kind)
+			}
+			sort.Strings(kinds)
+			return fmt.Errorf("interface %s qdisc kind %q is not supported, supported kinds are %v", name, kind, kinds)
+		}
+
+		known := map[string]bool{}
+		for _, attribute := range attributes {
+			known[attribute] = true
+			if !qdisc.Get(attribute).Exists() {
+				return fmt.Errorf("interface %s qdisc %s is missing %s", name, kind, attribute)
+			}
+		}
+		for attribute := range qdisc.Map() {
+			if !known[attribute] {
+				return fmt.Errorf("interface %s qdisc %s does not support %s", name, kind, attribute)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP qdisc validation", func() {
+	withQdisc := func(qdisc string) nmstatev1alpha1.State {
+		return nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+  qdisc:
+` + qdisc)
+	}
+	DescribeTable("desired state qdiscs",
+		func(desiredState nmstatev1alpha1.State, expectedError string) {
+			err := validateQdiscs(desiredState)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+		Entry("without qdisc", nmstatev1alpha1.NewState(`interfaces:
+- name: eth1
+  type: ethernet
+  state: up
+`), ""),
+		Entry("with tbf", withQdisc(`    kind: tbf
+    rate: 100mbit
+    burst: 32kbit
+    latency: 400ms
+`), ""),
+		Entry("with none", withQdisc(`    kind: none
+`), ""),
+		Entry("with unsupported kind", withQdisc(`    kind: htb
+`), `interface eth1 qdisc kind "htb" is not supported, supported kinds are [none tbf]`),
+		Entry("with tbf missing attributes", withQdisc(`    kind: tbf
+    rate: 100mbit
+`), "interface eth1 qdisc tbf is missing burst"),
+		Entry("with unsupported attributes", withQdisc(`    kind: none
+    rate: 100mbit
+`), "interface eth1 qdisc none does not support rate"),
+	)
+
+	It("should deny policies with unsupported qdiscs", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.DesiredState = withQdisc(`    kind: htb
+`)
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring(`qdisc kind "htb" is not supported`))
+	})
+})
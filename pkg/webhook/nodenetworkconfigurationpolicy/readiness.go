@@ -0,0 +1,42 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// validateReadinessChecks checks that every readiness check lists the
+// interfaces it waits for or has a valid TCP or HTTP target, only one of
+// them
+func validateReadinessChecks(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec) error {
+	for i, check := range policySpec.ReadinessChecks {
+		types := 0
+		if len(check.AnyInterfaceUp) > 0 {
+			types++
+		}
+		if check.TCPConnect != "" {
+			types++
+			_, port, err := net.SplitHostPort(check.TCPConnect)
+			if err != nil || port == "" {
+				return fmt.Errorf("readiness check %d tcpConnect %s has to be a host:port target", i, check.TCPConnect)
+			}
+		}
+		if check.HTTPGet != "" {
+			types++
+			target, err := url.Parse(check.HTTPGet)
+			if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+				return fmt.Errorf("readiness check %d httpGet %s has to be an http or https URL", i, check.HTTPGet)
+			}
+		}
+		if types == 0 {
+			return fmt.Errorf("readiness check %d has no interfaces at anyInterfaceUp nor tcpConnect or httpGet target", i)
+		}
+		if types > 1 {
+			return fmt.Errorf("readiness check %d has more than one of anyInterfaceUp, tcpConnect and httpGet", i)
+		}
+	}
+	return nil
+}
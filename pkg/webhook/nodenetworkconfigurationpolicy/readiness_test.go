@@ -0,0 +1,45 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP readiness checks validation", func() {
+	It("should allow checks with interfaces", func() {
+		Expect(validateReadinessChecks(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+			ReadinessChecks: []nmstatev1alpha1.ReadinessCheck{{AnyInterfaceUp: []string{"eth1", "eth2"}}},
+		})).To(Succeed())
+	})
+
+	It("should deny policies with checks without interfaces", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.ReadinessChecks = []nmstatev1alpha1.ReadinessCheck{{AnyInterfaceUp: []string{"eth1"}}, {}}
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("readiness check 1 has no interfaces at anyInterfaceUp"))
+	})
+
+	It("should allow checks with TCP and HTTP targets", func() {
+		Expect(validateReadinessChecks(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+			ReadinessChecks: []nmstatev1alpha1.ReadinessCheck{{TCPConnect: "172.30.0.10:443"}, {HTTPGet: "https://example.com/healthz"}},
+		})).To(Succeed())
+	})
+
+	It("should deny checks with invalid targets", func() {
+		for _, check := range []nmstatev1alpha1.ReadinessCheck{
+			{TCPConnect: "172.30.0.10"},
+			{HTTPGet: "ftp://example.com/"},
+			{HTTPGet: "/healthz"},
+			{AnyInterfaceUp: []string{"eth1"}, TCPConnect: "172.30.0.10:443"},
+		} {
+			Expect(validateReadinessChecks(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+				ReadinessChecks: []nmstatev1alpha1.ReadinessCheck{check},
+			})).ToNot(Succeed(), "%+v", check)
+		}
+	})
+})
@@ -0,0 +1,24 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"time"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// Each reconcile reads the node current state with nmstate, shorter
+// intervals would keep every handler busy with it
+const minReconcileInterval = 1 * time.Minute
+
+// validateReconcileInterval checks that the policy is not reconciled more
+// often than the minimum interval
+func validateReconcileInterval(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec) error {
+	if policySpec.ReconcileInterval == nil {
+		return nil
+	}
+	if interval := policySpec.ReconcileInterval.Duration; interval < minReconcileInterval {
+		return fmt.Errorf("reconcile interval %s is shorter than the minimum %s", interval, minReconcileInterval)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP reconcile interval validation", func() {
+	It("should allow intervals from the minimum on", func() {
+		Expect(validateReconcileInterval(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{})).To(Succeed())
+		for _, interval := range []time.Duration{time.Minute, time.Hour} {
+			Expect(validateReconcileInterval(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{ReconcileInterval: &metav1.Duration{Duration: interval}})).To(Succeed(), interval.String())
+		}
+	})
+
+	It("should deny policies with intervals shorter than the minimum", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.ReconcileInterval = &metav1.Duration{Duration: 10 * time.Second}
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("reconcile interval 10s is shorter than the minimum 1m0s"))
+	})
+})
@@ -0,0 +1,17 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// validateRolloutPriority checks that the rollout priority is only set for
+// policies rolled out by zone, it orders them within each zone
+func validateRolloutPriority(policySpec nmstatev1alpha1.NodeNetworkConfigurationPolicySpec) error {
+	rollout := policySpec.Rollout
+	if rollout == nil || rollout.Priority == 0 || rollout.Topology == nmstatev1alpha1.RolloutTopologyZone {
+		return nil
+	}
+	return fmt.Errorf("rollout priority %d only orders the policies rolled out by zone, set the rollout topology to %s", rollout.Priority, nmstatev1alpha1.RolloutTopologyZone)
+}
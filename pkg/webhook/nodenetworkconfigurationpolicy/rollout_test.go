@@ -0,0 +1,27 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP rollout priority validation", func() {
+	It("should allow priorities of policies rolled out by zone", func() {
+		Expect(validateRolloutPriority(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{})).To(Succeed())
+		Expect(validateRolloutPriority(nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{
+			Rollout: &nmstatev1alpha1.PolicyRollout{Topology: nmstatev1alpha1.RolloutTopologyZone, Priority: 10},
+		})).To(Succeed())
+	})
+
+	It("should deny policies with priority not rolled out by zone", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.Rollout = &nmstatev1alpha1.PolicyRollout{Priority: 10}
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("rollout priority 10 only orders the policies rolled out by zone, set the rollout topology to Zone"))
+	})
+})
@@ -8,7 +8,8 @@ import (
 )
 
 const (
-	webhookName = "nmstate"
+	webhookName           = "nmstate"
+	validatingWebhookName = "nmstate-validating"
 )
 
 func Add(mgr manager.Manager) error {
@@ -24,8 +25,24 @@ func Add(mgr manager.Manager) error {
 		webhookserver.WithHook("/nodenetworkconfigurationpolicies-mutate", deleteConditionsHook()),
 		webhookserver.WithHook("/nodenetworkconfigurationpolicies-status-mutate", setConditionsUnknownHook()),
 		webhookserver.WithHook("/nodenetworkconfigurationpolicies-timestamp-mutate", setTimestampAnnotationHook()),
+		webhookserver.WithHook("/nodenetworkconfigurationpolicies-node-invariant-mutate", setNodeInvariantAnnotationHook()),
+		webhookserver.WithHook("/nodenetworkconfigurationpolicies-dryrun-mutate", dryRunSummaryAnnotationHook()),
 	)
-	return add(mgr, server)
+	err := add(mgr, server)
+	if err != nil {
+		return err
+	}
+
+	// The dry-run and validation hooks do not modify the policy, they run as
+	// validating webhooks so the API server calls them after every mutation,
+	// they have their own server since a server updates one webhook config.
+	validatingServer := webhookserver.New(mgr, validatingWebhookName, certificate.ValidatingWebhook,
+		webhookserver.WithPort(8444),
+		webhookserver.WithCertDir("/etc/webhook/validating-certs/"),
+		webhookserver.WithHook("/nodenetworkconfigurationpolicies-dryrun", dryRunHook()),
+		webhookserver.WithHook("/nodenetworkconfigurationpolicies-validate", validatePolicyHook()),
+	)
+	return add(mgr, validatingServer)
 }
 
 // add adds a new Webhook to mgr with r as the webhook.Server
@@ -0,0 +1,29 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+	"strconv"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// validateActivateAnnotation checks that the activate annotation is a
+// generation the policy already had, a malformed or future one would never
+// match the policy generation and the staged desired state would be held
+// without telling why. Staged policies of a bundle hold the whole bundle,
+// so the annotation is validated the same for them.
+func validateActivateAnnotation(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy) error {
+	annotation := nmstatev1alpha1.NodeNetworkConfigurationPolicyActivateAnnotation
+	activate, ok := policy.Annotations[annotation]
+	if !ok {
+		return nil
+	}
+	generation, err := strconv.ParseInt(activate, 10, 64)
+	if err != nil || generation < 1 {
+		return fmt.Errorf("%s annotation %q has to be a policy generation", annotation, activate)
+	}
+	if generation > policy.Generation {
+		return fmt.Errorf("%s annotation %d is newer than the policy generation %d", annotation, generation, policy.Generation)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP activate annotation validation", func() {
+	policy := func(activate string) nmstatev1alpha1.NodeNetworkConfigurationPolicy {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       nmstatev1alpha1.NodeNetworkConfigurationPolicySpec{Stage: true},
+		}
+		if activate != "" {
+			policy.Annotations = map[string]string{nmstatev1alpha1.NodeNetworkConfigurationPolicyActivateAnnotation: activate}
+		}
+		return policy
+	}
+
+	DescribeTable("activating staged policies",
+		func(policy nmstatev1alpha1.NodeNetworkConfigurationPolicy, expectedErr string) {
+			err := validateActivateAnnotation(policy)
+			if expectedErr == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedErr))
+			}
+		},
+		Entry("should allow policies without the annotation", policy(""), ""),
+		Entry("should allow the current generation", policy("2"), ""),
+		Entry("should allow a previous generation, staging the current one", policy("1"), ""),
+		Entry("should deny a future generation", policy("3"), "nmstate.io/activate annotation 3 is newer than the policy generation 2"),
+		Entry("should deny a malformed generation", policy("latest"), `nmstate.io/activate annotation "latest" has to be a policy generation`),
+		Entry("should deny a zero generation", policy("0"), `nmstate.io/activate annotation "0" has to be a policy generation`),
+	)
+
+	It("should deny policies with a malformed activate annotation", func() {
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy("yes")))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring(`nmstate.io/activate annotation "yes" has to be a policy generation`))
+	})
+})
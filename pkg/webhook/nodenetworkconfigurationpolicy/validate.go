@@ -0,0 +1,112 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// validatePolicyHandler denies the policies with desired state options that
+// nmstate does not support and the handler does not know how to apply, it
+// does not mutate the policy.
+func validatePolicyHandler(ctx context.Context, req webhook.AdmissionRequest) webhook.AdmissionResponse {
+	policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+	err := json.Unmarshal(req.Object.Raw, &policy)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrapf(err, "failed decoding policy: %s", string(req.Object.Raw)))
+	}
+
+	err = validateDesiredStatePatch(policy.Spec)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateInterfaceNames(policy.Spec.DesiredState)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateInterfaceMatches(policy.Spec.DesiredState)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateNetworkNamespaces(policy.Spec.DesiredState)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateAllowedInterfaces(policy.Spec.DesiredState, allowedInterfaces)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateQdiscs(policy.Spec.DesiredState)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateVxlans(policy.Spec.DesiredState)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateForwarding(policy.Spec.DesiredState)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateDriftIgnore(policy.Spec)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateReadinessChecks(policy.Spec)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateRequireNodeFile(policy.Spec)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateReconcileInterval(policy.Spec)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateApplyTimeout(policy.Spec)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateRolloutPriority(policy.Spec)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateAnycastAddresses(policy.Spec)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	err = validateActivateAnnotation(policy)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("desired state is supported")
+}
+
+func validatePolicyHook() *webhook.Admission {
+	return &webhook.Admission{
+		Handler: admission.HandlerFunc(validatePolicyHandler),
+	}
+}
@@ -0,0 +1,37 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+	yaml "sigs.k8s.io/yaml"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+// validateVxlans checks that the desired state VXLAN interfaces have an id
+// and that they do not share it, conflicts with the VXLAN interfaces already
+// present at the nodes are checked by the handlers before applying
+func validateVxlans(desiredState nmstatev1alpha1.State) error {
+	desiredStateJSON, err := yaml.YAMLToJSON(desiredState.Raw)
+	if err != nil {
+		return fmt.Errorf("failed converting desired state to JSON: %v", err)
+	}
+
+	interfaceByID := map[int64]string{}
+	for _, vxlan := range gjson.ParseBytes(desiredStateJSON).Get("interfaces.#(type==vxlan)#").Array() {
+		name := vxlan.Get("name").String()
+		if vxlan.Get("state").String() == "absent" {
+			continue
+		}
+		id := vxlan.Get("vxlan.id")
+		if !id.Exists() {
+			return fmt.Errorf("vxlan interface %s has no id", name)
+		}
+		if other, found := interfaceByID[id.Int()]; found {
+			return fmt.Errorf("vxlan interfaces %s and %s have the same id %d", other, name, id.Int())
+		}
+		interfaceByID[id.Int()] = name
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package nodenetworkconfigurationpolicy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+var _ = Describe("NNCP VXLAN validation", func() {
+	vxlan := func(name string, id string) string {
+		return `- name: ` + name + `
+  type: vxlan
+  state: up
+  vxlan:
+    base-iface: eth1
+` + id
+	}
+	DescribeTable("desired state VXLAN interfaces",
+		func(interfaces string, expectedError string) {
+			err := validateVxlans(nmstatev1alpha1.NewState("interfaces:\n" + interfaces))
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+		Entry("with different ids", vxlan("vxlan10", "    id: 10\n")+vxlan("vxlan20", "    id: 20\n"), ""),
+		Entry("without id", vxlan("vxlan10", ""), "vxlan interface vxlan10 has no id"),
+		Entry("with removed interface without id", `- name: vxlan10
+  type: vxlan
+  state: absent
+`, ""),
+		Entry("with the same id", vxlan("vxlan10", "    id: 10\n")+vxlan("vxlan20", "    id: 10\n"), "vxlan interfaces vxlan10 and vxlan20 have the same id 10"),
+	)
+
+	It("should deny policies with VXLAN id conflicts", func() {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		policy.Spec.DesiredState = nmstatev1alpha1.NewState("interfaces:\n" + vxlan("vxlan10", "    id: 10\n") + vxlan("vxlan20", "    id: 10\n"))
+		response := validatePolicyHook().Handle(context.TODO(), requestForPolicy(policy))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("have the same id 10"))
+	})
+})
@@ -0,0 +1,79 @@
+package e2e
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidwall/gjson"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+func dummyWithVIPs(dummyName string) nmstatev1alpha1.State {
+	return nmstatev1alpha1.NewState(fmt.Sprintf(`interfaces:
+  - name: %s
+    type: dummy
+    state: up
+    ipv4:
+      enabled: true
+      address:
+      - ip: 192.0.2.10
+        prefix-length: 32
+      - ip: 192.0.2.11
+        prefix-length: 32
+`, dummyName))
+}
+
+func interfaceType(node string, name string) string {
+	path := fmt.Sprintf("interfaces.#(name==\"%s\").type", name)
+	return gjson.ParseBytes(currentStateJSON(node)).Get(path).String()
+}
+
+func ipv4Addresses(node string, name string) []string {
+	path := fmt.Sprintf("interfaces.#(name==\"%s\").ipv4.address.#.ip", name)
+	addresses := []string{}
+	for _, address := range gjson.ParseBytes(currentStateJSON(node)).Get(path).Array() {
+		addresses = append(addresses, address.String())
+	}
+	return addresses
+}
+
+var _ = Describe("Dummy interface", func() {
+	Context("when a dummy interface hosting service VIPs is configured", func() {
+		BeforeEach(func() {
+			updateDesiredState(dummyWithVIPs("vips0"))
+			waitForAvailableTestPolicy()
+		})
+		AfterEach(func() {
+			updateDesiredState(nmstatev1alpha1.NewState(`interfaces:
+  - name: vips0
+    type: dummy
+    state: absent
+`))
+			waitForAvailableTestPolicy()
+			resetDesiredStateForNodes()
+		})
+		It("should be reported as dummy with all its addresses", func() {
+			for _, node := range nodes {
+				interfacesNameForNodeEventually(node).Should(ContainElement("vips0"))
+				Expect(interfaceType(node, "vips0")).To(Equal("dummy"))
+				Eventually(func() []string {
+					return ipv4Addresses(node, "vips0")
+				}, ReadTimeout, ReadInterval).Should(ConsistOf("192.0.2.10", "192.0.2.11"))
+			}
+		})
+		Context("and it is dropped from the desired state", func() {
+			BeforeEach(func() {
+				updateDesiredState(nmstatev1alpha1.NewState("interfaces: []\n"))
+				waitForAvailableTestPolicy()
+			})
+			It("should be removed from the node", func() {
+				for _, node := range nodes {
+					interfacesNameForNodeEventually(node).ShouldNot(ContainElement("vips0"))
+				}
+			})
+		})
+	})
+})
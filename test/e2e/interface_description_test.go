@@ -0,0 +1,55 @@
+package e2e
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidwall/gjson"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+func ethernetNicWithDescription(nicName string, description string) nmstatev1alpha1.State {
+	return nmstatev1alpha1.NewState(fmt.Sprintf(`interfaces:
+  - name: %s
+    type: ethernet
+    state: up
+    description: %s
+`, nicName, description))
+}
+
+func interfaceDescription(node string, name string) string {
+	path := fmt.Sprintf("interfaces.#(name==\"%s\").description", name)
+	return gjson.ParseBytes(currentStateJSON(node)).Get(path).String()
+}
+
+var _ = Describe("Interface description", func() {
+	var (
+		description = "uplink-to-spine-1"
+	)
+	Context("when description is configured at policy", func() {
+		BeforeEach(func() {
+			updateDesiredState(ethernetNicWithDescription(*firstSecondaryNic, description))
+			waitForAvailableTestPolicy()
+		})
+		AfterEach(func() {
+			updateDesiredState(ethernetNicWithDescription(*firstSecondaryNic, `""`))
+			waitForAvailableTestPolicy()
+			resetDesiredStateForNodes()
+		})
+		It("should be reported at node network state and kept across refreshes", func() {
+			for _, node := range nodes {
+				Eventually(func() string {
+					return interfaceDescription(node, *firstSecondaryNic)
+				}, ReadTimeout, ReadInterval).Should(Equal(description))
+
+				Consistently(func() string {
+					return interfaceDescription(node, *firstSecondaryNic)
+				}, 15*time.Second, 1*time.Second).Should(Equal(description))
+			}
+		})
+	})
+})
@@ -0,0 +1,55 @@
+package e2e
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	framework "github.com/operator-framework/operator-sdk/pkg/test"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+func setTestPolicyRolloutOnFailure(onFailure nmstatev1alpha1.RolloutOnFailure) {
+	Eventually(func() error {
+		policy := nmstatev1alpha1.NodeNetworkConfigurationPolicy{}
+		err := framework.Global.Client.Get(context.TODO(), types.NamespacedName{Name: TestPolicy}, &policy)
+		if err != nil {
+			return err
+		}
+		policy.Spec.Rollout = &nmstatev1alpha1.PolicyRollout{OnFailure: onFailure}
+		return framework.Global.Client.Update(context.TODO(), &policy)
+	}, ReadTimeout, ReadInterval).ShouldNot(HaveOccurred())
+}
+
+var _ = Describe("Policy rollout on failure", func() {
+	Context("when the apply fails at a node with halt on failure", func() {
+		var failedNode string
+		BeforeEach(func() {
+			failedNode = nodes[0]
+			injectFailureAtNode(failedNode, "simulated node failure")
+			updateDesiredState(linuxBrUp(bridge1))
+			setTestPolicyRolloutOnFailure(nmstatev1alpha1.RolloutOnFailureHalt)
+		})
+		AfterEach(func() {
+			removeInjectedFailureAtNode(failedNode)
+			updateDesiredState(linuxBrAbsent(bridge1))
+			waitForAvailableTestPolicy()
+			resetDesiredStateForNodes()
+		})
+		It("should fail at the node and degrade the policy", func() {
+			waitForDegradedTestPolicy()
+			enactmentConditionsStatusEventually(failedNode).Should(ContainElement(
+				nmstatev1alpha1.Condition{
+					Type:   nmstatev1alpha1.NodeNetworkConfigurationEnactmentConditionFailing,
+					Status: corev1.ConditionTrue,
+				},
+			))
+			interfacesNameForNodeConsistently(failedNode).ShouldNot(ContainElement(bridge1))
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package e2e
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+func routeWithSource(source string) nmstatev1alpha1.State {
+	sourceAttribute := ""
+	if source != "" {
+		sourceAttribute = fmt.Sprintf("    source: %s\n", source)
+	}
+	return nmstatev1alpha1.NewState(fmt.Sprintf(`interfaces:
+  - name: %s
+    type: ethernet
+    state: up
+    ipv4:
+      enabled: true
+      dhcp: false
+      address:
+      - ip: 192.0.2.10
+        prefix-length: 24
+      - ip: 192.0.2.11
+        prefix-length: 24
+routes:
+  config:
+  - destination: 198.51.100.0/24
+    next-hop-address: 192.0.2.1
+    next-hop-interface: %s
+%s`, *firstSecondaryNic, *firstSecondaryNic, sourceAttribute))
+}
+
+func routeSourceAbsent() nmstatev1alpha1.State {
+	return nmstatev1alpha1.NewState(fmt.Sprintf(`interfaces:
+  - name: %s
+    type: ethernet
+    state: up
+    ipv4:
+      enabled: false
+routes:
+  config:
+  - destination: 198.51.100.0/24
+    state: absent
+`, *firstSecondaryNic))
+}
+
+func runningRoutePreferredSource(node string, destination string) string {
+	for _, route := range nodeNetworkState(types.NamespacedName{Name: node}).Status.RunningRoutes {
+		if route.Destination == destination {
+			return route.PreferredSource
+		}
+	}
+	return ""
+}
+
+var _ = Describe("Route preferred source address", func() {
+	Context("when a route is configured with a preferred source address", func() {
+		BeforeEach(func() {
+			updateDesiredState(routeWithSource("192.0.2.11"))
+			waitForAvailableTestPolicy()
+		})
+		AfterEach(func() {
+			updateDesiredState(routeSourceAbsent())
+			waitForAvailableTestPolicy()
+			resetDesiredStateForNodes()
+		})
+		It("should send the traffic through the route from the preferred source address", func() {
+			for _, node := range nodes {
+				Eventually(func() string {
+					output, _ := runAtNode(node, "sudo", "ip", "route", "get", "198.51.100.1")
+					return output
+				}, ReadTimeout, ReadInterval).Should(ContainSubstring("src 192.0.2.11"))
+				Eventually(func() string {
+					return runningRoutePreferredSource(node, "198.51.100.0/24")
+				}, ReadTimeout, ReadInterval).Should(Equal("192.0.2.11"))
+			}
+		})
+		Context("and the preferred source address is removed", func() {
+			BeforeEach(func() {
+				updateDesiredState(routeWithSource(""))
+				waitForAvailableTestPolicy()
+			})
+			It("should not report it anymore", func() {
+				for _, node := range nodes {
+					Eventually(func() string {
+						return runningRoutePreferredSource(node, "198.51.100.0/24")
+					}, ReadTimeout, ReadInterval).Should(BeEmpty())
+				}
+			})
+		})
+	})
+})
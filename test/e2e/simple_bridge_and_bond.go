@@ -28,6 +28,19 @@ func brAndBondAbsent(bridgeName string, bondName string) nmstatev1alpha1.State {
 `, bridgeName, bondName))
 }
 
+// bondAndVlanAbsent lists the bond before the vlan on top of it, the
+// handler has to remove the vlan first
+func bondAndVlanAbsent(bondName string) nmstatev1alpha1.State {
+	return nmstatev1alpha1.NewState(fmt.Sprintf(`interfaces:
+  - name: %s
+    type: bond
+    state: absent
+  - name: %s.102
+    type: vlan
+    state: absent
+`, bondName, bondName))
+}
+
 func bondUp(bondName string) nmstatev1alpha1.State {
 	return nmstatev1alpha1.NewState(fmt.Sprintf(`interfaces:
   - name: %s
@@ -271,13 +284,23 @@ var _ = Describe("NodeNetworkState", func() {
 				waitForAvailableTestPolicy()
 			})
 			AfterEach(func() {
-				updateDesiredState(bondAbsent(bond1))
+				updateDesiredState(bondAndVlanAbsent(bond1))
 				waitForAvailableTestPolicy()
 				for _, node := range nodes {
 					interfacesNameForNodeEventually(node).ShouldNot(ContainElement(bond1))
 				}
 				resetDesiredStateForNodes()
 			})
+			It("should remove the bond and the vlan on top of it with the same policy", func() {
+				updateDesiredState(bondAndVlanAbsent(bond1))
+				waitForAvailableTestPolicy()
+				for _, node := range nodes {
+					interfacesNameForNodeEventually(node).ShouldNot(SatisfyAny(
+						ContainElement(bond1),
+						ContainElement(fmt.Sprintf("%s.102", bond1)),
+					))
+				}
+			})
 			It("should have the bond interface with 2 slaves at currentState", func() {
 				var (
 					expectedBond        = interfaceByName(interfaces(bondUpWithEth1Eth2AndVlan(bond1)), bond1)
@@ -0,0 +1,98 @@
+package e2e
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidwall/gjson"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+func greUp(greName string, baseIface string, key int) nmstatev1alpha1.State {
+	return nmstatev1alpha1.NewState(fmt.Sprintf(`interfaces:
+  - name: %s
+    type: gre
+    state: up
+    gre:
+      local: 192.0.2.1
+      remote: 192.0.2.2
+      ttl: 64
+      key: %d
+      base-iface: %s
+    ipv4:
+      enabled: true
+      address:
+      - ip: 10.200.0.1
+        prefix-length: 30
+`, greName, key, baseIface))
+}
+
+func greAbsent(greName string) nmstatev1alpha1.State {
+	return nmstatev1alpha1.NewState(fmt.Sprintf(`interfaces:
+  - name: %s
+    type: gre
+    state: absent
+`, greName))
+}
+
+func greKey(node string, name string) int64 {
+	path := fmt.Sprintf("interfaces.#(name==\"%s\").gre.key", name)
+	return gjson.ParseBytes(currentStateJSON(node)).Get(path).Int()
+}
+
+var _ = Describe("GRE tunnel", func() {
+	Context("when a GRE tunnel is configured over an existing interface", func() {
+		BeforeEach(func() {
+			updateDesiredState(greUp("gre100", *firstSecondaryNic, 100))
+			waitForAvailableTestPolicy()
+		})
+		AfterEach(func() {
+			updateDesiredState(greAbsent("gre100"))
+			waitForAvailableTestPolicy()
+			for _, node := range nodes {
+				interfacesNameForNodeEventually(node).ShouldNot(ContainElement("gre100"))
+			}
+			resetDesiredStateForNodes()
+		})
+		It("should be reported at node network state", func() {
+			for _, node := range nodes {
+				interfacesNameForNodeEventually(node).Should(ContainElement("gre100"))
+				Eventually(func() int64 {
+					return greKey(node, "gre100")
+				}, ReadTimeout, ReadInterval).Should(Equal(int64(100)))
+			}
+		})
+		Context("and its key is changed", func() {
+			BeforeEach(func() {
+				updateDesiredState(greUp("gre100", *firstSecondaryNic, 101))
+				waitForAvailableTestPolicy()
+			})
+			It("should recreate it with the new key", func() {
+				for _, node := range nodes {
+					Eventually(func() int64 {
+						return greKey(node, "gre100")
+					}, ReadTimeout, ReadInterval).Should(Equal(int64(101)))
+				}
+			})
+		})
+	})
+	Context("when a GRE tunnel is configured over a missing interface", func() {
+		BeforeEach(func() {
+			updateDesiredState(greUp("gre101", "missing0", 101))
+		})
+		AfterEach(func() {
+			updateDesiredState(greAbsent("gre101"))
+			waitForAvailableTestPolicy()
+			resetDesiredStateForNodes()
+		})
+		It("should fail to configure without creating it", func() {
+			waitForDegradedTestPolicy()
+			for _, node := range nodes {
+				interfacesNameForNodeConsistently(node).ShouldNot(ContainElement("gre101"))
+			}
+		})
+	})
+})
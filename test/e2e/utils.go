@@ -287,6 +287,20 @@ func runAtPods(arguments ...string) {
 	}
 }
 
+// injectFailureAtNode makes the desired state applies at the node fail with
+// the message, needs the handler e2e failure injection enabled
+func injectFailureAtNode(node string, message string) {
+	By(fmt.Sprintf("Inject apply failure at node %s", node))
+	_, err := kubectl("annotate", "node", node, "--overwrite", fmt.Sprintf("%s=%s", nmstatev1alpha1.NodeFailApplyAnnotation, message))
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+}
+
+func removeInjectedFailureAtNode(node string) {
+	By(fmt.Sprintf("Remove injected apply failure at node %s", node))
+	_, err := kubectl("annotate", "node", node, nmstatev1alpha1.NodeFailApplyAnnotation+"-")
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+}
+
 func runAtNodes(command ...string) (outputs []string, errs []error) {
 	for _, node := range nodes {
 		output, err := runAtNode(node, command...)
@@ -0,0 +1,74 @@
+package e2e
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/tidwall/gjson"
+
+	nmstatev1alpha1 "github.com/nmstate/kubernetes-nmstate/pkg/apis/nmstate/v1alpha1"
+)
+
+func vxlanUp(vxlanName string, id int) nmstatev1alpha1.State {
+	return nmstatev1alpha1.NewState(fmt.Sprintf(`interfaces:
+  - name: %s
+    type: vxlan
+    state: up
+    vxlan:
+      base-iface: %s
+      id: %d
+      remote: 192.0.2.2
+      destination-port: 4789
+`, vxlanName, *firstSecondaryNic, id))
+}
+
+func vxlansAbsent(vxlanNames ...string) nmstatev1alpha1.State {
+	state := "interfaces:\n"
+	for _, vxlanName := range vxlanNames {
+		state += fmt.Sprintf(`  - name: %s
+    type: vxlan
+    state: absent
+`, vxlanName)
+	}
+	return nmstatev1alpha1.NewState(state)
+}
+
+func vxlanID(node string, name string) int64 {
+	path := fmt.Sprintf("interfaces.#(name==\"%s\").vxlan.id", name)
+	return gjson.ParseBytes(currentStateJSON(node)).Get(path).Int()
+}
+
+var _ = Describe("VXLAN", func() {
+	Context("when a VXLAN interface is configured over an existing interface", func() {
+		BeforeEach(func() {
+			updateDesiredState(vxlanUp("vxlan100", 100))
+			waitForAvailableTestPolicy()
+		})
+		AfterEach(func() {
+			updateDesiredState(vxlansAbsent("vxlan100", "vxlan101"))
+			waitForAvailableTestPolicy()
+			resetDesiredStateForNodes()
+		})
+		It("should be reported at node network state", func() {
+			for _, node := range nodes {
+				interfacesNameForNodeEventually(node).Should(ContainElement("vxlan100"))
+				Eventually(func() int64 {
+					return vxlanID(node, "vxlan100")
+				}, ReadTimeout, ReadInterval).Should(Equal(int64(100)))
+			}
+		})
+		Context("and another VXLAN interface reuses its id", func() {
+			BeforeEach(func() {
+				updateDesiredState(vxlanUp("vxlan101", 100))
+			})
+			It("should fail to configure without creating it", func() {
+				waitForDegradedTestPolicy()
+				for _, node := range nodes {
+					interfacesNameForNodeConsistently(node).ShouldNot(ContainElement("vxlan101"))
+				}
+			})
+		})
+	})
+})